@@ -19,12 +19,15 @@ package v1alpha1
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/argoproj-labs/applicationset/common"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Utility struct for a reference to a secret key.
@@ -34,9 +37,11 @@ type SecretRef struct {
 }
 
 // ApplicationSet is a set of Application resources
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=applicationsets,shortName=appset;appsets
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 type ApplicationSet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
@@ -47,9 +52,140 @@ type ApplicationSet struct {
 
 // ApplicationSetSpec represents a class of application set state.
 type ApplicationSetSpec struct {
-	Generators []ApplicationSetGenerator `json:"generators"`
-	Template   ApplicationSetTemplate    `json:"template"`
-	SyncPolicy *ApplicationSetSyncPolicy `json:"syncPolicy,omitempty"`
+	// GoTemplate renders the template with Go templates and the Sprig functions, rather than the {{param}} syntax.
+	GoTemplate bool `json:"goTemplate,omitempty"`
+	// GoTemplateOptions are the options of the Go templates, e.g. missingkey=error to fail on missing params.
+	GoTemplateOptions []string                  `json:"goTemplateOptions,omitempty"`
+	Generators        []ApplicationSetGenerator `json:"generators"`
+	// ParamDefaults are the values of the params which are missing from a generated param set.
+	ParamDefaults map[string]string `json:"paramDefaults,omitempty"`
+	// ResolveParamReferences substitutes the references to other params in the values of the params, e.g.
+	// https://{{name}}.{{domain}}, before the param transforms and the template.
+	ResolveParamReferences bool `json:"resolveParamReferences,omitempty"`
+	// ParamTransforms set or remove params of each generated param set, in order, before the template is rendered.
+	ParamTransforms []ParamTransform       `json:"paramTransforms,omitempty"`
+	Template        ApplicationSetTemplate `json:"template"`
+	// Templates are named templates, which the TemplateSelector chooses from for each param set.
+	Templates map[string]ApplicationSetTemplate `json:"templates,omitempty"`
+	// TemplateSelector is a Go template rendered with the params, e.g. '{{ .type }}', whose result is the name of the
+	// template of Templates each param set is rendered with. Param sets for which the result is empty are rendered with
+	// Template.
+	TemplateSelector string `json:"templateSelector,omitempty"`
+	// TemplateMergePolicy defines how the metadata of the generator templates is merged with the metadata of the
+	// template. Defaults to merge.
+	TemplateMergePolicy TemplateMergePolicy `json:"templateMergePolicy,omitempty"`
+	// TemplatePatch is a YAML or JSON strategic merge patch, rendered with the params like the template, and applied
+	// to the generated Applications. It allows fields of the Application which are not part of the template to be
+	// templated.
+	TemplatePatch *string                   `json:"templatePatch,omitempty"`
+	SyncPolicy    *ApplicationSetSyncPolicy `json:"syncPolicy,omitempty"`
+	// PreservedFields are the fields of the existing Applications which are left untouched when they are updated, e.g.
+	// spec.source.targetRevision, or metadata.annotations[argocd-image-updater.argoproj.io/image-list] for an annotation.
+	PreservedFields []string `json:"preservedFields,omitempty"`
+	// IgnoreApplicationDifferences are the fields of the existing Applications whose differences with the generated
+	// Applications are ignored: they keep their values, and differences in them don't cause the Applications to be
+	// updated.
+	IgnoreApplicationDifferences []ApplicationSetResourceIgnoreDifferences `json:"ignoreApplicationDifferences,omitempty"`
+	// DryRun runs the generators and renders the Applications, and reports the changes which would be made to the
+	// Applications in the status and the events of the ApplicationSet, without changing any Application.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Strategy defines how the changes to the Applications are rolled out. Defaults to all at once.
+	Strategy *ApplicationSetStrategy `json:"strategy,omitempty"`
+	// Suspend stops the generation of the Applications, and the changes to them, until it is set back to false. The
+	// status of the ApplicationSet is left as it was when it was suspended.
+	Suspend bool `json:"suspend,omitempty"`
+	// HistoryLimit is the maximum number of the creations, updates and deletions of the Applications recorded in the
+	// history of the status, the oldest ones being removed first. The history is not recorded if not set, or 0.
+	HistoryLimit *int64 `json:"historyLimit,omitempty"`
+}
+
+// ApplicationSetResourceIgnoreDifferences defines the fields of the Applications of an ApplicationSet whose
+// differences are ignored.
+type ApplicationSetResourceIgnoreDifferences struct {
+	// Name is the name of the Application the differences are ignored for. Defaults to all the Applications.
+	Name string `json:"name,omitempty"`
+	// JSONPointers are the RFC 6901 JSON pointers to the ignored fields, e.g. /spec/source/targetRevision.
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+	// JQPathExpressions are jq path expressions selecting the ignored fields, e.g.
+	// .spec.source.helm.parameters[] | select(.name == "image.tag").
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty"`
+}
+
+// ApplicationSetStrategy defines how the changes to the generated Applications are rolled out.
+type ApplicationSetStrategy struct {
+	// Type of the strategy: AllAtOnce, the default, or RollingSync.
+	// +kubebuilder:validation:Enum=AllAtOnce;RollingSync
+	Type ApplicationSetStrategyType `json:"type,omitempty"`
+	// RollingSync defines the steps of the RollingSync strategy.
+	RollingSync *ApplicationSetRolloutStrategy `json:"rollingSync,omitempty"`
+	// MaxUpdate is the maximum number of Applications which are created or updated in each reconciliation, as a number
+	// or a percentage of the generated Applications, e.g. 10%. Defaults to all of them.
+	MaxUpdate *intstr.IntOrString `json:"maxUpdate,omitempty"`
+	// Order defines the order in which the Applications are created and updated. Defaults to the order in which they
+	// are generated.
+	Order *ApplicationSetOrder `json:"order,omitempty"`
+}
+
+// ApplicationSetOrder defines the order of the Applications of an ApplicationSet, by a key rendered with their params.
+type ApplicationSetOrder struct {
+	// Key is rendered with the params of each Application, like the template, e.g. '{{waveNumber}}'.
+	Key string `json:"key"`
+	// Values are the keys in order, e.g. [dev, staging, prod]. The keys which are not part of the values come last.
+	// Without values, the keys are sorted numerically if they are integers, and lexically otherwise.
+	Values []string `json:"values,omitempty"`
+}
+
+// ApplicationSetStrategyType is the type of the strategy of an ApplicationSet.
+type ApplicationSetStrategyType string
+
+const (
+	// ApplicationSetStrategyTypeAllAtOnce applies the changes to all the Applications at once.
+	ApplicationSetStrategyTypeAllAtOnce ApplicationSetStrategyType = "AllAtOnce"
+	// ApplicationSetStrategyTypeRollingSync enables the automated sync of the Applications step by step.
+	ApplicationSetStrategyTypeRollingSync ApplicationSetStrategyType = "RollingSync"
+)
+
+// ApplicationSetRolloutStrategy groups the Applications into ordered steps: the automated sync of the Applications of
+// a step is disabled until the Applications of the previous steps are Healthy. The Applications which match no step
+// are rolled out after the last step.
+type ApplicationSetRolloutStrategy struct {
+	Steps []ApplicationSetRolloutStep `json:"steps,omitempty"`
+}
+
+// ApplicationSetRolloutStep is a step of the RollingSync strategy.
+type ApplicationSetRolloutStep struct {
+	// MatchExpressions select the Applications of the step by their labels. An Application belongs to the first step
+	// it matches.
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+	// HealthyPercentage is the percentage of the Applications of the step which must be synced and Healthy before the
+	// next step is rolled out. Defaults to 100.
+	HealthyPercentage *int64 `json:"healthyPercentage,omitempty"`
+}
+
+// TemplateMergePolicy defines how the labels, annotations and finalizers of a generator template are merged with those
+// of the template of the ApplicationSet.
+type TemplateMergePolicy string
+
+const (
+	// TemplateMergePolicyOverride uses the labels, annotations and finalizers of the generator template, when set,
+	// rather than those of the template.
+	TemplateMergePolicyOverride TemplateMergePolicy = "override"
+	// TemplateMergePolicyMerge merges the labels and annotations key-wise, the generator template taking precedence,
+	// and uses the finalizers of the generator template, when set.
+	TemplateMergePolicyMerge TemplateMergePolicy = "merge"
+	// TemplateMergePolicyDeepMerge merges the labels and annotations key-wise, like TemplateMergePolicyMerge, and
+	// combines the finalizers of both templates.
+	TemplateMergePolicyDeepMerge TemplateMergePolicy = "deep-merge"
+)
+
+// ParamTransform sets a param to a value derived from the other params of the param set, or removes it.
+type ParamTransform struct {
+	// Name of the param to set or remove.
+	Name string `json:"name"`
+	// Value is a Go template rendered with the params, e.g. '{{ .branch | slugify | truncName 20 }}'.
+	Value string `json:"value,omitempty"`
+	// Remove the param rather than setting it.
+	Remove bool `json:"remove,omitempty"`
 }
 
 // ApplicationSetSyncPolicy configures how generated Applications will relate to their
@@ -57,9 +193,66 @@ type ApplicationSetSpec struct {
 type ApplicationSetSyncPolicy struct {
 	// PreserveResourcesOnDeletion will preserve resources on deletion. If PreserveResourcesOnDeletion is set to true, these Applications will not be deleted.
 	PreserveResourcesOnDeletion bool `json:"preserveResourcesOnDeletion,omitempty"`
+	// PreserveApplicationsOnDeletion orphans the generated Applications when the ApplicationSet is deleted, rather than
+	// deleting them with the ApplicationSet.
+	PreserveApplicationsOnDeletion bool `json:"preserveApplicationsOnDeletion,omitempty"`
+	// AdoptExisting defines whether the controller takes ownership of the existing Applications which have the name
+	// of a generated Application, but are not owned by any ApplicationSet. If false, the controller fails to update
+	// them instead. Defaults to true.
+	AdoptExisting *bool `json:"adoptExisting,omitempty"`
+	// ApplicationsSync defines whether the controller may update or delete the existing Applications of the
+	// ApplicationSet, overriding the policy of the controller if the controller is started with
+	// --enable-policy-override. Defaults to the policy of the controller.
+	// +kubebuilder:validation:Enum=create-only;create-update;create-delete;sync
+	ApplicationsSync ApplicationsSyncPolicy `json:"applicationsSync,omitempty"`
+	// MaxDeletionPercentage is the maximum percentage of the Applications of the ApplicationSet which may be deleted by
+	// a reconciliation. If more Applications would be deleted, none is deleted. Defaults to the maximum deletion
+	// percentage of the controller.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxDeletionPercentage *int64 `json:"maxDeletionPercentage,omitempty"`
+	// PruneAfter is the grace period after which the Applications which are no longer generated are deleted, e.g. 24h.
+	// Defaults to deleting them as soon as they are no longer generated.
+	PruneAfter *metav1.Duration `json:"pruneAfter,omitempty"`
+	// DeletionBatches, if set, limits the number of Applications deleted at once, so that the Applications which are no
+	// longer generated are deleted by batches. Defaults to deleting them all at once.
+	DeletionBatches *ApplicationSetDeletionBatches `json:"deletionBatches,omitempty"`
+}
+
+// ApplicationSetDeletionBatches defines the batches by which the Applications of an ApplicationSet are deleted.
+type ApplicationSetDeletionBatches struct {
+	// Size is the maximum number of Applications deleted in a batch.
+	// +kubebuilder:validation:Minimum=1
+	Size int64 `json:"size"`
+	// Interval is the minimum delay between two batches, e.g. 1m. Defaults to 10s.
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
-// ApplicationSetTemplate represents argocd ApplicationSpec
+// ApplicationsSyncPolicy defines which changes the controller makes to the Applications of an ApplicationSet.
+type ApplicationsSyncPolicy string
+
+const (
+	// ApplicationsSyncPolicyCreateOnly only creates the missing Applications.
+	ApplicationsSyncPolicyCreateOnly ApplicationsSyncPolicy = "create-only"
+	// ApplicationsSyncPolicyCreateUpdate creates and updates the Applications, but does not delete them.
+	ApplicationsSyncPolicyCreateUpdate ApplicationsSyncPolicy = "create-update"
+	// ApplicationsSyncPolicyCreateDelete creates and deletes the Applications, but does not update them.
+	ApplicationsSyncPolicyCreateDelete ApplicationsSyncPolicy = "create-delete"
+	// ApplicationsSyncPolicySync creates, updates and deletes the Applications.
+	ApplicationsSyncPolicySync ApplicationsSyncPolicy = "sync"
+)
+
+// PreserveApplicationsFinalizer is the finalizer of the ApplicationSets which preserve their Applications on deletion:
+// the controller removes the owner references of the Applications before the ApplicationSet is deleted.
+const PreserveApplicationsFinalizer = "applicationset.argoproj.io/preserve-applications"
+
+// DeletionProtectedAnnotation is the annotation of the Applications which are not deleted when their ApplicationSet no
+// longer generates them, if its value is "true".
+const DeletionProtectedAnnotation = "applicationset.argoproj.io/deletion-protected"
+
+// ApplicationSetTemplate represents argocd ApplicationSpec. The CRD only validates the schema of the templates of the
+// ApplicationSets, and keeps the fields of the templates of the generators as they are, since they would repeat the
+// schema of the Applications for each generator.
 type ApplicationSetTemplate struct {
 	ApplicationSetTemplateMeta `json:"metadata"`
 	Spec                       v1alpha1.ApplicationSpec `json:"spec"`
@@ -77,42 +270,107 @@ type ApplicationSetTemplateMeta struct {
 
 // ApplicationSetGenerator represents a generator at the top level of an ApplicationSet.
 type ApplicationSetGenerator struct {
-	List                    *ListGenerator        `json:"list,omitempty"`
-	Clusters                *ClusterGenerator     `json:"clusters,omitempty"`
-	Git                     *GitGenerator         `json:"git,omitempty"`
-	SCMProvider             *SCMProviderGenerator `json:"scmProvider,omitempty"`
-	ClusterDecisionResource *DuckTypeGenerator    `json:"clusterDecisionResource,omitempty"`
-	PullRequest             *PullRequestGenerator `json:"pullRequest,omitempty"`
-	Matrix                  *MatrixGenerator      `json:"matrix,omitempty"`
-	Merge                   *MergeGenerator       `json:"merge,omitempty"`
-}
-
-// ApplicationSetNestedGenerator represents a generator nested within a combination-type generator (MatrixGenerator or
-// MergeGenerator).
+	List                    *ListGenerator               `json:"list,omitempty"`
+	Clusters                *ClusterGenerator            `json:"clusters,omitempty"`
+	Git                     *GitGenerator                `json:"git,omitempty"`
+	SCMProvider             *SCMProviderGenerator        `json:"scmProvider,omitempty"`
+	ClusterDecisionResource *DuckTypeGenerator           `json:"clusterDecisionResource,omitempty"`
+	PullRequest             *PullRequestGenerator        `json:"pullRequest,omitempty"`
+	Plugin                  *PluginGenerator             `json:"plugin,omitempty"`
+	HTTP                    *HTTPGenerator               `json:"http,omitempty"`
+	Secret                  *SecretGenerator             `json:"secret,omitempty"`
+	KubernetesResource      *KubernetesResourceGenerator `json:"kubernetesResource,omitempty"`
+	AzureSubscriptions      *AzureSubscriptionsGenerator `json:"azureSubscriptions,omitempty"`
+	Vault                   *VaultGenerator              `json:"vault,omitempty"`
+	Consul                  *ConsulGenerator             `json:"consul,omitempty"`
+	HelmRepository          *HelmRepositoryGenerator     `json:"helmRepository,omitempty"`
+	Bucket                  *BucketGenerator             `json:"bucket,omitempty"`
+	SQL                     *SQLGenerator                `json:"sql,omitempty"`
+	TerraformState          *TerraformStateGenerator     `json:"terraformState,omitempty"`
+	Matrix                  *MatrixGenerator             `json:"matrix,omitempty"`
+	Merge                   *MergeGenerator              `json:"merge,omitempty"`
+	Union                   *UnionGenerator              `json:"union,omitempty"`
+
+	// Schedule restricts the generator to time windows: outside of the windows, no parameters are generated.
+	Schedule *GeneratorSchedule `json:"schedule,omitempty"`
+}
+
+// GeneratorSchedule defines the time windows during which a generator generates parameters.
+type GeneratorSchedule struct {
+	// Windows during which the parameters are generated. The generator is active if any window is active.
+	Windows []ScheduleWindow `json:"windows"`
+	// TimeZone of the cron schedules, e.g. Europe/Paris. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleWindow is a time window which starts on a cron schedule, for a given duration.
+type ScheduleWindow struct {
+	// Schedule is the cron expression of the start of the window, e.g. "0 8 * * 1-5".
+	Schedule string `json:"schedule"`
+	// Duration of the window, e.g. 10h.
+	Duration string `json:"duration"`
+}
+
+// ApplicationSetNestedGenerator represents a generator nested within a combination-type generator (MatrixGenerator,
+// MergeGenerator or UnionGenerator). The CRD doesn't validate the schema of its combination-type generators, which
+// would repeat the schema of all the terminal generators for each of them, and keeps their fields as they are.
 type ApplicationSetNestedGenerator struct {
-	List                    *ListGenerator         `json:"list,omitempty"`
-	Clusters                *ClusterGenerator      `json:"clusters,omitempty"`
-	Git                     *GitGenerator          `json:"git,omitempty"`
-	SCMProvider             *SCMProviderGenerator  `json:"scmProvider,omitempty"`
-	ClusterDecisionResource *DuckTypeGenerator     `json:"clusterDecisionResource,omitempty"`
-	PullRequest             *PullRequestGenerator  `json:"pullRequest,omitempty"`
-	Matrix                  *NestedMatrixGenerator `json:"matrix,omitempty"`
-	Merge                   *NestedMergeGenerator  `json:"merge,omitempty"`
+	List                    *ListGenerator               `json:"list,omitempty"`
+	Clusters                *ClusterGenerator            `json:"clusters,omitempty"`
+	Git                     *GitGenerator                `json:"git,omitempty"`
+	SCMProvider             *SCMProviderGenerator        `json:"scmProvider,omitempty"`
+	ClusterDecisionResource *DuckTypeGenerator           `json:"clusterDecisionResource,omitempty"`
+	PullRequest             *PullRequestGenerator        `json:"pullRequest,omitempty"`
+	Plugin                  *PluginGenerator             `json:"plugin,omitempty"`
+	HTTP                    *HTTPGenerator               `json:"http,omitempty"`
+	Secret                  *SecretGenerator             `json:"secret,omitempty"`
+	KubernetesResource      *KubernetesResourceGenerator `json:"kubernetesResource,omitempty"`
+	AzureSubscriptions      *AzureSubscriptionsGenerator `json:"azureSubscriptions,omitempty"`
+	Vault                   *VaultGenerator              `json:"vault,omitempty"`
+	Consul                  *ConsulGenerator             `json:"consul,omitempty"`
+	HelmRepository          *HelmRepositoryGenerator     `json:"helmRepository,omitempty"`
+	Bucket                  *BucketGenerator             `json:"bucket,omitempty"`
+	SQL                     *SQLGenerator                `json:"sql,omitempty"`
+	TerraformState          *TerraformStateGenerator     `json:"terraformState,omitempty"`
+
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Matrix *NestedMatrixGenerator `json:"matrix,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Merge *NestedMergeGenerator `json:"merge,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Union *NestedUnionGenerator `json:"union,omitempty"`
 }
 
 type ApplicationSetNestedGenerators []ApplicationSetNestedGenerator
 
 // ApplicationSetTerminalGenerator represents a generator nested within a nested generator (for example, a list within
-// a merge within a matrix). A generator at this level may not be a combination-type generator (MatrixGenerator or
-// MergeGenerator). ApplicationSet enforces this nesting depth limit because CRDs do not support recursive types.
+// a merge within a matrix). A generator at this level may not be a combination-type generator (MatrixGenerator,
+// MergeGenerator or UnionGenerator). ApplicationSet enforces this nesting depth limit because CRDs do not support recursive types.
 // https://github.com/kubernetes-sigs/controller-tools/issues/477
 type ApplicationSetTerminalGenerator struct {
-	List                    *ListGenerator        `json:"list,omitempty"`
-	Clusters                *ClusterGenerator     `json:"clusters,omitempty"`
-	Git                     *GitGenerator         `json:"git,omitempty"`
-	SCMProvider             *SCMProviderGenerator `json:"scmProvider,omitempty"`
-	ClusterDecisionResource *DuckTypeGenerator    `json:"clusterDecisionResource,omitempty"`
-	PullRequest             *PullRequestGenerator `json:"pullRequest,omitempty"`
+	List                    *ListGenerator               `json:"list,omitempty"`
+	Clusters                *ClusterGenerator            `json:"clusters,omitempty"`
+	Git                     *GitGenerator                `json:"git,omitempty"`
+	SCMProvider             *SCMProviderGenerator        `json:"scmProvider,omitempty"`
+	ClusterDecisionResource *DuckTypeGenerator           `json:"clusterDecisionResource,omitempty"`
+	PullRequest             *PullRequestGenerator        `json:"pullRequest,omitempty"`
+	Plugin                  *PluginGenerator             `json:"plugin,omitempty"`
+	HTTP                    *HTTPGenerator               `json:"http,omitempty"`
+	Secret                  *SecretGenerator             `json:"secret,omitempty"`
+	KubernetesResource      *KubernetesResourceGenerator `json:"kubernetesResource,omitempty"`
+	AzureSubscriptions      *AzureSubscriptionsGenerator `json:"azureSubscriptions,omitempty"`
+	Vault                   *VaultGenerator              `json:"vault,omitempty"`
+	Consul                  *ConsulGenerator             `json:"consul,omitempty"`
+	HelmRepository          *HelmRepositoryGenerator     `json:"helmRepository,omitempty"`
+	Bucket                  *BucketGenerator             `json:"bucket,omitempty"`
+	SQL                     *SQLGenerator                `json:"sql,omitempty"`
+	TerraformState          *TerraformStateGenerator     `json:"terraformState,omitempty"`
 }
 
 type ApplicationSetTerminalGenerators []ApplicationSetTerminalGenerator
@@ -130,6 +388,17 @@ func (g ApplicationSetTerminalGenerators) toApplicationSetNestedGenerators() []A
 			SCMProvider:             terminalGenerator.SCMProvider,
 			ClusterDecisionResource: terminalGenerator.ClusterDecisionResource,
 			PullRequest:             terminalGenerator.PullRequest,
+			Plugin:                  terminalGenerator.Plugin,
+			HTTP:                    terminalGenerator.HTTP,
+			Secret:                  terminalGenerator.Secret,
+			KubernetesResource:      terminalGenerator.KubernetesResource,
+			AzureSubscriptions:      terminalGenerator.AzureSubscriptions,
+			Vault:                   terminalGenerator.Vault,
+			Consul:                  terminalGenerator.Consul,
+			HelmRepository:          terminalGenerator.HelmRepository,
+			Bucket:                  terminalGenerator.Bucket,
+			SQL:                     terminalGenerator.SQL,
+			TerraformState:          terminalGenerator.TerraformState,
 		}
 	}
 	return nestedGenerators
@@ -138,14 +407,26 @@ func (g ApplicationSetTerminalGenerators) toApplicationSetNestedGenerators() []A
 // ListGenerator include items info
 type ListGenerator struct {
 	Elements []apiextensionsv1.JSON `json:"elements"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
 	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the elements are generated again. The elements are not generated
+	// periodically by default.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // MatrixGenerator generates the cartesian product of two sets of parameters. The parameters are defined by two nested
 // generators.
 type MatrixGenerator struct {
 	Generators []ApplicationSetNestedGenerator `json:"generators"`
-	Template   ApplicationSetTemplate          `json:"template,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // NestedMatrixGenerator is a MatrixGenerator nested under another combination-type generator (MatrixGenerator or
@@ -153,6 +434,9 @@ type MatrixGenerator struct {
 // within the constituent generators of combination-type generators.
 type NestedMatrixGenerator struct {
 	Generators ApplicationSetTerminalGenerators `json:"generators"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // ToMatrixGenerator converts a NestedMatrixGenerator to a MatrixGenerator. This conversion is for convenience, allowing
@@ -160,7 +444,8 @@ type NestedMatrixGenerator struct {
 // no override template).
 func (g NestedMatrixGenerator) ToMatrixGenerator() *MatrixGenerator {
 	return &MatrixGenerator{
-		Generators: g.Generators.toApplicationSetNestedGenerators(),
+		Generators:          g.Generators.toApplicationSetNestedGenerators(),
+		RequeueAfterSeconds: g.RequeueAfterSeconds,
 	}
 }
 
@@ -177,7 +462,13 @@ func (g NestedMatrixGenerator) ToMatrixGenerator() *MatrixGenerator {
 type MergeGenerator struct {
 	Generators []ApplicationSetNestedGenerator `json:"generators"`
 	MergeKeys  []string                        `json:"mergeKeys"`
-	Template   ApplicationSetTemplate          `json:"template,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // NestedMergeGenerator is a MergeGenerator nested under another combination-type generator (MatrixGenerator or
@@ -186,6 +477,9 @@ type MergeGenerator struct {
 type NestedMergeGenerator struct {
 	Generators ApplicationSetTerminalGenerators `json:"generators"`
 	MergeKeys  []string                         `json:"mergeKeys"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // ToMergeGenerator converts a NestedMergeGenerator to a MergeGenerator. This conversion is for convenience, allowing
@@ -193,8 +487,49 @@ type NestedMergeGenerator struct {
 // no override template).
 func (g NestedMergeGenerator) ToMergeGenerator() *MergeGenerator {
 	return &MergeGenerator{
-		Generators: g.Generators.toApplicationSetNestedGenerators(),
-		MergeKeys:  g.MergeKeys,
+		Generators:          g.Generators.toApplicationSetNestedGenerators(),
+		MergeKeys:           g.MergeKeys,
+		RequeueAfterSeconds: g.RequeueAfterSeconds,
+	}
+}
+
+// UnionGenerator concatenates the parameters of two or more generators. If deduplication keys are specified, only the
+// first parameter set is kept among the parameter sets with the same values for all the keys.
+// For example, if the first generator produced [{a: '1', b: '2'}] and the second generator produced
+// [{a: '1', b: '3'}, {a: '2'}], the parameters for deduplication keys = ['a'] would be [{a: '1', b: '2'}, {a: '2'}].
+//
+// UnionGenerator supports template overriding, like MergeGenerator.
+type UnionGenerator struct {
+	Generators        []ApplicationSetNestedGenerator `json:"generators"`
+	DeduplicationKeys []string                        `json:"deduplicationKeys,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// NestedUnionGenerator is a UnionGenerator nested under another combination-type generator (MatrixGenerator,
+// MergeGenerator or UnionGenerator). NestedUnionGenerator does not have an override template, because template
+// overriding has no meaning within the constituent generators of combination-type generators.
+type NestedUnionGenerator struct {
+	Generators        ApplicationSetTerminalGenerators `json:"generators"`
+	DeduplicationKeys []string                         `json:"deduplicationKeys,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// ToUnionGenerator converts a NestedUnionGenerator to a UnionGenerator. This conversion is for convenience, allowing
+// a NestedUnionGenerator to be used where a UnionGenerator is expected (of course, the converted generator will have
+// no override template).
+func (g NestedUnionGenerator) ToUnionGenerator() *UnionGenerator {
+	return &UnionGenerator{
+		Generators:          g.Generators.toApplicationSetNestedGenerators(),
+		DeduplicationKeys:   g.DeduplicationKeys,
+		RequeueAfterSeconds: g.RequeueAfterSeconds,
 	}
 }
 
@@ -203,11 +538,18 @@ type ClusterGenerator struct {
 	// Selector defines a label selector to match against all clusters registered with ArgoCD.
 	// Clusters today are stored as Kubernetes Secrets, thus the Secret labels will be used
 	// for matching the selector.
-	Selector metav1.LabelSelector   `json:"selector,omitempty"`
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
 	Template ApplicationSetTemplate `json:"template,omitempty"`
 
 	// Values contains key/value pairs which are passed directly as parameters to the template
 	Values map[string]string `json:"values,omitempty"`
+
+	// RequeueAfterSeconds, if set, is how long before the clusters are listed again. The clusters are not listed
+	// periodically by default, since the changes to the cluster Secrets are watched.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
 }
 
 // DuckType defines a generator to match against clusters registered with ArgoCD.
@@ -221,6 +563,350 @@ type DuckTypeGenerator struct {
 	RequeueAfterSeconds *int64               `json:"requeueAfterSeconds,omitempty"`
 	LabelSelector       metav1.LabelSelector `json:"labelSelector,omitempty"`
 
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+	// StatusFields maps parameter names to JSONPath expressions (e.g. {.status.region}) evaluated against the duck
+	// typed resource. The results are passed to the template as status.<name> parameters.
+	StatusFields map[string]string `json:"statusFields,omitempty"`
+}
+
+// AzureSubscriptionsGenerator defines a generator which generates parameters from the Azure subscriptions the
+// credentials have access to.
+type AzureSubscriptionsGenerator struct {
+	// ManagementGroup is the optional ID of a management group: only the subscriptions within it, directly or through
+	// its descendant management groups, are selected.
+	ManagementGroup string `json:"managementGroup,omitempty"`
+	// TenantID is the ID of the Azure Active Directory tenant of the service principal.
+	TenantID string `json:"tenantId,omitempty"`
+	// ClientID is the client ID of the service principal or, if ClientSecretRef is not set, of the workload identity
+	// or the user-assigned managed identity to use.
+	ClientID string `json:"clientId,omitempty"`
+	// ClientSecretRef is a reference to the client secret of the service principal. If not set, the Azure Workload
+	// Identity of the service account of the ApplicationSet controller is used if configured, or its managed identity
+	// otherwise.
+	ClientSecretRef *SecretRef `json:"clientSecretRef,omitempty"`
+	// API is the URL of the Azure Resource Manager API, for clouds other than the Azure public cloud.
+	API string `json:"api,omitempty"`
+	// LoginAPI is the URL of the Azure Active Directory endpoint, for clouds other than the Azure public cloud.
+	LoginAPI string `json:"loginApi,omitempty"`
+	// RequeueAfterSeconds is how long before the subscriptions are listed again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// KubernetesResourceGenerator defines a generator which generates parameters from arbitrary Kubernetes resources of
+// the cluster the ApplicationSet controller runs in, such as Namespaces or custom resources.
+type KubernetesResourceGenerator struct {
+	// APIVersion and Kind of the resources, e.g. v1 and Namespace.
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace of the resources, if the kind is namespaced. Defaults to the namespace of the ApplicationSet.
+	Namespace string `json:"namespace,omitempty"`
+	// LabelSelector selects the resources to generate parameters from. All the resources are selected if empty.
+	LabelSelector metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// Fields maps parameter names to JSONPath expressions (e.g. {.metadata.labels.team}) evaluated against each
+	// resource.
+	Fields map[string]string `json:"fields,omitempty"`
+	// RequeueAfterSeconds is how long before the resources are listed again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// PluginGenerator defines a generator which retrieves its parameters from an external HTTP service, to integrate
+// inventories which are not supported by the other generators.
+type PluginGenerator struct {
+	// ConfigMapRef is the name of a ConfigMap, in the Argo CD namespace, containing the baseUrl of the plugin service,
+	// the token used to authenticate against it and, optionally, the requestTimeout in seconds.
+	ConfigMapRef string `json:"configMapRef"`
+	// Input is passed to the plugin service in each request.
+	Input PluginInput `json:"input,omitempty"`
+	// RequeueAfterSeconds is how long before the plugin service is queried again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// PluginInput is the input of a PluginGenerator, sent to the plugin service.
+type PluginInput struct {
+	// Parameters are arbitrary JSON values interpreted by the plugin service.
+	Parameters map[string]apiextensionsv1.JSON `json:"parameters,omitempty"`
+}
+
+// SecretGenerator defines a generator which retrieves its parameters from Secrets in the namespace of the
+// ApplicationSet. The generated parameter values are redacted from logs, events and the ApplicationSet status.
+type SecretGenerator struct {
+	// Name is the name of the Secret. Either Name or LabelSelector must be set.
+	Name string `json:"name,omitempty"`
+	// LabelSelector selects the Secrets to generate parameters from.
+	LabelSelector metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// Key is an optional key of the Secrets, containing a JSON or YAML list of parameter sets. If not set, the data of
+	// each Secret is a single parameter set.
+	Key string `json:"key,omitempty"`
+	// RequeueAfterSeconds is how long before the Secrets are read again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// VaultGenerator defines a generator which retrieves its parameters from a HashiCorp Vault KV secrets engine. The
+// generated parameter values are redacted from logs, events and the ApplicationSet status.
+type VaultGenerator struct {
+	// Address is the URL of the Vault server.
+	Address string `json:"address"`
+	// Namespace is the optional Vault Enterprise namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Mount is the mount path of the KV secrets engine. Defaults to secret.
+	Mount string `json:"mount,omitempty"`
+	// KVVersion is the version of the KV secrets engine, 1 or 2. Defaults to 2.
+	KVVersion int `json:"kvVersion,omitempty"`
+	// Path of the secrets within the KV secrets engine. If Key is not set, each secret directly under the path is a
+	// parameter set. Otherwise, the path is a single secret.
+	Path string `json:"path"`
+	// Key is an optional key of the secret at Path, containing a JSON list of parameter sets.
+	Key string `json:"key,omitempty"`
+	// Auth defines how the controller authenticates against Vault.
+	Auth VaultAuth `json:"auth"`
+	// RequeueAfterSeconds is how long before the secrets are read again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// VaultAuth defines how to authenticate against Vault. Exactly one of TokenRef or Kubernetes must be set.
+type VaultAuth struct {
+	// TokenRef is a reference to a Secret key containing a Vault token.
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// Kubernetes authenticates with the Kubernetes auth method, using the service account of the controller.
+	Kubernetes *VaultKubernetesAuth `json:"kubernetes,omitempty"`
+}
+
+// VaultKubernetesAuth defines the Kubernetes auth method role to log in with.
+type VaultKubernetesAuth struct {
+	// Role is the name of the Vault role bound to the service account of the controller.
+	Role string `json:"role"`
+	// Mount is the mount path of the Kubernetes auth method. Defaults to kubernetes.
+	Mount string `json:"mount,omitempty"`
+}
+
+// ConsulGenerator defines a generator which retrieves its parameters from the service catalog or the KV store of
+// HashiCorp Consul. Exactly one of Services or KV must be set.
+type ConsulGenerator struct {
+	// Address is the URL of the Consul HTTP API.
+	Address string `json:"address"`
+	// TokenRef is an optional reference to a Secret key containing a Consul ACL token.
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// Datacenters to query. Defaults to the datacenter of the Consul agent at Address.
+	Datacenters []string `json:"datacenters,omitempty"`
+	// Services generates a parameter set for each service of the catalog.
+	Services *ConsulServices `json:"services,omitempty"`
+	// KV generates a parameter set for each key under a prefix of the KV store.
+	KV *ConsulKV `json:"kv,omitempty"`
+	// RequeueAfterSeconds is how long before Consul is queried again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// ConsulServices filters the services of the Consul catalog.
+type ConsulServices struct {
+	// Tags the services must all have to be selected.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ConsulKV selects the keys of the Consul KV store.
+type ConsulKV struct {
+	// Prefix of the keys.
+	Prefix string `json:"prefix"`
+}
+
+// HelmRepositoryGenerator defines a generator which retrieves its parameters from the chart versions of a Helm
+// repository.
+type HelmRepositoryGenerator struct {
+	// RepoURL is the URL of a Helm repository serving an index.yaml, or of an OCI registry prefixed with oci://.
+	RepoURL string `json:"repoURL"`
+	// Charts are the names of the charts. Defaults to all the charts of the index.yaml; required for OCI registries.
+	Charts []string `json:"charts,omitempty"`
+	// Version is a semantic version constraint (e.g. ~1.2) the chart versions must match. Defaults to any version
+	// without a pre-release.
+	Version string `json:"version,omitempty"`
+	// AllVersions generates a parameter set for each matching version of a chart, rather than only for the latest.
+	AllVersions bool `json:"allVersions,omitempty"`
+	// Username of the repository.
+	Username string `json:"username,omitempty"`
+	// PasswordRef is a reference to a Secret key containing the password, or token, of the repository.
+	PasswordRef *SecretRef `json:"passwordRef,omitempty"`
+	// RequeueAfterSeconds is how long before the repository is queried again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// BucketGenerator defines a generator which retrieves its parameters from the JSON or YAML objects of an object storage
+// bucket. Exactly one of S3 or GCS must be set.
+type BucketGenerator struct {
+	S3  *BucketGeneratorS3  `json:"s3,omitempty"`
+	GCS *BucketGeneratorGCS `json:"gcs,omitempty"`
+	// Prefix of the keys of the objects. Only the objects with a .json, .yaml or .yml extension are read.
+	Prefix string `json:"prefix,omitempty"`
+	// RequeueAfterSeconds is how long before the objects are read again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// BucketGeneratorS3 defines a connection info specific to Amazon S3 and S3 compatible object storages.
+type BucketGeneratorS3 struct {
+	// Name of the bucket. Required.
+	Bucket string `json:"bucket"`
+	// Region of the bucket. Required.
+	Region string `json:"region"`
+	// The URL of an S3 compatible object storage. If blank, use the Amazon S3 endpoint of the region.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Access key ID reference. If blank, use the IAM role of the service account of the controller.
+	AccessKeyIDRef *SecretRef `json:"accessKeyIDRef,omitempty"`
+	// Secret access key reference.
+	SecretAccessKeyRef *SecretRef `json:"secretAccessKeyRef,omitempty"`
+}
+
+// BucketGeneratorGCS defines a connection info specific to Google Cloud Storage.
+type BucketGeneratorGCS struct {
+	// Name of the bucket. Required.
+	Bucket string `json:"bucket"`
+	// The Google Cloud Storage API URL to talk to. If blank, use https://storage.googleapis.com.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Service account JSON key reference. If blank, use the Workload Identity of the service account of the
+	// controller.
+	ServiceAccountKeyRef *SecretRef `json:"serviceAccountKeyRef,omitempty"`
+}
+
+// TerraformStateGenerator defines a generator which retrieves its parameters from the outputs of a Terraform state.
+// Exactly one of S3, GCS, HTTP or Remote must be set.
+type TerraformStateGenerator struct {
+	// S3 reads the state stored by the s3 backend in a bucket, at Key.
+	S3 *BucketGeneratorS3 `json:"s3,omitempty"`
+	// GCS reads the state stored by the gcs backend in a bucket, at Key.
+	GCS *BucketGeneratorGCS `json:"gcs,omitempty"`
+	// Key of the state in the S3 or GCS bucket, e.g. network/terraform.tfstate.
+	Key string `json:"key,omitempty"`
+	// HTTP reads the state stored by the http backend.
+	HTTP *TerraformStateHTTP `json:"http,omitempty"`
+	// Remote reads the current state of a workspace of Terraform Cloud or Terraform Enterprise.
+	Remote *TerraformStateRemote `json:"remote,omitempty"`
+	// Outputs are the names of the outputs passed as outputs.<name> parameters. Defaults to all the outputs.
+	Outputs []string `json:"outputs,omitempty"`
+	// ForEach is the name of a list or map output. If set, a parameter set is generated for each of its elements.
+	ForEach string `json:"forEach,omitempty"`
+	// RequeueAfterSeconds is how long before the state is read again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// TerraformStateHTTP defines a connection info specific to the http backend.
+type TerraformStateHTTP struct {
+	// Address of the state. Required.
+	Address string `json:"address"`
+	// Username of the basic authentication.
+	Username string `json:"username,omitempty"`
+	// Password reference of the basic authentication.
+	PasswordRef *SecretRef `json:"passwordRef,omitempty"`
+}
+
+// TerraformStateRemote defines a connection info specific to Terraform Cloud and Terraform Enterprise.
+type TerraformStateRemote struct {
+	// The Terraform Enterprise URL to talk to. If blank, use Terraform Cloud.
+	Address string `json:"address,omitempty"`
+	// Organization of the workspace. Required.
+	Organization string `json:"organization"`
+	// Name of the workspace. Required.
+	Workspace string `json:"workspace"`
+	// API token reference. Required.
+	TokenRef SecretRef `json:"tokenRef"`
+}
+
+// SQLGenerator defines a generator which retrieves its parameters from the rows returned by a SQL query.
+type SQLGenerator struct {
+	// Driver is the database driver, postgres or mysql.
+	Driver string `json:"driver"`
+	// DSNRef is a reference to a Secret key containing the data source name of the database.
+	DSNRef SecretRef `json:"dsnRef"`
+	// Query is run in a read-only transaction. Each row is a parameter set, with a parameter for each column.
+	Query string `json:"query"`
+	// RequeueAfterSeconds is how long before the query is run again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
+	// Values contains key/value pairs which are passed directly as parameters to the template
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// HTTPGenerator defines a generator which retrieves its parameters from a JSON HTTP endpoint.
+type HTTPGenerator struct {
+	// URL is queried with a GET request on each reconciliation.
+	URL string `json:"url"`
+	// Headers are added to the request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// TokenRef is a reference to a Secret key containing a token, sent as a bearer token in the Authorization header.
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// JSONPath is an optional JSONPath expression (e.g. {.items}) selecting the array of objects to convert to
+	// parameter sets within the response. If not set, the response must be an array of objects.
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Proxy is the URL of the HTTP or HTTPS proxy of the request, overriding the --proxy-url of the controller.
+	Proxy string `json:"proxy,omitempty"`
+	// CARef is a reference to a Secret key containing the PEM-encoded certificates of the CAs trusted in addition to
+	// the system CAs, e.g. the private CA of the endpoint.
+	CARef *SecretRef `json:"caRef,omitempty"`
+	// Insecure disables the verification of the TLS certificate of the endpoint.
+	Insecure bool `json:"insecure,omitempty"`
+	// RequeueAfterSeconds is how long before the endpoint is queried again.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
 	Template ApplicationSetTemplate `json:"template,omitempty"`
 	// Values contains key/value pairs which are passed directly as parameters to the template
 	Values map[string]string `json:"values,omitempty"`
@@ -232,7 +918,10 @@ type GitGenerator struct {
 	Files               []GitFileGeneratorItem      `json:"files,omitempty"`
 	Revision            string                      `json:"revision"`
 	RequeueAfterSeconds *int64                      `json:"requeueAfterSeconds,omitempty"`
-	Template            ApplicationSetTemplate      `json:"template,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
 }
 
 type GitDirectoryGeneratorItem struct {
@@ -254,9 +943,20 @@ type SCMProviderGenerator struct {
 	// Which protocol to use for the SCM URL. Default is provider-specific but ssh if possible. Not all providers
 	// necessarily support all protocols.
 	CloneProtocol string `json:"cloneProtocol,omitempty"`
+	// Proxy is the URL of the HTTP or HTTPS proxy of the requests to the provider API, overriding the --proxy-url of
+	// the controller.
+	Proxy string `json:"proxy,omitempty"`
+	// CARef is a reference to a Secret key containing the PEM-encoded certificates of the CAs trusted in addition to
+	// the system CAs, e.g. the private CA of a self-hosted provider.
+	CARef *SecretRef `json:"caRef,omitempty"`
+	// Insecure disables the verification of the TLS certificates of the provider API.
+	Insecure bool `json:"insecure,omitempty"`
 	// Standard parameters.
-	RequeueAfterSeconds *int64                 `json:"requeueAfterSeconds,omitempty"`
-	Template            ApplicationSetTemplate `json:"template,omitempty"`
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
 }
 
 // SCMProviderGeneratorGithub defines a connection info specific to GitHub.
@@ -267,10 +967,23 @@ type SCMProviderGeneratorGithub struct {
 	API string `json:"api,omitempty"`
 	// Authentication token reference.
 	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// Authentication with a GitHub App, instead of a token.
+	GithubApp *GithubAppRef `json:"githubApp,omitempty"`
 	// Scan all branches instead of just the default branch.
 	AllBranches bool `json:"allBranches,omitempty"`
 }
 
+// GithubAppRef references the credentials of a GitHub App installation, whose installation tokens authenticate the
+// requests to GitHub.
+type GithubAppRef struct {
+	// ID of the GitHub App. Required.
+	AppID int64 `json:"appID"`
+	// ID of the installation of the GitHub App in the organization or the user account. Required.
+	InstallationID int64 `json:"installationID"`
+	// Reference to the PEM-encoded private key of the GitHub App. Required.
+	PrivateKeyRef SecretRef `json:"privateKeyRef"`
+}
+
 // SCMProviderGeneratorGitlab defines a connection info specific to Gitlab.
 type SCMProviderGeneratorGitlab struct {
 	// Gitlab group to scan. Required.  You can use either the project id (recommended) or the full namespaced path.
@@ -302,10 +1015,28 @@ type SCMProviderGeneratorFilter struct {
 // PullRequestGenerator defines a generator that scrapes a PullRequest API to find candidate pull requests.
 type PullRequestGenerator struct {
 	// Which provider to use and config for it.
-	Github *PullRequestGeneratorGithub `json:"github,omitempty"`
+	Github      *PullRequestGeneratorGithub      `json:"github,omitempty"`
+	Gitea       *PullRequestGeneratorGitea       `json:"gitea,omitempty"`
+	AzureDevOps *PullRequestGeneratorAzureDevOps `json:"azuredevops,omitempty"`
+	GitLab      *PullRequestGeneratorGitLab      `json:"gitlab,omitempty"`
+	// Filters for which pull requests should be considered.
+	Filters []PullRequestGeneratorFilter `json:"filters,omitempty"`
+	// ShortSHALength is the number of characters of the head SHA exposed as head_short_sha. Defaults to 7.
+	ShortSHALength *int `json:"shortSHALength,omitempty"`
+	// Proxy is the URL of the HTTP or HTTPS proxy of the requests to the provider API, overriding the --proxy-url of
+	// the controller.
+	Proxy string `json:"proxy,omitempty"`
+	// CARef is a reference to a Secret key containing the PEM-encoded certificates of the CAs trusted in addition to
+	// the system CAs, e.g. the private CA of a self-hosted provider.
+	CARef *SecretRef `json:"caRef,omitempty"`
+	// Insecure disables the verification of the TLS certificates of the provider API.
+	Insecure bool `json:"insecure,omitempty"`
 	// Standard parameters.
-	RequeueAfterSeconds *int64                 `json:"requeueAfterSeconds,omitempty"`
-	Template            ApplicationSetTemplate `json:"template,omitempty"`
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template ApplicationSetTemplate `json:"template,omitempty"`
 }
 
 // PullRequestGenerator defines a connection info specific to GitHub.
@@ -318,8 +1049,88 @@ type PullRequestGeneratorGithub struct {
 	API string `json:"api,omitempty"`
 	// Authentication token reference.
 	TokenRef *SecretRef `json:"tokenRef,omitempty"`
-	// Labels is used to filter the PRs that you want to target
+	// Authentication with a GitHub App, instead of a token.
+	GithubApp *GithubAppRef `json:"githubApp,omitempty"`
+	// Labels is used to filter the PRs that you want to target. All of the labels must be present.
+	Labels []string `json:"labels,omitempty"`
+	// AnyLabels is used to filter the PRs that you want to target. At least one of the labels must be present.
+	AnyLabels []string `json:"anyLabels,omitempty"`
+	// NotLabels is used to exclude PRs. None of the labels may be present.
+	NotLabels []string `json:"notLabels,omitempty"`
+	// ExcludeDrafts excludes draft PRs.
+	ExcludeDrafts bool `json:"excludeDrafts,omitempty"`
+}
+
+// PullRequestGeneratorAzureDevOps defines a connection info specific to Azure DevOps (Azure Repos).
+type PullRequestGeneratorAzureDevOps struct {
+	// Azure DevOps org to scan. Required.
+	Organization string `json:"organization"`
+	// Azure DevOps project name to scan. Required.
+	Project string `json:"project"`
+	// Azure DevOps repo name to scan. Required.
+	Repo string `json:"repo"`
+	// The Azure DevOps API URL to talk to. If blank, use https://dev.azure.com/.
+	API string `json:"api,omitempty"`
+	// Authentication token reference, pointing to a personal access token (PAT).
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// TargetBranch, if set, only includes the PRs that target the given branch.
+	TargetBranch string `json:"targetBranch,omitempty"`
+	// Labels is used to filter the PRs that you want to target. All of the labels must be present.
+	Labels []string `json:"labels,omitempty"`
+	// AnyLabels is used to filter the PRs that you want to target. At least one of the labels must be present.
+	AnyLabels []string `json:"anyLabels,omitempty"`
+	// NotLabels is used to exclude PRs. None of the labels may be present.
+	NotLabels []string `json:"notLabels,omitempty"`
+	// ExcludeDrafts excludes draft PRs.
+	ExcludeDrafts bool `json:"excludeDrafts,omitempty"`
+}
+
+// PullRequestGeneratorGitea defines a connection info specific to Gitea.
+type PullRequestGeneratorGitea struct {
+	// Gitea org or user to scan. Required.
+	Owner string `json:"owner"`
+	// Gitea repo name to scan. Required.
+	Repo string `json:"repo"`
+	// The Gitea URL to talk to. For example https://gitea.mydomain.com/. Required.
+	API string `json:"api"`
+	// Authentication token reference.
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// Allow insecure tls, for self-signed certificates; default: false.
+	Insecure bool `json:"insecure,omitempty"`
+	// Labels is used to filter the PRs that you want to target. All of the labels must be present.
+	Labels []string `json:"labels,omitempty"`
+	// AnyLabels is used to filter the PRs that you want to target. At least one of the labels must be present.
+	AnyLabels []string `json:"anyLabels,omitempty"`
+	// NotLabels is used to exclude PRs. None of the labels may be present.
+	NotLabels []string `json:"notLabels,omitempty"`
+}
+
+// PullRequestGeneratorGitLab defines a connection info specific to GitLab.
+type PullRequestGeneratorGitLab struct {
+	// GitLab project to scan, either its numeric ID or its full path (e.g. mygroup/myproject). Required.
+	Project string `json:"project"`
+	// The GitLab API URL to talk to. If blank, use https://gitlab.com/.
+	API string `json:"api,omitempty"`
+	// Authentication token reference.
+	TokenRef *SecretRef `json:"tokenRef,omitempty"`
+	// Labels is used to filter the MRs that you want to target. All of the labels must be present.
 	Labels []string `json:"labels,omitempty"`
+	// AnyLabels is used to filter the MRs that you want to target. At least one of the labels must be present.
+	AnyLabels []string `json:"anyLabels,omitempty"`
+	// NotLabels is used to exclude MRs. None of the labels may be present.
+	NotLabels []string `json:"notLabels,omitempty"`
+	// ExcludeDrafts excludes draft MRs.
+	ExcludeDrafts bool `json:"excludeDrafts,omitempty"`
+}
+
+// PullRequestGeneratorFilter is a single pull request filter.
+// If multiple filter types are set on a single struct, they will be AND'd together. All filters must
+// pass for a pull request to be included.
+type PullRequestGeneratorFilter struct {
+	// A regex which must match the name of the branch the pull request originated from.
+	BranchMatch *string `json:"branchMatch,omitempty"`
+	// A regex which must match the name of the branch the pull request targets.
+	TargetBranchMatch *string `json:"targetBranchMatch,omitempty"`
 }
 
 // ApplicationSetStatus defines the observed state of ApplicationSet
@@ -327,8 +1138,134 @@ type ApplicationSetStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions []ApplicationSetCondition `json:"conditions,omitempty"`
+	// DryRunActions are the changes which would be made to the Applications, if the ApplicationSet was not in dry run
+	// mode.
+	DryRunActions []ApplicationSetDryRunAction `json:"dryRunActions,omitempty"`
+	// Rollout is the progress of the RollingSync strategy.
+	Rollout *ApplicationSetRolloutStatus `json:"rollout,omitempty"`
+	// GeneratorBackoff is the backoff of the generators, after they failed in consecutive reconciliations. It is
+	// removed once the generators succeed.
+	GeneratorBackoff *ApplicationSetGeneratorBackoffStatus `json:"generatorBackoff,omitempty"`
+	// PendingDeletions are the Applications which are no longer generated, and are deleted once the prune grace period
+	// of the ApplicationSet has elapsed.
+	PendingDeletions []ApplicationSetPendingDeletion `json:"pendingDeletions,omitempty"`
+	// LastDeletionBatchTime is the time at which the last batch of Applications was deleted, if the ApplicationSet
+	// deletes its Applications by batches.
+	LastDeletionBatchTime *metav1.Time `json:"lastDeletionBatchTime,omitempty"`
+	// ApplicationStatus is the health and sync status of each Application of the ApplicationSet.
+	ApplicationStatus []ApplicationSetApplicationStatus `json:"applicationStatus,omitempty"`
+	// History is the history of the creations, updates and deletions of the Applications, oldest first, if the
+	// ApplicationSet sets a history limit.
+	History []ApplicationSetHistoryEntry `json:"history,omitempty"`
+	// Parameters is the summary of the param sets generated by the generators.
+	Parameters *ApplicationSetParametersStatus `json:"parameters,omitempty"`
+}
+
+// ApplicationSetParametersStatus is the summary of the param sets generated by the generators of an ApplicationSet.
+type ApplicationSetParametersStatus struct {
+	// Count is the number of the param sets generated by all the generators.
+	Count int64 `json:"count"`
+	// Hash is the hash of the param sets, which changes whenever they change.
+	Hash string `json:"hash,omitempty"`
+	// LastGeneratedTime is the time at which the param sets were last generated. It is refreshed whenever the param
+	// sets change, and periodically otherwise.
+	LastGeneratedTime *metav1.Time `json:"lastGeneratedTime,omitempty"`
+	// Generators are the number of the param sets generated by each generator, in the order of the generators.
+	Generators []ApplicationSetGeneratorParametersStatus `json:"generators,omitempty"`
+}
+
+// ApplicationSetGeneratorParametersStatus is the number of the param sets generated by a generator of an ApplicationSet.
+type ApplicationSetGeneratorParametersStatus struct {
+	// Generator is the type of the generator, e.g. List or Matrix.
+	Generator string `json:"generator"`
+	// Count is the number of the param sets generated by the generator.
+	Count int64 `json:"count"`
+	// Stale is true if the generator failed, and its last known param sets were used.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// ApplicationSetHistoryEntry is a creation, update or deletion of an Application of an ApplicationSet, with the hashes
+// of the param set and of the rendered Application before and after the change, which tell whether it was caused by a
+// change of the params or of the template.
+type ApplicationSetHistoryEntry struct {
+	// Time is the time of the change.
+	Time metav1.Time `json:"time"`
+	// Application is the name of the Application.
+	Application string `json:"application"`
+	// Action is the change to the Application: create, update or delete.
+	Action ApplicationSetDryRunActionType `json:"action"`
+	// ReconcileID is the identifier of the reconciliation which made the change, as logged by the controller.
+	ReconcileID string `json:"reconcileID,omitempty"`
+	// PreviousParamsHash is the hash of the param set the Application was generated from before the change.
+	PreviousParamsHash string `json:"previousParamsHash,omitempty"`
+	// ParamsHash is the hash of the param set the Application is generated from after the change.
+	ParamsHash string `json:"paramsHash,omitempty"`
+	// PreviousRenderedHash is the hash of the rendered Application before the change.
+	PreviousRenderedHash string `json:"previousRenderedHash,omitempty"`
+	// RenderedHash is the hash of the rendered Application after the change.
+	RenderedHash string `json:"renderedHash,omitempty"`
+}
+
+// ApplicationSetApplicationStatus is the health and sync status of an Application of an ApplicationSet.
+type ApplicationSetApplicationStatus struct {
+	// Application is the name of the Application.
+	Application string `json:"application"`
+	// Health is the health status of the Application.
+	Health health.HealthStatusCode `json:"health,omitempty"`
+	// Sync is the sync status of the Application.
+	Sync v1alpha1.SyncStatusCode `json:"sync,omitempty"`
+	// LastTransitionTime is the time at which the health or sync status of the Application last changed.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ApplicationSetPendingDeletion is an Application which is no longer generated by its ApplicationSet, and whose
+// deletion is delayed by the prune grace period.
+type ApplicationSetPendingDeletion struct {
+	// Name is the name of the Application.
+	Name string `json:"name"`
+	// Since is the time from which the Application is no longer generated.
+	Since metav1.Time `json:"since"`
+	// DeletionTime is the time after which the Application is deleted.
+	DeletionTime metav1.Time `json:"deletionTime"`
+}
+
+// ApplicationSetGeneratorBackoffStatus is the backoff of the generators of an ApplicationSet, after they failed in
+// consecutive reconciliations.
+type ApplicationSetGeneratorBackoffStatus struct {
+	// ConsecutiveFailures is the number of consecutive reconciliations in which the generators failed.
+	ConsecutiveFailures int64 `json:"consecutiveFailures"`
+	// ObservedGeneration is the generation of the ApplicationSet when the generators last failed. The backoff is reset
+	// when the spec of the ApplicationSet changes.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// NextRetryTime, if set, is the time before which the generators are not run again, unless the ApplicationSet is
+	// refreshed.
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// ApplicationSetRolloutStatus is the progress of the RollingSync strategy of an ApplicationSet.
+type ApplicationSetRolloutStatus struct {
+	// CurrentStep is the index of the first step whose Applications are not rolled out yet, or the number of steps
+	// once the Applications of all the steps are rolled out.
+	CurrentStep int64 `json:"currentStep"`
 }
 
+// ApplicationSetDryRunAction is a change which would be made to an Application of an ApplicationSet in dry run mode.
+type ApplicationSetDryRunAction struct {
+	// Application is the name of the Application.
+	Application string `json:"application"`
+	// Action is the change to the Application: create, update or delete.
+	Action ApplicationSetDryRunActionType `json:"action"`
+}
+
+// ApplicationSetDryRunActionType is the type of a change to an Application.
+type ApplicationSetDryRunActionType string
+
+const (
+	ApplicationSetDryRunActionCreate ApplicationSetDryRunActionType = "create"
+	ApplicationSetDryRunActionUpdate ApplicationSetDryRunActionType = "update"
+	ApplicationSetDryRunActionDelete ApplicationSetDryRunActionType = "delete"
+)
+
 // ApplicationSetCondition contains details about an applicationset condition, which is usally an error or warning
 type ApplicationSetCondition struct {
 	// Type is an applicationset condition type
@@ -362,7 +1299,7 @@ const (
 // prefix "Info" means informational condition
 type ApplicationSetConditionType string
 
-//ErrorOccurred / ParametersGenerated / TemplateRendered / ResourcesUpToDate
+// ErrorOccurred / ParametersGenerated / TemplateRendered / ResourcesUpToDate
 const (
 	ApplicationSetConditionErrorOccurred       ApplicationSetConditionType = "ErrorOccurred"
 	ApplicationSetConditionParametersGenerated ApplicationSetConditionType = "ParametersGenerated"
@@ -383,6 +1320,16 @@ const (
 	ApplicationSetReasonDeleteApplicationError           = "DeleteApplicationError"
 	ApplicationSetReasonRefreshApplicationError          = "RefreshApplicationError"
 	ApplicationSetReasonApplicationValidationError       = "ApplicationValidationError"
+	ApplicationSetReasonInvalidApplicationsSyncPolicy    = "InvalidApplicationsSyncPolicy"
+	ApplicationSetReasonDryRunError                      = "DryRunError"
+	ApplicationSetReasonStrategyError                    = "StrategyError"
+	ApplicationSetReasonDeletionThresholdExceeded        = "DeletionThresholdExceeded"
+	ApplicationSetReasonSuspended                        = "Suspended"
+	ApplicationSetReasonDeletionProtected                = "DeletionProtected"
+	ApplicationSetReasonApplicationNameConflict          = "ApplicationNameConflict"
+	ApplicationSetReasonApplicationNotAllowed            = "ApplicationNotAllowed"
+	ApplicationSetReasonTemplatedProjectForbidden        = "TemplatedProjectForbidden"
+	ApplicationSetReasonParamSetsOverflow                = "ParamSetsOverflow"
 )
 
 // ApplicationSetList contains a list of ApplicationSet
@@ -403,6 +1350,51 @@ func (a *ApplicationSet) RefreshRequired() bool {
 	return found
 }
 
+// HardRefreshRequired checks if the ApplicationSet needs to be refreshed without using the last known parameters of
+// its generators
+func (a *ApplicationSet) HardRefreshRequired() bool {
+	return a.Annotations[common.AnnotationApplicationSetRefresh] == common.RefreshTypeHard
+}
+
+// PreserveApplicationsOnDeletion returns true if the Applications of the ApplicationSet are orphaned, rather than
+// deleted, when the ApplicationSet is deleted.
+func (a *ApplicationSet) PreserveApplicationsOnDeletion() bool {
+	return a.Spec.SyncPolicy != nil && a.Spec.SyncPolicy.PreserveApplicationsOnDeletion
+}
+
+// AdoptExisting returns true if the existing Applications which are not owned by any ApplicationSet are adopted by the
+// ApplicationSet when it generates Applications with their names, which is the default.
+func (a *ApplicationSet) AdoptExisting() bool {
+	return a.Spec.SyncPolicy == nil || a.Spec.SyncPolicy.AdoptExisting == nil || *a.Spec.SyncPolicy.AdoptExisting
+}
+
+// MaxDeletionPercentage returns the maximum percentage of the Applications of the ApplicationSet which may be deleted by
+// a reconciliation, or the given default if the ApplicationSet doesn't set it.
+func (a *ApplicationSet) MaxDeletionPercentage(defaultPercentage int64) int64 {
+	if a.Spec.SyncPolicy != nil && a.Spec.SyncPolicy.MaxDeletionPercentage != nil {
+		return *a.Spec.SyncPolicy.MaxDeletionPercentage
+	}
+	return defaultPercentage
+}
+
+// PruneAfter returns the grace period after which the Applications which are no longer generated by the ApplicationSet
+// are deleted, or 0 if they are deleted right away.
+func (a *ApplicationSet) PruneAfter() time.Duration {
+	if a.Spec.SyncPolicy == nil || a.Spec.SyncPolicy.PruneAfter == nil {
+		return 0
+	}
+	return a.Spec.SyncPolicy.PruneAfter.Duration
+}
+
+// HistoryLimit returns the maximum number of entries of the history of the changes to the Applications of the
+// ApplicationSet, or 0 if the history is not recorded.
+func (a *ApplicationSet) HistoryLimit() int {
+	if a.Spec.HistoryLimit == nil || *a.Spec.HistoryLimit < 0 {
+		return 0
+	}
+	return int(*a.Spec.HistoryLimit)
+}
+
 // SetConditions updates the applicationset status conditions for a subset of evaluated types.
 // If the applicationset has a pre-existing condition of a type that is not in the evaluated list,
 // it will be preserved. If the applicationset has a pre-existing condition of a type, status, reason that
@@ -33,4 +33,12 @@ var (
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
+
+	// SchemeGroupVersion is the group version of the typed clients of pkg/client, generated by client-gen
+	SchemeGroupVersion = GroupVersion
 )
+
+// Resource takes an unqualified resource and returns a group qualified GroupResource, for the listers of pkg/client
+func Resource(resource string) schema.GroupResource {
+	return GroupVersion.WithResource(resource).GroupResource()
+}
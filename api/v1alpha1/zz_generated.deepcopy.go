@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -22,7 +23,9 @@ package v1alpha1
 
 import (
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -52,6 +55,25 @@ func (in *ApplicationSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetApplicationStatus) DeepCopyInto(out *ApplicationSetApplicationStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetApplicationStatus.
+func (in *ApplicationSetApplicationStatus) DeepCopy() *ApplicationSetApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSetCondition) DeepCopyInto(out *ApplicationSetCondition) {
 	*out = *in
@@ -71,6 +93,41 @@ func (in *ApplicationSetCondition) DeepCopy() *ApplicationSetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetDeletionBatches) DeepCopyInto(out *ApplicationSetDeletionBatches) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetDeletionBatches.
+func (in *ApplicationSetDeletionBatches) DeepCopy() *ApplicationSetDeletionBatches {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetDeletionBatches)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetDryRunAction) DeepCopyInto(out *ApplicationSetDryRunAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetDryRunAction.
+func (in *ApplicationSetDryRunAction) DeepCopy() *ApplicationSetDryRunAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetDryRunAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSetGenerator) DeepCopyInto(out *ApplicationSetGenerator) {
 	*out = *in
@@ -104,6 +161,61 @@ func (in *ApplicationSetGenerator) DeepCopyInto(out *ApplicationSetGenerator) {
 		*out = new(PullRequestGenerator)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesResource != nil {
+		in, out := &in.KubernetesResource, &out.KubernetesResource
+		*out = new(KubernetesResourceGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureSubscriptions != nil {
+		in, out := &in.AzureSubscriptions, &out.AzureSubscriptions
+		*out = new(AzureSubscriptionsGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consul != nil {
+		in, out := &in.Consul, &out.Consul
+		*out = new(ConsulGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HelmRepository != nil {
+		in, out := &in.HelmRepository, &out.HelmRepository
+		*out = new(HelmRepositoryGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(BucketGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(SQLGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerraformState != nil {
+		in, out := &in.TerraformState, &out.TerraformState
+		*out = new(TerraformStateGenerator)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Matrix != nil {
 		in, out := &in.Matrix, &out.Matrix
 		*out = new(MatrixGenerator)
@@ -114,6 +226,16 @@ func (in *ApplicationSetGenerator) DeepCopyInto(out *ApplicationSetGenerator) {
 		*out = new(MergeGenerator)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Union != nil {
+		in, out := &in.Union, &out.Union
+		*out = new(UnionGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(GeneratorSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetGenerator.
@@ -126,6 +248,56 @@ func (in *ApplicationSetGenerator) DeepCopy() *ApplicationSetGenerator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetGeneratorParametersStatus) DeepCopyInto(out *ApplicationSetGeneratorParametersStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetGeneratorParametersStatus.
+func (in *ApplicationSetGeneratorParametersStatus) DeepCopy() *ApplicationSetGeneratorParametersStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetGeneratorParametersStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetGeneratorBackoffStatus) DeepCopyInto(out *ApplicationSetGeneratorBackoffStatus) {
+	*out = *in
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetGeneratorBackoffStatus.
+func (in *ApplicationSetGeneratorBackoffStatus) DeepCopy() *ApplicationSetGeneratorBackoffStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetGeneratorBackoffStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetHistoryEntry) DeepCopyInto(out *ApplicationSetHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetHistoryEntry.
+func (in *ApplicationSetHistoryEntry) DeepCopy() *ApplicationSetHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSetList) DeepCopyInto(out *ApplicationSetList) {
 	*out = *in
@@ -191,6 +363,61 @@ func (in *ApplicationSetNestedGenerator) DeepCopyInto(out *ApplicationSetNestedG
 		*out = new(PullRequestGenerator)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesResource != nil {
+		in, out := &in.KubernetesResource, &out.KubernetesResource
+		*out = new(KubernetesResourceGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureSubscriptions != nil {
+		in, out := &in.AzureSubscriptions, &out.AzureSubscriptions
+		*out = new(AzureSubscriptionsGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consul != nil {
+		in, out := &in.Consul, &out.Consul
+		*out = new(ConsulGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HelmRepository != nil {
+		in, out := &in.HelmRepository, &out.HelmRepository
+		*out = new(HelmRepositoryGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(BucketGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(SQLGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerraformState != nil {
+		in, out := &in.TerraformState, &out.TerraformState
+		*out = new(TerraformStateGenerator)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Matrix != nil {
 		in, out := &in.Matrix, &out.Matrix
 		*out = new(NestedMatrixGenerator)
@@ -201,6 +428,11 @@ func (in *ApplicationSetNestedGenerator) DeepCopyInto(out *ApplicationSetNestedG
 		*out = new(NestedMergeGenerator)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Union != nil {
+		in, out := &in.Union, &out.Union
+		*out = new(NestedUnionGenerator)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetNestedGenerator.
@@ -235,220 +467,1222 @@ func (in ApplicationSetNestedGenerators) DeepCopy() ApplicationSetNestedGenerato
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetSpec) DeepCopyInto(out *ApplicationSetSpec) {
+func (in *ApplicationSetOrder) DeepCopyInto(out *ApplicationSetOrder) {
 	*out = *in
-	if in.Generators != nil {
-		in, out := &in.Generators, &out.Generators
-		*out = make([]ApplicationSetGenerator, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Template.DeepCopyInto(&out.Template)
-	if in.SyncPolicy != nil {
-		in, out := &in.SyncPolicy, &out.SyncPolicy
-		*out = new(ApplicationSetSyncPolicy)
-		**out = **in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetSpec.
-func (in *ApplicationSetSpec) DeepCopy() *ApplicationSetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetOrder.
+func (in *ApplicationSetOrder) DeepCopy() *ApplicationSetOrder {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetSpec)
+	out := new(ApplicationSetOrder)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetStatus) DeepCopyInto(out *ApplicationSetStatus) {
+func (in *ApplicationSetParametersStatus) DeepCopyInto(out *ApplicationSetParametersStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ApplicationSetCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastGeneratedTime != nil {
+		in, out := &in.LastGeneratedTime, &out.LastGeneratedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetGeneratorParametersStatus, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetStatus.
-func (in *ApplicationSetStatus) DeepCopy() *ApplicationSetStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetParametersStatus.
+func (in *ApplicationSetParametersStatus) DeepCopy() *ApplicationSetParametersStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetStatus)
+	out := new(ApplicationSetParametersStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetSyncPolicy) DeepCopyInto(out *ApplicationSetSyncPolicy) {
+func (in *ApplicationSetPendingDeletion) DeepCopyInto(out *ApplicationSetPendingDeletion) {
 	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	in.DeletionTime.DeepCopyInto(&out.DeletionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetSyncPolicy.
-func (in *ApplicationSetSyncPolicy) DeepCopy() *ApplicationSetSyncPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetPendingDeletion.
+func (in *ApplicationSetPendingDeletion) DeepCopy() *ApplicationSetPendingDeletion {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetSyncPolicy)
+	out := new(ApplicationSetPendingDeletion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetTemplate) DeepCopyInto(out *ApplicationSetTemplate) {
+func (in *ApplicationSetResourceIgnoreDifferences) DeepCopyInto(out *ApplicationSetResourceIgnoreDifferences) {
 	*out = *in
-	in.ApplicationSetTemplateMeta.DeepCopyInto(&out.ApplicationSetTemplateMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.JSONPointers != nil {
+		in, out := &in.JSONPointers, &out.JSONPointers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JQPathExpressions != nil {
+		in, out := &in.JQPathExpressions, &out.JQPathExpressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTemplate.
-func (in *ApplicationSetTemplate) DeepCopy() *ApplicationSetTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetResourceIgnoreDifferences.
+func (in *ApplicationSetResourceIgnoreDifferences) DeepCopy() *ApplicationSetResourceIgnoreDifferences {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetTemplate)
+	out := new(ApplicationSetResourceIgnoreDifferences)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetTemplateMeta) DeepCopyInto(out *ApplicationSetTemplateMeta) {
+func (in *ApplicationSetRolloutStatus) DeepCopyInto(out *ApplicationSetRolloutStatus) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Finalizers != nil {
-		in, out := &in.Finalizers, &out.Finalizers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTemplateMeta.
-func (in *ApplicationSetTemplateMeta) DeepCopy() *ApplicationSetTemplateMeta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetRolloutStatus.
+func (in *ApplicationSetRolloutStatus) DeepCopy() *ApplicationSetRolloutStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetTemplateMeta)
+	out := new(ApplicationSetRolloutStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApplicationSetTerminalGenerator) DeepCopyInto(out *ApplicationSetTerminalGenerator) {
+func (in *ApplicationSetRolloutStep) DeepCopyInto(out *ApplicationSetRolloutStep) {
 	*out = *in
-	if in.List != nil {
-		in, out := &in.List, &out.List
-		*out = new(ListGenerator)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Clusters != nil {
-		in, out := &in.Clusters, &out.Clusters
-		*out = new(ClusterGenerator)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Git != nil {
-		in, out := &in.Git, &out.Git
-		*out = new(GitGenerator)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SCMProvider != nil {
-		in, out := &in.SCMProvider, &out.SCMProvider
-		*out = new(SCMProviderGenerator)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ClusterDecisionResource != nil {
-		in, out := &in.ClusterDecisionResource, &out.ClusterDecisionResource
-		*out = new(DuckTypeGenerator)
-		(*in).DeepCopyInto(*out)
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]metav1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.PullRequest != nil {
-		in, out := &in.PullRequest, &out.PullRequest
-		*out = new(PullRequestGenerator)
-		(*in).DeepCopyInto(*out)
+	if in.HealthyPercentage != nil {
+		in, out := &in.HealthyPercentage, &out.HealthyPercentage
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTerminalGenerator.
-func (in *ApplicationSetTerminalGenerator) DeepCopy() *ApplicationSetTerminalGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetRolloutStep.
+func (in *ApplicationSetRolloutStep) DeepCopy() *ApplicationSetRolloutStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetTerminalGenerator)
+	out := new(ApplicationSetRolloutStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ApplicationSetTerminalGenerators) DeepCopyInto(out *ApplicationSetTerminalGenerators) {
-	{
-		in := &in
-		*out = make(ApplicationSetTerminalGenerators, len(*in))
+func (in *ApplicationSetRolloutStrategy) DeepCopyInto(out *ApplicationSetRolloutStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ApplicationSetRolloutStep, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTerminalGenerators.
-func (in ApplicationSetTerminalGenerators) DeepCopy() ApplicationSetTerminalGenerators {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetRolloutStrategy.
+func (in *ApplicationSetRolloutStrategy) DeepCopy() *ApplicationSetRolloutStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(ApplicationSetTerminalGenerators)
+	out := new(ApplicationSetRolloutStrategy)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterGenerator) DeepCopyInto(out *ClusterGenerator) {
+func (in *ApplicationSetSpec) DeepCopyInto(out *ApplicationSetSpec) {
+	*out = *in
+	if in.GoTemplateOptions != nil {
+		in, out := &in.GoTemplateOptions, &out.GoTemplateOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ParamDefaults != nil {
+		in, out := &in.ParamDefaults, &out.ParamDefaults
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ParamTransforms != nil {
+		in, out := &in.ParamTransforms, &out.ParamTransforms
+		*out = make([]ParamTransform, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]ApplicationSetTemplate, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TemplatePatch != nil {
+		in, out := &in.TemplatePatch, &out.TemplatePatch
+		*out = new(string)
+		**out = **in
+	}
+	if in.SyncPolicy != nil {
+		in, out := &in.SyncPolicy, &out.SyncPolicy
+		*out = new(ApplicationSetSyncPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreservedFields != nil {
+		in, out := &in.PreservedFields, &out.PreservedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreApplicationDifferences != nil {
+		in, out := &in.IgnoreApplicationDifferences, &out.IgnoreApplicationDifferences
+		*out = make([]ApplicationSetResourceIgnoreDifferences, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(ApplicationSetStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetSpec.
+func (in *ApplicationSetSpec) DeepCopy() *ApplicationSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetStatus) DeepCopyInto(out *ApplicationSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ApplicationSetCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DryRunActions != nil {
+		in, out := &in.DryRunActions, &out.DryRunActions
+		*out = make([]ApplicationSetDryRunAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ApplicationSetRolloutStatus)
+		**out = **in
+	}
+	if in.GeneratorBackoff != nil {
+		in, out := &in.GeneratorBackoff, &out.GeneratorBackoff
+		*out = new(ApplicationSetGeneratorBackoffStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingDeletions != nil {
+		in, out := &in.PendingDeletions, &out.PendingDeletions
+		*out = make([]ApplicationSetPendingDeletion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDeletionBatchTime != nil {
+		in, out := &in.LastDeletionBatchTime, &out.LastDeletionBatchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ApplicationStatus != nil {
+		in, out := &in.ApplicationStatus, &out.ApplicationStatus
+		*out = make([]ApplicationSetApplicationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ApplicationSetHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(ApplicationSetParametersStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetStatus.
+func (in *ApplicationSetStatus) DeepCopy() *ApplicationSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetStrategy) DeepCopyInto(out *ApplicationSetStrategy) {
+	*out = *in
+	if in.RollingSync != nil {
+		in, out := &in.RollingSync, &out.RollingSync
+		*out = new(ApplicationSetRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxUpdate != nil {
+		in, out := &in.MaxUpdate, &out.MaxUpdate
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(ApplicationSetOrder)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetStrategy.
+func (in *ApplicationSetStrategy) DeepCopy() *ApplicationSetStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetSyncPolicy) DeepCopyInto(out *ApplicationSetSyncPolicy) {
+	*out = *in
+	if in.AdoptExisting != nil {
+		in, out := &in.AdoptExisting, &out.AdoptExisting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxDeletionPercentage != nil {
+		in, out := &in.MaxDeletionPercentage, &out.MaxDeletionPercentage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PruneAfter != nil {
+		in, out := &in.PruneAfter, &out.PruneAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DeletionBatches != nil {
+		in, out := &in.DeletionBatches, &out.DeletionBatches
+		*out = new(ApplicationSetDeletionBatches)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetSyncPolicy.
+func (in *ApplicationSetSyncPolicy) DeepCopy() *ApplicationSetSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetTemplate) DeepCopyInto(out *ApplicationSetTemplate) {
+	*out = *in
+	in.ApplicationSetTemplateMeta.DeepCopyInto(&out.ApplicationSetTemplateMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTemplate.
+func (in *ApplicationSetTemplate) DeepCopy() *ApplicationSetTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetTemplateMeta) DeepCopyInto(out *ApplicationSetTemplateMeta) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTemplateMeta.
+func (in *ApplicationSetTemplateMeta) DeepCopy() *ApplicationSetTemplateMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetTemplateMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetTerminalGenerator) DeepCopyInto(out *ApplicationSetTerminalGenerator) {
+	*out = *in
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = new(ListGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(ClusterGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SCMProvider != nil {
+		in, out := &in.SCMProvider, &out.SCMProvider
+		*out = new(SCMProviderGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterDecisionResource != nil {
+		in, out := &in.ClusterDecisionResource, &out.ClusterDecisionResource
+		*out = new(DuckTypeGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = new(PullRequestGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesResource != nil {
+		in, out := &in.KubernetesResource, &out.KubernetesResource
+		*out = new(KubernetesResourceGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureSubscriptions != nil {
+		in, out := &in.AzureSubscriptions, &out.AzureSubscriptions
+		*out = new(AzureSubscriptionsGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consul != nil {
+		in, out := &in.Consul, &out.Consul
+		*out = new(ConsulGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HelmRepository != nil {
+		in, out := &in.HelmRepository, &out.HelmRepository
+		*out = new(HelmRepositoryGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(BucketGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(SQLGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerraformState != nil {
+		in, out := &in.TerraformState, &out.TerraformState
+		*out = new(TerraformStateGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTerminalGenerator.
+func (in *ApplicationSetTerminalGenerator) DeepCopy() *ApplicationSetTerminalGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetTerminalGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ApplicationSetTerminalGenerators) DeepCopyInto(out *ApplicationSetTerminalGenerators) {
+	{
+		in := &in
+		*out = make(ApplicationSetTerminalGenerators, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetTerminalGenerators.
+func (in ApplicationSetTerminalGenerators) DeepCopy() ApplicationSetTerminalGenerators {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetTerminalGenerators)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureSubscriptionsGenerator) DeepCopyInto(out *AzureSubscriptionsGenerator) {
+	*out = *in
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureSubscriptionsGenerator.
+func (in *AzureSubscriptionsGenerator) DeepCopy() *AzureSubscriptionsGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureSubscriptionsGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketGenerator) DeepCopyInto(out *BucketGenerator) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(BucketGeneratorS3)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(BucketGeneratorGCS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketGenerator.
+func (in *BucketGenerator) DeepCopy() *BucketGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketGeneratorGCS) DeepCopyInto(out *BucketGeneratorGCS) {
+	*out = *in
+	if in.ServiceAccountKeyRef != nil {
+		in, out := &in.ServiceAccountKeyRef, &out.ServiceAccountKeyRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketGeneratorGCS.
+func (in *BucketGeneratorGCS) DeepCopy() *BucketGeneratorGCS {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketGeneratorGCS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketGeneratorS3) DeepCopyInto(out *BucketGeneratorS3) {
+	*out = *in
+	if in.AccessKeyIDRef != nil {
+		in, out := &in.AccessKeyIDRef, &out.AccessKeyIDRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.SecretAccessKeyRef != nil {
+		in, out := &in.SecretAccessKeyRef, &out.SecretAccessKeyRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketGeneratorS3.
+func (in *BucketGeneratorS3) DeepCopy() *BucketGeneratorS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketGeneratorS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGenerator) DeepCopyInto(out *ClusterGenerator) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGenerator.
+func (in *ClusterGenerator) DeepCopy() *ClusterGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsulGenerator) DeepCopyInto(out *ConsulGenerator) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ConsulServices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KV != nil {
+		in, out := &in.KV, &out.KV
+		*out = new(ConsulKV)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsulGenerator.
+func (in *ConsulGenerator) DeepCopy() *ConsulGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsulGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsulKV) DeepCopyInto(out *ConsulKV) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsulKV.
+func (in *ConsulKV) DeepCopy() *ConsulKV {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsulKV)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsulServices) DeepCopyInto(out *ConsulServices) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsulServices.
+func (in *ConsulServices) DeepCopy() *ConsulServices {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsulServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DuckTypeGenerator) DeepCopyInto(out *DuckTypeGenerator) {
+	*out = *in
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DuckTypeGenerator.
+func (in *DuckTypeGenerator) DeepCopy() *DuckTypeGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(DuckTypeGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorSchedule) DeepCopyInto(out *GeneratorSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorSchedule.
+func (in *GeneratorSchedule) DeepCopy() *GeneratorSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDirectoryGeneratorItem) DeepCopyInto(out *GitDirectoryGeneratorItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDirectoryGeneratorItem.
+func (in *GitDirectoryGeneratorItem) DeepCopy() *GitDirectoryGeneratorItem {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDirectoryGeneratorItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitFileGeneratorItem) DeepCopyInto(out *GitFileGeneratorItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitFileGeneratorItem.
+func (in *GitFileGeneratorItem) DeepCopy() *GitFileGeneratorItem {
+	if in == nil {
+		return nil
+	}
+	out := new(GitFileGeneratorItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitGenerator) DeepCopyInto(out *GitGenerator) {
+	*out = *in
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]GitDirectoryGeneratorItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]GitFileGeneratorItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitGenerator.
+func (in *GitGenerator) DeepCopy() *GitGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(GitGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubAppRef) DeepCopyInto(out *GithubAppRef) {
+	*out = *in
+	out.PrivateKeyRef = in.PrivateKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubAppRef.
+func (in *GithubAppRef) DeepCopy() *GithubAppRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubAppRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGenerator) DeepCopyInto(out *HTTPGenerator) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGenerator.
+func (in *HTTPGenerator) DeepCopy() *HTTPGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmRepositoryGenerator) DeepCopyInto(out *HelmRepositoryGenerator) {
+	*out = *in
+	if in.Charts != nil {
+		in, out := &in.Charts, &out.Charts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PasswordRef != nil {
+		in, out := &in.PasswordRef, &out.PasswordRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmRepositoryGenerator.
+func (in *HelmRepositoryGenerator) DeepCopy() *HelmRepositoryGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmRepositoryGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesResourceGenerator) DeepCopyInto(out *KubernetesResourceGenerator) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesResourceGenerator.
+func (in *KubernetesResourceGenerator) DeepCopy() *KubernetesResourceGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesResourceGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListGenerator) DeepCopyInto(out *ListGenerator) {
+	*out = *in
+	if in.Elements != nil {
+		in, out := &in.Elements, &out.Elements
+		*out = make([]v1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListGenerator.
+func (in *ListGenerator) DeepCopy() *ListGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ListGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixGenerator) DeepCopyInto(out *MatrixGenerator) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetNestedGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixGenerator.
+func (in *MatrixGenerator) DeepCopy() *MatrixGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeGenerator) DeepCopyInto(out *MergeGenerator) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetNestedGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MergeKeys != nil {
+		in, out := &in.MergeKeys, &out.MergeKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeGenerator.
+func (in *MergeGenerator) DeepCopy() *MergeGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NestedMatrixGenerator) DeepCopyInto(out *NestedMatrixGenerator) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make(ApplicationSetTerminalGenerators, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMatrixGenerator.
+func (in *NestedMatrixGenerator) DeepCopy() *NestedMatrixGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(NestedMatrixGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NestedMergeGenerator) DeepCopyInto(out *NestedMergeGenerator) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make(ApplicationSetTerminalGenerators, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MergeKeys != nil {
+		in, out := &in.MergeKeys, &out.MergeKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMergeGenerator.
+func (in *NestedMergeGenerator) DeepCopy() *NestedMergeGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(NestedMergeGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NestedUnionGenerator) DeepCopyInto(out *NestedUnionGenerator) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make(ApplicationSetTerminalGenerators, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeduplicationKeys != nil {
+		in, out := &in.DeduplicationKeys, &out.DeduplicationKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedUnionGenerator.
+func (in *NestedUnionGenerator) DeepCopy() *NestedUnionGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(NestedUnionGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParamTransform) DeepCopyInto(out *ParamTransform) {
 	*out = *in
-	in.Selector.DeepCopyInto(&out.Selector)
-	in.Template.DeepCopyInto(&out.Template)
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGenerator.
-func (in *ClusterGenerator) DeepCopy() *ClusterGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamTransform.
+func (in *ParamTransform) DeepCopy() *ParamTransform {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterGenerator)
+	out := new(ParamTransform)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DuckTypeGenerator) DeepCopyInto(out *DuckTypeGenerator) {
+func (in *PluginGenerator) DeepCopyInto(out *PluginGenerator) {
 	*out = *in
+	in.Input.DeepCopyInto(&out.Input)
 	if in.RequeueAfterSeconds != nil {
 		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
 		*out = new(int64)
 		**out = **in
 	}
-	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
 	in.Template.DeepCopyInto(&out.Template)
 	if in.Values != nil {
 		in, out := &in.Values, &out.Values
@@ -459,375 +1693,664 @@ func (in *DuckTypeGenerator) DeepCopyInto(out *DuckTypeGenerator) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DuckTypeGenerator.
-func (in *DuckTypeGenerator) DeepCopy() *DuckTypeGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginGenerator.
+func (in *PluginGenerator) DeepCopy() *PluginGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(DuckTypeGenerator)
+	out := new(PluginGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitDirectoryGeneratorItem) DeepCopyInto(out *GitDirectoryGeneratorItem) {
+func (in *PluginInput) DeepCopyInto(out *PluginInput) {
 	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]v1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDirectoryGeneratorItem.
-func (in *GitDirectoryGeneratorItem) DeepCopy() *GitDirectoryGeneratorItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginInput.
+func (in *PluginInput) DeepCopy() *PluginInput {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGenerator) DeepCopyInto(out *PullRequestGenerator) {
+	*out = *in
+	if in.Github != nil {
+		in, out := &in.Github, &out.Github
+		*out = new(PullRequestGeneratorGithub)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gitea != nil {
+		in, out := &in.Gitea, &out.Gitea
+		*out = new(PullRequestGeneratorGitea)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureDevOps != nil {
+		in, out := &in.AzureDevOps, &out.AzureDevOps
+		*out = new(PullRequestGeneratorAzureDevOps)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitLab != nil {
+		in, out := &in.GitLab, &out.GitLab
+		*out = new(PullRequestGeneratorGitLab)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]PullRequestGeneratorFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShortSHALength != nil {
+		in, out := &in.ShortSHALength, &out.ShortSHALength
+		*out = new(int)
+		**out = **in
+	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGenerator.
+func (in *PullRequestGenerator) DeepCopy() *PullRequestGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGeneratorAzureDevOps) DeepCopyInto(out *PullRequestGeneratorAzureDevOps) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnyLabels != nil {
+		in, out := &in.AnyLabels, &out.AnyLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotLabels != nil {
+		in, out := &in.NotLabels, &out.NotLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorAzureDevOps.
+func (in *PullRequestGeneratorAzureDevOps) DeepCopy() *PullRequestGeneratorAzureDevOps {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGeneratorAzureDevOps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGeneratorFilter) DeepCopyInto(out *PullRequestGeneratorFilter) {
+	*out = *in
+	if in.BranchMatch != nil {
+		in, out := &in.BranchMatch, &out.BranchMatch
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetBranchMatch != nil {
+		in, out := &in.TargetBranchMatch, &out.TargetBranchMatch
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorFilter.
+func (in *PullRequestGeneratorFilter) DeepCopy() *PullRequestGeneratorFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGeneratorFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGeneratorGitLab) DeepCopyInto(out *PullRequestGeneratorGitLab) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnyLabels != nil {
+		in, out := &in.AnyLabels, &out.AnyLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotLabels != nil {
+		in, out := &in.NotLabels, &out.NotLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorGitLab.
+func (in *PullRequestGeneratorGitLab) DeepCopy() *PullRequestGeneratorGitLab {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGeneratorGitLab)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGeneratorGitea) DeepCopyInto(out *PullRequestGeneratorGitea) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnyLabels != nil {
+		in, out := &in.AnyLabels, &out.AnyLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotLabels != nil {
+		in, out := &in.NotLabels, &out.NotLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorGitea.
+func (in *PullRequestGeneratorGitea) DeepCopy() *PullRequestGeneratorGitea {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGeneratorGitea)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestGeneratorGithub) DeepCopyInto(out *PullRequestGeneratorGithub) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.GithubApp != nil {
+		in, out := &in.GithubApp, &out.GithubApp
+		*out = new(GithubAppRef)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnyLabels != nil {
+		in, out := &in.AnyLabels, &out.AnyLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotLabels != nil {
+		in, out := &in.NotLabels, &out.NotLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorGithub.
+func (in *PullRequestGeneratorGithub) DeepCopy() *PullRequestGeneratorGithub {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestGeneratorGithub)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCMProviderGenerator) DeepCopyInto(out *SCMProviderGenerator) {
+	*out = *in
+	if in.Github != nil {
+		in, out := &in.Github, &out.Github
+		*out = new(SCMProviderGeneratorGithub)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gitlab != nil {
+		in, out := &in.Gitlab, &out.Gitlab
+		*out = new(SCMProviderGeneratorGitlab)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]SCMProviderGeneratorFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGenerator.
+func (in *SCMProviderGenerator) DeepCopy() *SCMProviderGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(GitDirectoryGeneratorItem)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitFileGeneratorItem) DeepCopyInto(out *GitFileGeneratorItem) {
-	*out = *in
+	out := new(SCMProviderGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCMProviderGeneratorFilter) DeepCopyInto(out *SCMProviderGeneratorFilter) {
+	*out = *in
+	if in.RepositoryMatch != nil {
+		in, out := &in.RepositoryMatch, &out.RepositoryMatch
+		*out = new(string)
+		**out = **in
+	}
+	if in.PathsExist != nil {
+		in, out := &in.PathsExist, &out.PathsExist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelMatch != nil {
+		in, out := &in.LabelMatch, &out.LabelMatch
+		*out = new(string)
+		**out = **in
+	}
+	if in.BranchMatch != nil {
+		in, out := &in.BranchMatch, &out.BranchMatch
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitFileGeneratorItem.
-func (in *GitFileGeneratorItem) DeepCopy() *GitFileGeneratorItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorFilter.
+func (in *SCMProviderGeneratorFilter) DeepCopy() *SCMProviderGeneratorFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(GitFileGeneratorItem)
+	out := new(SCMProviderGeneratorFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitGenerator) DeepCopyInto(out *GitGenerator) {
+func (in *SCMProviderGeneratorGithub) DeepCopyInto(out *SCMProviderGeneratorGithub) {
 	*out = *in
-	if in.Directories != nil {
-		in, out := &in.Directories, &out.Directories
-		*out = make([]GitDirectoryGeneratorItem, len(*in))
-		copy(*out, *in)
-	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = make([]GitFileGeneratorItem, len(*in))
-		copy(*out, *in)
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
 	}
-	if in.RequeueAfterSeconds != nil {
-		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
-		*out = new(int64)
+	if in.GithubApp != nil {
+		in, out := &in.GithubApp, &out.GithubApp
+		*out = new(GithubAppRef)
 		**out = **in
 	}
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitGenerator.
-func (in *GitGenerator) DeepCopy() *GitGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorGithub.
+func (in *SCMProviderGeneratorGithub) DeepCopy() *SCMProviderGeneratorGithub {
 	if in == nil {
 		return nil
 	}
-	out := new(GitGenerator)
+	out := new(SCMProviderGeneratorGithub)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ListGenerator) DeepCopyInto(out *ListGenerator) {
+func (in *SCMProviderGeneratorGitlab) DeepCopyInto(out *SCMProviderGeneratorGitlab) {
 	*out = *in
-	if in.Elements != nil {
-		in, out := &in.Elements, &out.Elements
-		*out = make([]v1.JSON, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
 	}
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListGenerator.
-func (in *ListGenerator) DeepCopy() *ListGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorGitlab.
+func (in *SCMProviderGeneratorGitlab) DeepCopy() *SCMProviderGeneratorGitlab {
 	if in == nil {
 		return nil
 	}
-	out := new(ListGenerator)
+	out := new(SCMProviderGeneratorGitlab)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MatrixGenerator) DeepCopyInto(out *MatrixGenerator) {
+func (in *SQLGenerator) DeepCopyInto(out *SQLGenerator) {
 	*out = *in
-	if in.Generators != nil {
-		in, out := &in.Generators, &out.Generators
-		*out = make([]ApplicationSetNestedGenerator, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.DSNRef = in.DSNRef
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixGenerator.
-func (in *MatrixGenerator) DeepCopy() *MatrixGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLGenerator.
+func (in *SQLGenerator) DeepCopy() *SQLGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(MatrixGenerator)
+	out := new(SQLGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MergeGenerator) DeepCopyInto(out *MergeGenerator) {
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
 	*out = *in
-	if in.Generators != nil {
-		in, out := &in.Generators, &out.Generators
-		*out = make([]ApplicationSetNestedGenerator, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.MergeKeys != nil {
-		in, out := &in.MergeKeys, &out.MergeKeys
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeGenerator.
-func (in *MergeGenerator) DeepCopy() *MergeGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(MergeGenerator)
+	out := new(ScheduleWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NestedMatrixGenerator) DeepCopyInto(out *NestedMatrixGenerator) {
+func (in *SecretGenerator) DeepCopyInto(out *SecretGenerator) {
 	*out = *in
-	if in.Generators != nil {
-		in, out := &in.Generators, &out.Generators
-		*out = make(ApplicationSetTerminalGenerators, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMatrixGenerator.
-func (in *NestedMatrixGenerator) DeepCopy() *NestedMatrixGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGenerator.
+func (in *SecretGenerator) DeepCopy() *SecretGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(NestedMatrixGenerator)
+	out := new(SecretGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NestedMergeGenerator) DeepCopyInto(out *NestedMergeGenerator) {
+func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
-	if in.Generators != nil {
-		in, out := &in.Generators, &out.Generators
-		*out = make(ApplicationSetTerminalGenerators, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.MergeKeys != nil {
-		in, out := &in.MergeKeys, &out.MergeKeys
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMergeGenerator.
-func (in *NestedMergeGenerator) DeepCopy() *NestedMergeGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRef.
+func (in *SecretRef) DeepCopy() *SecretRef {
 	if in == nil {
 		return nil
 	}
-	out := new(NestedMergeGenerator)
+	out := new(SecretRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PullRequestGenerator) DeepCopyInto(out *PullRequestGenerator) {
+func (in *TerraformStateGenerator) DeepCopyInto(out *TerraformStateGenerator) {
 	*out = *in
-	if in.Github != nil {
-		in, out := &in.Github, &out.Github
-		*out = new(PullRequestGeneratorGithub)
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(BucketGeneratorS3)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(BucketGeneratorGCS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(TerraformStateHTTP)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(TerraformStateRemote)
+		**out = **in
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.RequeueAfterSeconds != nil {
 		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
 		*out = new(int64)
 		**out = **in
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGenerator.
-func (in *PullRequestGenerator) DeepCopy() *PullRequestGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformStateGenerator.
+func (in *TerraformStateGenerator) DeepCopy() *TerraformStateGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(PullRequestGenerator)
+	out := new(TerraformStateGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PullRequestGeneratorGithub) DeepCopyInto(out *PullRequestGeneratorGithub) {
+func (in *TerraformStateHTTP) DeepCopyInto(out *TerraformStateHTTP) {
 	*out = *in
-	if in.TokenRef != nil {
-		in, out := &in.TokenRef, &out.TokenRef
+	if in.PasswordRef != nil {
+		in, out := &in.PasswordRef, &out.PasswordRef
 		*out = new(SecretRef)
 		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestGeneratorGithub.
-func (in *PullRequestGeneratorGithub) DeepCopy() *PullRequestGeneratorGithub {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformStateHTTP.
+func (in *TerraformStateHTTP) DeepCopy() *TerraformStateHTTP {
 	if in == nil {
 		return nil
 	}
-	out := new(PullRequestGeneratorGithub)
+	out := new(TerraformStateHTTP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SCMProviderGenerator) DeepCopyInto(out *SCMProviderGenerator) {
+func (in *TerraformStateRemote) DeepCopyInto(out *TerraformStateRemote) {
 	*out = *in
-	if in.Github != nil {
-		in, out := &in.Github, &out.Github
-		*out = new(SCMProviderGeneratorGithub)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Gitlab != nil {
-		in, out := &in.Gitlab, &out.Gitlab
-		*out = new(SCMProviderGeneratorGitlab)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Filters != nil {
-		in, out := &in.Filters, &out.Filters
-		*out = make([]SCMProviderGeneratorFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.RequeueAfterSeconds != nil {
-		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
-		*out = new(int64)
-		**out = **in
-	}
-	in.Template.DeepCopyInto(&out.Template)
+	out.TokenRef = in.TokenRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGenerator.
-func (in *SCMProviderGenerator) DeepCopy() *SCMProviderGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformStateRemote.
+func (in *TerraformStateRemote) DeepCopy() *TerraformStateRemote {
 	if in == nil {
 		return nil
 	}
-	out := new(SCMProviderGenerator)
+	out := new(TerraformStateRemote)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SCMProviderGeneratorFilter) DeepCopyInto(out *SCMProviderGeneratorFilter) {
+func (in *UnionGenerator) DeepCopyInto(out *UnionGenerator) {
 	*out = *in
-	if in.RepositoryMatch != nil {
-		in, out := &in.RepositoryMatch, &out.RepositoryMatch
-		*out = new(string)
-		**out = **in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetNestedGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.PathsExist != nil {
-		in, out := &in.PathsExist, &out.PathsExist
+	if in.DeduplicationKeys != nil {
+		in, out := &in.DeduplicationKeys, &out.DeduplicationKeys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.LabelMatch != nil {
-		in, out := &in.LabelMatch, &out.LabelMatch
-		*out = new(string)
-		**out = **in
-	}
-	if in.BranchMatch != nil {
-		in, out := &in.BranchMatch, &out.BranchMatch
-		*out = new(string)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorFilter.
-func (in *SCMProviderGeneratorFilter) DeepCopy() *SCMProviderGeneratorFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnionGenerator.
+func (in *UnionGenerator) DeepCopy() *UnionGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(SCMProviderGeneratorFilter)
+	out := new(UnionGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SCMProviderGeneratorGithub) DeepCopyInto(out *SCMProviderGeneratorGithub) {
+func (in *VaultAuth) DeepCopyInto(out *VaultAuth) {
 	*out = *in
 	if in.TokenRef != nil {
 		in, out := &in.TokenRef, &out.TokenRef
 		*out = new(SecretRef)
 		**out = **in
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(VaultKubernetesAuth)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorGithub.
-func (in *SCMProviderGeneratorGithub) DeepCopy() *SCMProviderGeneratorGithub {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuth.
+func (in *VaultAuth) DeepCopy() *VaultAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(SCMProviderGeneratorGithub)
+	out := new(VaultAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SCMProviderGeneratorGitlab) DeepCopyInto(out *SCMProviderGeneratorGitlab) {
+func (in *VaultGenerator) DeepCopyInto(out *VaultGenerator) {
 	*out = *in
-	if in.TokenRef != nil {
-		in, out := &in.TokenRef, &out.TokenRef
-		*out = new(SecretRef)
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.RequeueAfterSeconds != nil {
+		in, out := &in.RequeueAfterSeconds, &out.RequeueAfterSeconds
+		*out = new(int64)
 		**out = **in
 	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMProviderGeneratorGitlab.
-func (in *SCMProviderGeneratorGitlab) DeepCopy() *SCMProviderGeneratorGitlab {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultGenerator.
+func (in *VaultGenerator) DeepCopy() *VaultGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(SCMProviderGeneratorGitlab)
+	out := new(VaultGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretRef) DeepCopyInto(out *SecretRef) {
+func (in *VaultKubernetesAuth) DeepCopyInto(out *VaultKubernetesAuth) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRef.
-func (in *SecretRef) DeepCopy() *SecretRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultKubernetesAuth.
+func (in *VaultKubernetesAuth) DeepCopy() *VaultKubernetesAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretRef)
+	out := new(VaultKubernetesAuth)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,309 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &ApplicationSet{}
+
+// ConvertTo converts the ApplicationSet to the v1alpha1 version, the hub of the conversions.
+func (src *ApplicationSet) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.ApplicationSet)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ApplicationSetSpec{
+		GoTemplate:                   src.Spec.TemplateEngine != TemplateEngineLegacy,
+		GoTemplateOptions:            src.Spec.GoTemplateOptions,
+		ParamDefaults:                src.Spec.ParamDefaults,
+		ResolveParamReferences:       src.Spec.ResolveParamReferences,
+		ParamTransforms:              src.Spec.ParamTransforms,
+		Template:                     src.Spec.Template,
+		Templates:                    src.Spec.Templates,
+		TemplateSelector:             src.Spec.TemplateSelector,
+		TemplateMergePolicy:          src.Spec.TemplateMergePolicy,
+		TemplatePatch:                src.Spec.TemplatePatch,
+		SyncPolicy:                   src.Spec.SyncPolicy,
+		PreservedFields:              src.Spec.PreservedFields,
+		IgnoreApplicationDifferences: src.Spec.IgnoreApplicationDifferences,
+		DryRun:                       src.Spec.DryRun,
+		Strategy:                     src.Spec.Strategy,
+		Suspend:                      src.Spec.Suspend,
+		HistoryLimit:                 src.Spec.HistoryLimit,
+	}
+	for _, g := range src.Spec.Generators {
+		dst.Spec.Generators = append(dst.Spec.Generators, g.toV1alpha1())
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts the ApplicationSet from the v1alpha1 version, the hub of the conversions.
+func (dst *ApplicationSet) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.ApplicationSet)
+	dst.ObjectMeta = src.ObjectMeta
+	templateEngine := TemplateEngineLegacy
+	if src.Spec.GoTemplate {
+		templateEngine = TemplateEngineGo
+	}
+	dst.Spec = ApplicationSetSpec{
+		TemplateEngine:               templateEngine,
+		GoTemplateOptions:            src.Spec.GoTemplateOptions,
+		ParamDefaults:                src.Spec.ParamDefaults,
+		ResolveParamReferences:       src.Spec.ResolveParamReferences,
+		ParamTransforms:              src.Spec.ParamTransforms,
+		Template:                     src.Spec.Template,
+		Templates:                    src.Spec.Templates,
+		TemplateSelector:             src.Spec.TemplateSelector,
+		TemplateMergePolicy:          src.Spec.TemplateMergePolicy,
+		TemplatePatch:                src.Spec.TemplatePatch,
+		SyncPolicy:                   src.Spec.SyncPolicy,
+		PreservedFields:              src.Spec.PreservedFields,
+		IgnoreApplicationDifferences: src.Spec.IgnoreApplicationDifferences,
+		DryRun:                       src.Spec.DryRun,
+		Strategy:                     src.Spec.Strategy,
+		Suspend:                      src.Spec.Suspend,
+		HistoryLimit:                 src.Spec.HistoryLimit,
+	}
+	for _, g := range src.Spec.Generators {
+		dst.Spec.Generators = append(dst.Spec.Generators, generatorFromV1alpha1(g))
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+func (g ApplicationSetGenerator) toV1alpha1() v1alpha1.ApplicationSetGenerator {
+	res := v1alpha1.ApplicationSetGenerator{
+		List:                    g.List,
+		Clusters:                g.Clusters,
+		Git:                     g.Git,
+		SCMProvider:             g.SCMProvider,
+		ClusterDecisionResource: g.ClusterDecisionResource,
+		PullRequest:             g.PullRequest,
+		Plugin:                  g.Plugin,
+		HTTP:                    g.HTTP,
+		Secret:                  g.Secret,
+		KubernetesResource:      g.KubernetesResource,
+		AzureSubscriptions:      g.AzureSubscriptions,
+		Vault:                   g.Vault,
+		Consul:                  g.Consul,
+		HelmRepository:          g.HelmRepository,
+		Bucket:                  g.Bucket,
+		SQL:                     g.SQL,
+		TerraformState:          g.TerraformState,
+		Schedule:                g.Schedule,
+	}
+	if g.Matrix != nil {
+		res.Matrix = &v1alpha1.MatrixGenerator{
+			Generators:          nestedGeneratorsToV1alpha1(g.Matrix.Generators),
+			Template:            g.Matrix.Template,
+			RequeueAfterSeconds: g.Matrix.RequeueAfterSeconds,
+		}
+	}
+	if g.Merge != nil {
+		res.Merge = &v1alpha1.MergeGenerator{
+			Generators:          nestedGeneratorsToV1alpha1(g.Merge.Generators),
+			MergeKeys:           g.Merge.MergeKeys,
+			Template:            g.Merge.Template,
+			RequeueAfterSeconds: g.Merge.RequeueAfterSeconds,
+		}
+	}
+	if g.Union != nil {
+		res.Union = &v1alpha1.UnionGenerator{
+			Generators:          nestedGeneratorsToV1alpha1(g.Union.Generators),
+			DeduplicationKeys:   g.Union.DeduplicationKeys,
+			Template:            g.Union.Template,
+			RequeueAfterSeconds: g.Union.RequeueAfterSeconds,
+		}
+	}
+	return res
+}
+
+func generatorFromV1alpha1(g v1alpha1.ApplicationSetGenerator) ApplicationSetGenerator {
+	res := ApplicationSetGenerator{
+		TerminalGenerators: TerminalGenerators{
+			List:                    g.List,
+			Clusters:                g.Clusters,
+			Git:                     g.Git,
+			SCMProvider:             g.SCMProvider,
+			ClusterDecisionResource: g.ClusterDecisionResource,
+			PullRequest:             g.PullRequest,
+			Plugin:                  g.Plugin,
+			HTTP:                    g.HTTP,
+			Secret:                  g.Secret,
+			KubernetesResource:      g.KubernetesResource,
+			AzureSubscriptions:      g.AzureSubscriptions,
+			Vault:                   g.Vault,
+			Consul:                  g.Consul,
+			HelmRepository:          g.HelmRepository,
+			Bucket:                  g.Bucket,
+			SQL:                     g.SQL,
+			TerraformState:          g.TerraformState,
+		},
+		Schedule: g.Schedule,
+	}
+	if g.Matrix != nil {
+		res.Matrix = &MatrixGenerator{
+			Generators:          nestedGeneratorsFromV1alpha1(g.Matrix.Generators),
+			Template:            g.Matrix.Template,
+			RequeueAfterSeconds: g.Matrix.RequeueAfterSeconds,
+		}
+	}
+	if g.Merge != nil {
+		res.Merge = &MergeGenerator{
+			Generators:          nestedGeneratorsFromV1alpha1(g.Merge.Generators),
+			MergeKeys:           g.Merge.MergeKeys,
+			Template:            g.Merge.Template,
+			RequeueAfterSeconds: g.Merge.RequeueAfterSeconds,
+		}
+	}
+	if g.Union != nil {
+		res.Union = &UnionGenerator{
+			Generators:          nestedGeneratorsFromV1alpha1(g.Union.Generators),
+			DeduplicationKeys:   g.Union.DeduplicationKeys,
+			Template:            g.Union.Template,
+			RequeueAfterSeconds: g.Union.RequeueAfterSeconds,
+		}
+	}
+	return res
+}
+
+func nestedGeneratorsToV1alpha1(generators []ApplicationSetNestedGenerator) []v1alpha1.ApplicationSetNestedGenerator {
+	if generators == nil {
+		return nil
+	}
+	res := make([]v1alpha1.ApplicationSetNestedGenerator, len(generators))
+	for i, g := range generators {
+		res[i] = v1alpha1.ApplicationSetNestedGenerator{
+			List:                    g.List,
+			Clusters:                g.Clusters,
+			Git:                     g.Git,
+			SCMProvider:             g.SCMProvider,
+			ClusterDecisionResource: g.ClusterDecisionResource,
+			PullRequest:             g.PullRequest,
+			Plugin:                  g.Plugin,
+			HTTP:                    g.HTTP,
+			Secret:                  g.Secret,
+			KubernetesResource:      g.KubernetesResource,
+			AzureSubscriptions:      g.AzureSubscriptions,
+			Vault:                   g.Vault,
+			Consul:                  g.Consul,
+			HelmRepository:          g.HelmRepository,
+			Bucket:                  g.Bucket,
+			SQL:                     g.SQL,
+			TerraformState:          g.TerraformState,
+		}
+		if g.Matrix != nil {
+			res[i].Matrix = &v1alpha1.NestedMatrixGenerator{
+				Generators:          terminalGeneratorsToV1alpha1(g.Matrix.Generators),
+				RequeueAfterSeconds: g.Matrix.RequeueAfterSeconds,
+			}
+		}
+		if g.Merge != nil {
+			res[i].Merge = &v1alpha1.NestedMergeGenerator{
+				Generators:          terminalGeneratorsToV1alpha1(g.Merge.Generators),
+				MergeKeys:           g.Merge.MergeKeys,
+				RequeueAfterSeconds: g.Merge.RequeueAfterSeconds,
+			}
+		}
+		if g.Union != nil {
+			res[i].Union = &v1alpha1.NestedUnionGenerator{
+				Generators:          terminalGeneratorsToV1alpha1(g.Union.Generators),
+				DeduplicationKeys:   g.Union.DeduplicationKeys,
+				RequeueAfterSeconds: g.Union.RequeueAfterSeconds,
+			}
+		}
+	}
+	return res
+}
+
+func nestedGeneratorsFromV1alpha1(generators []v1alpha1.ApplicationSetNestedGenerator) []ApplicationSetNestedGenerator {
+	if generators == nil {
+		return nil
+	}
+	res := make([]ApplicationSetNestedGenerator, len(generators))
+	for i, g := range generators {
+		res[i] = ApplicationSetNestedGenerator{
+			TerminalGenerators: TerminalGenerators{
+				List:                    g.List,
+				Clusters:                g.Clusters,
+				Git:                     g.Git,
+				SCMProvider:             g.SCMProvider,
+				ClusterDecisionResource: g.ClusterDecisionResource,
+				PullRequest:             g.PullRequest,
+				Plugin:                  g.Plugin,
+				HTTP:                    g.HTTP,
+				Secret:                  g.Secret,
+				KubernetesResource:      g.KubernetesResource,
+				AzureSubscriptions:      g.AzureSubscriptions,
+				Vault:                   g.Vault,
+				Consul:                  g.Consul,
+				HelmRepository:          g.HelmRepository,
+				Bucket:                  g.Bucket,
+				SQL:                     g.SQL,
+				TerraformState:          g.TerraformState,
+			},
+		}
+		if g.Matrix != nil {
+			res[i].Matrix = &NestedMatrixGenerator{
+				Generators:          terminalGeneratorsFromV1alpha1(g.Matrix.Generators),
+				RequeueAfterSeconds: g.Matrix.RequeueAfterSeconds,
+			}
+		}
+		if g.Merge != nil {
+			res[i].Merge = &NestedMergeGenerator{
+				Generators:          terminalGeneratorsFromV1alpha1(g.Merge.Generators),
+				MergeKeys:           g.Merge.MergeKeys,
+				RequeueAfterSeconds: g.Merge.RequeueAfterSeconds,
+			}
+		}
+		if g.Union != nil {
+			res[i].Union = &NestedUnionGenerator{
+				Generators:          terminalGeneratorsFromV1alpha1(g.Union.Generators),
+				DeduplicationKeys:   g.Union.DeduplicationKeys,
+				RequeueAfterSeconds: g.Union.RequeueAfterSeconds,
+			}
+		}
+	}
+	return res
+}
+
+// terminalGeneratorsToV1alpha1 converts the terminal generators, whose fields are those of the v1alpha1 terminal
+// generators.
+func terminalGeneratorsToV1alpha1(generators []TerminalGenerators) v1alpha1.ApplicationSetTerminalGenerators {
+	if generators == nil {
+		return nil
+	}
+	res := make(v1alpha1.ApplicationSetTerminalGenerators, len(generators))
+	for i, g := range generators {
+		res[i] = v1alpha1.ApplicationSetTerminalGenerator(g)
+	}
+	return res
+}
+
+func terminalGeneratorsFromV1alpha1(generators v1alpha1.ApplicationSetTerminalGenerators) []TerminalGenerators {
+	if generators == nil {
+		return nil
+	}
+	res := make([]TerminalGenerators, len(generators))
+	for i, g := range generators {
+		res[i] = TerminalGenerators(g)
+	}
+	return res
+}
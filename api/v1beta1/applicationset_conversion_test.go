@@ -0,0 +1,105 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func newV1alpha1ApplicationSet() *v1alpha1.ApplicationSet {
+	requeueAfterSeconds := int64(60)
+	return &v1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+		Spec: v1alpha1.ApplicationSetSpec{
+			GoTemplate:        true,
+			GoTemplateOptions: []string{"missingkey=error"},
+			Generators: []v1alpha1.ApplicationSetGenerator{
+				{
+					List: &v1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster":"staging"}`)}},
+					},
+				},
+				{
+					Matrix: &v1alpha1.MatrixGenerator{
+						Generators: []v1alpha1.ApplicationSetNestedGenerator{
+							{Clusters: &v1alpha1.ClusterGenerator{}},
+							{
+								Merge: &v1alpha1.NestedMergeGenerator{
+									Generators: v1alpha1.ApplicationSetTerminalGenerators{
+										{Git: &v1alpha1.GitGenerator{RepoURL: "https://github.com/argoproj/argocd-example-apps.git", Revision: "HEAD"}},
+										{List: &v1alpha1.ListGenerator{}},
+									},
+									MergeKeys: []string{"path"},
+								},
+							},
+						},
+						RequeueAfterSeconds: &requeueAfterSeconds,
+					},
+				},
+			},
+			Template: v1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{Name: "{{.cluster}}-guestbook"},
+			},
+		},
+		Status: v1alpha1.ApplicationSetStatus{
+			Conditions: []v1alpha1.ApplicationSetCondition{{Type: v1alpha1.ApplicationSetConditionResourcesUpToDate, Status: v1alpha1.ApplicationSetConditionStatusTrue}},
+		},
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	src := newV1alpha1ApplicationSet()
+
+	var appSet ApplicationSet
+	require.NoError(t, appSet.ConvertFrom(src))
+	assert.Equal(t, TemplateEngineGo, appSet.Spec.TemplateEngine)
+	assert.Equal(t, "guestbook", appSet.Name)
+	assert.Equal(t, src.Status, appSet.Status)
+
+	var dst v1alpha1.ApplicationSet
+	require.NoError(t, appSet.ConvertTo(&dst))
+	assert.Equal(t, src, &dst)
+}
+
+func TestConvertTemplateEngine(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		templateEngine TemplateEngine
+		goTemplate     bool
+	}{
+		{name: "go", templateEngine: TemplateEngineGo, goTemplate: true},
+		{name: "legacy", templateEngine: TemplateEngineLegacy, goTemplate: false},
+		{name: "unset defaults to go", templateEngine: "", goTemplate: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := ApplicationSet{Spec: ApplicationSetSpec{TemplateEngine: c.templateEngine}}
+			var dst v1alpha1.ApplicationSet
+			require.NoError(t, appSet.ConvertTo(&dst))
+			assert.Equal(t, c.goTemplate, dst.Spec.GoTemplate)
+		})
+	}
+
+	var appSet ApplicationSet
+	require.NoError(t, appSet.ConvertFrom(&v1alpha1.ApplicationSet{}))
+	assert.Equal(t, TemplateEngineLegacy, appSet.Spec.TemplateEngine)
+}
+
+// The generators of both versions must have the same JSON, so that an ApplicationSet converts by only renaming
+// goTemplate, and a v1alpha1 manifest applies as v1beta1 once its apiVersion and goTemplate are updated.
+func TestConvertGeneratorsJSON(t *testing.T) {
+	src := newV1alpha1ApplicationSet()
+	var appSet ApplicationSet
+	require.NoError(t, appSet.ConvertFrom(src))
+
+	expected, err := json.Marshal(src.Spec.Generators)
+	require.NoError(t, err)
+	actual, err := json.Marshal(appSet.Spec.Generators)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
@@ -0,0 +1,235 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// ApplicationSet is a set of Application resources. The v1beta1 version is converted from and to the v1alpha1
+// version, which is the version stored and reconciled by the controller, by the conversion webhook of the controller.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=applicationsets,shortName=appset;appsets
+// +kubebuilder:subresource:status
+type ApplicationSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   ApplicationSetSpec            `json:"spec"`
+	Status v1alpha1.ApplicationSetStatus `json:"status,omitempty"`
+}
+
+// ApplicationSetSpec represents a class of application set state. It has the fields of the v1alpha1 version, except
+// for TemplateEngine, which replaces GoTemplate.
+type ApplicationSetSpec struct {
+	// TemplateEngine renders the templates with Go templates and the Sprig functions, the params being structured
+	// values such as lists and objects, or with the legacy {{param}} syntax, the params being flattened to strings.
+	// Defaults to Go.
+	// +kubebuilder:validation:Enum=Go;Legacy
+	TemplateEngine TemplateEngine `json:"templateEngine,omitempty"`
+	// GoTemplateOptions are the options of the Go templates, e.g. missingkey=error to fail on missing params.
+	GoTemplateOptions []string                  `json:"goTemplateOptions,omitempty"`
+	Generators        []ApplicationSetGenerator `json:"generators"`
+	// ParamDefaults are the values of the params which are missing from a generated param set.
+	ParamDefaults map[string]string `json:"paramDefaults,omitempty"`
+	// ResolveParamReferences substitutes the references to other params in the values of the params, e.g.
+	// https://{{name}}.{{domain}}, before the param transforms and the template.
+	ResolveParamReferences bool `json:"resolveParamReferences,omitempty"`
+	// ParamTransforms set or remove params of each generated param set, in order, before the template is rendered.
+	ParamTransforms []v1alpha1.ParamTransform       `json:"paramTransforms,omitempty"`
+	Template        v1alpha1.ApplicationSetTemplate `json:"template"`
+	// Templates are named templates, which the TemplateSelector chooses from for each param set.
+	Templates map[string]v1alpha1.ApplicationSetTemplate `json:"templates,omitempty"`
+	// TemplateSelector is a Go template rendered with the params, e.g. '{{ .type }}', whose result is the name of the
+	// template of Templates each param set is rendered with. Param sets for which the result is empty are rendered with
+	// Template.
+	TemplateSelector string `json:"templateSelector,omitempty"`
+	// TemplateMergePolicy defines how the metadata of the generator templates is merged with the metadata of the
+	// template. Defaults to merge.
+	TemplateMergePolicy v1alpha1.TemplateMergePolicy `json:"templateMergePolicy,omitempty"`
+	// TemplatePatch is a YAML or JSON strategic merge patch, rendered with the params like the template, and applied
+	// to the generated Applications. It allows fields of the Application which are not part of the template to be
+	// templated.
+	TemplatePatch *string                            `json:"templatePatch,omitempty"`
+	SyncPolicy    *v1alpha1.ApplicationSetSyncPolicy `json:"syncPolicy,omitempty"`
+	// PreservedFields are the fields of the existing Applications which are left untouched when they are updated, e.g.
+	// spec.source.targetRevision, or metadata.annotations[argocd-image-updater.argoproj.io/image-list] for an annotation.
+	PreservedFields []string `json:"preservedFields,omitempty"`
+	// IgnoreApplicationDifferences are the fields of the existing Applications whose differences with the generated
+	// Applications are ignored: they keep their values, and differences in them don't cause the Applications to be
+	// updated.
+	IgnoreApplicationDifferences []v1alpha1.ApplicationSetResourceIgnoreDifferences `json:"ignoreApplicationDifferences,omitempty"`
+	// DryRun runs the generators and renders the Applications, and reports the changes which would be made to the
+	// Applications in the status and the events of the ApplicationSet, without changing any Application.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Strategy defines how the changes to the Applications are rolled out. Defaults to all at once.
+	Strategy *v1alpha1.ApplicationSetStrategy `json:"strategy,omitempty"`
+	// Suspend stops the generation of the Applications, and the changes to them, until it is set back to false. The
+	// status of the ApplicationSet is left as it was when it was suspended.
+	Suspend bool `json:"suspend,omitempty"`
+	// HistoryLimit is the maximum number of the creations, updates and deletions of the Applications recorded in the
+	// history of the status, the oldest ones being removed first. The history is not recorded if not set, or 0.
+	HistoryLimit *int64 `json:"historyLimit,omitempty"`
+}
+
+// TemplateEngine is the engine rendering the templates of an ApplicationSet.
+type TemplateEngine string
+
+const (
+	// TemplateEngineGo renders the templates with Go templates and the Sprig functions.
+	TemplateEngineGo TemplateEngine = "Go"
+	// TemplateEngineLegacy renders the templates with the {{param}} syntax, as the v1alpha1 ApplicationSets do unless
+	// they set goTemplate.
+	TemplateEngineLegacy TemplateEngine = "Legacy"
+)
+
+// TerminalGenerators are the generators which don't combine other generators. They are shared by the generators of
+// all the nesting levels, each of which sets exactly one generator.
+type TerminalGenerators struct {
+	List                    *v1alpha1.ListGenerator               `json:"list,omitempty"`
+	Clusters                *v1alpha1.ClusterGenerator            `json:"clusters,omitempty"`
+	Git                     *v1alpha1.GitGenerator                `json:"git,omitempty"`
+	SCMProvider             *v1alpha1.SCMProviderGenerator        `json:"scmProvider,omitempty"`
+	ClusterDecisionResource *v1alpha1.DuckTypeGenerator           `json:"clusterDecisionResource,omitempty"`
+	PullRequest             *v1alpha1.PullRequestGenerator        `json:"pullRequest,omitempty"`
+	Plugin                  *v1alpha1.PluginGenerator             `json:"plugin,omitempty"`
+	HTTP                    *v1alpha1.HTTPGenerator               `json:"http,omitempty"`
+	Secret                  *v1alpha1.SecretGenerator             `json:"secret,omitempty"`
+	KubernetesResource      *v1alpha1.KubernetesResourceGenerator `json:"kubernetesResource,omitempty"`
+	AzureSubscriptions      *v1alpha1.AzureSubscriptionsGenerator `json:"azureSubscriptions,omitempty"`
+	Vault                   *v1alpha1.VaultGenerator              `json:"vault,omitempty"`
+	Consul                  *v1alpha1.ConsulGenerator             `json:"consul,omitempty"`
+	HelmRepository          *v1alpha1.HelmRepositoryGenerator     `json:"helmRepository,omitempty"`
+	Bucket                  *v1alpha1.BucketGenerator             `json:"bucket,omitempty"`
+	SQL                     *v1alpha1.SQLGenerator                `json:"sql,omitempty"`
+	TerraformState          *v1alpha1.TerraformStateGenerator     `json:"terraformState,omitempty"`
+}
+
+// ApplicationSetGenerator represents a generator at the top level of an ApplicationSet.
+type ApplicationSetGenerator struct {
+	TerminalGenerators `json:",inline"`
+	Matrix             *MatrixGenerator `json:"matrix,omitempty"`
+	Merge              *MergeGenerator  `json:"merge,omitempty"`
+	Union              *UnionGenerator  `json:"union,omitempty"`
+
+	// Schedule restricts the generator to time windows: outside of the windows, no parameters are generated.
+	Schedule *v1alpha1.GeneratorSchedule `json:"schedule,omitempty"`
+}
+
+// ApplicationSetNestedGenerator represents a generator nested within a combination-type generator (MatrixGenerator,
+// MergeGenerator or UnionGenerator). The generators it combines are terminal generators, since CRDs do not support
+// recursive types. As in the v1alpha1 version, the CRD doesn't validate the schema of its combination-type generators.
+type ApplicationSetNestedGenerator struct {
+	TerminalGenerators `json:",inline"`
+
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Matrix *NestedMatrixGenerator `json:"matrix,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Merge *NestedMergeGenerator `json:"merge,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Union *NestedUnionGenerator `json:"union,omitempty"`
+}
+
+// MatrixGenerator generates the cartesian product of two sets of parameters. The parameters are defined by two nested
+// generators.
+type MatrixGenerator struct {
+	Generators []ApplicationSetNestedGenerator `json:"generators"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template v1alpha1.ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// NestedMatrixGenerator is a MatrixGenerator nested under another combination-type generator, which combines terminal
+// generators, and has no override template.
+type NestedMatrixGenerator struct {
+	Generators []TerminalGenerators `json:"generators"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// MergeGenerator merges the output of two or more generators, by the values of their merge keys, the parameters of the
+// latter generators taking precedence.
+type MergeGenerator struct {
+	Generators []ApplicationSetNestedGenerator `json:"generators"`
+	MergeKeys  []string                        `json:"mergeKeys"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template v1alpha1.ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// NestedMergeGenerator is a MergeGenerator nested under another combination-type generator, which combines terminal
+// generators, and has no override template.
+type NestedMergeGenerator struct {
+	Generators []TerminalGenerators `json:"generators"`
+	MergeKeys  []string             `json:"mergeKeys"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// UnionGenerator concatenates the parameters of two or more generators, keeping only the first parameter set among
+// those with the same values for all the deduplication keys, if any.
+type UnionGenerator struct {
+	Generators        []ApplicationSetNestedGenerator `json:"generators"`
+	DeduplicationKeys []string                        `json:"deduplicationKeys,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Template v1alpha1.ApplicationSetTemplate `json:"template,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// NestedUnionGenerator is a UnionGenerator nested under another combination-type generator, which combines terminal
+// generators, and has no override template.
+type NestedUnionGenerator struct {
+	Generators        []TerminalGenerators `json:"generators"`
+	DeduplicationKeys []string             `json:"deduplicationKeys,omitempty"`
+	// RequeueAfterSeconds, if set, is how long before the child generators generate their parameters again, overriding
+	// the shortest requeue interval of the child generators.
+	RequeueAfterSeconds *int64 `json:"requeueAfterSeconds,omitempty"`
+}
+
+// ApplicationSetList contains a list of ApplicationSet
+// +kubebuilder:object:root=true
+type ApplicationSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApplicationSet{}, &ApplicationSetList{})
+}
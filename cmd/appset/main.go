@@ -0,0 +1,35 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/argoproj-labs/applicationset/pkg/cli"
+)
+
+func main() {
+	// The CLI is also installed as the kubectl-appset kubectl plugin, which kubectl runs for kubectl appset
+	name := "appset"
+	if strings.HasPrefix(filepath.Base(os.Args[0]), "kubectl-") {
+		name = "kubectl appset"
+	}
+	os.Exit(cli.Main(context.Background(), name, os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -1,6 +1,17 @@
 package common
 
 const (
-	// AnnotationApplicationRefresh is an annotation that is added when an ApplicationSet is requested to be refreshed by a webhook. The ApplicationSet controller will remove this annotation at the end of reconcilation.
+	// AnnotationApplicationRefresh is an annotation that is added when an ApplicationSet is requested to be refreshed by a webhook, or by users to force a refresh. Its value is "true", or "hard" to also discard the last known parameters of the generators. The ApplicationSet controller will remove this annotation at the end of reconcilation.
 	AnnotationApplicationSetRefresh = "argocd.argoproj.io/application-set-refresh"
+	// AnnotationApplicationSetTrackingID is the annotation of the Applications tracked by an ApplicationSet with the annotation tracking method, whose value is the namespace and the name of the ApplicationSet, separated by a slash.
+	AnnotationApplicationSetTrackingID = "argocd.argoproj.io/application-set-tracking-id"
+	// AnnotationApplicationSetRenderedHash is the annotation of the Applications of an ApplicationSet whose value is the hash of the Application rendered by the ApplicationSet, so that the Application is not updated while it is rendered the same.
+	AnnotationApplicationSetRenderedHash = "argocd.argoproj.io/application-set-rendered-hash"
+	// AnnotationApplicationSetParamsHash is the annotation of the Applications of an ApplicationSet recording the history of its changes, whose value is the hash of the param set the Application is generated from.
+	AnnotationApplicationSetParamsHash = "argocd.argoproj.io/application-set-params-hash"
+	// AnnotationApplicationSetLogLevel is an annotation of an ApplicationSet which overrides the log level of the controller for the logs of the ApplicationSet, e.g. debug to troubleshoot a single ApplicationSet.
+	AnnotationApplicationSetLogLevel = "argocd.argoproj.io/application-set-log-level"
 )
+
+// RefreshTypeHard is the value of the refresh annotation of an ApplicationSet requesting the parameters to be generated again, without falling back to the last known parameters of the generators when they fail.
+const RefreshTypeHard = "hard"
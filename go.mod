@@ -3,14 +3,22 @@ module github.com/argoproj-labs/applicationset
 go 1.16
 
 require (
+	github.com/Masterminds/semver v1.5.0
+	github.com/Masterminds/sprig/v3 v3.2.2
 	github.com/argoproj/argo-cd/v2 v2.2.0
 	github.com/argoproj/gitops-engine v0.5.1
 	github.com/argoproj/pkg v0.11.1-0.20211203175135-36c59d8fafe0
 	github.com/go-logr/logr v0.4.0
+	github.com/go-sql-driver/mysql v1.6.0
 	github.com/google/go-github/v35 v35.0.0
 	github.com/imdario/mergo v0.3.12
+	github.com/itchyny/gojq v0.12.3
 	github.com/jeremywohl/flatten v1.0.1
+	github.com/lib/pq v1.10.4
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/prometheus/client_golang v1.11.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
 	github.com/valyala/fasttemplate v1.2.1
@@ -0,0 +1,58 @@
+#!/usr/bin/env bash
+
+# Builds the archives of the kubectl appset plugin for each platform, and its krew plugin manifest, in dist/.
+
+set -eo pipefail
+
+SRCROOT="$( CDPATH='' cd -- "$(dirname "$0")/.." && pwd -P )"
+
+VERSION_PACKAGE=github.com/argoproj-labs/applicationset/common
+TAG="${TAG:-v$(cat "$SRCROOT/VERSION")}"
+GIT_COMMIT="$(git rev-parse HEAD)"
+LDFLAGS="-w -s -X ${VERSION_PACKAGE}.version=${TAG#v} -X ${VERSION_PACKAGE}.gitCommit=${GIT_COMMIT}"
+RELEASE_URL="https://github.com/argoproj-labs/applicationset/releases/download/${TAG}"
+PLATFORMS_LIST="${PLATFORMS_LIST:-linux/amd64 linux/arm64 darwin/amd64 darwin/arm64 windows/amd64}"
+
+DIST="$SRCROOT/dist"
+CHECKSUMS="$DIST/kubectl-appset_${TAG}_checksums.txt"
+mkdir -p "$DIST"
+: > "$CHECKSUMS"
+
+PLATFORMS=""
+for platform in $PLATFORMS_LIST; do
+	os="${platform%/*}"
+	arch="${platform#*/}"
+	bin="kubectl-appset"
+	if [ "$os" = "windows" ]; then
+		bin="kubectl-appset.exe"
+	fi
+
+	workdir=$(mktemp -d /tmp/kubectl-appset.XXXXXX)
+	CGO_ENABLED=0 GOOS="$os" GOARCH="$arch" go build -ldflags="${LDFLAGS}" -o "$workdir/$bin" "$SRCROOT/cmd/appset"
+	cp "$SRCROOT/LICENSE" "$workdir/"
+	archive="kubectl-appset_${TAG}_${os}_${arch}.tar.gz"
+	tar -czf "$DIST/$archive" -C "$workdir" "$bin" LICENSE
+	rm -rf "$workdir"
+
+	sha256=$(sha256sum "$DIST/$archive" | cut -d ' ' -f 1)
+	echo "$sha256  $archive" >> "$CHECKSUMS"
+	PLATFORMS="${PLATFORMS}  - selector:
+      matchLabels:
+        os: ${os}
+        arch: ${arch}
+    uri: ${RELEASE_URL}/${archive}
+    sha256: ${sha256}
+    bin: ${bin}
+    files:
+    - from: ${bin}
+      to: .
+    - from: LICENSE
+      to: .
+"
+done
+
+# The comment of the template is not part of the manifest
+TAG="$TAG" PLATFORMS="${PLATFORMS%$'\n'}" \
+	perl -pe 's/\$\{(TAG|PLATFORMS)\}/$ENV{$1}/g' "$SRCROOT/hack/krew/appset.yaml" | grep -v '^#' > "$DIST/appset.yaml"
+
+echo "Built the archives of the kubectl appset plugin, and its krew plugin manifest dist/appset.yaml"
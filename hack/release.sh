@@ -57,6 +57,10 @@ echo
 echo "*** build docker image"
 make image
 
+echo
+echo "*** build the kubectl appset plugin"
+make build-cli-plugin
+
 
 # Include optional parameters in the command output below
 set +u
@@ -78,4 +82,5 @@ echo
 echo "   git push ${TARGET_REMOTE} $RELEASE_BRANCH ${TARGET_TAG}"
 echo "   make ${CONTAINER_REGISTRY_OUTPUT} ${IMAGE_NAMESPACE_OUTPUT} IMAGE_TAG='${TARGET_TAG}'  image-push"
 echo
-echo "Then, create release tag"
+echo "Then, create release tag, with the dist/kubectl-appset_${TARGET_TAG}_* archives and checksums,"
+echo "and submit dist/appset.yaml to the krew index"
@@ -0,0 +1,44 @@
+#!/usr/bin/env bash
+
+# Generates the typed clientset, listers and informers of the ApplicationSet API into pkg/client, with the
+# code-generator tools installed in bin by 'make client-gen lister-gen informer-gen'.
+
+set -eo pipefail
+set -x
+
+SRCROOT="$( CDPATH='' cd -- "$(dirname "$0")/.." && pwd -P )"
+PROJECT=github.com/argoproj-labs/applicationset
+BIN=${SRCROOT}/bin
+HEADER=${SRCROOT}/hack/boilerplate.go.txt
+
+# The generators write the packages under their import path in the output base, so they are generated in a
+# temporary directory and then copied into the module.
+OUTPUT_BASE=$(mktemp -d /tmp/appset-codegen.XXXXXX)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+cd "${SRCROOT}"
+
+"${BIN}/client-gen" \
+  --go-header-file "${HEADER}" \
+  --output-base "${OUTPUT_BASE}" \
+  --input-base "${PROJECT}" \
+  --input api/v1alpha1 \
+  --clientset-name versioned \
+  --output-package "${PROJECT}/pkg/client/clientset"
+
+"${BIN}/lister-gen" \
+  --go-header-file "${HEADER}" \
+  --output-base "${OUTPUT_BASE}" \
+  --input-dirs "${PROJECT}/api/v1alpha1" \
+  --output-package "${PROJECT}/pkg/client/listers"
+
+"${BIN}/informer-gen" \
+  --go-header-file "${HEADER}" \
+  --output-base "${OUTPUT_BASE}" \
+  --input-dirs "${PROJECT}/api/v1alpha1" \
+  --versioned-clientset-package "${PROJECT}/pkg/client/clientset/versioned" \
+  --listers-package "${PROJECT}/pkg/client/listers" \
+  --output-package "${PROJECT}/pkg/client/informers"
+
+rm -rf "${SRCROOT}/pkg/client"
+cp -r "${OUTPUT_BASE}/${PROJECT}/pkg/client" "${SRCROOT}/pkg/client"
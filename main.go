@@ -28,26 +28,36 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argoprojiov1beta1 "github.com/argoproj-labs/applicationset/api/v1beta1"
 	"github.com/argoproj-labs/applicationset/pkg/controllers"
 	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/health"
 	"github.com/argoproj-labs/applicationset/pkg/services"
 	"github.com/argoproj-labs/applicationset/pkg/utils"
+	"github.com/argoproj-labs/applicationset/pkg/validation"
 
 	"github.com/argoproj-labs/applicationset/common"
 	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	argosettings "github.com/argoproj/argo-cd/v2/util/settings"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
 	"github.com/argoproj/pkg/stats"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -64,6 +74,7 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
 	_ = argoprojiov1alpha1.AddToScheme(scheme)
+	_ = argoprojiov1beta1.AddToScheme(scheme)
 
 	_ = argov1alpha1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
@@ -73,26 +84,90 @@ func main() {
 	var metricsAddr string
 	var probeBindAddr string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var leaderElectionLeaseDuration time.Duration
+	var leaderElectionRenewDeadline time.Duration
+	var leaderElectionRetryPeriod time.Duration
+	var gracefulShutdownTimeout time.Duration
 	var namespace string
 	var argocdRepoServer string
 	var policy string
+	var enablePolicyOverride bool
 	var debugLog bool
 	var dryRun bool
 	var logFormat string
 	var logLevel string
+	var watchClusterDecisionResources bool
+	var templatePartialsConfigMap string
+	var applicationSetSelector string
+	var concurrentReconciles int
+	var reconcileTimeout time.Duration
+	var generatorErrorBudget int64
+	var generatorBackoffBaseDelay time.Duration
+	var generatorBackoffMaxDelay time.Duration
+	var maxDeletionPercentage int64
+	var trackingMethod string
+	var skipUnchangedApplications bool
+	var serverSideApply bool
+	var readinessKubeAPI bool
+	var readinessSCMProviders bool
+	var webhookQuietPeriod time.Duration
+	var enableAdmissionWebhook bool
+	var enableConversionWebhook bool
+	var admissionWebhookCertDir string
+	var allowedDestinations string
+	var allowedProjects string
+	var proxyURL string
+	var enabledGenerators string
+	var disabledGenerators string
+	var forbidTemplatedProjects bool
+	var maxParamSets int
+	var applyChunkSize int
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeBindAddr, "probe-addr", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "58ac56fa.applicationsets.argoproj.io", "The name of the resource used for leader election, which must be distinct for each controller Deployment when ApplicationSets are sharded with applicationset-selector.")
+	flag.DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "The duration that the replicas which are not the leader wait before taking over the leadership, when the leader stops renewing its lease.")
+	flag.DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "The duration that the leader retries renewing its lease before giving up the leadership.")
+	flag.DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "The duration between the attempts of the replicas to acquire or renew the lease.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second, "The duration that the controller waits for the reconciliations in progress to finish when it stops, before releasing its lease.")
 	flag.StringVar(&namespace, "namespace", "", "Argo CD repo namespace (default: argocd)")
 	flag.StringVar(&argocdRepoServer, "argocd-repo-server", "argocd-repo-server:8081", "Argo CD repo server address")
-	flag.StringVar(&policy, "policy", "sync", "Modify how application is synced between the generator and the cluster. Default is 'sync' (create & update & delete), options: 'create-only', 'create-update' (no deletion)")
+	flag.StringVar(&policy, "policy", "sync", "Modify how application is synced between the generator and the cluster. Default is 'sync' (create & update & delete), options: 'create-only', 'create-update' (no deletion), 'create-delete' (no update)")
+	flag.BoolVar(&enablePolicyOverride, "enable-policy-override", false, "Allow the applicationsSync policy of each ApplicationSet to override the policy of the controller.")
 	flag.BoolVar(&debugLog, "debug", false, "Print debug logs. Takes precedence over loglevel")
 	flag.StringVar(&logLevel, "loglevel", "info", "Set the logging level. One of: debug|info|warn|error")
 	flag.BoolVar(&dryRun, "dry-run", false, "Enable dry run mode")
 	flag.StringVar(&logFormat, "logformat", "text", "Set the logging format. One of: text|json")
+	flag.BoolVar(&watchClusterDecisionResources, "enable-cluster-decision-resource-watch", false, "Watch the resources referenced by ClusterDecisionResource generators, and reconcile ApplicationSets as soon as they change. Requires permission to watch these resources.")
+	flag.StringVar(&templatePartialsConfigMap, "template-partials-configmap", "", "The name of a ConfigMap of the namespace of the controller, whose keys are named templates which the Go templates of ApplicationSets can include.")
+	flag.StringVar(&applicationSetSelector, "applicationset-selector", "", "Only reconcile the ApplicationSets whose labels match this label selector, e.g. 'team=frontend', so that several controllers may each reconcile a disjoint set of ApplicationSets.")
+	flag.IntVar(&concurrentReconciles, "concurrent-reconciles", 1, "The maximum number of ApplicationSets reconciled at the same time.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 5*time.Minute, "The maximum duration of the reconciliation of an ApplicationSet, after which the generated Applications are discarded and the ApplicationSet is requeued. 0 to disable.")
+	flag.Int64Var(&generatorErrorBudget, "generator-error-budget", 3, "The number of consecutive reconciliations in which the generators of an ApplicationSet may fail before the controller backs off.")
+	flag.DurationVar(&generatorBackoffBaseDelay, "generator-backoff-base-delay", 10*time.Second, "The delay before running the generators of an ApplicationSet again, after the first failure exceeding the error budget. It doubles at each subsequent failure. 0 to disable the backoff.")
+	flag.DurationVar(&generatorBackoffMaxDelay, "generator-backoff-max-delay", 15*time.Minute, "The maximum delay before running the generators of an ApplicationSet again, after consecutive failures.")
+	flag.Int64Var(&maxDeletionPercentage, "max-deletion-percentage", 100, "The maximum percentage of the Applications of an ApplicationSet which may be deleted by a reconciliation, unless the ApplicationSet sets syncPolicy.maxDeletionPercentage. If more Applications would be deleted, none is deleted. 100 to disable the limit.")
+	flag.StringVar(&trackingMethod, "tracking-method", string(utils.TrackingMethodOwnerReference), "How the Applications generated by an ApplicationSet are tracked: 'owner-reference' sets the ApplicationSet as their owner, so that they are deleted with it, 'annotation' sets the argocd.argoproj.io/application-set-tracking-id annotation, so that they are not deleted with it.")
+	flag.BoolVar(&skipUnchangedApplications, "skip-unchanged-applications", false, "Leave the existing Applications untouched while they are rendered the same as when they were last applied, unless their ApplicationSet is refreshed, rather than updating them when they differ from the rendered Applications. This avoids updating Applications at each reconciliation when their fields are normalized by the API server or other controllers.")
+	flag.BoolVar(&serverSideApply, "server-side-apply", false, "Create and update the Applications with server-side apply, so that the fields of the Applications set by other controllers or users are left untouched, and the conflicts with the fields they manage are reported as errors.")
+	flag.BoolVar(&readinessKubeAPI, "readiness-kube-api", false, "Report the controller as not ready while the Kubernetes API is unreachable.")
+	flag.BoolVar(&readinessSCMProviders, "readiness-scm-providers", false, "Report the controller as not ready while the last request to any SCM provider or pull request provider failed, i.e. no response was received, or the provider returned a server error or throttled the request.")
+	flag.DurationVar(&webhookQuietPeriod, "webhook-quiet-period", 0, "The duration without webhook events for an ApplicationSet after which it is refreshed, so that a burst of events results in a single refresh. The refresh is delayed at most 5 quiet periods after the first event. 0 to refresh the ApplicationSets as soon as an event is received.")
+	flag.BoolVar(&enableAdmissionWebhook, "enable-admission-webhook", false, "Serve the validating admission webhook of the ApplicationSets on port 9443, which rejects the ApplicationSets with invalid generators, undeclared params or forbidden destinations when they are applied.")
+	flag.BoolVar(&enableConversionWebhook, "enable-conversion-webhook", false, "Serve the conversion webhook of the ApplicationSets on port 9443, at /convert, which converts the ApplicationSets between the v1alpha1 and v1beta1 versions, so that the v1beta1 version can be served.")
+	flag.StringVar(&admissionWebhookCertDir, "admission-webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory of the tls.crt and tls.key serving certificate of the validating admission webhook and the conversion webhook.")
+	flag.StringVar(&allowedDestinations, "allowed-destinations", "", "A comma-separated list of <namespace>@<server> destinations, whose namespace and server URL or cluster name may contain '*' wildcards, e.g. 'team-*@https://kubernetes.default.svc', outside of which the generated Applications aren't created nor updated, and the validating admission webhook rejects the destinations of the templates. All the destinations are allowed if empty.")
+	flag.StringVar(&allowedProjects, "allowed-projects", "", "A comma-separated list of Argo CD projects, which may contain '*' wildcards, e.g. 'team-*', outside of which the generated Applications aren't created nor updated, and the validating admission webhook rejects the projects of the templates. All the projects are allowed if empty.")
+	flag.StringVar(&proxyURL, "proxy-url", "", "The URL of the HTTP or HTTPS proxy of the requests of the SCM Provider, Pull Request and HTTP generators, which the generators may override. Read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables if empty.")
+	flag.StringVar(&enabledGenerators, "enable-generators", "", "A comma-separated list of the generators which the ApplicationSets may use, e.g. 'list,clusters,git,matrix'. The other generators fail, and the validating admission webhook rejects them. All the generators are enabled if empty.")
+	flag.StringVar(&disabledGenerators, "disable-generators", "", "A comma-separated list of the generators which the ApplicationSets may not use, e.g. 'clusters,http'. The disabled generators fail, and the validating admission webhook rejects them.")
+	flag.BoolVar(&forbidTemplatedProjects, "forbid-templated-projects", false, "Reject the ApplicationSets whose templates or template patch render the project of the Applications from params, so that the params, e.g. the labels of a cluster or the name of a branch, can't place the Applications in another project. The existing Applications of the rejected ApplicationSets are left untouched.")
+	flag.IntVar(&maxParamSets, "max-param-sets", 0, "The maximum number of param sets generated for an ApplicationSet, including the combinations of the Matrix generators. Beyond it, the ApplicationSet reports a ParamSetsOverflow error, and its Applications are neither updated nor deleted. 0 to disable the maximum.")
+	flag.IntVar(&applyChunkSize, "apply-chunk-size", 0, "The number of Applications of an ApplicationSet validated, and created or updated, at once, as soon as they are rendered, rather than after all of them are rendered. 0 to apply all the Applications of an ApplicationSet at once.")
 	flag.Parse()
 
 	json := strings.ToLower(logFormat) == JsonFormat
@@ -104,12 +179,54 @@ func main() {
 
 	policyObj, exists := utils.Policies[policy]
 	if !exists {
-		setupLog.Info("Policy value can be: sync, create-only, create-update")
+		setupLog.Info("Policy value can be: sync, create-only, create-update, create-delete")
 		os.Exit(1)
 	}
 
 	setLoggingLevel(debugLog, logLevel)
 
+	trackingMethodObj, exists := utils.TrackingMethods[trackingMethod]
+	if !exists {
+		setupLog.Info("tracking-method value can be: owner-reference, annotation")
+		os.Exit(1)
+	}
+
+	if maxDeletionPercentage < 0 || maxDeletionPercentage > 100 {
+		setupLog.Info("max-deletion-percentage must be between 0 and 100", "max-deletion-percentage", maxDeletionPercentage)
+		os.Exit(1)
+	}
+
+	if concurrentReconciles < 1 {
+		setupLog.Info("concurrent-reconciles must be at least 1", "concurrent-reconciles", concurrentReconciles)
+		os.Exit(1)
+	}
+
+	allowedDestinationsObj, err := utils.ParseDestinationAllowList(allowedDestinations)
+	if err != nil {
+		setupLog.Error(err, "unable to parse allowed-destinations", "allowed-destinations", allowedDestinations)
+		os.Exit(1)
+	}
+	allowedProjectsObj := utils.ParseProjectAllowList(allowedProjects)
+
+	disabledGeneratorsObj, err := utils.ParseDisabledGenerators(enabledGenerators, disabledGenerators)
+	if err != nil {
+		setupLog.Error(err, "unable to parse enable-generators and disable-generators", "enable-generators", enabledGenerators, "disable-generators", disabledGenerators)
+		os.Exit(1)
+	}
+
+	if err := utils.SetDefaultProxy(proxyURL); err != nil {
+		setupLog.Error(err, "unable to parse proxy-url", "proxy-url", proxyURL)
+		os.Exit(1)
+	}
+
+	var applicationSetSelectorObj labels.Selector
+	if applicationSetSelector != "" {
+		if applicationSetSelectorObj, err = labels.Parse(applicationSetSelector); err != nil {
+			setupLog.Error(err, "unable to parse applicationset-selector", "applicationset-selector", applicationSetSelector)
+			os.Exit(1)
+		}
+	}
+
 	// If user has not specified a namespace on the CLI, then use the value from NAMESPACE env var
 	if len(namespace) == 0 {
 		// Determine the namespace we're running in. Normally injected into the pod as an env
@@ -136,15 +253,43 @@ func main() {
 		NewCache:               cache.MultiNamespacedCacheBuilder([]string{namespace}),
 		HealthProbeBindAddress: probeBindAddr,
 		Port:                   9443,
+		CertDir:                admissionWebhookCertDir,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "58ac56fa.applicationsets.argoproj.io",
-		DryRunClient:           dryRun,
+		LeaderElectionID:       leaderElectionID,
+		// Leases are lighter than the ConfigMaps used by default, and the lease is released when the controller stops,
+		// so that another replica takes over without waiting for the lease to expire.
+		LeaderElectionResourceLock:    resourcelock.LeasesResourceLock,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaderElectionLeaseDuration,
+		RenewDeadline:                 &leaderElectionRenewDeadline,
+		RetryPeriod:                   &leaderElectionRetryPeriod,
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
+		DryRunClient:                  dryRun,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if enableAdmissionWebhook {
+		mgr.GetWebhookServer().Register(validation.ValidatingWebhookPath, &webhook.Admission{
+			Handler: validation.NewApplicationSetValidator(validation.Options{
+				AllowedProjects:         allowedProjectsObj,
+				AllowedDestinations:     allowedDestinationsObj,
+				DisabledGenerators:      disabledGeneratorsObj,
+				ForbidTemplatedProjects: forbidTemplatedProjects,
+			}),
+		})
+	}
+	if enableConversionWebhook {
+		mgr.GetWebhookServer().Register("/convert", &conversion.Webhook{})
+	}
+
+	if err := addHealthChecks(mgr, readinessKubeAPI, readinessSCMProviders); err != nil {
+		setupLog.Error(err, "unable to set up health checks")
+		os.Exit(1)
+	}
+
 	k8s := kubernetes.NewForConfigOrDie(mgr.GetConfig())
 	dynClient := dynamic.NewForConfigOrDie(mgr.GetConfig())
 	argoSettingsMgr := argosettings.NewSettingsManager(context.Background(), k8s, namespace)
@@ -153,55 +298,72 @@ func main() {
 	argoCDDB := db.NewDB(namespace, argoSettingsMgr, k8s)
 
 	// start a webhook server that listens to incoming webhook payloads
-	webhookHandler, err := utils.NewWebhookHandler(namespace, argoSettingsMgr, mgr.GetClient())
+	webhookHandler, err := utils.NewWebhookHandler(namespace, argoSettingsMgr, mgr.GetClient(), webhookQuietPeriod)
 	if err != nil {
 		setupLog.Error(err, "failed to create webhook handler")
 		os.Exit(1)
 	}
 	startWebhookServer(webhookHandler)
 
-	terminalGenerators := map[string]generators.Generator{
-		"List":                    generators.NewListGenerator(),
-		"Clusters":                generators.NewClusterGenerator(mgr.GetClient(), context.Background(), k8s, namespace),
-		"Git":                     generators.NewGitGenerator(services.NewArgoCDService(argoCDDB, argocdRepoServer)),
-		"SCMProvider":             generators.NewSCMProviderGenerator(mgr.GetClient()),
-		"ClusterDecisionResource": generators.NewDuckTypeGenerator(context.Background(), dynClient, k8s, namespace),
-		"PullRequest":             generators.NewPullRequestGenerator(mgr.GetClient()),
-	}
-
-	nestedGenerators := map[string]generators.Generator{
-		"List":                    terminalGenerators["List"],
-		"Clusters":                terminalGenerators["Clusters"],
-		"Git":                     terminalGenerators["Git"],
-		"SCMProvider":             terminalGenerators["SCMProvider"],
-		"ClusterDecisionResource": terminalGenerators["ClusterDecisionResource"],
-		"PullRequest":             terminalGenerators["PullRequest"],
-		"Matrix":                  generators.NewMatrixGenerator(terminalGenerators),
-		"Merge":                   generators.NewMergeGenerator(terminalGenerators),
-	}
-
-	topLevelGenerators := map[string]generators.Generator{
-		"List":                    terminalGenerators["List"],
-		"Clusters":                terminalGenerators["Clusters"],
-		"Git":                     terminalGenerators["Git"],
-		"SCMProvider":             terminalGenerators["SCMProvider"],
-		"ClusterDecisionResource": terminalGenerators["ClusterDecisionResource"],
-		"PullRequest":             terminalGenerators["PullRequest"],
-		"Matrix":                  generators.NewMatrixGenerator(nestedGenerators),
-		"Merge":                   generators.NewMergeGenerator(nestedGenerators),
+	// the values generated from Secrets are redacted from the logs, as well as from the events and status of ApplicationSets
+	sensitiveValues := utils.NewSensitiveValues()
+	log.AddHook(sensitiveValues)
+
+	topLevelGenerators := generators.NewStandardGenerators(context.Background(), generators.Clients{
+		Client:          mgr.GetClient(),
+		KubeClientset:   k8s,
+		DynamicClient:   dynClient,
+		RESTMapper:      mgr.GetRESTMapper(),
+		Repos:           services.NewArgoCDService(argoCDDB, argocdRepoServer),
+		Namespace:       namespace,
+		SensitiveValues: sensitiveValues,
+	}, disabledGeneratorsObj)
+
+	ctx := ctrl.SetupSignalHandler()
+
+	var clusterDecisionResourceWatcher *controllers.ClusterDecisionResourceWatcher
+	if watchClusterDecisionResources {
+		clusterDecisionResourceWatcher = controllers.NewClusterDecisionResourceWatcher(ctx, mgr.GetClient(), dynClient, k8s, namespace)
+	}
+
+	renderer := &utils.Render{}
+	if templatePartialsConfigMap != "" {
+		renderer.Partials = utils.NewConfigMapTemplatePartials(context.Background(), mgr.GetClient(), namespace, templatePartialsConfigMap)
+	}
+
+	generatorBackoff := &controllers.GeneratorBackoff{
+		ErrorBudget: generatorErrorBudget,
+		BaseDelay:   generatorBackoffBaseDelay,
+		MaxDelay:    generatorBackoffMaxDelay,
 	}
 
 	if err = (&controllers.ApplicationSetReconciler{
-		Generators:       topLevelGenerators,
-		Client:           mgr.GetClient(),
-		Log:              ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
-		Scheme:           mgr.GetScheme(),
-		Recorder:         mgr.GetEventRecorderFor("applicationset-controller"),
-		Renderer:         &utils.Render{},
-		Policy:           policyObj,
-		ArgoAppClientset: appSetConfig,
-		KubeClientset:    k8s,
-		ArgoDB:           argoCDDB,
+		Generators:                     topLevelGenerators,
+		Client:                         mgr.GetClient(),
+		Log:                            ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+		Scheme:                         mgr.GetScheme(),
+		Recorder:                       mgr.GetEventRecorderFor("applicationset-controller"),
+		Renderer:                       renderer,
+		Policy:                         policyObj,
+		EnablePolicyOverride:           enablePolicyOverride,
+		ApplicationSetSelector:         applicationSetSelectorObj,
+		MaxConcurrentReconciles:        concurrentReconciles,
+		ReconcileTimeout:               reconcileTimeout,
+		GeneratorBackoff:               generatorBackoff,
+		MaxDeletionPercentage:          &maxDeletionPercentage,
+		TrackingMethod:                 trackingMethodObj,
+		SkipUnchangedApplications:      skipUnchangedApplications,
+		ServerSideApply:                serverSideApply,
+		AllowedProjects:                allowedProjectsObj,
+		AllowedDestinations:            allowedDestinationsObj,
+		ForbidTemplatedProjects:        forbidTemplatedProjects,
+		MaxParamSets:                   maxParamSets,
+		ApplyChunkSize:                 applyChunkSize,
+		ArgoAppClientset:               appSetConfig,
+		KubeClientset:                  k8s,
+		ArgoDB:                         argoCDDB,
+		ClusterDecisionResourceWatcher: clusterDecisionResourceWatcher,
+		SensitiveValues:                sensitiveValues,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApplicationSet")
 		os.Exit(1)
@@ -212,7 +374,7 @@ func main() {
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("Starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -232,9 +394,39 @@ func setLoggingLevel(debug bool, logLevel string) {
 	}
 }
 
+// addHealthChecks adds the liveness and readiness checks of the controller, served on the probe address, and the status
+// of the SCM providers, served on the metrics address.
+func addHealthChecks(mgr ctrl.Manager, kubeAPI bool, scmProviders bool) error {
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		return err
+	}
+	if kubeAPI {
+		// The check must not outlast the probe
+		config := rest.CopyConfig(mgr.GetConfig())
+		config.Timeout = 5 * time.Second
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return err
+		}
+		if err := mgr.AddReadyzCheck("kube-api", health.KubeAPICheck(discoveryClient)); err != nil {
+			return err
+		}
+	}
+	if scmProviders {
+		if err := mgr.AddReadyzCheck("scm-providers", health.SCMProvidersCheck()); err != nil {
+			return err
+		}
+	}
+	return mgr.AddMetricsExtraHandler("/healthz/scm-providers", health.SCMProvidersHandler())
+}
+
 func startWebhookServer(webhookHandler *utils.WebhookHandler) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/webhook", webhookHandler.Handler)
+	mux.HandleFunc("/api/webhook/refresh", webhookHandler.RefreshHandler)
 	go func() {
 		setupLog.Info("Starting webhook server")
 		err := http.ListenAndServe(":7000", mux)
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+)
+
+// OutputText is the output format of the changes as unified diffs.
+const OutputText = "text"
+
+// changeDescriptions describe the actions of the changes, e.g. "will be created".
+var changeDescriptions = map[argoprojiov1alpha1.ApplicationSetDryRunActionType]string{
+	argoprojiov1alpha1.ApplicationSetDryRunActionCreate: "will be created",
+	argoprojiov1alpha1.ApplicationSetDryRunActionUpdate: "will be updated",
+	argoprojiov1alpha1.ApplicationSetDryRunActionDelete: "will be deleted",
+}
+
+// applicationSetChanges are the changes to the Applications of an ApplicationSet.
+type applicationSetChanges struct {
+	namespace string
+	name      string
+	changes   []controllers.ApplicationChange
+}
+
+// changeOutput is a change of the JSON output.
+type changeOutput struct {
+	ApplicationSet string                                            `json:"applicationSet"`
+	Action         argoprojiov1alpha1.ApplicationSetDryRunActionType `json:"action"`
+	Application    string                                            `json:"application"`
+	// Diff is the unified diff of the fields of the Application managed by the ApplicationSet controller.
+	Diff string `json:"diff"`
+}
+
+// printChanges prints the changes to the Applications of the ApplicationSets, as unified diffs of the fields managed by
+// the ApplicationSet controller followed by a summary, or as JSON. The output is passed to redact, which replaces the
+// sensitive values.
+func printChanges(w io.Writer, output string, plans []applicationSetChanges, redact func(string) string) error {
+	outputs := []changeOutput{}
+	counts := map[argoprojiov1alpha1.ApplicationSetDryRunActionType]int{}
+	for _, plan := range plans {
+		for _, change := range plan.changes {
+			diff, err := changeDiff(plan.namespace, change)
+			if err != nil {
+				return err
+			}
+			outputs = append(outputs, changeOutput{ApplicationSet: plan.name, Action: change.Action, Application: change.Name, Diff: diff})
+			counts[change.Action]++
+		}
+	}
+
+	var text string
+	switch output {
+	case OutputText:
+		var b strings.Builder
+		for _, o := range outputs {
+			fmt.Fprintf(&b, "Application %s of ApplicationSet %s %s\n%s\n", o.Application, o.ApplicationSet, changeDescriptions[o.Action], o.Diff)
+		}
+		if len(outputs) == 0 {
+			b.WriteString("No changes.\n")
+		} else {
+			fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d to delete.\n",
+				counts[argoprojiov1alpha1.ApplicationSetDryRunActionCreate],
+				counts[argoprojiov1alpha1.ApplicationSetDryRunActionUpdate],
+				counts[argoprojiov1alpha1.ApplicationSetDryRunActionDelete])
+		}
+		text = b.String()
+	case OutputJSON:
+		data, err := json.MarshalIndent(outputs, "", "  ")
+		if err != nil {
+			return err
+		}
+		text = string(data) + "\n"
+	default:
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputText, OutputJSON)
+	}
+
+	_, err := io.WriteString(w, redact(text))
+	return err
+}
+
+// changeDiff returns the unified diff of the fields of the Application managed by the ApplicationSet controller,
+// before and after the change.
+func changeDiff(namespace string, change controllers.ApplicationChange) (string, error) {
+	current, err := managedFields(change.Current)
+	if err != nil {
+		return "", err
+	}
+	desired, err := managedFields(change.Desired)
+	if err != nil {
+		return "", err
+	}
+
+	fromFile, toFile := namespace+"/"+change.Name, namespace+"/"+change.Name
+	if change.Current == nil {
+		fromFile = "/dev/null"
+	}
+	if change.Desired == nil {
+		toFile = "/dev/null"
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(desired),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+// splitLines splits the text into lines ending with a newline.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// managedFields returns the fields of the Application managed by the ApplicationSet controller as YAML, or an empty
+// string if the Application is nil.
+func managedFields(app *argov1alpha1.Application) (string, error) {
+	if app == nil {
+		return "", nil
+	}
+	manifest, err := toManifest(&argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:          app.Labels,
+			Annotations:     app.Annotations,
+			Finalizers:      app.Finalizers,
+			OwnerReferences: app.OwnerReferences,
+		},
+		Spec: app.Spec,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling application %s: %v", app.Name, err)
+	}
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling application %s: %v", app.Name, err)
+	}
+	return string(data), nil
+}
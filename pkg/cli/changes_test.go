@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+)
+
+func TestPrintChanges(t *testing.T) {
+	app := func(name string, path string) *argov1alpha1.Application {
+		return &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd", ResourceVersion: "1"},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "default",
+				Source:  argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps.git", Path: path},
+			},
+		}
+	}
+	plans := []applicationSetChanges{{
+		namespace: "argocd",
+		name:      "guestbook",
+		changes: []controllers.ApplicationChange{
+			{Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate, Name: "staging", Desired: app("staging", "guestbook")},
+			{Action: argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, Name: "production", Current: app("production", "guestbook"), Desired: app("production", "secret-guestbook")},
+			{Action: argoprojiov1alpha1.ApplicationSetDryRunActionDelete, Name: "qa", Current: app("qa", "guestbook")},
+		},
+	}}
+	redact := func(text string) string {
+		return strings.ReplaceAll(text, "secret", "++++++++")
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printChanges(&out, OutputText, plans, redact))
+	assert.Contains(t, out.String(), `Application staging of ApplicationSet guestbook will be created
+--- /dev/null
++++ argocd/staging
+@@ -0,0 +1,7 @@
++metadata: {}
++spec:
+`)
+	// only the changed fields are shown, with their context
+	assert.Contains(t, out.String(), `Application production of ApplicationSet guestbook will be updated
+--- argocd/production
++++ argocd/production
+@@ -3,5 +3,5 @@
+`)
+	assert.Contains(t, out.String(), "-    path: guestbook\n+    path: ++++++++-guestbook\n")
+	assert.Contains(t, out.String(), "Application qa of ApplicationSet guestbook will be deleted\n--- argocd/qa\n+++ /dev/null\n")
+	assert.True(t, strings.HasSuffix(out.String(), "\nPlan: 1 to create, 1 to update, 1 to delete.\n"), out.String())
+
+	out.Reset()
+	require.NoError(t, printChanges(&out, OutputJSON, plans, redact))
+	assert.Contains(t, out.String(), `"applicationSet": "guestbook"`)
+	assert.Contains(t, out.String(), `"action": "update"`)
+	assert.Contains(t, out.String(), `"application": "production"`)
+	assert.NotContains(t, out.String(), "secret")
+
+	out.Reset()
+	require.NoError(t, printChanges(&out, OutputText, []applicationSetChanges{{namespace: "argocd", name: "guestbook"}}, redact))
+	assert.Equal(t, "No changes.\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printChanges(&out, OutputJSON, nil, redact))
+	assert.Equal(t, "[]\n", out.String())
+}
@@ -0,0 +1,321 @@
+// Package cli implements the appset command line interface, which renders and checks ApplicationSets outside of the
+// controller, e.g. to validate the changes to ApplicationSets in CI before they are merged.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argoprojiov1beta1 "github.com/argoproj-labs/applicationset/api/v1beta1"
+)
+
+const (
+	OutputYAML = "yaml"
+	OutputJSON = "json"
+)
+
+// command is a subcommand of the CLI, e.g. generate.
+type command struct {
+	name string
+	// usage is the synopsis of the arguments of the command.
+	usage string
+	// description is the one line description of the command.
+	description string
+	// run runs the command with its arguments, writing its output to stdout.
+	run func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{
+			name:        "generate",
+			usage:       "[flags] FILE...",
+			description: "Render the Applications of ApplicationSets offline, from fixtures of the clusters, SCM providers and Git repositories.",
+			run:         runGenerate,
+		},
+		{
+			name:        "diff",
+			usage:       "[flags] NAME | -f FILE...",
+			description: "Show the Applications which the controller would create, update and delete for an ApplicationSet of the cluster, or for ApplicationSet files, with the diffs of their fields.",
+			run:         runDiff,
+		},
+		{
+			name:        "params",
+			usage:       "[flags] NAME | -f FILE",
+			description: "Print the param sets of a generator of an ApplicationSet, run against the cluster, e.g. to debug its filters and merge keys.",
+			run:         runParams,
+		},
+		{
+			name:        "lint",
+			usage:       "[flags] FILE...",
+			description: "Check ApplicationSet files against their schema and for the errors which the controller would report, exiting with 1 on errors, e.g. in CI.",
+			run:         runLint,
+		},
+		{
+			name:        "refresh",
+			usage:       "[flags] NAME...",
+			description: "Request the controller to reconcile ApplicationSets of the cluster right away, and optionally wait until it has.",
+			run:         runRefresh,
+		},
+	}
+}
+
+// Main runs the command named by the first of the arguments, and returns the exit code of the CLI. The name of the
+// CLI is used in the usage messages, e.g. appset or kubectl appset.
+func Main(ctx context.Context, name string, args []string, stdout, stderr io.Writer) int {
+	log.SetOutput(stderr)
+	log.SetLevel(log.WarnLevel)
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(name, stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, name+" "+cmd.name, args[1:], stdout, stderr)
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		case err != nil:
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+	printUsage(name, stderr)
+	return 2
+}
+
+func printUsage(name string, w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s COMMAND [flags]\n\nCommands:\n", name)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(w, "\nRun '%s COMMAND --help' for the flags of a command.\n", name)
+}
+
+// errUsage is returned by the commands whose flags or arguments are invalid, once the usage was printed.
+var errUsage = errors.New("invalid usage")
+
+// newFlagSet returns the flag set of a command, printing its usage to stderr.
+func newFlagSet(name string, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of a command, and sets the level of the logs of the generators, unless logLevel is nil
+// for the commands which don't run the generators.
+func parseFlags(fs *flag.FlagSet, args []string, logLevel *string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if logLevel == nil {
+		return nil
+	}
+	level, err := log.ParseLevel(*logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid --loglevel %q: %v", *logLevel, err)
+	}
+	log.SetLevel(level)
+	return nil
+}
+
+// stringSliceFlag is a flag which may be repeated, or set to a comma-separated list.
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+	return nil
+}
+
+// readFile reads a file, or the standard input if the path is "-".
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// applicationSetDocument is an ApplicationSet read from a document of a file.
+type applicationSetDocument struct {
+	// path is the path of the file.
+	path string
+	// raw is the JSON of the document.
+	raw            json.RawMessage
+	applicationSet argoprojiov1alpha1.ApplicationSet
+}
+
+// readApplicationSets reads the ApplicationSets of YAML or JSON files, which may contain several documents. The
+// documents of other kinds are skipped, so that the files may contain other resources, e.g. the output of kustomize.
+func readApplicationSets(paths []string, defaultNamespace string) ([]argoprojiov1alpha1.ApplicationSet, error) {
+	docs, err := readApplicationSetDocuments(paths, defaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]argoprojiov1alpha1.ApplicationSet, len(docs))
+	for i := range docs {
+		res[i] = docs[i].applicationSet
+	}
+	return res, nil
+}
+
+// readApplicationSetDocuments reads the ApplicationSets of the files as readApplicationSets, with the documents they
+// were read from.
+func readApplicationSetDocuments(paths []string, defaultNamespace string) ([]applicationSetDocument, error) {
+	var res []applicationSetDocument
+	for _, path := range paths {
+		data, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("error parsing %s: %v", path, err)
+			}
+			if len(bytes.TrimSpace(raw)) == 0 || string(raw) == "null" {
+				continue
+			}
+
+			appSet, err := decodeApplicationSet(raw)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %v", path, err)
+			}
+			if appSet.Kind != "ApplicationSet" {
+				continue
+			}
+			if appSet.Namespace == "" {
+				appSet.Namespace = defaultNamespace
+			}
+			res = append(res, applicationSetDocument{path: path, raw: raw, applicationSet: appSet})
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no ApplicationSet found in %s", strings.Join(paths, ", "))
+	}
+	return res, nil
+}
+
+// decodeApplicationSet decodes the JSON of a document as an ApplicationSet. The v1beta1 ApplicationSets are converted to
+// v1alpha1, as the API server does through the conversion webhook.
+func decodeApplicationSet(raw json.RawMessage) (argoprojiov1alpha1.ApplicationSet, error) {
+	var appSet argoprojiov1alpha1.ApplicationSet
+	if err := json.Unmarshal(raw, &appSet); err != nil {
+		return appSet, err
+	}
+	if appSet.Kind != "ApplicationSet" || appSet.APIVersion != argoprojiov1beta1.GroupVersion.String() {
+		return appSet, nil
+	}
+
+	var v1beta1AppSet argoprojiov1beta1.ApplicationSet
+	if err := json.Unmarshal(raw, &v1beta1AppSet); err != nil {
+		return appSet, err
+	}
+	appSet = argoprojiov1alpha1.ApplicationSet{}
+	if err := v1beta1AppSet.ConvertTo(&appSet); err != nil {
+		return appSet, err
+	}
+	appSet.TypeMeta = metav1.TypeMeta{APIVersion: argoprojiov1alpha1.GroupVersion.String(), Kind: "ApplicationSet"}
+	return appSet, nil
+}
+
+// printObjects prints the objects as YAML documents, or as a JSON List, without their empty status and creation
+// timestamp, so that they can be applied as they are.
+func printObjects(w io.Writer, output string, objects []interface{}) error {
+	items := make([]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		item, err := toManifest(obj)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	switch output {
+	case OutputYAML:
+		for _, item := range items {
+			data, err := yaml.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputJSON:
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputYAML, OutputJSON)
+	}
+}
+
+// toManifest returns the object as a map, without its status and its null creation timestamp.
+func toManifest(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	manifest := map[string]interface{}{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok && metadata["creationTimestamp"] == nil {
+		delete(metadata, "creationTimestamp")
+	}
+	return manifest, nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadApplicationSets(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "appsets.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+---
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: guestbook
+spec:
+  generators: []
+---
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: helm
+  namespace: apps
+spec:
+  generators: []
+---
+apiVersion: argoproj.io/v1beta1
+kind: ApplicationSet
+metadata:
+  name: go
+spec:
+  generators: []
+---
+apiVersion: argoproj.io/v1beta1
+kind: ApplicationSet
+metadata:
+  name: legacy
+spec:
+  templateEngine: Legacy
+  generators: []
+`)
+
+	appSets, err := readApplicationSets([]string{path}, "argocd")
+	require.NoError(t, err)
+	require.Len(t, appSets, 4)
+	assert.Equal(t, "argocd", appSets[0].Namespace)
+	assert.Equal(t, "guestbook", appSets[0].Name)
+	assert.False(t, appSets[0].Spec.GoTemplate)
+	assert.Equal(t, "apps", appSets[1].Namespace)
+	assert.Equal(t, "argocd", appSets[2].Namespace)
+	assert.Equal(t, "argoproj.io/v1alpha1", appSets[2].APIVersion)
+	assert.True(t, appSets[2].Spec.GoTemplate)
+	assert.False(t, appSets[3].Spec.GoTemplate)
+
+	_, err = readApplicationSets([]string{writeFile(t, dir, "empty.yaml", "kind: ConfigMap\n")}, "argocd")
+	assert.EqualError(t, err, "no ApplicationSet found in "+filepath.Join(dir, "empty.yaml"))
+
+	_, err = readApplicationSets([]string{writeFile(t, dir, "invalid.yaml", "kind: ApplicationSet\nspec: [\n")}, "argocd")
+	assert.Error(t, err)
+}
+
+func TestPrintObjects(t *testing.T) {
+	app := &argov1alpha1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+		Spec:       argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printObjects(&out, OutputYAML, []interface{}{app, app}))
+	// the empty status and creation timestamp are not printed
+	assert.NotContains(t, out.String(), "status")
+	assert.NotContains(t, out.String(), "creationTimestamp")
+	assert.Equal(t, 2, bytes.Count(out.Bytes(), []byte("---\n")))
+	assert.Contains(t, out.String(), "  project: default\n")
+
+	out.Reset()
+	require.NoError(t, printObjects(&out, OutputJSON, []interface{}{app}))
+	assert.Contains(t, out.String(), `"kind": "List"`)
+	assert.Contains(t, out.String(), `"name": "guestbook"`)
+
+	assert.Error(t, printObjects(&out, "table", nil))
+}
+
+func TestMainCommands(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, Main(context.Background(), "appset", nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: appset COMMAND")
+	assert.Contains(t, stderr.String(), "generate")
+
+	stderr.Reset()
+	assert.Equal(t, 2, Main(context.Background(), "appset", []string{"unknown"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "unknown"`)
+
+	assert.Equal(t, 0, Main(context.Background(), "appset", []string{"help"}, &stdout, &stderr))
+}
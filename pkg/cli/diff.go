@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func runDiff(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, "[flags] NAME | -f FILE...", stderr)
+	var opts liveOptions
+	opts.addFlags(fs)
+	var files stringSliceFlag
+	fs.Var(&files, "filename", "A file of ApplicationSets to compare with the Applications of the ApplicationSets of the same names in the cluster, rather than an ApplicationSet of the cluster. May be repeated.")
+	fs.Var(&files, "f", "Shorthand for --filename.")
+	var output string
+	fs.StringVar(&output, "output", OutputText, "The output format of the changes: text or json.")
+	fs.StringVar(&output, "o", OutputText, "Shorthand for --output.")
+	policy := fs.String("policy", "sync", "The policy of the controller, as set with its --policy flag: sync, create-only, create-update or create-delete.")
+	enablePolicyOverride := fs.Bool("enable-policy-override", false, "Whether the controller allows the ApplicationSets to override its policy, as set with its --enable-policy-override flag.")
+	trackingMethod := fs.String("tracking-method", string(utils.TrackingMethodOwnerReference), "The tracking method of the controller, as set with its --tracking-method flag: owner-reference or annotation.")
+	serverSideApply := fs.Bool("server-side-apply", false, "Whether the controller applies the Applications with server-side apply, as set with its --server-side-apply flag.")
+	logLevel := fs.String("loglevel", "warn", "The level of the logs of the generators, written to the standard error. One of: debug|info|warn|error")
+	if err := parseFlags(fs, args, logLevel); err != nil {
+		return err
+	}
+	if (len(files) == 0) == (fs.NArg() == 0) || fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "either the name of an ApplicationSet or --filename must be set\n")
+		fs.Usage()
+		return errUsage
+	}
+	if output != OutputText && output != OutputJSON {
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputText, OutputJSON)
+	}
+	policyObj, ok := utils.Policies[*policy]
+	if !ok {
+		return fmt.Errorf("unknown policy %q, the policies are sync, create-only, create-update and create-delete", *policy)
+	}
+	trackingMethodObj, ok := utils.TrackingMethods[*trackingMethod]
+	if !ok {
+		return fmt.Errorf("unknown tracking method %q, the tracking methods are owner-reference and annotation", *trackingMethod)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	live, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	appSets, err := live.getApplicationSets(ctx, fs.Arg(0), files)
+	if err != nil {
+		return err
+	}
+
+	r := &controllers.ApplicationSetReconciler{
+		Client:               live.client,
+		Scheme:               live.scheme,
+		Generators:           live.generators,
+		Renderer:             live.renderer,
+		ArgoDB:               live.argoDB,
+		ArgoAppClientset:     live.appClientset,
+		KubeClientset:        live.kubeClientset,
+		SensitiveValues:      live.sensitiveValues,
+		Policy:               policyObj,
+		EnablePolicyOverride: *enablePolicyOverride,
+		TrackingMethod:       trackingMethodObj,
+		ServerSideApply:      *serverSideApply,
+	}
+
+	// The changes which were planned are printed even if others failed, and the command fails
+	var plans []applicationSetChanges
+	var errs []string
+	for _, appSet := range appSets {
+		changes, err := r.PlanApplications(ctx, appSet)
+		plans = append(plans, applicationSetChanges{namespace: appSet.Namespace, name: appSet.Name, changes: changes})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ApplicationSet %s/%s: %v", appSet.Namespace, appSet.Name, err))
+		}
+	}
+	if err := printChanges(stdout, output, plans, live.sensitiveValues.Redact); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errors.New(live.sensitiveValues.Redact(strings.Join(errs, "; ")))
+	}
+	return nil
+}
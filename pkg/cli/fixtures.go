@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	pullrequest "github.com/argoproj-labs/applicationset/pkg/services/pull_request"
+	"github.com/argoproj-labs/applicationset/pkg/services/scm_provider"
+)
+
+// fixtureCluster is a cluster of the clusters fixture, which the Cluster generator lists like the clusters registered
+// in Argo CD.
+type fixtureCluster struct {
+	Name        string            `json:"name"`
+	Server      string            `json:"server"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// readClusters reads the clusters fixture, a YAML list of clusters, and returns the Argo CD cluster Secrets of the
+// clusters in the namespace.
+func readClusters(path string, namespace string) ([]*corev1.Secret, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []fixtureCluster
+	if err := yaml.UnmarshalStrict(data, &clusters); err != nil {
+		return nil, fmt.Errorf("error parsing the clusters of %s: %v", path, err)
+	}
+
+	secrets := make([]*corev1.Secret, 0, len(clusters))
+	names := map[string]bool{}
+	for i, cluster := range clusters {
+		if cluster.Name == "" || cluster.Server == "" {
+			return nil, fmt.Errorf("the cluster %d of %s must have a name and a server", i, path)
+		}
+		if names[cluster.Name] {
+			return nil, fmt.Errorf("the cluster %s of %s is defined more than once", cluster.Name, path)
+		}
+		names[cluster.Name] = true
+
+		labels := map[string]string{generators.ArgoCDSecretTypeLabel: generators.ArgoCDSecretTypeCluster}
+		for k, v := range cluster.Labels {
+			labels[k] = v
+		}
+		secrets = append(secrets, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("cluster-%d", i),
+				Namespace:   namespace,
+				Labels:      labels,
+				Annotations: cluster.Annotations,
+			},
+			Data: map[string][]byte{
+				"name":   []byte(cluster.Name),
+				"server": []byte(cluster.Server),
+				"config": []byte("{}"),
+			},
+		})
+	}
+	return secrets, nil
+}
+
+// scmFixtures are the responses of the SCM providers and of the pull request providers, which the SCM Provider and
+// Pull Request generators list whatever their provider.
+type scmFixtures struct {
+	Repositories []fixtureRepository  `json:"repositories,omitempty"`
+	PullRequests []fixturePullRequest `json:"pullRequests,omitempty"`
+}
+
+type fixtureRepository struct {
+	Organization string   `json:"organization"`
+	Repository   string   `json:"repository"`
+	URL          string   `json:"url"`
+	Branch       string   `json:"branch"`
+	SHA          string   `json:"sha,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	// Paths are the files and directories of the repository, matched by the pathsExist filters.
+	Paths []string `json:"paths,omitempty"`
+}
+
+type fixturePullRequest struct {
+	Number       int       `json:"number"`
+	Branch       string    `json:"branch"`
+	TargetBranch string    `json:"targetBranch,omitempty"`
+	HeadSHA      string    `json:"headSHA"`
+	Labels       []string  `json:"labels,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	CreatedAt    time.Time `json:"createdAt,omitempty"`
+}
+
+// readSCMFixtures reads the SCM fixtures file.
+func readSCMFixtures(path string) (*scmFixtures, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := &scmFixtures{}
+	if err := yaml.UnmarshalStrict(data, fixtures); err != nil {
+		return nil, fmt.Errorf("error parsing the SCM fixtures of %s: %v", path, err)
+	}
+	return fixtures, nil
+}
+
+// fixtureSCMProvider is an SCM provider listing the repositories of the fixtures.
+type fixtureSCMProvider struct {
+	repositories []fixtureRepository
+}
+
+var _ scm_provider.SCMProviderService = (*fixtureSCMProvider)(nil)
+
+func (p *fixtureSCMProvider) ListRepos(_ context.Context, _ string) ([]*scm_provider.Repository, error) {
+	repos := make([]*scm_provider.Repository, 0, len(p.repositories))
+	for _, repo := range p.repositories {
+		repos = append(repos, &scm_provider.Repository{
+			Organization: repo.Organization,
+			Repository:   repo.Repository,
+			URL:          repo.URL,
+			Branch:       repo.Branch,
+			SHA:          repo.SHA,
+			Labels:       repo.Labels,
+		})
+	}
+	return repos, nil
+}
+
+// RepoHasPath returns true if a path of the fixture of the repository is the path, or is under the path.
+func (p *fixtureSCMProvider) RepoHasPath(_ context.Context, repo *scm_provider.Repository, path string) (bool, error) {
+	path = strings.Trim(path, "/")
+	for _, fixture := range p.repositories {
+		if fixture.Organization != repo.Organization || fixture.Repository != repo.Repository || fixture.Branch != repo.Branch {
+			continue
+		}
+		for _, fixturePath := range fixture.Paths {
+			fixturePath = strings.Trim(fixturePath, "/")
+			if fixturePath == path || strings.HasPrefix(fixturePath, path+"/") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// fixturePullRequestService is a pull request provider listing the pull requests of the fixtures.
+type fixturePullRequestService struct {
+	pullRequests []fixturePullRequest
+}
+
+var _ pullrequest.PullRequestService = (*fixturePullRequestService)(nil)
+
+func (s *fixturePullRequestService) List(_ context.Context) ([]*pullrequest.PullRequest, error) {
+	pulls := make([]*pullrequest.PullRequest, 0, len(s.pullRequests))
+	for _, pull := range s.pullRequests {
+		pulls = append(pulls, &pullrequest.PullRequest{
+			Number:       pull.Number,
+			Branch:       pull.Branch,
+			HeadSHA:      pull.HeadSHA,
+			TargetBranch: pull.TargetBranch,
+			Labels:       pull.Labels,
+			Author:       pull.Author,
+			Title:        pull.Title,
+			CreatedAt:    pull.CreatedAt,
+		})
+	}
+	return pulls, nil
+}
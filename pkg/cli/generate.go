@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func runGenerate(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, "[flags] FILE...", stderr)
+	var opts offlineOptions
+	opts.addFlags(fs)
+	var output string
+	fs.StringVar(&output, "output", OutputYAML, "The output format of the Applications: yaml or json.")
+	fs.StringVar(&output, "o", OutputYAML, "Shorthand for --output.")
+	logLevel := fs.String("loglevel", "warn", "The level of the logs of the generators, written to the standard error. One of: debug|info|warn|error")
+	if err := parseFlags(fs, args, logLevel); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(stderr, "no ApplicationSet file\n")
+		fs.Usage()
+		return errUsage
+	}
+	if output != OutputYAML && output != OutputJSON {
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputYAML, OutputJSON)
+	}
+
+	appSets, err := readApplicationSets(fs.Args(), opts.namespace)
+	if err != nil {
+		return err
+	}
+	topLevelGenerators, err := opts.generators(ctx)
+	if err != nil {
+		return err
+	}
+	r := &controllers.ApplicationSetReconciler{
+		Generators: topLevelGenerators,
+		Renderer:   &utils.Render{},
+	}
+
+	// The Applications which were rendered are printed even if others failed, and the command fails
+	var objects []interface{}
+	var errs []string
+	for _, appSet := range appSets {
+		apps, err := r.RenderApplications(ctx, appSet)
+		for i := range apps {
+			objects = append(objects, &apps[i])
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ApplicationSet %s/%s: %v", appSet.Namespace, appSet.Name, err))
+		}
+	}
+	if err := printObjects(stdout, output, objects); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
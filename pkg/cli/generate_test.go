@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const guestbookApplicationSet = `
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: guestbook
+spec:
+  generators:
+  - list:
+      elements:
+      - cluster: staging
+        url: https://staging.example.com
+  template:
+    metadata:
+      name: '{{cluster}}-guestbook'
+    spec:
+      project: default
+      source:
+        repoURL: https://github.com/argoproj/argocd-example-apps.git
+        targetRevision: HEAD
+        path: guestbook
+      destination:
+        server: '{{url}}'
+        namespace: guestbook
+`
+
+const httpApplicationSet = `
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: http
+  namespace: apps
+spec:
+  generators:
+  - http:
+      url: https://example.com/apps
+  template:
+    metadata:
+      name: '{{name}}'
+    spec:
+      project: default
+      source:
+        repoURL: https://github.com/argoproj/argocd-example-apps.git
+        path: '{{name}}'
+      destination:
+        server: https://kubernetes.default.svc
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	guestbook := writeFile(t, dir, "guestbook.yaml", guestbookApplicationSet)
+
+	var stdout, stderr bytes.Buffer
+	code := Main(context.Background(), "appset", []string{"generate", guestbook}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "kind: Application\n")
+	assert.Contains(t, stdout.String(), "  name: staging-guestbook\n")
+	assert.Contains(t, stdout.String(), "  namespace: argocd\n")
+	assert.Contains(t, stdout.String(), "server: https://staging.example.com\n")
+
+	stdout.Reset()
+	code = Main(context.Background(), "appset", []string{"generate", "-o", "json", "--namespace", "apps", guestbook}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"name": "staging-guestbook"`)
+	assert.Contains(t, stdout.String(), `"namespace": "apps"`)
+
+	// the Applications which were rendered are printed, and the errors of the other ApplicationSets are reported
+	stdout.Reset()
+	stderr.Reset()
+	code = Main(context.Background(), "appset", []string{"generate", guestbook, writeFile(t, dir, "http.yaml", httpApplicationSet)}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout.String(), "  name: staging-guestbook\n")
+	assert.Contains(t, stderr.String(), "error: ApplicationSet apps/http: the http generator is not supported offline")
+
+	stderr.Reset()
+	assert.Equal(t, 2, Main(context.Background(), "appset", []string{"generate"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no ApplicationSet file")
+}
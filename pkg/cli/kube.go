@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// kubeOptions select the cluster of the commands which read the ApplicationSets and Applications of a cluster, from
+// the kubeconfig files, like kubectl.
+type kubeOptions struct {
+	// kubeconfig is the path of the kubeconfig file, read from the KUBECONFIG environment variable or from
+	// ~/.kube/config if empty.
+	kubeconfig string
+	// context is the context of the kubeconfig, the current context if empty.
+	context string
+	// namespace is the Argo CD namespace, the namespace of the context if empty.
+	namespace string
+}
+
+func (o *kubeOptions) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "The path of the kubeconfig file. Read from the KUBECONFIG environment variable, or from ~/.kube/config, if empty.")
+	fs.StringVar(&o.context, "context", "", "The context of the kubeconfig. The current context if empty.")
+	fs.StringVar(&o.namespace, "namespace", "", "The Argo CD namespace, which is the namespace of the ApplicationSets and of the Applications. The namespace of the context if empty, or argocd if the context has no namespace.")
+	fs.StringVar(&o.namespace, "n", "", "Shorthand for --namespace.")
+}
+
+// config returns the client config of the cluster, and the Argo CD namespace.
+func (o *kubeOptions) config() (*rest.Config, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("error loading the kubeconfig: %v", err)
+	}
+
+	namespace := o.namespace
+	if namespace == "" {
+		// The namespace of the client config is "default" when the context has none, rather than the Argo CD default
+		raw, err := clientConfig.RawConfig()
+		if err != nil {
+			return nil, "", fmt.Errorf("error loading the kubeconfig: %v", err)
+		}
+		contextName := o.context
+		if contextName == "" {
+			contextName = raw.CurrentContext
+		}
+		if context, ok := raw.Contexts[contextName]; ok {
+			namespace = context.Namespace
+		}
+	}
+	if namespace == "" {
+		namespace = "argocd"
+	}
+	return config, namespace, nil
+}
+
+// client returns a client of the ApplicationSets of the cluster, without a cache, and the Argo CD namespace.
+func (o *kubeOptions) client() (client.Client, string, error) {
+	config, namespace, err := o.config()
+	if err != nil {
+		return nil, "", err
+	}
+	scheme := runtime.NewScheme()
+	_ = argoprojiov1alpha1.AddToScheme(scheme)
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("error connecting to the cluster: %v", err)
+	}
+	return c, namespace, nil
+}
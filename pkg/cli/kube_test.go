@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+- name: production
+  cluster:
+    server: https://production.example.com
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+- name: production
+  context:
+    cluster: production
+    user: admin
+    namespace: gitops
+current-context: staging
+`
+
+func TestKubeOptionsConfig(t *testing.T) {
+	kubeconfig := writeFile(t, t.TempDir(), "config", testKubeconfig)
+
+	// the Argo CD namespace defaults to argocd when the context has no namespace
+	config, namespace, err := (&kubeOptions{kubeconfig: kubeconfig}).config()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", config.Host)
+	assert.Equal(t, "argocd", namespace)
+
+	config, namespace, err = (&kubeOptions{kubeconfig: kubeconfig, context: "production"}).config()
+	require.NoError(t, err)
+	assert.Equal(t, "https://production.example.com", config.Host)
+	assert.Equal(t, "gitops", namespace)
+
+	_, namespace, err = (&kubeOptions{kubeconfig: kubeconfig, context: "production", namespace: "apps"}).config()
+	require.NoError(t, err)
+	assert.Equal(t, "apps", namespace)
+
+	_, _, err = (&kubeOptions{kubeconfig: kubeconfig, context: "unknown"}).config()
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argoprojiov1beta1 "github.com/argoproj-labs/applicationset/api/v1beta1"
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+	"github.com/argoproj-labs/applicationset/pkg/validation"
+)
+
+func runLint(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, "[flags] FILE...", stderr)
+	var opts offlineOptions
+	opts.addFlags(fs)
+	allowedProjects := fs.String("allowed-projects", "", "The projects allowed by the controller, as set with its --allowed-projects flag.")
+	allowedDestinations := fs.String("allowed-destinations", "", "The destinations allowed by the controller, as set with its --allowed-destinations flag.")
+	enabledGenerators := fs.String("enable-generators", "", "The generators enabled in the controller, as set with its --enable-generators flag.")
+	disabledGenerators := fs.String("disable-generators", "", "The generators disabled in the controller, as set with its --disable-generators flag.")
+	forbidTemplatedProjects := fs.Bool("forbid-templated-projects", false, "Whether the controller forbids the projects rendered from params, as set with its --forbid-templated-projects flag.")
+	logLevel := fs.String("loglevel", "warn", "The level of the logs of the generators, written to the standard error. One of: debug|info|warn|error")
+	if err := parseFlags(fs, args, logLevel); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(stderr, "no ApplicationSet file\n")
+		fs.Usage()
+		return errUsage
+	}
+
+	allowedDestinationsObj, err := utils.ParseDestinationAllowList(*allowedDestinations)
+	if err != nil {
+		return fmt.Errorf("invalid --allowed-destinations: %v", err)
+	}
+	disabledGeneratorsObj, err := utils.ParseDisabledGenerators(*enabledGenerators, *disabledGenerators)
+	if err != nil {
+		return fmt.Errorf("invalid --enable-generators or --disable-generators: %v", err)
+	}
+	validator := validation.NewApplicationSetValidator(validation.Options{
+		AllowedProjects:         utils.ParseProjectAllowList(*allowedProjects),
+		AllowedDestinations:     allowedDestinationsObj,
+		DisabledGenerators:      disabledGeneratorsObj,
+		ForbidTemplatedProjects: *forbidTemplatedProjects,
+	})
+
+	docs, err := readApplicationSetDocuments(fs.Args(), opts.namespace)
+	if err != nil {
+		return err
+	}
+	topLevelGenerators, err := opts.generators(ctx)
+	if err != nil {
+		return err
+	}
+	r := &controllers.ApplicationSetReconciler{
+		Generators: topLevelGenerators,
+		Renderer:   &utils.Render{},
+	}
+
+	errorCount := 0
+	for _, doc := range docs {
+		errs, warnings := lintApplicationSet(ctx, r, validator, doc)
+		prefix := fmt.Sprintf("%s: ApplicationSet %s/%s", doc.path, doc.applicationSet.Namespace, doc.applicationSet.Name)
+		for _, err := range errs {
+			fmt.Fprintf(stdout, "%s: error: %s\n", prefix, err)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(stdout, "%s: warning: %s\n", prefix, warning)
+		}
+		errorCount += len(errs)
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%d errors found in the ApplicationSets", errorCount)
+	}
+	return nil
+}
+
+// lintApplicationSet returns the errors and the warnings of an ApplicationSet: the errors of its schema, the errors
+// which the validating admission webhook would reject it for, and the names of its Applications, rendered offline,
+// which Argo CD would reject. The Applications which can't be rendered offline, e.g. as their generators need the
+// fixtures which aren't set, are reported as a warning, since their names aren't checked.
+func lintApplicationSet(ctx context.Context, r *controllers.ApplicationSetReconciler, validator *validation.ApplicationSetValidator, doc applicationSetDocument) ([]string, []string) {
+	var errs, warnings []string
+	errs = append(errs, schemaErrors(doc)...)
+	for _, err := range validator.Validate(&doc.applicationSet) {
+		errs = append(errs, err.Error())
+	}
+
+	apps, err := r.RenderApplications(ctx, doc.applicationSet)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("the names of the Applications which couldn't be rendered offline aren't checked: %v", err))
+	}
+	errs = append(errs, validateApplicationNames(apps)...)
+	return errs, warnings
+}
+
+// schemaErrors returns the errors of an ApplicationSet against the schema of its CRD: its fields which aren't in the
+// schema, which the API server would drop, and its values outside of the enums and the ranges of the schema.
+func schemaErrors(doc applicationSetDocument) []string {
+	var errs []string
+	var typeMeta metav1.TypeMeta
+	_ = json.Unmarshal(doc.raw, &typeMeta)
+	var obj interface{} = &argoprojiov1alpha1.ApplicationSet{}
+	v1beta1AppSet := &argoprojiov1beta1.ApplicationSet{}
+	if typeMeta.APIVersion == argoprojiov1beta1.GroupVersion.String() {
+		obj = v1beta1AppSet
+	}
+	decoder := json.NewDecoder(bytes.NewReader(doc.raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		errs = append(errs, strings.TrimPrefix(err.Error(), "json: "))
+	}
+
+	var fieldErrs field.ErrorList
+	switch v1beta1AppSet.Spec.TemplateEngine {
+	case "", argoprojiov1beta1.TemplateEngineGo, argoprojiov1beta1.TemplateEngineLegacy:
+	default:
+		fieldErrs = append(fieldErrs, field.NotSupported(field.NewPath("spec", "templateEngine"), v1beta1AppSet.Spec.TemplateEngine,
+			[]string{string(argoprojiov1beta1.TemplateEngineGo), string(argoprojiov1beta1.TemplateEngineLegacy)}))
+	}
+	appSet := &doc.applicationSet
+	if appSet.Name == "" {
+		fieldErrs = append(fieldErrs, field.Required(field.NewPath("metadata", "name"), "the name of the ApplicationSet is required"))
+	}
+	if strategy := appSet.Spec.Strategy; strategy != nil {
+		switch strategy.Type {
+		case "", argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce, argoprojiov1alpha1.ApplicationSetStrategyTypeRollingSync:
+		default:
+			fieldErrs = append(fieldErrs, field.NotSupported(field.NewPath("spec", "strategy", "type"), strategy.Type,
+				[]string{string(argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce), string(argoprojiov1alpha1.ApplicationSetStrategyTypeRollingSync)}))
+		}
+	}
+	if syncPolicy := appSet.Spec.SyncPolicy; syncPolicy != nil {
+		path := field.NewPath("spec", "syncPolicy")
+		switch syncPolicy.ApplicationsSync {
+		case "", argoprojiov1alpha1.ApplicationsSyncPolicyCreateOnly, argoprojiov1alpha1.ApplicationsSyncPolicyCreateUpdate,
+			argoprojiov1alpha1.ApplicationsSyncPolicyCreateDelete, argoprojiov1alpha1.ApplicationsSyncPolicySync:
+		default:
+			fieldErrs = append(fieldErrs, field.NotSupported(path.Child("applicationsSync"), syncPolicy.ApplicationsSync, []string{
+				string(argoprojiov1alpha1.ApplicationsSyncPolicyCreateOnly), string(argoprojiov1alpha1.ApplicationsSyncPolicyCreateUpdate),
+				string(argoprojiov1alpha1.ApplicationsSyncPolicyCreateDelete), string(argoprojiov1alpha1.ApplicationsSyncPolicySync),
+			}))
+		}
+		if p := syncPolicy.MaxDeletionPercentage; p != nil && (*p < 0 || *p > 100) {
+			fieldErrs = append(fieldErrs, field.Invalid(path.Child("maxDeletionPercentage"), *p, "must be between 0 and 100"))
+		}
+		if batches := syncPolicy.DeletionBatches; batches != nil && batches.Size < 1 {
+			fieldErrs = append(fieldErrs, field.Invalid(path.Child("deletionBatches", "size"), batches.Size, "must be greater than or equal to 1"))
+		}
+	}
+	for _, err := range fieldErrs {
+		errs = append(errs, err.Error())
+	}
+	return errs
+}
+
+// validateApplicationNames returns the errors of the names of the Applications, which must be DNS subdomains, and which
+// Argo CD sets as the value of the app.kubernetes.io/instance label of their resources, by default, so they must not be
+// longer than the values of the labels.
+func validateApplicationNames(apps []argov1alpha1.Application) []string {
+	var errs []string
+	for i := range apps {
+		name := apps[i].Name
+		for _, msg := range k8svalidation.IsDNS1123Subdomain(name) {
+			errs = append(errs, fmt.Sprintf("the name of the Application %q is invalid: %s", name, msg))
+		}
+		if len(name) > k8svalidation.LabelValueMaxLength {
+			errs = append(errs, fmt.Sprintf("the name of the Application %q is longer than %d characters, the maximum length of the app.kubernetes.io/instance label of its resources", name, k8svalidation.LabelValueMaxLength))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const invalidApplicationSet = `
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: invalid
+spec:
+  generators:
+  - merge:
+      generators:
+      - list:
+          elements:
+          - cluster: staging
+      - list:
+          elements:
+          - cluster: staging
+            env: staging
+  - matrix:
+      generators:
+      - list:
+          elements:
+          - cluster: staging
+  - list:
+      elements:
+      - cluster: production
+        env: production
+  syncPolicy:
+    applicationsSync: create
+  template:
+    metadata:
+      name: '{{cluster}}-guestbook-with-a-name-longer-than-the-values-of-the-labels-{{env}}'
+    spec:
+      project: default
+      source:
+        repoURL: https://github.com/argoproj/argocd-example-apps.git
+        path: guestbook
+      destination:
+        server: https://kubernetes.default.svc
+        namespace: guestbook
+        cluster: staging
+`
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	code := Main(context.Background(), "appset", []string{"lint", writeFile(t, dir, "guestbook.yaml", guestbookApplicationSet)}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Empty(t, stdout.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	invalid := writeFile(t, dir, "invalid.yaml", invalidApplicationSet)
+	code = Main(context.Background(), "appset", []string{"lint", invalid}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	prefix := invalid + ": ApplicationSet argocd/invalid: error: "
+	assert.Contains(t, stdout.String(), prefix+`unknown field "cluster"`)
+	assert.Contains(t, stdout.String(), prefix+`spec.syncPolicy.applicationsSync: Unsupported value: "create"`)
+	assert.Contains(t, stdout.String(), prefix+"spec.generators[0].merge.mergeKeys: Required value: at least one merge key is required")
+	assert.Contains(t, stdout.String(), prefix+"spec.generators[1].matrix.generators: Invalid value: 1: a Matrix generator requires exactly two generators")
+	assert.Contains(t, stdout.String(), prefix+"spec.template: Invalid value: \"{{env}}\": the param 'env' is not generated by spec.generators[1] (matrix)")
+	assert.Contains(t, stdout.String(), prefix+`the name of the Application "production-guestbook-with-a-name-longer-than-the-values-of-the-labels-production" is longer than 63 characters`)
+	assert.Contains(t, stdout.String(), ": ApplicationSet argocd/invalid: warning: the names of the Applications which couldn't be rendered offline aren't checked")
+	assert.Contains(t, stderr.String(), "error: 6 errors found in the ApplicationSets")
+}
+
+func TestLintV1beta1(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	v1beta1 := strings.Replace(guestbookApplicationSet, "argoproj.io/v1alpha1", "argoproj.io/v1beta1", 1)
+	v1beta1 = strings.Replace(v1beta1, "spec:\n  generators:", "spec:\n  templateEngine: Legacy\n  generators:", 1)
+	code := Main(context.Background(), "appset", []string{"lint", writeFile(t, dir, "guestbook.yaml", v1beta1)}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Empty(t, stdout.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	invalid := strings.Replace(v1beta1, "templateEngine: Legacy", "goTemplate: true\n  templateEngine: Sprig", 1)
+	code = Main(context.Background(), "appset", []string{"lint", writeFile(t, dir, "invalid.yaml", invalid)}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout.String(), `error: unknown field "goTemplate"`)
+	assert.Contains(t, stdout.String(), `error: spec.templateEngine: Unsupported value: "Sprig"`)
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	argosettings "github.com/argoproj/argo-cd/v2/util/settings"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/controllers"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/services"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// liveOptions are the options of the controller which the commands reading the cluster run the generators with.
+type liveOptions struct {
+	kubeOptions
+	argocdRepoServer          string
+	repos                     stringSliceFlag
+	templatePartialsConfigMap string
+	enabledGenerators         string
+	disabledGenerators        string
+}
+
+func (o *liveOptions) addFlags(fs *flag.FlagSet) {
+	o.kubeOptions.addFlags(fs)
+	fs.StringVar(&o.argocdRepoServer, "argocd-repo-server", "argocd-repo-server:8081", "The address of the Argo CD repo server, which the Git generators read the repositories from, e.g. localhost:8081 with kubectl port-forward.")
+	fs.Var(&o.repos, "repo", "The local checkout of a repository of the Git generators, as URL=DIR, or DIR for all the repositories, read rather than the Argo CD repo server. May be repeated.")
+	fs.StringVar(&o.templatePartialsConfigMap, "template-partials-configmap", "", "The ConfigMap of the named templates of the controller, as set with its --template-partials-configmap flag.")
+	fs.StringVar(&o.enabledGenerators, "enable-generators", "", "The generators enabled in the controller, as set with its --enable-generators flag.")
+	fs.StringVar(&o.disabledGenerators, "disable-generators", "", "The generators disabled in the controller, as set with its --disable-generators flag.")
+}
+
+// liveCluster is the connection to the cluster of the commands reading the cluster.
+type liveCluster struct {
+	namespace string
+	scheme    *runtime.Scheme
+	// client reads the objects of the namespace from a cache, which indexes the Applications like the controller.
+	client        client.Client
+	kubeClientset kubernetes.Interface
+	appClientset  appclientset.Interface
+	argoDB        db.ArgoDB
+	generators    map[string]generators.Generator
+	renderer      *utils.Render
+	// sensitiveValues are the values generated from Secrets, to redact from the output.
+	sensitiveValues *utils.SensitiveValues
+}
+
+// connect connects to the cluster, and returns the connection once the cache of the client is started. The cache is
+// stopped when the context is done.
+func (o *liveOptions) connect(ctx context.Context) (*liveCluster, error) {
+	disabledGenerators, err := utils.ParseDisabledGenerators(o.enabledGenerators, o.disabledGenerators)
+	if err != nil {
+		return nil, err
+	}
+	config, namespace, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = argoprojiov1alpha1.AddToScheme(scheme)
+	_ = argov1alpha1.AddToScheme(scheme)
+
+	// Like the cache of the controller, the cache is restricted to the Argo CD namespace
+	c, err := cluster.New(config, func(options *cluster.Options) {
+		options.Scheme = scheme
+		options.Namespace = namespace
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the cluster: %v", err)
+	}
+	if err := controllers.IndexTrackedApplications(ctx, c.GetFieldIndexer()); err != nil {
+		return nil, err
+	}
+	go func() {
+		_ = c.Start(ctx)
+	}()
+	if !c.GetCache().WaitForCacheSync(ctx) {
+		return nil, errors.New("error starting the cache of the cluster")
+	}
+
+	k8s, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	appClientset, err := appclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	argoDB := db.NewDB(namespace, argosettings.NewSettingsManager(ctx, k8s, namespace), k8s)
+
+	var repos services.Repos = services.NewArgoCDService(argoDB, o.argocdRepoServer)
+	if len(o.repos) > 0 {
+		repos = newLocalRepos(o.repos)
+	}
+	sensitiveValues := utils.NewSensitiveValues()
+	renderer := &utils.Render{}
+	if o.templatePartialsConfigMap != "" {
+		renderer.Partials = utils.NewConfigMapTemplatePartials(ctx, c.GetClient(), namespace, o.templatePartialsConfigMap)
+	}
+
+	return &liveCluster{
+		namespace:     namespace,
+		scheme:        scheme,
+		client:        c.GetClient(),
+		kubeClientset: k8s,
+		appClientset:  appClientset,
+		argoDB:        argoDB,
+		generators: generators.NewStandardGenerators(ctx, generators.Clients{
+			Client:          c.GetClient(),
+			KubeClientset:   k8s,
+			DynamicClient:   dynClient,
+			RESTMapper:      c.GetRESTMapper(),
+			Repos:           repos,
+			Namespace:       namespace,
+			SensitiveValues: sensitiveValues,
+		}, disabledGenerators),
+		renderer:        renderer,
+		sensitiveValues: sensitiveValues,
+	}, nil
+}
+
+// getApplicationSets returns the ApplicationSet of the cluster with the name, or the ApplicationSets of the files,
+// with the UID of the ApplicationSets of the cluster with the same name, so that they track their Applications.
+func (c *liveCluster) getApplicationSets(ctx context.Context, name string, files []string) ([]argoprojiov1alpha1.ApplicationSet, error) {
+	if len(files) == 0 {
+		var appSet argoprojiov1alpha1.ApplicationSet
+		if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: name}, &appSet); err != nil {
+			return nil, fmt.Errorf("error getting ApplicationSet %s/%s: %v", c.namespace, name, err)
+		}
+		return []argoprojiov1alpha1.ApplicationSet{appSet}, nil
+	}
+
+	appSets, err := readApplicationSets(files, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range appSets {
+		if appSets[i].Namespace != c.namespace {
+			return nil, fmt.Errorf("ApplicationSet %s/%s is not in the Argo CD namespace %s", appSets[i].Namespace, appSets[i].Name, c.namespace)
+		}
+		var existing argoprojiov1alpha1.ApplicationSet
+		err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: appSets[i].Name}, &existing)
+		if err != nil && !apierr.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting ApplicationSet %s/%s: %v", c.namespace, appSets[i].Name, err)
+		}
+		appSets[i].UID = existing.UID
+	}
+	return appSets, nil
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/services"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// offlineOptions are the fixtures read by the generators run offline, in place of the clusters, the SCM providers and
+// the Git repositories.
+type offlineOptions struct {
+	// namespace is the Argo CD namespace, of the clusters and of the ApplicationSets without a namespace.
+	namespace string
+	// clustersFile is the YAML file of the clusters listed by the Cluster generators.
+	clustersFile string
+	// scmFixturesFile is the YAML file of the repositories and pull requests listed by the SCM Provider and Pull
+	// Request generators.
+	scmFixturesFile string
+	// repos are the local checkouts of the repositories read by the Git generators, as [URL=]DIR.
+	repos stringSliceFlag
+}
+
+func (o *offlineOptions) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.namespace, "namespace", "argocd", "The Argo CD namespace, which is the namespace of the ApplicationSets without a namespace.")
+	fs.StringVar(&o.clustersFile, "clusters", "", "A YAML file listing the clusters of the Cluster generators, as a list of name, server, labels and annotations. The in-cluster cluster is also listed, unless a cluster of the file has its server.")
+	fs.StringVar(&o.scmFixturesFile, "scm-fixtures", "", "A YAML file of the repositories listed by the SCM Provider generators, and of the pull requests listed by the Pull Request generators, whatever their provider.")
+	fs.Var(&o.repos, "repo", "The local checkout of a repository of the Git generators, as URL=DIR, or DIR for all the repositories. May be repeated. The files are read from the working tree, whatever the revision.")
+}
+
+// offlineGenerators are the generators which can run offline, from the fixtures.
+var offlineGenerators = map[string]bool{
+	"List":        true,
+	"Clusters":    true,
+	"Git":         true,
+	"SCMProvider": true,
+	"PullRequest": true,
+	"Matrix":      true,
+	"Merge":       true,
+	"Union":       true,
+}
+
+// generators returns the top-level generators reading the fixtures. The generators which need the fixtures which are
+// not set, and the generators which can't run offline, fail.
+func (o *offlineOptions) generators(ctx context.Context) (map[string]generators.Generator, error) {
+	var objects []client.Object
+	var runtimeObjects []runtime.Object
+	if o.clustersFile != "" {
+		secrets, err := readClusters(o.clustersFile, o.namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			objects = append(objects, secret)
+			runtimeObjects = append(runtimeObjects, secret)
+		}
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	clientset := kubefake.NewSimpleClientset(runtimeObjects...)
+
+	disabled := map[string]bool{}
+	for name := range utils.GeneratorJSONNames() {
+		if !offlineGenerators[name] {
+			disabled[name] = true
+		}
+	}
+
+	var repos services.Repos
+	if len(o.repos) > 0 {
+		repos = newLocalRepos(o.repos)
+	}
+
+	scmProvider := generators.NewSCMProviderGenerator(kubeClient)
+	pullRequest := generators.NewPullRequestGenerator(kubeClient)
+	if o.scmFixturesFile != "" {
+		fixtures, err := readSCMFixtures(o.scmFixturesFile)
+		if err != nil {
+			return nil, err
+		}
+		scmProvider = generators.NewFixedSCMProviderGenerator(&fixtureSCMProvider{repositories: fixtures.Repositories})
+		pullRequest = generators.NewFixedPullRequestGenerator(&fixturePullRequestService{pullRequests: fixtures.PullRequests})
+	}
+
+	// The generators which can't run offline only provide their templates, so they have no clients
+	terminalGenerators := map[string]generators.Generator{
+		"List":                    generators.NewListGenerator(),
+		"Clusters":                generators.NewClusterGenerator(kubeClient, ctx, clientset, o.namespace),
+		"Git":                     generators.NewGitGenerator(repos),
+		"SCMProvider":             scmProvider,
+		"ClusterDecisionResource": generators.NewDuckTypeGenerator(ctx, nil, clientset, o.namespace),
+		"PullRequest":             pullRequest,
+		"Plugin":                  generators.NewPluginGenerator(nil, o.namespace),
+		"HTTP":                    generators.NewHTTPGenerator(nil),
+		"Secret":                  generators.NewSecretGenerator(nil, nil),
+		"KubernetesResource":      generators.NewKubernetesResourceGenerator(nil, nil),
+		"AzureSubscriptions":      generators.NewAzureSubscriptionsGenerator(nil),
+		"Vault":                   generators.NewVaultGenerator(nil, nil),
+		"Consul":                  generators.NewConsulGenerator(nil),
+		"HelmRepository":          generators.NewHelmRepositoryGenerator(nil),
+		"Bucket":                  generators.NewBucketGenerator(nil),
+		"SQL":                     generators.NewSQLGenerator(nil),
+		"TerraformState":          generators.NewTerraformStateGenerator(nil, nil),
+	}
+	generators.DisableGeneratorsWithReason(terminalGenerators, disabled, "not supported offline")
+	if repos == nil {
+		generators.DisableGeneratorsWithReason(terminalGenerators, map[string]bool{"Git": true}, "not supported offline without a local checkout of the repository, set with --repo")
+	}
+	if o.scmFixturesFile == "" {
+		generators.DisableGeneratorsWithReason(terminalGenerators, map[string]bool{"SCMProvider": true, "PullRequest": true}, "not supported offline without the fixtures of the providers, set with --scm-fixtures")
+	}
+
+	registry := generators.NewRegistry()
+	for name, g := range terminalGenerators {
+		if err := registry.Register(name, g); err != nil {
+			return nil, err
+		}
+	}
+	return registry.Generators(nil), nil
+}
+
+// newLocalRepos returns the local checkouts of the repositories, as [URL=]DIR.
+func newLocalRepos(repos []string) services.Repos {
+	dirs := map[string]string{}
+	defaultDir := ""
+	for _, repo := range repos {
+		if i := strings.LastIndex(repo, "="); i >= 0 {
+			dirs[repo[:i]] = repo[i+1:]
+		} else {
+			defaultDir = repo
+		}
+	}
+	return services.NewLocalRepos(dirs, defaultDir)
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// generateParams returns the param sets of the generator, run offline.
+func generateParams(t *testing.T, opts offlineOptions, generator argoprojiov1alpha1.ApplicationSetGenerator) ([]map[string]interface{}, error) {
+	topLevelGenerators, err := opts.generators(context.Background())
+	require.NoError(t, err)
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "appset", Namespace: "argocd"}}
+	results, err := generators.Transform(context.Background(), generator, topLevelGenerators, argoprojiov1alpha1.ApplicationSetTemplate{}, appSet)
+	var params []map[string]interface{}
+	for _, result := range results {
+		params = append(params, result.Params...)
+	}
+	return params, err
+}
+
+func TestOfflineClusters(t *testing.T) {
+	dir := t.TempDir()
+	clusters := writeFile(t, dir, "clusters.yaml", `
+- name: staging
+  server: https://staging.example.com
+  labels:
+    env: staging
+- name: production
+  server: https://production.example.com
+  labels:
+    env: production
+`)
+	opts := offlineOptions{namespace: "argocd", clustersFile: clusters}
+
+	params, err := generateParams(t, opts, argoprojiov1alpha1.ApplicationSetGenerator{
+		Clusters: &argoprojiov1alpha1.ClusterGenerator{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 1)
+	assert.Equal(t, "staging", params[0]["name"])
+	assert.Equal(t, "https://staging.example.com", params[0]["server"])
+	assert.Equal(t, "staging", params[0]["metadata.labels.env"])
+
+	// the in-cluster cluster is listed without a selector, like in Argo CD
+	params, err = generateParams(t, opts, argoprojiov1alpha1.ApplicationSetGenerator{
+		Clusters: &argoprojiov1alpha1.ClusterGenerator{},
+	})
+	require.NoError(t, err)
+	servers := []interface{}{}
+	for _, p := range params {
+		servers = append(servers, p["server"])
+	}
+	assert.ElementsMatch(t, []interface{}{"https://staging.example.com", "https://production.example.com", "https://kubernetes.default.svc"}, servers)
+
+	_, err = readClusters(writeFile(t, dir, "invalid.yaml", "- name: staging\n"), "argocd")
+	assert.EqualError(t, err, "the cluster 0 of "+filepath.Join(dir, "invalid.yaml")+" must have a name and a server")
+}
+
+func TestOfflineGit(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "apps/guestbook/kustomization.yaml", "")
+	writeFile(t, dir, "apps/helm-guestbook/Chart.yaml", "")
+	generator := argoprojiov1alpha1.ApplicationSetGenerator{
+		Git: &argoprojiov1alpha1.GitGenerator{
+			RepoURL:     "https://github.com/argoproj/argocd-example-apps.git",
+			Revision:    "HEAD",
+			Directories: []argoprojiov1alpha1.GitDirectoryGeneratorItem{{Path: "apps/*"}},
+		},
+	}
+
+	params, err := generateParams(t, offlineOptions{repos: stringSliceFlag{"https://github.com/argoproj/argocd-example-apps=" + dir}}, generator)
+	require.NoError(t, err)
+	basenames := []interface{}{}
+	for _, p := range params {
+		basenames = append(basenames, p["path.basename"])
+	}
+	assert.ElementsMatch(t, []interface{}{"guestbook", "helm-guestbook"}, basenames)
+
+	_, err = generateParams(t, offlineOptions{}, generator)
+	assert.EqualError(t, err, "the git generator is not supported offline without a local checkout of the repository, set with --repo")
+}
+
+func TestOfflineSCMFixtures(t *testing.T) {
+	fixtures := writeFile(t, t.TempDir(), "scm.yaml", `
+repositories:
+- organization: argoproj
+  repository: argo-cd
+  url: https://github.com/argoproj/argo-cd.git
+  branch: master
+  paths: [manifests/install.yaml]
+- organization: argoproj
+  repository: applicationset
+  url: https://github.com/argoproj-labs/applicationset.git
+  branch: master
+pullRequests:
+- number: 42
+  branch: feature
+  targetBranch: main
+  headSHA: 0123456789abcdef
+  title: Add a feature
+  createdAt: 2021-11-12T14:28:01Z
+`)
+	opts := offlineOptions{scmFixturesFile: fixtures}
+
+	params, err := generateParams(t, opts, argoprojiov1alpha1.ApplicationSetGenerator{
+		SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{
+			Github:  &argoprojiov1alpha1.SCMProviderGeneratorGithub{Organization: "argoproj"},
+			Filters: []argoprojiov1alpha1.SCMProviderGeneratorFilter{{PathsExist: []string{"manifests"}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 1)
+	assert.Equal(t, "argo-cd", params[0]["repository"])
+
+	params, err = generateParams(t, opts, argoprojiov1alpha1.ApplicationSetGenerator{
+		PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+			Github: &argoprojiov1alpha1.PullRequestGeneratorGithub{Owner: "argoproj", Repo: "argo-cd"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 1)
+	assert.Equal(t, "42", params[0]["number"])
+	assert.Equal(t, "0123456", params[0]["head_short_sha"])
+	assert.Equal(t, "2021-11-12T14:28:01Z", params[0]["created_at"])
+
+	_, err = generateParams(t, offlineOptions{}, argoprojiov1alpha1.ApplicationSetGenerator{
+		PullRequest: &argoprojiov1alpha1.PullRequestGenerator{},
+	})
+	assert.EqualError(t, err, "the pullRequest generator is not supported offline without the fixtures of the providers, set with --scm-fixtures")
+}
+
+func TestOfflineUnsupportedGenerators(t *testing.T) {
+	_, err := generateParams(t, offlineOptions{}, argoprojiov1alpha1.ApplicationSetGenerator{
+		HTTP: &argoprojiov1alpha1.HTTPGenerator{URL: "https://example.com"},
+	})
+	assert.EqualError(t, err, "the http generator is not supported offline")
+
+	// the child generators of the Matrix generators also run offline
+	params, err := generateParams(t, offlineOptions{}, argoprojiov1alpha1.ApplicationSetGenerator{
+		Matrix: &argoprojiov1alpha1.MatrixGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{List: &argoprojiov1alpha1.ListGenerator{Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"a": "1"}`)}}}},
+				{List: &argoprojiov1alpha1.ListGenerator{Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"b": "2"}`)}}}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 1)
+	assert.Equal(t, "1", params[0]["a"])
+	assert.Equal(t, "2", params[0]["b"])
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+)
+
+// OutputTable is the output format of the param sets as a table, with a column by param.
+const OutputTable = "table"
+
+func runParams(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, "[flags] NAME | -f FILE", stderr)
+	var opts liveOptions
+	opts.addFlags(fs)
+	var files stringSliceFlag
+	fs.Var(&files, "filename", "A file of the ApplicationSet whose generator is run, rather than an ApplicationSet of the cluster.")
+	fs.Var(&files, "f", "Shorthand for --filename.")
+	generatorIndex := fs.Int("generator-index", 0, "The index of the generator in the generators of the ApplicationSet, starting from 0.")
+	var output string
+	fs.StringVar(&output, "output", OutputTable, "The output format of the param sets: table or json.")
+	fs.StringVar(&output, "o", OutputTable, "Shorthand for --output.")
+	logLevel := fs.String("loglevel", "warn", "The level of the logs of the generators, written to the standard error. One of: debug|info|warn|error")
+	if err := parseFlags(fs, args, logLevel); err != nil {
+		return err
+	}
+	if (len(files) == 0) == (fs.NArg() == 0) || fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "either the name of an ApplicationSet or --filename must be set\n")
+		fs.Usage()
+		return errUsage
+	}
+	if output != OutputTable && output != OutputJSON {
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputTable, OutputJSON)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	live, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	appSets, err := live.getApplicationSets(ctx, fs.Arg(0), files)
+	if err != nil {
+		return err
+	}
+	if len(appSets) != 1 {
+		return fmt.Errorf("the files contain %d ApplicationSets, rather than a single one", len(appSets))
+	}
+	appSet := appSets[0]
+	if *generatorIndex < 0 || *generatorIndex >= len(appSet.Spec.Generators) {
+		return fmt.Errorf("ApplicationSet %s/%s has no generator %d, it has %d generators", appSet.Namespace, appSet.Name, *generatorIndex, len(appSet.Spec.Generators))
+	}
+
+	results, err := generators.Transform(generators.WithParamsMemo(ctx), appSet.Spec.Generators[*generatorIndex], live.generators, appSet.Spec.Template, &appSet)
+	if err != nil {
+		return errors.New(live.sensitiveValues.Redact(err.Error()))
+	}
+	var params []map[string]interface{}
+	for _, result := range results {
+		params = append(params, result.Params...)
+	}
+	return printParams(stdout, output, params, live.sensitiveValues.Redact)
+}
+
+// printParams prints the param sets as a table, with a column by param sorted by name, or as a JSON list. The output is
+// passed to redact, which replaces the sensitive values, cell by cell for the table so that its columns stay aligned.
+func printParams(w io.Writer, output string, params []map[string]interface{}, redact func(string) string) error {
+	var out string
+	switch output {
+	case OutputTable:
+		if len(params) == 0 {
+			out = "No param sets.\n"
+			break
+		}
+		keySet := map[string]bool{}
+		for _, p := range params {
+			for key := range p {
+				keySet[key] = true
+			}
+		}
+		keys := make([]string, 0, len(keySet))
+		for key := range keySet {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, redact(strings.Join(keys, "\t")))
+		for _, p := range params {
+			cells := make([]string, 0, len(keys))
+			for _, key := range keys {
+				cell, err := paramCell(p[key])
+				if err != nil {
+					return err
+				}
+				cells = append(cells, redact(cell))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		// the empty cells of the last columns are padded too
+		lines := strings.SplitAfter(b.String(), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimRight(lines[i], " \n")
+		}
+		out = strings.Join(lines[:len(lines)-1], "\n") + "\n"
+	case OutputJSON:
+		if params == nil {
+			params = []map[string]interface{}{}
+		}
+		data, err := json.MarshalIndent(params, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = redact(string(data)) + "\n"
+	default:
+		return fmt.Errorf("unknown output format %q, the output formats are %s and %s", output, OutputTable, OutputJSON)
+	}
+
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// paramCell returns the value of a param as a cell of the table: the strings as they are, unless they contain
+// whitespace other than spaces, and the other values as JSON.
+func paramCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		if strings.ContainsAny(v, "\t\n\r") {
+			return strconv.Quote(v), nil
+		}
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
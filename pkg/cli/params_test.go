@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintParams(t *testing.T) {
+	params := []map[string]interface{}{
+		{"name": "staging", "server": "https://staging.example.com", "values": map[string]interface{}{"replicas": 1}},
+		{"name": "production", "server": "https://production.example.com", "token": "secret", "notes": "multi\nline"},
+	}
+	redact := func(text string) string {
+		return strings.ReplaceAll(text, "secret", "++++++++")
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printParams(&out, OutputTable, params, redact))
+	assert.Equal(t, `name        notes          server                          token     values
+staging                    https://staging.example.com               {"replicas":1}
+production  "multi\nline"  https://production.example.com  ++++++++
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, printParams(&out, OutputJSON, params, redact))
+	assert.Contains(t, out.String(), `"name": "production"`)
+	assert.Contains(t, out.String(), `"replicas": 1`)
+	assert.NotContains(t, out.String(), "secret")
+
+	out.Reset()
+	require.NoError(t, printParams(&out, OutputTable, nil, redact))
+	assert.Equal(t, "No param sets.\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printParams(&out, OutputJSON, nil, redact))
+	assert.Equal(t, "[]\n", out.String())
+
+	assert.Error(t, printParams(&out, "yaml", params, redact))
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+// refreshPollInterval is the interval at which the refreshed ApplicationSets are polled, while waiting for the
+// controller to reconcile them.
+const refreshPollInterval = 2 * time.Second
+
+func runRefresh(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, "[flags] NAME...", stderr)
+	var opts kubeOptions
+	opts.addFlags(fs)
+	hard := fs.Bool("hard", false, "Also discard the last known params of the generators, so that a failing generator fails the reconciliation rather than generating the Applications from its last known params.")
+	waitForReconcile := fs.Bool("wait", false, "Wait until the controller has reconciled the ApplicationSets.")
+	timeout := fs.Duration("timeout", time.Minute, "The maximum duration to wait for the reconciliation with --wait.")
+	if err := parseFlags(fs, args, nil); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(stderr, "no ApplicationSet name\n")
+		fs.Usage()
+		return errUsage
+	}
+
+	c, namespace, err := opts.client()
+	if err != nil {
+		return err
+	}
+	if err := refreshApplicationSets(ctx, c, namespace, fs.Args(), *hard); err != nil {
+		return err
+	}
+	for _, appSetName := range fs.Args() {
+		fmt.Fprintf(stdout, "ApplicationSet %s/%s refresh requested\n", namespace, appSetName)
+	}
+	if !*waitForReconcile {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	if err := waitForRefresh(waitCtx, c, namespace, fs.Args(), refreshPollInterval); err != nil {
+		return err
+	}
+	for _, appSetName := range fs.Args() {
+		fmt.Fprintf(stdout, "ApplicationSet %s/%s reconciled\n", namespace, appSetName)
+	}
+	return nil
+}
+
+// refreshApplicationSets sets the refresh annotation of the ApplicationSets, which requests the controller to reconcile
+// them right away, as the webhooks do. The ApplicationSets must all exist, otherwise none is refreshed.
+func refreshApplicationSets(ctx context.Context, c client.Client, namespace string, names []string, hard bool) error {
+	for _, name := range names {
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &argoprojiov1alpha1.ApplicationSet{}); err != nil {
+			return fmt.Errorf("error getting ApplicationSet %s/%s: %v", namespace, name, err)
+		}
+	}
+
+	value := "true"
+	if hard {
+		value = common.RefreshTypeHard
+	}
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, common.AnnotationApplicationSetRefresh, value))
+	for _, name := range names {
+		appSet := &argoprojiov1alpha1.ApplicationSet{}
+		appSet.Namespace = namespace
+		appSet.Name = name
+		if err := c.Patch(ctx, appSet, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return fmt.Errorf("error refreshing ApplicationSet %s/%s: %v", namespace, name, err)
+		}
+	}
+	return nil
+}
+
+// waitForRefresh waits until the controller has removed the refresh annotation of the ApplicationSets, once it has
+// reconciled them, polling them at the interval until the context is done.
+func waitForRefresh(ctx context.Context, c client.Client, namespace string, names []string, interval time.Duration) error {
+	err := wait.PollImmediateUntil(interval, func() (bool, error) {
+		for _, name := range names {
+			var appSet argoprojiov1alpha1.ApplicationSet
+			if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &appSet); err != nil {
+				return false, fmt.Errorf("error getting ApplicationSet %s/%s: %v", namespace, name, err)
+			}
+			if appSet.RefreshRequired() {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, ctx.Done())
+	if err != nil && (errors.Is(err, wait.ErrWaitTimeout) || ctx.Err() != nil) {
+		return errors.New("timed out waiting for the controller to reconcile the ApplicationSets")
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+func TestRefreshApplicationSets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, argoprojiov1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd", Annotations: map[string]string{"team": "a"}}},
+		&argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "helm-guestbook", Namespace: "argocd"}},
+	).Build()
+	get := func(name string) *argoprojiov1alpha1.ApplicationSet {
+		var appSet argoprojiov1alpha1.ApplicationSet
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "argocd", Name: name}, &appSet))
+		return &appSet
+	}
+
+	// none is refreshed when one of them doesn't exist
+	err := refreshApplicationSets(context.Background(), c, "argocd", []string{"guestbook", "unknown"}, false)
+	assert.EqualError(t, err, `error getting ApplicationSet argocd/unknown: applicationsets.argoproj.io "unknown" not found`)
+	assert.False(t, get("guestbook").RefreshRequired())
+
+	require.NoError(t, refreshApplicationSets(context.Background(), c, "argocd", []string{"guestbook", "helm-guestbook"}, false))
+	assert.Equal(t, map[string]string{"team": "a", common.AnnotationApplicationSetRefresh: "true"}, get("guestbook").Annotations)
+	assert.True(t, get("helm-guestbook").RefreshRequired())
+	assert.False(t, get("helm-guestbook").HardRefreshRequired())
+
+	require.NoError(t, refreshApplicationSets(context.Background(), c, "argocd", []string{"helm-guestbook"}, true))
+	assert.True(t, get("helm-guestbook").HardRefreshRequired())
+
+	// the controller hasn't removed the annotations yet
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = waitForRefresh(ctx, c, "argocd", []string{"guestbook"}, 10*time.Millisecond)
+	assert.EqualError(t, err, "timed out waiting for the controller to reconcile the ApplicationSets")
+
+	appSet := get("guestbook")
+	delete(appSet.Annotations, common.AnnotationApplicationSetRefresh)
+	require.NoError(t, c.Update(context.Background(), appSet))
+	assert.NoError(t, waitForRefresh(context.Background(), c, "argocd", []string{"guestbook"}, 10*time.Millisecond))
+}
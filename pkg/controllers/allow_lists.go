@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"fmt"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// applicationNotAllowedError is returned when a generated Application targets a project or a destination outside of
+// the allow-lists of the controller.
+type applicationNotAllowedError struct {
+	err error
+}
+
+func (e *applicationNotAllowedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *applicationNotAllowedError) Unwrap() error {
+	return e.err
+}
+
+// validateAllowLists returns an applicationNotAllowedError if the project or the destination of the Application are
+// not allowed.
+func validateAllowLists(app *argov1alpha1.Application, projects utils.ProjectAllowList, destinations utils.DestinationAllowList) error {
+	if project := app.Spec.GetProject(); !projects.IsAllowed(project) {
+		return &applicationNotAllowedError{err: fmt.Errorf("application %s references project %s, which is not allowed: the allowed projects are %s", app.Name, project, projects.String())}
+	}
+	if destination := app.Spec.Destination; !destinations.IsAllowed(destination) {
+		server := destination.Server
+		if server == "" {
+			server = destination.Name
+		}
+		return &applicationNotAllowedError{err: fmt.Errorf("application %s targets destination %s@%s, which is not allowed: the allowed destinations are %s", app.Name, destination.Namespace, server, destinations.String())}
+	}
+	return nil
+}
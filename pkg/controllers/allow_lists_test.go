@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestValidateAllowLists(t *testing.T) {
+	projects := utils.ParseProjectAllowList("team-*")
+	destinations, err := utils.ParseDestinationAllowList("team-*@https://kubernetes.default.svc,*@staging")
+	assert.NoError(t, err)
+
+	app := func(project string, destination argov1alpha1.ApplicationDestination) *argov1alpha1.Application {
+		return &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: project, Destination: destination},
+		}
+	}
+
+	for _, c := range []struct {
+		name          string
+		app           *argov1alpha1.Application
+		expectedError string
+	}{
+		{
+			name: "allowed project and destination",
+			app:  app("team-a", argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "team-a"}),
+		},
+		{
+			name: "allowed cluster name",
+			app:  app("team-a", argov1alpha1.ApplicationDestination{Name: "staging", Namespace: "kube-system"}),
+		},
+		{
+			name:          "project outside of the allow-list",
+			app:           app("platform", argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "team-a"}),
+			expectedError: "application app references project platform, which is not allowed: the allowed projects are team-*",
+		},
+		{
+			name:          "default project",
+			app:           app("", argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "team-a"}),
+			expectedError: "application app references project default, which is not allowed: the allowed projects are team-*",
+		},
+		{
+			name:          "destination outside of the allow-list",
+			app:           app("team-a", argov1alpha1.ApplicationDestination{Name: "production", Namespace: "team-a"}),
+			expectedError: "application app targets destination team-a@production, which is not allowed: the allowed destinations are team-*@https://kubernetes.default.svc,*@staging",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateAllowLists(c.app, projects, destinations)
+			if c.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.expectedError)
+			var notAllowedErr *applicationNotAllowedError
+			assert.True(t, errors.As(err, &notAllowedErr))
+		})
+	}
+
+	// all the projects and destinations are allowed without allow-lists
+	assert.NoError(t, validateAllowLists(app("platform", argov1alpha1.ApplicationDestination{Name: "production", Namespace: "kube-system"}), nil, nil))
+}
+
+func TestApplicationErrorReasonNotAllowed(t *testing.T) {
+	err := validateAllowLists(&argov1alpha1.Application{Spec: argov1alpha1.ApplicationSpec{Project: "platform"}}, utils.ParseProjectAllowList("team-*"), nil)
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonApplicationNotAllowed,
+		applicationErrorReason(err, argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError))
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError,
+		applicationErrorReason(errors.New("invalid"), argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError))
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// updateApplicationStatus records the health and sync status of each current Application of the ApplicationSet in its
+// status, sorted by name, with the time at which they last changed. The status is only updated when it changes.
+func (r *ApplicationSetReconciler) updateApplicationStatus(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) error {
+	current, err := r.getCurrentApplications(ctx, *applicationSet)
+	if err != nil {
+		return err
+	}
+
+	previous := map[string]argoprojiov1alpha1.ApplicationSetApplicationStatus{}
+	for _, status := range applicationSet.Status.ApplicationStatus {
+		previous[status.Application] = status
+	}
+
+	// The status only keeps seconds, so the times are truncated to compare them with the previous ones
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	var statuses []argoprojiov1alpha1.ApplicationSetApplicationStatus
+	for _, app := range current {
+		status := argoprojiov1alpha1.ApplicationSetApplicationStatus{
+			Application:        app.Name,
+			Health:             app.Status.Health.Status,
+			Sync:               app.Status.Sync.Status,
+			LastTransitionTime: &now,
+		}
+		if previousStatus, found := previous[app.Name]; found && previousStatus.Health == status.Health && previousStatus.Sync == status.Sync {
+			status.LastTransitionTime = previousStatus.LastTransitionTime
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Application < statuses[j].Application
+	})
+
+	if equalApplicationStatus(statuses, applicationSet.Status.ApplicationStatus) {
+		return nil
+	}
+	return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.ApplicationStatus = statuses
+	})
+}
+
+// equalApplicationStatus returns true if the statuses of the Applications are the same, regardless of the location of
+// their times.
+func equalApplicationStatus(a []argoprojiov1alpha1.ApplicationSetApplicationStatus, b []argoprojiov1alpha1.ApplicationSetApplicationStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Application != b[i].Application || a[i].Health != b[i].Health || a[i].Sync != b[i].Sync ||
+			!a[i].LastTransitionTime.Equal(b[i].LastTransitionTime) {
+			return false
+		}
+	}
+	return true
+}
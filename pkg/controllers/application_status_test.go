@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestUpdateApplicationStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	twoHoursAgo := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Status: argoprojiov1alpha1.ApplicationSetStatus{
+			ApplicationStatus: []argoprojiov1alpha1.ApplicationSetApplicationStatus{
+				// app1 is unchanged, app2 became healthy, and app4 no longer exists
+				{Application: "app1", Health: health.HealthStatusHealthy, Sync: argov1alpha1.SyncStatusCodeSynced, LastTransitionTime: &twoHoursAgo},
+				{Application: "app2", Health: health.HealthStatusProgressing, Sync: argov1alpha1.SyncStatusCodeSynced, LastTransitionTime: &twoHoursAgo},
+				{Application: "app4", Health: health.HealthStatusHealthy, Sync: argov1alpha1.SyncStatusCodeSynced, LastTransitionTime: &twoHoursAgo},
+			},
+		},
+	}
+	initObjs := []crtclient.Object{&appSet}
+	for _, app := range []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app3", Namespace: "namespace"},
+			Status: argov1alpha1.ApplicationStatus{
+				Health: argov1alpha1.HealthStatus{Status: health.HealthStatusMissing},
+				Sync:   argov1alpha1.SyncStatus{Status: argov1alpha1.SyncStatusCodeOutOfSync},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app2", Namespace: "namespace"},
+			Status: argov1alpha1.ApplicationStatus{
+				Health: argov1alpha1.HealthStatus{Status: health.HealthStatusHealthy},
+				Sync:   argov1alpha1.SyncStatus{Status: argov1alpha1.SyncStatusCodeSynced},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "namespace"},
+			Status: argov1alpha1.ApplicationStatus{
+				Health: argov1alpha1.HealthStatus{Status: health.HealthStatusHealthy},
+				Sync:   argov1alpha1.SyncStatus{Status: argov1alpha1.SyncStatusCodeSynced},
+			},
+		},
+	} {
+		a := app
+		err := controllerutil.SetControllerReference(&appSet, &a, scheme)
+		assert.Nil(t, err)
+		initObjs = append(initObjs, &a)
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	err = r.updateApplicationStatus(context.TODO(), &appSet)
+	assert.NoError(t, err)
+
+	got := &argoprojiov1alpha1.ApplicationSet{}
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+	assert.Nil(t, err)
+	if assert.Len(t, got.Status.ApplicationStatus, 3) {
+		app1, app2, app3 := got.Status.ApplicationStatus[0], got.Status.ApplicationStatus[1], got.Status.ApplicationStatus[2]
+
+		assert.Equal(t, "app1", app1.Application)
+		assert.Equal(t, health.HealthStatusHealthy, app1.Health)
+		assert.Equal(t, argov1alpha1.SyncStatusCodeSynced, app1.Sync)
+		assert.True(t, twoHoursAgo.Equal(app1.LastTransitionTime))
+
+		assert.Equal(t, "app2", app2.Application)
+		assert.Equal(t, health.HealthStatusHealthy, app2.Health)
+		assert.WithinDuration(t, time.Now(), app2.LastTransitionTime.Time, 2*time.Second)
+
+		assert.Equal(t, "app3", app3.Application)
+		assert.Equal(t, health.HealthStatusMissing, app3.Health)
+		assert.Equal(t, argov1alpha1.SyncStatusCodeOutOfSync, app3.Sync)
+		assert.WithinDuration(t, time.Now(), app3.LastTransitionTime.Time, 2*time.Second)
+	}
+
+	// The status is left untouched when the Applications don't change
+	resourceVersion := got.ResourceVersion
+	err = r.updateApplicationStatus(context.TODO(), got)
+	assert.NoError(t, err)
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+	assert.Nil(t, err)
+	assert.Equal(t, resourceVersion, got.ResourceVersion)
+}
@@ -16,20 +16,31 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/argoproj-labs/applicationset/common"
+	"github.com/argoproj-labs/applicationset/pkg/engine"
 	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
 	"github.com/argoproj-labs/applicationset/pkg/utils"
+	"github.com/argoproj-labs/applicationset/pkg/validation"
 	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -54,6 +65,49 @@ const (
 	ReconcileRequeueOnValidationError = time.Minute * 3
 )
 
+// ErrDeletionThresholdExceeded is returned when a reconciliation would delete more than the maximum deletion percentage
+// of the Applications of an ApplicationSet.
+var ErrDeletionThresholdExceeded = errors.New("deletion threshold exceeded")
+
+// deletionProtectedError is returned when Applications which are no longer generated are not deleted, because they are
+// protected from deletion by their annotation.
+type deletionProtectedError struct {
+	names []string
+}
+
+func (e *deletionProtectedError) Error() string {
+	return fmt.Sprintf("applications %s are no longer generated, but are not deleted as they are protected by the %s annotation", strings.Join(e.names, ", "), argoprojiov1alpha1.DeletionProtectedAnnotation)
+}
+
+// applicationNameConflictError is returned when a generated Application has the name of another generated Application,
+// or of an existing Application which the ApplicationSet may not take over.
+type applicationNameConflictError struct {
+	err error
+}
+
+func (e *applicationNameConflictError) Error() string {
+	return e.err.Error()
+}
+
+func (e *applicationNameConflictError) Unwrap() error {
+	return e.err
+}
+
+// applicationErrorReason returns the reason of the condition reporting an error of the Applications: the name conflict
+// reason if the error is a name conflict, the not allowed reason if the project or the destination of an Application
+// are not allowed, or the given reason otherwise.
+func applicationErrorReason(err error, reason string) string {
+	var conflictErr *applicationNameConflictError
+	if errors.As(err, &conflictErr) {
+		return argoprojiov1alpha1.ApplicationSetReasonApplicationNameConflict
+	}
+	var notAllowedErr *applicationNotAllowedError
+	if errors.As(err, &notAllowedErr) {
+		return argoprojiov1alpha1.ApplicationSetReasonApplicationNotAllowed
+	}
+	return reason
+}
+
 // ApplicationSetReconciler reconciles a ApplicationSet object
 type ApplicationSetReconciler struct {
 	client.Client
@@ -64,8 +118,58 @@ type ApplicationSetReconciler struct {
 	ArgoDB           db.ArgoDB
 	ArgoAppClientset appclientset.Interface
 	KubeClientset    kubernetes.Interface
+	// ClusterDecisionResourceWatcher, if set, requeues ApplicationSets when the resources selected by their
+	// ClusterDecisionResource generators change.
+	ClusterDecisionResourceWatcher *ClusterDecisionResourceWatcher
+	// SensitiveValues, if set, are redacted from the events and the status of ApplicationSets.
+	SensitiveValues *utils.SensitiveValues
+	// EnablePolicyOverride allows the applications sync policy of each ApplicationSet to override Policy.
+	EnablePolicyOverride bool
+	// ApplicationSetSelector, if set, restricts the ApplicationSets reconciled by the controller to the ones whose
+	// labels it matches, so that several controllers may reconcile disjoint sets of ApplicationSets.
+	ApplicationSetSelector labels.Selector
+	// MaxConcurrentReconciles is the maximum number of ApplicationSets reconciled at the same time, 1 if not set.
+	MaxConcurrentReconciles int
+	// ReconcileTimeout, if set, is the maximum duration of the reconciliation of an ApplicationSet. The changes to the
+	// Applications are not applied when the generators take longer, and the ApplicationSet is requeued.
+	ReconcileTimeout time.Duration
+	// GeneratorBackoff, if set, delays the reconciliation of the ApplicationSets whose generators fail in consecutive
+	// reconciliations.
+	GeneratorBackoff *GeneratorBackoff
+	// MaxDeletionPercentage, if set, is the maximum percentage of the Applications of an ApplicationSet which may be
+	// deleted by a reconciliation, unless the ApplicationSet sets its own.
+	MaxDeletionPercentage *int64
+	// TrackingMethod is how the Applications generated by the ApplicationSets are tracked, with owner references if
+	// not set.
+	TrackingMethod utils.TrackingMethod
+	// SkipUnchangedApplications leaves the existing Applications untouched while they are rendered the same as when
+	// they were last applied, unless their ApplicationSet is refreshed, rather than comparing them with the rendered
+	// Applications at each reconciliation.
+	SkipUnchangedApplications bool
+	// ServerSideApply creates and updates the Applications with server-side apply, so that only the fields rendered by
+	// the ApplicationSets are managed by the controller, and the fields managed by other managers are left untouched.
+	ServerSideApply bool
+	// AllowedProjects restricts the projects of the generated Applications, all the projects are allowed if empty.
+	AllowedProjects utils.ProjectAllowList
+	// AllowedDestinations restricts the destinations of the generated Applications, all the destinations are allowed
+	// if empty.
+	AllowedDestinations utils.DestinationAllowList
+	// ForbidTemplatedProjects rejects the ApplicationSets whose templates render the project of the Applications from
+	// params, so that the params can't choose the project.
+	ForbidTemplatedProjects bool
+	// MaxParamSets is the maximum number of param sets of an ApplicationSet, no maximum if 0. Beyond it, the
+	// ApplicationSet reports the ParamSetsOverflow error, and its Applications are neither updated nor deleted.
+	MaxParamSets int
+	// ApplyChunkSize, if set, is the number of Applications validated, and created or updated, at once, as soon as they
+	// are rendered, so that the rendered Applications of an ApplicationSet are not all kept in memory. The
+	// ApplicationSets whose strategy orders their Applications, or limits their updates, and the dry runs, are still
+	// applied at once.
+	ApplyChunkSize int
 	utils.Policy
 	utils.Renderer
+
+	// paramsCache keeps the last known results of the generators, used when they fail.
+	paramsCache generatedParamsCache
 }
 
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;list;watch;create;update;patch;delete
@@ -75,26 +179,164 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	_ = r.Log.WithValues("applicationset", req.NamespacedName)
 	_ = log.WithField("applicationset", req.NamespacedName)
 
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
 	var applicationSetInfo argoprojiov1alpha1.ApplicationSet
 	parametersGenerated := false
+	startTime := time.Now()
 
 	if err := r.Get(ctx, req.NamespacedName, &applicationSetInfo); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			log.WithError(err).Infof("unable to get ApplicationSet: '%v' ", err)
+		} else {
+			r.paramsCache.delete(req.NamespacedName.String())
+			metrics.DeleteApplicationSet(req.Namespace, req.Name)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	defer func() {
+		metrics.ObserveReconcile(req.Namespace, req.Name, time.Since(startTime))
+	}()
+
+	reconcileID := string(uuid.NewUUID())
+	logCtx := utils.ApplicationSetLogger(&applicationSetInfo).WithField("reconcileID", reconcileID)
+	ctx = utils.ContextWithLogger(ctx, logCtx)
+
+	// The changes made to the Applications are recorded in the history once the reconciliation is over, whether it
+	// succeeded or not
+	history := newApplicationHistory(&applicationSetInfo, reconcileID)
+	ctx = contextWithHistory(ctx, history)
+	defer func() {
+		if err := r.recordHistory(ctx, &applicationSetInfo, history); err != nil {
+			logCtx.Warnf("error occurred while recording the history of the ApplicationSet: %v", err)
+		}
+	}()
 
-	// Do not attempt to further reconcile the ApplicationSet if it is being deleted.
+	// ApplicationSets which are not selected are reconciled by another controller
+	if r.ApplicationSetSelector != nil && !r.ApplicationSetSelector.Matches(labels.Set(applicationSetInfo.Labels)) {
+		logCtx.Debug("ignoring ApplicationSet not matching the ApplicationSet selector")
+		return ctrl.Result{}, nil
+	}
+
+	// Do not attempt to further reconcile the ApplicationSet if it is being deleted, other than orphaning its
+	// Applications if they are preserved.
 	if applicationSetInfo.ObjectMeta.DeletionTimestamp != nil {
+		if controllerutil.ContainsFinalizer(&applicationSetInfo, argoprojiov1alpha1.PreserveApplicationsFinalizer) {
+			if err := r.orphanApplications(ctx, &applicationSetInfo); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updatePreserveApplicationsFinalizer(ctx, &applicationSetInfo); err != nil {
+		logCtx.WithError(err).Warn("error occurred while updating the finalizers of the ApplicationSet")
+		return ctrl.Result{}, err
+	}
+
+	if r.ClusterDecisionResourceWatcher != nil {
+		r.ClusterDecisionResourceWatcher.WatchApplicationSet(&applicationSetInfo)
+	}
+
+	// Suspended ApplicationSets are reconciled again when they are resumed, which changes their spec
+	if applicationSetInfo.Spec.Suspend {
+		logCtx.Debug("ignoring suspended ApplicationSet")
+		if err := r.setSuspendedCondition(ctx, &applicationSetInfo); err != nil {
+			logCtx.WithError(err).Warn("error occurred while setting the condition of the suspended ApplicationSet")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	policy, err := r.getPolicy(applicationSetInfo)
+	if err != nil {
+		_ = r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: err.Error(),
+				Reason:  argoprojiov1alpha1.ApplicationSetReasonInvalidApplicationsSyncPolicy,
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		)
 		return ctrl.Result{}, nil
 	}
 
+	// The existing Applications are left untouched, rather than deleted, until the templates are fixed
+	if r.ForbidTemplatedProjects {
+		if errs := validation.ValidateTemplatedProjects(&applicationSetInfo); len(errs) > 0 {
+			err := errs.ToAggregate()
+			logCtx.WithError(err).Warn("the project of the Applications is rendered from params")
+			r.recordEvent(&applicationSetInfo, corev1.EventTypeWarning, argoprojiov1alpha1.ApplicationSetReasonTemplatedProjectForbidden, "%v", err)
+			_ = r.setApplicationSetStatusCondition(ctx,
+				&applicationSetInfo,
+				argoprojiov1alpha1.ApplicationSetCondition{
+					Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+					Message: err.Error(),
+					Reason:  argoprojiov1alpha1.ApplicationSetReasonTemplatedProjectForbidden,
+					Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+				}, parametersGenerated,
+			)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if delay := r.getGeneratorBackoffDelay(&applicationSetInfo); delay > 0 {
+		logCtx.WithField("requeueAfter", delay).
+			Debug("delaying the generators of the ApplicationSet after consecutive failures")
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	// Log a warning if there are unrecognized generators
 	utils.CheckInvalidGenerators(&applicationSetInfo)
-	// desiredApplications is the main list of all expected Applications from all generators in this appset.
-	desiredApplications, applicationSetReason, err := r.generateApplications(applicationSetInfo)
+	// The Applications of the ApplicationSet are applied as they are rendered, by chunks, if the controller applies
+	// them by chunks
+	var chunks *chunkedApply
+	if r.applyByChunks(&applicationSetInfo) {
+		chunks = newChunkedApply(ctx, r, applicationSetInfo, policy)
+	}
+	// desiredApplications is the main list of all expected Applications from all generators in this appset. When they
+	// are applied by chunks, only their names are kept.
+	desiredApplications, parameters, applicationSetReason, err := r.generateApplicationsByChunks(ctx, applicationSetInfo, chunks)
+	// The requests of the generators are cancelled at the timeout, so their errors are not reported as failures of the
+	// generators, and the Applications they generated are discarded rather than applied with an expired context.
+	if ctx.Err() != nil {
+		logCtx.Warnf("reconcile timed out after %s, requeueing", r.ReconcileTimeout)
+		return ctrl.Result{}, fmt.Errorf("reconcile of ApplicationSet %s timed out: %v", req.NamespacedName, ctx.Err())
+	}
+	// When a chunk of Applications failed to be validated or applied, the next chunks are not rendered, and no
+	// Application is deleted
+	if chunks != nil && chunks.err != nil {
+		parametersGenerated = true
+		logCtx.Errorf("error occurred while applying the Applications by chunks: %s", chunks.err.Error())
+		_ = r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: chunks.err.Error(),
+				Reason:  chunks.reason,
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		)
+		if chunks.reason == argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError {
+			return ctrl.Result{RequeueAfter: ReconcileRequeueOnValidationError}, nil
+		}
+		return ctrl.Result{}, chunks.err
+	}
+	// When generators failed but their last known parameters were used, the Applications are still created and
+	// updated, but none is deleted.
+	var staleErr *engine.StaleParamsError
+	if errors.As(err, &staleErr) {
+		logCtx.Warn(staleErr.Error())
+		r.recordEvent(&applicationSetInfo, corev1.EventTypeWarning, string(applicationSetReason), "Generators failed, %v", staleErr)
+		err = nil
+	}
 	if err != nil {
+		r.recordEvent(&applicationSetInfo, corev1.EventTypeWarning, string(applicationSetReason), "Failed to generate Applications: %v", err)
 		_ = r.setApplicationSetStatusCondition(ctx,
 			&applicationSetInfo,
 			argoprojiov1alpha1.ApplicationSetCondition{
@@ -104,12 +346,60 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
 			}, parametersGenerated,
 		)
+		// Too many param sets require the user to change the generators, so the ApplicationSet is not requeued with
+		// the rate limiting of the controller
+		if applicationSetReason == argoprojiov1alpha1.ApplicationSetReasonParamSetsOverflow {
+			return ctrl.Result{RequeueAfter: ReconcileRequeueOnValidationError}, nil
+		}
+		if r.GeneratorBackoff != nil && applicationSetReason == argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError {
+			delay, backoffErr := r.recordGeneratorFailure(ctx, &applicationSetInfo)
+			if backoffErr != nil {
+				logCtx.Warnf("error occurred while recording the failure of the generators of the ApplicationSet: %v", backoffErr)
+			}
+			// Beyond the error budget, the ApplicationSet is requeued after the backoff delay rather than with the
+			// rate limiting of the controller.
+			if delay > 0 {
+				logCtx.WithField("requeueAfter", delay).
+					Warnf("generators failed in %d consecutive reconciliations, backing off", applicationSetInfo.Status.GeneratorBackoff.ConsecutiveFailures)
+				// The refresh was attempted, so it no longer bypasses the backoff
+				if applicationSetInfo.RefreshRequired() {
+					delete(applicationSetInfo.Annotations, common.AnnotationApplicationSetRefresh)
+					if err := r.Client.Update(ctx, &applicationSetInfo); err != nil {
+						logCtx.Warnf("error occurred while updating ApplicationSet: %v", err)
+					}
+				}
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
 	parametersGenerated = true
 
-	validateErrors, err := r.validateGeneratedApplications(ctx, desiredApplications, applicationSetInfo, req.Namespace)
+	if err := r.setParametersStatus(ctx, &applicationSetInfo, parameters); err != nil {
+		logCtx.Warnf("error occurred while updating the parameters of the ApplicationSet: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	var generatorBackoffDelay time.Duration
+	if staleErr == nil {
+		if err := r.resetGeneratorBackoff(ctx, &applicationSetInfo); err != nil {
+			logCtx.Warnf("error occurred while resetting the backoff of the generators of the ApplicationSet: %v", err)
+			return ctrl.Result{}, err
+		}
+	} else if r.GeneratorBackoff != nil {
+		if generatorBackoffDelay, err = r.recordGeneratorFailure(ctx, &applicationSetInfo); err != nil {
+			logCtx.Warnf("error occurred while recording the failure of the generators of the ApplicationSet: %v", err)
+		}
+	}
+
+	var validateErrors map[int]error
+	if chunks != nil {
+		// The Applications applied by chunks were already validated, and none is left to create or update
+		validateErrors, err = chunks.validateErrors, nil
+	} else {
+		validateErrors, err = r.validateGeneratedApplications(ctx, desiredApplications, applicationSetInfo, req.Namespace)
+	}
 	if err != nil {
 		// While some generators may return an error that requires user intervention,
 		// other generators reference external resources that may change to cause
@@ -118,7 +408,7 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		//
 		// Changes to watched resources will cause this to be reconciled sooner than
 		// the RequeueAfter time.
-		log.Errorf("error occurred during application validation: %s", err.Error())
+		logCtx.Errorf("error occurred during application validation: %s", err.Error())
 
 		_ = r.setApplicationSetStatusCondition(ctx,
 			&applicationSetInfo,
@@ -134,16 +424,18 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	var validApps []argov1alpha1.Application
 	for i := range desiredApplications {
-		if validateErrors[i] == nil {
+		if chunks == nil && validateErrors[i] == nil {
 			validApps = append(validApps, desiredApplications[i])
 		}
 	}
 
 	if len(validateErrors) > 0 {
 		var message string
+		var lastErr error
 		for _, v := range validateErrors {
+			lastErr = v
 			message = v.Error()
-			log.Errorf("validation error found during application validation: %s", message)
+			logCtx.Errorf("validation error found during application validation: %s", message)
 		}
 		if len(validateErrors) > 1 {
 			// Only the last message gets added to the appset status, to keep the size reasonable.
@@ -154,13 +446,56 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			argoprojiov1alpha1.ApplicationSetCondition{
 				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
 				Message: message,
-				Reason:  argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError,
+				Reason:  applicationErrorReason(lastErr, argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError),
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		)
+	}
+
+	validApps, err = r.applyStrategy(ctx, &applicationSetInfo, validApps)
+	if err != nil {
+		_ = r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: err.Error(),
+				Reason:  argoprojiov1alpha1.ApplicationSetReasonStrategyError,
 				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
 			}, parametersGenerated,
 		)
+		return ctrl.Result{}, err
 	}
 
-	if r.Policy.Update() {
+	validApps, err = r.limitUpdates(ctx, applicationSetInfo, validApps, policy)
+	if err != nil {
+		_ = r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: err.Error(),
+				Reason:  argoprojiov1alpha1.ApplicationSetReasonStrategyError,
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		)
+		return ctrl.Result{}, err
+	}
+
+	var dryRunActions []argoprojiov1alpha1.ApplicationSetDryRunAction
+	if applicationSetInfo.Spec.DryRun {
+		dryRunActions, err = r.dryRunInCluster(ctx, applicationSetInfo, validApps, desiredApplications, policy)
+		if err != nil {
+			_ = r.setApplicationSetStatusCondition(ctx,
+				&applicationSetInfo,
+				argoprojiov1alpha1.ApplicationSetCondition{
+					Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+					Message: err.Error(),
+					Reason:  applicationErrorReason(err, argoprojiov1alpha1.ApplicationSetReasonDryRunError),
+					Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+				}, parametersGenerated,
+			)
+			return ctrl.Result{}, err
+		}
+	} else if policy.Update() {
 		err = r.createOrUpdateInCluster(ctx, applicationSetInfo, validApps)
 		if err != nil {
 			_ = r.setApplicationSetStatusCondition(ctx,
@@ -168,7 +503,7 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				argoprojiov1alpha1.ApplicationSetCondition{
 					Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
 					Message: err.Error(),
-					Reason:  argoprojiov1alpha1.ApplicationSetReasonUpdateApplicationError,
+					Reason:  applicationErrorReason(err, argoprojiov1alpha1.ApplicationSetReasonUpdateApplicationError),
 					Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
 				}, parametersGenerated,
 			)
@@ -182,7 +517,7 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				argoprojiov1alpha1.ApplicationSetCondition{
 					Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
 					Message: err.Error(),
-					Reason:  argoprojiov1alpha1.ApplicationSetReasonCreateApplicationError,
+					Reason:  applicationErrorReason(err, argoprojiov1alpha1.ApplicationSetReasonCreateApplicationError),
 					Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
 				}, parametersGenerated,
 			)
@@ -190,8 +525,34 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}
 
-	if r.Policy.Delete() {
-		err = r.deleteInCluster(ctx, applicationSetInfo, desiredApplications)
+	var deletionRequeueAfter time.Duration
+	var deletionProtectedErr *deletionProtectedError
+	if policy.Delete() && !applicationSetInfo.Spec.DryRun && staleErr == nil {
+		keptApplications, pendingDeletionRequeueAfter, err := r.delayDeletions(ctx, &applicationSetInfo, desiredApplications)
+		if err != nil {
+			logCtx.Warnf("error occurred while updating the pending deletions of the ApplicationSet: %v", err)
+			return ctrl.Result{}, err
+		}
+
+		deletionBatchRequeueAfter, err := r.deleteInCluster(ctx, applicationSetInfo, keptApplications)
+		if errors.Is(err, ErrDeletionThresholdExceeded) {
+			// The deletions are refused until the generators produce the Applications again, or the threshold is
+			// raised, so there is no point retrying sooner.
+			_ = r.setApplicationSetStatusCondition(ctx,
+				&applicationSetInfo,
+				argoprojiov1alpha1.ApplicationSetCondition{
+					Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+					Message: err.Error(),
+					Reason:  argoprojiov1alpha1.ApplicationSetReasonDeletionThresholdExceeded,
+					Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+				}, parametersGenerated,
+			)
+			return ctrl.Result{RequeueAfter: ReconcileRequeueOnValidationError}, nil
+		}
+		// The deletion protected Applications are reported once the reconciliation is complete
+		if errors.As(err, &deletionProtectedErr) {
+			err = nil
+		}
 		if err != nil {
 			_ = r.setApplicationSetStatusCondition(ctx,
 				&applicationSetInfo,
@@ -204,13 +565,25 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			)
 			return ctrl.Result{}, err
 		}
+		deletionRequeueAfter = minRequeueAfter(pendingDeletionRequeueAfter, deletionBatchRequeueAfter)
+	}
+
+	if err := r.setApplicationSetDryRunActions(ctx, &applicationSetInfo, dryRunActions); err != nil {
+		logCtx.Warnf("error occurred while updating the dry run actions of the ApplicationSet: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateApplicationStatus(ctx, &applicationSetInfo); err != nil {
+		logCtx.Warnf("error occurred while updating the status of the Applications of the ApplicationSet: %v", err)
+		return ctrl.Result{}, err
 	}
+	r.recordApplicationsMetrics(ctx, &applicationSetInfo, desiredApplications, len(desiredApplications)-len(validateErrors))
 
 	if applicationSetInfo.RefreshRequired() {
 		delete(applicationSetInfo.Annotations, common.AnnotationApplicationSetRefresh)
 		err := r.Client.Update(ctx, &applicationSetInfo)
 		if err != nil {
-			log.Warnf("error occurred while updating ApplicationSet: %v", err)
+			logCtx.Warnf("error occurred while updating ApplicationSet: %v", err)
 			_ = r.setApplicationSetStatusCondition(ctx,
 				&applicationSetInfo,
 				argoprojiov1alpha1.ApplicationSetCondition{
@@ -224,8 +597,40 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}
 
+	if staleErr != nil {
+		_ = r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: staleErr.Error(),
+				Reason:  argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError,
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		)
+		if generatorBackoffDelay > 0 {
+			return ctrl.Result{RequeueAfter: generatorBackoffDelay}, nil
+		}
+		return ctrl.Result{}, staleErr
+	}
+
 	requeueAfter := r.getMinRequeueAfter(&applicationSetInfo)
-	log.WithField("requeueAfter", requeueAfter).Info("end reconcile")
+	requeueAfter = minRequeueAfter(requeueAfter, deletionRequeueAfter)
+	logCtx.WithField("requeueAfter", requeueAfter).Info("end reconcile")
+
+	if deletionProtectedErr != nil {
+		if err := r.setApplicationSetStatusCondition(ctx,
+			&applicationSetInfo,
+			argoprojiov1alpha1.ApplicationSetCondition{
+				Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+				Message: deletionProtectedErr.Error(),
+				Reason:  argoprojiov1alpha1.ApplicationSetReasonDeletionProtected,
+				Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+			}, parametersGenerated,
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 
 	if len(validateErrors) == 0 {
 		if err := r.setApplicationSetStatusCondition(ctx,
@@ -287,6 +692,8 @@ func getResourceUpToDateCondition(errorOccurred bool, message string, reason str
 }
 
 func (r *ApplicationSetReconciler) setApplicationSetStatusCondition(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, condition argoprojiov1alpha1.ApplicationSetCondition, paramtersGenerated bool) error {
+	condition.Message = r.SensitiveValues.Redact(condition.Message)
+
 	// check if error occurred during reconcile process
 	errOccurred := condition.Type == argoprojiov1alpha1.ApplicationSetConditionErrorOccurred
 
@@ -348,17 +755,63 @@ func (r *ApplicationSetReconciler) setApplicationSetStatusCondition(ctx context.
 	return nil
 }
 
+// setSuspendedCondition sets the ResourcesUpToDate condition of the suspended ApplicationSet to false, leaving its
+// other conditions, and the rest of its status, as they were when it was suspended.
+func (r *ApplicationSetReconciler) setSuspendedCondition(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) error {
+	suspendedCondition := argoprojiov1alpha1.ApplicationSetCondition{
+		Type:    argoprojiov1alpha1.ApplicationSetConditionResourcesUpToDate,
+		Message: "ApplicationSet is suspended, its Applications are neither generated nor changed",
+		Reason:  argoprojiov1alpha1.ApplicationSetReasonSuspended,
+		Status:  argoprojiov1alpha1.ApplicationSetConditionStatusFalse,
+	}
+
+	conditions := []argoprojiov1alpha1.ApplicationSetCondition{suspendedCondition}
+	for _, c := range applicationSet.Status.Conditions {
+		if c.Type != suspendedCondition.Type {
+			conditions = append(conditions, c)
+			continue
+		}
+		// do nothing if appset already has the suspended condition
+		if c.Reason == suspendedCondition.Reason && c.Status == suspendedCondition.Status && c.Message == suspendedCondition.Message {
+			return nil
+		}
+	}
+
+	applicationSet.Status.SetConditions(conditions, map[argoprojiov1alpha1.ApplicationSetConditionType]bool{
+		argoprojiov1alpha1.ApplicationSetConditionResourcesUpToDate: true,
+	})
+	if err := r.Client.Status().Update(ctx, applicationSet); err != nil && !apierr.IsNotFound(err) {
+		return fmt.Errorf("unable to set application set condition: %v", err)
+	}
+	return nil
+}
+
 // validateGeneratedApplications uses the Argo CD validation functions to verify the correctness of the
 // generated applications.
 func (r *ApplicationSetReconciler) validateGeneratedApplications(ctx context.Context, desiredApplications []argov1alpha1.Application, applicationSetInfo argoprojiov1alpha1.ApplicationSet, namespace string) (map[int]error, error) {
+	return r.validateApplications(ctx, desiredApplications, applicationSetInfo, namespace, map[string]bool{})
+}
+
+// validateApplications validates the generated applications like validateGeneratedApplications, namesSet being the
+// names of the applications already validated, to which their names are added.
+func (r *ApplicationSetReconciler) validateApplications(ctx context.Context, desiredApplications []argov1alpha1.Application, applicationSetInfo argoprojiov1alpha1.ApplicationSet, namespace string, namesSet map[string]bool) (map[int]error, error) {
 	errorsByIndex := map[int]error{}
-	namesSet := map[string]bool{}
 	for i, app := range desiredApplications {
 
 		if !namesSet[app.Name] {
 			namesSet[app.Name] = true
 		} else {
-			errorsByIndex[i] = fmt.Errorf("ApplicationSet %s contains applications with duplicate name: %s", applicationSetInfo.Name, app.Name)
+			errorsByIndex[i] = &applicationNameConflictError{err: fmt.Errorf("ApplicationSet %s contains applications with duplicate name: %s", applicationSetInfo.Name, app.Name)}
+			continue
+		}
+
+		if errs := k8svalidation.IsDNS1123Subdomain(app.Name); len(errs) > 0 {
+			errorsByIndex[i] = fmt.Errorf("application name %q is invalid: %s", app.Name, strings.Join(errs, ", "))
+			continue
+		}
+
+		if err := validateAllowLists(&app, r.AllowedProjects, r.AllowedDestinations); err != nil {
+			errorsByIndex[i] = err
 			continue
 		}
 
@@ -390,6 +843,35 @@ func (r *ApplicationSetReconciler) validateGeneratedApplications(ctx context.Con
 	return errorsByIndex, nil
 }
 
+// getPolicy returns the policy of the changes to the Applications of the ApplicationSet: the applications sync policy
+// of the ApplicationSet if it is set, and if the policy of the controller may be overridden, or the policy of the
+// controller otherwise.
+func (r *ApplicationSetReconciler) getPolicy(applicationSetInfo argoprojiov1alpha1.ApplicationSet) (utils.Policy, error) {
+	if applicationSetInfo.Spec.SyncPolicy == nil || applicationSetInfo.Spec.SyncPolicy.ApplicationsSync == "" {
+		return r.Policy, nil
+	}
+
+	applicationsSync := applicationSetInfo.Spec.SyncPolicy.ApplicationsSync
+	policy, exists := utils.Policies[string(applicationsSync)]
+	if !exists {
+		return nil, fmt.Errorf("invalid applications sync policy %s, must be one of create-only, create-update, create-delete, sync", applicationsSync)
+	}
+	if !r.EnablePolicyOverride {
+		utils.ApplicationSetLogger(&applicationSetInfo).
+			Debugf("ignoring applications sync policy %s, as the policy of the controller may not be overridden", applicationsSync)
+		return r.Policy, nil
+	}
+	return policy, nil
+}
+
+// minRequeueAfter returns the shortest of the requeue delays, 0 meaning that no requeue is needed.
+func minRequeueAfter(a time.Duration, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 func (r *ApplicationSetReconciler) getMinRequeueAfter(applicationSetInfo *argoprojiov1alpha1.ApplicationSet) time.Duration {
 	var res time.Duration
 	for _, requestedGenerator := range applicationSetInfo.Spec.Generators {
@@ -405,112 +887,122 @@ func (r *ApplicationSetReconciler) getMinRequeueAfter(applicationSetInfo *argopr
 				res = t
 			}
 		}
+
+		if requestedGenerator.Schedule != nil {
+			t := generators.GetScheduleRequeueAfter(requestedGenerator.Schedule, time.Now())
+			if res == 0 || (t != 0 && t < res) {
+				res = t
+			}
+		}
 	}
 
 	return res
 }
 
-func getTempApplication(applicationSetTemplate argoprojiov1alpha1.ApplicationSetTemplate) *argov1alpha1.Application {
-	var tmplApplication argov1alpha1.Application
-	tmplApplication.Annotations = applicationSetTemplate.Annotations
-	tmplApplication.Labels = applicationSetTemplate.Labels
-	tmplApplication.Namespace = applicationSetTemplate.Namespace
-	tmplApplication.Name = applicationSetTemplate.Name
-	tmplApplication.Spec = applicationSetTemplate.Spec
-	tmplApplication.Finalizers = applicationSetTemplate.Finalizers
-
-	return &tmplApplication
+// generateApplications renders the Applications of the ApplicationSet from the param sets of its generators, and
+// returns them with the summary of the param sets, which is nil if the generators failed.
+func (r *ApplicationSetReconciler) generateApplications(ctx context.Context, applicationSetInfo argoprojiov1alpha1.ApplicationSet) ([]argov1alpha1.Application, *argoprojiov1alpha1.ApplicationSetParametersStatus, argoprojiov1alpha1.ApplicationSetReasonType, error) {
+	return r.generateApplicationsByChunks(ctx, applicationSetInfo, nil)
 }
 
-func (r *ApplicationSetReconciler) generateApplications(applicationSetInfo argoprojiov1alpha1.ApplicationSet) ([]argov1alpha1.Application, argoprojiov1alpha1.ApplicationSetReasonType, error) {
-	var res []argov1alpha1.Application
-
-	var firstError error
-	var applicationSetReason argoprojiov1alpha1.ApplicationSetReasonType
+// generateApplicationsByChunks renders the Applications of the ApplicationSet like generateApplications. If chunks is
+// set, the Applications are passed to it by chunks as soon as they are rendered, and only their names are returned.
+func (r *ApplicationSetReconciler) generateApplicationsByChunks(ctx context.Context, applicationSetInfo argoprojiov1alpha1.ApplicationSet, chunks *chunkedApply) ([]argov1alpha1.Application, *argoprojiov1alpha1.ApplicationSetParametersStatus, argoprojiov1alpha1.ApplicationSetReasonType, error) {
+	// The sensitive values are recorded again by the generators.
+	sensitiveValues := r.SensitiveValues.Values(applicationSetInfo.Namespace, applicationSetInfo.Name)
+	r.SensitiveValues.Reset(applicationSetInfo.Namespace, applicationSetInfo.Name)
 
-	for _, requestedGenerator := range applicationSetInfo.Spec.Generators {
-		t, err := generators.Transform(requestedGenerator, r.Generators, applicationSetInfo.Spec.Template, &applicationSetInfo)
-		if err != nil {
-			log.WithError(err).WithField("generator", requestedGenerator).
-				Error("error generating application from params")
-			if firstError == nil {
-				firstError = err
-				applicationSetReason = argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError
-			}
-			continue
-		}
-
-		for _, a := range t {
-			tmplApplication := getTempApplication(a.Template)
-
-			for _, p := range a.Params {
-				app, err := r.Renderer.RenderTemplateParams(tmplApplication, applicationSetInfo.Spec.SyncPolicy, p)
-				if err != nil {
-					log.WithError(err).WithField("params", a.Params).WithField("generator", requestedGenerator).
-						Error("error generating application from params")
-
-					if firstError == nil {
-						firstError = err
-						applicationSetReason = argoprojiov1alpha1.ApplicationSetReasonRenderTemplateParamsError
-					}
-					continue
-				}
-				res = append(res, *app)
-			}
-		}
-
-		log.WithField("generator", requestedGenerator).Infof("generated %d applications", len(res))
-		log.WithField("generator", requestedGenerator).Debugf("apps from generator: %+v", res)
+	if applicationSetInfo.HardRefreshRequired() {
+		utils.LoggerFromContext(ctx).Info("hard refresh requested, discarding the last known parameters of the generators")
+		r.paramsCache.delete(generatedParamsCacheKey(&applicationSetInfo))
 	}
 
-	return res, applicationSetReason, firstError
+	opts := engine.Options{
+		Generators:      r.Generators,
+		Renderer:        r.Renderer,
+		LastKnownParams: &r.paramsCache,
+		MaxParamSets:    r.MaxParamSets,
+	}
+	var result *engine.Result
+	var err error
+	if chunks != nil {
+		result, err = engine.StreamApplications(ctx, &applicationSetInfo, opts, r.ApplyChunkSize, chunks.apply)
+		result.Applications = chunks.generated
+	} else {
+		result, err = engine.GenerateApplications(ctx, &applicationSetInfo, opts)
+	}
+	var staleErr *engine.StaleParamsError
+	if errors.As(err, &staleErr) {
+		// The sensitive values of the last known parameters weren't recorded again
+		r.SensitiveValues.Add(applicationSetInfo.Namespace, applicationSetInfo.Name, sensitiveValues...)
+	}
+	return result.Applications, result.Parameters, result.Reason, err
 }
 
 func (r *ApplicationSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &argov1alpha1.Application{}, ".metadata.controller", func(rawObj client.Object) []string {
-		// grab the job object, extract the owner...
-		app := rawObj.(*argov1alpha1.Application)
-		owner := metav1.GetControllerOf(app)
-		if owner == nil {
-			return nil
-		}
-		// ...make sure it's a application set...
-		if owner.APIVersion != argoprojiov1alpha1.GroupVersion.String() || owner.Kind != "ApplicationSet" {
-			return nil
-		}
-
-		// ...and if so, return it
-		return []string{owner.Name}
-	}); err != nil {
+	if err := IndexTrackedApplications(context.TODO(), mgr.GetFieldIndexer()); err != nil {
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		For(&argoprojiov1alpha1.ApplicationSet{}).
 		Owns(&argov1alpha1.Application{}).
+		Watches(
+			&source.Kind{Type: &argov1alpha1.Application{}},
+			handler.EnqueueRequestsFromMapFunc(annotationTrackingApplicationSet)).
 		Watches(
 			&source.Kind{Type: &corev1.Secret{}},
 			&clusterSecretEventHandler{
 				Client: mgr.GetClient(),
 				Log:    log.WithField("type", "createSecretEventHandler"),
 			}).
-		// TODO: also watch Applications and respond on changes if we own them.
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			&secretRefEventHandler{
+				Client: mgr.GetClient(),
+				Log:    log.WithField("type", "secretRefEventHandler"),
+			})
+	if r.ClusterDecisionResourceWatcher != nil {
+		builder = builder.Watches(r.ClusterDecisionResourceWatcher.Source(), &handler.EnqueueRequestForObject{})
+	}
+	// TODO: also watch Applications and respond on changes if we own them.
+	return builder.Complete(r)
+}
+
+// annotationTrackingApplicationSet returns the ApplicationSet tracking the Application with the tracking ID
+// annotation, the Applications owned by an ApplicationSet being already watched as owned resources.
+func annotationTrackingApplicationSet(obj client.Object) []ctrl.Request {
+	namespace, name, ok := utils.ParseTrackingID(obj.GetAnnotations()[common.AnnotationApplicationSetTrackingID])
+	if !ok {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
 }
 
 // createOrUpdateInCluster will create / update application resources in the cluster.
 // - For new applications, it will call create
 // - For existing application, it will call update
-// The function also adds owner reference to all applications, and uses it to delete them.
+// The function also tracks all applications according to the tracking method, which is used to delete them.
 func (r *ApplicationSetReconciler) createOrUpdateInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, desiredApplications []argov1alpha1.Application) error {
 
 	var firstError error
+	history := historyFromContext(ctx)
 	// Creates or updates the application in appList
 	for _, generatedApp := range desiredApplications {
 
-		appLog := log.WithFields(log.Fields{"app": generatedApp.Name, "appSet": applicationSet.Name})
+		appLog := utils.LoggerFromContext(ctx).WithField("app", generatedApp.Name)
 		generatedApp.Namespace = applicationSet.Namespace
 
+		// The hashes of the existing Application are only needed by the history
+		var previous *argov1alpha1.Application
+		if history != nil {
+			previous = &argov1alpha1.Application{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: generatedApp.Namespace, Name: generatedApp.Name}, previous); err != nil {
+				previous = nil
+			}
+		}
+
 		found := &argov1alpha1.Application{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      generatedApp.Name,
@@ -522,38 +1014,269 @@ func (r *ApplicationSetReconciler) createOrUpdateInCluster(ctx context.Context,
 			},
 		}
 
-		action, err := utils.CreateOrUpdate(ctx, r.Client, found, func() error {
-			// Copy only the Application/ObjectMeta fields that are significant, from the generatedApp
-			found.Spec = generatedApp.Spec
-
-			// Preserve argo cd notifications state (https://github.com/argoproj-labs/applicationset/issues/180)
-			if state, exists := found.ObjectMeta.Annotations[NotifiedAnnotationKey]; exists {
-				if generatedApp.Annotations == nil {
-					generatedApp.Annotations = map[string]string{}
-				}
-				generatedApp.Annotations[NotifiedAnnotationKey] = state
-			}
-			found.ObjectMeta.Annotations = generatedApp.Annotations
-
-			found.ObjectMeta.Finalizers = generatedApp.Finalizers
-			found.ObjectMeta.Labels = generatedApp.Labels
-			return controllerutil.SetControllerReference(&applicationSet, found, r.Scheme)
-		})
+		action, err := r.createOrUpdate(ctx, r.Client, applicationSet, found, &generatedApp)
 
 		if err != nil {
 			appLog.WithError(err).WithField("action", action).Errorf("failed to %s Application", action)
+			reason := argoprojiov1alpha1.ApplicationSetReasonUpdateApplicationError
+			if found.ResourceVersion == "" {
+				reason = argoprojiov1alpha1.ApplicationSetReasonCreateApplicationError
+			}
+			r.recordEvent(&applicationSet, corev1.EventTypeWarning, applicationErrorReason(err, reason), "Failed to create or update Application %q: %v", generatedApp.Name, err)
 			if firstError == nil {
 				firstError = err
 			}
 			continue
 		}
 
-		r.Recorder.Eventf(&applicationSet, corev1.EventTypeNormal, fmt.Sprint(action), "%s Application %q", action, generatedApp.Name)
+		// The unchanged Applications are not recorded, so that the events only tell what the controller did
+		if action != controllerutil.OperationResultNone {
+			r.recordEvent(&applicationSet, corev1.EventTypeNormal, fmt.Sprint(action), "%s Application %q", action, generatedApp.Name)
+		}
+		switch action {
+		case controllerutil.OperationResultCreated:
+			history.add(argoprojiov1alpha1.ApplicationSetDryRunActionCreate, generatedApp.Name, previous, found)
+		case controllerutil.OperationResultUpdated:
+			history.add(argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, generatedApp.Name, previous, found)
+		}
 		appLog.Logf(log.InfoLevel, "%s Application", action)
 	}
 	return firstError
 }
 
+// applyGeneratedApplication copies the significant fields of the generated Application to the Application found in
+// the cluster, and tracks it as an Application of the ApplicationSet, according to the tracking method.
+func (r *ApplicationSetReconciler) applyGeneratedApplication(applicationSet argoprojiov1alpha1.ApplicationSet, found *argov1alpha1.Application, generatedApp *argov1alpha1.Application) error {
+	trackingID := utils.TrackingID(&applicationSet)
+
+	// An existing Application which is not owned by any ApplicationSet is adopted, unless the ApplicationSet refuses it
+	if found.ResourceVersion != "" && metav1.GetControllerOf(found) == nil {
+		existingTrackingID, tracked := found.Annotations[common.AnnotationApplicationSetTrackingID]
+		if tracked && existingTrackingID != trackingID {
+			return &applicationNameConflictError{err: fmt.Errorf("application %s already exists and is tracked by another ApplicationSet %s", found.Name, existingTrackingID)}
+		}
+		if !tracked && !applicationSet.AdoptExisting() {
+			return &applicationNameConflictError{err: fmt.Errorf("application %s already exists and is not owned by an ApplicationSet, and syncPolicy.adoptExisting is false", found.Name)}
+		}
+	}
+
+	// Leave an Application rendered the same as when it was last applied untouched, unless the ApplicationSet is
+	// refreshed, so that the changes made to it since then are only reverted on demand
+	var renderedHash string
+	if r.SkipUnchangedApplications || applicationSet.HistoryLimit() > 0 {
+		var err error
+		renderedHash, err = utils.RenderedHash(generatedApp)
+		if err != nil {
+			return err
+		}
+		if r.SkipUnchangedApplications && found.ResourceVersion != "" && found.Annotations[common.AnnotationApplicationSetRenderedHash] == renderedHash &&
+			!applicationSet.RefreshRequired() && r.isTracked(&applicationSet, found) {
+			return nil
+		}
+	}
+
+	// Leave the preserved fields, and the fields whose differences are ignored, of an existing Application untouched
+	if found.ResourceVersion != "" {
+		if err := utils.PreserveFields(found, generatedApp, applicationSet.Spec.PreservedFields); err != nil {
+			return err
+		}
+		if err := utils.IgnoreDifferences(applicationSet.Spec.IgnoreApplicationDifferences, found, generatedApp); err != nil {
+			return err
+		}
+	}
+
+	// Copy only the Application/ObjectMeta fields that are significant, from the generatedApp
+	found.Spec = generatedApp.Spec
+
+	// Preserve argo cd notifications state (https://github.com/argoproj-labs/applicationset/issues/180)
+	if state, exists := found.ObjectMeta.Annotations[NotifiedAnnotationKey]; exists {
+		if generatedApp.Annotations == nil {
+			generatedApp.Annotations = map[string]string{}
+		}
+		generatedApp.Annotations[NotifiedAnnotationKey] = state
+	}
+	if renderedHash != "" {
+		if generatedApp.Annotations == nil {
+			generatedApp.Annotations = map[string]string{}
+		}
+		generatedApp.Annotations[common.AnnotationApplicationSetRenderedHash] = renderedHash
+	}
+	found.ObjectMeta.Annotations = generatedApp.Annotations
+
+	found.ObjectMeta.Finalizers = generatedApp.Finalizers
+	found.ObjectMeta.Labels = generatedApp.Labels
+
+	if r.TrackingMethod != utils.TrackingMethodAnnotation {
+		delete(found.ObjectMeta.Annotations, common.AnnotationApplicationSetTrackingID)
+		err := controllerutil.SetControllerReference(&applicationSet, found, r.Scheme)
+		var alreadyOwnedErr *controllerutil.AlreadyOwnedError
+		if errors.As(err, &alreadyOwnedErr) {
+			return &applicationNameConflictError{err: err}
+		}
+		return err
+	}
+
+	// The owner reference of an Application previously tracked with owner references is removed, so that it is not
+	// deleted with the ApplicationSet anymore
+	if owner := metav1.GetControllerOf(found); owner != nil && owner.UID != applicationSet.UID {
+		return &applicationNameConflictError{err: fmt.Errorf("application %s is already owned by another controller %s", found.Name, owner.Name)}
+	}
+	found.OwnerReferences = withoutOwnerReference(found.OwnerReferences, applicationSet.UID)
+	if found.ObjectMeta.Annotations == nil {
+		found.ObjectMeta.Annotations = map[string]string{}
+	}
+	found.ObjectMeta.Annotations[common.AnnotationApplicationSetTrackingID] = trackingID
+	return nil
+}
+
+// isTracked returns true if the Application is tracked by the ApplicationSet with the tracking method of the controller.
+func (r *ApplicationSetReconciler) isTracked(applicationSet *argoprojiov1alpha1.ApplicationSet, app *argov1alpha1.Application) bool {
+	if r.TrackingMethod == utils.TrackingMethodAnnotation {
+		return metav1.GetControllerOf(app) == nil && app.Annotations[common.AnnotationApplicationSetTrackingID] == utils.TrackingID(applicationSet)
+	}
+	return metav1.IsControlledBy(app, applicationSet)
+}
+
+// dryRunInCluster returns the actions of the changes which would be made to the Applications of the ApplicationSet
+// according to the policy, without making them, and records them as events.
+func (r *ApplicationSetReconciler) dryRunInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, validApps []argov1alpha1.Application, desiredApplications []argov1alpha1.Application, policy utils.Policy) ([]argoprojiov1alpha1.ApplicationSetDryRunAction, error) {
+	changes, err := r.planInCluster(ctx, applicationSet, validApps, desiredApplications, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []argoprojiov1alpha1.ApplicationSetDryRunAction
+	for _, change := range changes {
+		actions = append(actions, argoprojiov1alpha1.ApplicationSetDryRunAction{Application: change.Name, Action: change.Action})
+	}
+
+	for _, action := range actions {
+		r.recordEvent(&applicationSet, corev1.EventTypeNormal, "DryRun", "Would %s Application %q", action.Action, action.Application)
+		utils.LoggerFromContext(ctx).WithField("app", action.Application).Infof("dry run: would %s Application", action.Action)
+	}
+	return actions, nil
+}
+
+// setApplicationSetDryRunActions sets the dry run actions of the status of the ApplicationSet, if they changed.
+func (r *ApplicationSetReconciler) setApplicationSetDryRunActions(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, actions []argoprojiov1alpha1.ApplicationSetDryRunAction) error {
+	if len(actions) == 0 && len(applicationSet.Status.DryRunActions) == 0 {
+		return nil
+	}
+	if reflect.DeepEqual(actions, applicationSet.Status.DryRunActions) {
+		return nil
+	}
+
+	return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.DryRunActions = actions
+	})
+}
+
+// updateApplicationSetStatus fetches the ApplicationSet, and updates its status with the given function.
+func (r *ApplicationSetReconciler) updateApplicationSetStatus(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, update func(status *argoprojiov1alpha1.ApplicationSetStatus)) error {
+	// fetch updated Application Set object before updating it
+	namespacedName := types.NamespacedName{Namespace: applicationSet.Namespace, Name: applicationSet.Name}
+	if err := r.Get(ctx, namespacedName, applicationSet); err != nil {
+		return fmt.Errorf("error fetching updated application set: %v", err)
+	}
+
+	update(&applicationSet.Status)
+	if err := r.Client.Status().Update(ctx, applicationSet); err != nil && !apierr.IsNotFound(err) {
+		return fmt.Errorf("unable to update application set status: %v", err)
+	}
+	return nil
+}
+
+// applyStrategy returns the Applications to create or update, according to the strategy of the ApplicationSet, and
+// records the progress of the rollout in the status of the ApplicationSet.
+func (r *ApplicationSetReconciler) applyStrategy(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, validApps []argov1alpha1.Application) ([]argov1alpha1.Application, error) {
+	strategy := applicationSet.Spec.Strategy
+	if strategy == nil || strategy.Type == "" || strategy.Type == argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce {
+		if applicationSet.Status.Rollout == nil {
+			return validApps, nil
+		}
+		return validApps, r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+			status.Rollout = nil
+		})
+	}
+	if strategy.Type != argoprojiov1alpha1.ApplicationSetStrategyTypeRollingSync {
+		return nil, fmt.Errorf("unknown strategy type %s, must be one of AllAtOnce, RollingSync", strategy.Type)
+	}
+
+	current, err := r.getCurrentApplications(ctx, *applicationSet)
+	if err != nil {
+		return nil, err
+	}
+
+	res, currentStep, err := utils.ApplyRollingSync(strategy.RollingSync, validApps, current)
+	if err != nil {
+		return nil, err
+	}
+
+	if applicationSet.Status.Rollout != nil && applicationSet.Status.Rollout.CurrentStep == currentStep {
+		return res, nil
+	}
+	r.recordEvent(applicationSet, corev1.EventTypeNormal, "RollingSync", "Rolling out step %d", currentStep)
+	utils.LoggerFromContext(ctx).WithField("step", currentStep).Info("rolling out step")
+	return res, r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.Rollout = &argoprojiov1alpha1.ApplicationSetRolloutStatus{CurrentStep: currentStep}
+	})
+}
+
+// limitUpdates returns the Applications to create or update in this reconciliation, according to the maximum number of
+// updates of the strategy of the ApplicationSet: the unchanged Applications, and the first Applications to create or
+// update. The other Applications are left as they are, until the next reconciliations.
+func (r *ApplicationSetReconciler) limitUpdates(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, validApps []argov1alpha1.Application, policy utils.Policy) ([]argov1alpha1.Application, error) {
+	if applicationSet.Spec.Strategy == nil || applicationSet.Spec.Strategy.MaxUpdate == nil {
+		return validApps, nil
+	}
+
+	maxUpdate, err := intstr.GetScaledValueFromIntOrPercent(applicationSet.Spec.Strategy.MaxUpdate, len(validApps), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxUpdate: %v", err)
+	}
+	if maxUpdate < 0 {
+		return nil, fmt.Errorf("invalid maxUpdate %s: must not be negative", applicationSet.Spec.Strategy.MaxUpdate.String())
+	}
+
+	var res []argov1alpha1.Application
+	updates := 0
+	for _, generatedApp := range validApps {
+		app := generatedApp
+		app.Namespace = applicationSet.Namespace
+		found := &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      app.Name,
+				Namespace: app.Namespace,
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Application",
+				APIVersion: "argoproj.io/v1alpha1",
+			},
+		}
+
+		operation, err := utils.ComputeOperation(ctx, r.Client, found, func() error {
+			return r.applyGeneratedApplication(applicationSet, found, &app)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Updates which the policy doesn't allow are not counted
+		if operation == controllerutil.OperationResultCreated || (operation == controllerutil.OperationResultUpdated && policy.Update()) {
+			if updates >= maxUpdate {
+				continue
+			}
+			updates++
+		}
+		res = append(res, generatedApp)
+	}
+
+	if len(res) < len(validApps) {
+		utils.LoggerFromContext(ctx).Infof("%d Applications are not created or updated in this reconciliation, as the maximum number of updates is %d", len(validApps)-len(res), maxUpdate)
+	}
+	return res, nil
+}
+
 // createInCluster will filter from the desiredApplications only the application that needs to be created
 // Then it will call createOrUpdateInCluster to do the actual create
 func (r *ApplicationSetReconciler) createInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, desiredApplications []argov1alpha1.Application) error {
@@ -596,17 +1319,18 @@ func (r *ApplicationSetReconciler) getCurrentApplications(_ context.Context, app
 
 // deleteInCluster will delete Applications that are currently on the cluster, but not in appList.
 // The function must be called after all generators had been called and generated applications
-func (r *ApplicationSetReconciler) deleteInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, desiredApplications []argov1alpha1.Application) error {
+// If the ApplicationSet deletes its Applications by batches, it returns the delay before deleting the next batch.
+func (r *ApplicationSetReconciler) deleteInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, desiredApplications []argov1alpha1.Application) (time.Duration, error) {
 
 	clusterList, err := utils.ListClusters(ctx, r.KubeClientset, applicationSet.Namespace)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Save current applications to be able to delete the ones that are not in appList
 	current, err := r.getCurrentApplications(ctx, applicationSet)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	m := make(map[string]bool) // Will holds the app names in appList for the deletion process
@@ -615,37 +1339,138 @@ func (r *ApplicationSetReconciler) deleteInCluster(ctx context.Context, applicat
 		m[app.Name] = true
 	}
 
-	// Delete apps that are not in m[string]bool
-	var firstError error
+	var deletions []argov1alpha1.Application
+	var protected []string
 	for _, app := range current {
-		appLog := log.WithFields(log.Fields{"app": app.Name, "appSet": applicationSet.Name})
-		_, exists := m[app.Name]
+		if m[app.Name] {
+			continue
+		}
+		if isDeletionProtected(app) {
+			protected = append(protected, app.Name)
+			continue
+		}
+		deletions = append(deletions, app)
+	}
+	maxDeletionPercentage := int64(100)
+	if r.MaxDeletionPercentage != nil {
+		maxDeletionPercentage = *r.MaxDeletionPercentage
+	}
+	maxDeletionPercentage = applicationSet.MaxDeletionPercentage(maxDeletionPercentage)
+	if int64(len(deletions))*100 > maxDeletionPercentage*int64(len(current)) {
+		err := fmt.Errorf("%w: %d of the %d Applications would be deleted, more than the maximum of %d%%", ErrDeletionThresholdExceeded, len(deletions), len(current), maxDeletionPercentage)
+		r.recordEvent(&applicationSet, corev1.EventTypeWarning, argoprojiov1alpha1.ApplicationSetReasonDeletionThresholdExceeded, "Refused to delete Applications: %v", err)
+		utils.LoggerFromContext(ctx).Warn(err.Error())
+		return 0, err
+	}
 
-		if !exists {
+	// The threshold applies to all the apps to delete, not only to the current batch
+	batch, requeueAfter, err := r.getDeletionBatch(ctx, &applicationSet, deletions)
+	if err != nil {
+		return 0, err
+	}
 
-			// Removes the Argo CD resources finalizer if the application contains an invalid target (eg missing cluster)
-			err := r.removeFinalizerOnInvalidDestination(ctx, applicationSet, &app, clusterList, appLog)
-			if err != nil {
-				appLog.WithError(err).Error("failed to update Application")
-				if firstError != nil {
-					firstError = err
-				}
-				continue
+	// Delete apps that are not in m[string]bool
+	var firstError error
+	for _, app := range batch {
+		appLog := utils.LoggerFromContext(ctx).WithField("app", app.Name)
+
+		// Removes the Argo CD resources finalizer if the application contains an invalid target (eg missing cluster)
+		err := r.removeFinalizerOnInvalidDestination(ctx, applicationSet, &app, clusterList, appLog)
+		if err != nil {
+			appLog.WithError(err).Error("failed to update Application")
+			if firstError == nil {
+				firstError = err
 			}
+			continue
+		}
 
-			err = r.Client.Delete(ctx, &app)
-			if err != nil {
-				appLog.WithError(err).Error("failed to delete Application")
-				if firstError != nil {
-					firstError = err
-				}
-				continue
+		err = r.Client.Delete(ctx, &app)
+		if err != nil {
+			appLog.WithError(err).Error("failed to delete Application")
+			r.recordEvent(&applicationSet, corev1.EventTypeWarning, argoprojiov1alpha1.ApplicationSetReasonDeleteApplicationError, "Failed to delete Application %q: %v", app.Name, err)
+			if firstError == nil {
+				firstError = err
 			}
-			r.Recorder.Eventf(&applicationSet, corev1.EventTypeNormal, "Deleted", "Deleted Application %q", app.Name)
-			appLog.Log(log.InfoLevel, "Deleted application")
+			continue
 		}
+		historyFromContext(ctx).add(argoprojiov1alpha1.ApplicationSetDryRunActionDelete, app.Name, &app, nil)
+		r.recordEvent(&applicationSet, corev1.EventTypeNormal, "Deleted", "Deleted Application %q", app.Name)
+		appLog.Log(log.InfoLevel, "Deleted application")
 	}
-	return firstError
+	if firstError == nil && len(protected) > 0 {
+		err := &deletionProtectedError{names: protected}
+		r.recordEvent(&applicationSet, corev1.EventTypeWarning, argoprojiov1alpha1.ApplicationSetReasonDeletionProtected, "Skipped the deletion of Applications: %v", err)
+		utils.LoggerFromContext(ctx).Warn(err.Error())
+		return requeueAfter, err
+	}
+	return requeueAfter, firstError
+}
+
+// isDeletionProtected returns true if the Application is not deleted when its ApplicationSet no longer generates it.
+func isDeletionProtected(app argov1alpha1.Application) bool {
+	return app.Annotations[argoprojiov1alpha1.DeletionProtectedAnnotation] == "true"
+}
+
+// updatePreserveApplicationsFinalizer adds the preserve Applications finalizer to the ApplicationSet if its
+// Applications are preserved on deletion, and removes it otherwise.
+func (r *ApplicationSetReconciler) updatePreserveApplicationsFinalizer(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) error {
+	hasFinalizer := controllerutil.ContainsFinalizer(applicationSet, argoprojiov1alpha1.PreserveApplicationsFinalizer)
+	if applicationSet.PreserveApplicationsOnDeletion() == hasFinalizer {
+		return nil
+	}
+
+	if hasFinalizer {
+		controllerutil.RemoveFinalizer(applicationSet, argoprojiov1alpha1.PreserveApplicationsFinalizer)
+	} else {
+		controllerutil.AddFinalizer(applicationSet, argoprojiov1alpha1.PreserveApplicationsFinalizer)
+	}
+	return r.Client.Update(ctx, applicationSet)
+}
+
+// orphanApplications removes the owner reference to the ApplicationSet, and the tracking ID annotation, from its
+// Applications, so that they are not deleted with the ApplicationSet, nor tracked by an ApplicationSet recreated with
+// the same name, then removes the preserve Applications finalizer to let the deletion proceed.
+func (r *ApplicationSetReconciler) orphanApplications(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) error {
+	current, err := r.getCurrentApplications(ctx, *applicationSet)
+	if err != nil {
+		return err
+	}
+
+	for i := range current {
+		app := &current[i]
+		appLog := utils.LoggerFromContext(ctx).WithField("app", app.Name)
+
+		ownerReferences := withoutOwnerReference(app.OwnerReferences, applicationSet.UID)
+		tracked := app.Annotations[common.AnnotationApplicationSetTrackingID] == utils.TrackingID(applicationSet)
+		if len(ownerReferences) == len(app.OwnerReferences) && !tracked {
+			continue
+		}
+		app.OwnerReferences = ownerReferences
+		if tracked {
+			delete(app.Annotations, common.AnnotationApplicationSetTrackingID)
+		}
+
+		if err := r.Client.Update(ctx, app); err != nil {
+			appLog.WithError(err).Error("failed to orphan Application")
+			return fmt.Errorf("error orphaning application %s: %v", app.Name, err)
+		}
+		r.recordEvent(applicationSet, corev1.EventTypeNormal, "Orphaned", "Orphaned Application %q", app.Name)
+		appLog.Log(log.InfoLevel, "Orphaned application")
+	}
+
+	controllerutil.RemoveFinalizer(applicationSet, argoprojiov1alpha1.PreserveApplicationsFinalizer)
+	return r.Client.Update(ctx, applicationSet)
+}
+
+// withoutOwnerReference returns the owner references without the reference to the owner with the UID.
+func withoutOwnerReference(ownerReferences []metav1.OwnerReference, uid types.UID) []metav1.OwnerReference {
+	var res []metav1.OwnerReference
+	for _, ownerReference := range ownerReferences {
+		if ownerReference.UID != uid {
+			res = append(res, ownerReference)
+		}
+	}
+	return res
 }
 
 // removeFinalizerOnInvalidDestination removes the Argo CD resources finalizer if the application contains an invalid target (eg missing cluster)
@@ -705,7 +1530,7 @@ func (r *ApplicationSetReconciler) removeFinalizerOnInvalidDestination(ctx conte
 		if len(newFinalizers) != len(app.Finalizers) {
 			app.Finalizers = newFinalizers
 
-			r.Recorder.Eventf(&applicationSet, corev1.EventTypeNormal, "Updated", "Updated Application %q finalizer before deletion, because application has an invalid destination", app.Name)
+			r.recordEvent(&applicationSet, corev1.EventTypeNormal, "Updated", "Updated Application %q finalizer before deletion, because application has an invalid destination", app.Name)
 			appLog.Log(log.InfoLevel, "Updating application finalizer before deletion, because application has an invalid destination")
 
 			err := r.Client.Update(ctx, app, &client.UpdateOptions{})
@@ -719,3 +1544,8 @@ func (r *ApplicationSetReconciler) removeFinalizerOnInvalidDestination(ctx conte
 }
 
 var _ handler.EventHandler = &clusterSecretEventHandler{}
+
+// recordEvent records an event on the ApplicationSet, with the sensitive values redacted from its message.
+func (r *ApplicationSetReconciler) recordEvent(applicationSet *argoprojiov1alpha1.ApplicationSet, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Recorder.Event(applicationSet, eventtype, reason, r.SensitiveValues.Redact(fmt.Sprintf(messageFmt, args...)))
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/argoproj-labs/applicationset/common"
+	"github.com/argoproj-labs/applicationset/pkg/engine"
 	"github.com/argoproj-labs/applicationset/pkg/generators"
 	"github.com/argoproj-labs/applicationset/pkg/utils"
 	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -17,10 +19,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -44,10 +50,10 @@ func (g *generatorMock) GetTemplate(appSetGenerator *argoprojiov1alpha1.Applicat
 	return args.Get(0).(*argoprojiov1alpha1.ApplicationSetTemplate)
 }
 
-func (g *generatorMock) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *generatorMock) GenerateParams(_ context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	args := g.Called(appSetGenerator)
 
-	return args.Get(0).([]map[string]string), args.Error(1)
+	return args.Get(0).([]map[string]interface{}), args.Error(1)
 }
 
 type rendererMock struct {
@@ -60,7 +66,7 @@ func (g *generatorMock) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.Appl
 	return args.Get(0).(time.Duration)
 }
 
-func (r *rendererMock) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]string) (*argov1alpha1.Application, error) {
+func (r *rendererMock) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error) {
 	args := r.Called(tmpl, params)
 
 	if args.Error(1) != nil {
@@ -71,6 +77,16 @@ func (r *rendererMock) RenderTemplateParams(tmpl *argov1alpha1.Application, sync
 
 }
 
+func (r *rendererMock) RenderTemplatePatch(app *argov1alpha1.Application, templatePatch string, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error) {
+	args := r.Called(app, templatePatch, params)
+
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*argov1alpha1.Application), args.Error(1)
+}
+
 func TestExtractApplications(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := argoprojiov1alpha1.AddToScheme(scheme)
@@ -81,16 +97,18 @@ func TestExtractApplications(t *testing.T) {
 
 	for _, c := range []struct {
 		name                string
-		params              []map[string]string
+		params              []map[string]interface{}
 		template            argoprojiov1alpha1.ApplicationSetTemplate
+		templatePatch       *string
 		generateParamsError error
 		rendererError       error
+		templatePatchError  error
 		expectErr           bool
 		expectedReason      v1alpha1.ApplicationSetReasonType
 	}{
 		{
 			name:   "Generate two applications",
-			params: []map[string]string{{"name": "app1"}, {"name": "app2"}},
+			params: []map[string]interface{}{{"name": "app1"}, {"name": "app2"}},
 			template: argoprojiov1alpha1.ApplicationSetTemplate{
 				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
 					Name:      "name",
@@ -109,7 +127,7 @@ func TestExtractApplications(t *testing.T) {
 		},
 		{
 			name:   "Handles error from the render",
-			params: []map[string]string{{"name": "app1"}, {"name": "app2"}},
+			params: []map[string]interface{}{{"name": "app1"}, {"name": "app2"}},
 			template: argoprojiov1alpha1.ApplicationSetTemplate{
 				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
 					Name:      "name",
@@ -122,6 +140,34 @@ func TestExtractApplications(t *testing.T) {
 			expectErr:      true,
 			expectedReason: v1alpha1.ApplicationSetReasonRenderTemplateParamsError,
 		},
+		{
+			name:   "Applies the template patch",
+			params: []map[string]interface{}{{"name": "app1"}, {"name": "app2"}},
+			template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argov1alpha1.ApplicationSpec{},
+			},
+			templatePatch:  pointer.StringPtr("spec: {project: '{{name}}'}"),
+			expectedReason: "",
+		},
+		{
+			name:   "Handles error from the template patch",
+			params: []map[string]interface{}{{"name": "app1"}, {"name": "app2"}},
+			template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argov1alpha1.ApplicationSpec{},
+			},
+			templatePatch:      pointer.StringPtr("spec: ["),
+			templatePatchError: errors.New("error"),
+			expectErr:          true,
+			expectedReason:     v1alpha1.ApplicationSetReasonRenderTemplateParamsError,
+		},
 	} {
 		cc := c
 		app := argov1alpha1.Application{
@@ -129,6 +175,14 @@ func TestExtractApplications(t *testing.T) {
 				Name: "test",
 			},
 		}
+		patchedApp := argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "patched",
+			},
+		}
 
 		t.Run(cc.name, func(t *testing.T) {
 
@@ -157,15 +211,26 @@ func TestExtractApplications(t *testing.T) {
 			var expectedApps []argov1alpha1.Application
 
 			if cc.generateParamsError == nil {
-				for _, p := range cc.params {
+				for _, params := range cc.params {
+					p := withApplicationSetParams(params)
 
 					if cc.rendererError != nil {
-						rendererMock.On("RenderTemplateParams", getTempApplication(cc.template), p).
+						rendererMock.On("RenderTemplateParams", engine.TemplateApplication(cc.template), p).
 							Return(nil, cc.rendererError)
-					} else {
-						rendererMock.On("RenderTemplateParams", getTempApplication(cc.template), p).
-							Return(&app, nil)
+						continue
+					}
+					rendererMock.On("RenderTemplateParams", engine.TemplateApplication(cc.template), p).
+						Return(&app, nil)
+
+					if cc.templatePatch == nil {
 						expectedApps = append(expectedApps, app)
+					} else if cc.templatePatchError != nil {
+						rendererMock.On("RenderTemplatePatch", &app, *cc.templatePatch, p).
+							Return(nil, cc.templatePatchError)
+					} else {
+						rendererMock.On("RenderTemplatePatch", &app, *cc.templatePatch, p).
+							Return(&patchedApp, nil)
+						expectedApps = append(expectedApps, patchedApp)
 					}
 				}
 			}
@@ -181,14 +246,15 @@ func TestExtractApplications(t *testing.T) {
 				KubeClientset: kubefake.NewSimpleClientset(),
 			}
 
-			got, reason, err := r.generateApplications(argoprojiov1alpha1.ApplicationSet{
+			got, _, reason, err := r.generateApplications(context.TODO(), argoprojiov1alpha1.ApplicationSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "name",
 					Namespace: "namespace",
 				},
 				Spec: argoprojiov1alpha1.ApplicationSetSpec{
-					Generators: []argoprojiov1alpha1.ApplicationSetGenerator{generator},
-					Template:   cc.template,
+					Generators:    []argoprojiov1alpha1.ApplicationSetGenerator{generator},
+					Template:      cc.template,
+					TemplatePatch: cc.templatePatch,
 				},
 			})
 
@@ -204,6 +270,9 @@ func TestExtractApplications(t *testing.T) {
 			if cc.generateParamsError == nil {
 				rendererMock.AssertNumberOfCalls(t, "RenderTemplateParams", len(cc.params))
 			}
+			if cc.templatePatch != nil {
+				rendererMock.AssertNumberOfCalls(t, "RenderTemplatePatch", len(cc.params))
+			}
 
 		})
 	}
@@ -219,7 +288,7 @@ func TestMergeTemplateApplications(t *testing.T) {
 
 	for _, c := range []struct {
 		name             string
-		params           []map[string]string
+		params           []map[string]interface{}
 		template         argoprojiov1alpha1.ApplicationSetTemplate
 		overrideTemplate argoprojiov1alpha1.ApplicationSetTemplate
 		expectedMerged   argoprojiov1alpha1.ApplicationSetTemplate
@@ -227,7 +296,7 @@ func TestMergeTemplateApplications(t *testing.T) {
 	}{
 		{
 			name:   "Generate app",
-			params: []map[string]string{{"name": "app1"}},
+			params: []map[string]interface{}{{"name": "app1"}},
 			template: argoprojiov1alpha1.ApplicationSetTemplate{
 				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
 					Name:      "name",
@@ -280,7 +349,7 @@ func TestMergeTemplateApplications(t *testing.T) {
 
 			rendererMock := rendererMock{}
 
-			rendererMock.On("RenderTemplateParams", getTempApplication(cc.expectedMerged), cc.params[0]).
+			rendererMock.On("RenderTemplateParams", engine.TemplateApplication(cc.expectedMerged), withApplicationSetParams(cc.params[0])).
 				Return(&cc.expectedApps[0], nil)
 
 			r := ApplicationSetReconciler{
@@ -294,7 +363,7 @@ func TestMergeTemplateApplications(t *testing.T) {
 				KubeClientset: kubefake.NewSimpleClientset(),
 			}
 
-			got, _, _ := r.generateApplications(argoprojiov1alpha1.ApplicationSet{
+			got, _, _, _ := r.generateApplications(context.TODO(), argoprojiov1alpha1.ApplicationSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "name",
 					Namespace: "namespace",
@@ -312,6 +381,200 @@ func TestMergeTemplateApplications(t *testing.T) {
 
 }
 
+func TestGenerateApplicationsWithTemplateSelector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = argoprojiov1alpha1.AddToScheme(scheme)
+	_ = argov1alpha1.AddToScheme(scheme)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	template := argoprojiov1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}"},
+		Spec:                       argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+	templates := map[string]argoprojiov1alpha1.ApplicationSetTemplate{
+		"frontend": {
+			ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}-frontend"},
+			Spec:                       argov1alpha1.ApplicationSpec{Project: "frontend"},
+		},
+		"backend": {
+			ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}-backend"},
+			Spec:                       argov1alpha1.ApplicationSpec{Project: "backend"},
+		},
+	}
+
+	for _, c := range []struct {
+		name              string
+		params            []map[string]interface{}
+		expectedTemplates []argoprojiov1alpha1.ApplicationSetTemplate
+		expectedError     string
+		expectedReason    v1alpha1.ApplicationSetReasonType
+	}{
+		{
+			name:              "Select the named templates",
+			params:            []map[string]interface{}{{"name": "app1", "type": "frontend"}, {"name": "app2", "type": "backend"}},
+			expectedTemplates: []argoprojiov1alpha1.ApplicationSetTemplate{templates["frontend"], templates["backend"]},
+		},
+		{
+			name:              "Empty selection uses the template",
+			params:            []map[string]interface{}{{"name": "app1", "type": ""}},
+			expectedTemplates: []argoprojiov1alpha1.ApplicationSetTemplate{template},
+		},
+		{
+			name:           "Undefined template",
+			params:         []map[string]interface{}{{"name": "app1", "type": "database"}},
+			expectedError:  "template database selected by the template selector is not defined",
+			expectedReason: v1alpha1.ApplicationSetReasonRenderTemplateParamsError,
+		},
+	} {
+		cc := c
+
+		t.Run(cc.name, func(t *testing.T) {
+
+			generatorMock := generatorMock{}
+			generator := argoprojiov1alpha1.ApplicationSetGenerator{
+				List: &argoprojiov1alpha1.ListGenerator{},
+			}
+
+			generatorMock.On("GenerateParams", &generator).
+				Return(cc.params, nil)
+
+			generatorMock.On("GetTemplate", &generator).
+				Return(&argoprojiov1alpha1.ApplicationSetTemplate{})
+
+			rendererMock := rendererMock{}
+
+			var expectedApps []argov1alpha1.Application
+			for i, expectedTemplate := range cc.expectedTemplates {
+				app := argov1alpha1.Application{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app%d", i+1)},
+					Spec:       argov1alpha1.ApplicationSpec{Project: expectedTemplate.Spec.Project},
+				}
+				rendererMock.On("RenderTemplateParams", engine.TemplateApplication(expectedTemplate), withApplicationSetParams(cc.params[i])).
+					Return(&app, nil)
+				expectedApps = append(expectedApps, app)
+			}
+
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(1),
+				Generators: map[string]generators.Generator{
+					"List": &generatorMock,
+				},
+				Renderer:      &rendererMock,
+				KubeClientset: kubefake.NewSimpleClientset(),
+			}
+
+			got, _, reason, err := r.generateApplications(context.TODO(), argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Generators:       []argoprojiov1alpha1.ApplicationSetGenerator{generator},
+					Template:         template,
+					Templates:        templates,
+					TemplateSelector: "{{ .type }}",
+				},
+			})
+
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, expectedApps, got)
+			assert.Equal(t, cc.expectedReason, reason)
+			rendererMock.AssertNumberOfCalls(t, "RenderTemplateParams", len(cc.expectedTemplates))
+		})
+	}
+}
+
+func TestGenerateApplicationsWithOrder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = argoprojiov1alpha1.AddToScheme(scheme)
+	_ = argov1alpha1.AddToScheme(scheme)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	params := []map[string]interface{}{
+		{"name": "app-prod", "env": "prod"},
+		{"name": "app-dev", "env": "dev"},
+		{"name": "app-staging", "env": "staging"},
+	}
+
+	generatorMock := generatorMock{}
+	generator := argoprojiov1alpha1.ApplicationSetGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{},
+	}
+	generatorMock.On("GenerateParams", &generator).
+		Return(params, nil)
+	generatorMock.On("GetTemplate", &generator).
+		Return(&argoprojiov1alpha1.ApplicationSetTemplate{})
+
+	template := argoprojiov1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}"},
+		Spec:                       argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+
+	rendererMock := rendererMock{}
+	apps := map[string]argov1alpha1.Application{}
+	for _, p := range params {
+		app := argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: p["name"].(string)},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "default"},
+		}
+		rendererMock.On("RenderTemplateParams", engine.TemplateApplication(template), withApplicationSetParams(p)).
+			Return(&app, nil)
+		apps[app.Name] = app
+	}
+
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(1),
+		Generators: map[string]generators.Generator{
+			"List": &generatorMock,
+		},
+		Renderer:      &rendererMock,
+		KubeClientset: kubefake.NewSimpleClientset(),
+	}
+
+	got, _, _, err := r.generateApplications(context.TODO(), argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{generator},
+			Template:   template,
+			Strategy: &argoprojiov1alpha1.ApplicationSetStrategy{
+				Order: &argoprojiov1alpha1.ApplicationSetOrder{
+					Key:    "{{env}}",
+					Values: []string{"dev", "staging", "prod"},
+				},
+			},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []argov1alpha1.Application{apps["app-dev"], apps["app-staging"], apps["app-prod"]}, got)
+}
+
+// withApplicationSetParams returns the params with the params describing the ApplicationSet of the tests, which are
+// added to the generated params.
+func withApplicationSetParams(params map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{
+		"applicationset.name":      "name",
+		"applicationset.namespace": "namespace",
+	}
+	for name, value := range params {
+		res[name] = value
+	}
+	return res
+}
+
 func TestCreateOrUpdateInCluster(t *testing.T) {
 
 	scheme := runtime.NewScheme()
@@ -819,83 +1082,9 @@ func TestCreateOrUpdateInCluster(t *testing.T) {
 				},
 			},
 		},
-	} {
-
-		t.Run(c.name, func(t *testing.T) {
-
-			initObjs := []crtclient.Object{&c.appSet}
-
-			for _, a := range c.existingApps {
-				err = controllerutil.SetControllerReference(&c.appSet, &a, scheme)
-				assert.Nil(t, err)
-				initObjs = append(initObjs, &a)
-			}
-
-			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
-
-			r := ApplicationSetReconciler{
-				Client:   client,
-				Scheme:   scheme,
-				Recorder: record.NewFakeRecorder(len(initObjs) + len(c.expected)),
-			}
-
-			err = r.createOrUpdateInCluster(context.TODO(), c.appSet, c.desiredApps)
-			assert.Nil(t, err)
-
-			for _, obj := range c.expected {
-				got := &argov1alpha1.Application{}
-				_ = client.Get(context.Background(), crtclient.ObjectKey{
-					Namespace: obj.Namespace,
-					Name:      obj.Name,
-				}, got)
-
-				err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
-				assert.Nil(t, err)
-				assert.Equal(t, obj, *got)
-			}
-		})
-	}
-}
-
-func TestRemoveFinalizerOnInvalidDestination_FinalizerTypes(t *testing.T) {
-
-	scheme := runtime.NewScheme()
-	err := argoprojiov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	err = argov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	for _, c := range []struct {
-		// name is human-readable test name
-		name               string
-		existingFinalizers []string
-		expectedFinalizers []string
-	}{
-		{
-			name:               "no finalizers",
-			existingFinalizers: []string{},
-			expectedFinalizers: nil,
-		},
 		{
-			name:               "contains only argo finalizer",
-			existingFinalizers: []string{argov1alpha1.ResourcesFinalizerName},
-			expectedFinalizers: nil,
-		},
-		{
-			name:               "contains only non-argo finalizer",
-			existingFinalizers: []string{"non-argo-finalizer"},
-			expectedFinalizers: []string{"non-argo-finalizer"},
-		},
-		{
-			name:               "contains both argo and non-argo finalizer",
-			existingFinalizers: []string{"non-argo-finalizer", argov1alpha1.ResourcesFinalizerName},
-			expectedFinalizers: []string{"non-argo-finalizer"},
-		},
-	} {
-		t.Run(c.name, func(t *testing.T) {
-
-			appSet := argoprojiov1alpha1.ApplicationSet{
+			name: "Ensure that the preserved fields of an existing app are left untouched",
+			appSet: argoprojiov1alpha1.ApplicationSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "name",
 					Namespace: "namespace",
@@ -906,263 +1095,43 @@ func TestRemoveFinalizerOnInvalidDestination_FinalizerTypes(t *testing.T) {
 							Project: "project",
 						},
 					},
-				},
-			}
-
-			app := argov1alpha1.Application{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:       "app1",
-					Finalizers: c.existingFinalizers,
-				},
-				Spec: argov1alpha1.ApplicationSpec{
-					Project: "project",
-					Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "revision", RepoURL: "repoURL"},
-					// Destination is always invalid, for this test:
-					Destination: argov1alpha1.ApplicationDestination{Name: "my-cluster", Namespace: "namespace"},
-				},
-			}
-
-			initObjs := []crtclient.Object{&app, &appSet}
-
-			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
-			secret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "my-secret",
-					Namespace: "namespace",
-					Labels: map[string]string{
-						generators.ArgoCDSecretTypeLabel: generators.ArgoCDSecretTypeCluster,
+					PreservedFields: []string{
+						"spec.source.targetRevision",
+						"metadata.annotations[argocd-image-updater.argoproj.io/image-list]",
+						"metadata.labels[pinned]",
 					},
 				},
-				Data: map[string][]byte{
-					// Since this test requires the cluster to be an invalid destination, we
-					// always return a cluster named 'my-cluster2' (different from app 'my-cluster', above)
-					"name":   []byte("mycluster2"),
-					"server": []byte("https://kubernetes.default.svc"),
-					"config": []byte("{\"username\":\"foo\",\"password\":\"foo\"}"),
-				},
-			}
-
-			objects := append([]runtime.Object{}, secret)
-			kubeclientset := kubefake.NewSimpleClientset(objects...)
-
-			r := ApplicationSetReconciler{
-				Client:        client,
-				Scheme:        scheme,
-				Recorder:      record.NewFakeRecorder(10),
-				KubeClientset: kubeclientset,
-			}
-
-			clusterList, err := utils.ListClusters(context.Background(), kubeclientset, "namespace")
-			assert.NoError(t, err, "Unexpected error")
-
-			appLog := log.WithFields(log.Fields{"app": app.Name, "appSet": ""})
-
-			appInputParam := app.DeepCopy()
-
-			err = r.removeFinalizerOnInvalidDestination(context.Background(), appSet, appInputParam, clusterList, appLog)
-			assert.NoError(t, err, "Unexpected error")
-
-			retrievedApp := argov1alpha1.Application{}
-			err = client.Get(context.Background(), crtclient.ObjectKeyFromObject(&app), &retrievedApp)
-			assert.NoError(t, err, "Unexpected error")
-
-			// App on the cluster should have the expected finalizers
-			assert.ElementsMatch(t, c.expectedFinalizers, retrievedApp.Finalizers)
-
-			// App object passed in as a parameter should have the expected finaliers
-			assert.ElementsMatch(t, c.expectedFinalizers, appInputParam.Finalizers)
-
-			bytes, _ := json.MarshalIndent(retrievedApp, "", "  ")
-			t.Log("Contents of app after call:", string(bytes))
-
-		})
-	}
-}
-
-func TestRemoveFinalizerOnInvalidDestination_DestinationTypes(t *testing.T) {
-
-	scheme := runtime.NewScheme()
-	err := argoprojiov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	err = argov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	for _, c := range []struct {
-		// name is human-readable test name
-		name                   string
-		destinationField       argov1alpha1.ApplicationDestination
-		expectFinalizerRemoved bool
-	}{
-		{
-			name: "invalid cluster: empty destination",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-			},
-			expectFinalizerRemoved: true,
-		},
-		{
-			name: "invalid cluster: invalid server url",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Server:    "https://1.2.3.4",
-			},
-			expectFinalizerRemoved: true,
-		},
-		{
-			name: "invalid cluster: invalid cluster name",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Name:      "invalid-cluster",
-			},
-			expectFinalizerRemoved: true,
-		},
-		{
-			name: "invalid cluster by both valid",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Name:      "mycluster2",
-				Server:    "https://kubernetes.default.svc",
-			},
-			expectFinalizerRemoved: true,
-		},
-		{
-			name: "invalid cluster by both invalid",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Name:      "mycluster3",
-				Server:    "https://4.5.6.7",
-			},
-			expectFinalizerRemoved: true,
-		},
-		{
-			name: "valid cluster by name",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Name:      "mycluster2",
-			},
-			expectFinalizerRemoved: false,
-		},
-		{
-			name: "valid cluster by server",
-			destinationField: argov1alpha1.ApplicationDestination{
-				Namespace: "namespace",
-				Server:    "https://kubernetes.default.svc",
 			},
-			expectFinalizerRemoved: false,
-		},
-	} {
-
-		t.Run(c.name, func(t *testing.T) {
-
-			appSet := argoprojiov1alpha1.ApplicationSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "name",
-					Namespace: "namespace",
-				},
-				Spec: argoprojiov1alpha1.ApplicationSetSpec{
-					Template: argoprojiov1alpha1.ApplicationSetTemplate{
-						Spec: argov1alpha1.ApplicationSpec{
-							Project: "project",
+			existingApps: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app1",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+						Annotations: map[string]string{
+							"annot-key": "annot-value",
+							"argocd-image-updater.argoproj.io/image-list": "guestbook=example/guestbook",
 						},
 					},
-				},
-			}
-
-			app := argov1alpha1.Application{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:       "app1",
-					Finalizers: []string{argov1alpha1.ResourcesFinalizerName},
-				},
-				Spec: argov1alpha1.ApplicationSpec{
-					Project:     "project",
-					Source:      argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "revision", RepoURL: "repoURL"},
-					Destination: c.destinationField,
-				},
-			}
-
-			initObjs := []crtclient.Object{&app, &appSet}
-
-			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
-			secret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "my-secret",
-					Namespace: "namespace",
-					Labels: map[string]string{
-						generators.ArgoCDSecretTypeLabel: generators.ArgoCDSecretTypeCluster,
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "v1.0.0"},
 					},
 				},
-				Data: map[string][]byte{
-					// Since this test requires the cluster to be an invalid destination, we
-					// always return a cluster named 'my-cluster2' (different from app 'my-cluster', above)
-					"name":   []byte("mycluster2"),
-					"server": []byte("https://kubernetes.default.svc"),
-					"config": []byte("{\"username\":\"foo\",\"password\":\"foo\"}"),
-				},
-			}
-
-			objects := append([]runtime.Object{}, secret)
-			kubeclientset := kubefake.NewSimpleClientset(objects...)
-
-			r := ApplicationSetReconciler{
-				Client:        client,
-				Scheme:        scheme,
-				Recorder:      record.NewFakeRecorder(10),
-				KubeClientset: kubeclientset,
-			}
-
-			clusterList, err := utils.ListClusters(context.Background(), kubeclientset, "namespace")
-			assert.NoError(t, err, "Unexpected error")
-
-			appLog := log.WithFields(log.Fields{"app": app.Name, "appSet": ""})
-
-			appInputParam := app.DeepCopy()
-
-			err = r.removeFinalizerOnInvalidDestination(context.Background(), appSet, appInputParam, clusterList, appLog)
-			assert.NoError(t, err, "Unexpected error")
-
-			retrievedApp := argov1alpha1.Application{}
-			err = client.Get(context.Background(), crtclient.ObjectKeyFromObject(&app), &retrievedApp)
-			assert.NoError(t, err, "Unexpected error")
-
-			finalizerRemoved := len(retrievedApp.Finalizers) == 0
-
-			assert.True(t, c.expectFinalizerRemoved == finalizerRemoved)
-
-			bytes, _ := json.MarshalIndent(retrievedApp, "", "  ")
-			t.Log("Contents of app after call:", string(bytes))
-
-		})
-	}
-}
-
-func TestCreateApplications(t *testing.T) {
-
-	scheme := runtime.NewScheme()
-	err := argoprojiov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	err = argov1alpha1.AddToScheme(scheme)
-	assert.Nil(t, err)
-
-	for _, c := range []struct {
-		appSet     argoprojiov1alpha1.ApplicationSet
-		existsApps []argov1alpha1.Application
-		apps       []argov1alpha1.Application
-		expected   []argov1alpha1.Application
-	}{
-		{
-			appSet: argoprojiov1alpha1.ApplicationSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "name",
-					Namespace: "namespace",
-				},
 			},
-			existsApps: nil,
-			apps: []argov1alpha1.Application{
+			desiredApps: []argov1alpha1.Application{
 				{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "app1",
+						Name:   "app1",
+						Labels: map[string]string{"pinned": "false", "label-key": "label-value"},
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "new-path", TargetRevision: "HEAD"},
 					},
 				},
 			},
@@ -1175,12 +1144,21 @@ func TestCreateApplications(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name:            "app1",
 						Namespace:       "namespace",
-						ResourceVersion: "1",
+						ResourceVersion: "3",
+						Labels:          map[string]string{"label-key": "label-value"},
+						Annotations: map[string]string{
+							"argocd-image-updater.argoproj.io/image-list": "guestbook=example/guestbook",
+						},
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "new-path", TargetRevision: "v1.0.0"},
 					},
 				},
 			},
 		},
 		{
+			name: "Ensure that an existing app is not updated when only ignored fields differ",
 			appSet: argoprojiov1alpha1.ApplicationSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "name",
@@ -1192,9 +1170,13 @@ func TestCreateApplications(t *testing.T) {
 							Project: "project",
 						},
 					},
+					IgnoreApplicationDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+						{JSONPointers: []string{"/spec/source/targetRevision"}},
+						{JQPathExpressions: []string{".metadata.annotations"}},
+					},
 				},
 			},
-			existsApps: []argov1alpha1.Application{
+			existingApps: []argov1alpha1.Application{
 				{
 					TypeMeta: metav1.TypeMeta{
 						Kind:       "Application",
@@ -1204,19 +1186,22 @@ func TestCreateApplications(t *testing.T) {
 						Name:            "app1",
 						Namespace:       "namespace",
 						ResourceVersion: "2",
+						Annotations:     map[string]string{"mutated-by": "webhook"},
 					},
 					Spec: argov1alpha1.ApplicationSpec{
-						Project: "test",
+						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "v1.0.0"},
 					},
 				},
 			},
-			apps: []argov1alpha1.Application{
+			desiredApps: []argov1alpha1.Application{
 				{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "app1",
 					},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "HEAD"},
 					},
 				},
 			},
@@ -1230,257 +1215,1722 @@ func TestCreateApplications(t *testing.T) {
 						Name:            "app1",
 						Namespace:       "namespace",
 						ResourceVersion: "2",
+						Annotations:     map[string]string{"mutated-by": "webhook"},
 					},
 					Spec: argov1alpha1.ApplicationSpec{
-						Project: "test",
-					},
-				},
-			},
-		},
-		{
-			appSet: argoprojiov1alpha1.ApplicationSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "name",
-					Namespace: "namespace",
-				},
-				Spec: argoprojiov1alpha1.ApplicationSetSpec{
-					Template: argoprojiov1alpha1.ApplicationSetTemplate{
-						Spec: argov1alpha1.ApplicationSpec{
-							Project: "project",
-						},
-					},
-				},
-			},
-			existsApps: []argov1alpha1.Application{
-				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "app1",
-						Namespace:       "namespace",
-						ResourceVersion: "2",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "test",
-					},
-				},
-			},
-			apps: []argov1alpha1.Application{
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "app2",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
-				},
-			},
-			expected: []argov1alpha1.Application{
-				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "app2",
-						Namespace:       "namespace",
-						ResourceVersion: "1",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
+						Project: "project",
+						Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "v1.0.0"},
 					},
 				},
 			},
 		},
 	} {
-		initObjs := []crtclient.Object{&c.appSet}
-		for _, a := range c.existsApps {
-			err = controllerutil.SetControllerReference(&c.appSet, &a, scheme)
-			assert.Nil(t, err)
-			initObjs = append(initObjs, &a)
-		}
 
-		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+		t.Run(c.name, func(t *testing.T) {
 
-		r := ApplicationSetReconciler{
-			Client:   client,
-			Scheme:   scheme,
-			Recorder: record.NewFakeRecorder(len(initObjs) + len(c.expected)),
-		}
+			initObjs := []crtclient.Object{&c.appSet}
 
-		err = r.createInCluster(context.TODO(), c.appSet, c.apps)
-		assert.Nil(t, err)
+			for _, a := range c.existingApps {
+				err = controllerutil.SetControllerReference(&c.appSet, &a, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, &a)
+			}
 
-		for _, obj := range c.expected {
-			got := &argov1alpha1.Application{}
-			_ = client.Get(context.Background(), crtclient.ObjectKey{
-				Namespace: obj.Namespace,
-				Name:      obj.Name,
-			}, got)
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
 
-			err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(len(initObjs) + len(c.expected)),
+			}
+
+			err = r.createOrUpdateInCluster(context.TODO(), c.appSet, c.desiredApps)
 			assert.Nil(t, err)
 
-			assert.Equal(t, obj, *got)
-		}
-	}
+			for _, obj := range c.expected {
+				got := &argov1alpha1.Application{}
+				_ = client.Get(context.Background(), crtclient.ObjectKey{
+					Namespace: obj.Namespace,
+					Name:      obj.Name,
+				}, got)
 
+				err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
+				assert.Nil(t, err)
+				assert.Equal(t, obj, *got)
+			}
+		})
+	}
 }
 
-func TestDeleteInCluster(t *testing.T) {
-
+func TestCreateOrUpdateInClusterAdoptExisting(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := argoprojiov1alpha1.AddToScheme(scheme)
 	assert.Nil(t, err)
+
 	err = argov1alpha1.AddToScheme(scheme)
 	assert.Nil(t, err)
 
+	otherAppSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: "namespace",
+			UID:       "other-uid",
+		},
+	}
+
 	for _, c := range []struct {
-		// appSet is the application set on which the delete function is called
-		appSet argoprojiov1alpha1.ApplicationSet
-		// existingApps is the current state of Applications on the cluster
-		existingApps []argov1alpha1.Application
-		// desireApps is the apps generated by the generator that we wish to keep alive
-		desiredApps []argov1alpha1.Application
-		// expected is the list of applications that we expect to exist after calling delete
-		expected []argov1alpha1.Application
-		// notExpected is the list of applications that we expect not to exist after calling delete
-		notExpected []argov1alpha1.Application
+		name          string
+		adoptExisting *bool
+		owner         *argoprojiov1alpha1.ApplicationSet
+		expectedError string
+		expectAdopted bool
 	}{
 		{
-			appSet: argoprojiov1alpha1.ApplicationSet{
+			name:          "Application not owned by any ApplicationSet is adopted by default",
+			expectAdopted: true,
+		},
+		{
+			name:          "Application not owned by any ApplicationSet is adopted",
+			adoptExisting: pointer.BoolPtr(true),
+			expectAdopted: true,
+		},
+		{
+			name:          "Application not owned by any ApplicationSet is not adopted if adoptExisting is false",
+			adoptExisting: pointer.BoolPtr(false),
+			expectedError: "application app1 already exists and is not owned by an ApplicationSet, and syncPolicy.adoptExisting is false",
+		},
+		{
+			name:          "Application owned by another ApplicationSet is not adopted",
+			owner:         &otherAppSet,
+			expectedError: "already owned by another ApplicationSet controller other",
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "name",
 					Namespace: "namespace",
+					UID:       "uid",
 				},
 				Spec: argoprojiov1alpha1.ApplicationSetSpec{
-					Template: argoprojiov1alpha1.ApplicationSetTemplate{
-						Spec: argov1alpha1.ApplicationSpec{
-							Project: "project",
-						},
-					},
-				},
-			},
-			existingApps: []argov1alpha1.Application{
-				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "delete",
-						Namespace:       "namespace",
-						ResourceVersion: "2",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
-				},
-				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "keep",
-						Namespace:       "namespace",
-						ResourceVersion: "2",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
-				},
-			},
-			desiredApps: []argov1alpha1.Application{
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "keep",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
+					SyncPolicy: &argoprojiov1alpha1.ApplicationSetSyncPolicy{AdoptExisting: cc.adoptExisting},
 				},
-			},
-			expected: []argov1alpha1.Application{
-				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "keep",
-						Namespace:       "namespace",
-						ResourceVersion: "2",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
+			}
+			existing := argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app1",
+					Namespace: "namespace",
 				},
-			},
-			notExpected: []argov1alpha1.Application{
+				Spec: argov1alpha1.ApplicationSpec{Project: "hand-made"},
+			}
+			if cc.owner != nil {
+				err := controllerutil.SetControllerReference(cc.owner, &existing, scheme)
+				assert.Nil(t, err)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet, &existing).Build()
+
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(1),
+			}
+
+			err := r.createOrUpdateInCluster(context.TODO(), appSet, []argov1alpha1.Application{
 				{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       "Application",
-						APIVersion: "argoproj.io/v1alpha1",
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:            "delete",
-						Namespace:       "namespace",
-						ResourceVersion: "1",
-					},
-					Spec: argov1alpha1.ApplicationSpec{
-						Project: "project",
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "app1"},
+					Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
 				},
-			},
-		},
-	} {
-		initObjs := []crtclient.Object{&c.appSet}
-		for _, a := range c.existingApps {
-			temp := a
-			err = controllerutil.SetControllerReference(&c.appSet, &temp, scheme)
-			assert.Nil(t, err)
-			initObjs = append(initObjs, &temp)
-		}
+			})
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonApplicationNameConflict, applicationErrorReason(err, argoprojiov1alpha1.ApplicationSetReasonUpdateApplicationError))
+			} else {
+				assert.NoError(t, err)
+			}
 
-		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			got := &argov1alpha1.Application{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "app1"}, got)
+			assert.Nil(t, err)
+			if cc.expectAdopted {
+				assert.True(t, metav1.IsControlledBy(got, &appSet))
+				assert.Equal(t, "project", got.Spec.Project)
+			} else {
+				assert.False(t, metav1.IsControlledBy(got, &appSet))
+				assert.Equal(t, "hand-made", got.Spec.Project)
+			}
+		})
+	}
+}
 
-		r := ApplicationSetReconciler{
-			Client:        client,
-			Scheme:        scheme,
-			Recorder:      record.NewFakeRecorder(len(initObjs) + len(c.expected)),
-			KubeClientset: kubefake.NewSimpleClientset(),
-		}
+func TestCreateOrUpdateInClusterTrackingMethod(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
 
-		err = r.deleteInCluster(context.TODO(), c.appSet, c.desiredApps)
-		assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+			UID:       "uid",
+		},
+	}
+
+	for _, c := range []struct {
+		name                 string
+		trackingMethod       utils.TrackingMethod
+		ownedExisting        bool
+		existingAnnotations  map[string]string
+		expectedError        string
+		expectedOwned        bool
+		expectedTrackingID   string
+		expectedUnchangedApp bool
+	}{
+		{
+			name:          "new Application tracked with an owner reference",
+			expectedOwned: true,
+		},
+		{
+			name:               "new Application tracked with the annotation",
+			trackingMethod:     utils.TrackingMethodAnnotation,
+			expectedTrackingID: "namespace/name",
+		},
+		{
+			name:               "owned Application migrated to the annotation",
+			trackingMethod:     utils.TrackingMethodAnnotation,
+			ownedExisting:      true,
+			expectedTrackingID: "namespace/name",
+		},
+		{
+			name:                "Application tracked with the annotation migrated to an owner reference",
+			trackingMethod:      utils.TrackingMethodOwnerReference,
+			existingAnnotations: map[string]string{common.AnnotationApplicationSetTrackingID: "namespace/name"},
+			expectedOwned:       true,
+		},
+		{
+			name:                 "Application tracked by another ApplicationSet",
+			trackingMethod:       utils.TrackingMethodAnnotation,
+			existingAnnotations:  map[string]string{common.AnnotationApplicationSetTrackingID: "namespace/other"},
+			expectedError:        "application app1 already exists and is tracked by another ApplicationSet namespace/other",
+			expectedTrackingID:   "namespace/other",
+			expectedUnchangedApp: true,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			initObjs := []crtclient.Object{&appSet}
+			if cc.ownedExisting || cc.existingAnnotations != nil {
+				existing := &argov1alpha1.Application{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "app1",
+						Namespace:   "namespace",
+						Annotations: cc.existingAnnotations,
+					},
+					Spec: argov1alpha1.ApplicationSpec{Project: "old"},
+				}
+				if cc.ownedExisting {
+					err := controllerutil.SetControllerReference(&appSet, existing, scheme)
+					assert.Nil(t, err)
+				}
+				initObjs = append(initObjs, existing)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+
+			r := ApplicationSetReconciler{
+				Client:         client,
+				Scheme:         scheme,
+				Recorder:       record.NewFakeRecorder(1),
+				TrackingMethod: cc.trackingMethod,
+			}
+
+			err := r.createOrUpdateInCluster(context.TODO(), appSet, []argov1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app1"},
+					Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+				},
+			})
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
 
-		// For each of the expected objects, verify they exist on the cluster
-		for _, obj := range c.expected {
 			got := &argov1alpha1.Application{}
-			_ = client.Get(context.Background(), crtclient.ObjectKey{
-				Namespace: obj.Namespace,
-				Name:      obj.Name,
-			}, got)
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "app1"}, got)
+			assert.Nil(t, err)
+			assert.Equal(t, cc.expectedOwned, metav1.IsControlledBy(got, &appSet))
+			if cc.expectedTrackingID != "" {
+				assert.Equal(t, cc.expectedTrackingID, got.Annotations[common.AnnotationApplicationSetTrackingID])
+			} else {
+				assert.NotContains(t, got.Annotations, common.AnnotationApplicationSetTrackingID)
+			}
+			if cc.expectedUnchangedApp {
+				assert.Equal(t, "old", got.Spec.Project)
+			} else {
+				assert.Equal(t, "project", got.Spec.Project)
+			}
+		})
+	}
+}
+
+func TestCreateOrUpdateInClusterSkipUnchangedApplications(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	generatedApp := argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app1"},
+		Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+	}
+	renderedHash, err := utils.RenderedHash(&generatedApp)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		name                      string
+		skipUnchangedApplications bool
+		existingHash              string
+		refresh                   bool
+		expectedProject           string
+		expectedHash              string
+	}{
+		{
+			name:            "Application compared with the rendered Application",
+			existingHash:    renderedHash,
+			expectedProject: "project",
+		},
+		{
+			name:                      "Application rendered the same is left untouched",
+			skipUnchangedApplications: true,
+			existingHash:              renderedHash,
+			expectedProject:           "changed",
+			expectedHash:              renderedHash,
+		},
+		{
+			name:                      "Application rendered the same is updated when the ApplicationSet is refreshed",
+			skipUnchangedApplications: true,
+			existingHash:              renderedHash,
+			refresh:                   true,
+			expectedProject:           "project",
+			expectedHash:              renderedHash,
+		},
+		{
+			name:                      "Application rendered differently is updated",
+			skipUnchangedApplications: true,
+			existingHash:              "other-hash",
+			expectedProject:           "project",
+			expectedHash:              renderedHash,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+					UID:       "uid",
+				},
+			}
+			if cc.refresh {
+				appSet.Annotations = map[string]string{common.AnnotationApplicationSetRefresh: "true"}
+			}
+			existing := argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app1",
+					Namespace:   "namespace",
+					Annotations: map[string]string{common.AnnotationApplicationSetRenderedHash: cc.existingHash},
+				},
+				Spec: argov1alpha1.ApplicationSpec{Project: "changed"},
+			}
+			err := controllerutil.SetControllerReference(&appSet, &existing, scheme)
+			assert.Nil(t, err)
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet, &existing).Build()
+
+			r := ApplicationSetReconciler{
+				Client:                    client,
+				Scheme:                    scheme,
+				Recorder:                  record.NewFakeRecorder(1),
+				SkipUnchangedApplications: cc.skipUnchangedApplications,
+			}
+
+			err = r.createOrUpdateInCluster(context.TODO(), appSet, []argov1alpha1.Application{*generatedApp.DeepCopy()})
+			assert.NoError(t, err)
+
+			got := &argov1alpha1.Application{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "app1"}, got)
+			assert.Nil(t, err)
+			assert.Equal(t, cc.expectedProject, got.Spec.Project)
+			assert.Equal(t, cc.expectedHash, got.Annotations[common.AnnotationApplicationSetRenderedHash])
+		})
+	}
+}
+
+func TestRemoveFinalizerOnInvalidDestination_FinalizerTypes(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		// name is human-readable test name
+		name               string
+		existingFinalizers []string
+		expectedFinalizers []string
+	}{
+		{
+			name:               "no finalizers",
+			existingFinalizers: []string{},
+			expectedFinalizers: nil,
+		},
+		{
+			name:               "contains only argo finalizer",
+			existingFinalizers: []string{argov1alpha1.ResourcesFinalizerName},
+			expectedFinalizers: nil,
+		},
+		{
+			name:               "contains only non-argo finalizer",
+			existingFinalizers: []string{"non-argo-finalizer"},
+			expectedFinalizers: []string{"non-argo-finalizer"},
+		},
+		{
+			name:               "contains both argo and non-argo finalizer",
+			existingFinalizers: []string{"non-argo-finalizer", argov1alpha1.ResourcesFinalizerName},
+			expectedFinalizers: []string{"non-argo-finalizer"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						Spec: argov1alpha1.ApplicationSpec{
+							Project: "project",
+						},
+					},
+				},
+			}
+
+			app := argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app1",
+					Finalizers: c.existingFinalizers,
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Project: "project",
+					Source:  argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "revision", RepoURL: "repoURL"},
+					// Destination is always invalid, for this test:
+					Destination: argov1alpha1.ApplicationDestination{Name: "my-cluster", Namespace: "namespace"},
+				},
+			}
+
+			initObjs := []crtclient.Object{&app, &appSet}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-secret",
+					Namespace: "namespace",
+					Labels: map[string]string{
+						generators.ArgoCDSecretTypeLabel: generators.ArgoCDSecretTypeCluster,
+					},
+				},
+				Data: map[string][]byte{
+					// Since this test requires the cluster to be an invalid destination, we
+					// always return a cluster named 'my-cluster2' (different from app 'my-cluster', above)
+					"name":   []byte("mycluster2"),
+					"server": []byte("https://kubernetes.default.svc"),
+					"config": []byte("{\"username\":\"foo\",\"password\":\"foo\"}"),
+				},
+			}
+
+			objects := append([]runtime.Object{}, secret)
+			kubeclientset := kubefake.NewSimpleClientset(objects...)
+
+			r := ApplicationSetReconciler{
+				Client:        client,
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+				KubeClientset: kubeclientset,
+			}
+
+			clusterList, err := utils.ListClusters(context.Background(), kubeclientset, "namespace")
+			assert.NoError(t, err, "Unexpected error")
+
+			appLog := log.WithFields(log.Fields{"app": app.Name, "appSet": ""})
+
+			appInputParam := app.DeepCopy()
+
+			err = r.removeFinalizerOnInvalidDestination(context.Background(), appSet, appInputParam, clusterList, appLog)
+			assert.NoError(t, err, "Unexpected error")
+
+			retrievedApp := argov1alpha1.Application{}
+			err = client.Get(context.Background(), crtclient.ObjectKeyFromObject(&app), &retrievedApp)
+			assert.NoError(t, err, "Unexpected error")
+
+			// App on the cluster should have the expected finalizers
+			assert.ElementsMatch(t, c.expectedFinalizers, retrievedApp.Finalizers)
+
+			// App object passed in as a parameter should have the expected finaliers
+			assert.ElementsMatch(t, c.expectedFinalizers, appInputParam.Finalizers)
+
+			bytes, _ := json.MarshalIndent(retrievedApp, "", "  ")
+			t.Log("Contents of app after call:", string(bytes))
+
+		})
+	}
+}
+
+func TestRemoveFinalizerOnInvalidDestination_DestinationTypes(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		// name is human-readable test name
+		name                   string
+		destinationField       argov1alpha1.ApplicationDestination
+		expectFinalizerRemoved bool
+	}{
+		{
+			name: "invalid cluster: empty destination",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+			},
+			expectFinalizerRemoved: true,
+		},
+		{
+			name: "invalid cluster: invalid server url",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Server:    "https://1.2.3.4",
+			},
+			expectFinalizerRemoved: true,
+		},
+		{
+			name: "invalid cluster: invalid cluster name",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Name:      "invalid-cluster",
+			},
+			expectFinalizerRemoved: true,
+		},
+		{
+			name: "invalid cluster by both valid",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Name:      "mycluster2",
+				Server:    "https://kubernetes.default.svc",
+			},
+			expectFinalizerRemoved: true,
+		},
+		{
+			name: "invalid cluster by both invalid",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Name:      "mycluster3",
+				Server:    "https://4.5.6.7",
+			},
+			expectFinalizerRemoved: true,
+		},
+		{
+			name: "valid cluster by name",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Name:      "mycluster2",
+			},
+			expectFinalizerRemoved: false,
+		},
+		{
+			name: "valid cluster by server",
+			destinationField: argov1alpha1.ApplicationDestination{
+				Namespace: "namespace",
+				Server:    "https://kubernetes.default.svc",
+			},
+			expectFinalizerRemoved: false,
+		},
+	} {
+
+		t.Run(c.name, func(t *testing.T) {
+
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						Spec: argov1alpha1.ApplicationSpec{
+							Project: "project",
+						},
+					},
+				},
+			}
+
+			app := argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app1",
+					Finalizers: []string{argov1alpha1.ResourcesFinalizerName},
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Project:     "project",
+					Source:      argov1alpha1.ApplicationSource{Path: "path", TargetRevision: "revision", RepoURL: "repoURL"},
+					Destination: c.destinationField,
+				},
+			}
+
+			initObjs := []crtclient.Object{&app, &appSet}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-secret",
+					Namespace: "namespace",
+					Labels: map[string]string{
+						generators.ArgoCDSecretTypeLabel: generators.ArgoCDSecretTypeCluster,
+					},
+				},
+				Data: map[string][]byte{
+					// Since this test requires the cluster to be an invalid destination, we
+					// always return a cluster named 'my-cluster2' (different from app 'my-cluster', above)
+					"name":   []byte("mycluster2"),
+					"server": []byte("https://kubernetes.default.svc"),
+					"config": []byte("{\"username\":\"foo\",\"password\":\"foo\"}"),
+				},
+			}
+
+			objects := append([]runtime.Object{}, secret)
+			kubeclientset := kubefake.NewSimpleClientset(objects...)
+
+			r := ApplicationSetReconciler{
+				Client:        client,
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+				KubeClientset: kubeclientset,
+			}
+
+			clusterList, err := utils.ListClusters(context.Background(), kubeclientset, "namespace")
+			assert.NoError(t, err, "Unexpected error")
+
+			appLog := log.WithFields(log.Fields{"app": app.Name, "appSet": ""})
+
+			appInputParam := app.DeepCopy()
+
+			err = r.removeFinalizerOnInvalidDestination(context.Background(), appSet, appInputParam, clusterList, appLog)
+			assert.NoError(t, err, "Unexpected error")
+
+			retrievedApp := argov1alpha1.Application{}
+			err = client.Get(context.Background(), crtclient.ObjectKeyFromObject(&app), &retrievedApp)
+			assert.NoError(t, err, "Unexpected error")
+
+			finalizerRemoved := len(retrievedApp.Finalizers) == 0
+
+			assert.True(t, c.expectFinalizerRemoved == finalizerRemoved)
+
+			bytes, _ := json.MarshalIndent(retrievedApp, "", "  ")
+			t.Log("Contents of app after call:", string(bytes))
+
+		})
+	}
+}
+
+func TestCreateApplications(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		appSet     argoprojiov1alpha1.ApplicationSet
+		existsApps []argov1alpha1.Application
+		apps       []argov1alpha1.Application
+		expected   []argov1alpha1.Application
+	}{
+		{
+			appSet: argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+			},
+			existsApps: nil,
+			apps: []argov1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "app1",
+					},
+				},
+			},
+			expected: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app1",
+						Namespace:       "namespace",
+						ResourceVersion: "1",
+					},
+				},
+			},
+		},
+		{
+			appSet: argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						Spec: argov1alpha1.ApplicationSpec{
+							Project: "project",
+						},
+					},
+				},
+			},
+			existsApps: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app1",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "test",
+					},
+				},
+			},
+			apps: []argov1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "app1",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+			expected: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app1",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "test",
+					},
+				},
+			},
+		},
+		{
+			appSet: argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						Spec: argov1alpha1.ApplicationSpec{
+							Project: "project",
+						},
+					},
+				},
+			},
+			existsApps: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app1",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "test",
+					},
+				},
+			},
+			apps: []argov1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "app2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+			expected: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "app2",
+						Namespace:       "namespace",
+						ResourceVersion: "1",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+		},
+	} {
+		initObjs := []crtclient.Object{&c.appSet}
+		for _, a := range c.existsApps {
+			err = controllerutil.SetControllerReference(&c.appSet, &a, scheme)
+			assert.Nil(t, err)
+			initObjs = append(initObjs, &a)
+		}
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+
+		r := ApplicationSetReconciler{
+			Client:   client,
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(len(initObjs) + len(c.expected)),
+		}
+
+		err = r.createInCluster(context.TODO(), c.appSet, c.apps)
+		assert.Nil(t, err)
+
+		for _, obj := range c.expected {
+			got := &argov1alpha1.Application{}
+			_ = client.Get(context.Background(), crtclient.ObjectKey{
+				Namespace: obj.Namespace,
+				Name:      obj.Name,
+			}, got)
+
+			err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
+			assert.Nil(t, err)
+
+			assert.Equal(t, obj, *got)
+		}
+	}
+
+}
+
+func TestDeleteInCluster(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		// appSet is the application set on which the delete function is called
+		appSet argoprojiov1alpha1.ApplicationSet
+		// existingApps is the current state of Applications on the cluster
+		existingApps []argov1alpha1.Application
+		// desireApps is the apps generated by the generator that we wish to keep alive
+		desiredApps []argov1alpha1.Application
+		// expected is the list of applications that we expect to exist after calling delete
+		expected []argov1alpha1.Application
+		// notExpected is the list of applications that we expect not to exist after calling delete
+		notExpected []argov1alpha1.Application
+	}{
+		{
+			appSet: argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						Spec: argov1alpha1.ApplicationSpec{
+							Project: "project",
+						},
+					},
+				},
+			},
+			existingApps: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "delete",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "keep",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+			desiredApps: []argov1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "keep",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+			expected: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "keep",
+						Namespace:       "namespace",
+						ResourceVersion: "2",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+			notExpected: []argov1alpha1.Application{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Application",
+						APIVersion: "argoproj.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "delete",
+						Namespace:       "namespace",
+						ResourceVersion: "1",
+					},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "project",
+					},
+				},
+			},
+		},
+	} {
+		initObjs := []crtclient.Object{&c.appSet}
+		for _, a := range c.existingApps {
+			temp := a
+			err = controllerutil.SetControllerReference(&c.appSet, &temp, scheme)
+			assert.Nil(t, err)
+			initObjs = append(initObjs, &temp)
+		}
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+
+		r := ApplicationSetReconciler{
+			Client:        client,
+			Scheme:        scheme,
+			Recorder:      record.NewFakeRecorder(len(initObjs) + len(c.expected)),
+			KubeClientset: kubefake.NewSimpleClientset(),
+		}
+
+		_, err = r.deleteInCluster(context.TODO(), c.appSet, c.desiredApps)
+		assert.Nil(t, err)
+
+		// For each of the expected objects, verify they exist on the cluster
+		for _, obj := range c.expected {
+			got := &argov1alpha1.Application{}
+			_ = client.Get(context.Background(), crtclient.ObjectKey{
+				Namespace: obj.Namespace,
+				Name:      obj.Name,
+			}, got)
+
+			err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
+			assert.Nil(t, err)
+
+			assert.Equal(t, obj, *got)
+		}
+
+		// Verify each of the unexpected objs cannot be found
+		for _, obj := range c.notExpected {
+			got := &argov1alpha1.Application{}
+			err := client.Get(context.Background(), crtclient.ObjectKey{
+				Namespace: obj.Namespace,
+				Name:      obj.Name,
+			}, got)
+
+			assert.EqualError(t, err, fmt.Sprintf("applications.argoproj.io \"%s\" not found", obj.Name))
+		}
+	}
+}
+
+func TestDeleteInClusterMaxDeletionPercentage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	fifty := int64(50)
+	eighty := int64(80)
+
+	for _, c := range []struct {
+		name                            string
+		controllerMaxDeletionPercentage *int64
+		appSetMaxDeletionPercentage     *int64
+		expectRefused                   bool
+	}{
+		{
+			name: "no maximum deletion percentage",
+		},
+		{
+			name:                            "maximum deletion percentage of the controller exceeded",
+			controllerMaxDeletionPercentage: &fifty,
+			expectRefused:                   true,
+		},
+		{
+			name:                            "maximum deletion percentage of the ApplicationSet overrides the controller",
+			controllerMaxDeletionPercentage: &fifty,
+			appSetMaxDeletionPercentage:     &eighty,
+		},
+		{
+			name:                        "maximum deletion percentage of the ApplicationSet exceeded",
+			appSetMaxDeletionPercentage: &fifty,
+			expectRefused:               true,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					SyncPolicy: &argoprojiov1alpha1.ApplicationSetSyncPolicy{
+						MaxDeletionPercentage: cc.appSetMaxDeletionPercentage,
+					},
+				},
+			}
+			initObjs := []crtclient.Object{&appSet}
+			for _, name := range []string{"app1", "app2", "app3", "app4"} {
+				app := &argov1alpha1.Application{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace"},
+					Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+				}
+				err := controllerutil.SetControllerReference(&appSet, app, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, app)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			recorder := record.NewFakeRecorder(10)
+
+			r := ApplicationSetReconciler{
+				Client:                client,
+				Scheme:                scheme,
+				Recorder:              recorder,
+				KubeClientset:         kubefake.NewSimpleClientset(),
+				MaxDeletionPercentage: cc.controllerMaxDeletionPercentage,
+			}
+
+			// 3 of the 4 Applications are no longer generated
+			_, err := r.deleteInCluster(context.TODO(), appSet, []argov1alpha1.Application{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app1"}},
+			})
+
+			var apps argov1alpha1.ApplicationList
+			assert.NoError(t, client.List(context.TODO(), &apps))
+			if cc.expectRefused {
+				assert.True(t, errors.Is(err, ErrDeletionThresholdExceeded))
+				assert.Len(t, apps.Items, 4)
+				assert.Contains(t, <-recorder.Events, "Warning DeletionThresholdExceeded Refused to delete Applications: deletion threshold exceeded: 3 of the 4 Applications would be deleted")
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, apps.Items, 1)
+			}
+		})
+	}
+}
+
+// failingDeleteClient fails to delete the Applications of the given names.
+type failingDeleteClient struct {
+	crtclient.Client
+	failing map[string]bool
+}
+
+func (c *failingDeleteClient) Delete(ctx context.Context, obj crtclient.Object, opts ...crtclient.DeleteOption) error {
+	if c.failing[obj.GetName()] {
+		return fmt.Errorf("could not delete %s", obj.GetName())
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestDeleteInClusterDeleteError(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+	}
+	initObjs := []crtclient.Object{&appSet}
+	for _, name := range []string{"app1", "app2", "app3"} {
+		app := &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		}
+		err := controllerutil.SetControllerReference(&appSet, app, scheme)
+		assert.Nil(t, err)
+		initObjs = append(initObjs, app)
+	}
+
+	client := &failingDeleteClient{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build(),
+		failing: map[string]bool{"app2": true},
+	}
+	r := ApplicationSetReconciler{
+		Client:        client,
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(3),
+		KubeClientset: kubefake.NewSimpleClientset(),
+	}
+
+	// The failure to delete an Application is returned, and the other Applications are still deleted
+	_, err = r.deleteInCluster(context.TODO(), appSet, []argov1alpha1.Application{
+		{ObjectMeta: metav1.ObjectMeta{Name: "app1"}},
+	})
+	assert.EqualError(t, err, "could not delete app2")
+
+	for name, expectedExists := range map[string]bool{"app1": true, "app2": true, "app3": false} {
+		got := &argov1alpha1.Application{}
+		err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: name}, got)
+		if expectedExists {
+			assert.NoError(t, err, name)
+		} else {
+			assert.True(t, apierr.IsNotFound(err), name)
+		}
+	}
+}
+
+func TestDeleteInClusterDeletionProtected(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+	}
+	initObjs := []crtclient.Object{&appSet}
+	for name, annotations := range map[string]map[string]string{
+		"app1": nil,
+		"app2": {argoprojiov1alpha1.DeletionProtectedAnnotation: "true"},
+		"app3": {argoprojiov1alpha1.DeletionProtectedAnnotation: "false"},
+	} {
+		app := &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace", Annotations: annotations},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		}
+		err := controllerutil.SetControllerReference(&appSet, app, scheme)
+		assert.Nil(t, err)
+		initObjs = append(initObjs, app)
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+	recorder := record.NewFakeRecorder(2)
+	r := ApplicationSetReconciler{
+		Client:        client,
+		Scheme:        scheme,
+		Recorder:      recorder,
+		KubeClientset: kubefake.NewSimpleClientset(),
+	}
+
+	_, err = r.deleteInCluster(context.TODO(), appSet, []argov1alpha1.Application{
+		{ObjectMeta: metav1.ObjectMeta{Name: "app1"}},
+	})
+	var protectedErr *deletionProtectedError
+	assert.True(t, errors.As(err, &protectedErr))
+	assert.EqualError(t, err, "applications app2 are no longer generated, but are not deleted as they are protected by the applicationset.argoproj.io/deletion-protected annotation")
+
+	for name, expectedExists := range map[string]bool{"app1": true, "app2": true, "app3": false} {
+		got := &argov1alpha1.Application{}
+		err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: name}, got)
+		if expectedExists {
+			assert.NoError(t, err, name)
+		} else {
+			assert.True(t, apierr.IsNotFound(err), name)
+		}
+	}
+	assert.Equal(t, "Normal Deleted Deleted Application \"app3\"", <-recorder.Events)
+	assert.Contains(t, <-recorder.Events, "Warning DeletionProtected Skipped the deletion of Applications")
+}
+
+func TestDryRunInCluster(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			DryRun: true,
+		},
+	}
+
+	existingApps := []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "keep", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "delete", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+	}
+	desiredApps := []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "keep"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "other-project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "create"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+	}
+
+	for _, c := range []struct {
+		name            string
+		policy          utils.Policy
+		expectedActions []argoprojiov1alpha1.ApplicationSetDryRunAction
+	}{
+		{
+			name:   "sync",
+			policy: &utils.SyncPolicy{},
+			expectedActions: []argoprojiov1alpha1.ApplicationSetDryRunAction{
+				{Application: "update", Action: argoprojiov1alpha1.ApplicationSetDryRunActionUpdate},
+				{Application: "create", Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate},
+				{Application: "delete", Action: argoprojiov1alpha1.ApplicationSetDryRunActionDelete},
+			},
+		},
+		{
+			name:   "create-only",
+			policy: &utils.CreateOnlyPolicy{},
+			expectedActions: []argoprojiov1alpha1.ApplicationSetDryRunAction{
+				{Application: "create", Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			initObjs := []crtclient.Object{&appSet}
+			for _, a := range existingApps {
+				temp := a
+				err = controllerutil.SetControllerReference(&appSet, &temp, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, &temp)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(len(c.expectedActions)),
+			}
+
+			actions, err := r.dryRunInCluster(context.TODO(), appSet, desiredApps, desiredApps, c.policy)
+			assert.Nil(t, err)
+			assert.ElementsMatch(t, c.expectedActions, actions)
+
+			// No Application was changed
+			var apps argov1alpha1.ApplicationList
+			err = client.List(context.TODO(), &apps)
+			assert.Nil(t, err)
+			var names []string
+			for _, app := range apps.Items {
+				names = append(names, app.Name)
+				assert.Equal(t, "project", app.Spec.Project)
+			}
+			assert.ElementsMatch(t, []string{"keep", "update", "delete"}, names)
+		})
+	}
+}
+
+func TestApplyStrategy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	automated := &argov1alpha1.SyncPolicy{Automated: &argov1alpha1.SyncPolicyAutomated{}}
+	apps := []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project", SyncPolicy: automated},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project", SyncPolicy: automated},
+		},
+	}
+
+	for _, c := range []struct {
+		name              string
+		strategy          *argoprojiov1alpha1.ApplicationSetStrategy
+		expectedAutomated []bool
+		expectedRollout   *argoprojiov1alpha1.ApplicationSetRolloutStatus
+		expectedError     string
+	}{
+		{
+			name:              "no strategy",
+			expectedAutomated: []bool{true, true},
+		},
+		{
+			name:              "all at once",
+			strategy:          &argoprojiov1alpha1.ApplicationSetStrategy{Type: argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce},
+			expectedAutomated: []bool{true, true},
+		},
+		{
+			name: "rolling sync",
+			strategy: &argoprojiov1alpha1.ApplicationSetStrategy{
+				Type: argoprojiov1alpha1.ApplicationSetStrategyTypeRollingSync,
+				RollingSync: &argoprojiov1alpha1.ApplicationSetRolloutStrategy{
+					Steps: []argoprojiov1alpha1.ApplicationSetRolloutStep{
+						{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev"}}}},
+						{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod"}}}},
+					},
+				},
+			},
+			expectedAutomated: []bool{true, false},
+			expectedRollout:   &argoprojiov1alpha1.ApplicationSetRolloutStatus{CurrentStep: 0},
+		},
+		{
+			name:          "unknown strategy",
+			strategy:      &argoprojiov1alpha1.ApplicationSetStrategy{Type: "Canary"},
+			expectedError: "unknown strategy type Canary, must be one of AllAtOnce, RollingSync",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Strategy: c.strategy,
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(1),
+			}
+
+			got, err := r.applyStrategy(context.TODO(), &appSet, apps)
+			if c.expectedError != "" {
+				assert.EqualError(t, err, c.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+
+			var gotAutomated []bool
+			for _, app := range got {
+				gotAutomated = append(gotAutomated, app.Spec.SyncPolicy.Automated != nil)
+			}
+			assert.Equal(t, c.expectedAutomated, gotAutomated)
+
+			updated := &argoprojiov1alpha1.ApplicationSet{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, updated)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expectedRollout, updated.Status.Rollout)
+		})
+	}
+}
+
+func TestLimitUpdates(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+	}
+
+	existingApps := []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unchanged", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update-1", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update-2", Namespace: "namespace"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+	}
+	desiredApps := []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update-1"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "other-project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unchanged"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "update-2"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "other-project"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "create"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+		},
+	}
+
+	for _, c := range []struct {
+		name          string
+		maxUpdate     *intstr.IntOrString
+		policy        utils.Policy
+		expectedApps  []string
+		expectedError string
+	}{
+		{
+			name:         "no maximum",
+			policy:       &utils.SyncPolicy{},
+			expectedApps: []string{"update-1", "unchanged", "update-2", "create"},
+		},
+		{
+			name:         "maximum number of updates",
+			maxUpdate:    &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+			policy:       &utils.SyncPolicy{},
+			expectedApps: []string{"update-1", "unchanged"},
+		},
+		{
+			name:         "maximum percentage of updates",
+			maxUpdate:    &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+			policy:       &utils.SyncPolicy{},
+			expectedApps: []string{"update-1", "unchanged", "update-2"},
+		},
+		{
+			name:         "updates the policy doesn't allow are not counted",
+			maxUpdate:    &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+			policy:       &utils.CreateOnlyPolicy{},
+			expectedApps: []string{"update-1", "unchanged", "update-2", "create"},
+		},
+		{
+			name:         "no updates",
+			maxUpdate:    &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+			policy:       &utils.SyncPolicy{},
+			expectedApps: []string{"unchanged"},
+		},
+		{
+			name:          "invalid maximum",
+			maxUpdate:     &intstr.IntOrString{Type: intstr.String, StrVal: "half"},
+			policy:        &utils.SyncPolicy{},
+			expectedError: "invalid maxUpdate: invalid value for IntOrString: invalid type: string is not a percentage",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := appSet
+			appSet.Spec.Strategy = &argoprojiov1alpha1.ApplicationSetStrategy{MaxUpdate: c.maxUpdate}
+
+			initObjs := []crtclient.Object{&appSet}
+			for _, a := range existingApps {
+				temp := a
+				err = controllerutil.SetControllerReference(&appSet, &temp, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, &temp)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := ApplicationSetReconciler{
+				Client: client,
+				Scheme: scheme,
+			}
+
+			got, err := r.limitUpdates(context.TODO(), appSet, desiredApps, c.policy)
+			if c.expectedError != "" {
+				assert.EqualError(t, err, c.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+
+			var names []string
+			for _, app := range got {
+				names = append(names, app.Name)
+			}
+			assert.Equal(t, c.expectedApps, names)
+		})
+	}
+}
+
+func TestUpdatePreserveApplicationsFinalizer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		name               string
+		syncPolicy         *argoprojiov1alpha1.ApplicationSetSyncPolicy
+		finalizers         []string
+		expectedFinalizers []string
+	}{
+		{
+			name: "no sync policy",
+		},
+		{
+			name:               "preserve applications adds the finalizer",
+			syncPolicy:         &argoprojiov1alpha1.ApplicationSetSyncPolicy{PreserveApplicationsOnDeletion: true},
+			finalizers:         []string{"other"},
+			expectedFinalizers: []string{"other", argoprojiov1alpha1.PreserveApplicationsFinalizer},
+		},
+		{
+			name:               "preserve applications keeps the finalizer",
+			syncPolicy:         &argoprojiov1alpha1.ApplicationSetSyncPolicy{PreserveApplicationsOnDeletion: true},
+			finalizers:         []string{argoprojiov1alpha1.PreserveApplicationsFinalizer},
+			expectedFinalizers: []string{argoprojiov1alpha1.PreserveApplicationsFinalizer},
+		},
+		{
+			name:               "delete applications removes the finalizer",
+			syncPolicy:         &argoprojiov1alpha1.ApplicationSetSyncPolicy{PreserveResourcesOnDeletion: true},
+			finalizers:         []string{argoprojiov1alpha1.PreserveApplicationsFinalizer, "other"},
+			expectedFinalizers: []string{"other"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "name",
+					Namespace:  "namespace",
+					Finalizers: c.finalizers,
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					SyncPolicy: c.syncPolicy,
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+			r := ApplicationSetReconciler{
+				Client: client,
+				Scheme: scheme,
+			}
+
+			err := r.updatePreserveApplicationsFinalizer(context.TODO(), &appSet)
+			assert.Nil(t, err)
+
+			got := &argoprojiov1alpha1.ApplicationSet{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expectedFinalizers, got.Finalizers)
+		})
+	}
+}
+
+func TestOrphanApplications(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "name",
+			Namespace:  "namespace",
+			UID:        "appset-uid",
+			Finalizers: []string{argoprojiov1alpha1.PreserveApplicationsFinalizer},
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			SyncPolicy: &argoprojiov1alpha1.ApplicationSetSyncPolicy{PreserveApplicationsOnDeletion: true},
+		},
+	}
+	otherOwner := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "other",
+		UID:        "other-uid",
+	}
+
+	initObjs := []crtclient.Object{&appSet}
+	for _, name := range []string{"app1", "app2"} {
+		app := &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "namespace",
+				OwnerReferences: []metav1.OwnerReference{otherOwner},
+				Finalizers:      []string{argov1alpha1.ResourcesFinalizerName},
+			},
+			Spec: argov1alpha1.ApplicationSpec{Project: "project"},
+		}
+		err = controllerutil.SetControllerReference(&appSet, app, scheme)
+		assert.Nil(t, err)
+		initObjs = append(initObjs, app)
+	}
+	initObjs = append(initObjs, &argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app3",
+			Namespace:       "namespace",
+			OwnerReferences: []metav1.OwnerReference{otherOwner},
+			Finalizers:      []string{argov1alpha1.ResourcesFinalizerName},
+			Annotations:     map[string]string{common.AnnotationApplicationSetTrackingID: "namespace/name"},
+		},
+		Spec: argov1alpha1.ApplicationSpec{Project: "project"},
+	})
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(3),
+	}
 
-			err = controllerutil.SetControllerReference(&c.appSet, &obj, r.Scheme)
-			assert.Nil(t, err)
+	err = r.orphanApplications(context.TODO(), &appSet)
+	assert.Nil(t, err)
 
-			assert.Equal(t, obj, *got)
-		}
+	for _, name := range []string{"app1", "app2", "app3"} {
+		got := &argov1alpha1.Application{}
+		err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: name}, got)
+		assert.Nil(t, err)
+		assert.Equal(t, []metav1.OwnerReference{otherOwner}, got.OwnerReferences)
+		assert.Equal(t, []string{argov1alpha1.ResourcesFinalizerName}, got.Finalizers)
+		assert.NotContains(t, got.Annotations, common.AnnotationApplicationSetTrackingID)
+	}
 
-		// Verify each of the unexpected objs cannot be found
-		for _, obj := range c.notExpected {
-			got := &argov1alpha1.Application{}
-			err := client.Get(context.Background(), crtclient.ObjectKey{
-				Namespace: obj.Namespace,
-				Name:      obj.Name,
-			}, got)
+	got := &argoprojiov1alpha1.ApplicationSet{}
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+	assert.Nil(t, err)
+	assert.Empty(t, got.Finalizers)
+}
 
-			assert.EqualError(t, err, fmt.Sprintf("applications.argoproj.io \"%s\" not found", obj.Name))
-		}
+func TestGetPolicy(t *testing.T) {
+	for _, c := range []struct {
+		name                 string
+		syncPolicy           *argoprojiov1alpha1.ApplicationSetSyncPolicy
+		enablePolicyOverride bool
+		expectedPolicy       utils.Policy
+		expectedError        string
+	}{
+		{
+			name:                 "no sync policy",
+			enablePolicyOverride: true,
+			expectedPolicy:       &utils.CreateOnlyPolicy{},
+		},
+		{
+			name:                 "no applications sync policy",
+			syncPolicy:           &argoprojiov1alpha1.ApplicationSetSyncPolicy{PreserveResourcesOnDeletion: true},
+			enablePolicyOverride: true,
+			expectedPolicy:       &utils.CreateOnlyPolicy{},
+		},
+		{
+			name:                 "applications sync policy overrides the policy of the controller",
+			syncPolicy:           &argoprojiov1alpha1.ApplicationSetSyncPolicy{ApplicationsSync: argoprojiov1alpha1.ApplicationsSyncPolicySync},
+			enablePolicyOverride: true,
+			expectedPolicy:       &utils.SyncPolicy{},
+		},
+		{
+			name:                 "create-delete",
+			syncPolicy:           &argoprojiov1alpha1.ApplicationSetSyncPolicy{ApplicationsSync: argoprojiov1alpha1.ApplicationsSyncPolicyCreateDelete},
+			enablePolicyOverride: true,
+			expectedPolicy:       &utils.CreateDeletePolicy{},
+		},
+		{
+			name:           "override disabled",
+			syncPolicy:     &argoprojiov1alpha1.ApplicationSetSyncPolicy{ApplicationsSync: argoprojiov1alpha1.ApplicationsSyncPolicySync},
+			expectedPolicy: &utils.CreateOnlyPolicy{},
+		},
+		{
+			name:                 "invalid applications sync policy",
+			syncPolicy:           &argoprojiov1alpha1.ApplicationSetSyncPolicy{ApplicationsSync: "delete-only"},
+			enablePolicyOverride: true,
+			expectedError:        "invalid applications sync policy delete-only, must be one of create-only, create-update, create-delete, sync",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := ApplicationSetReconciler{
+				Policy:               &utils.CreateOnlyPolicy{},
+				EnablePolicyOverride: c.enablePolicyOverride,
+			}
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"},
+				Spec:       argoprojiov1alpha1.ApplicationSetSpec{SyncPolicy: c.syncPolicy},
+			}
+
+			policy, err := r.getPolicy(appSet)
+			if c.expectedError != "" {
+				assert.EqualError(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedPolicy, policy)
+		})
 	}
 }
 
@@ -1580,7 +3030,7 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			apps: []argov1alpha1.Application{
 				{
 					TypeMeta:   metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "app"},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "default",
 						Source: argov1alpha1.ApplicationSource{
@@ -1603,7 +3053,7 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			apps: []argov1alpha1.Application{
 				{
 					TypeMeta:   metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "app"},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "default",
 						Source: argov1alpha1.ApplicationSource{
@@ -1627,7 +3077,7 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			apps: []argov1alpha1.Application{
 				{
 					TypeMeta:   metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "app"},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "DOES-NOT-EXIST",
 						Source: argov1alpha1.ApplicationSource{
@@ -1650,7 +3100,7 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			apps: []argov1alpha1.Application{
 				{
 					TypeMeta:   metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "app"},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "default",
 						Source: argov1alpha1.ApplicationSource{
@@ -1673,7 +3123,7 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			apps: []argov1alpha1.Application{
 				{
 					TypeMeta:   metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "app"},
 					Spec: argov1alpha1.ApplicationSpec{
 						Project: "default",
 						Source: argov1alpha1.ApplicationSource{
@@ -1691,6 +3141,29 @@ func TestValidateGeneratedApplications(t *testing.T) {
 			expectedErrors:   []string{"there are no clusters with this name: nonexistent-cluster"},
 			validationErrors: map[int]error{0: errors.New("application destination spec is invalid: unable to find destination server: there are no clusters with this name: nonexistent-cluster")},
 		},
+		{
+			name: "name should be a valid resource name",
+			apps: []argov1alpha1.Application{
+				{
+					TypeMeta:   metav1.TypeMeta{},
+					ObjectMeta: metav1.ObjectMeta{Name: "feature/My_Branch"},
+					Spec: argov1alpha1.ApplicationSpec{
+						Project: "default",
+						Source: argov1alpha1.ApplicationSource{
+							RepoURL:        "https://url",
+							Path:           "/",
+							TargetRevision: "HEAD",
+						},
+						Destination: argov1alpha1.ApplicationDestination{
+							Namespace: "namespace",
+							Name:      "my-cluster",
+						},
+					},
+				},
+			},
+			expectedErrors:   []string{`application name "feature/My_Branch" is invalid`},
+			validationErrors: map[int]error{0: errors.New(`application name "feature/My_Branch" is invalid: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`)},
+		},
 	} {
 
 		t.Run(cc.name, func(t *testing.T) {
@@ -1864,6 +3337,289 @@ func TestReconcilerValidationErrorBehaviour(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReconcileApplicationSetSelector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	defaultProject := argov1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "argocd"},
+		Spec:       argov1alpha1.AppProjectSpec{SourceRepos: []string{"*"}, Destinations: []argov1alpha1.ApplicationDestination{{Namespace: "*", Server: "https://good-cluster"}}},
+	}
+	goodCluster := argov1alpha1.Cluster{Server: "https://good-cluster", Name: "good-cluster"}
+
+	for _, c := range []struct {
+		name            string
+		selector        string
+		labels          map[string]string
+		expectReconcile bool
+	}{
+		{
+			name:            "No selector",
+			labels:          map[string]string{"team": "backend"},
+			expectReconcile: true,
+		},
+		{
+			name:            "ApplicationSet matching the selector",
+			selector:        "team=frontend",
+			labels:          map[string]string{"team": "frontend"},
+			expectReconcile: true,
+		},
+		{
+			name:            "ApplicationSet not matching the selector",
+			selector:        "team=frontend",
+			labels:          map[string]string{"team": "backend"},
+			expectReconcile: false,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "argocd",
+					Labels:    cc.labels,
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+						{
+							List: &argoprojiov1alpha1.ListGenerator{
+								Elements: []apiextensionsv1.JSON{{
+									Raw: []byte(`{"cluster": "good-cluster","url": "https://good-cluster"}`),
+								}},
+							},
+						},
+					},
+					Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+							Name:      "{{cluster}}",
+							Namespace: "argocd",
+						},
+						Spec: argov1alpha1.ApplicationSpec{
+							Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+							Project:     "default",
+							Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}"},
+						},
+					},
+				},
+			}
+
+			argoDBMock := dbmocks.ArgoDB{}
+			argoDBMock.On("GetCluster", mock.Anything, "https://good-cluster").Return(&goodCluster, nil)
+			argoDBMock.On("ListClusters", mock.Anything).Return(&argov1alpha1.ClusterList{Items: []argov1alpha1.Cluster{
+				goodCluster,
+			}}, nil)
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+
+			r := ApplicationSetReconciler{
+				Log:      ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+				Client:   client,
+				Scheme:   scheme,
+				Renderer: &utils.Render{},
+				Recorder: record.NewFakeRecorder(1),
+				Generators: map[string]generators.Generator{
+					"List": generators.NewListGenerator(),
+				},
+				ArgoDB:           &argoDBMock,
+				ArgoAppClientset: appclientset.NewSimpleClientset(&defaultProject),
+				KubeClientset:    kubefake.NewSimpleClientset(),
+				Policy:           &utils.SyncPolicy{},
+			}
+			if cc.selector != "" {
+				selector, err := labels.Parse(cc.selector)
+				assert.Nil(t, err)
+				r.ApplicationSetSelector = selector
+			}
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "argocd",
+					Name:      "name",
+				},
+			})
+			assert.Nil(t, err)
+
+			var app argov1alpha1.Application
+			err = r.Client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "good-cluster"}, &app)
+			if cc.expectReconcile {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, apierr.IsNotFound(err))
+			}
+		})
+	}
+}
+
+func TestReconcileSuspended(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	errorCondition := argoprojiov1alpha1.ApplicationSetCondition{
+		Type:    argoprojiov1alpha1.ApplicationSetConditionErrorOccurred,
+		Message: "error",
+		Reason:  argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError,
+		Status:  argoprojiov1alpha1.ApplicationSetConditionStatusTrue,
+	}
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojiov1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{
+							Raw: []byte(`{"cluster": "good-cluster","url": "https://good-cluster"}`),
+						}},
+					},
+				},
+			},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "{{cluster}}",
+					Namespace: "argocd",
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+					Project:     "default",
+					Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}"},
+				},
+			},
+			Suspend: true,
+		},
+		Status: argoprojiov1alpha1.ApplicationSetStatus{
+			Conditions: []argoprojiov1alpha1.ApplicationSetCondition{errorCondition},
+		},
+	}
+	staleApp := argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "argocd"},
+		Spec:       argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+	err = controllerutil.SetControllerReference(&appSet, &staleApp, scheme)
+	assert.Nil(t, err)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet, &staleApp).Build()
+
+	r := ApplicationSetReconciler{
+		Log:      ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+		Client:   client,
+		Scheme:   scheme,
+		Renderer: &utils.Render{},
+		Recorder: record.NewFakeRecorder(1),
+		Generators: map[string]generators.Generator{
+			"List": generators.NewListGenerator(),
+		},
+		KubeClientset: kubefake.NewSimpleClientset(),
+		Policy:        &utils.SyncPolicy{},
+	}
+
+	res, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: "argocd",
+			Name:      "name",
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	// The Applications are neither created nor deleted
+	var app argov1alpha1.Application
+	err = r.Client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "good-cluster"}, &app)
+	assert.True(t, apierr.IsNotFound(err))
+	err = r.Client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "stale"}, &app)
+	assert.NoError(t, err)
+
+	var got argoprojiov1alpha1.ApplicationSet
+	err = r.Client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "name"}, &got)
+	assert.NoError(t, err)
+	assert.Len(t, got.Status.Conditions, 2)
+	for _, condition := range got.Status.Conditions {
+		switch condition.Type {
+		case argoprojiov1alpha1.ApplicationSetConditionErrorOccurred:
+			assert.Equal(t, errorCondition.Message, condition.Message)
+		case argoprojiov1alpha1.ApplicationSetConditionResourcesUpToDate:
+			assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonSuspended, condition.Reason)
+			assert.Equal(t, argoprojiov1alpha1.ApplicationSetConditionStatusFalse, condition.Status)
+		default:
+			t.Errorf("unexpected condition %s", condition.Type)
+		}
+	}
+}
+
+func TestReconcileTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojiov1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{
+							Raw: []byte(`{"cluster": "good-cluster","url": "https://good-cluster"}`),
+						}},
+					},
+				},
+			},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "{{cluster}}",
+					Namespace: "argocd",
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+					Project:     "default",
+					Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}"},
+				},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+
+	r := ApplicationSetReconciler{
+		Log:      ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+		Client:   client,
+		Scheme:   scheme,
+		Renderer: &utils.Render{},
+		Recorder: record.NewFakeRecorder(1),
+		Generators: map[string]generators.Generator{
+			"List": generators.NewListGenerator(),
+		},
+		ArgoDB:           &dbmocks.ArgoDB{},
+		ArgoAppClientset: appclientset.NewSimpleClientset(),
+		KubeClientset:    kubefake.NewSimpleClientset(),
+		Policy:           &utils.SyncPolicy{},
+		ReconcileTimeout: time.Nanosecond,
+	}
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: "argocd",
+			Name:      "name",
+		},
+	})
+	assert.EqualError(t, err, "reconcile of ApplicationSet argocd/name timed out: context deadline exceeded")
+
+	var app argov1alpha1.Application
+	err = r.Client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "good-cluster"}, &app)
+	assert.True(t, apierr.IsNotFound(err))
+}
+
 func TestSetApplicationSetStatusCondition(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := argoprojiov1alpha1.AddToScheme(scheme)
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// applyByChunks returns true if the Applications of the ApplicationSet are validated, and created or updated, by chunks
+// as they are rendered: the controller applies the Applications by chunks, and the ApplicationSet needs neither all
+// of its Applications at once for its strategy, nor a dry run.
+func (r *ApplicationSetReconciler) applyByChunks(applicationSet *argoprojiov1alpha1.ApplicationSet) bool {
+	if r.ApplyChunkSize <= 0 || applicationSet.Spec.DryRun {
+		return false
+	}
+	strategy := applicationSet.Spec.Strategy
+	return strategy == nil || ((strategy.Type == "" || strategy.Type == argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce) &&
+		strategy.Order == nil && strategy.MaxUpdate == nil)
+}
+
+// chunkedApply validates, and creates or updates, the chunks of the Applications of an ApplicationSet passed to apply,
+// and only keeps the names of the Applications, so that the Applications which are no longer generated are deleted
+// once all of them are applied.
+type chunkedApply struct {
+	r              *ApplicationSetReconciler
+	ctx            context.Context
+	applicationSet argoprojiov1alpha1.ApplicationSet
+	policy         utils.Policy
+
+	// names are the names of the Applications validated so far, so that the duplicate names are found across chunks
+	names map[string]bool
+	// generated are the Applications passed to apply, with only their name
+	generated []argov1alpha1.Application
+	// validateErrors are the validation errors of the Applications, by index in generated
+	validateErrors map[int]error
+
+	// err is the error which stopped applying the Applications, if any, and reason the reason of the condition
+	// reporting it
+	err    error
+	reason string
+}
+
+func newChunkedApply(ctx context.Context, r *ApplicationSetReconciler, applicationSet argoprojiov1alpha1.ApplicationSet, policy utils.Policy) *chunkedApply {
+	return &chunkedApply{
+		r:              r,
+		ctx:            ctx,
+		applicationSet: applicationSet,
+		policy:         policy,
+		names:          map[string]bool{},
+		validateErrors: map[int]error{},
+	}
+}
+
+// apply validates the Applications of the chunk, and creates or updates the valid ones according to the policy.
+func (c *chunkedApply) apply(apps []argov1alpha1.Application) error {
+	validateErrors, err := c.r.validateApplications(c.ctx, apps, c.applicationSet, c.applicationSet.Namespace, c.names)
+	if err != nil {
+		c.err = err
+		c.reason = argoprojiov1alpha1.ApplicationSetReasonApplicationValidationError
+		return err
+	}
+
+	var validApps []argov1alpha1.Application
+	for i, app := range apps {
+		if validateErrors[i] != nil {
+			c.validateErrors[len(c.generated)] = validateErrors[i]
+		} else {
+			validApps = append(validApps, app)
+		}
+		c.generated = append(c.generated, argov1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: app.Name}})
+	}
+
+	reason := argoprojiov1alpha1.ApplicationSetReasonUpdateApplicationError
+	if c.policy.Update() {
+		err = c.r.createOrUpdateInCluster(c.ctx, c.applicationSet, validApps)
+	} else {
+		reason = argoprojiov1alpha1.ApplicationSetReasonCreateApplicationError
+		err = c.r.createInCluster(c.ctx, c.applicationSet, validApps)
+	}
+	if err != nil {
+		c.err = err
+		c.reason = applicationErrorReason(err, reason)
+		return err
+	}
+	return nil
+}
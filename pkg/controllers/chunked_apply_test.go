@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestApplyByChunks(t *testing.T) {
+	r := ApplicationSetReconciler{ApplyChunkSize: 10}
+	for _, c := range []struct {
+		name     string
+		spec     argoprojiov1alpha1.ApplicationSetSpec
+		expected bool
+	}{
+		{name: "no strategy", expected: true},
+		{name: "all at once", spec: argoprojiov1alpha1.ApplicationSetSpec{Strategy: &argoprojiov1alpha1.ApplicationSetStrategy{Type: argoprojiov1alpha1.ApplicationSetStrategyTypeAllAtOnce}}, expected: true},
+		{name: "dry run", spec: argoprojiov1alpha1.ApplicationSetSpec{DryRun: true}},
+		{name: "rolling sync", spec: argoprojiov1alpha1.ApplicationSetSpec{Strategy: &argoprojiov1alpha1.ApplicationSetStrategy{Type: argoprojiov1alpha1.ApplicationSetStrategyTypeRollingSync}}},
+		{name: "order", spec: argoprojiov1alpha1.ApplicationSetSpec{Strategy: &argoprojiov1alpha1.ApplicationSetStrategy{Order: &argoprojiov1alpha1.ApplicationSetOrder{Key: "{{cluster}}"}}}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, r.applyByChunks(&argoprojiov1alpha1.ApplicationSet{Spec: c.spec}))
+		})
+	}
+
+	assert.False(t, (&ApplicationSetReconciler{}).applyByChunks(&argoprojiov1alpha1.ApplicationSet{}))
+}
+
+func TestReconcileApplyByChunks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	defaultProject := argov1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "argocd"},
+		Spec:       argov1alpha1.AppProjectSpec{SourceRepos: []string{"*"}, Destinations: []argov1alpha1.ApplicationDestination{{Namespace: "*", Server: "https://good-cluster"}}},
+	}
+	var elements []apiextensionsv1.JSON
+	for _, name := range []string{"app1", "app2", "app3", "app1"} {
+		elements = append(elements, apiextensionsv1.JSON{Raw: []byte(`{"name": "` + name + `"}`)})
+	}
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+			UID:       "uid",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{List: &argoprojiov1alpha1.ListGenerator{Elements: elements}},
+			},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "{{name}}",
+					Namespace: "argocd",
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+					Project:     "default",
+					Destination: argov1alpha1.ApplicationDestination{Server: "https://good-cluster"},
+				},
+			},
+		},
+	}
+	// An Application which is no longer generated
+	old := argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "argocd"},
+		Spec:       argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+	err = controllerutil.SetControllerReference(&appSet, &old, scheme)
+	assert.Nil(t, err)
+
+	goodCluster := argov1alpha1.Cluster{Server: "https://good-cluster", Name: "good-cluster"}
+	argoDBMock := dbmocks.ArgoDB{}
+	argoDBMock.On("GetCluster", mock.Anything, "https://good-cluster").Return(&goodCluster, nil)
+	argoDBMock.On("ListClusters", mock.Anything).Return(&argov1alpha1.ClusterList{Items: []argov1alpha1.Cluster{goodCluster}}, nil)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet, &old).Build()
+	r := ApplicationSetReconciler{
+		Log:      ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+		Client:   client,
+		Scheme:   scheme,
+		Renderer: &utils.Render{},
+		Recorder: record.NewFakeRecorder(10),
+		Generators: map[string]generators.Generator{
+			"List": generators.NewListGenerator(),
+		},
+		ArgoDB:           &argoDBMock,
+		ArgoAppClientset: appclientset.NewSimpleClientset(&defaultProject),
+		KubeClientset:    kubefake.NewSimpleClientset(),
+		Policy:           &utils.SyncPolicy{},
+		ApplyChunkSize:   2,
+	}
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "name"}})
+	assert.NoError(t, err)
+
+	// The Applications of both chunks are created, and the Application which is no longer generated is deleted
+	for _, name := range []string{"app1", "app2", "app3"} {
+		var app argov1alpha1.Application
+		err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: name}, &app)
+		assert.NoError(t, err, name)
+	}
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "old"}, &argov1alpha1.Application{})
+	assert.Error(t, err)
+
+	// The duplicate name is found across the chunks
+	var got argoprojiov1alpha1.ApplicationSet
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "name"}, &got)
+	assert.NoError(t, err)
+	found := false
+	for _, condition := range got.Status.Conditions {
+		if condition.Type == argoprojiov1alpha1.ApplicationSetConditionErrorOccurred {
+			found = true
+			assert.Equal(t, argoprojiov1alpha1.ApplicationSetConditionStatusTrue, condition.Status)
+			assert.Equal(t, "ApplicationSet name contains applications with duplicate name: app1", condition.Message)
+			assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonApplicationNameConflict, condition.Reason)
+		}
+	}
+	assert.True(t, found)
+	if assert.NotNil(t, got.Status.Parameters) {
+		assert.Equal(t, int64(4), got.Status.Parameters.Count)
+	}
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+)
+
+// clusterDecisionResourceEventsBufferSize is the number of ApplicationSet events which may be pending before the
+// informers block.
+const clusterDecisionResourceEventsBufferSize = 100
+
+// ClusterDecisionResourceWatcher watches the duck-typed resources referenced by ClusterDecisionResource generators,
+// and requeues the ApplicationSets using them when one of the resources changes, rather than waiting for the
+// generator's requeueAfterSeconds to elapse.
+//
+// The resource kinds to watch are only known once the ConfigMap of a generator has been read, so an informer is
+// started lazily the first time an ApplicationSet referencing a new kind is reconciled.
+type ClusterDecisionResourceWatcher struct {
+	ctx           context.Context
+	client        client.Client
+	dynClient     dynamic.Interface
+	kubeClientset kubernetes.Interface
+	namespace     string // namespace is the Argo CD namespace
+	log           log.FieldLogger
+
+	events chan event.GenericEvent
+
+	lock     sync.Mutex
+	watching map[schema.GroupVersionResource]bool
+}
+
+func NewClusterDecisionResourceWatcher(ctx context.Context, client client.Client, dynClient dynamic.Interface, kubeClientset kubernetes.Interface, namespace string) *ClusterDecisionResourceWatcher {
+	return &ClusterDecisionResourceWatcher{
+		ctx:           ctx,
+		client:        client,
+		dynClient:     dynClient,
+		kubeClientset: kubeClientset,
+		namespace:     namespace,
+		log:           log.WithField("type", "clusterDecisionResourceWatcher"),
+		events:        make(chan event.GenericEvent, clusterDecisionResourceEventsBufferSize),
+		watching:      map[schema.GroupVersionResource]bool{},
+	}
+}
+
+// Source returns the source of the events the controller should watch to requeue ApplicationSets.
+func (w *ClusterDecisionResourceWatcher) Source() source.Source {
+	return &source.Channel{Source: w.events}
+}
+
+// WatchApplicationSet starts watching the resource kinds referenced by the ClusterDecisionResource generators of the
+// ApplicationSet, if they are not watched yet.
+func (w *ClusterDecisionResourceWatcher) WatchApplicationSet(appSet *argoprojiov1alpha1.ApplicationSet) {
+	for _, gen := range getClusterDecisionResourceGenerators(appSet) {
+		gvr, err := w.getGVR(gen)
+		if err != nil {
+			w.log.WithError(err).WithField("configMapRef", gen.ConfigMapRef).Warn("unable to determine clusterDecisionResource kind to watch")
+			continue
+		}
+		w.watch(gvr)
+	}
+}
+
+func (w *ClusterDecisionResourceWatcher) watch(gvr schema.GroupVersionResource) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.watching[gvr] {
+		return
+	}
+	w.watching[gvr] = true
+
+	w.log.WithField("resource", gvr.String()).Info("watching clusterDecisionResource kind")
+	informer := dynamicinformer.NewFilteredDynamicInformer(w.dynClient, gvr, w.namespace, 0, cache.Indexers{}, nil).Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.queueRelatedAppSets(gvr, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			w.queueRelatedAppSets(gvr, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			w.queueRelatedAppSets(gvr, obj)
+		},
+	})
+	go informer.Run(w.ctx.Done())
+}
+
+func (w *ClusterDecisionResourceWatcher) queueRelatedAppSets(gvr schema.GroupVersionResource, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	resource, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	for _, appSet := range w.getRelatedAppSets(gvr, resource) {
+		w.log.WithFields(log.Fields{
+			"resource":       gvr.String(),
+			"name":           resource.GetName(),
+			"applicationset": appSet.Name,
+		}).Info("requeue ApplicationSet for clusterDecisionResource change")
+		w.events <- event.GenericEvent{Object: appSet}
+	}
+}
+
+// getRelatedAppSets returns the ApplicationSets with a ClusterDecisionResource generator selecting the given resource.
+func (w *ClusterDecisionResourceWatcher) getRelatedAppSets(gvr schema.GroupVersionResource, resource *unstructured.Unstructured) []*argoprojiov1alpha1.ApplicationSet {
+	appSetList := &argoprojiov1alpha1.ApplicationSetList{}
+	if err := w.client.List(w.ctx, appSetList, client.InNamespace(w.namespace)); err != nil {
+		w.log.WithError(err).Error("unable to list ApplicationSets")
+		return nil
+	}
+
+	related := []*argoprojiov1alpha1.ApplicationSet{}
+	for i := range appSetList.Items {
+		appSet := &appSetList.Items[i]
+		for _, gen := range getClusterDecisionResourceGenerators(appSet) {
+			genGVR, err := w.getGVR(gen)
+			if err != nil || genGVR != gvr {
+				continue
+			}
+			if generatorSelectsResource(gen, resource) {
+				related = append(related, appSet)
+				break
+			}
+		}
+	}
+	return related
+}
+
+func (w *ClusterDecisionResourceWatcher) getGVR(gen *argoprojiov1alpha1.DuckTypeGenerator) (schema.GroupVersionResource, error) {
+	cm, err := w.kubeClientset.CoreV1().ConfigMaps(w.namespace).Get(w.ctx, gen.ConfigMapRef, metav1.GetOptions{})
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return generators.GetDuckTypeGVR(cm)
+}
+
+// generatorSelectsResource returns true if the resource is selected by the name or the label selector of the generator.
+func generatorSelectsResource(gen *argoprojiov1alpha1.DuckTypeGenerator, resource *unstructured.Unstructured) bool {
+	if gen.Name != "" {
+		return gen.Name == resource.GetName()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&gen.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(resource.GetLabels()))
+}
+
+// getClusterDecisionResourceGenerators returns the ClusterDecisionResource generators of the ApplicationSet, including
+// the ones nested within Matrix, Merge and Union generators.
+func getClusterDecisionResourceGenerators(appSet *argoprojiov1alpha1.ApplicationSet) []*argoprojiov1alpha1.DuckTypeGenerator {
+	gens := []*argoprojiov1alpha1.DuckTypeGenerator{}
+	addTerminal := func(terminal []argoprojiov1alpha1.ApplicationSetTerminalGenerator) {
+		for _, gen := range terminal {
+			if gen.ClusterDecisionResource != nil {
+				gens = append(gens, gen.ClusterDecisionResource)
+			}
+		}
+	}
+	addNested := func(nested []argoprojiov1alpha1.ApplicationSetNestedGenerator) {
+		for _, gen := range nested {
+			if gen.ClusterDecisionResource != nil {
+				gens = append(gens, gen.ClusterDecisionResource)
+			}
+			if gen.Matrix != nil {
+				addTerminal(gen.Matrix.Generators)
+			}
+			if gen.Merge != nil {
+				addTerminal(gen.Merge.Generators)
+			}
+			if gen.Union != nil {
+				addTerminal(gen.Union.Generators)
+			}
+		}
+	}
+
+	for _, gen := range appSet.Spec.Generators {
+		if gen.ClusterDecisionResource != nil {
+			gens = append(gens, gen.ClusterDecisionResource)
+		}
+		if gen.Matrix != nil {
+			addNested(gen.Matrix.Generators)
+		}
+		if gen.Merge != nil {
+			addNested(gen.Merge.Generators)
+		}
+		if gen.Union != nil {
+			addNested(gen.Union.Generators)
+		}
+	}
+	return gens
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestClusterDecisionResourceWatcherRelatedAppSets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "my-configmap",
+			Namespace: "argocd",
+		},
+		Data: map[string]string{
+			"apiVersion": "mallard.io/v1",
+			"kind":       "ducks",
+		},
+	}
+	duckGVR := schema.GroupVersionResource{Group: "mallard.io", Version: "v1", Resource: "ducks"}
+
+	appSetWithGenerator := func(name string, gen argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSet {
+		return &argoprojiov1alpha1.ApplicationSet{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "argocd"},
+			Spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{gen},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		appSetWithGenerator("by-name", argoprojiov1alpha1.ApplicationSetGenerator{
+			ClusterDecisionResource: &argoprojiov1alpha1.DuckTypeGenerator{ConfigMapRef: "my-configmap", Name: "quak"},
+		}),
+		appSetWithGenerator("by-label", argoprojiov1alpha1.ApplicationSetGenerator{
+			ClusterDecisionResource: &argoprojiov1alpha1.DuckTypeGenerator{
+				ConfigMapRef:  "my-configmap",
+				LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"duck": "spotted"}},
+			},
+		}),
+		appSetWithGenerator("nested-in-matrix", argoprojiov1alpha1.ApplicationSetGenerator{
+			Matrix: &argoprojiov1alpha1.MatrixGenerator{
+				Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					{List: &argoprojiov1alpha1.ListGenerator{}},
+					{ClusterDecisionResource: &argoprojiov1alpha1.DuckTypeGenerator{ConfigMapRef: "my-configmap", Name: "quak"}},
+				},
+			},
+		}),
+		appSetWithGenerator("other-configmap", argoprojiov1alpha1.ApplicationSetGenerator{
+			ClusterDecisionResource: &argoprojiov1alpha1.DuckTypeGenerator{ConfigMapRef: "missing-configmap", Name: "quak"},
+		}),
+		appSetWithGenerator("list", argoprojiov1alpha1.ApplicationSetGenerator{
+			List: &argoprojiov1alpha1.ListGenerator{},
+		}),
+	).Build()
+
+	watcher := NewClusterDecisionResourceWatcher(context.Background(), fakeClient, nil, kubefake.NewSimpleClientset(configMap), "argocd")
+
+	tests := []struct {
+		name     string
+		gvr      schema.GroupVersionResource
+		resource *unstructured.Unstructured
+		expected []string
+	}{
+		{
+			name:     "resource selected by name",
+			gvr:      duckGVR,
+			resource: newDuck("quak", map[string]string{"duck": "canvasback"}),
+			expected: []string{"by-name", "nested-in-matrix"},
+		},
+		{
+			name:     "resource selected by label",
+			gvr:      duckGVR,
+			resource: newDuck("other", map[string]string{"duck": "spotted"}),
+			expected: []string{"by-label"},
+		},
+		{
+			name:     "resource selected by name and label",
+			gvr:      duckGVR,
+			resource: newDuck("quak", map[string]string{"duck": "spotted"}),
+			expected: []string{"by-name", "by-label", "nested-in-matrix"},
+		},
+		{
+			name:     "resource of another kind",
+			gvr:      schema.GroupVersionResource{Group: "mallard.io", Version: "v1", Resource: "geese"},
+			resource: newDuck("quak", map[string]string{"duck": "spotted"}),
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, appSet := range watcher.getRelatedAppSets(test.gvr, test.resource) {
+				got = append(got, appSet.Name)
+			}
+			assert.ElementsMatch(t, test.expected, got)
+		})
+	}
+}
+
+func newDuck(name string, labels map[string]string) *unstructured.Unstructured {
+	duck := &unstructured.Unstructured{}
+	duck.SetAPIVersion("mallard.io/v1")
+	duck.SetKind("Duck")
+	duck.SetNamespace("argocd")
+	duck.SetName(name)
+	duck.SetLabels(labels)
+	return duck
+}
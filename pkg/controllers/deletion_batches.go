@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// defaultDeletionBatchInterval is the delay between two batches of deletions, if the ApplicationSet doesn't set it.
+const defaultDeletionBatchInterval = 10 * time.Second
+
+// getDeletionBatch returns the Applications to delete now, out of the Applications of the ApplicationSet which are no
+// longer generated, and the delay before deleting the next batch, 0 if they are all deleted now. If the ApplicationSet
+// deletes its Applications by batches, no Application is deleted until the interval since the last batch has elapsed,
+// and the time of the batch is recorded in the status of the ApplicationSet.
+func (r *ApplicationSetReconciler) getDeletionBatch(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, deletions []argov1alpha1.Application) ([]argov1alpha1.Application, time.Duration, error) {
+	var batches *argoprojiov1alpha1.ApplicationSetDeletionBatches
+	if applicationSet.Spec.SyncPolicy != nil {
+		batches = applicationSet.Spec.SyncPolicy.DeletionBatches
+	}
+	if batches == nil || len(deletions) == 0 {
+		return deletions, 0, nil
+	}
+
+	interval := defaultDeletionBatchInterval
+	if batches.Interval != nil {
+		interval = batches.Interval.Duration
+	}
+	if last := applicationSet.Status.LastDeletionBatchTime; last != nil {
+		if wait := time.Until(last.Add(interval)); wait > 0 {
+			return nil, wait, nil
+		}
+	}
+
+	size := int(batches.Size)
+	if size < 1 {
+		size = 1
+	}
+	var requeueAfter time.Duration
+	if size < len(deletions) {
+		// The next batch is deleted at the next reconciliation after the interval, which must be requeued even if the
+		// interval is 0
+		requeueAfter = interval
+		if requeueAfter <= 0 {
+			requeueAfter = time.Second
+		}
+	} else {
+		size = len(deletions)
+	}
+
+	now := metav1.Now()
+	err := r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.LastDeletionBatchTime = &now
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return deletions[:size], requeueAfter, nil
+}
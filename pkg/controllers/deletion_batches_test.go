@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestDeleteInClusterByBatches(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		name                  string
+		deletionBatches       *argoprojiov1alpha1.ApplicationSetDeletionBatches
+		lastDeletionBatchTime *metav1.Time
+		expectedRemaining     int
+		expectedRequeueAfter  time.Duration
+	}{
+		{
+			name:              "Applications deleted all at once",
+			expectedRemaining: 0,
+		},
+		{
+			name:                 "first batch",
+			deletionBatches:      &argoprojiov1alpha1.ApplicationSetDeletionBatches{Size: 2, Interval: &metav1.Duration{Duration: time.Minute}},
+			expectedRemaining:    3,
+			expectedRequeueAfter: time.Minute,
+		},
+		{
+			name:                  "interval since the last batch not elapsed",
+			deletionBatches:       &argoprojiov1alpha1.ApplicationSetDeletionBatches{Size: 2, Interval: &metav1.Duration{Duration: time.Minute}},
+			lastDeletionBatchTime: &metav1.Time{Time: time.Now().Add(-20 * time.Second)},
+			expectedRemaining:     5,
+			expectedRequeueAfter:  40 * time.Second,
+		},
+		{
+			name:                  "interval since the last batch elapsed",
+			deletionBatches:       &argoprojiov1alpha1.ApplicationSetDeletionBatches{Size: 2, Interval: &metav1.Duration{Duration: time.Minute}},
+			lastDeletionBatchTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			expectedRemaining:     3,
+			expectedRequeueAfter:  time.Minute,
+		},
+		{
+			name:                 "last batch",
+			deletionBatches:      &argoprojiov1alpha1.ApplicationSetDeletionBatches{Size: 5},
+			expectedRemaining:    0,
+			expectedRequeueAfter: 0,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					SyncPolicy: &argoprojiov1alpha1.ApplicationSetSyncPolicy{DeletionBatches: cc.deletionBatches},
+				},
+				Status: argoprojiov1alpha1.ApplicationSetStatus{
+					LastDeletionBatchTime: cc.lastDeletionBatchTime,
+				},
+			}
+			initObjs := []crtclient.Object{&appSet}
+			for i := 1; i <= 5; i++ {
+				app := &argov1alpha1.Application{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app%d", i), Namespace: "namespace"},
+					Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+				}
+				err := controllerutil.SetControllerReference(&appSet, app, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, app)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := ApplicationSetReconciler{
+				Client:        client,
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(5),
+				KubeClientset: kubefake.NewSimpleClientset(),
+			}
+
+			requeueAfter, err := r.deleteInCluster(context.TODO(), appSet, nil)
+			assert.NoError(t, err)
+			assert.InDelta(t, cc.expectedRequeueAfter, requeueAfter, float64(time.Second))
+
+			var apps argov1alpha1.ApplicationList
+			assert.NoError(t, client.List(context.TODO(), &apps))
+			assert.Len(t, apps.Items, cc.expectedRemaining)
+
+			got := &argoprojiov1alpha1.ApplicationSet{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+			assert.Nil(t, err)
+			if cc.deletionBatches != nil && cc.expectedRemaining < 5 {
+				assert.NotNil(t, got.Status.LastDeletionBatchTime)
+				assert.WithinDuration(t, time.Now(), got.Status.LastDeletionBatchTime.Time, 2*time.Second)
+			}
+		})
+	}
+}
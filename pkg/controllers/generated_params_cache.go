@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/engine"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+)
+
+// generatedParamsCache keeps the last known results of the generators of the ApplicationSets, so that the
+// Applications of a generator which transiently fails are generated from its last known parameters, rather than left
+// out. The results are only kept in memory, and are discarded when the spec of the ApplicationSet changes.
+type generatedParamsCache struct {
+	lock sync.Mutex
+	// entries are the last known results, by ApplicationSet namespace/name
+	entries map[string]*generatedParamsCacheEntry
+}
+
+var _ engine.LastKnownParams = &generatedParamsCache{}
+
+type generatedParamsCacheEntry struct {
+	// generation is the generation of the ApplicationSet whose generators produced the results
+	generation int64
+	// results are the results of each generator, by index in the generators of the ApplicationSet
+	results map[int][]generators.TransformResult
+}
+
+// get returns the last known results of the generator of the ApplicationSet at the given generation, if any.
+func (c *generatedParamsCache) get(key string, generation int64, generatorIndex int) ([]generators.TransformResult, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.generation != generation {
+		return nil, false
+	}
+	results, ok := entry.results[generatorIndex]
+	return results, ok
+}
+
+// set records the results of the generator of the ApplicationSet at the given generation, discarding the results
+// recorded for other generations.
+func (c *generatedParamsCache) set(key string, generation int64, generatorIndex int, results []generators.TransformResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]*generatedParamsCacheEntry{}
+	}
+	entry, ok := c.entries[key]
+	if !ok || entry.generation != generation {
+		entry = &generatedParamsCacheEntry{
+			generation: generation,
+			results:    map[int][]generators.TransformResult{},
+		}
+		c.entries[key] = entry
+	}
+	entry.results[generatorIndex] = results
+}
+
+// Get returns the last known results of the generator of the ApplicationSet at its current generation, if any.
+func (c *generatedParamsCache) Get(appSet *argoprojiov1alpha1.ApplicationSet, generatorIndex int) ([]generators.TransformResult, bool) {
+	return c.get(generatedParamsCacheKey(appSet), appSet.Generation, generatorIndex)
+}
+
+// Set records the results of the generator of the ApplicationSet at its current generation.
+func (c *generatedParamsCache) Set(appSet *argoprojiov1alpha1.ApplicationSet, generatorIndex int, results []generators.TransformResult) {
+	c.set(generatedParamsCacheKey(appSet), appSet.Generation, generatorIndex, results)
+}
+
+// delete discards the results of the ApplicationSet, once it is deleted.
+func (c *generatedParamsCache) delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, key)
+}
+
+// generatedParamsCacheKey returns the key of the results of the ApplicationSet, its namespace/name.
+func generatedParamsCacheKey(appSet *argoprojiov1alpha1.ApplicationSet) string {
+	return types.NamespacedName{Namespace: appSet.Namespace, Name: appSet.Name}.String()
+}
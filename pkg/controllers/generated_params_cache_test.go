@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	"github.com/argoproj-labs/applicationset/pkg/engine"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestGenerateApplicationsWithLastKnownParams(t *testing.T) {
+	generator := argoprojiov1alpha1.ApplicationSetGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{},
+	}
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "name",
+			Namespace:  "argocd",
+			Generation: 1,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{generator},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}"},
+				Spec:                       argov1alpha1.ApplicationSpec{Project: "default"},
+			},
+		},
+	}
+
+	generatorMock := generatorMock{}
+	generatorMock.On("GetTemplate", &generator).
+		Return(&argoprojiov1alpha1.ApplicationSetTemplate{})
+	generatorMock.On("GenerateParams", &generator).
+		Return([]map[string]interface{}{{"name": "app1"}, {"name": "app2"}}, nil).Once()
+	generatorMock.On("GenerateParams", &generator).
+		Return([]map[string]interface{}{}, errors.New("GitHub returned 500"))
+
+	r := ApplicationSetReconciler{
+		Recorder: record.NewFakeRecorder(1),
+		Generators: map[string]generators.Generator{
+			"List": &generatorMock,
+		},
+		Renderer: &utils.Render{},
+	}
+
+	got, parameters, _, err := r.generateApplications(context.TODO(), appSet)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	if assert.NotNil(t, parameters) {
+		assert.Equal(t, int64(2), parameters.Count)
+		assert.Len(t, parameters.Hash, 64)
+		assert.Equal(t, []argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{{Generator: "List", Count: 2}}, parameters.Generators)
+	}
+
+	// The generator fails: its last known parameters are used
+	got, staleParameters, reason, err := r.generateApplications(context.TODO(), appSet)
+	var staleErr *engine.StaleParamsError
+	assert.True(t, errors.As(err, &staleErr))
+	assert.Contains(t, err.Error(), "GitHub returned 500")
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonType(argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError), reason)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "app1", got[0].Name)
+		assert.Equal(t, "app2", got[1].Name)
+	}
+	if assert.NotNil(t, staleParameters) {
+		assert.Equal(t, parameters.Hash, staleParameters.Hash)
+		assert.Equal(t, []argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{{Generator: "List", Count: 2, Stale: true}}, staleParameters.Generators)
+	}
+
+	// The last known parameters are discarded by a hard refresh
+	appSet.Annotations = map[string]string{common.AnnotationApplicationSetRefresh: common.RefreshTypeHard}
+	_, parameters, _, err = r.generateApplications(context.TODO(), appSet)
+	assert.EqualError(t, err, "GitHub returned 500")
+	assert.Nil(t, parameters)
+	appSet.Annotations = nil
+	_, parameters, _, err = r.generateApplications(context.TODO(), appSet)
+	assert.EqualError(t, err, "GitHub returned 500")
+	assert.Nil(t, parameters)
+
+	// The last known parameters are discarded when the spec of the ApplicationSet changes
+	appSet.Generation = 2
+	_, parameters, _, err = r.generateApplications(context.TODO(), appSet)
+	assert.EqualError(t, err, "GitHub returned 500")
+	assert.Nil(t, parameters)
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// GeneratorBackoff is the backoff of the reconciliation of the ApplicationSets whose generators fail in consecutive
+// reconciliations, so that a failing generator, such as an unavailable SCM provider, isn't retried at the full rate.
+type GeneratorBackoff struct {
+	// ErrorBudget is the number of consecutive failures which are retried without backing off.
+	ErrorBudget int64
+	// BaseDelay is the delay before retrying after the first failure exceeding the error budget. It doubles at each
+	// subsequent failure.
+	BaseDelay time.Duration
+	// MaxDelay, if set, is the maximum delay before retrying.
+	MaxDelay time.Duration
+}
+
+// Delay returns the delay before retrying after the given number of consecutive failures, 0 if the failures are
+// within the error budget.
+func (b *GeneratorBackoff) Delay(consecutiveFailures int64) time.Duration {
+	exceeded := consecutiveFailures - b.ErrorBudget
+	if exceeded <= 0 || b.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := b.BaseDelay
+	for i := int64(1); i < exceeded && delay <= math.MaxInt64/2; i++ {
+		if b.MaxDelay > 0 && delay >= b.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	return delay
+}
+
+// getGeneratorBackoffDelay returns how long the generators of the ApplicationSet must wait before running again,
+// 0 if they may run now. The ApplicationSets whose spec changed since the last failure, or which are refreshed, are not
+// delayed.
+func (r *ApplicationSetReconciler) getGeneratorBackoffDelay(applicationSet *argoprojiov1alpha1.ApplicationSet) time.Duration {
+	backoff := applicationSet.Status.GeneratorBackoff
+	if r.GeneratorBackoff == nil || backoff == nil || backoff.NextRetryTime == nil {
+		return 0
+	}
+	if backoff.ObservedGeneration != applicationSet.Generation || applicationSet.RefreshRequired() {
+		return 0
+	}
+	if delay := time.Until(backoff.NextRetryTime.Time); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// recordGeneratorFailure records a failure of the generators in the status of the ApplicationSet, and returns the
+// delay before running them again.
+func (r *ApplicationSetReconciler) recordGeneratorFailure(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) (time.Duration, error) {
+	var delay time.Duration
+	err := r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		consecutiveFailures := int64(1)
+		if status.GeneratorBackoff != nil && status.GeneratorBackoff.ObservedGeneration == applicationSet.Generation {
+			consecutiveFailures = status.GeneratorBackoff.ConsecutiveFailures + 1
+		}
+
+		backoff := &argoprojiov1alpha1.ApplicationSetGeneratorBackoffStatus{
+			ConsecutiveFailures: consecutiveFailures,
+			ObservedGeneration:  applicationSet.Generation,
+		}
+		delay = r.GeneratorBackoff.Delay(consecutiveFailures)
+		if delay > 0 {
+			nextRetryTime := metav1.NewTime(time.Now().Add(delay))
+			backoff.NextRetryTime = &nextRetryTime
+		}
+		status.GeneratorBackoff = backoff
+	})
+	return delay, err
+}
+
+// resetGeneratorBackoff removes the backoff of the generators from the status of the ApplicationSet, once they
+// succeed.
+func (r *ApplicationSetReconciler) resetGeneratorBackoff(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet) error {
+	if applicationSet.Status.GeneratorBackoff == nil {
+		return nil
+	}
+	return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.GeneratorBackoff = nil
+	})
+}
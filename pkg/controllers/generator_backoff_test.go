@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGeneratorBackoffDelay(t *testing.T) {
+	backoff := GeneratorBackoff{
+		ErrorBudget: 2,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    time.Minute,
+	}
+
+	for _, c := range []struct {
+		consecutiveFailures int64
+		expected            time.Duration
+	}{
+		{consecutiveFailures: 1, expected: 0},
+		{consecutiveFailures: 2, expected: 0},
+		{consecutiveFailures: 3, expected: 10 * time.Second},
+		{consecutiveFailures: 4, expected: 20 * time.Second},
+		{consecutiveFailures: 5, expected: 40 * time.Second},
+		{consecutiveFailures: 6, expected: time.Minute},
+		{consecutiveFailures: 1000, expected: time.Minute},
+	} {
+		assert.Equal(t, c.expected, backoff.Delay(c.consecutiveFailures), "%d consecutive failures", c.consecutiveFailures)
+	}
+
+	unbounded := GeneratorBackoff{BaseDelay: time.Second}
+	assert.Greater(t, int64(unbounded.Delay(1000)), int64(0))
+}
+
+func TestReconcileGeneratorBackoff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	generator := argoprojiov1alpha1.ApplicationSetGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{},
+	}
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{generator},
+		},
+	}
+
+	generatorMock := generatorMock{}
+	generatorMock.On("GenerateParams", &generator).
+		Return([]map[string]interface{}{}, errors.New("SCM provider unavailable"))
+	generatorMock.On("GetTemplate", &generator).
+		Return(&argoprojiov1alpha1.ApplicationSetTemplate{})
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+	recorder := record.NewFakeRecorder(2)
+
+	r := ApplicationSetReconciler{
+		Log:      ctrl.Log.WithName("controllers").WithName("ApplicationSet"),
+		Client:   client,
+		Scheme:   scheme,
+		Renderer: &utils.Render{},
+		Recorder: recorder,
+		Generators: map[string]generators.Generator{
+			"List": &generatorMock,
+		},
+		KubeClientset: kubefake.NewSimpleClientset(),
+		Policy:        &utils.SyncPolicy{},
+		GeneratorBackoff: &GeneratorBackoff{
+			ErrorBudget: 1,
+			BaseDelay:   time.Minute,
+			MaxDelay:    10 * time.Minute,
+		},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "name"}}
+	getBackoff := func() *argoprojiov1alpha1.ApplicationSetGeneratorBackoffStatus {
+		var got argoprojiov1alpha1.ApplicationSet
+		err := client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "name"}, &got)
+		assert.NoError(t, err)
+		return got.Status.GeneratorBackoff
+	}
+
+	// The first failure is within the error budget
+	res, err := r.Reconcile(context.Background(), req)
+	assert.Error(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	backoff := getBackoff()
+	if assert.NotNil(t, backoff) {
+		assert.Equal(t, int64(1), backoff.ConsecutiveFailures)
+		assert.Nil(t, backoff.NextRetryTime)
+	}
+	assert.Contains(t, <-recorder.Events, "Warning ApplicationGenerationFromParamsError Failed to generate Applications")
+
+	// The second failure exceeds the error budget
+	res, err = r.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, res.RequeueAfter)
+	backoff = getBackoff()
+	if assert.NotNil(t, backoff) {
+		assert.Equal(t, int64(2), backoff.ConsecutiveFailures)
+		assert.NotNil(t, backoff.NextRetryTime)
+	}
+
+	// The generators don't run again before the next retry time
+	res, err = r.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Greater(t, int64(res.RequeueAfter), int64(0))
+	assert.LessOrEqual(t, int64(res.RequeueAfter), int64(time.Minute))
+	generatorMock.AssertNumberOfCalls(t, "GenerateParams", 2)
+	assert.Equal(t, int64(2), getBackoff().ConsecutiveFailures)
+
+	// The backoff is removed once the generators succeed
+	var current argoprojiov1alpha1.ApplicationSet
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "argocd", Name: "name"}, &current)
+	assert.NoError(t, err)
+	err = r.resetGeneratorBackoff(context.TODO(), &current)
+	assert.NoError(t, err)
+	assert.Nil(t, getBackoff())
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+// applicationHistory collects the changes made to the Applications of an ApplicationSet during a reconciliation, which
+// are recorded in the history of its status at the end of the reconciliation.
+type applicationHistory struct {
+	reconcileID string
+	entries     []argoprojiov1alpha1.ApplicationSetHistoryEntry
+}
+
+type applicationHistoryKey struct{}
+
+// newApplicationHistory returns the history of the changes of the reconciliation of the ApplicationSet, or nil if the
+// ApplicationSet doesn't record its history.
+func newApplicationHistory(applicationSet *argoprojiov1alpha1.ApplicationSet, reconcileID string) *applicationHistory {
+	if applicationSet.HistoryLimit() == 0 {
+		return nil
+	}
+	return &applicationHistory{reconcileID: reconcileID}
+}
+
+// contextWithHistory returns a copy of the context carrying the history of the changes of the reconciliation.
+func contextWithHistory(ctx context.Context, history *applicationHistory) context.Context {
+	return context.WithValue(ctx, applicationHistoryKey{}, history)
+}
+
+// historyFromContext returns the history of the changes of the reconciliation carried by the context, or nil if the
+// changes are not recorded.
+func historyFromContext(ctx context.Context) *applicationHistory {
+	history, _ := ctx.Value(applicationHistoryKey{}).(*applicationHistory)
+	return history
+}
+
+// add records a change to an Application, with its hashes before and after the change. previous is nil, or not
+// persisted, for a creation, and current is nil for a deletion.
+func (h *applicationHistory) add(action argoprojiov1alpha1.ApplicationSetDryRunActionType, name string, previous *argov1alpha1.Application, current *argov1alpha1.Application) {
+	if h == nil {
+		return
+	}
+	entry := argoprojiov1alpha1.ApplicationSetHistoryEntry{
+		Time:        metav1.Now(),
+		Application: name,
+		Action:      action,
+		ReconcileID: h.reconcileID,
+	}
+	if previous != nil && previous.ResourceVersion != "" {
+		entry.PreviousParamsHash = previous.Annotations[common.AnnotationApplicationSetParamsHash]
+		entry.PreviousRenderedHash = previous.Annotations[common.AnnotationApplicationSetRenderedHash]
+	}
+	if current != nil {
+		entry.ParamsHash = current.Annotations[common.AnnotationApplicationSetParamsHash]
+		entry.RenderedHash = current.Annotations[common.AnnotationApplicationSetRenderedHash]
+	}
+	h.entries = append(h.entries, entry)
+}
+
+// recordHistory appends the changes of the reconciliation to the history of the status of the ApplicationSet, keeping
+// only the most recent entries up to its history limit. The history is removed once the ApplicationSet no longer
+// records it.
+func (r *ApplicationSetReconciler) recordHistory(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, history *applicationHistory) error {
+	limit := applicationSet.HistoryLimit()
+	if history == nil || len(history.entries) == 0 {
+		if len(applicationSet.Status.History) <= limit {
+			return nil
+		}
+		return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+			status.History = appendHistory(status.History, nil, limit)
+		})
+	}
+
+	return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.History = appendHistory(status.History, history.entries, limit)
+	})
+}
+
+// appendHistory appends the entries to the history, and removes its oldest entries beyond the limit.
+func appendHistory(history []argoprojiov1alpha1.ApplicationSetHistoryEntry, entries []argoprojiov1alpha1.ApplicationSetHistoryEntry, limit int) []argoprojiov1alpha1.ApplicationSetHistoryEntry {
+	res := append(append([]argoprojiov1alpha1.ApplicationSetHistoryEntry{}, history...), entries...)
+	if len(res) > limit {
+		res = res[len(res)-limit:]
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
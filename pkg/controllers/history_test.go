@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestRecordHistory(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	limit := int64(3)
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			HistoryLimit: &limit,
+		},
+		Status: argoprojiov1alpha1.ApplicationSetStatus{
+			History: []argoprojiov1alpha1.ApplicationSetHistoryEntry{
+				{Application: "app1", Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate},
+				{Application: "app2", Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	history := newApplicationHistory(&appSet, "reconcile-id")
+	ctx := contextWithHistory(context.TODO(), history)
+	previous := &argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app1",
+			ResourceVersion: "1",
+			Annotations: map[string]string{
+				common.AnnotationApplicationSetParamsHash:   "params-1",
+				common.AnnotationApplicationSetRenderedHash: "rendered-1",
+			},
+		},
+	}
+	current := &argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "app1",
+			Annotations: map[string]string{
+				common.AnnotationApplicationSetParamsHash:   "params-2",
+				common.AnnotationApplicationSetRenderedHash: "rendered-2",
+			},
+		},
+	}
+	historyFromContext(ctx).add(argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, "app1", previous, current)
+	historyFromContext(ctx).add(argoprojiov1alpha1.ApplicationSetDryRunActionDelete, "app2", &argov1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app2", ResourceVersion: "1"}}, nil)
+	// Changes are not recorded without a history in the context
+	historyFromContext(context.TODO()).add(argoprojiov1alpha1.ApplicationSetDryRunActionDelete, "app3", nil, nil)
+
+	err = r.recordHistory(ctx, &appSet, history)
+	assert.NoError(t, err)
+
+	got := &argoprojiov1alpha1.ApplicationSet{}
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+	assert.Nil(t, err)
+	if assert.Len(t, got.Status.History, 3) {
+		assert.Equal(t, "app2", got.Status.History[0].Application)
+
+		update := got.Status.History[1]
+		assert.Equal(t, "app1", update.Application)
+		assert.Equal(t, argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, update.Action)
+		assert.Equal(t, "reconcile-id", update.ReconcileID)
+		assert.Equal(t, "params-1", update.PreviousParamsHash)
+		assert.Equal(t, "params-2", update.ParamsHash)
+		assert.Equal(t, "rendered-1", update.PreviousRenderedHash)
+		assert.Equal(t, "rendered-2", update.RenderedHash)
+		assert.False(t, update.Time.IsZero())
+
+		deletion := got.Status.History[2]
+		assert.Equal(t, "app2", deletion.Application)
+		assert.Equal(t, argoprojiov1alpha1.ApplicationSetDryRunActionDelete, deletion.Action)
+		assert.Empty(t, deletion.ParamsHash)
+	}
+
+	// The history is removed once it is no longer recorded
+	got.Spec.HistoryLimit = nil
+	assert.Nil(t, newApplicationHistory(got, "reconcile-id"))
+	err = r.recordHistory(context.TODO(), got, nil)
+	assert.NoError(t, err)
+	err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+	assert.Nil(t, err)
+	assert.Empty(t, got.Status.History)
+}
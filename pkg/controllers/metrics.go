@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"context"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// recordApplicationsMetrics records the number of the generated Applications of the ApplicationSet, of the valid ones,
+// and of its current Applications which are no longer generated.
+func (r *ApplicationSetReconciler) recordApplicationsMetrics(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, generatedApplications []argov1alpha1.Application, desired int) {
+	current, err := r.getCurrentApplications(ctx, *applicationSet)
+	if err != nil {
+		utils.LoggerFromContext(ctx).Warnf("error occurred while listing the Applications of the ApplicationSet for its metrics: %v", err)
+		return
+	}
+
+	generated := map[string]bool{}
+	for _, app := range generatedApplications {
+		generated[app.Name] = true
+	}
+	orphaned := 0
+	for _, app := range current {
+		if !generated[app.Name] {
+			orphaned++
+		}
+	}
+	metrics.SetApplications(applicationSet.Namespace, applicationSet.Name, len(generatedApplications), desired, orphaned)
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// ParametersStatusRefreshInterval is the interval at which the last generated time of the parameters status of an
+// ApplicationSet is refreshed while its param sets don't change. The status is not updated at each reconciliation, as
+// each update of the ApplicationSet triggers another reconciliation.
+const ParametersStatusRefreshInterval = 10 * time.Minute
+
+// setParametersStatus records the summary of the param sets generated by the generators in the status of the
+// ApplicationSet, if it changed, or if its last generated time is older than the refresh interval.
+func (r *ApplicationSetReconciler) setParametersStatus(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, parameters *argoprojiov1alpha1.ApplicationSetParametersStatus) error {
+	if parameters == nil {
+		return nil
+	}
+
+	// The status only keeps seconds
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	previous := applicationSet.Status.Parameters
+	if previous != nil && previous.LastGeneratedTime != nil && now.Sub(previous.LastGeneratedTime.Time) < ParametersStatusRefreshInterval &&
+		equalParametersStatus(previous, parameters) {
+		return nil
+	}
+
+	parameters = parameters.DeepCopy()
+	parameters.LastGeneratedTime = &now
+	return r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.Parameters = parameters
+	})
+}
+
+// equalParametersStatus returns true if the summaries of the param sets are the same, regardless of the time at which
+// they were generated.
+func equalParametersStatus(a *argoprojiov1alpha1.ApplicationSetParametersStatus, b *argoprojiov1alpha1.ApplicationSetParametersStatus) bool {
+	return a.Count == b.Count && a.Hash == b.Hash && reflect.DeepEqual(a.Generators, b.Generators)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestSetParametersStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	fiveMinutesAgo := metav1.NewTime(time.Now().Add(-5 * time.Minute).Truncate(time.Second))
+	twoHoursAgo := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+	parameters := argoprojiov1alpha1.ApplicationSetParametersStatus{
+		Count: 2,
+		Hash:  "hash",
+		Generators: []argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{
+			{Generator: "List", Count: 2},
+			{Generator: "Clusters", Count: 0},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		previous        *argoprojiov1alpha1.ApplicationSetParametersStatus
+		parameters      *argoprojiov1alpha1.ApplicationSetParametersStatus
+		expectUpdated   bool
+		expectUnchanged bool
+	}{
+		{
+			name:          "first generation",
+			parameters:    &parameters,
+			expectUpdated: true,
+		},
+		{
+			name: "unchanged param sets",
+			previous: func() *argoprojiov1alpha1.ApplicationSetParametersStatus {
+				p := parameters.DeepCopy()
+				p.LastGeneratedTime = &fiveMinutesAgo
+				return p
+			}(),
+			parameters:      &parameters,
+			expectUnchanged: true,
+		},
+		{
+			name: "unchanged param sets beyond the refresh interval",
+			previous: func() *argoprojiov1alpha1.ApplicationSetParametersStatus {
+				p := parameters.DeepCopy()
+				p.LastGeneratedTime = &twoHoursAgo
+				return p
+			}(),
+			parameters:    &parameters,
+			expectUpdated: true,
+		},
+		{
+			name: "changed param sets",
+			previous: &argoprojiov1alpha1.ApplicationSetParametersStatus{
+				Count:             1,
+				Hash:              "previous",
+				LastGeneratedTime: &fiveMinutesAgo,
+				Generators:        []argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{{Generator: "List", Count: 1}},
+			},
+			parameters:    &parameters,
+			expectUpdated: true,
+		},
+		{
+			name: "failed generators",
+			previous: func() *argoprojiov1alpha1.ApplicationSetParametersStatus {
+				p := parameters.DeepCopy()
+				p.LastGeneratedTime = &twoHoursAgo
+				return p
+			}(),
+			expectUnchanged: true,
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Status: argoprojiov1alpha1.ApplicationSetStatus{
+					Parameters: cc.previous,
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(1),
+			}
+
+			err := r.setParametersStatus(context.TODO(), &appSet, cc.parameters)
+			assert.NoError(t, err)
+
+			got := &argoprojiov1alpha1.ApplicationSet{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+			assert.Nil(t, err)
+			if cc.expectUnchanged {
+				assert.Equal(t, cc.previous.LastGeneratedTime.Time.Unix(), got.Status.Parameters.LastGeneratedTime.Time.Unix())
+			}
+			if cc.expectUpdated {
+				assert.Equal(t, cc.parameters.Count, got.Status.Parameters.Count)
+				assert.Equal(t, cc.parameters.Hash, got.Status.Parameters.Hash)
+				assert.Equal(t, cc.parameters.Generators, got.Status.Parameters.Generators)
+				assert.WithinDuration(t, time.Now(), got.Status.Parameters.LastGeneratedTime.Time, 2*time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// delayDeletions returns the Applications of the ApplicationSet which must not be deleted: the desired Applications,
+// and the Applications which are no longer generated, but whose prune grace period has not elapsed yet. It also
+// returns the delay before the next of these Applications is due to be deleted, 0 if there is none. The Applications
+// which are no longer generated are recorded in the status of the ApplicationSet, with the time from which they are no
+// longer generated, so that the grace period survives the restarts of the controller.
+func (r *ApplicationSetReconciler) delayDeletions(ctx context.Context, applicationSet *argoprojiov1alpha1.ApplicationSet, desiredApplications []argov1alpha1.Application) ([]argov1alpha1.Application, time.Duration, error) {
+	keptApplications := append([]argov1alpha1.Application{}, desiredApplications...)
+	var pendingDeletions []argoprojiov1alpha1.ApplicationSetPendingDeletion
+	var requeueAfter time.Duration
+
+	if pruneAfter := applicationSet.PruneAfter(); pruneAfter > 0 {
+		current, err := r.getCurrentApplications(ctx, *applicationSet)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		desired := map[string]bool{}
+		for _, app := range desiredApplications {
+			desired[app.Name] = true
+		}
+		previousSince := map[string]metav1.Time{}
+		for _, pendingDeletion := range applicationSet.Status.PendingDeletions {
+			previousSince[pendingDeletion.Name] = pendingDeletion.Since
+		}
+
+		// The status only keeps seconds, so the times are truncated to compare them with the previous ones
+		now := time.Now().Truncate(time.Second)
+		for _, app := range current {
+			if desired[app.Name] || isDeletionProtected(app) {
+				continue
+			}
+			since, found := previousSince[app.Name]
+			if !found {
+				since = metav1.NewTime(now)
+				r.recordEvent(applicationSet, corev1.EventTypeNormal, "PendingDeletion", "Application %q is no longer generated, and will be deleted after %s", app.Name, pruneAfter)
+			}
+			deletionTime := since.Add(pruneAfter)
+
+			// The Applications whose deletion is due stay in the status until they are deleted, so that their grace
+			// period doesn't start again if their deletion fails
+			pendingDeletions = append(pendingDeletions, argoprojiov1alpha1.ApplicationSetPendingDeletion{
+				Name:         app.Name,
+				Since:        since,
+				DeletionTime: metav1.NewTime(deletionTime),
+			})
+			if remaining := deletionTime.Sub(now); remaining > 0 {
+				keptApplications = append(keptApplications, app)
+				if requeueAfter == 0 || remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+			}
+		}
+	}
+
+	if equalPendingDeletions(pendingDeletions, applicationSet.Status.PendingDeletions) {
+		return keptApplications, requeueAfter, nil
+	}
+	err := r.updateApplicationSetStatus(ctx, applicationSet, func(status *argoprojiov1alpha1.ApplicationSetStatus) {
+		status.PendingDeletions = pendingDeletions
+	})
+	return keptApplications, requeueAfter, err
+}
+
+// equalPendingDeletions returns true if the pending deletions are the same, regardless of the location of their times.
+func equalPendingDeletions(a []argoprojiov1alpha1.ApplicationSetPendingDeletion, b []argoprojiov1alpha1.ApplicationSetPendingDeletion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !a[i].Since.Equal(&b[i].Since) || !a[i].DeletionTime.Equal(&b[i].DeletionTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestDelayDeletions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	twoHoursAgo := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+
+	for _, c := range []struct {
+		name                     string
+		pruneAfter               *metav1.Duration
+		expectedKept             []string
+		expectedPendingDeletions []string
+		expectedRequeueAfter     time.Duration
+	}{
+		{
+			name:         "Applications deleted right away",
+			expectedKept: []string{"app1"},
+		},
+		{
+			name:                     "Applications deleted after the prune grace period",
+			pruneAfter:               &metav1.Duration{Duration: time.Hour},
+			expectedKept:             []string{"app1", "app2"},
+			expectedPendingDeletions: []string{"app2", "app3"},
+			expectedRequeueAfter:     time.Hour,
+		},
+		{
+			name:                     "Applications deleted after a prune grace period longer than their absence",
+			pruneAfter:               &metav1.Duration{Duration: 3 * time.Hour},
+			expectedKept:             []string{"app1", "app2", "app3"},
+			expectedPendingDeletions: []string{"app2", "app3"},
+			expectedRequeueAfter:     time.Hour,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					SyncPolicy: &argoprojiov1alpha1.ApplicationSetSyncPolicy{PruneAfter: cc.pruneAfter},
+				},
+				Status: argoprojiov1alpha1.ApplicationSetStatus{
+					// app3 is no longer generated since two hours
+					PendingDeletions: []argoprojiov1alpha1.ApplicationSetPendingDeletion{
+						{Name: "app3", Since: twoHoursAgo, DeletionTime: twoHoursAgo},
+					},
+				},
+			}
+			initObjs := []crtclient.Object{&appSet}
+			for _, name := range []string{"app1", "app2", "app3"} {
+				app := &argov1alpha1.Application{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace"},
+					Spec:       argov1alpha1.ApplicationSpec{Project: "project"},
+				}
+				err := controllerutil.SetControllerReference(&appSet, app, scheme)
+				assert.Nil(t, err)
+				initObjs = append(initObjs, app)
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := ApplicationSetReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(1),
+			}
+
+			kept, requeueAfter, err := r.delayDeletions(context.TODO(), &appSet, []argov1alpha1.Application{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app1"}},
+			})
+			assert.NoError(t, err)
+
+			var keptNames []string
+			for _, app := range kept {
+				keptNames = append(keptNames, app.Name)
+			}
+			assert.Equal(t, cc.expectedKept, keptNames)
+			assert.InDelta(t, cc.expectedRequeueAfter, requeueAfter, float64(time.Second))
+
+			got := &argoprojiov1alpha1.ApplicationSet{}
+			err = client.Get(context.TODO(), crtclient.ObjectKey{Namespace: "namespace", Name: "name"}, got)
+			assert.Nil(t, err)
+			var pendingDeletionNames []string
+			for _, pendingDeletion := range got.Status.PendingDeletions {
+				pendingDeletionNames = append(pendingDeletionNames, pendingDeletion.Name)
+				if pendingDeletion.Name == "app3" {
+					assert.True(t, twoHoursAgo.Equal(&pendingDeletion.Since))
+					assert.True(t, pendingDeletion.DeletionTime.Equal(&metav1.Time{Time: twoHoursAgo.Add(cc.pruneAfter.Duration)}))
+				}
+			}
+			assert.Equal(t, cc.expectedPendingDeletions, pendingDeletionNames)
+		})
+	}
+}
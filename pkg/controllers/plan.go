@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// ApplicationChange is a change which would be made to an Application of an ApplicationSet.
+type ApplicationChange struct {
+	Action argoprojiov1alpha1.ApplicationSetDryRunActionType
+	Name   string
+	// Current is the Application in the cluster, nil for a creation.
+	Current *argov1alpha1.Application
+	// Desired is the Application as it would be created or updated, as returned by the API server in dry run mode,
+	// nil for a deletion.
+	Desired *argov1alpha1.Application
+}
+
+// IndexTrackedApplications indexes the Applications by the name of the ApplicationSet tracking them in their namespace,
+// as their owner or with the tracking annotation, which the reconciler lists the Applications of an ApplicationSet by.
+func IndexTrackedApplications(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &argov1alpha1.Application{}, ".metadata.controller", func(rawObj client.Object) []string {
+		// grab the job object, extract the application set tracking it, as its owner or with the tracking annotation...
+		app := rawObj.(*argov1alpha1.Application)
+		namespace, name, tracked := utils.GetTrackingApplicationSet(app)
+		// ...make sure it's in the same namespace...
+		if !tracked || namespace != app.Namespace {
+			return nil
+		}
+
+		// ...and if so, return it
+		return []string{name}
+	})
+}
+
+// PlanApplications returns the changes which the reconciliations of the ApplicationSet would make to its Applications,
+// without making them, e.g. to review the changes to an ApplicationSet before applying it. The ApplicationSet may
+// differ from the one in the cluster, and the Applications it tracks are the ones of the ApplicationSet of the same
+// name and UID. The creations and updates are sent to the API server in dry run mode, so that they are validated, and
+// the deletions are only listed. The changes are planned whether the ApplicationSet is suspended or not, and whatever
+// its strategy, which may spread them over several reconciliations. The Client of the reconciler must list the
+// Applications by the index of IndexTrackedApplications. When some generated Applications are invalid, the changes to
+// the others are returned with the error.
+func (r *ApplicationSetReconciler) PlanApplications(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet) ([]ApplicationChange, error) {
+	policy, err := r.getPolicy(applicationSet)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredApplications, _, _, err := r.generateApplications(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+
+	validateErrors, err := r.validateGeneratedApplications(ctx, desiredApplications, applicationSet, applicationSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var validApps []argov1alpha1.Application
+	var messages []string
+	for i := range desiredApplications {
+		if validateErrors[i] == nil {
+			validApps = append(validApps, desiredApplications[i])
+		} else {
+			messages = append(messages, validateErrors[i].Error())
+		}
+	}
+
+	changes, err := r.planInCluster(ctx, applicationSet, validApps, desiredApplications, policy)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return changes, fmt.Errorf("invalid applications: %s", strings.Join(messages, "; "))
+	}
+	return changes, nil
+}
+
+// planInCluster returns the changes which would be made to the Applications of the ApplicationSet according to the
+// policy, without making them: the creations and updates are sent to the API server in dry run mode, so that they are
+// validated, and the deletions are only listed.
+func (r *ApplicationSetReconciler) planInCluster(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet, validApps []argov1alpha1.Application, desiredApplications []argov1alpha1.Application, policy utils.Policy) ([]ApplicationChange, error) {
+	current, err := r.getCurrentApplications(ctx, applicationSet)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]bool) // Holds the app names that are current in the cluster
+	for _, app := range current {
+		m[app.Name] = true
+	}
+
+	dryRunClient := client.NewDryRunClient(r.Client)
+	var changes []ApplicationChange
+	for _, generatedApp := range validApps {
+		if m[generatedApp.Name] && !policy.Update() {
+			continue
+		}
+
+		generatedApp.Namespace = applicationSet.Namespace
+		previous := &argov1alpha1.Application{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: generatedApp.Namespace, Name: generatedApp.Name}, previous); err != nil {
+			previous = nil
+		}
+
+		found := &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generatedApp.Name,
+				Namespace: generatedApp.Namespace,
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Application",
+				APIVersion: "argoproj.io/v1alpha1",
+			},
+		}
+
+		action, err := r.createOrUpdate(ctx, dryRunClient, applicationSet, found, &generatedApp)
+		if err != nil {
+			return nil, fmt.Errorf("error in dry run of application %s: %v", generatedApp.Name, err)
+		}
+
+		switch action {
+		case controllerutil.OperationResultCreated:
+			changes = append(changes, ApplicationChange{Action: argoprojiov1alpha1.ApplicationSetDryRunActionCreate, Name: generatedApp.Name, Desired: found})
+		case controllerutil.OperationResultUpdated:
+			changes = append(changes, ApplicationChange{Action: argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, Name: generatedApp.Name, Current: previous, Desired: found})
+		}
+	}
+
+	if policy.Delete() {
+		desired := make(map[string]bool)
+		for _, app := range desiredApplications {
+			desired[app.Name] = true
+		}
+		for i := range current {
+			if !desired[current[i].Name] && !isDeletionProtected(current[i]) {
+				changes = append(changes, ApplicationChange{Action: argoprojiov1alpha1.ApplicationSetDryRunActionDelete, Name: current[i].Name, Current: &current[i]})
+			}
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	crtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestPlanApplications(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = argov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	defaultProject := argov1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "argocd"},
+		Spec:       argov1alpha1.AppProjectSpec{SourceRepos: []string{"*"}, Destinations: []argov1alpha1.ApplicationDestination{{Namespace: "*", Server: "https://good-cluster"}}},
+	}
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojiov1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{
+							Raw: []byte(`{"cluster": "good-cluster","url": "https://good-cluster"}`),
+						}, {
+							Raw: []byte(`{"cluster": "bad-cluster","url": "https://bad-cluster"}`),
+						}},
+					},
+				},
+			},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{
+					Name:      "{{cluster}}",
+					Namespace: "argocd",
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+					Project:     "default",
+					Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}"},
+				},
+			},
+		},
+	}
+
+	initObjs := []crtclient.Object{&appSet}
+	for _, app := range []argov1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "good-cluster", Namespace: "argocd"},
+			Spec: argov1alpha1.ApplicationSpec{
+				Source:      argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "helm-guestbook"},
+				Project:     "default",
+				Destination: argov1alpha1.ApplicationDestination{Server: "https://good-cluster"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "argocd"},
+			Spec:       argov1alpha1.ApplicationSpec{Project: "default"},
+		},
+	} {
+		temp := app
+		err = controllerutil.SetControllerReference(&appSet, &temp, scheme)
+		assert.Nil(t, err)
+		initObjs = append(initObjs, &temp)
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+	goodCluster := argov1alpha1.Cluster{Server: "https://good-cluster", Name: "good-cluster"}
+	badCluster := argov1alpha1.Cluster{Server: "https://bad-cluster", Name: "bad-cluster"}
+	argoDBMock := dbmocks.ArgoDB{}
+	argoDBMock.On("GetCluster", mock.Anything, "https://good-cluster").Return(&goodCluster, nil)
+	argoDBMock.On("GetCluster", mock.Anything, "https://bad-cluster").Return(&badCluster, nil)
+	argoDBMock.On("ListClusters", mock.Anything).Return(&argov1alpha1.ClusterList{Items: []argov1alpha1.Cluster{
+		goodCluster,
+	}}, nil)
+
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Renderer: &utils.Render{},
+		Recorder: record.NewFakeRecorder(1),
+		Generators: map[string]generators.Generator{
+			"List": generators.NewListGenerator(),
+		},
+		ArgoDB:           &argoDBMock,
+		ArgoAppClientset: appclientset.NewSimpleClientset(&defaultProject),
+		KubeClientset:    kubefake.NewSimpleClientset(),
+		Policy:           &utils.SyncPolicy{},
+	}
+
+	// the changes to the valid Applications are returned with the error of the invalid ones
+	changes, err := r.PlanApplications(context.Background(), appSet)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid applications: ")
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetDryRunActionUpdate, changes[0].Action)
+	assert.Equal(t, "good-cluster", changes[0].Name)
+	assert.Equal(t, "helm-guestbook", changes[0].Current.Spec.Source.Path)
+	assert.Equal(t, "guestbook", changes[0].Desired.Spec.Source.Path)
+
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetDryRunActionDelete, changes[1].Action)
+	assert.Equal(t, "stale", changes[1].Name)
+	assert.Nil(t, changes[1].Desired)
+
+	// no Application was changed
+	app := &argov1alpha1.Application{}
+	err = client.Get(context.Background(), crtclient.ObjectKey{Namespace: "argocd", Name: "good-cluster"}, app)
+	assert.Nil(t, err)
+	assert.Equal(t, "helm-guestbook", app.Spec.Source.Path)
+	err = client.Get(context.Background(), crtclient.ObjectKey{Namespace: "argocd", Name: "bad-cluster"}, app)
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"context"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/engine"
+)
+
+// RenderApplications renders the Applications of the ApplicationSet from the param sets of its generators, as the
+// reconciliations do, without creating, updating or deleting any Application, e.g. to preview the Applications of an
+// ApplicationSet. Only the Generators and the Renderer of the reconciler are used, with engine.GenerateApplications. The Applications are in the
+// namespace of the ApplicationSet. When the generators or the templates fail, the Applications which were rendered
+// are returned with the error.
+func (r *ApplicationSetReconciler) RenderApplications(ctx context.Context, applicationSet argoprojiov1alpha1.ApplicationSet) ([]argov1alpha1.Application, error) {
+	result, err := engine.GenerateApplications(ctx, &applicationSet, engine.Options{
+		Generators: r.Generators,
+		Renderer:   r.Renderer,
+	})
+	applications := result.Applications
+	for i := range applications {
+		applications[i].APIVersion = "argoproj.io/v1alpha1"
+		applications[i].Kind = "Application"
+		applications[i].Namespace = applicationSet.Namespace
+	}
+	return applications, err
+}
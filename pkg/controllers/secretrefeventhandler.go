@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// secretRefEventHandler is used when watching Secrets to regenerate the ApplicationSets whose generators reference
+// them, e.g. with a tokenRef, so that a rotated token is used, or a fixed one retried, without waiting for the requeue
+// of the generators.
+type secretRefEventHandler struct {
+	Log    log.FieldLogger
+	Client client.Client
+}
+
+// Create queues the ApplicationSets referencing the Secret, e.g. those which failed because it was missing. The
+// Secrets are also created when the cache of the controller starts, so the ApplicationSets are not refreshed.
+func (h *secretRefEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	for _, appSet := range h.referencingApplicationSets(e.Object) {
+		q.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: appSet.Namespace, Name: appSet.Name}})
+	}
+}
+
+// Update refreshes the ApplicationSets referencing the Secret when its data changes, the refresh bypassing the backoff
+// of the failing generators.
+func (h *secretRefEventHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldSecret, oldOK := e.ObjectOld.(*corev1.Secret)
+	newSecret, newOK := e.ObjectNew.(*corev1.Secret)
+	if oldOK && newOK && reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+		return
+	}
+	h.refreshApplicationSets(e.ObjectNew)
+}
+
+func (h *secretRefEventHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.refreshApplicationSets(e.Object)
+}
+
+func (h *secretRefEventHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+// referencingApplicationSets returns the ApplicationSets of the namespace of the Secret whose generators reference it.
+func (h *secretRefEventHandler) referencingApplicationSets(object client.Object) []argoprojiov1alpha1.ApplicationSet {
+	appSetList := &argoprojiov1alpha1.ApplicationSetList{}
+	if err := h.Client.List(context.Background(), appSetList, client.InNamespace(object.GetNamespace())); err != nil {
+		h.Log.WithError(err).Error("unable to list ApplicationSets")
+		return nil
+	}
+
+	var res []argoprojiov1alpha1.ApplicationSet
+	for _, appSet := range appSetList.Items {
+		for _, name := range utils.ReferencedSecrets(&appSet) {
+			if name == object.GetName() {
+				res = append(res, appSet)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func (h *secretRefEventHandler) refreshApplicationSets(object client.Object) {
+	for _, appSet := range h.referencingApplicationSets(object) {
+		logCtx := h.Log.WithFields(log.Fields{
+			"applicationset": appSet.Name,
+			"namespace":      appSet.Namespace,
+			"secret":         object.GetName(),
+		})
+		key := types.NamespacedName{Namespace: appSet.Namespace, Name: appSet.Name}
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			appSet := &argoprojiov1alpha1.ApplicationSet{}
+			if err := h.Client.Get(context.Background(), key, appSet); err != nil {
+				return err
+			}
+			if appSet.Annotations == nil {
+				appSet.Annotations = map[string]string{}
+			}
+			appSet.Annotations[common.AnnotationApplicationSetRefresh] = "true"
+			return h.Client.Update(context.Background(), appSet)
+		})
+		if err != nil {
+			logCtx.WithError(err).Error("unable to refresh the ApplicationSet referencing the Secret")
+			continue
+		}
+		logCtx.Info("refreshed the ApplicationSet after a change of a referenced Secret")
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+func TestSecretRefEventHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, argoprojiov1alpha1.AddToScheme(scheme))
+
+	appSetWithTokenRef := func(namespace, name, secretName string) *argoprojiov1alpha1.ApplicationSet {
+		return &argoprojiov1alpha1.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: argoprojiov1alpha1.ApplicationSetSpec{Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{HTTP: &argoprojiov1alpha1.HTTPGenerator{TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: secretName, Key: "token"}}},
+			}},
+		}
+	}
+	secret := func(token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "argocd", Name: "token"},
+			Data:       map[string][]byte{"token": []byte(token)},
+		}
+	}
+
+	newHandler := func() *secretRefEventHandler {
+		return &secretRefEventHandler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				appSetWithTokenRef("argocd", "referencing", "token"),
+				appSetWithTokenRef("argocd", "other", "other-token"),
+				appSetWithTokenRef("other", "other-namespace", "token"),
+			).Build(),
+			Log: log.NewEntry(log.StandardLogger()),
+		}
+	}
+	refreshed := func(t *testing.T, h *secretRefEventHandler) []string {
+		list := &argoprojiov1alpha1.ApplicationSetList{}
+		assert.NoError(t, h.Client.List(context.Background(), list))
+		var res []string
+		for _, appSet := range list.Items {
+			if appSet.RefreshRequired() {
+				res = append(res, appSet.Namespace+"/"+appSet.Name)
+			}
+		}
+		return res
+	}
+
+	t.Run("created Secret", func(t *testing.T) {
+		h := newHandler()
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer q.ShutDown()
+
+		h.Create(event.CreateEvent{Object: secret("a")}, q)
+		assert.Equal(t, 1, q.Len())
+		item, _ := q.Get()
+		assert.Equal(t, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "referencing"}}, item)
+		assert.Empty(t, refreshed(t, h))
+	})
+
+	t.Run("updated Secret", func(t *testing.T) {
+		h := newHandler()
+		h.Update(event.UpdateEvent{ObjectOld: secret("a"), ObjectNew: secret("b")}, nil)
+		assert.Equal(t, []string{"argocd/referencing"}, refreshed(t, h))
+	})
+
+	t.Run("Secret updated without a change of its data", func(t *testing.T) {
+		h := newHandler()
+		updated := secret("a")
+		updated.Labels = map[string]string{"team": "a"}
+		h.Update(event.UpdateEvent{ObjectOld: secret("a"), ObjectNew: updated}, nil)
+		assert.Empty(t, refreshed(t, h))
+	})
+
+	t.Run("deleted Secret", func(t *testing.T) {
+		h := newHandler()
+		h.Delete(event.DeleteEvent{Object: secret("a")}, nil)
+		assert.Equal(t, []string{"argocd/referencing"}, refreshed(t, h))
+
+		appSet := &argoprojiov1alpha1.ApplicationSet{}
+		assert.NoError(t, h.Client.Get(context.Background(), types.NamespacedName{Namespace: "argocd", Name: "referencing"}, appSet))
+		assert.Equal(t, "true", appSet.Annotations[common.AnnotationApplicationSetRefresh])
+	})
+}
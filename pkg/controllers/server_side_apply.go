@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// FieldManager is the manager of the fields of the Applications applied by the ApplicationSet controller with
+// server-side apply.
+const FieldManager = "argocd-applicationset-controller"
+
+// createOrUpdate creates or updates the Application found in the cluster from the generated Application, with a
+// server-side apply if enabled, and returns the executed operation.
+func (r *ApplicationSetReconciler) createOrUpdate(ctx context.Context, c client.Client, applicationSet argoprojiov1alpha1.ApplicationSet, found *argov1alpha1.Application, generatedApp *argov1alpha1.Application) (controllerutil.OperationResult, error) {
+	if !r.ServerSideApply {
+		return utils.CreateOrUpdate(ctx, c, found, func() error {
+			return r.applyGeneratedApplication(applicationSet, found, generatedApp)
+		})
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(found), found); err != nil && !apierr.IsNotFound(err) {
+		return controllerutil.OperationResultNone, err
+	}
+	existing := found.DeepCopy()
+	_, notifiedGenerated := generatedApp.Annotations[NotifiedAnnotationKey]
+
+	if err := r.applyGeneratedApplication(applicationSet, found, generatedApp); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if existing.ResourceVersion != "" && equalAppliedFields(existing, found) {
+		return controllerutil.OperationResultNone, nil
+	}
+
+	applied, err := appliedApplication(&applicationSet, found, notifiedGenerated)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	// Without force, the fields also managed by another manager are reported as conflicts rather than overwritten
+	if err := c.Patch(ctx, applied, client.Apply, client.FieldOwner(FieldManager)); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if existing.ResourceVersion == "" {
+		return controllerutil.OperationResultCreated, nil
+	}
+
+	result := &argov1alpha1.Application{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(applied.Object, result); err != nil {
+		return controllerutil.OperationResultNone, fmt.Errorf("error converting the applied application %s: %v", found.Name, err)
+	}
+	if equalAppliedFields(existing, result) {
+		return controllerutil.OperationResultNone, nil
+	}
+	return controllerutil.OperationResultUpdated, nil
+}
+
+// appliedApplication returns the applied configuration of the Application, which only holds the fields managed by the
+// ApplicationSet controller, so that the fields set by other managers are left untouched: the labels, annotations,
+// finalizers and spec, and the owner reference to the ApplicationSet. The state of Argo CD notifications is only part
+// of it if the template generates it.
+func appliedApplication(applicationSet *argoprojiov1alpha1.ApplicationSet, app *argov1alpha1.Application, notifiedGenerated bool) (*unstructured.Unstructured, error) {
+	applied := &argov1alpha1.Application{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Application",
+			APIVersion: "argoproj.io/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       app.Name,
+			Namespace:  app.Namespace,
+			Labels:     app.Labels,
+			Finalizers: app.Finalizers,
+		},
+		Spec: app.Spec,
+	}
+	if len(app.Annotations) > 0 {
+		applied.Annotations = map[string]string{}
+		for key, value := range app.Annotations {
+			if key != NotifiedAnnotationKey || notifiedGenerated {
+				applied.Annotations[key] = value
+			}
+		}
+	}
+	for _, ref := range app.OwnerReferences {
+		if ref.UID == applicationSet.UID {
+			applied.OwnerReferences = append(applied.OwnerReferences, ref)
+		}
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applied)
+	if err != nil {
+		return nil, fmt.Errorf("error converting application %s: %v", app.Name, err)
+	}
+	// The status and creation timestamp are not omitted when empty, but are not managed by the controller
+	delete(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// equalAppliedFields returns true if the fields of the Applications applied by the ApplicationSet controller are the
+// same.
+func equalAppliedFields(a *argov1alpha1.Application, b *argov1alpha1.Application) bool {
+	return utils.SemanticEqual(a.Spec, b.Spec) &&
+		utils.SemanticEqual(a.Labels, b.Labels) &&
+		utils.SemanticEqual(a.Annotations, b.Annotations) &&
+		utils.SemanticEqual(a.Finalizers, b.Finalizers) &&
+		utils.SemanticEqual(a.OwnerReferences, b.OwnerReferences)
+}
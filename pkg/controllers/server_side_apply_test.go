@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestAppliedApplication(t *testing.T) {
+	appSet := &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace", UID: "appset-uid"},
+	}
+	app := &argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app",
+			Namespace:       "namespace",
+			ResourceVersion: "2",
+			Labels:          map[string]string{"label": "value"},
+			Annotations:     map[string]string{"annotation": "value", NotifiedAnnotationKey: "state"},
+			Finalizers:      []string{"resources-finalizer.argocd.argoproj.io"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet", Name: "name", UID: "appset-uid"},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+			},
+		},
+		Spec: argov1alpha1.ApplicationSpec{Project: "project"},
+		Status: argov1alpha1.ApplicationStatus{
+			Sync: argov1alpha1.SyncStatus{Status: argov1alpha1.SyncStatusCodeSynced},
+		},
+	}
+
+	for _, c := range []struct {
+		name                string
+		notifiedGenerated   bool
+		expectedAnnotations map[string]interface{}
+	}{
+		{
+			name:                "notifications state not generated",
+			expectedAnnotations: map[string]interface{}{"annotation": "value"},
+		},
+		{
+			name:                "notifications state generated",
+			notifiedGenerated:   true,
+			expectedAnnotations: map[string]interface{}{"annotation": "value", NotifiedAnnotationKey: "state"},
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			applied, err := appliedApplication(appSet, app, cc.notifiedGenerated)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "argoproj.io/v1alpha1", applied.GetAPIVersion())
+			assert.Equal(t, "Application", applied.GetKind())
+			assert.Equal(t, "app", applied.GetName())
+			assert.Equal(t, "namespace", applied.GetNamespace())
+			assert.Empty(t, applied.GetResourceVersion())
+			assert.Equal(t, map[string]string{"label": "value"}, applied.GetLabels())
+			assert.Equal(t, []string{"resources-finalizer.argocd.argoproj.io"}, applied.GetFinalizers())
+
+			annotations, _, err := unstructured.NestedMap(applied.Object, "metadata", "annotations")
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedAnnotations, annotations)
+
+			ownerReferences := applied.GetOwnerReferences()
+			if assert.Len(t, ownerReferences, 1) {
+				assert.Equal(t, appSet.UID, ownerReferences[0].UID)
+			}
+
+			project, _, err := unstructured.NestedString(applied.Object, "spec", "project")
+			assert.NoError(t, err)
+			assert.Equal(t, "project", project)
+			_, found := applied.Object["status"]
+			assert.False(t, found)
+			_, found, _ = unstructured.NestedFieldNoCopy(applied.Object, "metadata", "creationTimestamp")
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestEqualAppliedFields(t *testing.T) {
+	app := &argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Labels:      map[string]string{"label": "value"},
+			Annotations: map[string]string{},
+		},
+		Spec: argov1alpha1.ApplicationSpec{Project: "project"},
+	}
+
+	other := app.DeepCopy()
+	other.ResourceVersion = "3"
+	other.Annotations = nil
+	other.Status.Sync.Status = argov1alpha1.SyncStatusCodeOutOfSync
+	assert.True(t, equalAppliedFields(app, other))
+
+	other = app.DeepCopy()
+	other.Labels["label"] = "other"
+	assert.False(t, equalAppliedFields(app, other))
+
+	other = app.DeepCopy()
+	other.Spec.Project = "other"
+	assert.False(t, equalAppliedFields(app, other))
+}
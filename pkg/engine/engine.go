@@ -0,0 +1,376 @@
+// Package engine generates the Applications of ApplicationSets from their generators and templates, without creating,
+// updating or deleting any resource, as the ApplicationSet controller does before applying them. It is the entrypoint
+// of the programs embedding the ApplicationSet engine, e.g. to preview the Applications of an ApplicationSet.
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// Options are the options of GenerateApplications.
+type Options struct {
+	// Generators are the top-level generators, by Go name, e.g. returned by the Generators of a generators.Registry.
+	Generators map[string]generators.Generator
+	// Renderer renders the templates with the params, utils.Render if nil.
+	Renderer utils.Renderer
+	// LastKnownParams, if set, keeps the results of the generators, so that the Applications of a generator which
+	// fails are generated from its last known results.
+	LastKnownParams LastKnownParams
+	// MaxParamSets is the maximum number of param sets of the ApplicationSet, no maximum if 0. When the generators
+	// generate more param sets, no Application is returned, and the error wraps generators.ErrParamSetsOverflow.
+	MaxParamSets int
+}
+
+// LastKnownParams keeps the last results of the generators of the ApplicationSets.
+type LastKnownParams interface {
+	// Get returns the last known results of the generator of the ApplicationSet, by index in its generators, if any.
+	Get(appSet *argoprojiov1alpha1.ApplicationSet, generatorIndex int) ([]generators.TransformResult, bool)
+	// Set records the results of the generator of the ApplicationSet, by index in its generators.
+	Set(appSet *argoprojiov1alpha1.ApplicationSet, generatorIndex int, results []generators.TransformResult)
+}
+
+// Result is the result of GenerateApplications.
+type Result struct {
+	// Applications are the rendered Applications, in the order of the generators, or in the order of the strategy of
+	// the ApplicationSet. Their namespace is the namespace of their template, if any.
+	Applications []argov1alpha1.Application
+	// Parameters is the summary of the param sets of the generators, nil if the generators or the templates failed.
+	Parameters *argoprojiov1alpha1.ApplicationSetParametersStatus
+	// Reason is the reason of the error, if any, as set in the conditions of the ApplicationSet.
+	Reason argoprojiov1alpha1.ApplicationSetReasonType
+}
+
+// StaleParamsError is returned when generators failed, and their Applications were generated from their last known
+// results.
+type StaleParamsError struct {
+	errs []error
+}
+
+func (e *StaleParamsError) Error() string {
+	return fmt.Sprintf("using the last known parameters of the generators which failed, no Application is deleted: %v", utilerrors.NewAggregate(e.errs))
+}
+
+// GenerateApplications renders the Applications of the ApplicationSet from the param sets of its generators, as the
+// ApplicationSet controller does before applying them. When the generators or the templates fail, the Applications
+// which were rendered are returned with the first error. When the generators failed but their last known results were
+// used, the Applications are returned with a *StaleParamsError.
+func GenerateApplications(ctx context.Context, appSet *argoprojiov1alpha1.ApplicationSet, opts Options) (*Result, error) {
+	var res []argov1alpha1.Application
+	// orderKeys are the order keys of the Applications of res, if the ApplicationSet defines an order
+	var orderKeys []string
+	var order *argoprojiov1alpha1.ApplicationSetOrder
+	if appSet.Spec.Strategy != nil {
+		order = appSet.Spec.Strategy.Order
+	}
+
+	g := newGeneration(ctx, appSet, opts)
+	if g.generateParams() {
+		g.render(false, func(app argov1alpha1.Application, p map[string]interface{}) error {
+			if order != nil {
+				key, err := utils.RenderOrderKey(order.Key, p, appSet.Spec.GoTemplate, appSet.Spec.GoTemplateOptions)
+				if err != nil {
+					utils.LoggerFromContext(ctx).WithError(err).WithField("params", p).
+						Error("error rendering the order key of application")
+					return err
+				}
+				orderKeys = append(orderKeys, key)
+			}
+			res = append(res, app)
+			return nil
+		})
+	}
+
+	if order != nil {
+		res = utils.SortApplicationsByOrder(res, orderKeys, order.Values)
+	}
+	result, err := g.result()
+	result.Applications = res
+	return result, err
+}
+
+// StreamApplications renders the Applications of the ApplicationSet like GenerateApplications, but rather than
+// returning them, passes them to apply by chunks of at most chunkSize Applications as soon as they are rendered, so
+// that no more than chunkSize rendered Applications are kept in memory. The param sets of all the generators are
+// generated, and checked against the maximum number of param sets, before any Application is rendered.
+//
+// apply is not called when a generator fails, unless its last known results are used. The rendering stops at the
+// first error of a template or of apply, which is returned, the chunks already passed to apply being kept. The order
+// of the strategy of the ApplicationSet is not supported, since it requires all the Applications.
+func StreamApplications(ctx context.Context, appSet *argoprojiov1alpha1.ApplicationSet, opts Options, chunkSize int, apply func(apps []argov1alpha1.Application) error) (*Result, error) {
+	if appSet.Spec.Strategy != nil && appSet.Spec.Strategy.Order != nil {
+		return &Result{}, errors.New("the Applications of an ApplicationSet ordered by its strategy cannot be streamed")
+	}
+	if chunkSize <= 0 {
+		return &Result{}, fmt.Errorf("invalid chunk size %d, must be positive", chunkSize)
+	}
+
+	chunk := make([]argov1alpha1.Application, 0, chunkSize)
+	g := newGeneration(ctx, appSet, opts)
+	if g.generateParams() {
+		completed := g.render(true, func(app argov1alpha1.Application, _ map[string]interface{}) error {
+			chunk = append(chunk, app)
+			if len(chunk) < chunkSize {
+				return nil
+			}
+			err := apply(chunk)
+			// The applied Applications are released before the next ones are rendered
+			chunk = make([]argov1alpha1.Application, 0, chunkSize)
+			return err
+		})
+		if completed && len(chunk) > 0 {
+			if err := apply(chunk); err != nil {
+				g.fail(err, "")
+			}
+		}
+	}
+	return g.result()
+}
+
+// generation generates the Applications of an ApplicationSet: generateParams runs its generators, then render renders
+// their param sets.
+type generation struct {
+	ctx      context.Context
+	appSet   *argoprojiov1alpha1.ApplicationSet
+	opts     Options
+	renderer utils.Renderer
+
+	// results are the results of the generators, nil for the generators which failed without last known results
+	results    [][]generators.TransformResult
+	parameters *argoprojiov1alpha1.ApplicationSetParametersStatus
+	// paramSetsHasher hashes the param sets of all the generators, for the summary of the parameters
+	paramSetsHasher *utils.ParamSetsHasher
+
+	firstError           error
+	applicationSetReason argoprojiov1alpha1.ApplicationSetReasonType
+	// staleErrors are the errors of the generators whose last known results were used
+	staleErrors []error
+}
+
+func newGeneration(ctx context.Context, appSet *argoprojiov1alpha1.ApplicationSet, opts Options) *generation {
+	renderer := opts.Renderer
+	if renderer == nil {
+		renderer = &utils.Render{}
+	}
+	// The identical generators of the ApplicationSet generate their params once
+	ctx = generators.WithParamsMemo(ctx)
+	if opts.MaxParamSets > 0 {
+		ctx = generators.WithMaxParamSets(ctx, opts.MaxParamSets)
+	}
+	return &generation{
+		ctx:             ctx,
+		appSet:          appSet,
+		opts:            opts,
+		renderer:        renderer,
+		parameters:      &argoprojiov1alpha1.ApplicationSetParametersStatus{},
+		paramSetsHasher: utils.NewParamSetsHasher(),
+	}
+}
+
+// fail records the error, unless an error was already recorded.
+func (g *generation) fail(err error, reason argoprojiov1alpha1.ApplicationSetReasonType) {
+	if g.firstError == nil {
+		g.firstError = err
+		g.applicationSetReason = reason
+	}
+}
+
+// generateParams runs the generators of the ApplicationSet, and returns false if they generated more param sets than
+// the maximum, in which case no Application is rendered.
+func (g *generation) generateParams() bool {
+	for i, requestedGenerator := range g.appSet.Spec.Generators {
+		generatorName := strings.Join(generators.GetRelevantGeneratorNames(&requestedGenerator, g.opts.Generators), ",")
+		genLog := utils.LoggerFromContext(g.ctx).WithField("generator", generatorName)
+		generatorParameters := argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{Generator: generatorName}
+		t, err := generators.Transform(g.ctx, requestedGenerator, g.opts.Generators, g.appSet.Spec.Template, g.appSet)
+		if err != nil {
+			genLog.WithError(err).
+				Error("error generating application from params")
+			// Beyond the maximum number of param sets, no Application is rendered
+			if errors.Is(err, generators.ErrParamSetsOverflow) {
+				g.firstError = err
+				g.applicationSetReason = argoprojiov1alpha1.ApplicationSetReasonParamSetsOverflow
+				return false
+			}
+			var cached []generators.TransformResult
+			ok := false
+			if g.opts.LastKnownParams != nil {
+				cached, ok = g.opts.LastKnownParams.Get(g.appSet, i)
+			}
+			if !ok {
+				g.fail(err, argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError)
+				g.results = append(g.results, nil)
+				continue
+			}
+			genLog.Warn("using the last known parameters of the generator")
+			g.staleErrors = append(g.staleErrors, err)
+			generatorParameters.Stale = true
+			t = cached
+		} else if g.opts.LastKnownParams != nil {
+			g.opts.LastKnownParams.Set(g.appSet, i, t)
+		}
+
+		for _, a := range t {
+			generatorParameters.Count += int64(len(a.Params))
+		}
+		g.parameters.Generators = append(g.parameters.Generators, generatorParameters)
+		g.parameters.Count += generatorParameters.Count
+		if generatorParameters.Count == 0 {
+			genLog.Info("generator returned no param sets")
+		}
+		if g.opts.MaxParamSets > 0 && g.parameters.Count > int64(g.opts.MaxParamSets) {
+			genLog.WithField("count", g.parameters.Count).Error("the generators generated too many param sets")
+			g.firstError = fmt.Errorf("%w: the generators generated at least %d param sets, more than the maximum of %d", generators.ErrParamSetsOverflow, g.parameters.Count, g.opts.MaxParamSets)
+			g.applicationSetReason = argoprojiov1alpha1.ApplicationSetReasonParamSetsOverflow
+			return false
+		}
+		g.results = append(g.results, t)
+	}
+	return true
+}
+
+// render renders the Applications of the param sets of the generators, and passes each of them to emit with its
+// params. If stopOnError is set, render returns false at the first error, rather than rendering the other
+// Applications, and even before rendering any Application if a generator failed without last known results.
+func (g *generation) render(stopOnError bool, emit func(app argov1alpha1.Application, params map[string]interface{}) error) bool {
+	if stopOnError && g.firstError != nil {
+		return false
+	}
+	for i, t := range g.results {
+		if t == nil {
+			continue
+		}
+		genLog := utils.LoggerFromContext(g.ctx).WithField("generator", strings.Join(generators.GetRelevantGeneratorNames(&g.appSet.Spec.Generators[i], g.opts.Generators), ","))
+		rendered := 0
+		for _, a := range t {
+			tmplApplication := TemplateApplication(a.Template)
+
+			for _, p := range a.Params {
+				if err := g.paramSetsHasher.Add(p); err != nil {
+					g.fail(err, argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError)
+					if stopOnError {
+						return false
+					}
+				}
+
+				app, err := g.renderApplication(a, p, tmplApplication, genLog)
+				if err == nil {
+					err = emit(*app, p)
+				}
+				if err != nil {
+					g.fail(err, argoprojiov1alpha1.ApplicationSetReasonRenderTemplateParamsError)
+					if stopOnError {
+						return false
+					}
+					continue
+				}
+				rendered++
+			}
+		}
+
+		genLog.Infof("generated %d applications", rendered)
+	}
+	return true
+}
+
+// renderApplication renders the Application of the params, from the template selected for them.
+func (g *generation) renderApplication(result generators.TransformResult, p map[string]interface{}, tmplApplication *argov1alpha1.Application, genLog *log.Entry) (*argov1alpha1.Application, error) {
+	appSet := g.appSet
+	selectedTmplApplication, err := selectTemplate(appSet, result, p, tmplApplication)
+	if err != nil {
+		genLog.WithError(err).WithField("params", result.Params).
+			Error("error selecting the template of application")
+		return nil, err
+	}
+
+	app, err := g.renderer.RenderTemplateParams(selectedTmplApplication, appSet.Spec.SyncPolicy, p, appSet.Spec.GoTemplate, appSet.Spec.GoTemplateOptions)
+	if err != nil {
+		genLog.WithError(err).WithField("params", result.Params).
+			Error("error generating application from params")
+		return nil, err
+	}
+
+	if appSet.Spec.TemplatePatch != nil {
+		app, err = g.renderer.RenderTemplatePatch(app, *appSet.Spec.TemplatePatch, p, appSet.Spec.GoTemplate, appSet.Spec.GoTemplateOptions)
+		if err != nil {
+			genLog.WithError(err).WithField("params", result.Params).
+				Error("error applying template patch to application")
+			return nil, err
+		}
+	}
+	if appSet.HistoryLimit() > 0 {
+		paramsHash, err := utils.ParamsHash(p)
+		if err != nil {
+			genLog.WithError(err).WithField("params", result.Params).
+				Error("error hashing the params of application")
+			return nil, err
+		}
+		if app.Annotations == nil {
+			app.Annotations = map[string]string{}
+		}
+		app.Annotations[common.AnnotationApplicationSetParamsHash] = paramsHash
+	}
+	genLog.Debugf("rendered application %s", app.Name)
+	return app, nil
+}
+
+// result returns the result of the generation, without the Applications.
+func (g *generation) result() (*Result, error) {
+	if g.firstError != nil {
+		return &Result{Reason: g.applicationSetReason}, g.firstError
+	}
+
+	g.parameters.Hash = g.paramSetsHasher.Sum()
+
+	if len(g.staleErrors) > 0 {
+		return &Result{Parameters: g.parameters, Reason: argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError}, &StaleParamsError{errs: g.staleErrors}
+	}
+
+	return &Result{Parameters: g.parameters, Reason: g.applicationSetReason}, nil
+}
+
+// TemplateApplication returns the Application of the template, before its params are rendered.
+func TemplateApplication(applicationSetTemplate argoprojiov1alpha1.ApplicationSetTemplate) *argov1alpha1.Application {
+	var tmplApplication argov1alpha1.Application
+	tmplApplication.Annotations = applicationSetTemplate.Annotations
+	tmplApplication.Labels = applicationSetTemplate.Labels
+	tmplApplication.Namespace = applicationSetTemplate.Namespace
+	tmplApplication.Name = applicationSetTemplate.Name
+	tmplApplication.Spec = applicationSetTemplate.Spec
+	tmplApplication.Finalizers = applicationSetTemplate.Finalizers
+
+	return &tmplApplication
+}
+
+// selectTemplate returns the template of the Application generated from the params: the named template chosen by the
+// template selector of the ApplicationSet, if any, or the template otherwise.
+func selectTemplate(appSet *argoprojiov1alpha1.ApplicationSet, result generators.TransformResult, params map[string]interface{}, tmplApplication *argov1alpha1.Application) (*argov1alpha1.Application, error) {
+	if appSet.Spec.TemplateSelector == "" {
+		return tmplApplication, nil
+	}
+
+	name, err := utils.RenderTemplateSelector(appSet.Spec.TemplateSelector, params, appSet.Spec.GoTemplateOptions)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return tmplApplication, nil
+	}
+
+	namedTemplate, ok := result.NamedTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s selected by the template selector is not defined", name)
+	}
+	return TemplateApplication(namedTemplate), nil
+}
@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/generators"
+)
+
+func newApplicationSet(elements ...string) *argoprojiov1alpha1.ApplicationSet {
+	var list []apiextensionsv1.JSON
+	for _, e := range elements {
+		list = append(list, apiextensionsv1.JSON{Raw: []byte(e)})
+	}
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd", Generation: 1},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{List: &argoprojiov1alpha1.ListGenerator{Elements: list}},
+			},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}-guestbook"},
+				Spec: argov1alpha1.ApplicationSpec{
+					Project:     "default",
+					Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}", Namespace: "guestbook"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateApplications(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging","url":"https://staging"}`, `{"cluster":"production","url":"https://production"}`)
+	result, err := GenerateApplications(context.Background(), appSet, Options{
+		Generators: map[string]generators.Generator{"List": generators.NewListGenerator()},
+	})
+	require.NoError(t, err)
+	if assert.Len(t, result.Applications, 2) {
+		assert.Equal(t, "staging-guestbook", result.Applications[0].Name)
+		assert.Equal(t, "https://staging", result.Applications[0].Spec.Destination.Server)
+		assert.Equal(t, "production-guestbook", result.Applications[1].Name)
+	}
+	if assert.NotNil(t, result.Parameters) {
+		assert.Equal(t, int64(2), result.Parameters.Count)
+		assert.Equal(t, []argoprojiov1alpha1.ApplicationSetGeneratorParametersStatus{{Generator: "List", Count: 2}}, result.Parameters.Generators)
+	}
+	assert.Empty(t, result.Reason)
+}
+
+func TestGenerateApplicationsError(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging"}`)
+	invalid := newApplicationSet(`"staging"`).Spec.Generators[0]
+	appSet.Spec.Generators = append(appSet.Spec.Generators, invalid)
+	result, err := GenerateApplications(context.Background(), appSet, Options{
+		Generators: map[string]generators.Generator{"List": generators.NewListGenerator()},
+	})
+	assert.Error(t, err)
+	assert.Len(t, result.Applications, 1)
+	assert.Nil(t, result.Parameters)
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonType(argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError), result.Reason)
+}
+
+func TestGenerateApplicationsMaxParamSets(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging","url":"https://staging"}`, `{"cluster":"production","url":"https://production"}`)
+	appSet.Spec.Generators = append(appSet.Spec.Generators, newApplicationSet(`{"cluster":"qa","url":"https://qa"}`).Spec.Generators[0])
+	opts := Options{
+		Generators:   map[string]generators.Generator{"List": generators.NewListGenerator()},
+		MaxParamSets: 3,
+	}
+	result, err := GenerateApplications(context.Background(), appSet, opts)
+	require.NoError(t, err)
+	assert.Len(t, result.Applications, 3)
+
+	// Beyond the maximum, no Application is returned
+	opts.MaxParamSets = 2
+	result, err = GenerateApplications(context.Background(), appSet, opts)
+	assert.True(t, errors.Is(err, generators.ErrParamSetsOverflow))
+	assert.Empty(t, result.Applications)
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonType(argoprojiov1alpha1.ApplicationSetReasonParamSetsOverflow), result.Reason)
+}
+
+func TestStreamApplications(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging","url":"https://staging"}`, `{"cluster":"production","url":"https://production"}`, `{"cluster":"qa","url":"https://qa"}`)
+	opts := Options{
+		Generators: map[string]generators.Generator{"List": generators.NewListGenerator()},
+	}
+
+	var chunks [][]string
+	apply := func(apps []argov1alpha1.Application) error {
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+		chunks = append(chunks, names)
+		return nil
+	}
+	result, err := StreamApplications(context.Background(), appSet, opts, 2, apply)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"staging-guestbook", "production-guestbook"}, {"qa-guestbook"}}, chunks)
+	assert.Empty(t, result.Applications)
+	if assert.NotNil(t, result.Parameters) {
+		assert.Equal(t, int64(3), result.Parameters.Count)
+		// The param sets are hashed as when the Applications are generated at once
+		generated, err := GenerateApplications(context.Background(), appSet, opts)
+		require.NoError(t, err)
+		assert.Equal(t, generated.Parameters.Hash, result.Parameters.Hash)
+	}
+
+	// Beyond the maximum number of param sets, no chunk is applied
+	chunks = nil
+	opts.MaxParamSets = 2
+	result, err = StreamApplications(context.Background(), appSet, opts, 2, apply)
+	assert.True(t, errors.Is(err, generators.ErrParamSetsOverflow))
+	assert.Empty(t, chunks)
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonType(argoprojiov1alpha1.ApplicationSetReasonParamSetsOverflow), result.Reason)
+}
+
+func TestStreamApplicationsError(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging","url":"https://staging"}`, `{"cluster":"production","url":"https://production"}`, `{"cluster":"qa","url":"https://qa"}`)
+	opts := Options{
+		Generators: map[string]generators.Generator{"List": generators.NewListGenerator()},
+	}
+
+	// The rendering stops at the first chunk which fails to be applied
+	applied := 0
+	_, err := StreamApplications(context.Background(), appSet, opts, 1, func(apps []argov1alpha1.Application) error {
+		applied++
+		if apps[0].Name == "production-guestbook" {
+			return errors.New("could not apply production-guestbook")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "could not apply production-guestbook")
+	assert.Equal(t, 2, applied)
+
+	// No chunk is applied when a generator fails
+	invalid := newApplicationSet(`"staging"`).Spec.Generators[0]
+	appSet.Spec.Generators = append(appSet.Spec.Generators, invalid)
+	applied = 0
+	result, err := StreamApplications(context.Background(), appSet, opts, 1, func(apps []argov1alpha1.Application) error {
+		applied++
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, applied)
+	assert.Equal(t, argoprojiov1alpha1.ApplicationSetReasonType(argoprojiov1alpha1.ApplicationSetReasonApplicationParamsGenerationError), result.Reason)
+}
+
+// lastKnownParams keeps the last known results in memory, by generator index.
+type lastKnownParams map[int][]generators.TransformResult
+
+func (l lastKnownParams) Get(_ *argoprojiov1alpha1.ApplicationSet, generatorIndex int) ([]generators.TransformResult, bool) {
+	results, ok := l[generatorIndex]
+	return results, ok
+}
+
+func (l lastKnownParams) Set(_ *argoprojiov1alpha1.ApplicationSet, generatorIndex int, results []generators.TransformResult) {
+	l[generatorIndex] = results
+}
+
+// failingGenerator is a List generator which fails once failing is set.
+type failingGenerator struct {
+	generators.Generator
+	failing bool
+}
+
+func (g *failingGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if g.failing {
+		return nil, errors.New("GitHub returned 500")
+	}
+	return g.Generator.GenerateParams(ctx, appSetGenerator, appSet)
+}
+
+func TestGenerateApplicationsWithLastKnownParams(t *testing.T) {
+	appSet := newApplicationSet(`{"cluster":"staging"}`)
+	generator := &failingGenerator{Generator: generators.NewListGenerator()}
+	opts := Options{
+		Generators:      map[string]generators.Generator{"List": generator},
+		LastKnownParams: lastKnownParams{},
+	}
+
+	_, err := GenerateApplications(context.Background(), appSet, opts)
+	require.NoError(t, err)
+
+	generator.failing = true
+	result, err := GenerateApplications(context.Background(), appSet, opts)
+	var staleErr *StaleParamsError
+	assert.True(t, errors.As(err, &staleErr))
+	assert.Contains(t, err.Error(), "GitHub returned 500")
+	if assert.Len(t, result.Applications, 1) {
+		assert.Equal(t, "staging-guestbook", result.Applications[0].Name)
+	}
+	if assert.NotNil(t, result.Parameters) {
+		assert.True(t, result.Parameters.Generators[0].Stale)
+	}
+}
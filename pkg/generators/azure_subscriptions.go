@@ -0,0 +1,98 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	azuresubscriptions "github.com/argoproj-labs/applicationset/pkg/services/azure_subscriptions"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*AzureSubscriptionsGenerator)(nil)
+
+const (
+	DefaultAzureSubscriptionsRequeueAfterSeconds = 30 * time.Minute
+)
+
+type AzureSubscriptionsGenerator struct {
+	client                    client.Client
+	selectServiceProviderFunc func(context.Context, *argoprojiov1alpha1.AzureSubscriptionsGenerator, *argoprojiov1alpha1.ApplicationSet) (azuresubscriptions.SubscriptionService, error)
+}
+
+func NewAzureSubscriptionsGenerator(client client.Client) Generator {
+	g := &AzureSubscriptionsGenerator{
+		client: client,
+	}
+	g.selectServiceProviderFunc = g.selectServiceProvider
+	return g
+}
+
+func (g *AzureSubscriptionsGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no default is specified.
+
+	if appSetGenerator.AzureSubscriptions.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.AzureSubscriptions.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultAzureSubscriptionsRequeueAfterSeconds
+}
+
+func (g *AzureSubscriptionsGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.AzureSubscriptions.Template
+}
+
+func (g *AzureSubscriptionsGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.AzureSubscriptions == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	svc, err := g.selectServiceProviderFunc(ctx, appSetGenerator.AzureSubscriptions, applicationSetInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select Azure subscriptions service: %v", err)
+	}
+
+	subscriptions, err := svc.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing Azure subscriptions: %v", err)
+	}
+	params := make([]map[string]interface{}, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		p := map[string]interface{}{
+			"subscription_id": subscription.ID,
+			"display_name":    subscription.DisplayName,
+			"tenant_id":       subscription.TenantID,
+			"state":           subscription.State,
+		}
+		for key, value := range subscription.Tags {
+			p[fmt.Sprintf("tags.%s", key)] = value
+		}
+		for key, value := range appSetGenerator.AzureSubscriptions.Values {
+			p[fmt.Sprintf("values.%s", key)] = value
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// selectServiceProvider returns the service listing the subscriptions, authenticated with the service principal of the
+// generator if it references a client secret, or else with the managed identity of the controller.
+func (g *AzureSubscriptionsGenerator) selectServiceProvider(ctx context.Context, generatorConfig *argoprojiov1alpha1.AzureSubscriptionsGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) (azuresubscriptions.SubscriptionService, error) {
+	clientSecret, err := utils.GetSecretRef(ctx, g.client, generatorConfig.ClientSecretRef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret client secret: %v", err)
+	}
+	credentials := azuresubscriptions.Credentials{
+		TenantID:     generatorConfig.TenantID,
+		ClientID:     generatorConfig.ClientID,
+		ClientSecret: clientSecret,
+	}
+	return azuresubscriptions.NewARMService(ctx, credentials, generatorConfig.ManagementGroup, generatorConfig.API, generatorConfig.LoginAPI)
+}
@@ -0,0 +1,93 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	azuresubscriptions "github.com/argoproj-labs/applicationset/pkg/services/azure_subscriptions"
+)
+
+func TestAzureSubscriptionsGenerateParams(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		selectFunc  func(context.Context, *argoprojiov1alpha1.AzureSubscriptionsGenerator, *argoprojiov1alpha1.ApplicationSet) (azuresubscriptions.SubscriptionService, error)
+		values      map[string]string
+		expected    []map[string]interface{}
+		expectedErr error
+	}{
+		{
+			selectFunc: func(context.Context, *argoprojiov1alpha1.AzureSubscriptionsGenerator, *argoprojiov1alpha1.ApplicationSet) (azuresubscriptions.SubscriptionService, error) {
+				return azuresubscriptions.NewFakeService(
+					ctx,
+					[]*azuresubscriptions.Subscription{
+						{
+							ID:          "00000000-0000-0000-0000-000000000001",
+							DisplayName: "Production",
+							TenantID:    "00000000-0000-0000-0000-00000000000a",
+							State:       "Enabled",
+							Tags:        map[string]string{"env": "prod", "cost-center": "42"},
+						},
+						{
+							ID:          "00000000-0000-0000-0000-000000000002",
+							DisplayName: "Sandbox",
+							TenantID:    "00000000-0000-0000-0000-00000000000a",
+							State:       "Disabled",
+							Tags:        map[string]string{},
+						},
+					},
+					nil,
+				)
+			},
+			values: map[string]string{"region": "westeurope"},
+			expected: []map[string]interface{}{
+				{
+					"subscription_id":  "00000000-0000-0000-0000-000000000001",
+					"display_name":     "Production",
+					"tenant_id":        "00000000-0000-0000-0000-00000000000a",
+					"state":            "Enabled",
+					"tags.env":         "prod",
+					"tags.cost-center": "42",
+					"values.region":    "westeurope",
+				},
+				{
+					"subscription_id": "00000000-0000-0000-0000-000000000002",
+					"display_name":    "Sandbox",
+					"tenant_id":       "00000000-0000-0000-0000-00000000000a",
+					"state":           "Disabled",
+					"values.region":   "westeurope",
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			selectFunc: func(context.Context, *argoprojiov1alpha1.AzureSubscriptionsGenerator, *argoprojiov1alpha1.ApplicationSet) (azuresubscriptions.SubscriptionService, error) {
+				return azuresubscriptions.NewFakeService(
+					ctx,
+					nil,
+					errors.New("fake error"),
+				)
+			},
+			expected:    nil,
+			expectedErr: errors.New("error listing Azure subscriptions: fake error"),
+		},
+	}
+
+	for _, c := range cases {
+		gen := AzureSubscriptionsGenerator{
+			selectServiceProviderFunc: c.selectFunc,
+		}
+		generatorConfig := argoprojiov1alpha1.ApplicationSetGenerator{
+			AzureSubscriptions: &argoprojiov1alpha1.AzureSubscriptionsGenerator{
+				Values: c.values,
+			},
+		}
+
+		got, gotErr := gen.GenerateParams(context.Background(), &generatorConfig, nil)
+		assert.Equal(t, c.expectedErr, gotErr)
+		assert.ElementsMatch(t, c.expected, got)
+	}
+}
@@ -0,0 +1,163 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/bucket"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*BucketGenerator)(nil)
+
+const (
+	DefaultBucketRequeueAfterSeconds = 30 * time.Minute
+)
+
+// BucketGenerator generates parameters from the JSON or YAML objects of an S3 or Google Cloud Storage bucket.
+type BucketGenerator struct {
+	client client.Client
+}
+
+func NewBucketGenerator(client client.Client) Generator {
+	g := &BucketGenerator{
+		client: client,
+	}
+	return g
+}
+
+func (g *BucketGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.Bucket.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Bucket.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultBucketRequeueAfterSeconds
+}
+
+func (g *BucketGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Bucket.Template
+}
+
+func (g *BucketGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.Bucket == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.Bucket
+
+	b, err := g.newBucket(ctx, generatorConfig.S3, generatorConfig.GCS, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := b.List(ctx, generatorConfig.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	// Sort the keys to ensure a deterministic processing order
+	sort.Strings(keys)
+
+	res := []map[string]interface{}{}
+	for _, key := range keys {
+		switch path.Ext(key) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		content, err := b.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		paramSets, err := generateParamsFromBucketObject(key, content, useGoTemplate(applicationSetInfo))
+		if err != nil {
+			return nil, fmt.Errorf("unable to process object '%s': %v", key, err)
+		}
+		for _, params := range paramSets {
+			for k, v := range generatorConfig.Values {
+				params[fmt.Sprintf("values.%s", k)] = v
+			}
+			res = append(res, params)
+		}
+	}
+
+	return res, nil
+}
+
+// generateParamsFromBucketObject returns the parameter sets of a JSON or YAML object, which may either contain a single
+// object, or a list of objects.
+func generateParamsFromBucketObject(key string, content []byte, useGoTemplate bool) ([]map[string]interface{}, error) {
+	objectsFound := []map[string]interface{}{}
+
+	// First, we attempt to parse as an array
+	err := yaml.Unmarshal(content, &objectsFound)
+	if err != nil {
+		// If unable to parse as an array, attempt to parse as a single object
+		singleObj := make(map[string]interface{})
+		err = yaml.Unmarshal(content, &singleObj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse object: %v", err)
+		}
+		objectsFound = append(objectsFound, singleObj)
+	}
+
+	basename := strings.TrimSuffix(path.Base(key), path.Ext(key))
+	res := []map[string]interface{}{}
+	for _, objectFound := range objectsFound {
+		params, err := fileObjectToParams(objectFound, useGoTemplate)
+		if err != nil {
+			return nil, err
+		}
+		params["key"] = key
+		params["key.basename"] = basename
+		params["key.basenameNormalized"] = sanitizeName(basename)
+		res = append(res, params)
+	}
+	return res, nil
+}
+
+// newBucket returns the client of the bucket of the generator, with the credentials read from the Secrets of the
+// namespace.
+func (g *BucketGenerator) newBucket(ctx context.Context, s3 *argoprojiov1alpha1.BucketGeneratorS3, gcs *argoprojiov1alpha1.BucketGeneratorGCS, namespace string) (bucket.Bucket, error) {
+	if (s3 == nil) == (gcs == nil) {
+		return nil, fmt.Errorf("bucket generator requires exactly one of s3 or gcs")
+	}
+
+	if s3 != nil {
+		if (s3.AccessKeyIDRef == nil) != (s3.SecretAccessKeyRef == nil) {
+			return nil, fmt.Errorf("accessKeyIDRef and secretAccessKeyRef must be set together")
+		}
+		accessKeyID, err := utils.GetSecretRef(ctx, g.client, s3.AccessKeyIDRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret access key ID: %v", err)
+		}
+		secretAccessKey, err := utils.GetSecretRef(ctx, g.client, s3.SecretAccessKeyRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret secret access key: %v", err)
+		}
+		return bucket.NewS3Bucket(s3.Bucket, s3.Region, s3.Endpoint, bucket.S3Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+		})
+	}
+
+	serviceAccountKey, err := utils.GetSecretRef(ctx, g.client, gcs.ServiceAccountKeyRef, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret service account key: %v", err)
+	}
+	return bucket.NewGCSBucket(gcs.Bucket, gcs.Endpoint, []byte(serviceAccountKey))
+}
@@ -0,0 +1,131 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestBucketGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=AKIAEXAMPLE/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/inventory/":
+			if r.URL.Query().Get("prefix") == "invalid" {
+				_, _ = fmt.Fprint(w, `<ListBucketResult><Contents><Key>invalid/staging.yaml</Key></Contents></ListBucketResult>`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `<ListBucketResult>
+  <Contents><Key>environments/staging.yaml</Key></Contents>
+  <Contents><Key>environments/production.json</Key></Contents>
+  <Contents><Key>environments/README.md</Key></Contents>
+</ListBucketResult>`)
+		case "/inventory/environments/staging.yaml":
+			_, _ = fmt.Fprint(w, "cluster:\n  name: staging\n  url: https://staging.example.com\n")
+		case "/inventory/environments/production.json":
+			_, _ = fmt.Fprint(w, `[{"cluster":{"name":"production-eu"}},{"cluster":{"name":"production-us"}}]`)
+		case "/inventory/invalid/staging.yaml":
+			_, _ = fmt.Fprint(w, "- not\n  valid")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := func() *argoprojiov1alpha1.BucketGeneratorS3 {
+		return &argoprojiov1alpha1.BucketGeneratorS3{
+			Bucket:             "inventory",
+			Region:             "eu-west-1",
+			Endpoint:           ts.URL,
+			AccessKeyIDRef:     &argoprojiov1alpha1.SecretRef{SecretName: "s3-credentials", Key: "accessKeyID"},
+			SecretAccessKeyRef: &argoprojiov1alpha1.SecretRef{SecretName: "s3-credentials", Key: "secretAccessKey"},
+		}
+	}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.BucketGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "objects under a prefix",
+			generator: &argoprojiov1alpha1.BucketGenerator{
+				S3:     s3(),
+				Prefix: "environments",
+				Values: map[string]string{"team": "platform"},
+			},
+			expected: []map[string]interface{}{
+				{"cluster.name": "production-eu", "key": "environments/production.json", "key.basename": "production", "key.basenameNormalized": "production", "values.team": "platform"},
+				{"cluster.name": "production-us", "key": "environments/production.json", "key.basename": "production", "key.basenameNormalized": "production", "values.team": "platform"},
+				{"cluster.name": "staging", "cluster.url": "https://staging.example.com", "key": "environments/staging.yaml", "key.basename": "staging", "key.basenameNormalized": "staging", "values.team": "platform"},
+			},
+		},
+		{
+			name: "invalid object",
+			generator: &argoprojiov1alpha1.BucketGenerator{
+				S3:     s3(),
+				Prefix: "invalid",
+			},
+			expectedError: "unable to process object 'invalid/staging.yaml': unable to parse object",
+		},
+		{
+			name: "both s3 and gcs",
+			generator: &argoprojiov1alpha1.BucketGenerator{
+				S3:  s3(),
+				GCS: &argoprojiov1alpha1.BucketGeneratorGCS{Bucket: "inventory"},
+			},
+			expectedError: "bucket generator requires exactly one of s3 or gcs",
+		},
+		{
+			name: "missing secret access key",
+			generator: &argoprojiov1alpha1.BucketGenerator{
+				S3: &argoprojiov1alpha1.BucketGeneratorS3{
+					Bucket:         "inventory",
+					Region:         "eu-west-1",
+					AccessKeyIDRef: &argoprojiov1alpha1.SecretRef{SecretName: "s3-credentials", Key: "accessKeyID"},
+				},
+			},
+			expectedError: "accessKeyIDRef and secretAccessKeyRef must be set together",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "argocd"},
+		Data:       map[string][]byte{"accessKeyID": []byte("AKIAEXAMPLE"), "secretAccessKey": []byte("secret")},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			gen := NewBucketGenerator(fakeClient)
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{Bucket: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), cc.expectedError)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
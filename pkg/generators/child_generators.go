@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"time"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// childGenerator converts a child generator of a Matrix, Merge or Union generator to a generator which can be
+// transformed.
+func childGenerator(appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator) *argoprojiov1alpha1.ApplicationSetGenerator {
+	var matrix *argoprojiov1alpha1.MatrixGenerator
+	if appSetBaseGenerator.Matrix != nil {
+		matrix = appSetBaseGenerator.Matrix.ToMatrixGenerator()
+	}
+
+	var mergeGenerator *argoprojiov1alpha1.MergeGenerator
+	if appSetBaseGenerator.Merge != nil {
+		mergeGenerator = appSetBaseGenerator.Merge.ToMergeGenerator()
+	}
+
+	var unionGenerator *argoprojiov1alpha1.UnionGenerator
+	if appSetBaseGenerator.Union != nil {
+		unionGenerator = appSetBaseGenerator.Union.ToUnionGenerator()
+	}
+
+	return &argoprojiov1alpha1.ApplicationSetGenerator{
+		List:                    appSetBaseGenerator.List,
+		Clusters:                appSetBaseGenerator.Clusters,
+		Git:                     appSetBaseGenerator.Git,
+		SCMProvider:             appSetBaseGenerator.SCMProvider,
+		ClusterDecisionResource: appSetBaseGenerator.ClusterDecisionResource,
+		PullRequest:             appSetBaseGenerator.PullRequest,
+		Plugin:                  appSetBaseGenerator.Plugin,
+		HTTP:                    appSetBaseGenerator.HTTP,
+		Secret:                  appSetBaseGenerator.Secret,
+		KubernetesResource:      appSetBaseGenerator.KubernetesResource,
+		AzureSubscriptions:      appSetBaseGenerator.AzureSubscriptions,
+		Vault:                   appSetBaseGenerator.Vault,
+		Consul:                  appSetBaseGenerator.Consul,
+		HelmRepository:          appSetBaseGenerator.HelmRepository,
+		Bucket:                  appSetBaseGenerator.Bucket,
+		SQL:                     appSetBaseGenerator.SQL,
+		TerraformState:          appSetBaseGenerator.TerraformState,
+		Matrix:                  matrix,
+		Merge:                   mergeGenerator,
+		Union:                   unionGenerator,
+	}
+}
+
+// getChildRequeueAfter returns the requeue interval of a Matrix, Merge or Union generator: requeueAfterSeconds if set,
+// otherwise the shortest requeue interval of its child generators.
+func getChildRequeueAfter(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, requeueAfterSeconds *int64, supportedGenerators map[string]Generator) time.Duration {
+	if requeueAfterSeconds != nil {
+		return time.Duration(*requeueAfterSeconds) * time.Second
+	}
+
+	res := maxDuration
+	var found bool
+
+	for _, r := range generators {
+		base := childGenerator(r)
+		for _, g := range GetRelevantGenerators(base, supportedGenerators) {
+			temp := g.GetRequeueAfter(base)
+			if temp < res && temp != NoRequeueAfter {
+				found = true
+				res = temp
+			}
+		}
+	}
+
+	if found {
+		return res
+	}
+	return NoRequeueAfter
+}
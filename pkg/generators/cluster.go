@@ -29,7 +29,6 @@ var _ Generator = (*ClusterGenerator)(nil)
 // ClusterGenerator generates Applications for some or all clusters registered with ArgoCD.
 type ClusterGenerator struct {
 	client.Client
-	ctx       context.Context
 	clientset kubernetes.Interface
 	// namespace is the Argo CD namespace
 	namespace       string
@@ -42,7 +41,6 @@ func NewClusterGenerator(c client.Client, ctx context.Context, clientset kuberne
 
 	g := &ClusterGenerator{
 		Client:          c,
-		ctx:             ctx,
 		clientset:       clientset,
 		namespace:       namespace,
 		settingsManager: settingsManager,
@@ -51,6 +49,12 @@ func NewClusterGenerator(c client.Client, ctx context.Context, clientset kuberne
 }
 
 func (g *ClusterGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Don't requeue by default, if no override is specified.
+
+	if appSetGenerator.Clusters.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Clusters.RequeueAfterSeconds) * time.Second
+	}
+
 	return NoRequeueAfter
 }
 
@@ -58,8 +62,7 @@ func (g *ClusterGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.Appli
 	return &appSetGenerator.Clusters.Template
 }
 
-func (g *ClusterGenerator) GenerateParams(
-	appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *ClusterGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
@@ -74,7 +77,7 @@ func (g *ClusterGenerator) GenerateParams(
 	ignoreLocalClusters := len(appSetGenerator.Clusters.Selector.MatchExpressions) > 0 || len(appSetGenerator.Clusters.Selector.MatchLabels) > 0
 
 	// ListCluster from Argo CD's util/db package will include the local cluster in the list of clusters
-	clustersFromArgoCD, err := utils.ListClusters(g.ctx, g.clientset, g.namespace)
+	clustersFromArgoCD, err := utils.ListClusters(ctx, g.clientset, g.namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -83,12 +86,12 @@ func (g *ClusterGenerator) GenerateParams(
 		return nil, nil
 	}
 
-	clusterSecrets, err := g.getSecretsByClusterName(appSetGenerator)
+	clusterSecrets, err := g.getSecretsByClusterName(ctx, appSetGenerator)
 	if err != nil {
 		return nil, err
 	}
 
-	res := []map[string]string{}
+	res := []map[string]interface{}{}
 
 	secretsFound := []corev1.Secret{}
 
@@ -101,7 +104,7 @@ func (g *ClusterGenerator) GenerateParams(
 
 		} else if !ignoreLocalClusters {
 			// If there is no secret for the cluster, it's the local cluster, so handle it here.
-			params := map[string]string{}
+			params := map[string]interface{}{}
 			params["name"] = cluster.Name
 			params["server"] = cluster.Server
 
@@ -117,7 +120,7 @@ func (g *ClusterGenerator) GenerateParams(
 
 	// For each matching cluster secret (non-local clusters only)
 	for _, cluster := range secretsFound {
-		params := map[string]string{}
+		params := map[string]interface{}{}
 		params["name"] = string(cluster.Data["name"])
 		params["nameNormalized"] = sanitizeName(string(cluster.Data["name"]))
 		params["server"] = string(cluster.Data["server"])
@@ -138,7 +141,7 @@ func (g *ClusterGenerator) GenerateParams(
 	return res, nil
 }
 
-func (g *ClusterGenerator) getSecretsByClusterName(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) (map[string]corev1.Secret, error) {
+func (g *ClusterGenerator) getSecretsByClusterName(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) (map[string]corev1.Secret, error) {
 	// List all Clusters:
 	clusterSecretList := &corev1.SecretList{}
 
@@ -148,7 +151,7 @@ func (g *ClusterGenerator) getSecretsByClusterName(appSetGenerator *argoprojiov1
 		return nil, err
 	}
 
-	if err := g.Client.List(context.Background(), clusterSecretList, client.MatchingLabelsSelector{Selector: secretSelector}); err != nil {
+	if err := g.Client.List(ctx, clusterSecretList, client.MatchingLabelsSelector{Selector: secretSelector}); err != nil {
 		return nil, err
 	}
 	log.Debug("clusters matching labels", "count", len(clusterSecretList.Items))
@@ -0,0 +1,160 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/consul"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*ConsulGenerator)(nil)
+
+const (
+	DefaultConsulRequeueAfterSeconds = 3 * time.Minute
+)
+
+// ConsulGenerator generates parameters from the service catalog or the KV store of Consul.
+type ConsulGenerator struct {
+	client client.Client
+}
+
+func NewConsulGenerator(client client.Client) Generator {
+	g := &ConsulGenerator{
+		client: client,
+	}
+	return g
+}
+
+func (g *ConsulGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 3 minutes, if no override is specified.
+
+	if appSetGenerator.Consul.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Consul.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultConsulRequeueAfterSeconds
+}
+
+func (g *ConsulGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Consul.Template
+}
+
+func (g *ConsulGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.Consul == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.Consul
+	if (generatorConfig.Services == nil) == (generatorConfig.KV == nil) {
+		return nil, fmt.Errorf("consul generator requires exactly one of services or kv")
+	}
+
+	token, err := utils.GetSecretRef(ctx, g.client, generatorConfig.TokenRef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret token: %v", err)
+	}
+	catalog, err := consul.NewClient(generatorConfig.Address, token)
+	if err != nil {
+		return nil, err
+	}
+
+	datacenters := generatorConfig.Datacenters
+	if len(datacenters) == 0 {
+		all, err := catalog.Datacenters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(all) == 0 {
+			return nil, fmt.Errorf("consul agent at %s returned no datacenter", generatorConfig.Address)
+		}
+		datacenters = all[:1]
+	}
+
+	res := []map[string]interface{}{}
+	for _, datacenter := range datacenters {
+		var paramSets []map[string]interface{}
+		if generatorConfig.Services != nil {
+			paramSets, err = getConsulServiceParams(ctx, catalog, datacenter, generatorConfig.Services.Tags)
+		} else {
+			paramSets, err = getConsulKVParams(ctx, catalog, datacenter, generatorConfig.KV.Prefix)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, params := range paramSets {
+			for key, value := range generatorConfig.Values {
+				params[fmt.Sprintf("values.%s", key)] = value
+			}
+			res = append(res, params)
+		}
+	}
+
+	return res, nil
+}
+
+// getConsulServiceParams returns a parameter set for each service of the datacenter having all the tags.
+func getConsulServiceParams(ctx context.Context, catalog consul.CatalogService, datacenter string, tags []string) ([]map[string]interface{}, error) {
+	services, err := catalog.Services(ctx, datacenter)
+	if err != nil {
+		return nil, err
+	}
+
+	paramSets := []map[string]interface{}{}
+	for _, service := range services {
+		if !hasAllTags(service.Tags, tags) {
+			continue
+		}
+		paramSets = append(paramSets, map[string]interface{}{
+			"service":    service.Name,
+			"tags":       strings.Join(service.Tags, ","),
+			"datacenter": service.Datacenter,
+		})
+	}
+	return paramSets, nil
+}
+
+// getConsulKVParams returns a parameter set for each key under the prefix in the datacenter.
+func getConsulKVParams(ctx context.Context, catalog consul.CatalogService, datacenter, prefix string) ([]map[string]interface{}, error) {
+	pairs, err := catalog.KV(ctx, datacenter, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	paramSets := make([]map[string]interface{}, 0, len(pairs))
+	for _, pair := range pairs {
+		paramSets = append(paramSets, map[string]interface{}{
+			"key":        pair.Key,
+			"name":       strings.TrimPrefix(strings.TrimPrefix(pair.Key, strings.TrimPrefix(prefix, "/")), "/"),
+			"value":      pair.Value,
+			"datacenter": pair.Datacenter,
+		})
+	}
+	return paramSets, nil
+}
+
+func hasAllTags(tags, required []string) bool {
+	for _, r := range required {
+		found := false
+		for _, tag := range tags {
+			if tag == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
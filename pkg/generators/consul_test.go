@@ -0,0 +1,133 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestConsulGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		dc := r.URL.Query().Get("dc")
+		switch {
+		case r.URL.Path == "/v1/catalog/datacenters":
+			_, _ = fmt.Fprint(w, `["dc1","dc2"]`)
+		case r.URL.Path == "/v1/catalog/services" && dc == "dc1":
+			_, _ = fmt.Fprint(w, `{"web":["argocd","public"],"consul":[],"api":["argocd"]}`)
+		case r.URL.Path == "/v1/catalog/services" && dc == "dc2":
+			_, _ = fmt.Fprint(w, `{"web":["argocd"]}`)
+		case r.URL.Path == "/v1/kv/apps" && dc == "dc1":
+			// "production" and "staging", base64 encoded
+			_, _ = fmt.Fprint(w, `[{"Key":"apps/web","Value":"cHJvZHVjdGlvbg=="},{"Key":"apps/api","Value":"c3RhZ2luZw=="}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tokenRef := &argoprojiov1alpha1.SecretRef{SecretName: "consul-token", Key: "token"}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.ConsulGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "services of the local datacenter",
+			generator: &argoprojiov1alpha1.ConsulGenerator{
+				Address:  ts.URL,
+				TokenRef: tokenRef,
+				Services: &argoprojiov1alpha1.ConsulServices{},
+				Values:   map[string]string{"team": "platform"},
+			},
+			expected: []map[string]interface{}{
+				{"service": "api", "tags": "argocd", "datacenter": "dc1", "values.team": "platform"},
+				{"service": "consul", "tags": "", "datacenter": "dc1", "values.team": "platform"},
+				{"service": "web", "tags": "argocd,public", "datacenter": "dc1", "values.team": "platform"},
+			},
+		},
+		{
+			name: "services with tags in several datacenters",
+			generator: &argoprojiov1alpha1.ConsulGenerator{
+				Address:     ts.URL,
+				TokenRef:    tokenRef,
+				Datacenters: []string{"dc1", "dc2"},
+				Services:    &argoprojiov1alpha1.ConsulServices{Tags: []string{"argocd"}},
+			},
+			expected: []map[string]interface{}{
+				{"service": "api", "tags": "argocd", "datacenter": "dc1"},
+				{"service": "web", "tags": "argocd,public", "datacenter": "dc1"},
+				{"service": "web", "tags": "argocd", "datacenter": "dc2"},
+			},
+		},
+		{
+			name: "keys under a prefix",
+			generator: &argoprojiov1alpha1.ConsulGenerator{
+				Address:  ts.URL,
+				TokenRef: tokenRef,
+				KV:       &argoprojiov1alpha1.ConsulKV{Prefix: "apps"},
+			},
+			expected: []map[string]interface{}{
+				{"key": "apps/web", "name": "web", "value": "production", "datacenter": "dc1"},
+				{"key": "apps/api", "name": "api", "value": "staging", "datacenter": "dc1"},
+			},
+		},
+		{
+			name: "both services and kv",
+			generator: &argoprojiov1alpha1.ConsulGenerator{
+				Address:  ts.URL,
+				Services: &argoprojiov1alpha1.ConsulServices{},
+				KV:       &argoprojiov1alpha1.ConsulKV{Prefix: "apps"},
+			},
+			expectedError: "consul generator requires exactly one of services or kv",
+		},
+		{
+			name: "missing token",
+			generator: &argoprojiov1alpha1.ConsulGenerator{
+				Address:     ts.URL,
+				Datacenters: []string{"dc1"},
+				Services:    &argoprojiov1alpha1.ConsulServices{},
+			},
+			expectedError: "error listing services of datacenter dc1: unexpected status 403 Forbidden from /v1/catalog/services",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "consul-token", Namespace: "argocd"},
+		Data:       map[string][]byte{"token": []byte("my-token")},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			gen := NewConsulGenerator(fakeClient)
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{Consul: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
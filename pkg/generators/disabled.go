@@ -0,0 +1,42 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// disabledGenerator is a generator disabled by the controller, which fails rather than generating no param sets, so
+// that the Applications of the ApplicationSets using it are not deleted.
+type disabledGenerator struct {
+	Generator
+	name string
+	// reason tells why the generator is disabled, e.g. "disabled by the ApplicationSet controller".
+	reason string
+}
+
+var _ Generator = (*disabledGenerator)(nil)
+
+func (g *disabledGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	return nil, fmt.Errorf("the %s generator is %s", g.name, g.reason)
+}
+
+// DisableGenerators replaces the disabled generators of the map, by their Go names, e.g. SCMProvider, with generators
+// which fail.
+func DisableGenerators(generators map[string]Generator, disabled map[string]bool) map[string]Generator {
+	return DisableGeneratorsWithReason(generators, disabled, "disabled by the ApplicationSet controller")
+}
+
+// DisableGeneratorsWithReason is like DisableGenerators, with the reason given by the errors of the disabled
+// generators, e.g. "not supported offline".
+func DisableGeneratorsWithReason(generators map[string]Generator, disabled map[string]bool, reason string) map[string]Generator {
+	names := utils.GeneratorJSONNames()
+	for name, generator := range generators {
+		if disabled[name] {
+			generators[name] = &disabledGenerator{Generator: generator, name: names[name], reason: reason}
+		}
+	}
+	return generators
+}
@@ -0,0 +1,40 @@
+package generators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestDisableGenerators(t *testing.T) {
+	list := NewListGenerator()
+	generators := DisableGenerators(map[string]Generator{
+		"List":        list,
+		"SCMProvider": NewSCMProviderGenerator(nil),
+	}, map[string]bool{"SCMProvider": true})
+	assert.Same(t, list, generators["List"])
+
+	requeueAfterSeconds := int64(60)
+	appSetGenerator := &argoprojiov1alpha1.ApplicationSetGenerator{
+		SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{RequeueAfterSeconds: &requeueAfterSeconds},
+	}
+	_, err := generators["SCMProvider"].GenerateParams(context.Background(), appSetGenerator, &argoprojiov1alpha1.ApplicationSet{})
+	assert.EqualError(t, err, "the scmProvider generator is disabled by the ApplicationSet controller")
+
+	// the ApplicationSets are still requeued, so that they recover once the generator is enabled again
+	assert.Equal(t, time.Minute, generators["SCMProvider"].GetRequeueAfter(appSetGenerator))
+	assert.Same(t, &appSetGenerator.SCMProvider.Template, generators["SCMProvider"].GetTemplate(appSetGenerator))
+}
+
+func TestDisableGeneratorsWithReason(t *testing.T) {
+	generators := DisableGeneratorsWithReason(map[string]Generator{
+		"HTTP": NewHTTPGenerator(nil),
+	}, map[string]bool{"HTTP": true}, "not supported offline")
+
+	_, err := generators["HTTP"].GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{HTTP: &argoprojiov1alpha1.HTTPGenerator{}}, &argoprojiov1alpha1.ApplicationSet{})
+	assert.EqualError(t, err, "the http generator is not supported offline")
+}
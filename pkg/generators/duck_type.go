@@ -1,6 +1,7 @@
 package generators
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -12,18 +13,19 @@ import (
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
 	"github.com/argoproj-labs/applicationset/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 var _ Generator = (*DuckTypeGenerator)(nil)
 
 // DuckTypeGenerator generates Applications for some or all clusters registered with ArgoCD.
 type DuckTypeGenerator struct {
-	ctx             context.Context
 	dynClient       dynamic.Interface
 	clientset       kubernetes.Interface
 	namespace       string // namespace is the Argo CD namespace
@@ -35,7 +37,6 @@ func NewDuckTypeGenerator(ctx context.Context, dynClient dynamic.Interface, clie
 	settingsManager := settings.NewSettingsManager(ctx, clientset, namespace)
 
 	g := &DuckTypeGenerator{
-		ctx:             ctx,
 		dynClient:       dynClient,
 		clientset:       clientset,
 		namespace:       namespace,
@@ -59,7 +60,7 @@ func (g *DuckTypeGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.Appl
 	return &appSetGenerator.ClusterDecisionResource.Template
 }
 
-func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *DuckTypeGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
@@ -71,7 +72,7 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 	}
 
 	// ListCluster from Argo CD's util/db package will include the local cluster in the list of clusters
-	clustersFromArgoCD, err := utils.ListClusters(g.ctx, g.clientset, g.namespace)
+	clustersFromArgoCD, err := utils.ListClusters(ctx, g.clientset, g.namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -81,24 +82,23 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 	}
 
 	// Read the configMapRef
-	cm, err := g.clientset.CoreV1().ConfigMaps(g.namespace).Get(g.ctx, appSetGenerator.ClusterDecisionResource.ConfigMapRef, metav1.GetOptions{})
+	cm, err := g.clientset.CoreV1().ConfigMaps(g.namespace).Get(ctx, appSetGenerator.ClusterDecisionResource.ConfigMapRef, metav1.GetOptions{})
 
 	if err != nil {
 		return nil, err
 	}
 
 	// Extract GVK data for the dynamic client to use
-	versionIdx := strings.Index(cm.Data["apiVersion"], "/")
-	kind := cm.Data["kind"]
 	resourceName := appSetGenerator.ClusterDecisionResource.Name
 	labelSelector := appSetGenerator.ClusterDecisionResource.LabelSelector
 
-	log.WithField("kind.apiVersion", kind+"."+cm.Data["apiVersion"]).Info("Kind.Group/Version Reference")
+	log.WithField("kind.apiVersion", cm.Data["kind"]+"."+cm.Data["apiVersion"]).Info("Kind.Group/Version Reference")
 
 	// Validate the fields
-	if kind == "" || versionIdx < 1 {
-		log.Warningf("kind=%v, resourceName=%v, versionIdx=%v", kind, resourceName, versionIdx)
-		return nil, errors.New("There is a problem with the apiVersion, kind or resourceName provided")
+	duckGVR, err := GetDuckTypeGVR(cm)
+	if err != nil {
+		log.Warningf("kind=%v, resourceName=%v, apiVersion=%v", cm.Data["kind"], resourceName, cm.Data["apiVersion"])
+		return nil, err
 	}
 
 	if (resourceName == "" && labelSelector.MatchLabels == nil && labelSelector.MatchExpressions == nil) ||
@@ -108,12 +108,7 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 		return nil, errors.New("There is a problem with the definition of the ClusterDecisionResource generator")
 	}
 
-	// Split up the apiVersion
-	group := cm.Data["apiVersion"][0:versionIdx]
-	version := cm.Data["apiVersion"][versionIdx+1:]
-	log.WithField("kind.group.version", kind+"."+group+"/"+version).Debug("decoded Ref")
-
-	duckGVR := schema.GroupVersionResource{Group: group, Version: version, Resource: kind}
+	log.WithField("kind.group.version", duckGVR.Resource+"."+duckGVR.Group+"/"+duckGVR.Version).Debug("decoded Ref")
 
 	listOptions := metav1.ListOptions{}
 	if resourceName == "" {
@@ -125,7 +120,7 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 		log.WithField("listOptions.FieldSelector", listOptions.FieldSelector).Info("selection type")
 	}
 
-	duckResources, err := g.dynClient.Resource(duckGVR).Namespace(g.namespace).List(g.ctx, listOptions)
+	duckResources, err := g.dynClient.Resource(duckGVR).Namespace(g.namespace).List(ctx, listOptions)
 
 	if err != nil {
 		log.WithField("GVK", duckGVR).Warning("resources were not found")
@@ -151,8 +146,10 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 
 	}
 
-	res := []map[string]string{}
+	res := []map[string]interface{}{}
 	clusterDecisions := []interface{}{}
+	// statusParams holds the statusFields params of the resource each cluster decision was read from
+	statusParams := []map[string]string{}
 
 	// Build the decision slice
 	for _, duckResource := range duckResources.Items {
@@ -165,7 +162,16 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 
 		log.WithField("duckResourceStatus", duckResource.Object["status"]).Debug("found resource")
 
-		clusterDecisions = append(clusterDecisions, duckResource.Object["status"].(map[string]interface{})[statusListKey].([]interface{})...)
+		resourceStatusParams, err := getStatusFieldParams(duckResource.Object, appSetGenerator.ClusterDecisionResource.StatusFields)
+		if err != nil {
+			return nil, fmt.Errorf("error reading status fields of clusterDecisionResource %s: %v", duckResource.GetName(), err)
+		}
+
+		decisions := duckResource.Object["status"].(map[string]interface{})[statusListKey].([]interface{})
+		clusterDecisions = append(clusterDecisions, decisions...)
+		for range decisions {
+			statusParams = append(statusParams, resourceStatusParams)
+		}
 
 	}
 	log.Infof("Number of decisions found: %v", len(clusterDecisions))
@@ -174,10 +180,10 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 	argoClusters := clustersFromArgoCD.Items
 
 	if len(clusterDecisions) > 0 {
-		for _, cluster := range clusterDecisions {
+		for i, cluster := range clusterDecisions {
 
 			// generated instance of cluster params
-			params := map[string]string{}
+			params := map[string]interface{}{}
 
 			log.Infof("cluster: %v", cluster)
 			matchValue := cluster.(map[string]interface{})[matchKey]
@@ -217,6 +223,10 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 				params[fmt.Sprintf("values.%s", key)] = value
 			}
 
+			for key, value := range statusParams[i] {
+				params[key] = value
+			}
+
 			res = append(res, params)
 		}
 	} else {
@@ -226,3 +236,39 @@ func (g *DuckTypeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.A
 
 	return res, nil
 }
+
+// GetDuckTypeGVR returns the resource of the duck type defined by the given ClusterDecisionResource ConfigMap.
+func GetDuckTypeGVR(cm *corev1.ConfigMap) (schema.GroupVersionResource, error) {
+	versionIdx := strings.Index(cm.Data["apiVersion"], "/")
+	kind := cm.Data["kind"]
+	if kind == "" || versionIdx < 1 {
+		return schema.GroupVersionResource{}, errors.New("There is a problem with the apiVersion, kind or resourceName provided")
+	}
+
+	// Split up the apiVersion
+	group := cm.Data["apiVersion"][0:versionIdx]
+	version := cm.Data["apiVersion"][versionIdx+1:]
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: kind}, nil
+}
+
+// getStatusFieldParams evaluates the given JSONPath expressions against a duck typed resource, returning the results
+// as status.<name> params. Expressions may omit the surrounding braces, and fields missing from the resource
+// produce an empty value.
+func getStatusFieldParams(resource map[string]interface{}, statusFields map[string]string) (map[string]string, error) {
+	params := map[string]string{}
+	for name, expression := range statusFields {
+		if !strings.HasPrefix(expression, "{") {
+			expression = fmt.Sprintf("{%s}", expression)
+		}
+		jp := jsonpath.New(name).AllowMissingKeys(true)
+		if err := jp.Parse(expression); err != nil {
+			return nil, fmt.Errorf("invalid JSONPath expression %q for status field %q: %v", expression, name, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := jp.Execute(buf, resource); err != nil {
+			return nil, fmt.Errorf("error evaluating status field %q: %v", name, err)
+		}
+		params[fmt.Sprintf("status.%s", name)] = buf.String()
+	}
+	return params, nil
+}
@@ -115,6 +115,29 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 		},
 	}
 
+	duckTypeWithPlacement := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": resourceApiVersion,
+			"kind":       "Duck",
+			"metadata": map[string]interface{}{
+				"name":      resourceName,
+				"namespace": "namespace",
+				"labels":    map[string]interface{}{"duck": "all-species"},
+			},
+			"status": map[string]interface{}{
+				"decisions": []interface{}{
+					map[string]interface{}{
+						"clusterName": "production-01",
+					},
+				},
+				"placement": map[string]interface{}{
+					"region": "eu-west-1",
+					"score":  int64(42),
+				},
+			},
+		},
+	}
+
 	duckTypeEmpty := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": resourceApiVersion,
@@ -148,7 +171,8 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 		labelSelector metav1.LabelSelector
 		resource      *unstructured.Unstructured
 		values        map[string]string
-		expected      []map[string]string
+		statusFields  map[string]string
+		expected      []map[string]interface{}
 		expectedError error
 	}{
 		{
@@ -156,7 +180,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			resourceName:  "",
 			resource:      duckType,
 			values:        nil,
-			expected:      []map[string]string{},
+			expected:      []map[string]interface{}{},
 			expectedError: errors.New("There is a problem with the definition of the ClusterDecisionResource generator"),
 		},
 		/*** This does not work with the FAKE runtime client, fieldSelectors are broken.
@@ -165,7 +189,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			resourceName:  resourceName + "-different",
 			resource:      duckType,
 			values:        nil,
-			expected:      []map[string]string{},
+			expected:      []map[string]interface{}{},
 			expectedError: errors.New("duck.mallard.io \"quak\" not found"),
 		},
 		***/
@@ -174,7 +198,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			resourceName: resourceName,
 			resource:     duckType,
 			values:       nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"clusterName": "production-01", "name": "production-01", "server": "https://production-01.example.com"},
 
 				{"clusterName": "staging-01", "name": "staging-01", "server": "https://staging-01.example.com"},
@@ -188,11 +212,33 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			values: map[string]string{
 				"foo": "bar",
 			},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"clusterName": "production-01", "values.foo": "bar", "name": "production-01", "server": "https://production-01.example.com"},
 			},
 			expectedError: nil,
 		},
+		{
+			name:         "duck type generator statusFields",
+			resourceName: resourceName,
+			resource:     duckTypeWithPlacement,
+			statusFields: map[string]string{
+				"region":  "{.status.placement.region}",
+				"score":   ".status.placement.score",
+				"missing": "{.status.placement.missing}",
+			},
+			expected: []map[string]interface{}{
+				{"clusterName": "production-01", "name": "production-01", "server": "https://production-01.example.com",
+					"status.region": "eu-west-1", "status.score": "42", "status.missing": ""},
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "duck type generator invalid statusFields",
+			resourceName:  resourceName,
+			resource:      duckTypeWithPlacement,
+			statusFields:  map[string]string{"region": "{.status.placement[}"},
+			expectedError: errors.New("error reading status fields of clusterDecisionResource quak: invalid JSONPath expression \"{.status.placement[}\" for status field \"region\": unterminated array"),
+		},
 		{
 			name:          "duck type empty status",
 			resourceName:  resourceName,
@@ -216,7 +262,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			labelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"duck": "all-species"}},
 			resource:      duckType,
 			values:        nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"clusterName": "production-01", "name": "production-01", "server": "https://production-01.example.com"},
 
 				{"clusterName": "staging-01", "name": "staging-01", "server": "https://staging-01.example.com"},
@@ -231,7 +277,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			values: map[string]string{
 				"foo": "bar",
 			},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"clusterName": "production-01", "values.foo": "bar", "name": "production-01", "server": "https://production-01.example.com"},
 			},
 			expectedError: nil,
@@ -248,7 +294,7 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 			}},
 			resource: duckType,
 			values:   nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"clusterName": "production-01", "name": "production-01", "server": "https://production-01.example.com"},
 
 				{"clusterName": "staging-01", "name": "staging-01", "server": "https://staging-01.example.com"},
@@ -294,12 +340,13 @@ func TestGenerateParamsForDuckType(t *testing.T) {
 
 			var duckTypeGenerator = NewDuckTypeGenerator(context.Background(), fakeDynClient, appClientset, "namespace")
 
-			got, err := duckTypeGenerator.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+			got, err := duckTypeGenerator.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
 				ClusterDecisionResource: &argoprojiov1alpha1.DuckTypeGenerator{
 					ConfigMapRef:  "my-configmap",
 					Name:          testCase.resourceName,
 					LabelSelector: testCase.labelSelector,
 					Values:        testCase.values,
+					StatusFields:  testCase.statusFields,
 				},
 			}, nil)
 
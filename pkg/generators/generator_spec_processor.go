@@ -1,15 +1,29 @@
 package generators
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"time"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 	"github.com/imdario/mergo"
-	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func GetRelevantGenerators(requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, generators map[string]Generator) []Generator {
 	var res []Generator
+	for _, name := range GetRelevantGeneratorNames(requestedGenerator, generators) {
+		res = append(res, generators[name])
+	}
+	return res
+}
+
+// GetRelevantGeneratorNames returns the names of the generators set in the requested generator, e.g. List or Git.
+func GetRelevantGeneratorNames(requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, generators map[string]Generator) []string {
+	var res []string
 
 	v := reflect.Indirect(reflect.ValueOf(requestedGenerator))
 	for i := 0; i < v.NumField(); i++ {
@@ -18,8 +32,14 @@ func GetRelevantGenerators(requestedGenerator *argoprojiov1alpha1.ApplicationSet
 			continue
 		}
 
+		// Only the fields of generators are relevant, not the fields common to all generators such as Schedule.
+		name := v.Type().Field(i).Name
+		if _, ok := generators[name]; !ok {
+			continue
+		}
+
 		if !reflect.ValueOf(field.Interface()).IsNil() {
-			res = append(res, generators[v.Type().Field(i).Name])
+			res = append(res, name)
 		}
 	}
 
@@ -27,21 +47,78 @@ func GetRelevantGenerators(requestedGenerator *argoprojiov1alpha1.ApplicationSet
 }
 
 type TransformResult struct {
-	Params   []map[string]string
+	Params   []map[string]interface{}
 	Template argoprojiov1alpha1.ApplicationSetTemplate
+	// NamedTemplates are the named templates of the ApplicationSet, merged with the template of the generator.
+	NamedTemplates map[string]argoprojiov1alpha1.ApplicationSetTemplate
 }
 
 //Transform a spec generator to list of paramSets and a template
-func Transform(requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, allGenerators map[string]Generator, baseTemplate argoprojiov1alpha1.ApplicationSetTemplate, appSet *argoprojiov1alpha1.ApplicationSet) ([]TransformResult, error) {
+func Transform(ctx context.Context, requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, allGenerators map[string]Generator, baseTemplate argoprojiov1alpha1.ApplicationSetTemplate, appSet *argoprojiov1alpha1.ApplicationSet) ([]TransformResult, error) {
+	results, firstError := transform(ctx, requestedGenerator, allGenerators, baseTemplate, appSet)
+
+	// The params of the ApplicationSet, the defaults, the param references and the transforms apply to the params of
+	// the top level generators only: the params of child generators are combined first.
+	res := make([]TransformResult, 0, len(results))
+	for _, result := range results {
+		params := addMissingParams(result.Params, applicationSetParams(appSet))
+		if appSet != nil {
+			params = addMissingParams(params, appSet.Spec.ParamDefaults)
+		}
+		params, err := resolveParamReferences(params, appSet)
+		if err == nil {
+			params, err = transformParams(params, appSet)
+		}
+		if err != nil {
+			utils.ApplicationSetLogger(appSet).WithError(err).Error("error transforming params")
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+
+		res = append(res, TransformResult{
+			Params:         params,
+			Template:       result.Template,
+			NamedTemplates: result.NamedTemplates,
+		})
+	}
+
+	return res, firstError
+}
+
+// transform a generator to a list of paramSets and a template, without the params common to all the generators of the
+// ApplicationSet: it is used for the child generators of the Matrix, Merge and Union generators.
+func transform(ctx context.Context, requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, allGenerators map[string]Generator, baseTemplate argoprojiov1alpha1.ApplicationSetTemplate, appSet *argoprojiov1alpha1.ApplicationSet) ([]TransformResult, error) {
 	res := []TransformResult{}
 	var firstError error
+	logger := utils.ApplicationSetLogger(appSet)
+
+	active := true
+	if requestedGenerator.Schedule != nil {
+		var err error
+		active, err = IsScheduleActive(requestedGenerator.Schedule, time.Now())
+		if err != nil {
+			logger.WithError(err).Error("error evaluating generator schedule")
+			return res, err
+		}
+	}
 
-	generators := GetRelevantGenerators(&requestedGenerator, allGenerators)
-	for _, g := range generators {
+	for _, name := range GetRelevantGeneratorNames(&requestedGenerator, allGenerators) {
+		g := allGenerators[name]
 		// we call mergeGeneratorTemplate first because GenerateParams might be more costly so we want to fail fast if there is an error
-		mergedTemplate, err := mergeGeneratorTemplate(g, &requestedGenerator, baseTemplate)
+		mergedTemplate, err := mergeGeneratorTemplate(g, &requestedGenerator, baseTemplate, templateMergePolicy(appSet))
 		if err != nil {
-			log.WithError(err).WithField("generator", g).
+			logger.WithError(err).WithField("generator", name).
+				Error("error generating params")
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+		namedTemplates, err := mergeNamedTemplates(g, &requestedGenerator, appSet)
+		if err != nil {
+			logger.WithError(err).WithField("generator", name).
 				Error("error generating params")
 			if firstError == nil {
 				firstError = err
@@ -49,9 +126,25 @@ func Transform(requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, al
 			continue
 		}
 
-		params, err := g.GenerateParams(&requestedGenerator, appSet)
+		// Outside of the windows of the schedule, no parameters are generated, so that the Applications are deleted.
+		if !active {
+			res = append(res, TransformResult{
+				Params:         []map[string]interface{}{},
+				Template:       mergedTemplate,
+				NamedTemplates: namedTemplates,
+			})
+			continue
+		}
+
+		start := time.Now()
+		params, memoized, err := generateParams(ctx, name, g, &requestedGenerator, appSet)
+		if memoized {
+			logger.WithField("generator", name).Debug("reusing the params of an identical generator")
+		} else {
+			metrics.ObserveGenerator(name, time.Since(start), err)
+		}
 		if err != nil {
-			log.WithError(err).WithField("generator", g).
+			logger.WithError(err).WithField("generator", name).
 				Error("error generating params")
 			if firstError == nil {
 				firstError = err
@@ -60,8 +153,9 @@ func Transform(requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, al
 		}
 
 		res = append(res, TransformResult{
-			Params:   params,
-			Template: mergedTemplate,
+			Params:         params,
+			Template:       mergedTemplate,
+			NamedTemplates: namedTemplates,
 		})
 
 	}
@@ -70,13 +164,205 @@ func Transform(requestedGenerator argoprojiov1alpha1.ApplicationSetGenerator, al
 
 }
 
-func mergeGeneratorTemplate(g Generator, requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetTemplate argoprojiov1alpha1.ApplicationSetTemplate) (argoprojiov1alpha1.ApplicationSetTemplate, error) {
+func mergeGeneratorTemplate(g Generator, requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetTemplate argoprojiov1alpha1.ApplicationSetTemplate, mergePolicy argoprojiov1alpha1.TemplateMergePolicy) (argoprojiov1alpha1.ApplicationSetTemplate, error) {
 
 	// Make a copy of the value from `GetTemplate()` before merge, rather than copying directly into
 	// the provided parameter (which will touch the original resource object returned by client-go)
-	dest := g.GetTemplate(requestedGenerator).DeepCopy()
+	generatorTemplate := g.GetTemplate(requestedGenerator)
+	dest := generatorTemplate.DeepCopy()
 
 	err := mergo.Merge(dest, applicationSetTemplate)
+	if err != nil {
+		return *dest, err
+	}
+
+	// The metadata is merged according to the merge policy, rather than by mergo, which keeps the empty values of
+	// the template rather than those of the generator template.
+	generatorMeta := generatorTemplate.ApplicationSetTemplateMeta
+	switch mergePolicy {
+	case argoprojiov1alpha1.TemplateMergePolicyOverride:
+		dest.Labels = overrideMetadataMap(applicationSetTemplate.Labels, generatorMeta.Labels)
+		dest.Annotations = overrideMetadataMap(applicationSetTemplate.Annotations, generatorMeta.Annotations)
+		dest.Finalizers = overrideFinalizers(applicationSetTemplate.Finalizers, generatorMeta.Finalizers)
+	case "", argoprojiov1alpha1.TemplateMergePolicyMerge, argoprojiov1alpha1.TemplateMergePolicyDeepMerge:
+		dest.Labels = mergeMetadataMap(applicationSetTemplate.Labels, generatorMeta.Labels)
+		dest.Annotations = mergeMetadataMap(applicationSetTemplate.Annotations, generatorMeta.Annotations)
+		if mergePolicy == argoprojiov1alpha1.TemplateMergePolicyDeepMerge {
+			dest.Finalizers = combineFinalizers(applicationSetTemplate.Finalizers, generatorMeta.Finalizers)
+		}
+	default:
+		return *dest, fmt.Errorf("unknown template merge policy: %s", mergePolicy)
+	}
+
+	return *dest, nil
+}
+
+// mergeNamedTemplates merges the template of the generator with each named template of the ApplicationSet, like with
+// the template of the ApplicationSet.
+func mergeNamedTemplates(g Generator, requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) (map[string]argoprojiov1alpha1.ApplicationSetTemplate, error) {
+	if appSet == nil || len(appSet.Spec.Templates) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]argoprojiov1alpha1.ApplicationSetTemplate, len(appSet.Spec.Templates))
+	for name, namedTemplate := range appSet.Spec.Templates {
+		mergedTemplate, err := mergeGeneratorTemplate(g, requestedGenerator, namedTemplate, templateMergePolicy(appSet))
+		if err != nil {
+			return nil, err
+		}
+		res[name] = mergedTemplate
+	}
+	return res, nil
+}
+
+// templateMergePolicy returns the template merge policy of the ApplicationSet.
+func templateMergePolicy(appSet *argoprojiov1alpha1.ApplicationSet) argoprojiov1alpha1.TemplateMergePolicy {
+	if appSet == nil {
+		return ""
+	}
+	return appSet.Spec.TemplateMergePolicy
+}
+
+// overrideMetadataMap returns a copy of the map of the generator template if it is set, even if it is empty, and of the
+// map of the template otherwise.
+func overrideMetadataMap(templateMap, generatorMap map[string]string) map[string]string {
+	if generatorMap != nil {
+		return copyMetadataMap(generatorMap)
+	}
+	return copyMetadataMap(templateMap)
+}
+
+// mergeMetadataMap returns the keys of both maps, with the values of the generator template taking precedence, even if
+// they are empty.
+func mergeMetadataMap(templateMap, generatorMap map[string]string) map[string]string {
+	if templateMap == nil && generatorMap == nil {
+		return nil
+	}
+	res := copyMetadataMap(templateMap)
+	if res == nil {
+		res = make(map[string]string, len(generatorMap))
+	}
+	for key, value := range generatorMap {
+		res[key] = value
+	}
+	return res
+}
+
+func copyMetadataMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	res := make(map[string]string, len(m))
+	for key, value := range m {
+		res[key] = value
+	}
+	return res
+}
+
+// overrideFinalizers returns the finalizers of the generator template if they are set, even if empty, and those of the
+// template otherwise.
+func overrideFinalizers(templateFinalizers, generatorFinalizers []string) []string {
+	if generatorFinalizers != nil {
+		return append([]string{}, generatorFinalizers...)
+	}
+	return append([]string(nil), templateFinalizers...)
+}
+
+// combineFinalizers returns the finalizers of the template, followed by those of the generator template which are not
+// already present.
+func combineFinalizers(templateFinalizers, generatorFinalizers []string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, finalizer := range append(append([]string{}, templateFinalizers...), generatorFinalizers...) {
+		if !seen[finalizer] {
+			seen[finalizer] = true
+			res = append(res, finalizer)
+		}
+	}
+	return res
+}
+
+// addMissingParams returns the param sets with the given values for the params they don't have. Params with an empty
+// value are kept as they are.
+func addMissingParams(params []map[string]interface{}, values map[string]string) []map[string]interface{} {
+	if len(values) == 0 {
+		return params
+	}
+
+	res := make([]map[string]interface{}, len(params))
+	for i, paramSet := range params {
+		withValues := make(map[string]interface{}, len(paramSet)+len(values))
+		for name, value := range values {
+			withValues[name] = value
+		}
+		for name, value := range paramSet {
+			withValues[name] = value
+		}
+		res[i] = withValues
+	}
+	return res
+}
+
+// applicationSetParams returns the params describing the ApplicationSet itself, which are available to every template:
+// its name, namespace, labels and annotations, except the last configuration applied by kubectl.
+func applicationSetParams(appSet *argoprojiov1alpha1.ApplicationSet) map[string]string {
+	if appSet == nil {
+		return nil
+	}
+
+	res := map[string]string{
+		"applicationset.name":      appSet.Name,
+		"applicationset.namespace": appSet.Namespace,
+	}
+	for key, value := range appSet.Labels {
+		res["applicationset.labels."+key] = value
+	}
+	for key, value := range appSet.Annotations {
+		if key == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		res["applicationset.annotations."+key] = value
+	}
+	return res
+}
+
+// resolveParamReferences substitutes the references to other params in each param set, if enabled in the
+// ApplicationSet.
+func resolveParamReferences(params []map[string]interface{}, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSet == nil || !appSet.Spec.ResolveParamReferences {
+		return params, nil
+	}
+
+	res := make([]map[string]interface{}, len(params))
+	for i, paramSet := range params {
+		resolved, err := utils.ResolveParamReferences(paramSet)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = resolved
+	}
+	return res, nil
+}
+
+// transformParams applies the param transforms of the ApplicationSet to each param set.
+func transformParams(params []map[string]interface{}, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSet == nil || len(appSet.Spec.ParamTransforms) == 0 {
+		return params, nil
+	}
+
+	res := make([]map[string]interface{}, len(params))
+	for i, paramSet := range params {
+		transformed, err := utils.ApplyParamTransforms(paramSet, appSet.Spec.ParamTransforms, appSet.Spec.GoTemplateOptions)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = transformed
+	}
+	return res, nil
+}
 
-	return *dest, err
+// useGoTemplate returns whether the templates of the ApplicationSet are Go templates, in which case generators may
+// generate structured parameters, such as lists and objects, rather than strings only.
+func useGoTemplate(appSet *argoprojiov1alpha1.ApplicationSet) bool {
+	return appSet != nil && appSet.Spec.GoTemplate
 }
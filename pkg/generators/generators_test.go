@@ -1,11 +1,15 @@
 package generators
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/argoproj-labs/applicationset/api/v1alpha1"
 )
@@ -50,10 +54,332 @@ func TestNoGeneratorNilReferenceError(t *testing.T) {
 		t.Run(fmt.Sprintf("%s does not throw a nil reference error when all generator fields are nil", generatorName), func(t *testing.T) {
 			t.Parallel()
 
-			params, err := generator.GenerateParams(&v1alpha1.ApplicationSetGenerator{}, &v1alpha1.ApplicationSet{})
+			params, err := generator.GenerateParams(context.Background(), &v1alpha1.ApplicationSetGenerator{}, &v1alpha1.ApplicationSet{})
 
 			assert.ErrorIs(t, err, EmptyAppSetGeneratorError)
 			assert.Nil(t, params)
 		})
 	}
 }
+
+func TestTransformWithParamDefaults(t *testing.T) {
+	requestedGenerator := v1alpha1.ApplicationSetGenerator{
+		List: &v1alpha1.ListGenerator{
+			Elements: []apiextensionsv1.JSON{
+				{Raw: []byte(`{"cluster": "staging"}`)},
+				{Raw: []byte(`{"cluster": "production", "branch": "release", "namespace": ""}`)},
+			},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		paramDefaults map[string]string
+		expected      []map[string]interface{}
+	}{
+		{
+			name: "no defaults",
+			expected: []map[string]interface{}{
+				{"cluster": "staging", "applicationset.name": "guestbook", "applicationset.namespace": "argocd"},
+				{"cluster": "production", "branch": "release", "namespace": "", "applicationset.name": "guestbook", "applicationset.namespace": "argocd"},
+			},
+		},
+		{
+			name:          "defaults of the missing params only",
+			paramDefaults: map[string]string{"branch": "main", "namespace": "default"},
+			expected: []map[string]interface{}{
+				{"cluster": "staging", "branch": "main", "namespace": "default", "applicationset.name": "guestbook", "applicationset.namespace": "argocd"},
+				{"cluster": "production", "branch": "release", "namespace": "", "applicationset.name": "guestbook", "applicationset.namespace": "argocd"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := &v1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+				Spec:       v1alpha1.ApplicationSetSpec{ParamDefaults: cc.paramDefaults},
+			}
+
+			results, err := Transform(context.Background(), requestedGenerator, map[string]Generator{"List": NewListGenerator()}, v1alpha1.ApplicationSetTemplate{}, appSet)
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, cc.expected, results[0].Params)
+			}
+		})
+	}
+}
+
+func TestTransformWithParamTransforms(t *testing.T) {
+	requestedGenerator := v1alpha1.ApplicationSetGenerator{
+		List: &v1alpha1.ListGenerator{
+			Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "engineering-dev"}`)}},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		paramTransforms []v1alpha1.ParamTransform
+		expected        []map[string]interface{}
+		expectedError   string
+	}{
+		{
+			name: "transforms after the defaults",
+			paramTransforms: []v1alpha1.ParamTransform{
+				{Name: "name", Value: "{{ .cluster }}-{{ .env }}"},
+				{Name: "owner", Value: "{{ .applicationset.name }}"},
+				{Name: "applicationset.name", Remove: true},
+				{Name: "applicationset.namespace", Remove: true},
+			},
+			expected: []map[string]interface{}{
+				{"cluster": "engineering-dev", "env": "dev", "name": "engineering-dev-dev", "owner": "guestbook"},
+			},
+		},
+		{
+			name: "invalid transform",
+			paramTransforms: []v1alpha1.ParamTransform{
+				{Name: "name", Value: "{{ .cluster "},
+			},
+			expectedError: "failed to transform param name: failed to parse template {{ .cluster : template: :1: unclosed action",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := &v1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+				Spec: v1alpha1.ApplicationSetSpec{
+					ParamDefaults:   map[string]string{"env": "dev"},
+					ParamTransforms: cc.paramTransforms,
+				},
+			}
+
+			results, err := Transform(context.Background(), requestedGenerator, map[string]Generator{"List": NewListGenerator()}, v1alpha1.ApplicationSetTemplate{}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				assert.Empty(t, results)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, cc.expected, results[0].Params)
+			}
+		})
+	}
+}
+
+func TestMergeGeneratorTemplate(t *testing.T) {
+	template := v1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{
+			Name:        "{{cluster}}-guestbook",
+			Labels:      map[string]string{"team": "platform", "tier": "frontend"},
+			Annotations: map[string]string{"example.com/owner": "platform"},
+			Finalizers:  []string{"resources-finalizer.argocd.argoproj.io"},
+		},
+		Spec: argov1alpha1.ApplicationSpec{Project: "default"},
+	}
+	generatorTemplate := v1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{
+			Labels:     map[string]string{"tier": "", "env": "{{env}}"},
+			Finalizers: []string{"example.com/finalizer"},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		mergePolicy   v1alpha1.TemplateMergePolicy
+		expected      v1alpha1.ApplicationSetTemplateMeta
+		expectedError string
+	}{
+		{
+			name: "merge by default",
+			expected: v1alpha1.ApplicationSetTemplateMeta{
+				Name:        "{{cluster}}-guestbook",
+				Labels:      map[string]string{"team": "platform", "tier": "", "env": "{{env}}"},
+				Annotations: map[string]string{"example.com/owner": "platform"},
+				Finalizers:  []string{"example.com/finalizer"},
+			},
+		},
+		{
+			name:        "override",
+			mergePolicy: v1alpha1.TemplateMergePolicyOverride,
+			expected: v1alpha1.ApplicationSetTemplateMeta{
+				Name:        "{{cluster}}-guestbook",
+				Labels:      map[string]string{"tier": "", "env": "{{env}}"},
+				Annotations: map[string]string{"example.com/owner": "platform"},
+				Finalizers:  []string{"example.com/finalizer"},
+			},
+		},
+		{
+			name:        "deep-merge",
+			mergePolicy: v1alpha1.TemplateMergePolicyDeepMerge,
+			expected: v1alpha1.ApplicationSetTemplateMeta{
+				Name:        "{{cluster}}-guestbook",
+				Labels:      map[string]string{"team": "platform", "tier": "", "env": "{{env}}"},
+				Annotations: map[string]string{"example.com/owner": "platform"},
+				Finalizers:  []string{"resources-finalizer.argocd.argoproj.io", "example.com/finalizer"},
+			},
+		},
+		{
+			name:          "unknown policy",
+			mergePolicy:   "replace",
+			expectedError: "unknown template merge policy: replace",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			requestedGenerator := &v1alpha1.ApplicationSetGenerator{
+				List: &v1alpha1.ListGenerator{Template: *generatorTemplate.DeepCopy()},
+			}
+
+			got, err := mergeGeneratorTemplate(NewListGenerator(), requestedGenerator, *template.DeepCopy(), cc.mergePolicy)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got.ApplicationSetTemplateMeta)
+			assert.Equal(t, "default", got.Spec.Project)
+			// The generator template is not modified
+			assert.Equal(t, generatorTemplate, requestedGenerator.List.Template)
+		})
+	}
+}
+
+func TestApplicationSetParams(t *testing.T) {
+	appSet := &v1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: "argocd",
+			Labels:    map[string]string{"team": "platform"},
+			Annotations: map[string]string{
+				"example.com/owner": "platform-team",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"applicationset.name":                          "guestbook",
+		"applicationset.namespace":                     "argocd",
+		"applicationset.labels.team":                   "platform",
+		"applicationset.annotations.example.com/owner": "platform-team",
+	}, applicationSetParams(appSet))
+
+	// Generated params take precedence
+	params := addMissingParams([]map[string]interface{}{{"applicationset.name": "other"}}, applicationSetParams(appSet))
+	assert.Equal(t, "other", params[0]["applicationset.name"])
+	assert.Equal(t, "argocd", params[0]["applicationset.namespace"])
+}
+
+func TestTransformWithNamedTemplates(t *testing.T) {
+	requestedGenerator := v1alpha1.ApplicationSetGenerator{
+		List: &v1alpha1.ListGenerator{
+			Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "engineering-dev"}`)}},
+			Template: v1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{Labels: map[string]string{"env": "dev"}},
+			},
+		},
+	}
+	appSet := &v1alpha1.ApplicationSet{
+		Spec: v1alpha1.ApplicationSetSpec{
+			Templates: map[string]v1alpha1.ApplicationSetTemplate{
+				"frontend": {
+					ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{
+						Name:   "{{cluster}}-frontend",
+						Labels: map[string]string{"tier": "frontend"},
+					},
+					Spec: argov1alpha1.ApplicationSpec{Project: "frontend"},
+				},
+			},
+		},
+	}
+
+	results, err := Transform(context.Background(), requestedGenerator, map[string]Generator{"List": NewListGenerator()}, v1alpha1.ApplicationSetTemplate{}, appSet)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, map[string]v1alpha1.ApplicationSetTemplate{
+			"frontend": {
+				ApplicationSetTemplateMeta: v1alpha1.ApplicationSetTemplateMeta{
+					Name:   "{{cluster}}-frontend",
+					Labels: map[string]string{"tier": "frontend", "env": "dev"},
+				},
+				Spec: argov1alpha1.ApplicationSpec{Project: "frontend"},
+			},
+		}, results[0].NamedTemplates)
+	}
+}
+
+func TestTransformWithParamReferences(t *testing.T) {
+	requestedGenerator := v1alpha1.ApplicationSetGenerator{
+		List: &v1alpha1.ListGenerator{
+			Elements: []apiextensionsv1.JSON{
+				{Raw: []byte(`{"name": "guestbook"}`)},
+				{Raw: []byte(`{"name": "api", "url": "https://{{name}}.internal"}`)},
+			},
+		},
+	}
+
+	cases := []struct {
+		name                   string
+		resolveParamReferences bool
+		paramDefaults          map[string]string
+		expected               []map[string]interface{}
+		expectedError          string
+	}{
+		{
+			name:          "references are not resolved by default",
+			paramDefaults: map[string]string{"url": "https://{{name}}.{{domain}}", "domain": "example.com"},
+			expected: []map[string]interface{}{
+				{"name": "guestbook", "url": "https://{{name}}.{{domain}}", "domain": "example.com"},
+				{"name": "api", "url": "https://{{name}}.internal", "domain": "example.com"},
+			},
+		},
+		{
+			name:                   "references of the params and the defaults",
+			resolveParamReferences: true,
+			paramDefaults:          map[string]string{"url": "https://{{name}}.{{domain}}", "domain": "example.com"},
+			expected: []map[string]interface{}{
+				{"name": "guestbook", "url": "https://guestbook.example.com", "domain": "example.com"},
+				{"name": "api", "url": "https://api.internal", "domain": "example.com"},
+			},
+		},
+		{
+			name:                   "cyclic references",
+			resolveParamReferences: true,
+			paramDefaults:          map[string]string{"url": "https://{{domain}}", "domain": "{{url}}"},
+			expectedError:          "cyclic param references: ",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := &v1alpha1.ApplicationSet{
+				Spec: v1alpha1.ApplicationSetSpec{
+					ParamDefaults:          cc.paramDefaults,
+					ResolveParamReferences: cc.resolveParamReferences,
+					ParamTransforms: []v1alpha1.ParamTransform{
+						{Name: "applicationset.name", Remove: true},
+						{Name: "applicationset.namespace", Remove: true},
+					},
+				},
+			}
+
+			results, err := Transform(context.Background(), requestedGenerator, map[string]Generator{"List": NewListGenerator()}, v1alpha1.ApplicationSetTemplate{}, appSet)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, cc.expected, results[0].Params)
+			}
+		})
+	}
+}
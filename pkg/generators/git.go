@@ -44,7 +44,7 @@ func (g *GitGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.Appli
 	return DefaultRequeueAfterSeconds
 }
 
-func (g *GitGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *GitGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
@@ -55,11 +55,11 @@ func (g *GitGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Applic
 	}
 
 	var err error
-	var res []map[string]string
+	var res []map[string]interface{}
 	if appSetGenerator.Git.Directories != nil {
-		res, err = g.generateParamsForGitDirectories(appSetGenerator)
+		res, err = g.generateParamsForGitDirectories(ctx, appSetGenerator)
 	} else if appSetGenerator.Git.Files != nil {
-		res, err = g.generateParamsForGitFiles(appSetGenerator)
+		res, err = g.generateParamsForGitFiles(ctx, appSetGenerator, useGoTemplate(applicationSetInfo))
 	} else {
 		return nil, EmptyAppSetGeneratorError
 	}
@@ -70,10 +70,10 @@ func (g *GitGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Applic
 	return res, nil
 }
 
-func (g *GitGenerator) generateParamsForGitDirectories(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) ([]map[string]string, error) {
+func (g *GitGenerator) generateParamsForGitDirectories(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) ([]map[string]interface{}, error) {
 
 	// Directories, not files
-	allPaths, err := g.repos.GetDirectories(context.TODO(), appSetGenerator.Git.RepoURL, appSetGenerator.Git.Revision)
+	allPaths, err := g.repos.GetDirectories(ctx, appSetGenerator.Git.RepoURL, appSetGenerator.Git.Revision)
 	if err != nil {
 		return nil, err
 	}
@@ -92,12 +92,12 @@ func (g *GitGenerator) generateParamsForGitDirectories(appSetGenerator *argoproj
 	return res, nil
 }
 
-func (g *GitGenerator) generateParamsForGitFiles(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) ([]map[string]string, error) {
+func (g *GitGenerator) generateParamsForGitFiles(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, useGoTemplate bool) ([]map[string]interface{}, error) {
 
 	// Get all files that match the requested path string, removing duplicates
 	allFiles := make(map[string][]byte)
 	for _, requestedPath := range appSetGenerator.Git.Files {
-		files, err := g.repos.GetFiles(context.TODO(), appSetGenerator.Git.RepoURL, appSetGenerator.Git.Revision, requestedPath.Path)
+		files, err := g.repos.GetFiles(ctx, appSetGenerator.Git.RepoURL, appSetGenerator.Git.Revision, requestedPath.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -115,11 +115,11 @@ func (g *GitGenerator) generateParamsForGitFiles(appSetGenerator *argoprojiov1al
 	sort.Strings(allPaths)
 
 	// Generate params from each path, and return
-	res := []map[string]string{}
+	res := []map[string]interface{}{}
 	for _, path := range allPaths {
 
 		// A JSON / YAML file path can contain multiple sets of parameters (ie it is an array)
-		paramsArray, err := g.generateParamsFromGitFile(path, allFiles[path])
+		paramsArray, err := g.generateParamsFromGitFile(path, allFiles[path], useGoTemplate)
 		if err != nil {
 			return nil, fmt.Errorf("unable to process file '%s': %v", path, err)
 		}
@@ -131,7 +131,7 @@ func (g *GitGenerator) generateParamsForGitFiles(appSetGenerator *argoprojiov1al
 	return res, nil
 }
 
-func (g *GitGenerator) generateParamsFromGitFile(filePath string, fileContent []byte) ([]map[string]string, error) {
+func (g *GitGenerator) generateParamsFromGitFile(filePath string, fileContent []byte, useGoTemplate bool) ([]map[string]interface{}, error) {
 	objectsFound := []map[string]interface{}{}
 
 	// First, we attempt to parse as an array
@@ -146,27 +146,16 @@ func (g *GitGenerator) generateParamsFromGitFile(filePath string, fileContent []
 		objectsFound = append(objectsFound, singleObj)
 	}
 
-	res := []map[string]string{}
+	res := []map[string]interface{}{}
 
 	// Flatten all objects found, and return them
 	for _, objectFound := range objectsFound {
 
-		flat, err := flatten.Flatten(objectFound, "", flatten.DotStyle)
+		params, err := fileObjectToParams(objectFound, useGoTemplate)
 		if err != nil {
 			return nil, err
 		}
-		params := map[string]string{}
-		for k, v := range flat {
-			params[k] = fmt.Sprintf("%v", v)
-		}
-		params["path"] = path.Dir(filePath)
-		params["path.basename"] = path.Base(params["path"])
-		params["path.basenameNormalized"] = sanitizeName(path.Base(params["path"]))
-		for k, v := range strings.Split(strings.TrimSuffix(params["path"], params["path.basename"]), "/") {
-			if len(v) > 0 {
-				params["path["+strconv.Itoa(k)+"]"] = v
-			}
-		}
+		addPathParams(params, path.Dir(filePath))
 		res = append(res, params)
 	}
 
@@ -202,23 +191,51 @@ func (g *GitGenerator) filterApps(Directories []argoprojiov1alpha1.GitDirectoryG
 	return res
 }
 
-func (g *GitGenerator) generateParamsFromApps(requestedApps []string, _ *argoprojiov1alpha1.ApplicationSetGenerator) []map[string]string {
+func (g *GitGenerator) generateParamsFromApps(requestedApps []string, _ *argoprojiov1alpha1.ApplicationSetGenerator) []map[string]interface{} {
 	// TODO: At some point, the appicationSetGenerator param should be used
 
-	res := make([]map[string]string, len(requestedApps))
+	res := make([]map[string]interface{}, len(requestedApps))
 	for i, a := range requestedApps {
 
-		params := make(map[string]string, 2)
-		params["path"] = a
-		params["path.basename"] = path.Base(a)
-		params["path.basenameNormalized"] = sanitizeName(path.Base(a))
-		for k, v := range strings.Split(strings.TrimSuffix(params["path"], params["path.basename"]), "/") {
-			if len(v) > 0 {
-				params["path["+strconv.Itoa(k)+"]"] = v
-			}
-		}
+		params := make(map[string]interface{}, 2)
+		addPathParams(params, a)
 		res[i] = params
 	}
 
 	return res
 }
+
+// fileObjectToParams converts an object read from a JSON or YAML file to a parameter set. With Go templates, the
+// object is kept as-is, so templates can use nested lists and objects. Otherwise, the object is flattened, with the
+// dot-separated path of each value as parameter name.
+func fileObjectToParams(object map[string]interface{}, useGoTemplate bool) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if useGoTemplate {
+		for k, v := range object {
+			params[k] = v
+		}
+		return params, nil
+	}
+
+	flat, err := flatten.Flatten(object, "", flatten.DotStyle)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range flat {
+		params[k] = fmt.Sprintf("%v", v)
+	}
+	return params, nil
+}
+
+// addPathParams adds the parameters of the path of a directory: the path, its basename, and each of its segments.
+func addPathParams(params map[string]interface{}, dir string) {
+	basename := path.Base(dir)
+	params["path"] = dir
+	params["path.basename"] = basename
+	params["path.basenameNormalized"] = sanitizeName(basename)
+	for k, v := range strings.Split(strings.TrimSuffix(dir, basename), "/") {
+		if len(v) > 0 {
+			params["path["+strconv.Itoa(k)+"]"] = v
+		}
+	}
+}
@@ -53,7 +53,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 		directories   []argoprojiov1alpha1.GitDirectoryGeneratorItem
 		repoApps      []string
 		repoError     error
-		expected      []map[string]string
+		expected      []map[string]interface{}
 		expectedError error
 	}{
 		{
@@ -66,7 +66,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 				"p1/app4",
 			},
 			repoError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"path": "app1", "path.basename": "app1", "path.basenameNormalized": "app1"},
 				{"path": "app2", "path.basename": "app2", "path.basenameNormalized": "app2"},
 				{"path": "app_3", "path.basename": "app_3", "path.basenameNormalized": "app-3"},
@@ -83,7 +83,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 				"p1/p2/p3/app4",
 			},
 			repoError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"path": "p1/app2", "path.basename": "app2", "path[0]": "p1", "path.basenameNormalized": "app2"},
 				{"path": "p1/p2/app3", "path.basename": "app3", "path[0]": "p1", "path[1]": "p2", "path.basenameNormalized": "app3"},
 			},
@@ -100,7 +100,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 				"p2/app3",
 			},
 			repoError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"path": "app1", "path.basename": "app1", "path.basenameNormalized": "app1"},
 				{"path": "app2", "path.basename": "app2", "path.basenameNormalized": "app2"},
 				{"path": "p2/app3", "path.basename": "app3", "path[0]": "p2", "path.basenameNormalized": "app3"},
@@ -118,7 +118,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 				"p2/app3",
 			},
 			repoError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"path": "app1", "path.basename": "app1", "path.basenameNormalized": "app1"},
 				{"path": "app2", "path.basename": "app2", "path.basenameNormalized": "app2"},
 				{"path": "p2/app3", "path.basename": "app3", "path[0]": "p2", "path.basenameNormalized": "app3"},
@@ -130,7 +130,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 			directories:   []argoprojiov1alpha1.GitDirectoryGeneratorItem{{Path: "*"}},
 			repoApps:      []string{},
 			repoError:     nil,
-			expected:      []map[string]string{},
+			expected:      []map[string]interface{}{},
 			expectedError: nil,
 		},
 		{
@@ -138,7 +138,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 			directories:   []argoprojiov1alpha1.GitDirectoryGeneratorItem{{Path: "*"}},
 			repoApps:      []string{},
 			repoError:     fmt.Errorf("error"),
-			expected:      []map[string]string{},
+			expected:      []map[string]interface{}{},
 			expectedError: fmt.Errorf("error"),
 		},
 	}
@@ -169,7 +169,7 @@ func TestGitGenerateParamsFromDirectories(t *testing.T) {
 				},
 			}
 
-			got, err := gitGenerator.GenerateParams(&applicationSetInfo.Spec.Generators[0], nil)
+			got, err := gitGenerator.GenerateParams(context.Background(), &applicationSetInfo.Spec.Generators[0], nil)
 
 			if testCaseCopy.expectedError != nil {
 				assert.EqualError(t, err, testCaseCopy.expectedError.Error())
@@ -194,7 +194,7 @@ func TestGitGenerateParamsFromFiles(t *testing.T) {
 		repoFileContents map[string][]byte
 		// if repoPathsError is non-nil, the call to GetPaths(...) will return this error value
 		repoPathsError error
-		expected       []map[string]string
+		expected       []map[string]interface{}
 		expectedError  error
 	}{
 		{
@@ -225,7 +225,7 @@ func TestGitGenerateParamsFromFiles(t *testing.T) {
 }`),
 			},
 			repoPathsError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{
 					"cluster.owner":           "john.doe@example.com",
 					"cluster.name":            "production",
@@ -256,7 +256,7 @@ func TestGitGenerateParamsFromFiles(t *testing.T) {
 			files:            []argoprojiov1alpha1.GitFileGeneratorItem{{Path: "**/config.json"}},
 			repoFileContents: map[string][]byte{},
 			repoPathsError:   fmt.Errorf("paths error"),
-			expected:         []map[string]string{},
+			expected:         []map[string]interface{}{},
 			expectedError:    fmt.Errorf("paths error"),
 		},
 		{
@@ -266,7 +266,7 @@ func TestGitGenerateParamsFromFiles(t *testing.T) {
 				"cluster-config/production/config.json": []byte(`invalid json file`),
 			},
 			repoPathsError: nil,
-			expected:       []map[string]string{},
+			expected:       []map[string]interface{}{},
 			expectedError:  fmt.Errorf("unable to process file 'cluster-config/production/config.json': unable to parse file: error unmarshaling JSON: while decoding JSON: json: cannot unmarshal string into Go value of type map[string]interface {}"),
 		},
 		{
@@ -295,7 +295,7 @@ func TestGitGenerateParamsFromFiles(t *testing.T) {
 ]`),
 			},
 			repoPathsError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{
 					"cluster.owner":           "john.doe@example.com",
 					"cluster.name":            "production",
@@ -341,7 +341,7 @@ cluster:
 `),
 			},
 			repoPathsError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{
 					"cluster.owner":           "john.doe@example.com",
 					"cluster.name":            "production",
@@ -383,7 +383,7 @@ cluster:
     address: https://kubernetes.default.svc`),
 			},
 			repoPathsError: nil,
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{
 					"cluster.owner":           "john.doe@example.com",
 					"cluster.name":            "production",
@@ -434,7 +434,7 @@ cluster:
 				},
 			}
 
-			got, err := gitGenerator.GenerateParams(&applicationSetInfo.Spec.Generators[0], nil)
+			got, err := gitGenerator.GenerateParams(context.Background(), &applicationSetInfo.Spec.Generators[0], nil)
 			fmt.Println(got, err)
 
 			if testCaseCopy.expectedError != nil {
@@ -449,3 +449,53 @@ cluster:
 	}
 
 }
+
+func TestGitGenerateParamsFromFilesGoTemplate(t *testing.T) {
+	argoCDServiceMock := argoCDServiceMock{mock: &mock.Mock{}}
+	argoCDServiceMock.mock.On("GetFiles", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(map[string][]byte{
+			"cluster-config/production/config.json": []byte(`{
+   "cluster": {
+       "name": "production",
+       "address": "https://kubernetes.default.svc"
+   },
+   "regions": ["eu", "us"],
+   "replicas": 3
+}`),
+		}, nil)
+
+	var gitGenerator = NewGitGenerator(argoCDServiceMock)
+	applicationSetInfo := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "set",
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			GoTemplate: true,
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{{
+				Git: &argoprojiov1alpha1.GitGenerator{
+					RepoURL:  "RepoURL",
+					Revision: "Revision",
+					Files:    []argoprojiov1alpha1.GitFileGeneratorItem{{Path: "**/config.json"}},
+				},
+			}},
+		},
+	}
+
+	got, err := gitGenerator.GenerateParams(context.Background(), &applicationSetInfo.Spec.Generators[0], &applicationSetInfo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"cluster": map[string]interface{}{
+				"name":    "production",
+				"address": "https://kubernetes.default.svc",
+			},
+			"regions":                 []interface{}{"eu", "us"},
+			"replicas":                float64(3),
+			"path":                    "cluster-config/production",
+			"path.basename":           "production",
+			"path[0]":                 "cluster-config",
+			"path.basenameNormalized": "production",
+		},
+	}, got)
+}
@@ -0,0 +1,137 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/helm_repository"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*HelmRepositoryGenerator)(nil)
+
+const (
+	DefaultHelmRepositoryRequeueAfterSeconds = 30 * time.Minute
+)
+
+// HelmRepositoryGenerator generates parameters from the chart versions of a Helm repository.
+type HelmRepositoryGenerator struct {
+	client client.Client
+}
+
+func NewHelmRepositoryGenerator(client client.Client) Generator {
+	g := &HelmRepositoryGenerator{
+		client: client,
+	}
+	return g
+}
+
+func (g *HelmRepositoryGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.HelmRepository.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.HelmRepository.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultHelmRepositoryRequeueAfterSeconds
+}
+
+func (g *HelmRepositoryGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.HelmRepository.Template
+}
+
+func (g *HelmRepositoryGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.HelmRepository == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.HelmRepository
+
+	constraint := generatorConfig.Version
+	if constraint == "" {
+		constraint = "*"
+	}
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint %q: %v", constraint, err)
+	}
+
+	password, err := utils.GetSecretRef(ctx, g.client, generatorConfig.PasswordRef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret password: %v", err)
+	}
+	var service helm_repository.ChartVersionService
+	if strings.HasPrefix(generatorConfig.RepoURL, "oci://") {
+		service, err = helm_repository.NewOCIService(generatorConfig.RepoURL, generatorConfig.Username, password)
+	} else {
+		service, err = helm_repository.NewIndexService(generatorConfig.RepoURL, generatorConfig.Username, password)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	chartVersions, err := service.List(ctx, generatorConfig.Charts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []map[string]interface{}{}
+	for _, chartVersion := range selectChartVersions(chartVersions, constraints, generatorConfig.AllVersions) {
+		params := map[string]interface{}{
+			"name":        chartVersion.Name,
+			"version":     chartVersion.Version,
+			"app_version": chartVersion.AppVersion,
+			"repo_url":    generatorConfig.RepoURL,
+		}
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+		res = append(res, params)
+	}
+
+	return res, nil
+}
+
+// selectChartVersions returns the versions matching the constraints, sorted by chart name and then by descending
+// version. Unless all is true, only the latest version of each chart is returned. Versions which aren't semantic
+// versions are ignored.
+func selectChartVersions(chartVersions []*helm_repository.ChartVersion, constraints *semver.Constraints, all bool) []*helm_repository.ChartVersion {
+	type matchingVersion struct {
+		chartVersion *helm_repository.ChartVersion
+		version      *semver.Version
+	}
+	matching := []matchingVersion{}
+	for _, chartVersion := range chartVersions {
+		version, err := semver.NewVersion(chartVersion.Version)
+		if err != nil || !constraints.Check(version) {
+			continue
+		}
+		matching = append(matching, matchingVersion{chartVersion: chartVersion, version: version})
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if matching[i].chartVersion.Name != matching[j].chartVersion.Name {
+			return matching[i].chartVersion.Name < matching[j].chartVersion.Name
+		}
+		return matching[i].version.GreaterThan(matching[j].version)
+	})
+
+	selected := []*helm_repository.ChartVersion{}
+	for i, m := range matching {
+		if !all && i > 0 && matching[i-1].chartVersion.Name == m.chartVersion.Name {
+			continue
+		}
+		selected = append(selected, m.chartVersion)
+	}
+	return selected
+}
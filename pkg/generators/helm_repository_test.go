@@ -0,0 +1,142 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestHelmRepositoryGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "my-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `apiVersion: v1
+entries:
+  guestbook:
+  - version: 1.2.1
+    appVersion: "2.1"
+  - version: 1.3.0-rc.1
+    appVersion: "2.2"
+  - version: 1.1.9
+    appVersion: "2.0"
+  - version: 1.2.0
+    appVersion: "2.0"
+  - version: not-semver
+  redis:
+  - version: 0.3.0
+    appVersion: "6.2"
+`)
+	}))
+	defer ts.Close()
+
+	passwordRef := &argoprojiov1alpha1.SecretRef{SecretName: "helm-repository", Key: "password"}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.HelmRepositoryGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "latest version of every chart",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL:     ts.URL,
+				Username:    "user",
+				PasswordRef: passwordRef,
+				Values:      map[string]string{"team": "platform"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "guestbook", "version": "1.2.1", "app_version": "2.1", "repo_url": ts.URL, "values.team": "platform"},
+				{"name": "redis", "version": "0.3.0", "app_version": "6.2", "repo_url": ts.URL, "values.team": "platform"},
+			},
+		},
+		{
+			name: "all versions matching a constraint",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL:     ts.URL,
+				Charts:      []string{"guestbook"},
+				Version:     "~1.2",
+				AllVersions: true,
+				Username:    "user",
+				PasswordRef: passwordRef,
+			},
+			expected: []map[string]interface{}{
+				{"name": "guestbook", "version": "1.2.1", "app_version": "2.1", "repo_url": ts.URL},
+				{"name": "guestbook", "version": "1.2.0", "app_version": "2.0", "repo_url": ts.URL},
+			},
+		},
+		{
+			name: "pre-release constraint",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL:     ts.URL,
+				Charts:      []string{"guestbook"},
+				Version:     ">=1.3.0-0",
+				Username:    "user",
+				PasswordRef: passwordRef,
+			},
+			expected: []map[string]interface{}{
+				{"name": "guestbook", "version": "1.3.0-rc.1", "app_version": "2.2", "repo_url": ts.URL},
+			},
+		},
+		{
+			name: "no matching version",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL:     ts.URL,
+				Version:     ">=2.0.0",
+				Username:    "user",
+				PasswordRef: passwordRef,
+			},
+			expected: []map[string]interface{}{},
+		},
+		{
+			name: "invalid constraint",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL: ts.URL,
+				Version: "latest",
+			},
+			expectedError: "invalid version constraint \"latest\": improper constraint: latest",
+		},
+		{
+			name: "missing credentials",
+			generator: &argoprojiov1alpha1.HelmRepositoryGenerator{
+				RepoURL: ts.URL,
+			},
+			expectedError: fmt.Sprintf("error fetching index of helm repository %s: unexpected status 401 Unauthorized", ts.URL),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "helm-repository", Namespace: "argocd"},
+		Data:       map[string][]byte{"password": []byte("my-password")},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			gen := NewHelmRepositoryGenerator(fakeClient)
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{HelmRepository: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
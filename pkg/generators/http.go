@@ -0,0 +1,223 @@
+package generators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*HTTPGenerator)(nil)
+
+const (
+	DefaultHTTPRequeueAfterSeconds = 30 * time.Minute
+	// DefaultHTTPRequestTimeout is the timeout of the requests sent by the HTTP generator.
+	DefaultHTTPRequestTimeout = 30 * time.Second
+	// maxCachedHTTPParams is the maximum number of the param sets kept for the responses which are not modified.
+	maxCachedHTTPParams = 100000
+)
+
+// HTTPGenerator generates parameters from the array of objects returned by a JSON HTTP endpoint.
+type HTTPGenerator struct {
+	client client.Client
+	// params are the last param sets of the generators, by generator, so that they are not computed again when the
+	// endpoint answers that its response is not modified.
+	params *utils.LRUCache
+}
+
+// httpGeneratorParams are the param sets of a generator, and the validators of the response they were computed from.
+type httpGeneratorParams struct {
+	validators string
+	params     []map[string]interface{}
+}
+
+func NewHTTPGenerator(client client.Client) Generator {
+	g := &HTTPGenerator{
+		client: client,
+		params: utils.NewLRUCache(maxCachedHTTPParams),
+	}
+	return g
+}
+
+func (g *HTTPGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.HTTP.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.HTTP.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultHTTPRequeueAfterSeconds
+}
+
+func (g *HTTPGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.HTTP.Template
+}
+
+func (g *HTTPGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.HTTP == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.HTTP
+
+	token, err := utils.GetSecretRef(ctx, g.client, generatorConfig.TokenRef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret token: %v", err)
+	}
+
+	caData, err := utils.GetSecretRef(ctx, g.client, generatorConfig.CARef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret CA certificates: %v", err)
+	}
+
+	httpClient, err := utils.NewHTTPClient(utils.HTTPClientOptions{ProxyURL: generatorConfig.Proxy, Insecure: generatorConfig.Insecure, CAData: caData})
+	if err != nil {
+		return nil, fmt.Errorf("error creating the HTTP client: %v", err)
+	}
+
+	body, validators, notModified, err := g.get(ctx, httpClient, generatorConfig.URL, generatorConfig.Headers, token)
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s: %v", generatorConfig.URL, err)
+	}
+
+	// The param sets of a response which is not modified are the last param sets of the generator
+	paramsKey, err := httpGeneratorParamsKey(applicationSetInfo, generatorConfig)
+	if err != nil {
+		return nil, err
+	}
+	if notModified && g.params != nil {
+		if value, ok := g.params.Get(paramsKey); ok && value.(*httpGeneratorParams).validators == validators {
+			return copyParamSets(value.(*httpGeneratorParams).params), nil
+		}
+	}
+
+	objects, err := selectJSONObjects(body, generatorConfig.JSONPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response of %s: %v", generatorConfig.URL, err)
+	}
+
+	res := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		params, err := jsonObjectToParams(object, useGoTemplate(applicationSetInfo))
+		if err != nil {
+			return nil, fmt.Errorf("error reading response of %s: %v", generatorConfig.URL, err)
+		}
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+		res = append(res, params)
+	}
+
+	if g.params != nil {
+		g.params.Add(paramsKey, &httpGeneratorParams{validators: validators, params: copyParamSets(res)}, int64(len(res)))
+	}
+	return res, nil
+}
+
+// httpGeneratorParamsKey returns the key of the param sets of the generator of the ApplicationSet: the hash of its
+// namespace, of the generator and of its template engine, which the param sets depend on.
+func httpGeneratorParamsKey(applicationSetInfo *argoprojiov1alpha1.ApplicationSet, generatorConfig *argoprojiov1alpha1.HTTPGenerator) (string, error) {
+	generatorJSON, err := json.Marshal(generatorConfig)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling the generator: %v", err)
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%t\n%s", applicationSetInfo.Namespace, useGoTemplate(applicationSetInfo), generatorJSON)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// get sends a conditional GET request to the URL, and returns the body of the response, its validators, and whether
+// the server answered that it is not modified since the last request.
+func (g *HTTPGenerator) get(ctx context.Context, httpClient *http.Client, url string, headers map[string]string, token string) ([]byte, string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// The HTTP clients are shared, so the timeout is set on a copy
+	conditionalClient := utils.NewConditionalClient(httpClient)
+	conditionalClient.Timeout = DefaultHTTPRequestTimeout
+	resp, err := conditionalClient.Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, utils.ResponseValidators(resp), utils.NotModified(resp), nil
+}
+
+// selectJSONObjects decodes the JSON body, and returns the objects selected by the JSONPath expression. If the
+// expression selects an array, its elements are returned. If no expression is given, the body must be an array of
+// objects.
+func selectJSONObjects(body []byte, jsonPathExpr string) ([]map[string]interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("error unmarshalling JSON: %v", err)
+	}
+
+	values := []interface{}{data}
+	if jsonPathExpr != "" {
+		if !strings.HasPrefix(jsonPathExpr, "{") {
+			jsonPathExpr = fmt.Sprintf("{%s}", jsonPathExpr)
+		}
+		jp := jsonpath.New("http")
+		if err := jp.Parse(jsonPathExpr); err != nil {
+			return nil, fmt.Errorf("error parsing jsonPath %s: %v", jsonPathExpr, err)
+		}
+		results, err := jp.FindResults(data)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating jsonPath %s: %v", jsonPathExpr, err)
+		}
+		values = []interface{}{}
+		for _, result := range results {
+			for _, value := range result {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	objects := []map[string]interface{}{}
+	for _, value := range values {
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		for _, item := range items {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected an array of objects, found %T", item)
+			}
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
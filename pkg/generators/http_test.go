@@ -0,0 +1,255 @@
+package generators
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestHTTPGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		if r.Header.Get("Authorization") != "Bearer my-token" || r.Header.Get("X-Team") != "platform" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, "unauthorized")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/environments":
+			_, _ = fmt.Fprint(w, `[{"name":"staging","replicas":1},{"name":"production","replicas":3,"public":true}]`)
+		case "/inventory":
+			_, _ = fmt.Fprint(w, `{"kind":"inventory","items":[{"name":"eu","zones":["a","b"]},{"name":"us","zones":["c"]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "inventory-secret", Namespace: "argocd"},
+		Data:       map[string][]byte{"token": []byte("my-token")},
+	}
+	tokenRef := &argoprojiov1alpha1.SecretRef{SecretName: "inventory-secret", Key: "token"}
+	headers := map[string]string{"X-Team": "platform"}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.HTTPGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "array of objects",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:      ts.URL + "/environments",
+				Headers:  headers,
+				TokenRef: tokenRef,
+				Values:   map[string]string{"cluster": "in-cluster"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "staging", "replicas": "1", "values.cluster": "in-cluster"},
+				{"name": "production", "replicas": "3", "public": "true", "values.cluster": "in-cluster"},
+			},
+		},
+		{
+			name: "array selected by jsonPath",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:      ts.URL + "/inventory",
+				Headers:  headers,
+				TokenRef: tokenRef,
+				JSONPath: ".items",
+			},
+			expected: []map[string]interface{}{
+				{"name": "eu", "zones": `["a","b"]`},
+				{"name": "us", "zones": `["c"]`},
+			},
+		},
+		{
+			name: "objects selected by jsonPath",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:      ts.URL + "/inventory",
+				Headers:  headers,
+				TokenRef: tokenRef,
+				JSONPath: "{.items[?(@.name==\"us\")]}",
+			},
+			expected: []map[string]interface{}{
+				{"name": "us", "zones": `["c"]`},
+			},
+		},
+		{
+			name: "response is not an array of objects",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:      ts.URL + "/inventory",
+				Headers:  headers,
+				TokenRef: tokenRef,
+				JSONPath: ".kind",
+			},
+			expectedError: "expected an array of objects, found string",
+		},
+		{
+			name: "missing token",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:     ts.URL + "/environments",
+				Headers: headers,
+			},
+			expectedError: "unexpected status 401: unauthorized",
+		},
+		{
+			name: "missing secret",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:      ts.URL + "/environments",
+				TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: "missing", Key: "token"},
+			},
+			expectedError: "error fetching secret argocd/missing",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	gen := NewHTTPGenerator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build())
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{HTTP: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
+
+func TestHTTPGenerateParamsTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `[{"name":"staging"}]`)
+	}))
+	defer ts.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "inventory-ca", Namespace: "argocd"},
+		Data:       map[string][]byte{"ca.crt": ca, "invalid": []byte("not a certificate")},
+	}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.HTTPGenerator
+		expectedError string
+	}{
+		{
+			name:          "untrusted certificate",
+			generator:     &argoprojiov1alpha1.HTTPGenerator{URL: ts.URL},
+			expectedError: "certificate",
+		},
+		{
+			name: "certificate of a trusted CA",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:   ts.URL,
+				CARef: &argoprojiov1alpha1.SecretRef{SecretName: "inventory-ca", Key: "ca.crt"},
+			},
+		},
+		{
+			name:      "insecure",
+			generator: &argoprojiov1alpha1.HTTPGenerator{URL: ts.URL, Insecure: true},
+		},
+		{
+			name: "invalid CA",
+			generator: &argoprojiov1alpha1.HTTPGenerator{
+				URL:   ts.URL,
+				CARef: &argoprojiov1alpha1.SecretRef{SecretName: "inventory-ca", Key: "invalid"},
+			},
+			expectedError: "error parsing the CA certificates",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	gen := NewHTTPGenerator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build())
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{HTTP: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []map[string]interface{}{{"name": "staging"}}, got)
+		})
+	}
+}
+
+func TestHTTPGenerateParamsCancelled(t *testing.T) {
+	unblock := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer ts.Close()
+	defer close(unblock)
+
+	gen := NewHTTPGenerator(fake.NewClientBuilder().Build())
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := gen.GenerateParams(ctx, &argoprojiov1alpha1.ApplicationSetGenerator{HTTP: &argoprojiov1alpha1.HTTPGenerator{URL: ts.URL}}, &argoprojiov1alpha1.ApplicationSet{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+}
+
+func TestHTTPGenerateParamsNotModified(t *testing.T) {
+	body := `[{"name":"staging"}]`
+	notModified := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"`+body+`"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"`+body+`"`)
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer ts.Close()
+
+	gen := NewHTTPGenerator(fake.NewClientBuilder().Build())
+	appSetGenerator := &argoprojiov1alpha1.ApplicationSetGenerator{HTTP: &argoprojiov1alpha1.HTTPGenerator{URL: ts.URL, Values: map[string]string{"team": "platform"}}}
+	expected := []map[string]interface{}{{"name": "staging", "values.team": "platform"}}
+
+	got, err := gen.GenerateParams(context.Background(), appSetGenerator, &argoprojiov1alpha1.ApplicationSet{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	got[0]["name"] = "modified by the caller"
+
+	// The last param sets are returned while the response is not modified
+	got, err = gen.GenerateParams(context.Background(), appSetGenerator, &argoprojiov1alpha1.ApplicationSet{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	assert.Equal(t, 1, notModified)
+
+	body = `[{"name":"production"}]`
+	got, err = gen.GenerateParams(context.Background(), appSetGenerator, &argoprojiov1alpha1.ApplicationSet{})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"name": "production", "values.team": "platform"}}, got)
+}
@@ -1,6 +1,7 @@
 package generators
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -12,7 +13,9 @@ type Generator interface {
 	// GenerateParams interprets the ApplicationSet and generates all relevant parameters for the application template.
 	// The expected / desired list of parameters is returned, it then will be render and reconciled
 	// against the current state of the Applications in the cluster.
-	GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error)
+	// The context is cancelled when the reconciliation times out or the controller stops, which must interrupt the
+	// requests of the generator, e.g. to an SCM provider or to the repo server.
+	GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error)
 
 	// GetRequeueAfter is the the generator can controller the next reconciled loop
 	// In case there is more then one generator the time will be the minimum of the times.
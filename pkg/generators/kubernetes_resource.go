@@ -0,0 +1,137 @@
+package generators
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+var _ Generator = (*KubernetesResourceGenerator)(nil)
+
+// KubernetesResourceGenerator generates parameters from the Kubernetes resources of an arbitrary kind.
+type KubernetesResourceGenerator struct {
+	dynClient  dynamic.Interface
+	restMapper meta.RESTMapper
+}
+
+func NewKubernetesResourceGenerator(dynClient dynamic.Interface, restMapper meta.RESTMapper) Generator {
+	g := &KubernetesResourceGenerator{
+		dynClient:  dynClient,
+		restMapper: restMapper,
+	}
+	return g
+}
+
+func (g *KubernetesResourceGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 3 minutes, if no override is specified.
+
+	if appSetGenerator.KubernetesResource.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.KubernetesResource.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultRequeueAfterSeconds
+}
+
+func (g *KubernetesResourceGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.KubernetesResource.Template
+}
+
+func (g *KubernetesResourceGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.KubernetesResource == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.KubernetesResource
+	if generatorConfig.APIVersion == "" || generatorConfig.Kind == "" {
+		return nil, fmt.Errorf("kubernetesResource generator requires an apiVersion and a kind")
+	}
+
+	gv, err := schema.ParseGroupVersion(generatorConfig.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %v", generatorConfig.APIVersion, err)
+	}
+	mapping, err := g.restMapper.RESTMapping(gv.WithKind(generatorConfig.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error finding resource of kind %s %s: %v", generatorConfig.APIVersion, generatorConfig.Kind, err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&generatorConfig.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %v", err)
+	}
+	listOptions := metav1.ListOptions{LabelSelector: selector.String()}
+
+	var resourceClient dynamic.ResourceInterface = g.dynClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace := generatorConfig.Namespace
+		if namespace == "" {
+			namespace = applicationSetInfo.Namespace
+		}
+		resourceClient = g.dynClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	resources, err := resourceClient.List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %v", mapping.Resource.String(), err)
+	}
+
+	res := make([]map[string]interface{}, 0, len(resources.Items))
+	for _, resource := range resources.Items {
+		params := map[string]interface{}{
+			"name":      resource.GetName(),
+			"namespace": resource.GetNamespace(),
+		}
+
+		fieldParams, err := getResourceFieldParams(resource.Object, generatorConfig.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fields of %s %s: %v", generatorConfig.Kind, resource.GetName(), err)
+		}
+		for key, value := range fieldParams {
+			params[key] = value
+		}
+
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+
+		res = append(res, params)
+	}
+
+	return res, nil
+}
+
+// getResourceFieldParams evaluates the given JSONPath expressions against a resource, returning the results as params
+// named after the fields. Expressions may omit the surrounding braces, and fields missing from the resource produce an
+// empty value.
+func getResourceFieldParams(resource map[string]interface{}, fields map[string]string) (map[string]string, error) {
+	params := map[string]string{}
+	for name, expression := range fields {
+		if !strings.HasPrefix(expression, "{") {
+			expression = fmt.Sprintf("{%s}", expression)
+		}
+		jp := jsonpath.New(name).AllowMissingKeys(true)
+		if err := jp.Parse(expression); err != nil {
+			return nil, fmt.Errorf("invalid JSONPath expression %q for field %q: %v", expression, name, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := jp.Execute(buf, resource); err != nil {
+			return nil, fmt.Errorf("error evaluating field %q: %v", name, err)
+		}
+		params[name] = buf.String()
+	}
+	return params, nil
+}
@@ -0,0 +1,132 @@
+package generators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynfake "k8s.io/client-go/dynamic/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestKubernetesResourceGenerateParams(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	newNamespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		}
+	}
+	newConfigMap := func(name, namespace string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       data,
+		}
+	}
+
+	dynClient := dynfake.NewSimpleDynamicClient(scheme,
+		newNamespace("team-x-dev", map[string]string{"team": "x", "env": "dev"}),
+		newNamespace("team-x-prod", map[string]string{"team": "x", "env": "prod"}),
+		newNamespace("team-y", map[string]string{"team": "y"}),
+		newConfigMap("region-eu", "argocd", map[string]string{"region": "eu"}),
+		newConfigMap("region-us", "other", map[string]string{"region": "us"}),
+	)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.KubernetesResourceGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "cluster scoped resources selected by label",
+			generator: &argoprojiov1alpha1.KubernetesResourceGenerator{
+				APIVersion:    "v1",
+				Kind:          "Namespace",
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "x"}},
+				Fields: map[string]string{
+					"env":   ".metadata.labels.env",
+					"owner": "{.metadata.annotations.owner}",
+				},
+				Values: map[string]string{"team": "x"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "team-x-dev", "namespace": "", "env": "dev", "owner": "", "values.team": "x"},
+				{"name": "team-x-prod", "namespace": "", "env": "prod", "owner": "", "values.team": "x"},
+			},
+		},
+		{
+			name: "namespaced resources default to the ApplicationSet namespace",
+			generator: &argoprojiov1alpha1.KubernetesResourceGenerator{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Fields:     map[string]string{"region": ".data.region"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "region-eu", "namespace": "argocd", "region": "eu"},
+			},
+		},
+		{
+			name: "namespaced resources in another namespace",
+			generator: &argoprojiov1alpha1.KubernetesResourceGenerator{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "other",
+			},
+			expected: []map[string]interface{}{
+				{"name": "region-us", "namespace": "other"},
+			},
+		},
+		{
+			name: "unknown kind",
+			generator: &argoprojiov1alpha1.KubernetesResourceGenerator{
+				APIVersion: "mallard.io/v1",
+				Kind:       "Duck",
+			},
+			expectedError: "error finding resource of kind mallard.io/v1 Duck",
+		},
+		{
+			name: "invalid field expression",
+			generator: &argoprojiov1alpha1.KubernetesResourceGenerator{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Fields:     map[string]string{"region": ".data[region"},
+			},
+			expectedError: "error reading fields of ConfigMap region-eu: invalid JSONPath expression",
+		},
+		{
+			name:          "missing kind",
+			generator:     &argoprojiov1alpha1.KubernetesResourceGenerator{APIVersion: "v1"},
+			expectedError: "kubernetesResource generator requires an apiVersion and a kind",
+		},
+	}
+
+	gen := NewKubernetesResourceGenerator(dynClient, restMapper)
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{KubernetesResource: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, cc.expected, got)
+		})
+	}
+}
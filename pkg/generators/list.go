@@ -1,6 +1,7 @@
 package generators
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -19,6 +20,12 @@ func NewListGenerator() Generator {
 }
 
 func (g *ListGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Don't requeue by default, if no override is specified.
+
+	if appSetGenerator.List.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.List.RequeueAfterSeconds) * time.Second
+	}
+
 	return NoRequeueAfter
 }
 
@@ -26,7 +33,7 @@ func (g *ListGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.Applicat
 	return &appSetGenerator.List.Template
 }
 
-func (g *ListGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, _ *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *ListGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
 	}
@@ -35,10 +42,12 @@ func (g *ListGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Appli
 		return nil, EmptyAppSetGeneratorError
 	}
 
-	res := make([]map[string]string, len(appSetGenerator.List.Elements))
+	res := make([]map[string]interface{}, len(appSetGenerator.List.Elements))
+	// With Go templates, elements may contain lists and objects, which are passed as-is
+	structured := useGoTemplate(applicationSetInfo)
 
 	for i, tmpItem := range appSetGenerator.List.Elements {
-		params := map[string]string{}
+		params := map[string]interface{}{}
 		var element map[string]interface{}
 		err := json.Unmarshal(tmpItem.Raw, &element)
 		if err != nil {
@@ -52,18 +61,16 @@ func (g *ListGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Appli
 					return nil, fmt.Errorf("error parsing values map")
 				}
 				for k, v := range values {
-					value, ok := v.(string)
-					if !ok {
+					if _, ok := v.(string); !ok && !structured {
 						return nil, fmt.Errorf("error parsing value as string %v", err)
 					}
-					params[fmt.Sprintf("values.%s", k)] = value
+					params[fmt.Sprintf("values.%s", k)] = v
 				}
 			} else {
-				v, ok := value.(string)
-				if !ok {
+				if _, ok := value.(string); !ok && !structured {
 					return nil, fmt.Errorf("error parsing value as string %v", err)
 				}
-				params[key] = v
+				params[key] = value
 			}
 		}
 
@@ -1,7 +1,9 @@
 package generators
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
@@ -10,15 +12,24 @@ import (
 
 func TestGenerateListParams(t *testing.T) {
 	testCases := []struct {
-		elements []apiextensionsv1.JSON
-		expected []map[string]string
+		elements    []apiextensionsv1.JSON
+		goTemplate  bool
+		expected    []map[string]interface{}
+		expectedErr bool
 	}{
 		{
 			elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "cluster","url": "url"}`)}},
-			expected: []map[string]string{{"cluster": "cluster", "url": "url"}},
+			expected: []map[string]interface{}{{"cluster": "cluster", "url": "url"}},
 		}, {
 			elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "cluster","url": "url","values":{"foo":"bar"}}`)}},
-			expected: []map[string]string{{"cluster": "cluster", "url": "url", "values.foo": "bar"}},
+			expected: []map[string]interface{}{{"cluster": "cluster", "url": "url", "values.foo": "bar"}},
+		}, {
+			elements:    []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "cluster","regions": ["eu", "us"]}`)}},
+			expectedErr: true,
+		}, {
+			elements:   []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "cluster","regions": ["eu", "us"],"values":{"replicas":2}}`)}},
+			goTemplate: true,
+			expected:   []map[string]interface{}{{"cluster": "cluster", "regions": []interface{}{"eu", "us"}, "values.replicas": float64(2)}},
 		},
 	}
 
@@ -26,13 +37,32 @@ func TestGenerateListParams(t *testing.T) {
 
 		var listGenerator = NewListGenerator()
 
-		got, err := listGenerator.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+		got, err := listGenerator.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
 			List: &argoprojiov1alpha1.ListGenerator{
 				Elements: testCase.elements,
-			}}, nil)
+			}}, &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{GoTemplate: testCase.goTemplate}})
 
+		if testCase.expectedErr {
+			assert.Error(t, err)
+			continue
+		}
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, testCase.expected, got)
 
 	}
 }
+
+func TestListGetRequeueAfter(t *testing.T) {
+	listGenerator := NewListGenerator()
+
+	got := listGenerator.GetRequeueAfter(&argoprojiov1alpha1.ApplicationSetGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{},
+	})
+	assert.Equal(t, NoRequeueAfter, got)
+
+	requeueAfterSeconds := int64(30)
+	got = listGenerator.GetRequeueAfter(&argoprojiov1alpha1.ApplicationSetGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{RequeueAfterSeconds: &requeueAfterSeconds},
+	})
+	assert.Equal(t, 30*time.Second, got)
+}
@@ -1,6 +1,7 @@
 package generators
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -27,7 +28,7 @@ func NewMatrixGenerator(supportedGenerators map[string]Generator) Generator {
 	return m
 }
 
-func (m *MatrixGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (m *MatrixGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 
 	if appSetGenerator.Matrix == nil {
 		return nil, EmptyAppSetGeneratorError
@@ -41,19 +42,22 @@ func (m *MatrixGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.App
 		return nil, MoreThanTwoGenerators
 	}
 
-	res := []map[string]string{}
+	res := []map[string]interface{}{}
 
-	g0, err := m.getParams(appSetGenerator.Matrix.Generators[0], appSet)
+	paramSets, err := getChildParams(appSetGenerator.Matrix.Generators, func(generator argoprojiov1alpha1.ApplicationSetNestedGenerator) ([]map[string]interface{}, error) {
+		return m.getParams(ctx, generator, appSet)
+	})
 	if err != nil {
 		return nil, err
 	}
-	g1, err := m.getParams(appSetGenerator.Matrix.Generators[1], appSet)
-	if err != nil {
-		return nil, err
+
+	// The param sets are combined only if they fit the maximum, since their product may not fit in memory
+	if max := MaxParamSets(ctx); max > 0 && len(paramSets[0])*len(paramSets[1]) > max {
+		return nil, fmt.Errorf("%w: the Matrix generator would combine %d and %d param sets into %d param sets, more than the maximum of %d", ErrParamSetsOverflow, len(paramSets[0]), len(paramSets[1]), len(paramSets[0])*len(paramSets[1]), max)
 	}
 
-	for _, a := range g0 {
-		for _, b := range g1 {
+	for _, a := range paramSets[0] {
+		for _, b := range paramSets[1] {
 			val, err := utils.CombineStringMaps(a, b)
 			if err != nil {
 				return nil, err
@@ -65,34 +69,16 @@ func (m *MatrixGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.App
 	return res, nil
 }
 
-func (m *MatrixGenerator) getParams(appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
-	var matrix *argoprojiov1alpha1.MatrixGenerator
-	if appSetBaseGenerator.Matrix != nil {
-		matrix = appSetBaseGenerator.Matrix.ToMatrixGenerator()
-	}
-
-	var mergeGenerator *argoprojiov1alpha1.MergeGenerator
-	if appSetBaseGenerator.Merge != nil {
-		mergeGenerator = appSetBaseGenerator.Merge.ToMergeGenerator()
-	}
-
-	t, err := Transform(
-		argoprojiov1alpha1.ApplicationSetGenerator{
-			List:                    appSetBaseGenerator.List,
-			Clusters:                appSetBaseGenerator.Clusters,
-			Git:                     appSetBaseGenerator.Git,
-			SCMProvider:             appSetBaseGenerator.SCMProvider,
-			ClusterDecisionResource: appSetBaseGenerator.ClusterDecisionResource,
-			PullRequest:             appSetBaseGenerator.PullRequest,
-			Matrix:                  matrix,
-			Merge:                   mergeGenerator,
-		},
+func (m *MatrixGenerator) getParams(ctx context.Context, appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	t, err := transform(
+		ctx,
+		*childGenerator(appSetBaseGenerator),
 		m.supportedGenerators,
 		argoprojiov1alpha1.ApplicationSetTemplate{},
 		appSet)
 
 	if err != nil {
-		return nil, fmt.Errorf("child generator returned an error on parameter generation: %v", err)
+		return nil, fmt.Errorf("child generator returned an error on parameter generation: %w", err)
 	}
 
 	if len(t) == 0 {
@@ -109,32 +95,7 @@ func (m *MatrixGenerator) getParams(appSetBaseGenerator argoprojiov1alpha1.Appli
 const maxDuration time.Duration = 1<<63 - 1
 
 func (m *MatrixGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
-	res := maxDuration
-	var found bool
-
-	for _, r := range appSetGenerator.Matrix.Generators {
-		base := &argoprojiov1alpha1.ApplicationSetGenerator{
-			List:     r.List,
-			Clusters: r.Clusters,
-			Git:      r.Git,
-		}
-		generators := GetRelevantGenerators(base, m.supportedGenerators)
-
-		for _, g := range generators {
-			temp := g.GetRequeueAfter(base)
-			if temp < res && temp != NoRequeueAfter {
-				found = true
-				res = temp
-			}
-		}
-	}
-
-	if found {
-		return res
-	} else {
-		return NoRequeueAfter
-	}
-
+	return getChildRequeueAfter(appSetGenerator.Matrix.Generators, appSetGenerator.Matrix.RequeueAfterSeconds, m.supportedGenerators)
 }
 
 func (m *MatrixGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
@@ -1,6 +1,8 @@
 package generators
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -26,7 +28,7 @@ func TestMatrixGenerate(t *testing.T) {
 		name           string
 		baseGenerators []argoprojiov1alpha1.ApplicationSetNestedGenerator
 		expectedErr    error
-		expected       []map[string]string
+		expected       []map[string]interface{}
 	}{
 		{
 			name: "happy flow - generate params",
@@ -38,7 +40,7 @@ func TestMatrixGenerate(t *testing.T) {
 					List: listGenerator,
 				},
 			},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"path": "app1", "path.basename": "app1", "path.basenameNormalized": "app1", "cluster": "Cluster", "url": "Url"},
 				{"path": "app2", "path.basename": "app2", "path.basenameNormalized": "app2", "cluster": "Cluster", "url": "Url"},
 			},
@@ -63,7 +65,7 @@ func TestMatrixGenerate(t *testing.T) {
 					},
 				},
 			},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "1", "b": "1"},
 				{"a": "1", "b": "2"},
 				{"a": "2", "b": "1"},
@@ -135,7 +137,7 @@ func TestMatrixGenerate(t *testing.T) {
 					Git:  g.Git,
 					List: g.List,
 				}
-				mock.On("GenerateParams", &gitGeneratorSpec, appSet).Return([]map[string]string{
+				mock.On("GenerateParams", &gitGeneratorSpec, appSet).Return([]map[string]interface{}{
 					{
 						"path":                    "app1",
 						"path.basename":           "app1",
@@ -159,7 +161,7 @@ func TestMatrixGenerate(t *testing.T) {
 				},
 			)
 
-			got, err := matrixGenerator.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+			got, err := matrixGenerator.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
 				Matrix: &argoprojiov1alpha1.MatrixGenerator{
 					Generators: testCaseCopy.baseGenerators,
 					Template:   argoprojiov1alpha1.ApplicationSetTemplate{},
@@ -178,6 +180,35 @@ func TestMatrixGenerate(t *testing.T) {
 	}
 }
 
+func TestMatrixGenerateMaxParamSets(t *testing.T) {
+	list := func(elements ...string) argoprojiov1alpha1.ApplicationSetNestedGenerator {
+		var list []apiextensionsv1.JSON
+		for _, e := range elements {
+			list = append(list, apiextensionsv1.JSON{Raw: []byte(e)})
+		}
+		return argoprojiov1alpha1.ApplicationSetNestedGenerator{List: &argoprojiov1alpha1.ListGenerator{Elements: list}}
+	}
+	appSetGenerator := &argoprojiov1alpha1.ApplicationSetGenerator{
+		Matrix: &argoprojiov1alpha1.MatrixGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				list(`{"cluster":"staging"}`, `{"cluster":"production"}`),
+				list(`{"app":"guestbook"}`, `{"app":"helm-guestbook"}`),
+			},
+		},
+	}
+	matrixGenerator := NewMatrixGenerator(map[string]Generator{"List": NewListGenerator()})
+	appSet := &argoprojiov1alpha1.ApplicationSet{}
+
+	params, err := matrixGenerator.GenerateParams(WithMaxParamSets(context.Background(), 4), appSetGenerator, appSet)
+	assert.NoError(t, err)
+	assert.Len(t, params, 4)
+
+	// The param sets are not combined beyond the maximum
+	params, err = matrixGenerator.GenerateParams(WithMaxParamSets(context.Background(), 3), appSetGenerator, appSet)
+	assert.True(t, errors.Is(err, ErrParamSetsOverflow))
+	assert.Nil(t, params)
+}
+
 func TestMatrixGetRequeueAfter(t *testing.T) {
 
 	gitGenerator := &argoprojiov1alpha1.GitGenerator{
@@ -190,11 +221,14 @@ func TestMatrixGetRequeueAfter(t *testing.T) {
 		Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "Cluster","url": "Url"}`)}},
 	}
 
+	oneHour := int64(3600)
+
 	testCases := []struct {
-		name               string
-		baseGenerators     []argoprojiov1alpha1.ApplicationSetNestedGenerator
-		gitGetRequeueAfter time.Duration
-		expected           time.Duration
+		name                string
+		baseGenerators      []argoprojiov1alpha1.ApplicationSetNestedGenerator
+		gitGetRequeueAfter  time.Duration
+		requeueAfterSeconds *int64
+		expected            time.Duration
 	}{
 		{
 			name: "return NoRequeueAfter if all the inner baseGenerators returns it",
@@ -222,6 +256,20 @@ func TestMatrixGetRequeueAfter(t *testing.T) {
 			gitGetRequeueAfter: time.Duration(1),
 			expected:           time.Duration(1),
 		},
+		{
+			name: "requeueAfterSeconds overrides the inner baseGenerators",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{
+					Git: gitGenerator,
+				},
+				{
+					List: listGenerator,
+				},
+			},
+			gitGetRequeueAfter:  time.Duration(1),
+			requeueAfterSeconds: &oneHour,
+			expected:            time.Hour,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -247,8 +295,9 @@ func TestMatrixGetRequeueAfter(t *testing.T) {
 
 			got := matrixGenerator.GetRequeueAfter(&argoprojiov1alpha1.ApplicationSetGenerator{
 				Matrix: &argoprojiov1alpha1.MatrixGenerator{
-					Generators: testCaseCopy.baseGenerators,
-					Template:   argoprojiov1alpha1.ApplicationSetTemplate{},
+					Generators:          testCaseCopy.baseGenerators,
+					Template:            argoprojiov1alpha1.ApplicationSetTemplate{},
+					RequeueAfterSeconds: testCaseCopy.requeueAfterSeconds,
 				},
 			})
 
@@ -269,10 +318,10 @@ func (g *generatorMock) GetTemplate(appSetGenerator *argoprojiov1alpha1.Applicat
 	return args.Get(0).(*argoprojiov1alpha1.ApplicationSetTemplate)
 }
 
-func (g *generatorMock) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *generatorMock) GenerateParams(_ context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	args := g.Called(appSetGenerator, appSet)
 
-	return args.Get(0).([]map[string]string), args.Error(1)
+	return args.Get(0).([]map[string]interface{}), args.Error(1)
 }
 
 func (g *generatorMock) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
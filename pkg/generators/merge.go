@@ -1,6 +1,7 @@
 package generators
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,23 +30,16 @@ func NewMergeGenerator(supportedGenerators map[string]Generator) Generator {
 	return m
 }
 
-// getParamSetsForAllGenerators generates params for each child generator in a MergeGenerator. Param sets are returned
-// in slices ordered according to the order of the given generators.
-func (m *MergeGenerator) getParamSetsForAllGenerators(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([][]map[string]string, error) {
-	var paramSets [][]map[string]string
-	for _, generator := range generators {
-		generatorParamSets, err := m.getParams(generator, appSet)
-		if err != nil {
-			return nil, err
-		}
-		// concatenate param lists produced by each generator
-		paramSets = append(paramSets, generatorParamSets)
-	}
-	return paramSets, nil
+// getParamSetsForAllGenerators generates params for each child generator in a MergeGenerator, in parallel. Param sets
+// are returned in slices ordered according to the order of the given generators.
+func (m *MergeGenerator) getParamSetsForAllGenerators(ctx context.Context, generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([][]map[string]interface{}, error) {
+	return getChildParams(generators, func(generator argoprojiov1alpha1.ApplicationSetNestedGenerator) ([]map[string]interface{}, error) {
+		return m.getParams(ctx, generator, appSet)
+	})
 }
 
 // GenerateParams gets the params produced by the MergeGenerator.
-func (m *MergeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (m *MergeGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	if appSetGenerator.Merge == nil {
 		return nil, EmptyAppSetGeneratorError
 	}
@@ -54,7 +48,7 @@ func (m *MergeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Appl
 		return nil, LessThanTwoGeneratorsInMerge
 	}
 
-	paramSetsFromGenerators, err := m.getParamSetsForAllGenerators(appSetGenerator.Merge.Generators, appSet)
+	paramSetsFromGenerators, err := m.getParamSetsForAllGenerators(ctx, appSetGenerator.Merge.Generators, appSet)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +75,7 @@ func (m *MergeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Appl
 		}
 	}
 
-	mergedParamSets := make([]map[string]string, len(baseParamSetsByMergeKey))
+	mergedParamSets := make([]map[string]interface{}, len(baseParamSetsByMergeKey))
 	var i = 0
 	for _, mergedParamSet := range baseParamSetsByMergeKey {
 		mergedParamSets[i] = mergedParamSet
@@ -94,7 +88,7 @@ func (m *MergeGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.Appl
 // getParamSetsByMergeKey converts the given list of parameter sets to a map of parameter sets where the key is the
 // unique key of the parameter set as determined by the given mergeKeys. If any two parameter sets share the same merge
 // key, getParamSetsByMergeKey will throw NonUniqueParamSets.
-func getParamSetsByMergeKey(mergeKeys []string, paramSets []map[string]string) (map[string]map[string]string, error) {
+func getParamSetsByMergeKey(mergeKeys []string, paramSets []map[string]interface{}) (map[string]map[string]interface{}, error) {
 	if len(mergeKeys) < 1 {
 		return nil, NoMergeKeys
 	}
@@ -104,11 +98,11 @@ func getParamSetsByMergeKey(mergeKeys []string, paramSets []map[string]string) (
 		deDuplicatedMergeKeys[mergeKey] = false
 	}
 
-	paramSetsByMergeKey := make(map[string]map[string]string, len(paramSets))
+	paramSetsByMergeKey := make(map[string]map[string]interface{}, len(paramSets))
 	for _, paramSet := range paramSets {
-		paramSetKey := make(map[string]string)
+		paramSetKey := make(map[string]interface{})
 		for mergeKey := range deDuplicatedMergeKeys {
-			paramSetKey[mergeKey] = paramSet[mergeKey]
+			paramSetKey[mergeKey] = paramKeyValue(paramSet, mergeKey)
 		}
 		paramSetKeyJson, err := json.Marshal(paramSetKey)
 		if err != nil {
@@ -125,34 +119,16 @@ func getParamSetsByMergeKey(mergeKeys []string, paramSets []map[string]string) (
 }
 
 // getParams get the parameters generated by this generator.
-func (m *MergeGenerator) getParams(appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
-	var matrix *argoprojiov1alpha1.MatrixGenerator
-	if appSetBaseGenerator.Matrix != nil {
-		matrix = appSetBaseGenerator.Matrix.ToMatrixGenerator()
-	}
-
-	var mergeGenerator *argoprojiov1alpha1.MergeGenerator
-	if appSetBaseGenerator.Merge != nil {
-		mergeGenerator = appSetBaseGenerator.Merge.ToMergeGenerator()
-	}
-
-	t, err := Transform(
-		argoprojiov1alpha1.ApplicationSetGenerator{
-			List:                    appSetBaseGenerator.List,
-			Clusters:                appSetBaseGenerator.Clusters,
-			Git:                     appSetBaseGenerator.Git,
-			SCMProvider:             appSetBaseGenerator.SCMProvider,
-			ClusterDecisionResource: appSetBaseGenerator.ClusterDecisionResource,
-			PullRequest:             appSetBaseGenerator.PullRequest,
-			Matrix:                  matrix,
-			Merge:                   mergeGenerator,
-		},
+func (m *MergeGenerator) getParams(ctx context.Context, appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	t, err := transform(
+		ctx,
+		*childGenerator(appSetBaseGenerator),
 		m.supportedGenerators,
 		argoprojiov1alpha1.ApplicationSetTemplate{},
 		appSet)
 
 	if err != nil {
-		return nil, fmt.Errorf("child generator returned an error on parameter generation: %v", err)
+		return nil, fmt.Errorf("child generator returned an error on parameter generation: %w", err)
 	}
 
 	if len(t) == 0 {
@@ -167,35 +143,20 @@ func (m *MergeGenerator) getParams(appSetBaseGenerator argoprojiov1alpha1.Applic
 }
 
 func (m *MergeGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
-	res := maxDuration
-	var found bool
-
-	for _, r := range appSetGenerator.Merge.Generators {
-		base := &argoprojiov1alpha1.ApplicationSetGenerator{
-			List:     r.List,
-			Clusters: r.Clusters,
-			Git:      r.Git,
-		}
-		generators := GetRelevantGenerators(base, m.supportedGenerators)
-
-		for _, g := range generators {
-			temp := g.GetRequeueAfter(base)
-			if temp < res && temp != NoRequeueAfter {
-				found = true
-				res = temp
-			}
-		}
-	}
-
-	if found {
-		return res
-	} else {
-		return NoRequeueAfter
-	}
-
+	return getChildRequeueAfter(appSetGenerator.Merge.Generators, appSetGenerator.Merge.RequeueAfterSeconds, m.supportedGenerators)
 }
 
 // GetTemplate gets the Template field for the MergeGenerator.
 func (m *MergeGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
 	return &appSetGenerator.Merge.Template
 }
+
+// paramKeyValue returns the value of a key of a parameter set, used to match parameter sets. A missing key is
+// considered as an empty value.
+func paramKeyValue(paramSet map[string]interface{}, key string) interface{} {
+	value, ok := paramSet[key]
+	if !ok {
+		return ""
+	}
+	return value
+}
@@ -1,9 +1,11 @@
 package generators
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
@@ -34,7 +36,7 @@ func getTerminalListGeneratorMultiple(jsons []string) argoprojiov1alpha1.Applica
 	return generator
 }
 
-func listOfMapsToSet(maps []map[string]string) (map[string]bool, error) {
+func listOfMapsToSet(maps []map[string]interface{}) (map[string]bool, error) {
 	set := make(map[string]bool, len(maps))
 	for _, paramMap := range maps {
 		paramMapAsJson, err := json.Marshal(paramMap)
@@ -54,7 +56,7 @@ func TestMergeGenerate(t *testing.T) {
 		baseGenerators []argoprojiov1alpha1.ApplicationSetNestedGenerator
 		mergeKeys      []string
 		expectedErr    error
-		expected       []map[string]string
+		expected       []map[string]interface{}
 	}{
 		{
 			name:           "no generators",
@@ -78,7 +80,7 @@ func TestMergeGenerate(t *testing.T) {
 				*getNestedListGenerator(`{"a": "3_1","b": "different","c": "3_3"}`), // gets ignored because its merge key value isn't in the base params set
 			},
 			mergeKeys: []string{"b"},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "2_1", "b": "same", "c": "1_3"},
 			},
 		},
@@ -89,7 +91,7 @@ func TestMergeGenerate(t *testing.T) {
 				*getNestedListGenerator(`{"a": "a"}`),
 			},
 			mergeKeys: []string{"b"},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "a"},
 			},
 		},
@@ -100,7 +102,7 @@ func TestMergeGenerate(t *testing.T) {
 				*getNestedListGenerator(`{"b": "b"}`),
 			},
 			mergeKeys: []string{"b"},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "a"},
 			},
 		},
@@ -118,7 +120,7 @@ func TestMergeGenerate(t *testing.T) {
 				*getNestedListGenerator(`{"a": "1", "b": "1", "c": "added"}`),
 			},
 			mergeKeys: []string{"a", "b"},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "1", "b": "1", "c": "added"},
 				{"a": "1", "b": "2"},
 				{"a": "2", "b": "1"},
@@ -140,7 +142,7 @@ func TestMergeGenerate(t *testing.T) {
 				*getNestedListGenerator(`{"a": "1", "b": "3", "d": "added"}`),
 			},
 			mergeKeys: []string{"a", "b"},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{"a": "1", "b": "3", "c": "added", "d": "added"},
 				{"a": "2", "b": "2"},
 			},
@@ -171,7 +173,7 @@ func TestMergeGenerate(t *testing.T) {
 				},
 			)
 
-			got, err := mergeGenerator.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+			got, err := mergeGenerator.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
 				Merge: &argoprojiov1alpha1.MergeGenerator{
 					Generators: testCaseCopy.baseGenerators,
 					MergeKeys:  testCaseCopy.mergeKeys,
@@ -199,9 +201,9 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 	testCases := []struct {
 		name        string
 		mergeKeys   []string
-		paramSets   []map[string]string
+		paramSets   []map[string]interface{}
 		expectedErr error
-		expected    map[string]map[string]string
+		expected    map[string]map[string]interface{}
 	}{
 		{
 			name:        "no merge keys",
@@ -211,13 +213,13 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:      "no paramSets",
 			mergeKeys: []string{"key"},
-			expected:  make(map[string]map[string]string),
+			expected:  make(map[string]map[string]interface{}),
 		},
 		{
 			name:      "simple key, unique paramSets",
 			mergeKeys: []string{"key"},
-			paramSets: []map[string]string{{"key": "a"}, {"key": "b"}},
-			expected: map[string]map[string]string{
+			paramSets: []map[string]interface{}{{"key": "a"}, {"key": "b"}},
+			expected: map[string]map[string]interface{}{
 				`{"key":"a"}`: {"key": "a"},
 				`{"key":"b"}`: {"key": "b"},
 			},
@@ -225,14 +227,14 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:        "simple key, non-unique paramSets",
 			mergeKeys:   []string{"key"},
-			paramSets:   []map[string]string{{"key": "a"}, {"key": "b"}, {"key": "b"}},
+			paramSets:   []map[string]interface{}{{"key": "a"}, {"key": "b"}, {"key": "b"}},
 			expectedErr: fmt.Errorf("%w. Duplicate key was %s", NonUniqueParamSets, `{"key":"b"}`),
 		},
 		{
 			name:      "simple key, duplicated key name, unique paramSets",
 			mergeKeys: []string{"key", "key"},
-			paramSets: []map[string]string{{"key": "a"}, {"key": "b"}},
-			expected: map[string]map[string]string{
+			paramSets: []map[string]interface{}{{"key": "a"}, {"key": "b"}},
+			expected: map[string]map[string]interface{}{
 				`{"key":"a"}`: {"key": "a"},
 				`{"key":"b"}`: {"key": "b"},
 			},
@@ -240,18 +242,18 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:        "simple key, duplicated key name, non-unique paramSets",
 			mergeKeys:   []string{"key", "key"},
-			paramSets:   []map[string]string{{"key": "a"}, {"key": "b"}, {"key": "b"}},
+			paramSets:   []map[string]interface{}{{"key": "a"}, {"key": "b"}, {"key": "b"}},
 			expectedErr: fmt.Errorf("%w. Duplicate key was %s", NonUniqueParamSets, `{"key":"b"}`),
 		},
 		{
 			name:      "compound key, unique paramSets",
 			mergeKeys: []string{"key1", "key2"},
-			paramSets: []map[string]string{
+			paramSets: []map[string]interface{}{
 				{"key1": "a", "key2": "a"},
 				{"key1": "a", "key2": "b"},
 				{"key1": "b", "key2": "a"},
 			},
-			expected: map[string]map[string]string{
+			expected: map[string]map[string]interface{}{
 				`{"key1":"a","key2":"a"}`: {"key1": "a", "key2": "a"},
 				`{"key1":"a","key2":"b"}`: {"key1": "a", "key2": "b"},
 				`{"key1":"b","key2":"a"}`: {"key1": "b", "key2": "a"},
@@ -260,12 +262,12 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:      "compound key, duplicate key names, unique paramSets",
 			mergeKeys: []string{"key1", "key1", "key2"},
-			paramSets: []map[string]string{
+			paramSets: []map[string]interface{}{
 				{"key1": "a", "key2": "a"},
 				{"key1": "a", "key2": "b"},
 				{"key1": "b", "key2": "a"},
 			},
-			expected: map[string]map[string]string{
+			expected: map[string]map[string]interface{}{
 				`{"key1":"a","key2":"a"}`: {"key1": "a", "key2": "a"},
 				`{"key1":"a","key2":"b"}`: {"key1": "a", "key2": "b"},
 				`{"key1":"b","key2":"a"}`: {"key1": "b", "key2": "a"},
@@ -274,7 +276,7 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:      "compound key, non-unique paramSets",
 			mergeKeys: []string{"key1", "key2"},
-			paramSets: []map[string]string{
+			paramSets: []map[string]interface{}{
 				{"key1": "a", "key2": "a"},
 				{"key1": "a", "key2": "a"},
 				{"key1": "b", "key2": "a"},
@@ -284,7 +286,7 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 		{
 			name:      "compound key, duplicate key names, non-unique paramSets",
 			mergeKeys: []string{"key1", "key1", "key2"},
-			paramSets: []map[string]string{
+			paramSets: []map[string]interface{}{
 				{"key1": "a", "key2": "a"},
 				{"key1": "a", "key2": "a"},
 				{"key1": "b", "key2": "a"},
@@ -312,3 +314,23 @@ func TestParamSetsAreUniqueByMergeKeys(t *testing.T) {
 
 	}
 }
+
+func TestMergeGetRequeueAfter(t *testing.T) {
+	requeueAfterSeconds := int64(60)
+	mergeGenerator := NewMergeGenerator(map[string]Generator{
+		"List":        &ListGenerator{},
+		"SCMProvider": &SCMProviderGenerator{},
+	})
+
+	got := mergeGenerator.GetRequeueAfter(&argoprojiov1alpha1.ApplicationSetGenerator{
+		Merge: &argoprojiov1alpha1.MergeGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1"}`),
+				{SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{RequeueAfterSeconds: &requeueAfterSeconds}},
+			},
+			MergeKeys: []string{"a"},
+		},
+	})
+
+	assert.Equal(t, time.Minute, got)
+}
@@ -0,0 +1,38 @@
+package generators
+
+import (
+	"sync"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// maxParallelChildGenerators is the maximum number of child generators of a Matrix or Merge generator generating their
+// params at the same time.
+const maxParallelChildGenerators = 4
+
+// getChildParams calls getParams for each of the child generators, running at most maxParallelChildGenerators of them
+// at the same time. The params are returned in slices ordered according to the order of the given generators. If any
+// child generator fails, the errors of all the failed child generators are returned together.
+func getChildParams(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, getParams func(argoprojiov1alpha1.ApplicationSetNestedGenerator) ([]map[string]interface{}, error)) ([][]map[string]interface{}, error) {
+	paramSets := make([][]map[string]interface{}, len(generators))
+	errs := make([]error, len(generators))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelChildGenerators)
+	for i := range generators {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			paramSets[i], errs[i] = getParams(generators[i])
+		}(i)
+	}
+	wg.Wait()
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+	return paramSets, nil
+}
@@ -0,0 +1,57 @@
+package generators
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChildParams(t *testing.T) {
+	generators := make([]argoprojiov1alpha1.ApplicationSetNestedGenerator, 10)
+	for i := range generators {
+		generators[i] = argoprojiov1alpha1.ApplicationSetNestedGenerator{
+			List: &argoprojiov1alpha1.ListGenerator{Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: fmt.Sprint(i)},
+			}},
+		}
+	}
+
+	t.Run("params are returned in the order of the generators", func(t *testing.T) {
+		var running, maxRunning int32
+		paramSets, err := getChildParams(generators, func(generator argoprojiov1alpha1.ApplicationSetNestedGenerator) ([]map[string]interface{}, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return []map[string]interface{}{{"name": generator.List.Template.Name}}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, paramSets, len(generators))
+		for i, params := range paramSets {
+			assert.Equal(t, []map[string]interface{}{{"name": fmt.Sprint(i)}}, params)
+		}
+		assert.LessOrEqual(t, maxRunning, int32(maxParallelChildGenerators))
+	})
+
+	t.Run("errors of all the failed generators are returned", func(t *testing.T) {
+		_, err := getChildParams(generators, func(generator argoprojiov1alpha1.ApplicationSetNestedGenerator) ([]map[string]interface{}, error) {
+			if generator.List.Template.Name == "2" || generator.List.Template.Name == "7" {
+				return nil, errors.New("failed generator " + generator.List.Template.Name)
+			}
+			return []map[string]interface{}{}, nil
+		})
+
+		assert.EqualError(t, err, "[failed generator 2, failed generator 7]")
+	})
+}
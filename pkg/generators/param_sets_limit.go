@@ -0,0 +1,26 @@
+package generators
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrParamSetsOverflow is returned when the generators of an ApplicationSet generate more param sets than the maximum
+// of the context.
+var ErrParamSetsOverflow = errors.New("too many param sets")
+
+// maxParamSetsKey is the key of the maximum number of param sets in the contexts.
+type maxParamSetsKey struct{}
+
+// WithMaxParamSets returns a copy of the context in which the generators of an ApplicationSet fail with
+// ErrParamSetsOverflow rather than generating more than max param sets, e.g. the Matrix generators rather than
+// combining their child generators into more param sets. There is no maximum if max is 0.
+func WithMaxParamSets(ctx context.Context, max int) context.Context {
+	return context.WithValue(ctx, maxParamSetsKey{}, max)
+}
+
+// MaxParamSets returns the maximum number of param sets of the context, 0 if there is no maximum.
+func MaxParamSets(ctx context.Context) int {
+	max, _ := ctx.Value(maxParamSetsKey{}).(int)
+	return max
+}
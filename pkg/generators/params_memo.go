@@ -0,0 +1,140 @@
+package generators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// paramsMemoKey is the key of the params memo in the contexts.
+type paramsMemoKey struct{}
+
+// paramsMemo keeps the params generated by the generators of an ApplicationSet during a reconciliation, by generator
+// spec, so that the identical generators of the ApplicationSet, e.g. the same Git generator combined with two others by
+// Matrix generators, generate their params once.
+type paramsMemo struct {
+	lock    sync.Mutex
+	entries map[string]*paramsMemoEntry
+}
+
+type paramsMemoEntry struct {
+	// done is closed once the params are generated
+	done   chan struct{}
+	params []map[string]interface{}
+	err    error
+}
+
+// WithParamsMemo returns a copy of the context in which the generators of an ApplicationSet generate the params of each
+// generator spec once, e.g. during a reconciliation of the ApplicationSet. The identical generators, whether top-level
+// or children of Matrix, Merge and Union generators, get copies of the params of the first of them. The context must
+// not be shared by several ApplicationSets.
+func WithParamsMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, paramsMemoKey{}, &paramsMemo{entries: map[string]*paramsMemoEntry{}})
+}
+
+// generateParams returns the params of the generator, generated by g, or the params generated for an identical
+// generator spec if the context has a params memo. The params of the identical generators running at the same time are
+// generated once.
+func generateParams(ctx context.Context, name string, g Generator, requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, bool, error) {
+	memo, _ := ctx.Value(paramsMemoKey{}).(*paramsMemo)
+	if memo == nil {
+		params, err := g.GenerateParams(ctx, requestedGenerator, appSet)
+		return params, false, err
+	}
+
+	key, err := paramsMemoEntryKey(name, requestedGenerator)
+	if err != nil {
+		return nil, false, err
+	}
+
+	memo.lock.Lock()
+	entry, ok := memo.entries[key]
+	if !ok {
+		entry = &paramsMemoEntry{done: make(chan struct{})}
+		memo.entries[key] = entry
+	}
+	memo.lock.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+		return copyParamSets(entry.params), true, entry.err
+	}
+
+	func() {
+		defer close(entry.done)
+		entry.params, entry.err = g.GenerateParams(ctx, requestedGenerator, appSet)
+	}()
+	return copyParamSets(entry.params), false, entry.err
+}
+
+// paramsMemoEntryKey returns the key of the params of the generator: the hash of its name and of its spec.
+func paramsMemoEntryKey(name string, requestedGenerator *argoprojiov1alpha1.ApplicationSetGenerator) (string, error) {
+	generatorJSON, err := json.Marshal(requestedGenerator)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling the generator: %v", err)
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s", name, generatorJSON)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyParamSets returns a deep copy of the param sets, which are modified by the callers of the generators. The maps
+// and slices of the params are copied, the other values are immutable.
+func copyParamSets(paramSets []map[string]interface{}) []map[string]interface{} {
+	if paramSets == nil {
+		return nil
+	}
+	res := make([]map[string]interface{}, 0, len(paramSets))
+	for _, params := range paramSets {
+		res = append(res, copyParamValue(params).(map[string]interface{}))
+	}
+	return res
+}
+
+func copyParamValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		res := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			res[key] = copyParamValue(value)
+		}
+		return res
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		res := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			res = append(res, copyParamValue(value))
+		}
+		return res
+	case map[string]string:
+		if v == nil {
+			return v
+		}
+		res := make(map[string]string, len(v))
+		for key, value := range v {
+			res[key] = value
+		}
+		return res
+	case []string:
+		if v == nil {
+			return v
+		}
+		return append(make([]string, 0, len(v)), v...)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,90 @@
+package generators
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// countingGenerator is a List generator counting the calls of GenerateParams.
+type countingGenerator struct {
+	ListGenerator
+	lock  sync.Mutex
+	calls int
+}
+
+func (g *countingGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	g.lock.Lock()
+	g.calls++
+	g.lock.Unlock()
+	return g.ListGenerator.GenerateParams(ctx, appSetGenerator, appSet)
+}
+
+func TestTransformWithParamsMemo(t *testing.T) {
+	list := func(element string) argoprojiov1alpha1.ApplicationSetNestedGenerator {
+		return argoprojiov1alpha1.ApplicationSetNestedGenerator{
+			List: &argoprojiov1alpha1.ListGenerator{Elements: []apiextensionsv1.JSON{{Raw: []byte(element)}}},
+		}
+	}
+	clusters := list(`{"cluster":"staging"}`)
+	matrix := func(other argoprojiov1alpha1.ApplicationSetNestedGenerator) argoprojiov1alpha1.ApplicationSetGenerator {
+		return argoprojiov1alpha1.ApplicationSetGenerator{
+			Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{clusters, other}},
+		}
+	}
+	appSetGenerators := []argoprojiov1alpha1.ApplicationSetGenerator{
+		matrix(list(`{"app":"guestbook"}`)),
+		matrix(list(`{"app":"helm-guestbook"}`)),
+	}
+	appSet := &argoprojiov1alpha1.ApplicationSet{}
+
+	counting := &countingGenerator{}
+	allGenerators := map[string]Generator{"List": counting}
+	allGenerators["Matrix"] = NewMatrixGenerator(allGenerators)
+
+	transformAll := func(ctx context.Context) [][]map[string]interface{} {
+		var res [][]map[string]interface{}
+		for _, g := range appSetGenerators {
+			results, err := Transform(ctx, g, allGenerators, argoprojiov1alpha1.ApplicationSetTemplate{}, appSet)
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				res = append(res, results[0].Params)
+			}
+		}
+		return res
+	}
+
+	// Without a memo, the child generator shared by the Matrix generators generates its params twice
+	expected := transformAll(context.Background())
+	assert.Equal(t, 4, counting.calls)
+
+	counting.calls = 0
+	got := transformAll(WithParamsMemo(context.Background()))
+	assert.Equal(t, 3, counting.calls)
+	assert.Equal(t, expected, got)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "staging", got[1][0]["cluster"])
+		assert.Equal(t, "helm-guestbook", got[1][0]["app"])
+	}
+}
+
+func TestCopyParamSets(t *testing.T) {
+	paramSets := []map[string]interface{}{
+		{"cluster": "staging", "zones": []interface{}{"a"}, "labels": map[string]interface{}{"env": "staging"}, "paths": []string{"apps"}},
+	}
+	copied := copyParamSets(paramSets)
+	assert.Equal(t, paramSets, copied)
+
+	copied[0]["cluster"] = "production"
+	copied[0]["zones"].([]interface{})[0] = "b"
+	copied[0]["labels"].(map[string]interface{})["env"] = "production"
+	copied[0]["paths"].([]string)[0] = "infra"
+	assert.Equal(t, []map[string]interface{}{
+		{"cluster": "staging", "zones": []interface{}{"a"}, "labels": map[string]interface{}{"env": "staging"}, "paths": []string{"apps"}},
+	}, paramSets)
+}
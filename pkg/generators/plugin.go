@@ -0,0 +1,240 @@
+package generators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+var _ Generator = (*PluginGenerator)(nil)
+
+const (
+	DefaultPluginRequeueAfterSeconds = 30 * time.Minute
+	// DefaultPluginRequestTimeout is the timeout of the requests to the plugin service, if not set in the ConfigMap.
+	DefaultPluginRequestTimeout = 30 * time.Second
+
+	pluginConfigMapBaseURLKey        = "baseUrl"
+	pluginConfigMapTokenKey          = "token"
+	pluginConfigMapRequestTimeoutKey = "requestTimeout"
+
+	// pluginGetParamsPath is the path of the plugin service endpoint returning the parameters.
+	pluginGetParamsPath = "/api/v1/getparams.execute"
+)
+
+// PluginGenerator generates parameters by querying an external plugin service over HTTP.
+type PluginGenerator struct {
+	client    client.Client
+	namespace string // namespace is the Argo CD namespace
+}
+
+func NewPluginGenerator(client client.Client, namespace string) Generator {
+	g := &PluginGenerator{
+		client:    client,
+		namespace: namespace,
+	}
+	return g
+}
+
+// pluginRequest is the body of the requests sent to the plugin service.
+type pluginRequest struct {
+	ApplicationSetName string                         `json:"applicationSetName"`
+	Input              argoprojiov1alpha1.PluginInput `json:"input"`
+}
+
+// pluginResponse is the body of the responses returned by the plugin service.
+type pluginResponse struct {
+	Output struct {
+		Parameters []map[string]interface{} `json:"parameters"`
+	} `json:"output"`
+}
+
+// pluginConfig is the configuration of a plugin service, read from the ConfigMap referenced by the generator.
+type pluginConfig struct {
+	baseURL        string
+	token          string
+	requestTimeout time.Duration
+}
+
+func (g *PluginGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.Plugin.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Plugin.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultPluginRequeueAfterSeconds
+}
+
+func (g *PluginGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Plugin.Template
+}
+
+func (g *PluginGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.Plugin == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	config, err := g.getPluginConfig(ctx, appSetGenerator.Plugin.ConfigMapRef)
+	if err != nil {
+		return nil, err
+	}
+
+	appSetName := ""
+	if applicationSetInfo != nil {
+		appSetName = applicationSetInfo.Name
+	}
+	paramSets, err := g.getParams(ctx, config, pluginRequest{
+		ApplicationSetName: appSetName,
+		Input:              appSetGenerator.Plugin.Input,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error calling plugin %s: %v", appSetGenerator.Plugin.ConfigMapRef, err)
+	}
+
+	res := make([]map[string]interface{}, 0, len(paramSets))
+	for _, paramSet := range paramSets {
+		params, err := jsonObjectToParams(paramSet, useGoTemplate(applicationSetInfo))
+		if err != nil {
+			return nil, fmt.Errorf("error reading parameters returned by plugin %s: %v", appSetGenerator.Plugin.ConfigMapRef, err)
+		}
+		for key, value := range appSetGenerator.Plugin.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+		res = append(res, params)
+	}
+
+	return res, nil
+}
+
+// getPluginConfig reads the configuration of the plugin service from the given ConfigMap.
+func (g *PluginGenerator) getPluginConfig(ctx context.Context, configMapRef string) (*pluginConfig, error) {
+	configMap := &corev1.ConfigMap{}
+	err := g.client.Get(ctx, client.ObjectKey{Name: configMapRef, Namespace: g.namespace}, configMap)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching plugin ConfigMap %s/%s: %v", g.namespace, configMapRef, err)
+	}
+
+	baseURL := configMap.Data[pluginConfigMapBaseURLKey]
+	if baseURL == "" {
+		return nil, fmt.Errorf("plugin ConfigMap %s/%s is missing %s", g.namespace, configMapRef, pluginConfigMapBaseURLKey)
+	}
+
+	config := &pluginConfig{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		requestTimeout: DefaultPluginRequestTimeout,
+	}
+
+	if tokenRef := configMap.Data[pluginConfigMapTokenKey]; tokenRef != "" {
+		config.token, err = g.getToken(ctx, tokenRef)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching token of plugin ConfigMap %s/%s: %v", g.namespace, configMapRef, err)
+		}
+	}
+
+	if requestTimeout := configMap.Data[pluginConfigMapRequestTimeoutKey]; requestTimeout != "" {
+		seconds, err := strconv.Atoi(requestTimeout)
+		if err != nil || seconds < 1 {
+			return nil, fmt.Errorf("invalid %s %q in plugin ConfigMap %s/%s: must be a positive number of seconds", pluginConfigMapRequestTimeoutKey, requestTimeout, g.namespace, configMapRef)
+		}
+		config.requestTimeout = time.Duration(seconds) * time.Second
+	}
+
+	return config, nil
+}
+
+// getToken resolves a token reference of the form $<secret name>:<key> to the value of the key in the Secret.
+func (g *PluginGenerator) getToken(ctx context.Context, tokenRef string) (string, error) {
+	if !strings.HasPrefix(tokenRef, "$") {
+		return "", fmt.Errorf("token %q must be a reference to a secret key, of the form $<secret name>:<key>", tokenRef)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(tokenRef, "$"), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("token %q must be a reference to a secret key, of the form $<secret name>:<key>", tokenRef)
+	}
+	secretName, key := parts[0], parts[1]
+
+	secret := &corev1.Secret{}
+	err := g.client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: g.namespace}, secret)
+	if err != nil {
+		return "", fmt.Errorf("error fetching secret %s/%s: %v", g.namespace, secretName, err)
+	}
+	token, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q in secret %s/%s not found", key, g.namespace, secretName)
+	}
+	return string(token), nil
+}
+
+// getParams sends the request to the plugin service, and returns the parameter sets of its response.
+func (g *PluginGenerator) getParams(ctx context.Context, config *pluginConfig, request pluginRequest) ([]map[string]interface{}, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.baseURL+pluginGetParamsPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.token != "" {
+		req.Header.Set("Authorization", "Bearer "+config.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var pluginResp pluginResponse
+	if err := json.Unmarshal(respBody, &pluginResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	return pluginResp.Output.Parameters, nil
+}
+
+// jsonObjectToParams converts a decoded JSON object to a parameter set. With Go templates, values are kept as-is, so
+// templates can use lists and objects. Otherwise, string values are kept as-is, and other values are converted to their
+// JSON representation.
+func jsonObjectToParams(object map[string]interface{}, useGoTemplate bool) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if _, ok := value.(string); ok || useGoTemplate {
+			params[key] = value
+			continue
+		}
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling parameter %s: %v", key, err)
+		}
+		params[key] = string(valueJSON)
+	}
+	return params, nil
+}
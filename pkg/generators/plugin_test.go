@@ -0,0 +1,160 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestPluginGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/getparams.execute", r.URL.Path)
+
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, "unauthorized")
+			return
+		}
+
+		var req pluginRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "my-appset", req.ApplicationSetName)
+
+		if string(req.Input.Parameters["slow"].Raw) == "true" {
+			time.Sleep(2 * time.Second)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"output":{"parameters":[{"name":"staging","replicas":2,"regions":["eu","us"]},{"name":"production","input":%s}]}}`, string(req.Input.Parameters["env"].Raw))
+	}))
+	defer ts.Close()
+
+	newConfigMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"},
+			Data:       data,
+		}
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "plugin-secret", Namespace: "argocd"},
+		Data:       map[string][]byte{"token": []byte("my-token")},
+	}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.PluginGenerator
+		goTemplate    bool
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "params returned by the plugin",
+			generator: &argoprojiov1alpha1.PluginGenerator{
+				ConfigMapRef: "plugin",
+				Input: argoprojiov1alpha1.PluginInput{
+					Parameters: map[string]apiextensionsv1.JSON{"env": {Raw: []byte(`"prod"`)}},
+				},
+				Values: map[string]string{"team": "platform"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "staging", "replicas": "2", "regions": `["eu","us"]`, "values.team": "platform"},
+				{"name": "production", "input": "prod", "values.team": "platform"},
+			},
+		},
+		{
+			name: "structured params with Go templates",
+			generator: &argoprojiov1alpha1.PluginGenerator{
+				ConfigMapRef: "plugin",
+				Input: argoprojiov1alpha1.PluginInput{
+					Parameters: map[string]apiextensionsv1.JSON{"env": {Raw: []byte(`{"name":"prod"}`)}},
+				},
+			},
+			goTemplate: true,
+			expected: []map[string]interface{}{
+				{"name": "staging", "replicas": float64(2), "regions": []interface{}{"eu", "us"}},
+				{"name": "production", "input": map[string]interface{}{"name": "prod"}},
+			},
+		},
+		{
+			name:          "missing ConfigMap",
+			generator:     &argoprojiov1alpha1.PluginGenerator{ConfigMapRef: "missing"},
+			expectedError: "error fetching plugin ConfigMap argocd/missing",
+		},
+		{
+			name:          "ConfigMap without baseUrl",
+			generator:     &argoprojiov1alpha1.PluginGenerator{ConfigMapRef: "plugin-no-url"},
+			expectedError: "plugin ConfigMap argocd/plugin-no-url is missing baseUrl",
+		},
+		{
+			name:          "token not referencing a secret",
+			generator:     &argoprojiov1alpha1.PluginGenerator{ConfigMapRef: "plugin-plain-token"},
+			expectedError: "must be a reference to a secret key",
+		},
+		{
+			name:          "wrong token",
+			generator:     &argoprojiov1alpha1.PluginGenerator{ConfigMapRef: "plugin-no-token"},
+			expectedError: "unexpected status 401: unauthorized",
+		},
+		{
+			name:          "invalid request timeout",
+			generator:     &argoprojiov1alpha1.PluginGenerator{ConfigMapRef: "plugin-bad-timeout"},
+			expectedError: "invalid requestTimeout \"0\"",
+		},
+		{
+			name: "request timeout",
+			generator: &argoprojiov1alpha1.PluginGenerator{
+				ConfigMapRef: "plugin-short-timeout",
+				Input: argoprojiov1alpha1.PluginInput{
+					Parameters: map[string]apiextensionsv1.JSON{"slow": {Raw: []byte(`true`)}},
+				},
+			},
+			expectedError: "context deadline exceeded",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		secret,
+		newConfigMap("plugin", map[string]string{"baseUrl": ts.URL + "/", "token": "$plugin-secret:token"}),
+		newConfigMap("plugin-no-url", map[string]string{"token": "$plugin-secret:token"}),
+		newConfigMap("plugin-plain-token", map[string]string{"baseUrl": ts.URL, "token": "my-token"}),
+		newConfigMap("plugin-no-token", map[string]string{"baseUrl": ts.URL}),
+		newConfigMap("plugin-bad-timeout", map[string]string{"baseUrl": ts.URL, "token": "$plugin-secret:token", "requestTimeout": "0"}),
+		newConfigMap("plugin-short-timeout", map[string]string{"baseUrl": ts.URL, "token": "$plugin-secret:token", "requestTimeout": "1"}),
+	).Build()
+
+	gen := NewPluginGenerator(fakeClient, "argocd")
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			appSet := &argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"},
+				Spec:       argoprojiov1alpha1.ApplicationSetSpec{GoTemplate: cc.goTemplate},
+			}
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{Plugin: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
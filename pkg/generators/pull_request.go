@@ -4,19 +4,24 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
-	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/github_app"
 	pullrequest "github.com/argoproj-labs/applicationset/pkg/services/pull_request"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
 var _ Generator = (*PullRequestGenerator)(nil)
 
 const (
 	DefaultPullRequestRequeueAfterSeconds = 30 * time.Minute
+	// DefaultPullRequestShortSHALength is the length of the head_short_sha param, if not specified.
+	DefaultPullRequestShortSHALength = 7
 )
 
 type PullRequestGenerator struct {
@@ -32,6 +37,16 @@ func NewPullRequestGenerator(client client.Client) Generator {
 	return g
 }
 
+// NewFixedPullRequestGenerator returns a Pull Request generator listing the pull requests of the given service,
+// whatever the provider configured by the ApplicationSets, e.g. to render ApplicationSets offline.
+func NewFixedPullRequestGenerator(service pullrequest.PullRequestService) Generator {
+	g := &PullRequestGenerator{}
+	g.selectServiceProviderFunc = func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error) {
+		return service, nil
+	}
+	return g
+}
+
 func (g *PullRequestGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
 	// Return a requeue default of 30 minutes, if no default is specified.
 
@@ -46,7 +61,7 @@ func (g *PullRequestGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.A
 	return &appSetGenerator.PullRequest.Template
 }
 
-func (g *PullRequestGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *PullRequestGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
 	}
@@ -55,60 +70,124 @@ func (g *PullRequestGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha
 		return nil, EmptyAppSetGeneratorError
 	}
 
-	ctx := context.Background()
+	shortSHALength := DefaultPullRequestShortSHALength
+	if appSetGenerator.PullRequest.ShortSHALength != nil {
+		shortSHALength = *appSetGenerator.PullRequest.ShortSHALength
+		if shortSHALength < 1 {
+			return nil, fmt.Errorf("invalid shortSHALength %d: must be greater than 0", shortSHALength)
+		}
+	}
+
 	svc, err := g.selectServiceProviderFunc(ctx, appSetGenerator.PullRequest, applicationSetInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to select pull request service provider: %v", err)
 	}
 
-	pulls, err := svc.List(ctx)
+	pulls, err := pullrequest.ListPullRequests(ctx, svc, appSetGenerator.PullRequest.Filters)
 	if err != nil {
 		return nil, fmt.Errorf("error listing repos: %v", err)
 	}
-	params := make([]map[string]string, 0, len(pulls))
+	params := make([]map[string]interface{}, 0, len(pulls))
 	for _, pull := range pulls {
-		params = append(params, map[string]string{
-			"number":   strconv.Itoa(pull.Number),
-			"branch":   pull.Branch,
-			"head_sha": pull.HeadSHA,
+		shortSHA := pull.HeadSHA
+		if len(shortSHA) > shortSHALength {
+			shortSHA = shortSHA[:shortSHALength]
+		}
+		params = append(params, map[string]interface{}{
+			"number":         strconv.Itoa(pull.Number),
+			"branch":         pull.Branch,
+			"head_sha":       pull.HeadSHA,
+			"head_short_sha": shortSHA,
+			"author":         pull.Author,
+			"title":          sanitizePullRequestTitle(pull.Title),
+			"labels":         strings.Join(pull.Labels, ","),
+			"created_at":     formatPullRequestTime(pull.CreatedAt),
 		})
 	}
 	return params, nil
 }
 
+// sanitizePullRequestTitle collapses line breaks, tabs and repeated spaces in a pull request title into single
+// spaces, so the title can be embedded in annotations without spanning multiple lines.
+func sanitizePullRequestTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(title, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+}
+
+// formatPullRequestTime formats the given time as RFC 3339 in UTC, or returns an empty string if it is unset.
+func formatPullRequestTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 // selectServiceProvider selects the provider to get pull requests from the configuration
 func (g *PullRequestGenerator) selectServiceProvider(ctx context.Context, generatorConfig *argoprojiov1alpha1.PullRequestGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error) {
+	caData, err := utils.GetSecretRef(ctx, g.client, generatorConfig.CARef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret CA certificates: %v", err)
+	}
+	httpClientOptions := utils.HTTPClientOptions{ProxyURL: generatorConfig.Proxy, Insecure: generatorConfig.Insecure, CAData: caData}
+	if generatorConfig.Gitea != nil && generatorConfig.Gitea.Insecure {
+		httpClientOptions.Insecure = true
+	}
+	httpClient, err := utils.NewHTTPClient(httpClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the HTTP client: %v", err)
+	}
+
+	if generatorConfig.Github != nil && generatorConfig.Github.GithubApp != nil {
+		providerConfig := generatorConfig.Github
+		githubApp := providerConfig.GithubApp
+		privateKey, err := utils.GetSecretRef(ctx, g.client, &githubApp.PrivateKeyRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Github App private key: %v", err)
+		}
+		auth := github_app.Authentication{AppID: githubApp.AppID, InstallationID: githubApp.InstallationID, PrivateKey: privateKey}
+		return pullrequest.NewGithubAppService(ctx, auth, providerConfig.API, providerConfig.Owner, providerConfig.Repo, getLabelFilter(providerConfig.Labels, providerConfig.AnyLabels, providerConfig.NotLabels), providerConfig.ExcludeDrafts, httpClient)
+	}
 	if generatorConfig.Github != nil {
 		providerConfig := generatorConfig.Github
-		token, err := g.getSecretRef(ctx, providerConfig.TokenRef, applicationSetInfo.Namespace)
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.TokenRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret token: %v", err)
+		}
+		return pullrequest.NewGithubService(ctx, token, providerConfig.API, providerConfig.Owner, providerConfig.Repo, getLabelFilter(providerConfig.Labels, providerConfig.AnyLabels, providerConfig.NotLabels), providerConfig.ExcludeDrafts, httpClient)
+	}
+	if generatorConfig.Gitea != nil {
+		providerConfig := generatorConfig.Gitea
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.TokenRef, applicationSetInfo.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("error fetching Secret token: %v", err)
 		}
-		return pullrequest.NewGithubService(ctx, token, providerConfig.API, providerConfig.Owner, providerConfig.Repo, providerConfig.Labels)
+		return pullrequest.NewGiteaService(ctx, token, providerConfig.API, providerConfig.Owner, providerConfig.Repo, getLabelFilter(providerConfig.Labels, providerConfig.AnyLabels, providerConfig.NotLabels), httpClient)
+	}
+	if generatorConfig.AzureDevOps != nil {
+		providerConfig := generatorConfig.AzureDevOps
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.TokenRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret token: %v", err)
+		}
+		return pullrequest.NewAzureDevOpsService(ctx, token, providerConfig.API, providerConfig.Organization, providerConfig.Project, providerConfig.Repo, providerConfig.TargetBranch, getLabelFilter(providerConfig.Labels, providerConfig.AnyLabels, providerConfig.NotLabels), providerConfig.ExcludeDrafts, httpClient)
+	}
+	if generatorConfig.GitLab != nil {
+		providerConfig := generatorConfig.GitLab
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.TokenRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret token: %v", err)
+		}
+		return pullrequest.NewGitLabService(ctx, token, providerConfig.API, providerConfig.Project, getLabelFilter(providerConfig.Labels, providerConfig.AnyLabels, providerConfig.NotLabels), providerConfig.ExcludeDrafts, httpClient)
 	}
 	return nil, fmt.Errorf("no Pull Request provider implementation configured")
 }
 
-// getSecretRef gets the value of the key for the specified Secret resource.
-func (g *PullRequestGenerator) getSecretRef(ctx context.Context, ref *argoprojiov1alpha1.SecretRef, namespace string) (string, error) {
-	if ref == nil {
-		return "", nil
-	}
-
-	secret := &corev1.Secret{}
-	err := g.client.Get(
-		ctx,
-		client.ObjectKey{
-			Name:      ref.SecretName,
-			Namespace: namespace,
-		},
-		secret)
-	if err != nil {
-		return "", fmt.Errorf("error fetching secret %s/%s: %v", namespace, ref.SecretName, err)
-	}
-	tokenBytes, ok := secret.Data[ref.Key]
-	if !ok {
-		return "", fmt.Errorf("key %q in secret %s/%s not found", ref.Key, namespace, ref.SecretName)
+// getLabelFilter builds the label filter for a provider from its configured labels, anyLabels and notLabels.
+func getLabelFilter(labels, anyLabels, notLabels []string) pullrequest.LabelFilter {
+	return pullrequest.LabelFilter{
+		Labels:    labels,
+		AnyLabels: anyLabels,
+		NotLabels: notLabels,
 	}
-	return string(tokenBytes), nil
 }
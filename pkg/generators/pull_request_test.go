@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -12,14 +13,16 @@ import (
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
 	pullrequest "github.com/argoproj-labs/applicationset/pkg/services/pull_request"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
 func TestPullRequestGithubGenerateParams(t *testing.T) {
 	ctx := context.Background()
 	cases := []struct {
-		selectFunc  func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error)
-		expected    []map[string]string
-		expectedErr error
+		selectFunc     func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error)
+		shortSHALength *int
+		expected       []map[string]interface{}
+		expectedErr    error
 	}{
 		{
 			selectFunc: func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error) {
@@ -27,19 +30,28 @@ func TestPullRequestGithubGenerateParams(t *testing.T) {
 					ctx,
 					[]*pullrequest.PullRequest{
 						&pullrequest.PullRequest{
-							Number:  1,
-							Branch:  "branch1",
-							HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958",
+							Number:    1,
+							Branch:    "branch1",
+							HeadSHA:   "089d92cbf9ff857a39e6feccd32798ca700fb958",
+							Labels:    []string{"preview", "team-a"},
+							Author:    "alice",
+							Title:     "Add\n  feature\tone",
+							CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC),
 						},
 					},
 					nil,
 				)
 			},
-			expected: []map[string]string{
+			expected: []map[string]interface{}{
 				{
-					"number":   "1",
-					"branch":   "branch1",
-					"head_sha": "089d92cbf9ff857a39e6feccd32798ca700fb958",
+					"number":         "1",
+					"branch":         "branch1",
+					"head_sha":       "089d92cbf9ff857a39e6feccd32798ca700fb958",
+					"head_short_sha": "089d92c",
+					"author":         "alice",
+					"title":          "Add feature one",
+					"labels":         "preview,team-a",
+					"created_at":     "2021-09-01T10:00:00Z",
 				},
 			},
 			expectedErr: nil,
@@ -55,6 +67,43 @@ func TestPullRequestGithubGenerateParams(t *testing.T) {
 			expected:    nil,
 			expectedErr: errors.New("error listing repos: fake error"),
 		},
+		{
+			selectFunc: func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error) {
+				return pullrequest.NewFakeService(
+					ctx,
+					[]*pullrequest.PullRequest{
+						&pullrequest.PullRequest{
+							Number:  2,
+							Branch:  "branch2",
+							HeadSHA: "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d",
+						},
+					},
+					nil,
+				)
+			},
+			shortSHALength: intPtr(10),
+			expected: []map[string]interface{}{
+				{
+					"number":         "2",
+					"branch":         "branch2",
+					"head_sha":       "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d",
+					"head_short_sha": "5a5f5ecb5b",
+					"author":         "",
+					"title":          "",
+					"labels":         "",
+					"created_at":     "",
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			selectFunc: func(context.Context, *argoprojiov1alpha1.PullRequestGenerator, *argoprojiov1alpha1.ApplicationSet) (pullrequest.PullRequestService, error) {
+				return pullrequest.NewFakeService(ctx, nil, nil)
+			},
+			shortSHALength: intPtr(0),
+			expected:       nil,
+			expectedErr:    errors.New("invalid shortSHALength 0: must be greater than 0"),
+		},
 	}
 
 	for _, c := range cases {
@@ -62,9 +111,11 @@ func TestPullRequestGithubGenerateParams(t *testing.T) {
 			selectServiceProviderFunc: c.selectFunc,
 		}
 		generatorConfig := argoprojiov1alpha1.ApplicationSetGenerator{
-			PullRequest: &argoprojiov1alpha1.PullRequestGenerator{},
+			PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+				ShortSHALength: c.shortSHALength,
+			},
 		}
-		got, gotErr := gen.GenerateParams(&generatorConfig, nil)
+		got, gotErr := gen.GenerateParams(context.Background(), &generatorConfig, nil)
 		assert.Equal(t, c.expectedErr, gotErr)
 		assert.ElementsMatch(t, c.expected, got)
 	}
@@ -124,7 +175,7 @@ func TestPullRequestGetSecretRef(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			token, err := gen.getSecretRef(ctx, c.ref, c.namespace)
+			token, err := utils.GetSecretRef(ctx, gen.client, c.ref, c.namespace)
 			if c.hasError {
 				assert.NotNil(t, err)
 			} else {
@@ -134,3 +185,33 @@ func TestPullRequestGetSecretRef(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestSelectGithubAppServiceProvider(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: "test"},
+		Data: map[string][]byte{
+			"privateKey": []byte("not a key"),
+		},
+	}
+	gen := &PullRequestGenerator{client: fake.NewClientBuilder().WithObjects(secret).Build()}
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Namespace: "test"}}
+
+	generatorConfig := func(ref argoprojiov1alpha1.SecretRef) *argoprojiov1alpha1.PullRequestGenerator {
+		return &argoprojiov1alpha1.PullRequestGenerator{Github: &argoprojiov1alpha1.PullRequestGeneratorGithub{
+			Owner:     "argoproj-labs",
+			Repo:      "applicationset",
+			GithubApp: &argoprojiov1alpha1.GithubAppRef{AppID: 123, InstallationID: 456, PrivateKeyRef: ref},
+		}}
+	}
+
+	_, err := gen.selectServiceProvider(context.Background(), generatorConfig(argoprojiov1alpha1.SecretRef{SecretName: "other", Key: "privateKey"}), appSet)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error fetching Github App private key")
+
+	_, err = gen.selectServiceProvider(context.Background(), generatorConfig(argoprojiov1alpha1.SecretRef{SecretName: "github-app", Key: "privateKey"}), appSet)
+	assert.EqualError(t, err, "error parsing the GitHub App private key: no PEM block found")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,184 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// combinationGenerators are the generators combining other generators, which a Registry builds from its generators.
+var combinationGenerators = map[string]bool{"Matrix": true, "Merge": true, "Union": true}
+
+// Registry is the set of generators of an ApplicationSet engine, for the programs embedding it: it holds the terminal
+// generators by their Go names, e.g. SCMProvider, from which it builds the Matrix, Merge and Union generators, and the
+// in-process plugins, which generate the params of the Plugin generators referencing them by their configMapRef. A
+// Registry is not safe for concurrent use: the generators are registered before the Generators are built.
+type Registry struct {
+	generators map[string]Generator
+	plugins    map[string]Generator
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		generators: map[string]Generator{},
+		plugins:    map[string]Generator{},
+	}
+}
+
+// NewStandardRegistry returns a Registry of the standard generators of the ApplicationSet controller.
+func NewStandardRegistry(ctx context.Context, clients Clients) *Registry {
+	r := NewRegistry()
+	for name, g := range map[string]Generator{
+		"List":                    NewListGenerator(),
+		"Clusters":                NewClusterGenerator(clients.Client, ctx, clients.KubeClientset, clients.Namespace),
+		"Git":                     NewGitGenerator(clients.Repos),
+		"SCMProvider":             NewSCMProviderGenerator(clients.Client),
+		"ClusterDecisionResource": NewDuckTypeGenerator(ctx, clients.DynamicClient, clients.KubeClientset, clients.Namespace),
+		"PullRequest":             NewPullRequestGenerator(clients.Client),
+		"Plugin":                  NewPluginGenerator(clients.Client, clients.Namespace),
+		"HTTP":                    NewHTTPGenerator(clients.Client),
+		"Secret":                  NewSecretGenerator(clients.Client, clients.SensitiveValues),
+		"KubernetesResource":      NewKubernetesResourceGenerator(clients.DynamicClient, clients.RESTMapper),
+		"AzureSubscriptions":      NewAzureSubscriptionsGenerator(clients.Client),
+		"Vault":                   NewVaultGenerator(clients.Client, clients.SensitiveValues),
+		"Consul":                  NewConsulGenerator(clients.Client),
+		"HelmRepository":          NewHelmRepositoryGenerator(clients.Client),
+		"Bucket":                  NewBucketGenerator(clients.Client),
+		"SQL":                     NewSQLGenerator(clients.Client),
+		"TerraformState":          NewTerraformStateGenerator(clients.Client, clients.SensitiveValues),
+	} {
+		r.generators[name] = g
+	}
+	return r
+}
+
+// Register sets the generator of the ApplicationSet generator field with the Go name, e.g. Git, replacing the standard
+// generator, if any. The Matrix, Merge and Union generators can't be registered, since the Registry builds them.
+func (r *Registry) Register(name string, g Generator) error {
+	if combinationGenerators[name] {
+		return fmt.Errorf("the %s generator is built from the other generators, it can't be registered", name)
+	}
+	if _, ok := utils.GeneratorJSONNames()[name]; !ok {
+		return fmt.Errorf("unknown generator %q, the generators are %s", name, strings.Join(registrableGeneratorNames(), ", "))
+	}
+	if g == nil {
+		return fmt.Errorf("the %s generator is nil", name)
+	}
+	r.generators[name] = g
+	return nil
+}
+
+// RegisterPlugin sets an in-process plugin, which generates the params of the Plugin generators whose configMapRef is
+// the name, rather than the plugin service configured by the ConfigMap of this name. The Plugin generators referencing
+// other ConfigMaps still query their plugin services.
+func (r *Registry) RegisterPlugin(name string, g Generator) error {
+	if name == "" {
+		return fmt.Errorf("the name of the plugin is empty")
+	}
+	if g == nil {
+		return fmt.Errorf("the %s plugin is nil", name)
+	}
+	r.plugins[name] = g
+	return nil
+}
+
+// Generators returns the top-level generators, by their Go names, as expected by Transform and the
+// ApplicationSetReconciler. The Matrix, Merge and Union generators combine the other generators, and one level of
+// nested Matrix, Merge and Union generators. The disabled generators are replaced by generators which fail, rather than
+// removed, so that the Applications of the ApplicationSets using them are not deleted. The generators registered after
+// this call are not part of the returned generators.
+func (r *Registry) Generators(disabled map[string]bool) map[string]Generator {
+	terminalGenerators := map[string]Generator{}
+	for name, g := range r.generators {
+		terminalGenerators[name] = g
+	}
+	if len(r.plugins) > 0 {
+		plugins := map[string]Generator{}
+		for name, g := range r.plugins {
+			plugins[name] = g
+		}
+		terminalGenerators["Plugin"] = &pluginRouter{services: terminalGenerators["Plugin"], plugins: plugins}
+	}
+	terminalGenerators = DisableGenerators(terminalGenerators, disabled)
+
+	nestedGenerators := DisableGenerators(withCombinations(terminalGenerators, terminalGenerators), disabled)
+	return DisableGenerators(withCombinations(terminalGenerators, nestedGenerators), disabled)
+}
+
+// withCombinations returns the terminal generators, with Matrix, Merge and Union generators combining the child
+// generators.
+func withCombinations(terminalGenerators map[string]Generator, childGenerators map[string]Generator) map[string]Generator {
+	res := map[string]Generator{
+		"Matrix": NewMatrixGenerator(childGenerators),
+		"Merge":  NewMergeGenerator(childGenerators),
+		"Union":  NewUnionGenerator(childGenerators),
+	}
+	for name, generator := range terminalGenerators {
+		res[name] = generator
+	}
+	return res
+}
+
+// registrableGeneratorNames returns the sorted Go names of the generators which can be registered.
+func registrableGeneratorNames() []string {
+	var res []string
+	for name := range utils.GeneratorJSONNames() {
+		if !combinationGenerators[name] {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// pluginRouter is the Plugin generator of a Registry with in-process plugins: it routes the Plugin generators to the
+// in-process plugin registered with their configMapRef, if any, and to the Plugin generator querying the plugin
+// services otherwise.
+type pluginRouter struct {
+	services Generator
+	plugins  map[string]Generator
+}
+
+var _ Generator = (*pluginRouter)(nil)
+
+func (g *pluginRouter) generator(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) (Generator, error) {
+	if appSetGenerator == nil || appSetGenerator.Plugin == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+	if plugin, ok := g.plugins[appSetGenerator.Plugin.ConfigMapRef]; ok {
+		return plugin, nil
+	}
+	if g.services == nil {
+		return nil, fmt.Errorf("no plugin %q is registered", appSetGenerator.Plugin.ConfigMapRef)
+	}
+	return g.services, nil
+}
+
+func (g *pluginRouter) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	generator, err := g.generator(appSetGenerator)
+	if err != nil {
+		return nil, err
+	}
+	return generator.GenerateParams(ctx, appSetGenerator, applicationSetInfo)
+}
+
+func (g *pluginRouter) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	generator, err := g.generator(appSetGenerator)
+	if err != nil {
+		return NoRequeueAfter
+	}
+	return generator.GetRequeueAfter(appSetGenerator)
+}
+
+func (g *pluginRouter) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	if appSetGenerator == nil || appSetGenerator.Plugin == nil {
+		return &argoprojiov1alpha1.ApplicationSetTemplate{}
+	}
+	return &appSetGenerator.Plugin.Template
+}
@@ -0,0 +1,87 @@
+package generators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	assert.EqualError(t, r.Register("Matrix", NewListGenerator()), "the Matrix generator is built from the other generators, it can't be registered")
+	assert.Error(t, r.Register("Custom", NewListGenerator()))
+	assert.EqualError(t, r.Register("List", nil), "the List generator is nil")
+	assert.EqualError(t, r.RegisterPlugin("", NewListGenerator()), "the name of the plugin is empty")
+
+	// a registered generator replaces the standard one, also as a child generator
+	git := &generatorMock{}
+	git.On("GetTemplate", mock.Anything).Return(&argoprojiov1alpha1.ApplicationSetTemplate{})
+	git.On("GenerateParams", mock.Anything, mock.Anything).Return([]map[string]interface{}{{"path": "apps/guestbook"}}, nil)
+	require.NoError(t, r.Register("List", NewListGenerator()))
+	require.NoError(t, r.Register("Git", git))
+	generators := r.Generators(nil)
+	assert.Contains(t, generators, "Matrix")
+	assert.NotContains(t, generators, "Clusters")
+
+	results, err := Transform(context.Background(), argoprojiov1alpha1.ApplicationSetGenerator{
+		Matrix: &argoprojiov1alpha1.MatrixGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{List: &argoprojiov1alpha1.ListGenerator{Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "staging"}`)}}}},
+				{Git: &argoprojiov1alpha1.GitGenerator{RepoURL: "https://example.com/apps.git"}},
+			},
+		},
+	}, generators, argoprojiov1alpha1.ApplicationSetTemplate{}, &argoprojiov1alpha1.ApplicationSet{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Params, 1)
+	assert.Equal(t, "staging", results[0].Params[0]["cluster"])
+	assert.Equal(t, "apps/guestbook", results[0].Params[0]["path"])
+}
+
+func TestRegistryRegisterPlugin(t *testing.T) {
+	services := &generatorMock{}
+	services.On("GenerateParams", mock.Anything, mock.Anything).Return([]map[string]interface{}{{"from": "service"}}, nil)
+	services.On("GetRequeueAfter", mock.Anything).Return(DefaultPluginRequeueAfterSeconds)
+	inProcess := &generatorMock{}
+	inProcess.On("GenerateParams", mock.Anything, mock.Anything).Return([]map[string]interface{}{{"from": "in-process"}}, nil)
+	inProcess.On("GetRequeueAfter", mock.Anything).Return(time.Minute)
+
+	r := NewRegistry()
+	require.NoError(t, r.Register("Plugin", services))
+	require.NoError(t, r.RegisterPlugin("inventory", inProcess))
+	generators := r.Generators(nil)
+
+	plugin := func(configMapRef string) argoprojiov1alpha1.ApplicationSetGenerator {
+		return argoprojiov1alpha1.ApplicationSetGenerator{Plugin: &argoprojiov1alpha1.PluginGenerator{
+			ConfigMapRef: configMapRef,
+			Template:     argoprojiov1alpha1.ApplicationSetTemplate{ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{from}}"}},
+		}}
+	}
+	for configMapRef, expected := range map[string]string{"inventory": "in-process", "other-plugin": "service"} {
+		appSetGenerator := plugin(configMapRef)
+		results, err := Transform(context.Background(), appSetGenerator, generators, argoprojiov1alpha1.ApplicationSetTemplate{}, &argoprojiov1alpha1.ApplicationSet{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, expected, results[0].Params[0]["from"])
+		assert.Equal(t, "{{from}}", results[0].Template.Name)
+	}
+	inventory := plugin("inventory")
+	assert.Equal(t, time.Minute, generators["Plugin"].GetRequeueAfter(&inventory))
+
+	// the in-process plugins are disabled with the Plugin generator
+	_, err := Transform(context.Background(), plugin("inventory"), r.Generators(map[string]bool{"Plugin": true}), argoprojiov1alpha1.ApplicationSetTemplate{}, &argoprojiov1alpha1.ApplicationSet{})
+	assert.EqualError(t, err, "the plugin generator is disabled by the ApplicationSet controller")
+
+	// without a Plugin generator, only the in-process plugins are supported
+	r = NewRegistry()
+	require.NoError(t, r.RegisterPlugin("inventory", inProcess))
+	_, err = Transform(context.Background(), plugin("other-plugin"), r.Generators(nil), argoprojiov1alpha1.ApplicationSetTemplate{}, &argoprojiov1alpha1.ApplicationSet{})
+	assert.EqualError(t, err, `no plugin "other-plugin" is registered`)
+}
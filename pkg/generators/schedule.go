@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// IsScheduleActive returns true if one of the windows of the schedule is active at the given time.
+func IsScheduleActive(schedule *argoprojiov1alpha1.GeneratorSchedule, now time.Time) (bool, error) {
+	active, _, err := evaluateSchedule(schedule, now)
+	return active, err
+}
+
+// GetScheduleRequeueAfter returns the time until the next start or end of a window of the schedule, so that the
+// parameters are generated again as soon as the schedule changes. NoRequeueAfter is returned if the schedule is invalid.
+func GetScheduleRequeueAfter(schedule *argoprojiov1alpha1.GeneratorSchedule, now time.Time) time.Duration {
+	_, next, err := evaluateSchedule(schedule, now)
+	if err != nil {
+		return NoRequeueAfter
+	}
+	return next
+}
+
+// evaluateSchedule returns whether a window of the schedule is active at the given time, and the time until the next
+// start or end of a window.
+func evaluateSchedule(schedule *argoprojiov1alpha1.GeneratorSchedule, now time.Time) (bool, time.Duration, error) {
+	if len(schedule.Windows) == 0 {
+		return false, NoRequeueAfter, fmt.Errorf("schedule requires at least one window")
+	}
+
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return false, NoRequeueAfter, fmt.Errorf("invalid schedule time zone %q: %v", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(location)
+
+	active := false
+	var next time.Duration
+	for _, window := range schedule.Windows {
+		cronSchedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return false, NoRequeueAfter, fmt.Errorf("invalid schedule %q: %v", window.Schedule, err)
+		}
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return false, NoRequeueAfter, fmt.Errorf("invalid schedule duration %q: %v", window.Duration, err)
+		}
+		if duration <= 0 {
+			return false, NoRequeueAfter, fmt.Errorf("invalid schedule duration %q: must be positive", window.Duration)
+		}
+
+		// The window is active if it started less than its duration ago.
+		transition := cronSchedule.Next(now.Add(-duration))
+		if transition.IsZero() {
+			// The schedule never fires
+			continue
+		}
+		if !transition.After(now) {
+			active = true
+			transition = transition.Add(duration)
+		}
+		if until := transition.Sub(now); next == 0 || until < next {
+			next = until
+		}
+	}
+
+	return active, next, nil
+}
@@ -0,0 +1,151 @@
+package generators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	// Working hours, and Saturday mornings
+	workingHours := []argoprojiov1alpha1.ScheduleWindow{
+		{Schedule: "0 8 * * 1-5", Duration: "10h"},
+		{Schedule: "0 9 * * 6", Duration: "3h"},
+	}
+
+	cases := []struct {
+		name           string
+		schedule       argoprojiov1alpha1.GeneratorSchedule
+		now            time.Time
+		expectedActive bool
+		expectedNext   time.Duration
+		expectedError  string
+	}{
+		{
+			name:           "inside a window",
+			schedule:       argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours},
+			now:            time.Date(2021, 12, 15, 10, 30, 0, 0, time.UTC), // Wednesday
+			expectedActive: true,
+			expectedNext:   7*time.Hour + 30*time.Minute,
+		},
+		{
+			name:           "at the start of a window",
+			schedule:       argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours},
+			now:            time.Date(2021, 12, 15, 8, 0, 0, 0, time.UTC),
+			expectedActive: true,
+			expectedNext:   10 * time.Hour,
+		},
+		{
+			name:           "at the end of a window",
+			schedule:       argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours},
+			now:            time.Date(2021, 12, 15, 18, 0, 0, 0, time.UTC),
+			expectedActive: false,
+			expectedNext:   14 * time.Hour,
+		},
+		{
+			name:           "before the next window",
+			schedule:       argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours},
+			now:            time.Date(2021, 12, 17, 20, 0, 0, 0, time.UTC), // Friday
+			expectedActive: false,
+			expectedNext:   13 * time.Hour,
+		},
+		{
+			name:           "window of another time zone",
+			schedule:       argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours, TimeZone: "America/New_York"},
+			now:            time.Date(2021, 12, 15, 10, 30, 0, 0, time.UTC), // 5:30 in New York
+			expectedActive: false,
+			expectedNext:   2*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "schedule which never fires",
+			schedule: argoprojiov1alpha1.GeneratorSchedule{Windows: []argoprojiov1alpha1.ScheduleWindow{
+				{Schedule: "0 0 30 2 *", Duration: "1h"},
+			}},
+			now:            time.Date(2021, 12, 15, 10, 30, 0, 0, time.UTC),
+			expectedActive: false,
+			expectedNext:   NoRequeueAfter,
+		},
+		{
+			name:          "no windows",
+			schedule:      argoprojiov1alpha1.GeneratorSchedule{},
+			expectedError: "schedule requires at least one window",
+		},
+		{
+			name: "invalid cron expression",
+			schedule: argoprojiov1alpha1.GeneratorSchedule{Windows: []argoprojiov1alpha1.ScheduleWindow{
+				{Schedule: "0 8 * *", Duration: "1h"},
+			}},
+			expectedError: "invalid schedule \"0 8 * *\": expected exactly 5 fields, found 4: [0 8 * *]",
+		},
+		{
+			name: "negative duration",
+			schedule: argoprojiov1alpha1.GeneratorSchedule{Windows: []argoprojiov1alpha1.ScheduleWindow{
+				{Schedule: "0 8 * * *", Duration: "-1h"},
+			}},
+			expectedError: "invalid schedule duration \"-1h\": must be positive",
+		},
+		{
+			name:          "unknown time zone",
+			schedule:      argoprojiov1alpha1.GeneratorSchedule{Windows: workingHours, TimeZone: "Mars/Olympus_Mons"},
+			expectedError: "invalid schedule time zone \"Mars/Olympus_Mons\": unknown time zone Mars/Olympus_Mons",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			active, next, err := evaluateSchedule(&cc.schedule, cc.now)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedActive, active)
+			assert.Equal(t, cc.expectedNext, next)
+		})
+	}
+}
+
+func TestTransformWithSchedule(t *testing.T) {
+	listGenerator := &argoprojiov1alpha1.ListGenerator{
+		Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster": "staging"}`)}},
+	}
+
+	cases := []struct {
+		name     string
+		windows  []argoprojiov1alpha1.ScheduleWindow
+		expected []map[string]interface{}
+	}{
+		{
+			name:     "inside a window",
+			windows:  []argoprojiov1alpha1.ScheduleWindow{{Schedule: "* * * * *", Duration: "1h"}},
+			expected: []map[string]interface{}{{"cluster": "staging", "applicationset.name": "", "applicationset.namespace": ""}},
+		},
+		{
+			name:     "outside of the windows",
+			windows:  []argoprojiov1alpha1.ScheduleWindow{{Schedule: "0 0 30 2 *", Duration: "1h"}},
+			expected: []map[string]interface{}{},
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			requestedGenerator := argoprojiov1alpha1.ApplicationSetGenerator{
+				List:     listGenerator,
+				Schedule: &argoprojiov1alpha1.GeneratorSchedule{Windows: cc.windows},
+			}
+
+			results, err := Transform(context.Background(), requestedGenerator, map[string]Generator{"List": NewListGenerator()}, argoprojiov1alpha1.ApplicationSetTemplate{}, &argoprojiov1alpha1.ApplicationSet{})
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, cc.expected, results[0].Params)
+			}
+		})
+	}
+}
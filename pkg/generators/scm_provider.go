@@ -6,11 +6,12 @@ import (
 	"strings"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/github_app"
 	"github.com/argoproj-labs/applicationset/pkg/services/scm_provider"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
 var _ Generator = (*SCMProviderGenerator)(nil)
@@ -29,6 +30,12 @@ func NewSCMProviderGenerator(client client.Client) Generator {
 	return &SCMProviderGenerator{client: client}
 }
 
+// NewFixedSCMProviderGenerator returns an SCM Provider generator listing the repositories of the given provider,
+// whatever the provider configured by the ApplicationSets, e.g. to render ApplicationSets offline.
+func NewFixedSCMProviderGenerator(provider scm_provider.SCMProviderService) Generator {
+	return &SCMProviderGenerator{overrideProvider: provider}
+}
+
 func (g *SCMProviderGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
 	// Return a requeue default of 30 minutes, if no default is specified.
 
@@ -43,7 +50,7 @@ func (g *SCMProviderGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.A
 	return &appSetGenerator.SCMProvider.Template
 }
 
-func (g *SCMProviderGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]string, error) {
+func (g *SCMProviderGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
 	if appSetGenerator == nil {
 		return nil, EmptyAppSetGeneratorError
 	}
@@ -52,33 +59,17 @@ func (g *SCMProviderGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha
 		return nil, EmptyAppSetGeneratorError
 	}
 
-	ctx := context.Background()
-
 	// Create the SCM provider helper.
 	providerConfig := appSetGenerator.SCMProvider
 	var provider scm_provider.SCMProviderService
 	if g.overrideProvider != nil {
 		provider = g.overrideProvider
-	} else if providerConfig.Github != nil {
-		token, err := g.getSecretRef(ctx, providerConfig.Github.TokenRef, applicationSetInfo.Namespace)
-		if err != nil {
-			return nil, fmt.Errorf("error fetching Github token: %v", err)
-		}
-		provider, err = scm_provider.NewGithubProvider(ctx, providerConfig.Github.Organization, token, providerConfig.Github.API, providerConfig.Github.AllBranches)
-		if err != nil {
-			return nil, fmt.Errorf("error initializing Github service: %v", err)
-		}
-	} else if providerConfig.Gitlab != nil {
-		token, err := g.getSecretRef(ctx, providerConfig.Gitlab.TokenRef, applicationSetInfo.Namespace)
-		if err != nil {
-			return nil, fmt.Errorf("error fetching Gitlab token: %v", err)
-		}
-		provider, err = scm_provider.NewGitlabProvider(ctx, providerConfig.Gitlab.Group, token, providerConfig.Gitlab.API, providerConfig.Gitlab.AllBranches, providerConfig.Gitlab.IncludeSubgroups)
+	} else {
+		var err error
+		provider, err = g.selectServiceProvider(ctx, providerConfig, applicationSetInfo)
 		if err != nil {
-			return nil, fmt.Errorf("error initializing Gitlab service: %v", err)
+			return nil, err
 		}
-	} else {
-		return nil, fmt.Errorf("no SCM provider implementation configured")
 	}
 
 	// Find all the available repos.
@@ -86,9 +77,9 @@ func (g *SCMProviderGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha
 	if err != nil {
 		return nil, fmt.Errorf("error listing repos: %v", err)
 	}
-	params := make([]map[string]string, 0, len(repos))
+	params := make([]map[string]interface{}, 0, len(repos))
 	for _, repo := range repos {
-		params = append(params, map[string]string{
+		params = append(params, map[string]interface{}{
 			"organization": repo.Organization,
 			"repository":   repo.Repository,
 			"url":          repo.URL,
@@ -100,25 +91,50 @@ func (g *SCMProviderGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha
 	return params, nil
 }
 
-func (g *SCMProviderGenerator) getSecretRef(ctx context.Context, ref *argoprojiov1alpha1.SecretRef, namespace string) (string, error) {
-	if ref == nil {
-		return "", nil
+// selectServiceProvider creates the SCM provider of the configuration
+func (g *SCMProviderGenerator) selectServiceProvider(ctx context.Context, providerConfig *argoprojiov1alpha1.SCMProviderGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) (scm_provider.SCMProviderService, error) {
+	caData, err := utils.GetSecretRef(ctx, g.client, providerConfig.CARef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret CA certificates: %v", err)
 	}
-
-	secret := &corev1.Secret{}
-	err := g.client.Get(
-		ctx,
-		client.ObjectKey{
-			Name:      ref.SecretName,
-			Namespace: namespace,
-		},
-		secret)
+	httpClient, err := utils.NewHTTPClient(utils.HTTPClientOptions{ProxyURL: providerConfig.Proxy, Insecure: providerConfig.Insecure, CAData: caData})
 	if err != nil {
-		return "", fmt.Errorf("error fetching secret %s/%s: %v", namespace, ref.SecretName, err)
+		return nil, fmt.Errorf("error creating the HTTP client: %v", err)
+	}
+	if providerConfig.Github != nil && providerConfig.Github.GithubApp != nil {
+		githubApp := providerConfig.Github.GithubApp
+		privateKey, err := utils.GetSecretRef(ctx, g.client, &githubApp.PrivateKeyRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Github App private key: %v", err)
+		}
+		auth := github_app.Authentication{AppID: githubApp.AppID, InstallationID: githubApp.InstallationID, PrivateKey: privateKey}
+		provider, err := scm_provider.NewGithubAppProvider(ctx, providerConfig.Github.Organization, auth, providerConfig.Github.API, providerConfig.Github.AllBranches, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Github service: %v", err)
+		}
+		return provider, nil
+	}
+	if providerConfig.Github != nil {
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.Github.TokenRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Github token: %v", err)
+		}
+		provider, err := scm_provider.NewGithubProvider(ctx, providerConfig.Github.Organization, token, providerConfig.Github.API, providerConfig.Github.AllBranches, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Github service: %v", err)
+		}
+		return provider, nil
 	}
-	tokenBytes, ok := secret.Data[ref.Key]
-	if !ok {
-		return "", fmt.Errorf("key %q in secret %s/%s not found", ref.Key, namespace, ref.SecretName)
+	if providerConfig.Gitlab != nil {
+		token, err := utils.GetSecretRef(ctx, g.client, providerConfig.Gitlab.TokenRef, applicationSetInfo.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Gitlab token: %v", err)
+		}
+		provider, err := scm_provider.NewGitlabProvider(ctx, providerConfig.Gitlab.Group, token, providerConfig.Gitlab.API, providerConfig.Gitlab.AllBranches, providerConfig.Gitlab.IncludeSubgroups, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Gitlab service: %v", err)
+		}
+		return provider, nil
 	}
-	return string(tokenBytes), nil
+	return nil, fmt.Errorf("no SCM provider implementation configured")
 }
@@ -11,6 +11,7 @@ import (
 
 	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
 	"github.com/argoproj-labs/applicationset/pkg/services/scm_provider"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
 func TestSCMProviderGetSecretRef(t *testing.T) {
@@ -67,7 +68,7 @@ func TestSCMProviderGetSecretRef(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			token, err := gen.getSecretRef(ctx, c.ref, c.namespace)
+			token, err := utils.GetSecretRef(ctx, gen.client, c.ref, c.namespace)
 			if c.hasError {
 				assert.NotNil(t, err)
 			} else {
@@ -100,7 +101,7 @@ func TestSCMProviderGenerateParams(t *testing.T) {
 		},
 	}
 	gen := &SCMProviderGenerator{overrideProvider: mockProvider}
-	params, err := gen.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+	params, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
 		SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{},
 	}, nil)
 	assert.Nil(t, err)
@@ -0,0 +1,143 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*SecretGenerator)(nil)
+
+// SecretGenerator generates parameters from the data of Secrets. The generated values are recorded as sensitive, so
+// that they are redacted from logs, events and the ApplicationSet status.
+type SecretGenerator struct {
+	client          client.Client
+	sensitiveValues *utils.SensitiveValues
+}
+
+func NewSecretGenerator(client client.Client, sensitiveValues *utils.SensitiveValues) Generator {
+	g := &SecretGenerator{
+		client:          client,
+		sensitiveValues: sensitiveValues,
+	}
+	return g
+}
+
+func (g *SecretGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 3 minutes, if no override is specified.
+
+	if appSetGenerator.Secret.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Secret.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultRequeueAfterSeconds
+}
+
+func (g *SecretGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Secret.Template
+}
+
+func (g *SecretGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.Secret == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	secrets, err := g.getSecrets(ctx, appSetGenerator.Secret, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []map[string]interface{}{}
+	for _, secret := range secrets {
+		paramSets, err := getSecretParamSets(secret, appSetGenerator.Secret.Key, useGoTemplate(applicationSetInfo))
+		if err != nil {
+			return nil, err
+		}
+		for _, params := range paramSets {
+			for _, value := range params {
+				g.sensitiveValues.AddParam(applicationSetInfo.Namespace, applicationSetInfo.Name, value)
+			}
+			for key, value := range appSetGenerator.Secret.Values {
+				params[fmt.Sprintf("values.%s", key)] = value
+			}
+			res = append(res, params)
+		}
+	}
+
+	return res, nil
+}
+
+// getSecrets returns the Secret with the name of the generator, or the Secrets selected by its label selector.
+func (g *SecretGenerator) getSecrets(ctx context.Context, generatorConfig *argoprojiov1alpha1.SecretGenerator, namespace string) ([]corev1.Secret, error) {
+	if generatorConfig.Name != "" {
+		secret := corev1.Secret{}
+		err := g.client.Get(ctx, client.ObjectKey{Name: generatorConfig.Name, Namespace: namespace}, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching secret %s/%s: %v", namespace, generatorConfig.Name, err)
+		}
+		return []corev1.Secret{secret}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&generatorConfig.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret label selector: %v", err)
+	}
+	if selector.Empty() {
+		return nil, fmt.Errorf("secret generator requires either a name or a label selector")
+	}
+
+	secretList := &corev1.SecretList{}
+	err = g.client.List(ctx, secretList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets in %s: %v", namespace, err)
+	}
+	return secretList.Items, nil
+}
+
+// getSecretParamSets returns the parameter sets of the Secret: the data of the Secret if key is empty, or else the
+// list of parameter sets contained in the key. Errors never include the content of the Secret.
+func getSecretParamSets(secret corev1.Secret, key string, useGoTemplate bool) ([]map[string]interface{}, error) {
+	if key == "" {
+		params := make(map[string]interface{}, len(secret.Data))
+		for k, v := range secret.Data {
+			params[k] = string(v)
+		}
+		return []map[string]interface{}{params}, nil
+	}
+
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("key %q in secret %s/%s not found", key, secret.Namespace, secret.Name)
+	}
+	dataJSON, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("key %q in secret %s/%s is not valid JSON or YAML", key, secret.Namespace, secret.Name)
+	}
+	objects := []map[string]interface{}{}
+	if err := json.Unmarshal(dataJSON, &objects); err != nil {
+		return nil, fmt.Errorf("key %q in secret %s/%s must contain a list of objects", key, secret.Namespace, secret.Name)
+	}
+
+	paramSets := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		params, err := jsonObjectToParams(object, useGoTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("key %q in secret %s/%s contains an invalid parameter set", key, secret.Namespace, secret.Name)
+		}
+		paramSets = append(paramSets, params)
+	}
+	return paramSets, nil
+}
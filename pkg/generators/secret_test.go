@@ -0,0 +1,122 @@
+package generators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestSecretGenerateParams(t *testing.T) {
+	newSecret := func(name string, labels map[string]string, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd", Labels: labels},
+			Data:       map[string][]byte{},
+		}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+
+	cases := []struct {
+		name              string
+		generator         *argoprojiov1alpha1.SecretGenerator
+		expected          []map[string]interface{}
+		expectedError     string
+		expectedSensitive []string
+	}{
+		{
+			name: "secret data by name",
+			generator: &argoprojiov1alpha1.SecretGenerator{
+				Name:   "tenant-a",
+				Values: map[string]string{"team": "team-a"},
+			},
+			expected: []map[string]interface{}{
+				{"tenant": "alpha", "password": "s3cr3t-a", "values.team": "team-a"},
+			},
+			expectedSensitive: []string{"s3cr3t-a"},
+		},
+		{
+			name: "secrets data by label selector",
+			generator: &argoprojiov1alpha1.SecretGenerator{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			},
+			expected: []map[string]interface{}{
+				{"tenant": "alpha", "password": "s3cr3t-a"},
+				{"tenant": "bravo", "password": "s3cr3t-b"},
+			},
+			expectedSensitive: []string{"s3cr3t-a", "s3cr3t-b"},
+		},
+		{
+			name: "list of parameter sets in a key",
+			generator: &argoprojiov1alpha1.SecretGenerator{
+				Name: "tenants",
+				Key:  "tenants.yaml",
+			},
+			expected: []map[string]interface{}{
+				{"tenant": "charlie", "password": "s3cr3t-c", "replicas": "2"},
+				{"tenant": "delta", "password": "s3cr3t-d", "replicas": "3"},
+			},
+			expectedSensitive: []string{"s3cr3t-c", "s3cr3t-d"},
+		},
+		{
+			name: "missing key",
+			generator: &argoprojiov1alpha1.SecretGenerator{
+				Name: "tenants",
+				Key:  "missing",
+			},
+			expectedError: "key \"missing\" in secret argocd/tenants not found",
+		},
+		{
+			name: "key not containing a list",
+			generator: &argoprojiov1alpha1.SecretGenerator{
+				Name: "tenant-a",
+				Key:  "password",
+			},
+			expectedError: "key \"password\" in secret argocd/tenant-a must contain a list of objects",
+		},
+		{
+			name:          "missing name and label selector",
+			generator:     &argoprojiov1alpha1.SecretGenerator{},
+			expectedError: "secret generator requires either a name or a label selector",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newSecret("tenant-a", map[string]string{"tenant": "true"}, map[string]string{"tenant": "alpha", "password": "s3cr3t-a"}),
+		newSecret("tenant-b", map[string]string{"tenant": "true"}, map[string]string{"tenant": "bravo", "password": "s3cr3t-b"}),
+		newSecret("tenants", nil, map[string]string{"tenants.yaml": "- tenant: charlie\n  password: s3cr3t-c\n  replicas: 2\n- tenant: delta\n  password: s3cr3t-d\n  replicas: 3\n"}),
+	).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			sensitiveValues := utils.NewSensitiveValues()
+			gen := NewSecretGenerator(fakeClient, sensitiveValues)
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{Secret: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, cc.expected, got)
+			for _, value := range cc.expectedSensitive {
+				assert.Equal(t, utils.RedactedValue, sensitiveValues.Redact(value))
+			}
+			// The values of the generator are not read from the Secrets, and thus not sensitive.
+			assert.Equal(t, "team-a", sensitiveValues.Redact("team-a"))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package generators
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	// Register the supported database drivers
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*SQLGenerator)(nil)
+
+const (
+	DefaultSQLRequeueAfterSeconds = 3 * time.Minute
+	// sqlQueryTimeout is how long the query may run before being cancelled.
+	sqlQueryTimeout = 30 * time.Second
+)
+
+// sqlDrivers are the supported database drivers.
+var sqlDrivers = map[string]bool{
+	"postgres": true,
+	"mysql":    true,
+}
+
+// SQLGenerator generates parameters from the rows returned by a SQL query.
+type SQLGenerator struct {
+	client client.Client
+	// open opens the database, overridden in tests.
+	open func(driver, dsn string) (*sql.DB, error)
+}
+
+func NewSQLGenerator(client client.Client) Generator {
+	g := &SQLGenerator{
+		client: client,
+		open:   sql.Open,
+	}
+	return g
+}
+
+func (g *SQLGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 3 minutes, if no override is specified.
+
+	if appSetGenerator.SQL.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.SQL.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultSQLRequeueAfterSeconds
+}
+
+func (g *SQLGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.SQL.Template
+}
+
+func (g *SQLGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.SQL == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.SQL
+	if !sqlDrivers[generatorConfig.Driver] {
+		return nil, fmt.Errorf("unsupported sql driver %q: must be postgres or mysql", generatorConfig.Driver)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sqlQueryTimeout)
+	defer cancel()
+
+	dsn, err := utils.GetSecretRef(ctx, g.client, &generatorConfig.DSNRef, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Secret dsn: %v", err)
+	}
+	db, err := g.open(generatorConfig.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := querySQLRows(ctx, db, generatorConfig.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string]interface{}, 0, len(rows))
+	for _, params := range rows {
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+		res = append(res, params)
+	}
+
+	return res, nil
+}
+
+// querySQLRows runs the query in a read-only transaction, which is always rolled back, and returns a map of the
+// columns to their values for each row.
+func querySQLRows(ctx context.Context, db *sql.DB, query string) ([]map[string]interface{}, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting read-only transaction: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error running query: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns: %v", err)
+	}
+
+	res := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error reading row: %v", err)
+		}
+
+		params := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			params[column] = sqlValueToString(values[i])
+		}
+		res = append(res, params)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %v", err)
+	}
+	return res, nil
+}
+
+// sqlValueToString converts a value scanned from a column to a parameter value. NULL is converted to an empty string.
+func sqlValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,212 @@
+package generators
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// fakeSQLDriver is a database driver returning the rows of fakeSQLTables, which only allows read-only transactions.
+type fakeSQLDriver struct {
+	rolledBack int
+}
+
+var fakeSQLTables = map[string]struct {
+	columns []string
+	rows    [][]driver.Value
+}{
+	"SELECT name, replicas, created, owner FROM tenants": {
+		columns: []string{"name", "replicas", "created", "owner"},
+		rows: [][]driver.Value{
+			{[]byte("alpha"), int64(3), time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC), "team-alpha"},
+			{[]byte("beta"), int64(1), time.Date(2021, 12, 2, 10, 0, 0, 0, time.UTC), nil},
+		},
+	},
+}
+
+func (d *fakeSQLDriver) Open(dsn string) (driver.Conn, error) {
+	if dsn != "postgres://reader@db/tenants" {
+		return nil, fmt.Errorf("authentication failed")
+	}
+	return &fakeSQLConn{driver: d}, nil
+}
+
+type fakeSQLConn struct {
+	driver *fakeSQLDriver
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	table, ok := fakeSQLTables[query]
+	if !ok {
+		return nil, fmt.Errorf("syntax error")
+	}
+	return &fakeSQLStmt{columns: table.columns, rows: table.rows}, nil
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("only read-only transactions are allowed")
+}
+
+func (c *fakeSQLConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if !opts.ReadOnly {
+		return nil, fmt.Errorf("only read-only transactions are allowed")
+	}
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	return fmt.Errorf("read-only transactions must be rolled back")
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	c.driver.rolledBack++
+	return nil
+}
+
+type fakeSQLStmt struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (s *fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) NumInput() int {
+	return 0
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("read-only transaction")
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeSQLRows{columns: s.columns, rows: s.rows}, nil
+}
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var fakeSQL = &fakeSQLDriver{}
+
+func init() {
+	sql.Register("fake-sql", fakeSQL)
+}
+
+func TestSQLGenerateParams(t *testing.T) {
+	dsnRef := argoprojiov1alpha1.SecretRef{SecretName: "tenant-registry", Key: "dsn"}
+
+	cases := []struct {
+		name          string
+		generator     *argoprojiov1alpha1.SQLGenerator
+		expected      []map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "rows of a query",
+			generator: &argoprojiov1alpha1.SQLGenerator{
+				Driver: "postgres",
+				DSNRef: dsnRef,
+				Query:  "SELECT name, replicas, created, owner FROM tenants",
+				Values: map[string]string{"env": "production"},
+			},
+			expected: []map[string]interface{}{
+				{"name": "alpha", "replicas": "3", "created": "2021-12-01T10:00:00Z", "owner": "team-alpha", "values.env": "production"},
+				{"name": "beta", "replicas": "1", "created": "2021-12-02T10:00:00Z", "owner": "", "values.env": "production"},
+			},
+		},
+		{
+			name: "invalid query",
+			generator: &argoprojiov1alpha1.SQLGenerator{
+				Driver: "mysql",
+				DSNRef: dsnRef,
+				Query:  "SELEKT * FROM tenants",
+			},
+			expectedError: "error running query: syntax error",
+		},
+		{
+			name: "wrong credentials",
+			generator: &argoprojiov1alpha1.SQLGenerator{
+				Driver: "postgres",
+				DSNRef: argoprojiov1alpha1.SecretRef{SecretName: "tenant-registry", Key: "wrong-dsn"},
+				Query:  "SELECT name, replicas, created, owner FROM tenants",
+			},
+			expectedError: "error starting read-only transaction: authentication failed",
+		},
+		{
+			name: "unsupported driver",
+			generator: &argoprojiov1alpha1.SQLGenerator{
+				Driver: "sqlite3",
+				DSNRef: dsnRef,
+				Query:  "SELECT name, replicas, created, owner FROM tenants",
+			},
+			expectedError: "unsupported sql driver \"sqlite3\": must be postgres or mysql",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-registry", Namespace: "argocd"},
+		Data: map[string][]byte{
+			"dsn":       []byte("postgres://reader@db/tenants"),
+			"wrong-dsn": []byte("postgres://writer@db/tenants"),
+		},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			gen := NewSQLGenerator(fakeClient)
+			gen.(*SQLGenerator).open = func(driver, dsn string) (*sql.DB, error) {
+				return sql.Open("fake-sql", dsn)
+			}
+			rolledBack := fakeSQL.rolledBack
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{SQL: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+			assert.Equal(t, rolledBack+1, fakeSQL.rolledBack)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package generators
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/argoproj-labs/applicationset/pkg/services"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+// Clients are the clients and services used by the standard generators.
+type Clients struct {
+	// Client reads the clusters, and the Secrets and ConfigMaps referenced by the generators.
+	Client        client.Client
+	KubeClientset kubernetes.Interface
+	// DynamicClient and RESTMapper read the resources of the ClusterDecisionResource and KubernetesResource
+	// generators.
+	DynamicClient dynamic.Interface
+	RESTMapper    meta.RESTMapper
+	// Repos reads the files and directories of the repositories of the Git generators.
+	Repos services.Repos
+	// Namespace is the Argo CD namespace, of the clusters and of the ConfigMaps of the plugins.
+	Namespace string
+	// SensitiveValues, if set, collects the values generated from Secrets, so that they are redacted.
+	SensitiveValues *utils.SensitiveValues
+}
+
+// NewStandardGenerators returns the top-level generators of the ApplicationSet controller, by their Go names, e.g.
+// SCMProvider, as built by the Generators of the standard Registry.
+func NewStandardGenerators(ctx context.Context, clients Clients, disabled map[string]bool) map[string]Generator {
+	return NewStandardRegistry(ctx, clients).Generators(disabled)
+}
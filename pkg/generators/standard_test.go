@@ -0,0 +1,46 @@
+package generators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestNewStandardGenerators(t *testing.T) {
+	generators := NewStandardGenerators(context.Background(), Clients{Namespace: "argocd"}, map[string]bool{"HTTP": true})
+
+	// every generator of the ApplicationSets is supported
+	for name := range utils.GeneratorJSONNames() {
+		assert.Contains(t, generators, name)
+	}
+
+	// the disabled generators also fail when nested
+	appSet := &argoprojiov1alpha1.ApplicationSet{}
+	list := argoprojiov1alpha1.ApplicationSetNestedGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"a": "1"}`)}}},
+	}
+	_, err := Transform(context.Background(), argoprojiov1alpha1.ApplicationSetGenerator{
+		Matrix: &argoprojiov1alpha1.MatrixGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				list,
+				{HTTP: &argoprojiov1alpha1.HTTPGenerator{URL: "https://example.com"}},
+			},
+		},
+	}, generators, argoprojiov1alpha1.ApplicationSetTemplate{}, appSet)
+	assert.EqualError(t, err, "child generator returned an error on parameter generation: the http generator is disabled by the ApplicationSet controller")
+
+	results, err := Transform(context.Background(), argoprojiov1alpha1.ApplicationSetGenerator{
+		Matrix: &argoprojiov1alpha1.MatrixGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{list, list},
+		},
+	}, generators, argoprojiov1alpha1.ApplicationSetTemplate{}, appSet)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1", results[0].Params[0]["a"])
+}
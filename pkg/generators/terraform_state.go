@@ -0,0 +1,204 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/terraform_state"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*TerraformStateGenerator)(nil)
+
+const (
+	DefaultTerraformStateRequeueAfterSeconds = 30 * time.Minute
+)
+
+// TerraformStateGenerator generates parameters from the outputs of a Terraform state.
+type TerraformStateGenerator struct {
+	client client.Client
+	// sensitiveValues collects the values of the sensitive outputs, to redact them from the logs and events.
+	sensitiveValues *utils.SensitiveValues
+}
+
+func NewTerraformStateGenerator(client client.Client, sensitiveValues *utils.SensitiveValues) Generator {
+	g := &TerraformStateGenerator{
+		client:          client,
+		sensitiveValues: sensitiveValues,
+	}
+	return g
+}
+
+func (g *TerraformStateGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.TerraformState.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.TerraformState.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultTerraformStateRequeueAfterSeconds
+}
+
+func (g *TerraformStateGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.TerraformState.Template
+}
+
+func (g *TerraformStateGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.TerraformState == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.TerraformState
+
+	reader, err := g.newStateReader(ctx, generatorConfig, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := reader.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := generatorConfig.Outputs
+	if len(names) == 0 {
+		for name := range outputs {
+			names = append(names, name)
+		}
+	}
+	selected := map[string]interface{}{}
+	for _, name := range names {
+		output, ok := outputs[name]
+		if !ok {
+			return nil, fmt.Errorf("output %q not found in terraform state", name)
+		}
+		selected[name] = output.Value
+	}
+	outputParams, err := jsonObjectToParams(selected, useGoTemplate(applicationSetInfo))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if outputs[name].Sensitive {
+			g.sensitiveValues.AddParam(applicationSetInfo.Namespace, applicationSetInfo.Name, outputParams[name])
+		}
+	}
+
+	res := []map[string]interface{}{{}}
+	if generatorConfig.ForEach != "" {
+		output, ok := outputs[generatorConfig.ForEach]
+		if !ok {
+			return nil, fmt.Errorf("output %q not found in terraform state", generatorConfig.ForEach)
+		}
+		res, err = terraformOutputElementsToParams(output.Value, useGoTemplate(applicationSetInfo))
+		if err != nil {
+			return nil, fmt.Errorf("error processing output %q: %v", generatorConfig.ForEach, err)
+		}
+		if output.Sensitive {
+			for _, params := range res {
+				for key, value := range params {
+					if key != "key" {
+						g.sensitiveValues.AddParam(applicationSetInfo.Namespace, applicationSetInfo.Name, value)
+					}
+				}
+			}
+		}
+	}
+
+	for _, params := range res {
+		for name, value := range outputParams {
+			params[fmt.Sprintf("outputs.%s", name)] = value
+		}
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+	}
+
+	return res, nil
+}
+
+// terraformOutputElementsToParams returns a parameter set for each element of a list or map output value. The fields of
+// object elements are passed as parameters, other elements are passed as the value parameter. The key parameter is the
+// index of the element in the list, or its key in the map.
+func terraformOutputElementsToParams(value interface{}, useGoTemplate bool) ([]map[string]interface{}, error) {
+	keys := []string{}
+	elements := map[string]interface{}{}
+	switch v := value.(type) {
+	case []interface{}:
+		for i, element := range v {
+			keys = append(keys, strconv.Itoa(i))
+			elements[strconv.Itoa(i)] = element
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			keys = append(keys, key)
+			elements[key] = element
+		}
+		sort.Strings(keys)
+	default:
+		return nil, fmt.Errorf("must be a list or a map")
+	}
+
+	res := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		object, ok := elements[key].(map[string]interface{})
+		if !ok {
+			object = map[string]interface{}{"value": elements[key]}
+		}
+		params, err := jsonObjectToParams(object, useGoTemplate)
+		if err != nil {
+			return nil, err
+		}
+		params["key"] = key
+		res = append(res, params)
+	}
+	return res, nil
+}
+
+// newStateReader returns the reader of the state of the backend of the generator, with the credentials read from the
+// Secrets of the namespace.
+func (g *TerraformStateGenerator) newStateReader(ctx context.Context, generatorConfig *argoprojiov1alpha1.TerraformStateGenerator, namespace string) (terraform_state.StateReader, error) {
+	backends := 0
+	for _, set := range []bool{generatorConfig.S3 != nil, generatorConfig.GCS != nil, generatorConfig.HTTP != nil, generatorConfig.Remote != nil} {
+		if set {
+			backends++
+		}
+	}
+	if backends != 1 {
+		return nil, fmt.Errorf("terraform state generator requires exactly one of s3, gcs, http or remote")
+	}
+
+	switch {
+	case generatorConfig.HTTP != nil:
+		password, err := utils.GetSecretRef(ctx, g.client, generatorConfig.HTTP.PasswordRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret password: %v", err)
+		}
+		return terraform_state.NewHTTPReader(generatorConfig.HTTP.Address, generatorConfig.HTTP.Username, password)
+	case generatorConfig.Remote != nil:
+		remote := generatorConfig.Remote
+		token, err := utils.GetSecretRef(ctx, g.client, &remote.TokenRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching Secret token: %v", err)
+		}
+		return terraform_state.NewRemoteReader(remote.Address, remote.Organization, remote.Workspace, token)
+	}
+
+	if generatorConfig.Key == "" {
+		return nil, fmt.Errorf("key of the terraform state must be set")
+	}
+	b, err := (&BucketGenerator{client: g.client}).newBucket(ctx, generatorConfig.S3, generatorConfig.GCS, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return terraform_state.NewBucketReader(b, generatorConfig.Key), nil
+}
@@ -0,0 +1,156 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestTerraformStateGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "argocd" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{
+  "version": 4,
+  "outputs": {
+    "vpc_id": {"value": "vpc-0123", "type": "string"},
+    "subnets": {"value": {"a": "10.0.1.0/24", "b": "10.0.2.0/24"}, "type": ["map", "string"]},
+    "clusters": {"value": [{"name": "eu", "endpoint": "https://eu.example.com"}, {"name": "us", "endpoint": "https://us.example.com"}]},
+    "regions": {"value": ["eu-west-1", "us-east-1"]},
+    "db_password": {"value": "s3cr3t", "type": "string", "sensitive": true}
+  }
+}`)
+	}))
+	defer ts.Close()
+
+	httpBackend := func() *argoprojiov1alpha1.TerraformStateHTTP {
+		return &argoprojiov1alpha1.TerraformStateHTTP{
+			Address:     ts.URL,
+			Username:    "argocd",
+			PasswordRef: &argoprojiov1alpha1.SecretRef{SecretName: "terraform", Key: "password"},
+		}
+	}
+
+	cases := []struct {
+		name              string
+		generator         *argoprojiov1alpha1.TerraformStateGenerator
+		expected          []map[string]interface{}
+		expectedSensitive []string
+		expectedError     string
+	}{
+		{
+			name: "selected outputs",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP:    httpBackend(),
+				Outputs: []string{"vpc_id", "subnets", "db_password"},
+				Values:  map[string]string{"env": "production"},
+			},
+			expected: []map[string]interface{}{
+				{
+					"outputs.vpc_id":      "vpc-0123",
+					"outputs.subnets":     `{"a":"10.0.1.0/24","b":"10.0.2.0/24"}`,
+					"outputs.db_password": "s3cr3t",
+					"values.env":          "production",
+				},
+			},
+			expectedSensitive: []string{"s3cr3t"},
+		},
+		{
+			name: "for each element of a list of objects",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP:    httpBackend(),
+				Outputs: []string{"vpc_id"},
+				ForEach: "clusters",
+			},
+			expected: []map[string]interface{}{
+				{"key": "0", "name": "eu", "endpoint": "https://eu.example.com", "outputs.vpc_id": "vpc-0123"},
+				{"key": "1", "name": "us", "endpoint": "https://us.example.com", "outputs.vpc_id": "vpc-0123"},
+			},
+		},
+		{
+			name: "for each element of a map",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP:    httpBackend(),
+				Outputs: []string{"vpc_id"},
+				ForEach: "subnets",
+			},
+			expected: []map[string]interface{}{
+				{"key": "a", "value": "10.0.1.0/24", "outputs.vpc_id": "vpc-0123"},
+				{"key": "b", "value": "10.0.2.0/24", "outputs.vpc_id": "vpc-0123"},
+			},
+		},
+		{
+			name: "for each of a scalar output",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP:    httpBackend(),
+				ForEach: "vpc_id",
+			},
+			expectedError: "error processing output \"vpc_id\": must be a list or a map",
+		},
+		{
+			name: "unknown output",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP:    httpBackend(),
+				Outputs: []string{"cluster_endpoint"},
+			},
+			expectedError: "output \"cluster_endpoint\" not found in terraform state",
+		},
+		{
+			name: "several backends",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				HTTP: httpBackend(),
+				S3:   &argoprojiov1alpha1.BucketGeneratorS3{Bucket: "terraform", Region: "eu-west-1"},
+				Key:  "network/terraform.tfstate",
+			},
+			expectedError: "terraform state generator requires exactly one of s3, gcs, http or remote",
+		},
+		{
+			name: "missing key",
+			generator: &argoprojiov1alpha1.TerraformStateGenerator{
+				S3: &argoprojiov1alpha1.BucketGeneratorS3{Bucket: "terraform", Region: "eu-west-1"},
+			},
+			expectedError: "key of the terraform state must be set",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "terraform", Namespace: "argocd"},
+		Data:       map[string][]byte{"password": []byte("pass")},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			sensitiveValues := utils.NewSensitiveValues()
+			gen := NewTerraformStateGenerator(fakeClient, sensitiveValues)
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{TerraformState: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+			for _, value := range cc.expectedSensitive {
+				assert.Equal(t, utils.RedactedValue, sensitiveValues.Redact(value))
+			}
+			assert.Equal(t, "vpc-0123", sensitiveValues.Redact("vpc-0123"))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+var _ Generator = (*UnionGenerator)(nil)
+
+var LessThanTwoGeneratorsInUnion = errors.New("found less than two generators, Union requires two or more")
+
+type UnionGenerator struct {
+	// The inner generators supported by the union generator (cluster, git, list...)
+	supportedGenerators map[string]Generator
+}
+
+// NewUnionGenerator returns a UnionGenerator which allows the given supportedGenerators as child generators.
+func NewUnionGenerator(supportedGenerators map[string]Generator) Generator {
+	u := &UnionGenerator{
+		supportedGenerators: supportedGenerators,
+	}
+	return u
+}
+
+// GenerateParams gets the params produced by the UnionGenerator: the params of the child generators, in the order of the
+// generators, without the duplicates if deduplication keys are specified.
+func (u *UnionGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator.Union == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if len(appSetGenerator.Union.Generators) < 2 {
+		return nil, LessThanTwoGeneratorsInUnion
+	}
+
+	res := []map[string]interface{}{}
+	for _, generator := range appSetGenerator.Union.Generators {
+		paramSets, err := u.getParams(ctx, generator, appSet)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, paramSets...)
+	}
+
+	if len(appSetGenerator.Union.DeduplicationKeys) == 0 {
+		return res, nil
+	}
+	return deduplicateParamSets(appSetGenerator.Union.DeduplicationKeys, res)
+}
+
+// deduplicateParamSets returns the given parameter sets, keeping only the first parameter set among the ones with the
+// same values for all the given keys.
+func deduplicateParamSets(keys []string, paramSets []map[string]interface{}) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0, len(paramSets))
+	seen := make(map[string]bool, len(paramSets))
+	for _, paramSet := range paramSets {
+		paramSetKey := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			paramSetKey[key] = paramKeyValue(paramSet, key)
+		}
+		paramSetKeyJson, err := json.Marshal(paramSetKey)
+		if err != nil {
+			return nil, err
+		}
+		if seen[string(paramSetKeyJson)] {
+			continue
+		}
+		seen[string(paramSetKeyJson)] = true
+		res = append(res, paramSet)
+	}
+	return res, nil
+}
+
+// getParams get the parameters generated by this generator.
+func (u *UnionGenerator) getParams(ctx context.Context, appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	t, err := transform(
+		ctx,
+		*childGenerator(appSetBaseGenerator),
+		u.supportedGenerators,
+		argoprojiov1alpha1.ApplicationSetTemplate{},
+		appSet)
+
+	if err != nil {
+		return nil, fmt.Errorf("child generator returned an error on parameter generation: %w", err)
+	}
+
+	if len(t) == 0 {
+		return nil, fmt.Errorf("child generator generated no parameters")
+	}
+
+	if len(t) > 1 {
+		return nil, MoreThenOneInnerGenerators
+	}
+
+	return t[0].Params, nil
+}
+
+func (u *UnionGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	return getChildRequeueAfter(appSetGenerator.Union.Generators, appSetGenerator.Union.RequeueAfterSeconds, u.supportedGenerators)
+}
+
+// GetTemplate gets the Template field for the UnionGenerator.
+func (u *UnionGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Union.Template
+}
@@ -0,0 +1,172 @@
+package generators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionGenerate(t *testing.T) {
+
+	testCases := []struct {
+		name              string
+		baseGenerators    []argoprojiov1alpha1.ApplicationSetNestedGenerator
+		deduplicationKeys []string
+		expectedErr       error
+		expected          []map[string]interface{}
+	}{
+		{
+			name:           "no generators",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{},
+			expectedErr:    LessThanTwoGeneratorsInUnion,
+		},
+		{
+			name: "one generator",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1"}`),
+			},
+			expectedErr: LessThanTwoGeneratorsInUnion,
+		},
+		{
+			name: "happy flow - concatenate paramSets",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1", "b": "1"}`),
+				*getNestedListGenerator(`{"a": "1", "b": "2"}`),
+				*getNestedListGenerator(`{"c": "3"}`),
+			},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1"},
+				{"a": "1", "b": "2"},
+				{"c": "3"},
+			},
+		},
+		{
+			name: "deduplicate by key - keep the first paramSet",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1", "b": "1"}`),
+				*getNestedListGenerator(`{"a": "1", "b": "2"}`),
+				*getNestedListGenerator(`{"a": "2", "b": "2"}`),
+			},
+			deduplicationKeys: []string{"a"},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1"},
+				{"a": "2", "b": "2"},
+			},
+		},
+		{
+			name: "deduplicate by several keys",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1", "b": "1"}`),
+				*getNestedListGenerator(`{"a": "1", "b": "2"}`),
+				*getNestedListGenerator(`{"a": "1", "b": "1", "c": "ignored"}`),
+			},
+			deduplicationKeys: []string{"a", "b"},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1"},
+				{"a": "1", "b": "2"},
+			},
+		},
+		{
+			name: "union nested matrix with a list",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{
+					Matrix: &argoprojiov1alpha1.NestedMatrixGenerator{
+						Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
+							getTerminalListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
+							getTerminalListGeneratorMultiple([]string{`{"b": "1"}`}),
+						},
+					},
+				},
+				*getNestedListGenerator(`{"a": "3", "b": "1"}`),
+			},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1"},
+				{"a": "2", "b": "1"},
+				{"a": "3", "b": "1"},
+			},
+		},
+		{
+			name: "union nested union with a list",
+			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{
+					Union: &argoprojiov1alpha1.NestedUnionGenerator{
+						DeduplicationKeys: []string{"a"},
+						Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
+							getTerminalListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
+							getTerminalListGeneratorMultiple([]string{`{"a": "2", "b": "ignored"}`}),
+						},
+					},
+				},
+				*getNestedListGenerator(`{"a": "1"}`),
+			},
+			expected: []map[string]interface{}{
+				{"a": "1"},
+				{"a": "2"},
+				{"a": "1"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCaseCopy := testCase // since tests may run in parallel
+
+		t.Run(testCaseCopy.name, func(t *testing.T) {
+			t.Parallel()
+
+			appSet := &argoprojiov1alpha1.ApplicationSet{}
+
+			var unionGenerator = NewUnionGenerator(
+				map[string]Generator{
+					"List": &ListGenerator{},
+					"Matrix": &MatrixGenerator{
+						supportedGenerators: map[string]Generator{
+							"List": &ListGenerator{},
+						},
+					},
+					"Union": &UnionGenerator{
+						supportedGenerators: map[string]Generator{
+							"List": &ListGenerator{},
+						},
+					},
+				},
+			)
+
+			got, err := unionGenerator.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{
+				Union: &argoprojiov1alpha1.UnionGenerator{
+					Generators:        testCaseCopy.baseGenerators,
+					DeduplicationKeys: testCaseCopy.deduplicationKeys,
+					Template:          argoprojiov1alpha1.ApplicationSetTemplate{},
+				},
+			}, appSet)
+
+			if testCaseCopy.expectedErr != nil {
+				assert.EqualError(t, err, testCaseCopy.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCaseCopy.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnionGetRequeueAfter(t *testing.T) {
+	requeueAfterSeconds := int64(60)
+	unionGenerator := NewUnionGenerator(map[string]Generator{
+		"List":   &ListGenerator{},
+		"Plugin": &PluginGenerator{},
+	})
+
+	got := unionGenerator.GetRequeueAfter(&argoprojiov1alpha1.ApplicationSetGenerator{
+		Union: &argoprojiov1alpha1.UnionGenerator{
+			Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				*getNestedListGenerator(`{"a": "1"}`),
+				{Plugin: &argoprojiov1alpha1.PluginGenerator{RequeueAfterSeconds: &requeueAfterSeconds}},
+			},
+		},
+	})
+
+	assert.Equal(t, time.Minute, got)
+}
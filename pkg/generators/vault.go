@@ -0,0 +1,183 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/services/vault"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+var _ Generator = (*VaultGenerator)(nil)
+
+const (
+	DefaultVaultRequeueAfterSeconds = 30 * time.Minute
+	// serviceAccountTokenPath is the path of the token of the controller service account, used to log in with the
+	// Vault Kubernetes auth method.
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// VaultGenerator generates parameters from the secrets of a Vault KV secrets engine. The generated values are recorded
+// as sensitive, so that they are redacted from logs, events and the ApplicationSet status.
+type VaultGenerator struct {
+	client          client.Client
+	sensitiveValues *utils.SensitiveValues
+	// serviceAccountTokenPath is the path of the service account token, overridden in tests.
+	serviceAccountTokenPath string
+}
+
+func NewVaultGenerator(client client.Client, sensitiveValues *utils.SensitiveValues) Generator {
+	g := &VaultGenerator{
+		client:                  client,
+		sensitiveValues:         sensitiveValues,
+		serviceAccountTokenPath: serviceAccountTokenPath,
+	}
+	return g
+}
+
+func (g *VaultGenerator) GetRequeueAfter(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) time.Duration {
+	// Return a requeue default of 30 minutes, if no override is specified.
+
+	if appSetGenerator.Vault.RequeueAfterSeconds != nil {
+		return time.Duration(*appSetGenerator.Vault.RequeueAfterSeconds) * time.Second
+	}
+
+	return DefaultVaultRequeueAfterSeconds
+}
+
+func (g *VaultGenerator) GetTemplate(appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator) *argoprojiov1alpha1.ApplicationSetTemplate {
+	return &appSetGenerator.Vault.Template
+}
+
+func (g *VaultGenerator) GenerateParams(ctx context.Context, appSetGenerator *argoprojiov1alpha1.ApplicationSetGenerator, applicationSetInfo *argoprojiov1alpha1.ApplicationSet) ([]map[string]interface{}, error) {
+	if appSetGenerator == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	if appSetGenerator.Vault == nil {
+		return nil, EmptyAppSetGeneratorError
+	}
+
+	generatorConfig := appSetGenerator.Vault
+
+	auth, err := g.getAuth(ctx, &generatorConfig.Auth, applicationSetInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := vault.NewKVClient(ctx, generatorConfig.Address, generatorConfig.Namespace, generatorConfig.Mount, generatorConfig.KVVersion, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	paramSets, err := getVaultParamSets(ctx, kv, generatorConfig.Path, generatorConfig.Key, useGoTemplate(applicationSetInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string]interface{}, 0, len(paramSets))
+	for _, params := range paramSets {
+		for key, value := range params {
+			if key == "secret_name" {
+				continue
+			}
+			g.sensitiveValues.AddParam(applicationSetInfo.Namespace, applicationSetInfo.Name, value)
+		}
+		for key, value := range generatorConfig.Values {
+			params[fmt.Sprintf("values.%s", key)] = value
+		}
+		res = append(res, params)
+	}
+
+	return res, nil
+}
+
+// getVaultParamSets returns a parameter set for each secret under the path if key is empty, with their name as the
+// secret_name parameter. Otherwise, it returns the list of parameter sets contained in the key of the secret at the
+// path. Errors never include the content of the secrets.
+func getVaultParamSets(ctx context.Context, kv vault.KVService, secretPath, key string, useGoTemplate bool) ([]map[string]interface{}, error) {
+	if key != "" {
+		data, err := kv.Read(ctx, secretPath)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q in vault secret %s not found", key, secretPath)
+		}
+		// The list may either be stored as a JSON string, or as a JSON value of a secret written from a JSON file.
+		var objects []map[string]interface{}
+		valueJSON, isString := value.(string)
+		if !isString {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("key %q in vault secret %s must contain a list of objects", key, secretPath)
+			}
+			valueJSON = string(encoded)
+		}
+		if err := json.Unmarshal([]byte(valueJSON), &objects); err != nil {
+			return nil, fmt.Errorf("key %q in vault secret %s must contain a list of objects", key, secretPath)
+		}
+
+		paramSets := make([]map[string]interface{}, 0, len(objects))
+		for _, object := range objects {
+			params, err := jsonObjectToParams(object, useGoTemplate)
+			if err != nil {
+				return nil, fmt.Errorf("key %q in vault secret %s contains an invalid parameter set", key, secretPath)
+			}
+			paramSets = append(paramSets, params)
+		}
+		return paramSets, nil
+	}
+
+	names, err := kv.List(ctx, secretPath)
+	if err != nil {
+		return nil, err
+	}
+	paramSets := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		data, err := kv.Read(ctx, path.Join(secretPath, name))
+		if err != nil {
+			return nil, err
+		}
+		params, err := jsonObjectToParams(data, useGoTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("vault secret %s contains an invalid value", path.Join(secretPath, name))
+		}
+		params["secret_name"] = name
+		paramSets = append(paramSets, params)
+	}
+	return paramSets, nil
+}
+
+// getAuth returns the credentials used to authenticate against Vault.
+func (g *VaultGenerator) getAuth(ctx context.Context, auth *argoprojiov1alpha1.VaultAuth, namespace string) (vault.Auth, error) {
+	if (auth.TokenRef == nil) == (auth.Kubernetes == nil) {
+		return vault.Auth{}, fmt.Errorf("vault generator requires exactly one of auth.tokenRef or auth.kubernetes")
+	}
+
+	if auth.TokenRef != nil {
+		token, err := utils.GetSecretRef(ctx, g.client, auth.TokenRef, namespace)
+		if err != nil {
+			return vault.Auth{}, fmt.Errorf("error fetching Secret token: %v", err)
+		}
+		return vault.Auth{Token: token}, nil
+	}
+
+	jwt, err := ioutil.ReadFile(g.serviceAccountTokenPath)
+	if err != nil {
+		return vault.Auth{}, fmt.Errorf("error reading service account token: %v", err)
+	}
+	return vault.Auth{
+		KubernetesRole:      auth.Kubernetes.Role,
+		KubernetesAuthMount: auth.Kubernetes.Mount,
+		JWT:                 strings.TrimSpace(string(jwt)),
+	}, nil
+}
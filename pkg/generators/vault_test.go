@@ -0,0 +1,152 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestVaultGenerateParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/auth/kubernetes/login" {
+			_, _ = fmt.Fprint(w, `{"auth":{"client_token":"login-token"}}`)
+			return
+		}
+		if token := r.Header.Get("X-Vault-Token"); token != "my-token" && token != "login-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		switch {
+		case r.Method == "LIST" && r.URL.Path == "/v1/secret/metadata/tenants":
+			_, _ = fmt.Fprint(w, `{"data":{"keys":["tenant-a","tenant-b"]}}`)
+		case r.URL.Path == "/v1/secret/data/tenants/tenant-a":
+			_, _ = fmt.Fprint(w, `{"data":{"data":{"password":"s3cr3t-a","replicas":1}}}`)
+		case r.URL.Path == "/v1/secret/data/tenants/tenant-b":
+			_, _ = fmt.Fprint(w, `{"data":{"data":{"password":"s3cr3t-b","replicas":2}}}`)
+		case r.URL.Path == "/v1/secret/data/inventory":
+			_, _ = fmt.Fprint(w, `{"data":{"data":{"environments":"[{\"name\":\"staging\",\"cluster\":\"cluster-staging\"},{\"name\":\"production\",\"cluster\":\"cluster-production\"}]"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+	defer ts.Close()
+
+	tokenDir, err := ioutil.TempDir("", "vault-generator")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tokenDir)
+	tokenPath := filepath.Join(tokenDir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenPath, []byte("my-jwt\n"), 0600))
+
+	tokenAuth := argoprojiov1alpha1.VaultAuth{TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: "vault-token", Key: "token"}}
+
+	cases := []struct {
+		name              string
+		generator         *argoprojiov1alpha1.VaultGenerator
+		expected          []map[string]interface{}
+		expectedError     string
+		expectedSensitive []string
+	}{
+		{
+			name: "secrets under a path",
+			generator: &argoprojiov1alpha1.VaultGenerator{
+				Address: ts.URL,
+				Path:    "tenants",
+				Auth:    tokenAuth,
+				Values:  map[string]string{"team": "platform"},
+			},
+			expected: []map[string]interface{}{
+				{"secret_name": "tenant-a", "password": "s3cr3t-a", "replicas": "1", "values.team": "platform"},
+				{"secret_name": "tenant-b", "password": "s3cr3t-b", "replicas": "2", "values.team": "platform"},
+			},
+			expectedSensitive: []string{"s3cr3t-a", "s3cr3t-b"},
+		},
+		{
+			name: "list of parameter sets in a key, with kubernetes auth",
+			generator: &argoprojiov1alpha1.VaultGenerator{
+				Address: ts.URL,
+				Path:    "inventory",
+				Key:     "environments",
+				Auth:    argoprojiov1alpha1.VaultAuth{Kubernetes: &argoprojiov1alpha1.VaultKubernetesAuth{Role: "applicationset"}},
+			},
+			expected: []map[string]interface{}{
+				{"name": "staging", "cluster": "cluster-staging"},
+				{"name": "production", "cluster": "cluster-production"},
+			},
+			expectedSensitive: []string{"cluster-staging", "cluster-production"},
+		},
+		{
+			name: "missing key",
+			generator: &argoprojiov1alpha1.VaultGenerator{
+				Address: ts.URL,
+				Path:    "inventory",
+				Key:     "missing",
+				Auth:    tokenAuth,
+			},
+			expectedError: "key \"missing\" in vault secret inventory not found",
+		},
+		{
+			name: "missing auth",
+			generator: &argoprojiov1alpha1.VaultGenerator{
+				Address: ts.URL,
+				Path:    "tenants",
+			},
+			expectedError: "vault generator requires exactly one of auth.tokenRef or auth.kubernetes",
+		},
+		{
+			name: "wrong token",
+			generator: &argoprojiov1alpha1.VaultGenerator{
+				Address: ts.URL,
+				Path:    "tenants",
+				Auth:    argoprojiov1alpha1.VaultAuth{TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: "vault-token", Key: "wrong"}},
+			},
+			expectedError: "error listing secrets in tenants: unexpected status 403 Forbidden: permission denied",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: "argocd"},
+		Data:       map[string][]byte{"token": []byte("my-token"), "wrong": []byte("wrong-token")},
+	}).Build()
+	appSet := &argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "my-appset", Namespace: "argocd"}}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			sensitiveValues := utils.NewSensitiveValues()
+			gen := NewVaultGenerator(fakeClient, sensitiveValues)
+			gen.(*VaultGenerator).serviceAccountTokenPath = tokenPath
+
+			got, err := gen.GenerateParams(context.Background(), &argoprojiov1alpha1.ApplicationSetGenerator{Vault: cc.generator}, appSet)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, cc.expected, got)
+			for _, value := range cc.expectedSensitive {
+				assert.Equal(t, utils.RedactedValue, sensitiveValues.Redact(value))
+			}
+			// The secret names are not sensitive.
+			assert.Equal(t, "tenant-a", sensitiveValues.Redact("tenant-a"))
+		})
+	}
+}
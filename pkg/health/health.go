@@ -0,0 +1,59 @@
+// Package health defines the optional health checks of the ApplicationSet controller, which are served on its probe
+// address with the checks of controller-runtime, and the detail of the status of the SCM providers.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+// KubeAPICheck returns a check which fails if the version of the Kubernetes API can't be fetched. The client should be
+// configured with a timeout shorter than the timeout of the probe.
+func KubeAPICheck(client discovery.ServerVersionInterface) healthz.Checker {
+	return func(_ *http.Request) error {
+		if _, err := client.ServerVersion(); err != nil {
+			return fmt.Errorf("kubernetes API is unreachable: %v", err)
+		}
+		return nil
+	}
+}
+
+// SCMProviderExpiry is the duration after which an SCM provider which is no longer requested, e.g. because the
+// ApplicationSets using it were deleted, is ignored by the check of the SCM providers.
+const SCMProviderExpiry = 30 * time.Minute
+
+// SCMProvidersCheck returns a check which fails if the last request to the API of any SCM provider the controller sent
+// requests to failed. The providers are not requested by the check itself, so that it doesn't use their rate limits.
+func SCMProvidersCheck() healthz.Checker {
+	return func(_ *http.Request) error {
+		var failed []string
+		for _, status := range metrics.SCMProviderStatuses() {
+			if status.LastRequestFailed && time.Since(status.LastRequestTime) < SCMProviderExpiry {
+				failed = append(failed, fmt.Sprintf("%s: %s", status.Provider, status.LastError))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("the last requests to SCM providers failed: %s", strings.Join(failed, "; "))
+		}
+		return nil
+	}
+}
+
+// SCMProvidersHandler serves the status of the SCM providers the controller sent requests to, with their last error, as
+// JSON.
+func SCMProvidersHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(metrics.SCMProviderStatuses()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+type unreachableDiscovery struct{}
+
+func (unreachableDiscovery) ServerVersion() (*version.Info, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestKubeAPICheck(t *testing.T) {
+	assert.NoError(t, KubeAPICheck(fake.NewSimpleClientset().Discovery())(nil))
+	assert.EqualError(t, KubeAPICheck(unreachableDiscovery{})(nil), "kubernetes API is unreachable: connection refused")
+}
+
+func TestSCMProviders(t *testing.T) {
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, SCMProvidersCheck()(nil), "no provider was requested yet")
+
+	client := metrics.NewSCMClient("github", nil)
+	resp, err := client.Get(server.URL + "/repos")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	err = SCMProvidersCheck()(nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "github: GET")
+		assert.Contains(t, err.Error(), "returned 502")
+	}
+
+	rec := httptest.NewRecorder()
+	SCMProvidersHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/scm-providers", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var statuses []metrics.SCMProviderStatus
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+	if assert.Len(t, statuses, 1) {
+		assert.Equal(t, "github", statuses[0].Provider)
+		assert.True(t, statuses[0].LastRequestFailed)
+		assert.NotNil(t, statuses[0].LastErrorTime)
+	}
+
+	// The check succeeds again once the provider responds
+	failing = false
+	resp, err = client.Get(server.URL + "/repos")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.NoError(t, SCMProvidersCheck()(nil))
+}
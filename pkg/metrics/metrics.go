@@ -0,0 +1,183 @@
+// Package metrics defines the Prometheus metrics of the ApplicationSet controller. They are registered in the registry
+// of controller-runtime, and served with its own metrics on the metrics address of the controller.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ApplicationsGenerated is the state of the Applications rendered from the parameters of the generators.
+	ApplicationsGenerated = "generated"
+	// ApplicationsDesired is the state of the generated Applications which are valid, and are created or updated.
+	ApplicationsDesired = "desired"
+	// ApplicationsOrphaned is the state of the existing Applications which are no longer generated, but are not deleted
+	// yet, e.g. because of the policy, the prune grace period or the deletion protection.
+	ApplicationsOrphaned = "orphaned"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "argocd_appset_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliations of the ApplicationSets.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "name"})
+
+	generatorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "argocd_appset_generator_duration_seconds",
+		Help:    "Duration of the generation of the parameters by the generators, by type of generator.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"generator"})
+
+	generatorErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "argocd_appset_generator_errors_total",
+		Help: "Number of the failures of the generators, by type of generator.",
+	}, []string{"generator"})
+
+	applications = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "argocd_appset_applications",
+		Help: "Number of the Applications of the ApplicationSets, by state: generated, desired or orphaned.",
+	}, []string{"namespace", "name", "state"})
+
+	scmRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "argocd_appset_scm_api_requests_total",
+		Help: "Number of the requests to the APIs of the SCM providers, by provider and response code.",
+	}, []string{"provider", "code"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(reconcileDuration, generatorDuration, generatorErrors, applications, scmRequests)
+}
+
+// ObserveReconcile records the duration of a reconciliation of the ApplicationSet.
+func ObserveReconcile(namespace string, name string, duration time.Duration) {
+	reconcileDuration.WithLabelValues(namespace, name).Observe(duration.Seconds())
+}
+
+// ObserveGenerator records the duration of the generation of parameters by a generator of the given type, and its
+// failure if err is not nil.
+func ObserveGenerator(generator string, duration time.Duration, err error) {
+	generatorDuration.WithLabelValues(generator).Observe(duration.Seconds())
+	if err != nil {
+		generatorErrors.WithLabelValues(generator).Inc()
+	}
+}
+
+// SetApplications records the number of the generated, desired and orphaned Applications of the ApplicationSet.
+func SetApplications(namespace string, name string, generated int, desired int, orphaned int) {
+	applications.WithLabelValues(namespace, name, ApplicationsGenerated).Set(float64(generated))
+	applications.WithLabelValues(namespace, name, ApplicationsDesired).Set(float64(desired))
+	applications.WithLabelValues(namespace, name, ApplicationsOrphaned).Set(float64(orphaned))
+}
+
+// DeleteApplicationSet removes the metrics of a deleted ApplicationSet, so that they are no longer exported.
+func DeleteApplicationSet(namespace string, name string) {
+	reconcileDuration.DeleteLabelValues(namespace, name)
+	for _, state := range []string{ApplicationsGenerated, ApplicationsDesired, ApplicationsOrphaned} {
+		applications.DeleteLabelValues(namespace, name, state)
+	}
+}
+
+// scmTransport counts the requests to the API of an SCM provider.
+type scmTransport struct {
+	provider string
+	base     http.RoundTripper
+}
+
+// NewSCMTransport returns a transport which counts the requests to the API of the SCM provider, sent with the base
+// transport, or with the default transport if base is nil.
+func NewSCMTransport(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &scmTransport{provider: provider, base: base}
+}
+
+func (t *scmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	scmRequests.WithLabelValues(t.provider, code).Inc()
+	scmStatuses.record(t.provider, req, resp, err)
+	return resp, err
+}
+
+// SCMProviderStatus is the outcome of the last requests to the API of an SCM provider.
+type SCMProviderStatus struct {
+	// Provider is the name of the SCM provider, e.g. github.
+	Provider string `json:"provider"`
+	// LastRequestTime is the time of the last request to the API of the provider.
+	LastRequestTime time.Time `json:"lastRequestTime"`
+	// LastRequestFailed is true if the last request failed, i.e. no response was received, or the provider
+	// returned a server error or throttled the request.
+	LastRequestFailed bool `json:"lastRequestFailed"`
+	// LastError is the error of the last request which failed, if any.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time of the last request which failed, if any.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// scmProviderStatuses keeps the status of the SCM providers the controller sent requests to.
+type scmProviderStatuses struct {
+	lock     sync.Mutex
+	statuses map[string]SCMProviderStatus
+}
+
+var scmStatuses = &scmProviderStatuses{statuses: map[string]SCMProviderStatus{}}
+
+func (s *scmProviderStatuses) record(provider string, req *http.Request, resp *http.Response, err error) {
+	now := time.Now()
+	var requestErr string
+	if err != nil {
+		requestErr = err.Error()
+	} else if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		requestErr = fmt.Sprintf("%s %s %s returned %s", req.Method, req.URL.Host, req.URL.Path, resp.Status)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := s.statuses[provider]
+	status.Provider = provider
+	status.LastRequestTime = now
+	status.LastRequestFailed = requestErr != ""
+	if requestErr != "" {
+		status.LastError = requestErr
+		status.LastErrorTime = &now
+	}
+	s.statuses[provider] = status
+}
+
+// SCMProviderStatuses returns the status of the SCM providers the controller sent requests to, sorted by provider.
+func SCMProviderStatuses() []SCMProviderStatus {
+	scmStatuses.lock.Lock()
+	defer scmStatuses.lock.Unlock()
+	res := make([]SCMProviderStatus, 0, len(scmStatuses.statuses))
+	for _, status := range scmStatuses.statuses {
+		res = append(res, status)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Provider < res[j].Provider
+	})
+	return res
+}
+
+// NewSCMClient returns a copy of the HTTP client, or of the default client if client is nil, whose requests to the API
+// of the SCM provider are counted.
+func NewSCMClient(provider string, client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	instrumented := *client
+	instrumented.Transport = NewSCMTransport(provider, client.Transport)
+	return &instrumented
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveGenerator(t *testing.T) {
+	ObserveGenerator("List", time.Second, nil)
+	ObserveGenerator("SCMProvider", time.Second, errors.New("SCM provider unavailable"))
+	ObserveGenerator("SCMProvider", time.Second, nil)
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(generatorErrors.WithLabelValues("List")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(generatorErrors.WithLabelValues("SCMProvider")))
+	assert.Equal(t, 2, testutil.CollectAndCount(generatorDuration))
+}
+
+func TestSetApplications(t *testing.T) {
+	SetApplications("argocd", "guestbook", 3, 2, 1)
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(applications.WithLabelValues("argocd", "guestbook", ApplicationsGenerated)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(applications.WithLabelValues("argocd", "guestbook", ApplicationsDesired)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(applications.WithLabelValues("argocd", "guestbook", ApplicationsOrphaned)))
+
+	ObserveReconcile("argocd", "guestbook", time.Second)
+	assert.Equal(t, 1, testutil.CollectAndCount(reconcileDuration))
+
+	DeleteApplicationSet("argocd", "guestbook")
+	assert.Equal(t, 0, testutil.CollectAndCount(applications))
+	assert.Equal(t, 0, testutil.CollectAndCount(reconcileDuration))
+}
+
+func TestNewSCMClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewSCMClient("github", nil)
+	assert.Nil(t, http.DefaultClient.Transport, "the default client must not be changed")
+
+	for _, path := range []string{"/repos", "/repos", "/missing"} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	_, err := client.Get("http://127.0.0.1:0/unreachable")
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(scmRequests.WithLabelValues("github", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(scmRequests.WithLabelValues("github", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(scmRequests.WithLabelValues("github", "error")))
+}
+
+func TestSCMProviderStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	gitlab := NewSCMClient("gitlab", nil)
+	gitea := NewSCMClient("gitea", nil)
+
+	resp, err := gitlab.Get(server.URL + "/unavailable")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	// A missing resource is not a failure of the provider
+	resp, err = gitlab.Get(server.URL + "/missing")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	resp, err = gitea.Get(server.URL + "/repos")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	resp, err = gitea.Get(server.URL + "/unavailable")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	statuses := map[string]SCMProviderStatus{}
+	for _, status := range SCMProviderStatuses() {
+		statuses[status.Provider] = status
+	}
+
+	assert.False(t, statuses["gitlab"].LastRequestFailed)
+	assert.Contains(t, statuses["gitlab"].LastError, "/unavailable returned 503")
+	assert.NotNil(t, statuses["gitlab"].LastErrorTime)
+
+	assert.True(t, statuses["gitea"].LastRequestFailed)
+	assert.Contains(t, statuses["gitea"].LastError, "returned 503")
+}
@@ -0,0 +1,274 @@
+package azure_subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// ARMDefaultAPI is the URL of the Azure Resource Manager API of the Azure public cloud.
+	ARMDefaultAPI = "https://management.azure.com"
+	// ARMDefaultLoginAPI is the URL of the Azure Active Directory endpoint of the Azure public cloud.
+	ARMDefaultLoginAPI = "https://login.microsoftonline.com"
+	// armDefaultIMDSAPI is the URL of the Azure Instance Metadata Service, issuing the managed identity tokens.
+	armDefaultIMDSAPI = "http://169.254.169.254"
+
+	armSubscriptionsAPIVersion    = "2020-01-01"
+	armManagementGroupsAPIVersion = "2020-05-01"
+	armIMDSAPIVersion             = "2018-02-01"
+
+	// armClientAssertionType is the type of the federated tokens exchanged for access tokens with Azure Workload
+	// Identity.
+	armClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// Credentials are the credentials used to authenticate against Azure. If ClientSecret is set, the service principal
+// with the given TenantID and ClientID is used. Otherwise, if the AZURE_FEDERATED_TOKEN_FILE environment variable is
+// injected by Azure Workload Identity, the token of the service account of the controller is exchanged for a token of
+// the identity with the given ClientID and TenantID, defaulting to the AZURE_CLIENT_ID and AZURE_TENANT_ID environment
+// variables. Otherwise, the managed identity of the controller is used: ClientID optionally selects a user-assigned
+// identity.
+type Credentials struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+}
+
+type ARMService struct {
+	client          *http.Client
+	api             string
+	loginAPI        string
+	imdsAPI         string
+	credentials     Credentials
+	managementGroup string
+}
+
+var _ SubscriptionService = (*ARMService)(nil)
+
+// armSubscriptionList is the subset of the Azure Resource Manager subscription list response used by the generator.
+type armSubscriptionList struct {
+	Value []struct {
+		SubscriptionID string            `json:"subscriptionId"`
+		DisplayName    string            `json:"displayName"`
+		TenantID       string            `json:"tenantId"`
+		State          string            `json:"state"`
+		Tags           map[string]string `json:"tags"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// armManagementGroupDescendantList is the subset of the management group descendants response used by the generator.
+type armManagementGroupDescendantList struct {
+	Value []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// armToken is the subset of the Azure Active Directory and Instance Metadata Service token responses used by the
+// generator.
+type armToken struct {
+	AccessToken string `json:"access_token"`
+}
+
+// NewARMService returns a service listing the Azure subscriptions the credentials have access to. If managementGroup
+// is set, only the subscriptions within the management group, directly or through its descendant management groups,
+// are returned.
+func NewARMService(ctx context.Context, credentials Credentials, managementGroup, api, loginAPI string) (SubscriptionService, error) {
+	if credentials.ClientSecret != "" && (credentials.TenantID == "" || credentials.ClientID == "") {
+		return nil, fmt.Errorf("tenantId and clientId are required to authenticate with a client secret")
+	}
+	if api == "" {
+		api = ARMDefaultAPI
+	}
+	if loginAPI == "" {
+		loginAPI = os.Getenv("AZURE_AUTHORITY_HOST")
+	}
+	if loginAPI == "" {
+		loginAPI = ARMDefaultLoginAPI
+	}
+	return &ARMService{
+		client:          &http.Client{},
+		api:             strings.TrimSuffix(api, "/"),
+		loginAPI:        strings.TrimSuffix(loginAPI, "/"),
+		imdsAPI:         armDefaultIMDSAPI,
+		credentials:     credentials,
+		managementGroup: managementGroup,
+	}, nil
+}
+
+func (a *ARMService) List(ctx context.Context) ([]*Subscription, error) {
+	token, err := a.getToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error authenticating against Azure: %v", err)
+	}
+
+	var inManagementGroup map[string]bool
+	if a.managementGroup != "" {
+		inManagementGroup, err = a.listManagementGroupSubscriptions(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("error listing subscriptions of management group %s: %v", a.managementGroup, err)
+		}
+	}
+
+	subscriptions := []*Subscription{}
+	next := fmt.Sprintf("%s/subscriptions?api-version=%s", a.api, armSubscriptionsAPIVersion)
+	for next != "" {
+		page := &armSubscriptionList{}
+		if err := a.get(ctx, token, next, page); err != nil {
+			return nil, fmt.Errorf("error listing subscriptions: %v", err)
+		}
+		for _, sub := range page.Value {
+			if inManagementGroup != nil && !inManagementGroup[sub.SubscriptionID] {
+				continue
+			}
+			tags := sub.Tags
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			subscriptions = append(subscriptions, &Subscription{
+				ID:          sub.SubscriptionID,
+				DisplayName: sub.DisplayName,
+				TenantID:    sub.TenantID,
+				State:       sub.State,
+				Tags:        tags,
+			})
+		}
+		next = page.NextLink
+	}
+	return subscriptions, nil
+}
+
+// listManagementGroupSubscriptions returns the IDs of the subscriptions within the management group, including the
+// ones in its descendant management groups.
+func (a *ARMService) listManagementGroupSubscriptions(ctx context.Context, token string) (map[string]bool, error) {
+	ids := map[string]bool{}
+	next := fmt.Sprintf("%s/providers/Microsoft.Management/managementGroups/%s/descendants?api-version=%s",
+		a.api, url.PathEscape(a.managementGroup), armManagementGroupsAPIVersion)
+	for next != "" {
+		page := &armManagementGroupDescendantList{}
+		if err := a.get(ctx, token, next, page); err != nil {
+			return nil, err
+		}
+		for _, descendant := range page.Value {
+			if strings.EqualFold(descendant.Type, "Microsoft.Management/managementGroups/subscriptions") || strings.EqualFold(descendant.Type, "/subscriptions") {
+				ids[descendant.Name] = true
+			}
+		}
+		next = page.NextLink
+	}
+	return ids, nil
+}
+
+// getToken returns an Azure Resource Manager access token, using either the service principal, the workload identity
+// or the managed identity.
+func (a *ARMService) getToken(ctx context.Context) (string, error) {
+	var req *http.Request
+	var err error
+	if a.credentials.ClientSecret != "" {
+		form := url.Values{}
+		form.Set("client_secret", a.credentials.ClientSecret)
+		req, err = a.newClientCredentialsRequest(ctx, a.credentials.TenantID, a.credentials.ClientID, form)
+		if err != nil {
+			return "", err
+		}
+	} else if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+		tenantID, clientID := a.credentials.TenantID, a.credentials.ClientID
+		if tenantID == "" {
+			tenantID = os.Getenv("AZURE_TENANT_ID")
+		}
+		if clientID == "" {
+			clientID = os.Getenv("AZURE_CLIENT_ID")
+		}
+		if tenantID == "" || clientID == "" {
+			return "", fmt.Errorf("tenantId and clientId are required to authenticate with a workload identity")
+		}
+		token, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading federated token: %v", err)
+		}
+		form := url.Values{}
+		form.Set("client_assertion_type", armClientAssertionType)
+		form.Set("client_assertion", strings.TrimSpace(string(token)))
+		req, err = a.newClientCredentialsRequest(ctx, tenantID, clientID, form)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		query := url.Values{}
+		query.Set("api-version", armIMDSAPIVersion)
+		query.Set("resource", a.api+"/")
+		if a.credentials.ClientID != "" {
+			query.Set("client_id", a.credentials.ClientID)
+		}
+		u := fmt.Sprintf("%s/metadata/identity/oauth2/token?%s", a.imdsAPI, query.Encode())
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, req.URL.Host)
+	}
+
+	token := &armToken{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return "", fmt.Errorf("error decoding token response: %v", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("no access token in the response from %s", req.URL.Host)
+	}
+	return token.AccessToken, nil
+}
+
+// newClientCredentialsRequest returns a request of an access token of the client, authenticated by the form.
+func (a *ARMService) newClientCredentialsRequest(ctx context.Context, tenantID, clientID string, form url.Values) (*http.Request, error) {
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("scope", a.api+"/.default")
+	u := fmt.Sprintf("%s/%s/oauth2/v2.0/token", a.loginAPI, url.PathEscape(tenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// get fetches a page from the Azure Resource Manager API, decoding the response into out.
+func (a *ARMService) get(ctx context.Context, token, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, req.URL.Path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package azure_subscriptions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestARMList(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/my-tenant/oauth2/v2.0/token":
+			assert.NoError(t, r.ParseForm())
+			if r.PostForm.Get("client_id") != "my-client" || r.PostForm.Get("client_secret") != "my-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, ts.URL+"/.default", r.PostForm.Get("scope"))
+			_, _ = fmt.Fprint(w, `{"access_token":"sp-token"}`)
+			return
+		case "/metadata/identity/oauth2/token":
+			assert.Equal(t, "true", r.Header.Get("Metadata"))
+			assert.Equal(t, ts.URL+"/", r.URL.Query().Get("resource"))
+			_, _ = fmt.Fprint(w, `{"access_token":"msi-token"}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer sp-token" && r.Header.Get("Authorization") != "Bearer msi-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/subscriptions":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = fmt.Fprint(w, `{"value":[{"subscriptionId":"sub-3","displayName":"Sandbox","tenantId":"my-tenant","state":"Enabled"}]}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"value":[
+				{"subscriptionId":"sub-1","displayName":"Production","tenantId":"my-tenant","state":"Enabled","tags":{"env":"prod"}},
+				{"subscriptionId":"sub-2","displayName":"Staging","tenantId":"my-tenant","state":"Enabled","tags":{"env":"staging"}}
+			],"nextLink":"%s/subscriptions?api-version=2020-01-01&page=2"}`, ts.URL)
+		case "/providers/Microsoft.Management/managementGroups/platform/descendants":
+			_, _ = fmt.Fprint(w, `{"value":[
+				{"type":"Microsoft.Management/managementGroups","name":"platform-nonprod"},
+				{"type":"Microsoft.Management/managementGroups/subscriptions","name":"sub-2"},
+				{"type":"Microsoft.Management/managementGroups/subscriptions","name":"sub-3"}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	production := &Subscription{ID: "sub-1", DisplayName: "Production", TenantID: "my-tenant", State: "Enabled", Tags: map[string]string{"env": "prod"}}
+	staging := &Subscription{ID: "sub-2", DisplayName: "Staging", TenantID: "my-tenant", State: "Enabled", Tags: map[string]string{"env": "staging"}}
+	sandbox := &Subscription{ID: "sub-3", DisplayName: "Sandbox", TenantID: "my-tenant", State: "Enabled", Tags: map[string]string{}}
+
+	cases := []struct {
+		name            string
+		credentials     Credentials
+		managementGroup string
+		expected        []*Subscription
+		expectedError   string
+	}{
+		{
+			name:        "service principal",
+			credentials: Credentials{TenantID: "my-tenant", ClientID: "my-client", ClientSecret: "my-secret"},
+			expected:    []*Subscription{production, staging, sandbox},
+		},
+		{
+			name:     "managed identity",
+			expected: []*Subscription{production, staging, sandbox},
+		},
+		{
+			name:            "management group",
+			managementGroup: "platform",
+			expected:        []*Subscription{staging, sandbox},
+		},
+		{
+			name:          "wrong client secret",
+			credentials:   Credentials{TenantID: "my-tenant", ClientID: "my-client", ClientSecret: "wrong"},
+			expectedError: "error authenticating against Azure: unexpected status 401 Unauthorized",
+		},
+		{
+			name:            "unknown management group",
+			managementGroup: "unknown",
+			expectedError:   "error listing subscriptions of management group unknown: unexpected status 404 Not Found",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			svc, err := NewARMService(context.Background(), cc.credentials, cc.managementGroup, ts.URL, ts.URL)
+			assert.NoError(t, err)
+			svc.(*ARMService).imdsAPI = ts.URL
+
+			subscriptions, err := svc.List(context.Background())
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, subscriptions)
+		})
+	}
+}
+
+func TestNewARMServiceRequiresServicePrincipal(t *testing.T) {
+	_, err := NewARMService(context.Background(), Credentials{ClientSecret: "my-secret"}, "", "", "")
+	assert.EqualError(t, err, "tenantId and clientId are required to authenticate with a client secret")
+}
+
+func TestARMListWorkloadIdentity(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/my-tenant/oauth2/v2.0/token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, armClientAssertionType, r.PostForm.Get("client_assertion_type"))
+			assert.Equal(t, ts.URL+"/.default", r.PostForm.Get("scope"))
+			if r.PostForm.Get("client_id") != "my-client" || r.PostForm.Get("client_assertion") != "federated-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"access_token":"wi-token"}`)
+		case "/subscriptions":
+			if r.Header.Get("Authorization") != "Bearer wi-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"value":[{"subscriptionId":"sub-1","displayName":"Production","tenantId":"my-tenant","state":"Enabled"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, ioutil.WriteFile(tokenPath, []byte("federated-token\n"), 0600))
+	assert.NoError(t, os.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenPath))
+	defer os.Unsetenv("AZURE_FEDERATED_TOKEN_FILE")
+	assert.NoError(t, os.Setenv("AZURE_AUTHORITY_HOST", ts.URL))
+	defer os.Unsetenv("AZURE_AUTHORITY_HOST")
+	assert.NoError(t, os.Setenv("AZURE_TENANT_ID", "my-tenant"))
+	defer os.Unsetenv("AZURE_TENANT_ID")
+
+	svc, err := NewARMService(context.Background(), Credentials{}, "", ts.URL, "")
+	assert.NoError(t, err)
+	_, err = svc.List(context.Background())
+	assert.EqualError(t, err, "error authenticating against Azure: tenantId and clientId are required to authenticate with a workload identity")
+
+	// the client ID defaults to the one injected by Azure Workload Identity
+	assert.NoError(t, os.Setenv("AZURE_CLIENT_ID", "my-client"))
+	defer os.Unsetenv("AZURE_CLIENT_ID")
+	subscriptions, err := svc.List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*Subscription{{ID: "sub-1", DisplayName: "Production", TenantID: "my-tenant", State: "Enabled", Tags: map[string]string{}}}, subscriptions)
+
+	// the client ID of the generator selects another identity federated with the service account
+	svc, err = NewARMService(context.Background(), Credentials{ClientID: "other-client"}, "", ts.URL, "")
+	assert.NoError(t, err)
+	_, err = svc.List(context.Background())
+	assert.EqualError(t, err, "error authenticating against Azure: unexpected status 401 Unauthorized from "+strings.TrimPrefix(ts.URL, "http://"))
+}
@@ -0,0 +1,23 @@
+package azure_subscriptions
+
+import (
+	"context"
+)
+
+type FakeService struct {
+	listSubscriptions []*Subscription
+	listError         error
+}
+
+var _ SubscriptionService = (*FakeService)(nil)
+
+func NewFakeService(_ context.Context, listSubscriptions []*Subscription, listError error) (SubscriptionService, error) {
+	return &FakeService{
+		listSubscriptions: listSubscriptions,
+		listError:         listError,
+	}, nil
+}
+
+func (f *FakeService) List(ctx context.Context) ([]*Subscription, error) {
+	return f.listSubscriptions, f.listError
+}
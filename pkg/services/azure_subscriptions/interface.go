@@ -0,0 +1,21 @@
+package azure_subscriptions
+
+import "context"
+
+type Subscription struct {
+	// ID is the subscription ID, a UUID.
+	ID string
+	// DisplayName is the name of the subscription shown in the Azure portal.
+	DisplayName string
+	// TenantID is the ID of the Azure Active Directory tenant of the subscription.
+	TenantID string
+	// State is the state of the subscription, e.g. Enabled or Disabled.
+	State string
+	// Tags are the tags set on the subscription.
+	Tags map[string]string
+}
+
+type SubscriptionService interface {
+	// List gets a list of subscriptions.
+	List(ctx context.Context) ([]*Subscription, error)
+}
@@ -0,0 +1,230 @@
+package bucket
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// GCSDefaultEndpoint is the endpoint of the Google Cloud Storage JSON API.
+	GCSDefaultEndpoint = "https://storage.googleapis.com"
+	// gcsDefaultMetadataEndpoint is the endpoint of the GCE metadata server, issuing the workload identity tokens.
+	gcsDefaultMetadataEndpoint = "http://metadata.google.internal"
+	// gcsScope is the OAuth scope requested with a service account key.
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_only"
+)
+
+type GCSBucket struct {
+	client   *http.Client
+	bucket   string
+	endpoint string
+	// serviceAccountKey is the optional JSON key of a service account.
+	serviceAccountKey []byte
+	// metadataEndpoint is the endpoint of the metadata server, overridden in tests.
+	metadataEndpoint string
+	token            string
+}
+
+var _ Bucket = (*GCSBucket)(nil)
+
+// gcsServiceAccountKey is the subset of the JSON key of a service account used by the generator.
+type gcsServiceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcsToken is the subset of the OAuth token responses used by the generator.
+type gcsToken struct {
+	AccessToken string `json:"access_token"`
+}
+
+// gcsObjectList is the subset of the objects list response used by the generator.
+type gcsObjectList struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// NewGCSBucket returns a client of a Google Cloud Storage bucket. If serviceAccountKey is empty, the controller
+// authenticates with the Workload Identity of its service account.
+func NewGCSBucket(bucket, endpoint string, serviceAccountKey []byte) (Bucket, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("the bucket of a GCS bucket is required")
+	}
+	if endpoint == "" {
+		endpoint = GCSDefaultEndpoint
+	}
+	return &GCSBucket{
+		client:            &http.Client{Timeout: 30 * time.Second},
+		bucket:            bucket,
+		endpoint:          strings.TrimSuffix(endpoint, "/"),
+		serviceAccountKey: serviceAccountKey,
+		metadataEndpoint:  gcsDefaultMetadataEndpoint,
+	}, nil
+}
+
+func (b *GCSBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("fields", "items(name),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		body, err := b.get(ctx, fmt.Sprintf("%s/storage/v1/b/%s/o?%s", b.endpoint, url.PathEscape(b.bucket), query.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("error listing objects of gcs bucket %s: %v", b.bucket, err)
+		}
+		page := &gcsObjectList{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return nil, fmt.Errorf("error decoding objects of gcs bucket %s: %v", b.bucket, err)
+		}
+		for _, item := range page.Items {
+			keys = append(keys, item.Name)
+		}
+		if page.NextPageToken == "" {
+			return keys, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (b *GCSBucket) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := b.get(ctx, fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", b.endpoint, url.PathEscape(b.bucket), url.PathEscape(key)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading object %s of gcs bucket %s: %v", key, b.bucket, err)
+	}
+	return body, nil
+}
+
+// get sends an authenticated GET request, returning the response body.
+func (b *GCSBucket) get(ctx context.Context, u string) ([]byte, error) {
+	if b.token == "" {
+		token, err := b.getToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error authenticating against Google Cloud: %v", err)
+		}
+		b.token = token
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return b.do(req)
+}
+
+// getToken returns an access token of the service account key if set, or of the Workload Identity otherwise.
+func (b *GCSBucket) getToken(ctx context.Context) (string, error) {
+	var req *http.Request
+	if len(b.serviceAccountKey) == 0 {
+		var err error
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, b.metadataEndpoint+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+	} else {
+		key := &gcsServiceAccountKey{}
+		if err := json.Unmarshal(b.serviceAccountKey, key); err != nil {
+			return "", fmt.Errorf("invalid service account key")
+		}
+		assertion, err := gcsSignJWT(key, time.Now())
+		if err != nil {
+			return "", err
+		}
+		form := url.Values{}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, key.TokenURI, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	body, err := b.do(req)
+	if err != nil {
+		return "", err
+	}
+	token := &gcsToken{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return "", fmt.Errorf("error decoding token: %v", err)
+	}
+	return token.AccessToken, nil
+}
+
+func (b *GCSBucket) do(req *http.Request) ([]byte, error) {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return body, nil
+}
+
+// gcsSignJWT returns the JWT assertion exchanged for an access token of the service account.
+func gcsSignJWT(key *gcsServiceAccountKey, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("invalid private key in service account key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("invalid private key in service account key")
+		}
+	}
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("the private key of the service account key must be an RSA key")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   key.ClientEmail,
+		"scope": gcsScope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing token request: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,110 @@
+package bucket
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCSBucket(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			assert.NoError(t, r.ParseForm())
+			parts := strings.Split(r.PostForm.Get("assertion"), ".")
+			assert.Len(t, parts, 3)
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			assert.NoError(t, err)
+			hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			if rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature) != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"access_token":"key-token"}`)
+			return
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			_, _ = fmt.Fprint(w, `{"access_token":"workload-identity-token"}`)
+			return
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer key-token" && auth != "Bearer workload-identity-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/storage/v1/b/inventory/o" && r.URL.Query().Get("pageToken") == "":
+			assert.Equal(t, "environments/", r.URL.Query().Get("prefix"))
+			_, _ = fmt.Fprint(w, `{"items":[{"name":"environments/production.json"}],"nextPageToken":"page-2"}`)
+		case r.URL.Path == "/storage/v1/b/inventory/o" && r.URL.Query().Get("pageToken") == "page-2":
+			_, _ = fmt.Fprint(w, `{"items":[{"name":"environments/staging.json"}]}`)
+		case r.URL.EscapedPath() == "/storage/v1/b/inventory/o/environments%2Fstaging.json" && r.URL.Query().Get("alt") == "media":
+			_, _ = fmt.Fprint(w, `{"name":"staging"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	serviceAccountKey, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "applicationset@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    ts.URL + "/token",
+	})
+	assert.NoError(t, err)
+
+	t.Run("service account key", func(t *testing.T) {
+		b, err := NewGCSBucket("inventory", ts.URL, serviceAccountKey)
+		assert.NoError(t, err)
+
+		keys, err := b.List(ctx, "environments/")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"environments/production.json", "environments/staging.json"}, keys)
+
+		content, err := b.Get(ctx, "environments/staging.json")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"staging"}`, string(content))
+
+		_, err = b.Get(ctx, "environments/missing.json")
+		assert.EqualError(t, err, "error reading object environments/missing.json of gcs bucket inventory: unexpected status 404 Not Found")
+	})
+
+	t.Run("workload identity", func(t *testing.T) {
+		b, err := NewGCSBucket("inventory", ts.URL, nil)
+		assert.NoError(t, err)
+		b.(*GCSBucket).metadataEndpoint = ts.URL
+
+		keys, err := b.List(ctx, "environments/")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"environments/production.json", "environments/staging.json"}, keys)
+	})
+
+	t.Run("invalid service account key", func(t *testing.T) {
+		b, err := NewGCSBucket("inventory", ts.URL, []byte(`{"private_key":"invalid"}`))
+		assert.NoError(t, err)
+
+		_, err = b.List(ctx, "environments/")
+		assert.EqualError(t, err, "error listing objects of gcs bucket inventory: error authenticating against Google Cloud: invalid private key in service account key")
+	})
+}
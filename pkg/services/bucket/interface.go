@@ -0,0 +1,10 @@
+package bucket
+
+import "context"
+
+type Bucket interface {
+	// List gets the keys of the objects under the prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Get gets the content of an object.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
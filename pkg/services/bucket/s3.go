@@ -0,0 +1,295 @@
+package bucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3EmptyPayloadHash is the SHA-256 of an empty request body.
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// s3SessionName is the session name of the role assumed with a web identity.
+	s3SessionName = "argocd-applicationset"
+)
+
+// S3Credentials are the credentials used to authenticate against AWS. If AccessKeyID is not set, the IAM role of the
+// service account of the controller (IRSA) is assumed, from the AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE
+// environment variables injected by EKS.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+type S3Bucket struct {
+	client      *http.Client
+	bucket      string
+	region      string
+	endpoint    string
+	credentials S3Credentials
+	// stsEndpoint is the endpoint of the AWS Security Token Service, overridden in tests.
+	stsEndpoint string
+	// now returns the signing time, overridden in tests.
+	now func() time.Time
+}
+
+var _ Bucket = (*S3Bucket)(nil)
+
+// s3ListBucketResult is the subset of the ListObjectsV2 response used by the generator.
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// s3AssumeRoleWithWebIdentityResponse is the subset of the AssumeRoleWithWebIdentity response used by the generator.
+type s3AssumeRoleWithWebIdentityResponse struct {
+	Credentials struct {
+		AccessKeyID     string `xml:"AccessKeyId"`
+		SecretAccessKey string `xml:"SecretAccessKey"`
+		SessionToken    string `xml:"SessionToken"`
+	} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+// NewS3Bucket returns a client of an S3 bucket. If endpoint is set, e.g. for an S3 compatible object storage, the
+// bucket is addressed with a path-style URL. Otherwise, the virtual-hosted-style URL of the bucket in the region is
+// used.
+func NewS3Bucket(bucket, region, endpoint string, credentials S3Credentials) (Bucket, error) {
+	if bucket == "" || region == "" {
+		return nil, fmt.Errorf("the bucket and region of an S3 bucket are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	} else {
+		endpoint = strings.TrimSuffix(endpoint, "/") + "/" + bucket
+	}
+	return &S3Bucket{
+		client:      &http.Client{Timeout: 30 * time.Second},
+		bucket:      bucket,
+		region:      region,
+		endpoint:    endpoint,
+		credentials: credentials,
+		stsEndpoint: fmt.Sprintf("https://sts.%s.amazonaws.com", region),
+		now:         time.Now,
+	}, nil
+}
+
+func (b *S3Bucket) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		body, err := b.get(ctx, "", query)
+		if err != nil {
+			return nil, fmt.Errorf("error listing objects of s3 bucket %s: %v", b.bucket, err)
+		}
+		page := &s3ListBucketResult{}
+		if err := xml.Unmarshal(body, page); err != nil {
+			return nil, fmt.Errorf("error decoding objects of s3 bucket %s: %v", b.bucket, err)
+		}
+		for _, content := range page.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		continuationToken = page.NextContinuationToken
+	}
+}
+
+func (b *S3Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := b.get(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading object %s of s3 bucket %s: %v", key, b.bucket, err)
+	}
+	return body, nil
+}
+
+// get sends a signed GET request for the key of the bucket, returning the response body.
+func (b *S3Bucket) get(ctx context.Context, key string, query url.Values) ([]byte, error) {
+	if b.credentials.AccessKeyID == "" {
+		credentials, err := b.assumeRoleWithWebIdentity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.credentials = credentials
+	}
+
+	u := b.endpoint + "/" + s3URIEncode(key, false)
+	if len(query) > 0 {
+		u += "?" + s3CanonicalQuery(query)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	b.sign(req, b.now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return body, nil
+}
+
+// sign adds the AWS Signature Version 4 of the request to its headers. The request must not have a body.
+func (b *S3Bucket) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", amzDate[:8], b.region)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3EmptyPayloadHash)
+	if b.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", b.credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3EmptyPayloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := []byte("AWS4" + b.credentials.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], b.region, "s3", "aws4_request"} {
+		signingKey = s3HMAC(signingKey, part)
+	}
+	signature := hex.EncodeToString(s3HMAC(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// assumeRoleWithWebIdentity returns temporary credentials of the IAM role of the service account of the controller.
+func (b *S3Bucket) assumeRoleWithWebIdentity(ctx context.Context) (S3Credentials, error) {
+	roleARN := os.Getenv("AWS_ROLE_ARN")
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return S3Credentials{}, fmt.Errorf("no AWS credentials: either set the access key of the generator, or use IAM roles for service accounts")
+	}
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return S3Credentials{}, fmt.Errorf("error reading web identity token: %v", err)
+	}
+
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithWebIdentity")
+	form.Set("Version", "2011-06-15")
+	form.Set("RoleArn", roleARN)
+	form.Set("RoleSessionName", s3SessionName)
+	form.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.stsEndpoint+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return S3Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return S3Credentials{}, fmt.Errorf("error assuming role %s: %v", roleARN, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return S3Credentials{}, fmt.Errorf("error assuming role %s: unexpected status %s", roleARN, resp.Status)
+	}
+	assumed := &s3AssumeRoleWithWebIdentityResponse{}
+	if err := xml.NewDecoder(resp.Body).Decode(assumed); err != nil {
+		return S3Credentials{}, fmt.Errorf("error decoding credentials of role %s: %v", roleARN, err)
+	}
+	return S3Credentials{
+		AccessKeyID:     assumed.Credentials.AccessKeyID,
+		SecretAccessKey: assumed.Credentials.SecretAccessKey,
+		SessionToken:    assumed.Credentials.SessionToken,
+	}, nil
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3CanonicalQuery returns the query string sorted by key, with the keys and values URI encoded as required by the
+// AWS Signature Version 4.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3URIEncode(key, true)+"="+s3URIEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3URIEncode encodes all the characters of s but the unreserved ones and, unless encodeSlash is true, the slashes.
+func s3URIEncode(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for _, c := range []byte(s) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
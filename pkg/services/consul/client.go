@@ -0,0 +1,151 @@
+package consul
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Service is a service of the Consul catalog.
+type Service struct {
+	Name       string
+	Tags       []string
+	Datacenter string
+}
+
+// KVPair is a key of the Consul KV store, with its decoded value.
+type KVPair struct {
+	Key        string
+	Value      string
+	Datacenter string
+}
+
+// CatalogService reads the Consul service catalog and KV store.
+type CatalogService interface {
+	// Datacenters returns the known datacenters, the one of the queried agent first.
+	Datacenters(ctx context.Context) ([]string, error)
+	// Services returns the services registered in the datacenter.
+	Services(ctx context.Context, datacenter string) ([]*Service, error)
+	// KV returns the keys under the prefix in the datacenter. Folders are skipped.
+	KV(ctx context.Context, datacenter, prefix string) ([]*KVPair, error)
+}
+
+type Client struct {
+	client  *http.Client
+	address string
+	token   string
+}
+
+var _ CatalogService = (*Client)(nil)
+
+// NewClient returns a client of the Consul HTTP API at address. token is an optional ACL token.
+func NewClient(address, token string) (CatalogService, error) {
+	if address == "" {
+		return nil, fmt.Errorf("consul address is required")
+	}
+	return &Client{
+		client:  &http.Client{},
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+	}, nil
+}
+
+func (c *Client) Datacenters(ctx context.Context) ([]string, error) {
+	datacenters := []string{}
+	if err := c.get(ctx, "/v1/catalog/datacenters", nil, &datacenters); err != nil {
+		return nil, fmt.Errorf("error listing datacenters: %v", err)
+	}
+	return datacenters, nil
+}
+
+func (c *Client) Services(ctx context.Context, datacenter string) ([]*Service, error) {
+	query := url.Values{}
+	query.Set("dc", datacenter)
+	tagsByService := map[string][]string{}
+	if err := c.get(ctx, "/v1/catalog/services", query, &tagsByService); err != nil {
+		return nil, fmt.Errorf("error listing services of datacenter %s: %v", datacenter, err)
+	}
+
+	services := make([]*Service, 0, len(tagsByService))
+	for name, tags := range tagsByService {
+		if tags == nil {
+			tags = []string{}
+		}
+		services = append(services, &Service{Name: name, Tags: tags, Datacenter: datacenter})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+func (c *Client) KV(ctx context.Context, datacenter, prefix string) ([]*KVPair, error) {
+	query := url.Values{}
+	query.Set("dc", datacenter)
+	query.Set("recurse", "true")
+	entries := []struct {
+		Key   string `json:"Key"`
+		Value string `json:"Value"`
+	}{}
+	err := c.get(ctx, "/v1/kv/"+strings.TrimPrefix(prefix, "/"), query, &entries)
+	if err == errNotFound {
+		return []*KVPair{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading keys %s of datacenter %s: %v", prefix, datacenter, err)
+	}
+
+	pairs := make([]*KVPair, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Key, "/") {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding value of key %s: %v", entry.Key, err)
+		}
+		pairs = append(pairs, &KVPair{Key: entry.Key, Value: string(value), Datacenter: datacenter})
+	}
+	return pairs, nil
+}
+
+// errNotFound is returned by get when the Consul API replies with a 404, e.g. when no key matches a KV prefix.
+var errNotFound = fmt.Errorf("not found")
+
+// get sends a GET request to the Consul API, decoding the response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.address + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
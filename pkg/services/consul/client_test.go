@@ -0,0 +1,65 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/catalog/datacenters":
+			_, _ = fmt.Fprint(w, `["dc1","dc2"]`)
+		case r.URL.Path == "/v1/catalog/services" && r.URL.Query().Get("dc") == "dc1":
+			_, _ = fmt.Fprint(w, `{"web":["http","public"],"consul":null,"api":["http"]}`)
+		case r.URL.Path == "/v1/kv/apps" && r.URL.Query().Get("recurse") == "true":
+			// "production" and "staging", base64 encoded
+			_, _ = fmt.Fprint(w, `[{"Key":"apps/","Value":null},{"Key":"apps/web","Value":"cHJvZHVjdGlvbg=="},{"Key":"apps/api","Value":"c3RhZ2luZw=="}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	c, err := NewClient(ts.URL, "my-token")
+	assert.NoError(t, err)
+
+	datacenters, err := c.Datacenters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dc1", "dc2"}, datacenters)
+
+	services, err := c.Services(ctx, "dc1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Service{
+		{Name: "api", Tags: []string{"http"}, Datacenter: "dc1"},
+		{Name: "consul", Tags: []string{}, Datacenter: "dc1"},
+		{Name: "web", Tags: []string{"http", "public"}, Datacenter: "dc1"},
+	}, services)
+
+	pairs, err := c.KV(ctx, "dc1", "apps")
+	assert.NoError(t, err)
+	assert.Equal(t, []*KVPair{
+		{Key: "apps/web", Value: "production", Datacenter: "dc1"},
+		{Key: "apps/api", Value: "staging", Datacenter: "dc1"},
+	}, pairs)
+
+	pairs, err = c.KV(ctx, "dc1", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, []*KVPair{}, pairs)
+
+	unauthorized, err := NewClient(ts.URL, "")
+	assert.NoError(t, err)
+	_, err = unauthorized.Services(ctx, "dc1")
+	assert.EqualError(t, err, "error listing services of datacenter dc1: unexpected status 403 Forbidden from /v1/catalog/services")
+}
@@ -0,0 +1,174 @@
+package github_app
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"golang.org/x/oauth2"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+const (
+	// jwtExpiry is the lifetime of the JWTs authenticating as the GitHub App, GitHub accepts at most 10 minutes.
+	jwtExpiry = 9 * time.Minute
+	// jwtClockSkew backdates the JWTs, to allow for the clock drift between the controller and GitHub.
+	jwtClockSkew = time.Minute
+	// tokenExpiryDelta renews the installation tokens this long before they expire, so that a token doesn't expire
+	// while the generators use it.
+	tokenExpiryDelta = 5 * time.Minute
+)
+
+// Authentication are the credentials of a GitHub App installation.
+type Authentication struct {
+	// AppID is the ID of the GitHub App.
+	AppID int64
+	// InstallationID is the ID of the installation of the GitHub App in an organization or a user account.
+	InstallationID int64
+	// PrivateKey is the PEM-encoded private key of the GitHub App.
+	PrivateKey string
+}
+
+var (
+	tokenSourcesLock sync.Mutex
+	// tokenSources caches the token sources by API URL and credentials, so that the installation tokens are reused by
+	// the generators until they expire, rather than created at each generation.
+	tokenSources = map[string]oauth2.TokenSource{}
+)
+
+// NewTokenSource returns a token source of the installation tokens of a GitHub App, which are renewed before they
+// expire. The url is the GitHub Enterprise API URL, or empty for https://api.github.com/. The tokens are created with
+// the httpClient, or with the default client if nil.
+func NewTokenSource(auth Authentication, url string, httpClient *http.Client) (oauth2.TokenSource, error) {
+	key, err := parsePrivateKey(auth.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256([]byte(auth.PrivateKey))
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	// The HTTP clients are shared by the generators with the same options, so their pointers identify the options
+	cacheKey := fmt.Sprintf("%s|%d|%d|%s|%p", url, auth.AppID, auth.InstallationID, hex.EncodeToString(hash[:]), httpClient)
+
+	tokenSourcesLock.Lock()
+	defer tokenSourcesLock.Unlock()
+	if ts, ok := tokenSources[cacheKey]; ok {
+		return ts, nil
+	}
+
+	scmClient := metrics.NewSCMClient("github", &http.Client{Transport: &appTransport{appID: auth.AppID, key: key, base: httpClient.Transport}})
+	var client *github.Client
+	if url == "" {
+		client = github.NewClient(scmClient)
+	} else {
+		client, err = github.NewEnterpriseClient(url, url, scmClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ts := oauth2.ReuseTokenSource(nil, &installationTokenSource{client: client, installationID: auth.InstallationID})
+	tokenSources[cacheKey] = ts
+	return ts, nil
+}
+
+// installationTokenSource creates the installation tokens of a GitHub App.
+type installationTokenSource struct {
+	client         *github.Client
+	installationID int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.client.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating a token for the GitHub App installation %d: %v", s.installationID, err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "token",
+		Expiry:      token.GetExpiresAt().Add(-tokenExpiryDelta),
+	}, nil
+}
+
+// appTransport authenticates the requests as the GitHub App, with a JWT signed by its private key.
+type appTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+	// base sends the requests, http.DefaultTransport if nil.
+	base http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := signJWT(t.appID, t.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if t.base == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// signJWT returns a JWT identifying the GitHub App, signed with RS256.
+func signJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtExpiry).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing the GitHub App JWT: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key, in the PKCS #1 format of the keys generated by GitHub, or in
+// the PKCS #8 format.
+func parsePrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, fmt.Errorf("error parsing the GitHub App private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the GitHub App private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("error parsing the GitHub App private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
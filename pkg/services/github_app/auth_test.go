@@ -0,0 +1,133 @@
+package github_app
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generatePrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+// verifyJWT checks the signature of a JWT, and returns its claims.
+func verifyJWT(t *testing.T, jwt string, key *rsa.PublicKey) map[string]interface{} {
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	claims := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	return claims
+}
+
+func TestSignJWT(t *testing.T) {
+	key, _ := generatePrivateKey(t)
+	now := time.Unix(1600000000, 0)
+
+	jwt, err := signJWT(123, key, now)
+	require.NoError(t, err)
+
+	claims := verifyJWT(t, jwt, &key.PublicKey)
+	assert.Equal(t, "123", claims["iss"])
+	assert.Equal(t, float64(now.Add(-jwtClockSkew).Unix()), claims["iat"])
+	assert.Equal(t, float64(now.Add(jwtExpiry).Unix()), claims["exp"])
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, pkcs1 := generatePrivateKey(t)
+
+	parsed, err := parsePrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsed, err = parsePrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = parsePrivateKey("not a key")
+	assert.EqualError(t, err, "error parsing the GitHub App private key: no PEM block found")
+
+	_, err = parsePrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})))
+	assert.Error(t, err)
+}
+
+func TestNewTokenSource(t *testing.T) {
+	key, privateKey := generatePrivateKey(t)
+
+	created := 0
+	expiresIn := time.Hour
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path == "/api/v3/app/installations/789/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		claims := verifyJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &key.PublicKey)
+		assert.Equal(t, "123", claims["iss"])
+
+		created++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token": "token-%d", "expires_at": "%s"}`, created, time.Now().Add(expiresIn).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	auth := Authentication{AppID: 123, InstallationID: 456, PrivateKey: privateKey}
+	ts, err := NewTokenSource(auth, server.URL, nil)
+	require.NoError(t, err)
+
+	token, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	// the token is reused until it expires, also by the token sources of the next generations
+	ts, err = NewTokenSource(auth, server.URL, nil)
+	require.NoError(t, err)
+	token, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+	assert.Equal(t, 1, created)
+
+	// the token is renewed before it expires
+	expiresIn = tokenExpiryDelta
+	ts, err = NewTokenSource(Authentication{AppID: 123, InstallationID: 457, PrivateKey: privateKey}, server.URL, nil)
+	require.NoError(t, err)
+	token, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.AccessToken)
+	token, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-3", token.AccessToken)
+
+	// the errors of GitHub are reported
+	ts, err = NewTokenSource(Authentication{AppID: 123, InstallationID: 789, PrivateKey: privateKey}, server.URL, nil)
+	require.NoError(t, err)
+	_, err = ts.Token()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error creating a token for the GitHub App installation 789")
+
+	_, err = NewTokenSource(Authentication{AppID: 123, InstallationID: 456, PrivateKey: "not a key"}, server.URL, nil)
+	assert.Error(t, err)
+}
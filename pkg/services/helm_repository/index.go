@@ -0,0 +1,98 @@
+package helm_repository
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// IndexService lists the chart versions of a Helm repository serving an index.yaml.
+type IndexService struct {
+	client   *http.Client
+	repoURL  string
+	username string
+	password string
+}
+
+var _ ChartVersionService = &IndexService{}
+
+// repositoryIndex is the subset of the index.yaml of a Helm repository read by IndexService.
+type repositoryIndex struct {
+	Entries map[string][]struct {
+		Version    string `json:"version"`
+		AppVersion string `json:"appVersion"`
+	} `json:"entries"`
+}
+
+func NewIndexService(repoURL, username, password string) (ChartVersionService, error) {
+	if repoURL == "" {
+		return nil, fmt.Errorf("helm repository URL is required")
+	}
+	return &IndexService{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		repoURL:  strings.TrimSuffix(repoURL, "/"),
+		username: username,
+		password: password,
+	}, nil
+}
+
+func (s *IndexService) List(ctx context.Context, charts []string) ([]*ChartVersion, error) {
+	index, err := s.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(charts) == 0 {
+		for name := range index.Entries {
+			charts = append(charts, name)
+		}
+		sort.Strings(charts)
+	}
+
+	versions := []*ChartVersion{}
+	for _, name := range charts {
+		entries, ok := index.Entries[name]
+		if !ok {
+			return nil, fmt.Errorf("chart %s not found in helm repository %s", name, s.repoURL)
+		}
+		for _, entry := range entries {
+			versions = append(versions, &ChartVersion{Name: name, Version: entry.Version, AppVersion: entry.AppVersion})
+		}
+	}
+	return versions, nil
+}
+
+func (s *IndexService) getIndex(ctx context.Context) (*repositoryIndex, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.repoURL+"/index.yaml", nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching index of helm repository %s: %v", s.repoURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching index of helm repository %s: unexpected status %s", s.repoURL, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading index of helm repository %s: %v", s.repoURL, err)
+	}
+	index := &repositoryIndex{}
+	if err := yaml.Unmarshal(body, index); err != nil {
+		return nil, fmt.Errorf("error parsing index of helm repository %s: %v", s.repoURL, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,95 @@
+package helm_repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIndex = `apiVersion: v1
+entries:
+  guestbook:
+  - name: guestbook
+    version: 1.2.1
+    appVersion: "2.0"
+  - name: guestbook
+    version: 1.1.0
+    appVersion: "1.9"
+  redis:
+  - name: redis
+    version: 0.3.0
+generated: "2021-12-01T00:00:00Z"
+`
+
+func TestIndexServiceList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/charts/index.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, testIndex)
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	cases := []struct {
+		name          string
+		charts        []string
+		password      string
+		expected      []*ChartVersion
+		expectedError string
+	}{
+		{
+			name:     "all charts",
+			password: "pass",
+			expected: []*ChartVersion{
+				{Name: "guestbook", Version: "1.2.1", AppVersion: "2.0"},
+				{Name: "guestbook", Version: "1.1.0", AppVersion: "1.9"},
+				{Name: "redis", Version: "0.3.0"},
+			},
+		},
+		{
+			name:     "selected charts",
+			charts:   []string{"redis"},
+			password: "pass",
+			expected: []*ChartVersion{
+				{Name: "redis", Version: "0.3.0"},
+			},
+		},
+		{
+			name:          "unknown chart",
+			charts:        []string{"missing"},
+			password:      "pass",
+			expectedError: fmt.Sprintf("chart missing not found in helm repository %s/charts", ts.URL),
+		},
+		{
+			name:          "wrong password",
+			password:      "wrong",
+			expectedError: fmt.Sprintf("error fetching index of helm repository %s/charts: unexpected status 401 Unauthorized", ts.URL),
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			service, err := NewIndexService(ts.URL+"/charts/", "user", cc.password)
+			assert.NoError(t, err)
+
+			got, err := service.List(ctx, cc.charts)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package helm_repository
+
+import "context"
+
+type ChartVersion struct {
+	// Name is the name of the chart.
+	Name string
+	// Version is the version of the chart.
+	Version string
+	// AppVersion is the version of the application packaged by the chart. It is only known for repositories serving
+	// an index.yaml.
+	AppVersion string
+}
+
+type ChartVersionService interface {
+	// List gets the versions of the charts. If charts is empty, the versions of all the charts of the repository are
+	// returned.
+	List(ctx context.Context, charts []string) ([]*ChartVersion, error)
+}
@@ -0,0 +1,179 @@
+package helm_repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OCIService lists the chart versions of a Helm repository stored in an OCI registry, from the tags of the chart
+// repositories.
+type OCIService struct {
+	client *http.Client
+	// scheme of the registry API, overridden in tests.
+	scheme     string
+	registry   string
+	repository string
+	username   string
+	password   string
+}
+
+var _ ChartVersionService = &OCIService{}
+
+// NewOCIService returns a service for the OCI registry at repoURL, e.g. oci://registry.example.com/charts.
+func NewOCIService(repoURL, username, password string) (ChartVersionService, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme != "oci" || u.Host == "" {
+		return nil, fmt.Errorf("invalid OCI repository URL %q: must be oci://<registry>/<repository>", repoURL)
+	}
+	return &OCIService{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		scheme:     "https",
+		registry:   u.Host,
+		repository: strings.Trim(u.Path, "/"),
+		username:   username,
+		password:   password,
+	}, nil
+}
+
+func (s *OCIService) List(ctx context.Context, charts []string) ([]*ChartVersion, error) {
+	if len(charts) == 0 {
+		return nil, fmt.Errorf("the charts of an OCI repository must be listed, as OCI registries can't be browsed")
+	}
+
+	versions := []*ChartVersion{}
+	for _, name := range charts {
+		repository := strings.TrimPrefix(s.repository+"/"+name, "/")
+		tags, err := s.listTags(ctx, repository)
+		if err != nil {
+			return nil, fmt.Errorf("error listing versions of chart %s in %s: %v", name, s.registry, err)
+		}
+		for _, tag := range tags {
+			// Helm replaces the + of build metadata, which is not allowed in tags, with _.
+			versions = append(versions, &ChartVersion{Name: name, Version: strings.ReplaceAll(tag, "_", "+")})
+		}
+	}
+	return versions, nil
+}
+
+// linkNextRegexp matches the Link header of a paginated tag list.
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// listTags lists all the tags of a repository of the registry, following pagination.
+func (s *OCIService) listTags(ctx context.Context, repository string) ([]string, error) {
+	tags := []string{}
+	next := fmt.Sprintf("/v2/%s/tags/list", repository)
+	authorization := ""
+	for next != "" {
+		resp, err := s.get(ctx, next, authorization)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			authorization, err = s.authorize(ctx, challenge)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		page := struct {
+			Tags []string `json:"tags"`
+		}{}
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			return json.NewDecoder(resp.Body).Decode(&page)
+		}()
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+
+		next = ""
+		if match := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next = match[1]
+		}
+	}
+	return tags, nil
+}
+
+func (s *OCIService) get(ctx context.Context, path, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", s.scheme, s.registry, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return s.client.Do(req)
+}
+
+// challengeParamRegexp matches the parameters of a WWW-Authenticate header.
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize returns the Authorization header answering the WWW-Authenticate challenge of the registry: either the
+// basic credentials, or a bearer token obtained from the token service of the registry.
+func (s *OCIService) authorize(ctx context.Context, challenge string) (string, error) {
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		if s.username == "" && s.password == "" {
+			return "", fmt.Errorf("registry requires credentials")
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(s.username, s.password)
+		return req.Header.Get("Authorization"), nil
+	}
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer") {
+		return "", fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry authentication challenge has no realm")
+	}
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching registry token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching registry token: unexpected status %s", resp.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding registry token: %v", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
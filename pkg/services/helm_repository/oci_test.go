@@ -0,0 +1,71 @@
+package helm_repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOCIServiceList(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "registry", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:charts/guestbook:pull", r.URL.Query().Get("scope"))
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"token":"registry-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:charts/guestbook:pull"`, ts.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/charts/guestbook/tags/list" && r.URL.Query().Get("last") == "":
+			w.Header().Set("Link", `</v2/charts/guestbook/tags/list?last=1.1.0&n=2>; rel="next"`)
+			_, _ = fmt.Fprint(w, `{"name":"charts/guestbook","tags":["1.0.0","1.1.0"]}`)
+		case r.URL.Path == "/v2/charts/guestbook/tags/list":
+			_, _ = fmt.Fprint(w, `{"name":"charts/guestbook","tags":["1.2.0_build.1"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	newService := func(password string) ChartVersionService {
+		service, err := NewOCIService(fmt.Sprintf("oci://%s/charts", tsURL.Host), "user", password)
+		assert.NoError(t, err)
+		service.(*OCIService).scheme = "http"
+		return service
+	}
+
+	got, err := newService("pass").List(ctx, []string{"guestbook"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*ChartVersion{
+		{Name: "guestbook", Version: "1.0.0"},
+		{Name: "guestbook", Version: "1.1.0"},
+		{Name: "guestbook", Version: "1.2.0+build.1"},
+	}, got)
+
+	_, err = newService("wrong").List(ctx, []string{"guestbook"})
+	assert.EqualError(t, err, fmt.Sprintf("error listing versions of chart guestbook in %s: error fetching registry token: unexpected status 401 Unauthorized", tsURL.Host))
+
+	_, err = newService("pass").List(ctx, nil)
+	assert.EqualError(t, err, "the charts of an OCI repository must be listed, as OCI registries can't be browsed")
+
+	_, err = NewOCIService("https://registry.example.com/charts", "", "")
+	assert.EqualError(t, err, "invalid OCI repository URL \"https://registry.example.com/charts\": must be oci://<registry>/<repository>")
+}
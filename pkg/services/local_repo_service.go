@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// localRepos reads the files and directories of local checkouts of the repositories, rather than fetching them from
+// the Git servers, e.g. to render ApplicationSets offline. The revisions are ignored: the working trees are read as
+// they are.
+type localRepos struct {
+	// dirs are the directories of the checkouts, by normalized repository URL.
+	dirs map[string]string
+	// defaultDir, if set, is the checkout of the repositories which are not in dirs.
+	defaultDir string
+}
+
+var _ Repos = (*localRepos)(nil)
+
+// NewLocalRepos returns the repositories of the local checkouts: dirs are the directories of the checkouts by
+// repository URL, and defaultDir, if set, is the checkout of any other repository.
+func NewLocalRepos(dirs map[string]string, defaultDir string) Repos {
+	normalized := map[string]string{}
+	for repoURL, dir := range dirs {
+		normalized[normalizeRepoURL(repoURL)] = dir
+	}
+	return &localRepos{dirs: normalized, defaultDir: defaultDir}
+}
+
+// normalizeRepoURL returns the URL of a repository without the trailing slash or .git suffix, so that the URLs of
+// the ApplicationSets match the URLs of the checkouts with or without them.
+func normalizeRepoURL(repoURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(repoURL), "/"), ".git")
+}
+
+func (l *localRepos) dir(repoURL string) (string, error) {
+	if dir, ok := l.dirs[normalizeRepoURL(repoURL)]; ok {
+		return dir, nil
+	}
+	if l.defaultDir != "" {
+		return l.defaultDir, nil
+	}
+	return "", fmt.Errorf("no local checkout of the repository %s", repoURL)
+}
+
+func (l *localRepos) GetFiles(ctx context.Context, repoURL string, revision string, pattern string) (map[string][]byte, error) {
+	root, err := l.dir(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	matcher, err := newPathspecMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string][]byte{}
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, fnErr error) error {
+		if fnErr != nil {
+			return fnErr
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if !matcher(relativePath) {
+			return nil
+		}
+
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		res[relativePath] = bytes
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error listing the files of the local checkout of %s: %v", repoURL, err)
+	}
+	return res, nil
+}
+
+func (l *localRepos) GetDirectories(ctx context.Context, repoURL string, revision string) ([]string, error) {
+	root, err := l.dir(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The directories are listed like the directories of the checkouts of the Argo CD repositories
+	filteredPaths := []string{}
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, fnErr error) error {
+		if fnErr != nil {
+			return fnErr
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if relativePath == "." {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		filteredPaths = append(filteredPaths, filepath.ToSlash(relativePath))
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error listing the directories of the local checkout of %s: %v", repoURL, err)
+	}
+	return filteredPaths, nil
+}
+
+// newPathspecMatcher returns a matcher of the paths matched by a Git pathspec, like the paths listed by
+// `git ls-files <pattern>`: the '*' wildcards also match the '/' separators, and a pattern without wildcards matches
+// a path or the paths under a directory.
+func newPathspecMatcher(pattern string) (func(path string) bool, error) {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.ContainsAny(pattern, "*?[") {
+		dir := strings.TrimSuffix(pattern, "/")
+		return func(path string) bool {
+			return dir == "" || path == dir || strings.HasPrefix(path, dir+"/")
+		}, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the path pattern %q: %v", pattern, err)
+	}
+	return re.MatchString, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLocalRepo(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLocalReposGetFiles(t *testing.T) {
+	dir := writeLocalRepo(t, map[string]string{
+		"apps/guestbook/config.json":   `{"name": "guestbook"}`,
+		"apps/helm/nested/config.json": `{"name": "helm"}`,
+		"apps/README.md":               "apps",
+		"cluster-config/a.yaml":        "a",
+		".git/config":                  "[core]",
+	})
+	repos := NewLocalRepos(map[string]string{"https://github.com/argoproj/argocd-example-apps.git": dir}, "")
+
+	for _, c := range []struct {
+		pattern  string
+		expected []string
+	}{
+		{"apps/*/config.json", []string{"apps/guestbook/config.json", "apps/helm/nested/config.json"}},
+		{"apps/gu?stbook/*.json", []string{"apps/guestbook/config.json"}},
+		{"apps/[gh]*/config.json", []string{"apps/guestbook/config.json", "apps/helm/nested/config.json"}},
+		{"apps/[!g]*/config.json", []string{"apps/helm/nested/config.json"}},
+		{"cluster-config", []string{"cluster-config/a.yaml"}},
+		{"apps/README.md", []string{"apps/README.md"}},
+		{"*.toml", []string{}},
+	} {
+		t.Run(c.pattern, func(t *testing.T) {
+			// the URLs match with or without the .git suffix
+			files, err := repos.GetFiles(context.Background(), "https://github.com/argoproj/argocd-example-apps/", "HEAD", c.pattern)
+			require.NoError(t, err)
+			paths := []string{}
+			for path := range files {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			assert.Equal(t, c.expected, paths)
+		})
+	}
+
+	files, err := repos.GetFiles(context.Background(), "https://github.com/argoproj/argocd-example-apps", "HEAD", "apps/guestbook/config.json")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"apps/guestbook/config.json": []byte(`{"name": "guestbook"}`)}, files)
+
+	_, err = repos.GetFiles(context.Background(), "https://github.com/argoproj/other", "HEAD", "*")
+	assert.EqualError(t, err, "no local checkout of the repository https://github.com/argoproj/other")
+}
+
+func TestLocalReposGetDirectories(t *testing.T) {
+	dir := writeLocalRepo(t, map[string]string{
+		"apps/guestbook/config.json": "{}",
+		"apps/helm/values.yaml":      "",
+		".github/workflows/ci.yaml":  "",
+		".git/config":                "[core]",
+	})
+	// the default checkout is used for all the repositories
+	repos := NewLocalRepos(nil, dir)
+
+	dirs, err := repos.GetDirectories(context.Background(), "https://github.com/argoproj/other", "main")
+	require.NoError(t, err)
+	sort.Strings(dirs)
+	assert.Equal(t, []string{"apps", "apps/guestbook", "apps/helm"}, dirs)
+}
@@ -0,0 +1,161 @@
+package pull_request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+const (
+	// AzureDevOpsDefaultAPI is the URL of the hosted Azure DevOps Services.
+	AzureDevOpsDefaultAPI = "https://dev.azure.com"
+	azureDevOpsAPIVersion = "6.0"
+	azureDevOpsPageSize   = 100
+)
+
+type AzureDevOpsService struct {
+	client       *http.Client
+	api          string
+	token        string
+	organization string
+	project      string
+	repo         string
+	targetBranch string
+	labels       LabelFilter
+	// excludeDrafts skips the pull requests marked as draft.
+	excludeDrafts bool
+}
+
+var _ PullRequestService = (*AzureDevOpsService)(nil)
+
+// azureDevOpsPullRequestList is the subset of the Azure Repos pull request list response used by the generator.
+type azureDevOpsPullRequestList struct {
+	Value []struct {
+		PullRequestID int       `json:"pullRequestId"`
+		Title         string    `json:"title"`
+		IsDraft       bool      `json:"isDraft"`
+		CreationDate  time.Time `json:"creationDate"`
+		CreatedBy     struct {
+			UniqueName string `json:"uniqueName"`
+		} `json:"createdBy"`
+		SourceRefName         string `json:"sourceRefName"`
+		TargetRefName         string `json:"targetRefName"`
+		LastMergeSourceCommit struct {
+			CommitID string `json:"commitId"`
+		} `json:"lastMergeSourceCommit"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"value"`
+}
+
+// NewAzureDevOpsService returns a service listing the active pull requests of an Azure Repos repository. token is a
+// personal access token (PAT). If targetBranch is set, only pull requests targeting that branch are returned. If
+// excludeDrafts is set, draft pull requests are skipped. The requests are sent with the httpClient, or with the default
+// client if nil.
+func NewAzureDevOpsService(ctx context.Context, token, url, organization, project, repo, targetBranch string, labels LabelFilter, excludeDrafts bool, httpClient *http.Client) (PullRequestService, error) {
+	// Undocumented environment variable to set a default token, to be used in testing.
+	if token == "" {
+		token = os.Getenv("AZURE_DEVOPS_TOKEN")
+	}
+	if url == "" {
+		url = AzureDevOpsDefaultAPI
+	}
+	return &AzureDevOpsService{
+		client:        metrics.NewSCMClient("azure-devops", httpClient),
+		api:           strings.TrimSuffix(url, "/"),
+		token:         token,
+		organization:  organization,
+		project:       project,
+		repo:          repo,
+		targetBranch:  targetBranch,
+		labels:        labels,
+		excludeDrafts: excludeDrafts,
+	}, nil
+}
+
+func (a *AzureDevOpsService) List(ctx context.Context) ([]*PullRequest, error) {
+	pullRequests := []*PullRequest{}
+	for skip := 0; ; skip += azureDevOpsPageSize {
+		page, err := a.listPage(ctx, skip)
+		if err != nil {
+			return nil, fmt.Errorf("error listing pull requests for %s/%s/%s: %v", a.organization, a.project, a.repo, err)
+		}
+		for _, pull := range page.Value {
+			if a.excludeDrafts && pull.IsDraft {
+				continue
+			}
+			labels := make([]string, 0, len(pull.Labels))
+			for _, label := range pull.Labels {
+				labels = append(labels, label.Name)
+			}
+			if !a.labels.Match(labels) {
+				continue
+			}
+			pullRequests = append(pullRequests, &PullRequest{
+				Number:       pull.PullRequestID,
+				Branch:       strings.TrimPrefix(pull.SourceRefName, "refs/heads/"),
+				HeadSHA:      pull.LastMergeSourceCommit.CommitID,
+				TargetBranch: strings.TrimPrefix(pull.TargetRefName, "refs/heads/"),
+				Labels:       labels,
+				Author:       pull.CreatedBy.UniqueName,
+				Title:        pull.Title,
+				CreatedAt:    pull.CreationDate,
+			})
+		}
+		if len(page.Value) < azureDevOpsPageSize {
+			break
+		}
+	}
+	return pullRequests, nil
+}
+
+// listPage fetches a single page of active pull requests from the Azure DevOps API.
+func (a *AzureDevOpsService) listPage(ctx context.Context, skip int) (*azureDevOpsPullRequestList, error) {
+	query := url.Values{}
+	query.Set("api-version", azureDevOpsAPIVersion)
+	query.Set("searchCriteria.status", "active")
+	query.Set("$top", fmt.Sprint(azureDevOpsPageSize))
+	query.Set("$skip", fmt.Sprint(skip))
+	if a.targetBranch != "" {
+		targetRef := a.targetBranch
+		if !strings.HasPrefix(targetRef, "refs/") {
+			targetRef = "refs/heads/" + targetRef
+		}
+		query.Set("searchCriteria.targetRefName", targetRef)
+	}
+	u := fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/pullrequests?%s",
+		a.api, url.PathEscape(a.organization), url.PathEscape(a.project), url.PathEscape(a.repo), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if a.token != "" {
+		// Azure DevOps accepts a personal access token as the password of a basic auth header with an empty user.
+		req.SetBasicAuth("", a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, u)
+	}
+
+	page := &azureDevOpsPullRequestList{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,93 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const azureDevOpsPullsResponse = `{
+	"count": 2,
+	"value": [
+		{
+			"pullRequestId": 1,
+			"title": "Add feature A",
+			"creationDate": "2021-09-01T10:00:00Z",
+			"createdBy": {"uniqueName": "alice@example.com"},
+			"sourceRefName": "refs/heads/feature-a",
+			"targetRefName": "refs/heads/main",
+			"lastMergeSourceCommit": {"commitId": "089d92cbf9ff857a39e6feccd32798ca700fb958"},
+			"labels": [{"name": "preview"}]
+		},
+		{
+			"pullRequestId": 2,
+			"isDraft": true,
+			"sourceRefName": "refs/heads/feature-b",
+			"targetRefName": "refs/heads/main",
+			"lastMergeSourceCommit": {"commitId": "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d"}
+		}
+	]
+}`
+
+func TestAzureDevOpsList(t *testing.T) {
+	var gotTargetRef string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/myorg/myproject/_apis/git/repositories/myrepo/pullrequests", r.URL.Path)
+		assert.Equal(t, "active", r.URL.Query().Get("searchCriteria.status"))
+		_, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "pat", password)
+		gotTargetRef = r.URL.Query().Get("searchCriteria.targetRefName")
+		_, _ = fmt.Fprint(w, azureDevOpsPullsResponse)
+	}))
+	defer ts.Close()
+
+	cases := []struct {
+		name              string
+		targetBranch      string
+		labels            []string
+		excludeDrafts     bool
+		expectedTargetRef string
+		expected          []*PullRequest
+	}{
+		{
+			name: "no filters",
+			expected: []*PullRequest{
+				{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice@example.com", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)},
+				{Number: 2, Branch: "feature-b", HeadSHA: "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d", TargetBranch: "main", Labels: []string{}},
+			},
+		},
+		{
+			name:          "exclude drafts",
+			excludeDrafts: true,
+			expected: []*PullRequest{
+				{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice@example.com", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:              "target branch and label",
+			targetBranch:      "main",
+			labels:            []string{"preview"},
+			expectedTargetRef: "refs/heads/main",
+			expected: []*PullRequest{
+				{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice@example.com", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, err := NewAzureDevOpsService(context.Background(), "pat", ts.URL, "myorg", "myproject", "myrepo", c.targetBranch, LabelFilter{Labels: c.labels}, c.excludeDrafts, nil)
+			assert.NoError(t, err)
+			pulls, err := svc.List(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, pulls)
+			assert.Equal(t, c.expectedTargetRef, gotTargetRef)
+		})
+	}
+}
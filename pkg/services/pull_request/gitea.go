@@ -0,0 +1,144 @@
+package pull_request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+// giteaPageSize is the number of pull requests requested per page. Gitea caps this value server-side with its
+// MAX_RESPONSE_ITEMS setting, so a page may have fewer entries than requested without being the last page.
+const giteaPageSize = 50
+
+type GiteaService struct {
+	client *http.Client
+	api    string
+	token  string
+	owner  string
+	repo   string
+	labels LabelFilter
+}
+
+var _ PullRequestService = (*GiteaService)(nil)
+
+// giteaPullRequest is the subset of the Gitea pull request API object used by the generator.
+type giteaPullRequest struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref string `json:"ref"`
+		Sha string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// NewGiteaService returns a service listing the open pull requests of a Gitea repository. The requests are sent with
+// the httpClient, which may skip the verification of the certificates of self-hosted instances, or with the default
+// client if nil.
+func NewGiteaService(ctx context.Context, token, url, owner, repo string, labels LabelFilter, httpClient *http.Client) (PullRequestService, error) {
+	if url == "" {
+		return nil, fmt.Errorf("the Gitea API URL is required")
+	}
+	// Undocumented environment variable to set a default token, to be used in testing.
+	if token == "" {
+		token = os.Getenv("GITEA_TOKEN")
+	}
+	return &GiteaService{
+		client: metrics.NewSCMClient("gitea", httpClient),
+		api:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		owner:  owner,
+		repo:   repo,
+		labels: labels,
+	}, nil
+}
+
+func (g *GiteaService) List(ctx context.Context) ([]*PullRequest, error) {
+	pullRequests := []*PullRequest{}
+	listed := 0
+	for page := 1; ; page++ {
+		pulls, total, err := g.listPage(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("error listing pull requests for %s/%s: %v", g.owner, g.repo, err)
+		}
+		// The pages are listed until an empty page, or until the total count of pull requests is reached
+		if len(pulls) == 0 {
+			break
+		}
+		listed += len(pulls)
+		for _, pull := range pulls {
+			labels := make([]string, 0, len(pull.Labels))
+			for _, label := range pull.Labels {
+				labels = append(labels, label.Name)
+			}
+			if !g.labels.Match(labels) {
+				continue
+			}
+			pullRequests = append(pullRequests, &PullRequest{
+				Number:       pull.Number,
+				Branch:       pull.Head.Ref,
+				HeadSHA:      pull.Head.Sha,
+				TargetBranch: pull.Base.Ref,
+				Labels:       labels,
+				Author:       pull.User.Login,
+				Title:        pull.Title,
+				CreatedAt:    pull.CreatedAt,
+			})
+		}
+		if total >= 0 && listed >= total {
+			break
+		}
+	}
+	return pullRequests, nil
+}
+
+// listPage fetches a single page of open pull requests from the Gitea API, with the total count of open pull requests
+// from the X-Total-Count header, or -1 if the header is missing.
+func (g *GiteaService) listPage(ctx context.Context, page int) ([]*giteaPullRequest, int, error) {
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s/pulls?state=open&page=%d&limit=%d",
+		g.api, url.PathEscape(g.owner), url.PathEscape(g.repo), page, giteaPageSize)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status %s from %s", resp.Status, u)
+	}
+
+	pulls := []*giteaPullRequest{}
+	if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+		return nil, 0, fmt.Errorf("error decoding response: %v", err)
+	}
+	total, err := strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	if err != nil {
+		total = -1
+	}
+	return pulls, total, nil
+}
@@ -0,0 +1,143 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const giteaPullsResponse = `[
+	{
+		"number": 1,
+		"title": "Add feature A",
+		"created_at": "2021-09-01T10:00:00Z",
+		"user": {"login": "alice"},
+		"head": {"ref": "feature-a", "sha": "089d92cbf9ff857a39e6feccd32798ca700fb958"},
+		"base": {"ref": "main"},
+		"labels": [{"name": "preview"}]
+	},
+	{
+		"number": 2,
+		"head": {"ref": "feature-b", "sha": "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d"},
+		"base": {"ref": "release-1.0"},
+		"labels": []
+	}
+]`
+
+func newGiteaTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/myorg/myrepo/pulls", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") != "1" {
+			_, _ = fmt.Fprint(w, "[]")
+			return
+		}
+		_, _ = fmt.Fprint(w, giteaPullsResponse)
+	}))
+}
+
+func TestGiteaList(t *testing.T) {
+	ts := newGiteaTestServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		name     string
+		labels   []string
+		expected []*PullRequest
+	}{
+		{
+			name:   "no labels",
+			labels: nil,
+			expected: []*PullRequest{
+				{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)},
+				{Number: 2, Branch: "feature-b", HeadSHA: "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d", TargetBranch: "release-1.0", Labels: []string{}},
+			},
+		},
+		{
+			name:   "matching label",
+			labels: []string{"preview"},
+			expected: []*PullRequest{
+				{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:     "no matching label",
+			labels:   []string{"other"},
+			expected: []*PullRequest{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, err := NewGiteaService(context.Background(), "secret", ts.URL, "myorg", "myrepo", LabelFilter{Labels: c.labels}, nil)
+			assert.NoError(t, err)
+			pulls, err := svc.List(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, pulls)
+		})
+	}
+}
+
+func TestGiteaListPagination(t *testing.T) {
+	// The server caps the pages at fewer pull requests than requested, as Gitea does with a low MAX_RESPONSE_ITEMS
+	const maxResponseItems = 2
+	for _, withTotalCount := range []bool{true, false} {
+		t.Run(fmt.Sprintf("X-Total-Count %v", withTotalCount), func(t *testing.T) {
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				page, err := strconv.Atoi(r.URL.Query().Get("page"))
+				assert.NoError(t, err)
+				var pulls []string
+				for number := (page-1)*maxResponseItems + 1; number <= page*maxResponseItems && number <= 5; number++ {
+					pulls = append(pulls, fmt.Sprintf(`{"number":%d,"head":{"ref":"feature-%d"}}`, number, number))
+				}
+				if withTotalCount {
+					w.Header().Set("X-Total-Count", "5")
+				}
+				_, _ = fmt.Fprintf(w, "[%s]", strings.Join(pulls, ","))
+			}))
+			defer ts.Close()
+
+			svc, err := NewGiteaService(context.Background(), "", ts.URL, "myorg", "myrepo", LabelFilter{}, nil)
+			assert.NoError(t, err)
+			pulls, err := svc.List(context.Background())
+			assert.NoError(t, err)
+			if assert.Len(t, pulls, 5) {
+				assert.Equal(t, 5, pulls[4].Number)
+				assert.Equal(t, "feature-5", pulls[4].Branch)
+			}
+			// Without the total count, the pages are listed until an empty page
+			if withTotalCount {
+				assert.Equal(t, 3, requests)
+			} else {
+				assert.Equal(t, 4, requests)
+			}
+		})
+	}
+}
+
+func TestGiteaListError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	svc, err := NewGiteaService(context.Background(), "", ts.URL, "myorg", "myrepo", LabelFilter{}, nil)
+	assert.NoError(t, err)
+	_, err = svc.List(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNewGiteaServiceRequiresAPI(t *testing.T) {
+	_, err := NewGiteaService(context.Background(), "", "", "myorg", "myrepo", LabelFilter{}, nil)
+	assert.Error(t, err)
+}
@@ -3,22 +3,30 @@ package pull_request
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/google/go-github/v35/github"
 	"golang.org/x/oauth2"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+	"github.com/argoproj-labs/applicationset/pkg/services/github_app"
 )
 
 type GithubService struct {
 	client *github.Client
 	owner  string
 	repo   string
-	labels []string
+	labels LabelFilter
+	// excludeDrafts skips the pull requests marked as draft.
+	excludeDrafts bool
 }
 
 var _ PullRequestService = (*GithubService)(nil)
 
-func NewGithubService(ctx context.Context, token, url, owner, repo string, labels []string) (PullRequestService, error) {
+// NewGithubService returns a GitHub service authenticated with a token. The requests are sent with the httpClient, or
+// with the default client if nil.
+func NewGithubService(ctx context.Context, token, url, owner, repo string, labels LabelFilter, excludeDrafts bool, httpClient *http.Client) (PullRequestService, error) {
 	var ts oauth2.TokenSource
 	// Undocumented environment variable to set a default token, to be used in testing to dodge anonymous rate limits.
 	if token == "" {
@@ -29,22 +37,39 @@ func NewGithubService(ctx context.Context, token, url, owner, repo string, label
 			&oauth2.Token{AccessToken: token},
 		)
 	}
-	httpClient := oauth2.NewClient(ctx, ts)
+	return newGithubService(ctx, ts, url, owner, repo, labels, excludeDrafts, httpClient)
+}
+
+// NewGithubAppService returns a GitHub service authenticated with the installation tokens of a GitHub App.
+func NewGithubAppService(ctx context.Context, auth github_app.Authentication, url, owner, repo string, labels LabelFilter, excludeDrafts bool, httpClient *http.Client) (PullRequestService, error) {
+	ts, err := github_app.NewTokenSource(auth, url, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return newGithubService(ctx, ts, url, owner, repo, labels, excludeDrafts, httpClient)
+}
+
+func newGithubService(ctx context.Context, ts oauth2.TokenSource, url, owner, repo string, labels LabelFilter, excludeDrafts bool, httpClient *http.Client) (PullRequestService, error) {
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+	scmClient := metrics.NewSCMClient("github", oauth2.NewClient(ctx, ts))
 	var client *github.Client
 	if url == "" {
-		client = github.NewClient(httpClient)
+		client = github.NewClient(scmClient)
 	} else {
 		var err error
-		client, err = github.NewEnterpriseClient(url, url, httpClient)
+		client, err = github.NewEnterpriseClient(url, url, scmClient)
 		if err != nil {
 			return nil, err
 		}
 	}
 	return &GithubService{
-		client: client,
-		owner:  owner,
-		repo:   repo,
-		labels: labels,
+		client:        client,
+		owner:         owner,
+		repo:          repo,
+		labels:        labels,
+		excludeDrafts: excludeDrafts,
 	}, nil
 }
 
@@ -61,13 +86,22 @@ func (g *GithubService) List(ctx context.Context) ([]*PullRequest, error) {
 			return nil, fmt.Errorf("error listing pull requests for %s/%s: %v", g.owner, g.repo, err)
 		}
 		for _, pull := range pulls {
-			if !containLabels(g.labels, pull.Labels) {
+			if g.excludeDrafts && pull.GetDraft() {
+				continue
+			}
+			labels := getGithubLabelNames(pull.Labels)
+			if !g.labels.Match(labels) {
 				continue
 			}
 			pullRequests = append(pullRequests, &PullRequest{
-				Number:  *pull.Number,
-				Branch:  *pull.Head.Ref,
-				HeadSHA: *pull.Head.SHA,
+				Number:       *pull.Number,
+				Branch:       *pull.Head.Ref,
+				HeadSHA:      *pull.Head.SHA,
+				TargetBranch: *pull.Base.Ref,
+				Labels:       labels,
+				Author:       pull.GetUser().GetLogin(),
+				Title:        pull.GetTitle(),
+				CreatedAt:    pull.GetCreatedAt(),
 			})
 		}
 		if resp.NextPage == 0 {
@@ -78,22 +112,14 @@ func (g *GithubService) List(ctx context.Context) ([]*PullRequest, error) {
 	return pullRequests, nil
 }
 
-// containLabels returns true if gotLabels contains expectedLabels
-func containLabels(expectedLabels []string, gotLabels []*github.Label) bool {
-	for _, expected := range expectedLabels {
-		found := false
-		for _, got := range gotLabels {
-			if got.Name == nil {
-				continue
-			}
-			if expected == *got.Name {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
+// getGithubLabelNames returns the names of the given labels
+func getGithubLabelNames(gotLabels []*github.Label) []string {
+	labels := make([]string, 0, len(gotLabels))
+	for _, got := range gotLabels {
+		if got.Name == nil {
+			continue
 		}
+		labels = append(labels, *got.Name)
 	}
-	return true
+	return labels
 }
@@ -10,7 +10,7 @@ func toPtr(s string) *string {
 	return &s
 }
 
-func TestContainLabels(t *testing.T) {
+func TestGithubLabelFilter(t *testing.T) {
 	cases := []struct {
 		Name       string
 		Labels     []string
@@ -37,6 +37,15 @@ func TestContainLabels(t *testing.T) {
 			},
 			Expect: false,
 		},
+		{
+			Name:   "Label without name",
+			Labels: []string{"label1"},
+			PullLabels: []*github.Label{
+				&github.Label{Name: nil},
+				&github.Label{Name: toPtr("label1")},
+			},
+			Expect: true,
+		},
 		{
 			Name:   "No specify",
 			Labels: []string{},
@@ -51,7 +60,8 @@ func TestContainLabels(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
-			if got := containLabels(c.Labels, c.PullLabels); got != c.Expect {
+			filter := LabelFilter{Labels: c.Labels}
+			if got := filter.Match(getGithubLabelNames(c.PullLabels)); got != c.Expect {
 				t.Errorf("expect: %v, got: %v", c.Expect, got)
 			}
 		})
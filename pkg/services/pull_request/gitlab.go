@@ -0,0 +1,99 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	gitlab "github.com/xanzy/go-gitlab"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+)
+
+type GitLabService struct {
+	client  *gitlab.Client
+	project string
+	labels  LabelFilter
+	// excludeDrafts skips the merge requests marked as draft.
+	excludeDrafts bool
+}
+
+var _ PullRequestService = (*GitLabService)(nil)
+
+// NewGitLabService returns a service listing the open merge requests of a GitLab project. project is either the
+// numeric ID or the full path of the project.
+func NewGitLabService(ctx context.Context, token, url, project string, labels LabelFilter, excludeDrafts bool, httpClient *http.Client) (PullRequestService, error) {
+	// Undocumented environment variable to set a default token, to be used in testing to dodge anonymous rate limits.
+	if token == "" {
+		token = os.Getenv("GITLAB_TOKEN")
+	}
+	var client *gitlab.Client
+	if url == "" {
+		var err error
+		client, err = gitlab.NewClient(token, gitlab.WithHTTPClient(metrics.NewSCMClient("gitlab", httpClient)))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		client, err = gitlab.NewClient(token, gitlab.WithBaseURL(url), gitlab.WithHTTPClient(metrics.NewSCMClient("gitlab", httpClient)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &GitLabService{
+		client:        client,
+		project:       project,
+		labels:        labels,
+		excludeDrafts: excludeDrafts,
+	}, nil
+}
+
+func (g *GitLabService) List(ctx context.Context) ([]*PullRequest, error) {
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		State: gitlab.String("opened"),
+	}
+	pullRequests := []*PullRequest{}
+	for {
+		mrs, resp, err := g.client.MergeRequests.ListProjectMergeRequests(g.project, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("error listing merge requests for %s: %v", g.project, err)
+		}
+		for _, mr := range mrs {
+			if g.excludeDrafts && mr.WorkInProgress {
+				continue
+			}
+			labels := []string(mr.Labels)
+			if labels == nil {
+				labels = []string{}
+			}
+			if !g.labels.Match(labels) {
+				continue
+			}
+			pullRequest := &PullRequest{
+				Number:       mr.IID,
+				Branch:       mr.SourceBranch,
+				HeadSHA:      mr.SHA,
+				TargetBranch: mr.TargetBranch,
+				Labels:       labels,
+				Title:        mr.Title,
+			}
+			if mr.Author != nil {
+				pullRequest.Author = mr.Author.Username
+			}
+			if mr.CreatedAt != nil {
+				pullRequest.CreatedAt = *mr.CreatedAt
+			}
+			pullRequests = append(pullRequests, pullRequest)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return pullRequests, nil
+}
@@ -0,0 +1,88 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gitlabMergeRequestsResponse = `[
+	{
+		"iid": 1,
+		"title": "Add feature A",
+		"created_at": "2021-09-01T10:00:00Z",
+		"author": {"username": "alice"},
+		"source_branch": "feature-a",
+		"target_branch": "main",
+		"sha": "089d92cbf9ff857a39e6feccd32798ca700fb958",
+		"labels": ["preview"],
+		"work_in_progress": false
+	},
+	{
+		"iid": 2,
+		"title": "Draft: Add feature B",
+		"created_at": "2021-09-02T10:00:00Z",
+		"author": {"username": "bob"},
+		"source_branch": "feature-b",
+		"target_branch": "main",
+		"sha": "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d",
+		"labels": [],
+		"work_in_progress": true
+	}
+]`
+
+func TestGitLabList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/merge_requests"):
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			_, _ = fmt.Fprint(w, gitlabMergeRequestsResponse)
+		default:
+			// go-gitlab requests the API root before the merge requests
+			_, _ = fmt.Fprint(w, "{}")
+		}
+	}))
+	defer ts.Close()
+
+	featureA := &PullRequest{Number: 1, Branch: "feature-a", HeadSHA: "089d92cbf9ff857a39e6feccd32798ca700fb958", TargetBranch: "main", Labels: []string{"preview"}, Author: "alice", Title: "Add feature A", CreatedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)}
+	featureB := &PullRequest{Number: 2, Branch: "feature-b", HeadSHA: "5a5f5ecb5b0b2a8b6a0d2d9e1c1a8d2f0b7e6c4d", TargetBranch: "main", Labels: []string{}, Author: "bob", Title: "Draft: Add feature B", CreatedAt: time.Date(2021, 9, 2, 10, 0, 0, 0, time.UTC)}
+
+	cases := []struct {
+		name          string
+		labels        LabelFilter
+		excludeDrafts bool
+		expected      []*PullRequest
+	}{
+		{
+			name:     "no filters",
+			expected: []*PullRequest{featureA, featureB},
+		},
+		{
+			name:     "matching label",
+			labels:   LabelFilter{Labels: []string{"preview"}},
+			expected: []*PullRequest{featureA},
+		},
+		{
+			name:          "exclude drafts",
+			excludeDrafts: true,
+			expected:      []*PullRequest{featureA},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, err := NewGitLabService(context.Background(), "token", ts.URL, "myorg/myrepo", c.labels, c.excludeDrafts, nil)
+			assert.NoError(t, err)
+			pulls, err := svc.List(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, pulls)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package pull_request
 
-import "context"
+import (
+	"context"
+	"regexp"
+	"time"
+)
 
 type PullRequest struct {
 	// Number is a number that will be the ID of the pull request.
@@ -9,9 +13,25 @@ type PullRequest struct {
 	Branch string
 	// HeadSHA is the SHA of the HEAD from which the pull request originated.
 	HeadSHA string
+	// TargetBranch is the name of the branch the pull request is meant to be merged into.
+	TargetBranch string
+	// Labels are the names of the labels set on the pull request.
+	Labels []string
+	// Author is the login of the user who opened the pull request.
+	Author string
+	// Title is the title of the pull request.
+	Title string
+	// CreatedAt is the time at which the pull request was opened.
+	CreatedAt time.Time
 }
 
 type PullRequestService interface {
 	// List gets a list of pull requests.
 	List(ctx context.Context) ([]*PullRequest, error)
 }
+
+// A compiled version of PullRequestGeneratorFilter for performance.
+type Filter struct {
+	BranchMatch       *regexp.Regexp
+	TargetBranchMatch *regexp.Regexp
+}
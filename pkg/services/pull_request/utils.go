@@ -0,0 +1,117 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func compileFilters(filters []argoprojiov1alpha1.PullRequestGeneratorFilter) ([]*Filter, error) {
+	outFilters := make([]*Filter, 0, len(filters))
+	for _, filter := range filters {
+		outFilter := &Filter{}
+		var err error
+		if filter.BranchMatch != nil {
+			outFilter.BranchMatch, err = regexp.Compile(*filter.BranchMatch)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling BranchMatch regexp %q: %v", *filter.BranchMatch, err)
+			}
+		}
+		if filter.TargetBranchMatch != nil {
+			outFilter.TargetBranchMatch, err = regexp.Compile(*filter.TargetBranchMatch)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling TargetBranchMatch regexp %q: %v", *filter.TargetBranchMatch, err)
+			}
+		}
+		outFilters = append(outFilters, outFilter)
+	}
+	return outFilters, nil
+}
+
+func matchFilter(pullRequest *PullRequest, filter *Filter) bool {
+	if filter.BranchMatch != nil && !filter.BranchMatch.MatchString(pullRequest.Branch) {
+		return false
+	}
+	if filter.TargetBranchMatch != nil && !filter.TargetBranchMatch.MatchString(pullRequest.TargetBranch) {
+		return false
+	}
+
+	return true
+}
+
+// ListPullRequests lists the pull requests of the given service which pass at least one of the given filters.
+func ListPullRequests(ctx context.Context, service PullRequestService, filters []argoprojiov1alpha1.PullRequestGeneratorFilter) ([]*PullRequest, error) {
+	compiledFilters, err := compileFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Special case, if we have no filters, allow everything.
+	if len(compiledFilters) == 0 {
+		return pullRequests, nil
+	}
+
+	filteredPullRequests := make([]*PullRequest, 0, len(pullRequests))
+	for _, pullRequest := range pullRequests {
+		for _, filter := range compiledFilters {
+			if matchFilter(pullRequest, filter) {
+				filteredPullRequests = append(filteredPullRequests, pullRequest)
+				break
+			}
+		}
+	}
+	return filteredPullRequests, nil
+}
+
+// LabelFilter holds the label conditions a pull request must satisfy to be included.
+type LabelFilter struct {
+	// Labels must all be present on the pull request.
+	Labels []string
+	// AnyLabels, if not empty, must have at least one entry present on the pull request.
+	AnyLabels []string
+	// NotLabels must all be absent from the pull request.
+	NotLabels []string
+}
+
+// Match returns true if a pull request with the given labels satisfies the filter.
+func (f LabelFilter) Match(labels []string) bool {
+	for _, expected := range f.Labels {
+		if !containLabel(labels, expected) {
+			return false
+		}
+	}
+	if len(f.AnyLabels) > 0 {
+		found := false
+		for _, expected := range f.AnyLabels {
+			if containLabel(labels, expected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, excluded := range f.NotLabels {
+		if containLabel(labels, excluded) {
+			return false
+		}
+	}
+	return true
+}
+
+func containLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package pull_request
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func strp(s string) *string {
+	return &s
+}
+
+func newFilterTestService() PullRequestService {
+	svc, _ := NewFakeService(
+		context.Background(),
+		[]*PullRequest{
+			{
+				Number:       1,
+				Branch:       "feature-a",
+				TargetBranch: "main",
+			},
+			{
+				Number:       2,
+				Branch:       "feature-b",
+				TargetBranch: "release-1.0",
+			},
+			{
+				Number:       3,
+				Branch:       "hotfix-c",
+				TargetBranch: "main",
+			},
+		},
+		nil,
+	)
+	return svc
+}
+
+func TestFilterTargetBranchMatch(t *testing.T) {
+	filters := []argoprojiov1alpha1.PullRequestGeneratorFilter{
+		{
+			TargetBranchMatch: strp("^main$"),
+		},
+	}
+	pulls, err := ListPullRequests(context.Background(), newFilterTestService(), filters)
+	assert.Nil(t, err)
+	assert.Len(t, pulls, 2)
+	assert.Equal(t, 1, pulls[0].Number)
+	assert.Equal(t, 3, pulls[1].Number)
+}
+
+func TestFilterBranchMatch(t *testing.T) {
+	filters := []argoprojiov1alpha1.PullRequestGeneratorFilter{
+		{
+			BranchMatch: strp("^feature-"),
+		},
+	}
+	pulls, err := ListPullRequests(context.Background(), newFilterTestService(), filters)
+	assert.Nil(t, err)
+	assert.Len(t, pulls, 2)
+	assert.Equal(t, 1, pulls[0].Number)
+	assert.Equal(t, 2, pulls[1].Number)
+}
+
+func TestMultiFilterAnd(t *testing.T) {
+	filters := []argoprojiov1alpha1.PullRequestGeneratorFilter{
+		{
+			BranchMatch:       strp("^feature-"),
+			TargetBranchMatch: strp("^main$"),
+		},
+	}
+	pulls, err := ListPullRequests(context.Background(), newFilterTestService(), filters)
+	assert.Nil(t, err)
+	assert.Len(t, pulls, 1)
+	assert.Equal(t, 1, pulls[0].Number)
+}
+
+func TestMultiFilterOr(t *testing.T) {
+	filters := []argoprojiov1alpha1.PullRequestGeneratorFilter{
+		{
+			BranchMatch: strp("^hotfix-"),
+		},
+		{
+			TargetBranchMatch: strp("^release-"),
+		},
+	}
+	pulls, err := ListPullRequests(context.Background(), newFilterTestService(), filters)
+	assert.Nil(t, err)
+	assert.Len(t, pulls, 2)
+	assert.Equal(t, 2, pulls[0].Number)
+	assert.Equal(t, 3, pulls[1].Number)
+}
+
+func TestNoFilters(t *testing.T) {
+	pulls, err := ListPullRequests(context.Background(), newFilterTestService(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, pulls, 3)
+}
+
+func TestInvalidFilter(t *testing.T) {
+	filters := []argoprojiov1alpha1.PullRequestGeneratorFilter{
+		{
+			TargetBranchMatch: strp("("),
+		},
+	}
+	_, err := ListPullRequests(context.Background(), newFilterTestService(), filters)
+	assert.NotNil(t, err)
+}
+
+func TestLabelFilterMatch(t *testing.T) {
+	cases := []struct {
+		name   string
+		filter LabelFilter
+		labels []string
+		expect bool
+	}{
+		{
+			name:   "empty filter",
+			filter: LabelFilter{},
+			labels: []string{"preview"},
+			expect: true,
+		},
+		{
+			name:   "all labels present",
+			filter: LabelFilter{Labels: []string{"preview", "team-a"}},
+			labels: []string{"preview", "team-a", "other"},
+			expect: true,
+		},
+		{
+			name:   "one required label missing",
+			filter: LabelFilter{Labels: []string{"preview", "team-a"}},
+			labels: []string{"preview"},
+			expect: false,
+		},
+		{
+			name:   "any label present",
+			filter: LabelFilter{AnyLabels: []string{"team-a", "team-b"}},
+			labels: []string{"team-b"},
+			expect: true,
+		},
+		{
+			name:   "no any label present",
+			filter: LabelFilter{AnyLabels: []string{"team-a", "team-b"}},
+			labels: []string{"team-c"},
+			expect: false,
+		},
+		{
+			name:   "excluded label present",
+			filter: LabelFilter{Labels: []string{"preview"}, NotLabels: []string{"no-preview"}},
+			labels: []string{"preview", "no-preview"},
+			expect: false,
+		},
+		{
+			name:   "excluded label absent",
+			filter: LabelFilter{NotLabels: []string{"no-preview"}},
+			labels: []string{},
+			expect: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expect, c.filter.Match(c.labels))
+		})
+	}
+}
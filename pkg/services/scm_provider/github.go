@@ -3,12 +3,34 @@ package scm_provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 
 	"github.com/google/go-github/v35/github"
 	"golang.org/x/oauth2"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+	"github.com/argoproj-labs/applicationset/pkg/services/github_app"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
+// maxCachedGithubRepositories is the maximum number of the repositories kept for the organizations whose repositories
+// are not modified.
+const maxCachedGithubRepositories = 100000
+
+// githubRepositories are the last repositories listed by the GitHub providers, by API URL, organization, branches and
+// clone protocol, so that the branches of the repositories are not listed again while the repositories of the
+// organization are not modified.
+var githubRepositories = utils.NewLRUCache(maxCachedGithubRepositories)
+
+// githubListedRepositories are the repositories of an organization, and the validators of the pages of the
+// repositories of the organization they were listed from.
+type githubListedRepositories struct {
+	validators string
+	repos      []Repository
+}
+
 type GithubProvider struct {
 	client       *github.Client
 	organization string
@@ -17,7 +39,9 @@ type GithubProvider struct {
 
 var _ SCMProviderService = &GithubProvider{}
 
-func NewGithubProvider(ctx context.Context, organization string, token string, url string, allBranches bool) (*GithubProvider, error) {
+// NewGithubProvider returns a GitHub provider authenticated with a token. The requests are sent with the httpClient, or
+// with the default client if nil.
+func NewGithubProvider(ctx context.Context, organization string, token string, url string, allBranches bool, httpClient *http.Client) (*GithubProvider, error) {
 	var ts oauth2.TokenSource
 	// Undocumented environment variable to set a default token, to be used in testing to dodge anonymous rate limits.
 	if token == "" {
@@ -28,13 +52,28 @@ func NewGithubProvider(ctx context.Context, organization string, token string, u
 			&oauth2.Token{AccessToken: token},
 		)
 	}
-	httpClient := oauth2.NewClient(ctx, ts)
+	return newGithubProvider(ctx, organization, ts, url, allBranches, httpClient)
+}
+
+// NewGithubAppProvider returns a GitHub provider authenticated with the installation tokens of a GitHub App.
+func NewGithubAppProvider(ctx context.Context, organization string, auth github_app.Authentication, url string, allBranches bool, httpClient *http.Client) (*GithubProvider, error) {
+	ts, err := github_app.NewTokenSource(auth, url, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return newGithubProvider(ctx, organization, ts, url, allBranches, httpClient)
+}
+
+func newGithubProvider(ctx context.Context, organization string, ts oauth2.TokenSource, url string, allBranches bool, httpClient *http.Client) (*GithubProvider, error) {
+	// The requests are conditional, so that the unmodified resources don't consume the rate limit
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, utils.NewConditionalClient(httpClient))
+	scmClient := metrics.NewSCMClient("github", oauth2.NewClient(ctx, ts))
 	var client *github.Client
 	if url == "" {
-		client = github.NewClient(httpClient)
+		client = github.NewClient(scmClient)
 	} else {
 		var err error
-		client, err = github.NewEnterpriseClient(url, url, httpClient)
+		client, err = github.NewEnterpriseClient(url, url, scmClient)
 		if err != nil {
 			return nil, err
 		}
@@ -42,50 +81,79 @@ func NewGithubProvider(ctx context.Context, organization string, token string, u
 	return &GithubProvider{client: client, organization: organization, allBranches: allBranches}, nil
 }
 
+// ListRepos lists the repositories of the organization, with their branches. While GitHub answers that the pages of the
+// repositories of the organization are not modified, the repositories are the last ones listed, without listing their
+// branches again: a push to a branch of a repository modifies the organization's page of the repository.
 func (g *GithubProvider) ListRepos(ctx context.Context, cloneProtocol string) ([]*Repository, error) {
 	opt := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
-	repos := []*Repository{}
+	githubRepos := []*github.Repository{}
+	var validators strings.Builder
+	notModified := true
 	for {
-		githubRepos, resp, err := g.client.Repositories.ListByOrg(ctx, g.organization, opt)
+		page, resp, err := g.client.Repositories.ListByOrg(ctx, g.organization, opt)
 		if err != nil {
 			return nil, fmt.Errorf("error listing repositories for %s: %v", g.organization, err)
 		}
-		for _, githubRepo := range githubRepos {
-			var url string
-			switch cloneProtocol {
-			// Default to SSH if unspecified (i.e. if "").
-			case "", "ssh":
-				url = githubRepo.GetSSHURL()
-			case "https":
-				url = githubRepo.GetCloneURL()
-			default:
-				return nil, fmt.Errorf("unknown clone protocol for GitHub %v", cloneProtocol)
-			}
-
-			branches, err := g.listBranches(ctx, githubRepo)
-			if err != nil {
-				return nil, fmt.Errorf("error listing branches for %s/%s: %v", githubRepo.Owner.GetLogin(), githubRepo.GetName(), err)
-			}
-
-			for _, branch := range branches {
-				repos = append(repos, &Repository{
-					Organization: githubRepo.Owner.GetLogin(),
-					Repository:   githubRepo.GetName(),
-					URL:          url,
-					Branch:       branch.GetName(),
-					SHA:          branch.GetCommit().GetSHA(),
-					Labels:       githubRepo.Topics,
-				})
-			}
-		}
+		githubRepos = append(githubRepos, page...)
+		validators.WriteString(utils.ResponseValidators(resp.Response) + "\n")
+		notModified = notModified && utils.NotModified(resp.Response)
 		if resp.NextPage == 0 {
 			break
 		}
 		opt.Page = resp.NextPage
 	}
-	return repos, nil
+
+	cacheKey := fmt.Sprintf("%s\n%s\n%t\n%s", g.client.BaseURL, g.organization, g.allBranches, cloneProtocol)
+	if notModified {
+		if value, ok := githubRepositories.Get(cacheKey); ok && value.(*githubListedRepositories).validators == validators.String() {
+			return copyRepositories(value.(*githubListedRepositories).repos), nil
+		}
+	}
+
+	repos := []Repository{}
+	for _, githubRepo := range githubRepos {
+		var url string
+		switch cloneProtocol {
+		// Default to SSH if unspecified (i.e. if "").
+		case "", "ssh":
+			url = githubRepo.GetSSHURL()
+		case "https":
+			url = githubRepo.GetCloneURL()
+		default:
+			return nil, fmt.Errorf("unknown clone protocol for GitHub %v", cloneProtocol)
+		}
+
+		branches, err := g.listBranches(ctx, githubRepo)
+		if err != nil {
+			return nil, fmt.Errorf("error listing branches for %s/%s: %v", githubRepo.Owner.GetLogin(), githubRepo.GetName(), err)
+		}
+
+		for _, branch := range branches {
+			repos = append(repos, Repository{
+				Organization: githubRepo.Owner.GetLogin(),
+				Repository:   githubRepo.GetName(),
+				URL:          url,
+				Branch:       branch.GetName(),
+				SHA:          branch.GetCommit().GetSHA(),
+				Labels:       githubRepo.Topics,
+			})
+		}
+	}
+	githubRepositories.Add(cacheKey, &githubListedRepositories{validators: validators.String(), repos: repos}, int64(len(repos)))
+	return copyRepositories(repos), nil
+}
+
+// copyRepositories returns copies of the repositories, which are shared by the reconciliations.
+func copyRepositories(repos []Repository) []*Repository {
+	res := make([]*Repository, 0, len(repos))
+	for _, repo := range repos {
+		repo := repo
+		repo.Labels = append([]string(nil), repo.Labels...)
+		res = append(res, &repo)
+	}
+	return res
 }
 
 func (g *GithubProvider) RepoHasPath(ctx context.Context, repo *Repository, path string) (bool, error) {
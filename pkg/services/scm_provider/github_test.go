@@ -2,6 +2,9 @@ package scm_provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -69,7 +72,7 @@ func TestGithubListRepos(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			provider, _ := NewGithubProvider(context.Background(), "argoproj-labs", "", "", c.allBranches)
+			provider, _ := NewGithubProvider(context.Background(), "argoproj-labs", "", "", c.allBranches, nil)
 			rawRepos, err := provider.ListRepos(context.Background(), c.proto)
 			if c.hasError {
 				assert.NotNil(t, err)
@@ -96,7 +99,7 @@ func TestGithubListRepos(t *testing.T) {
 }
 
 func TestGithubHasPath(t *testing.T) {
-	host, _ := NewGithubProvider(context.Background(), "argoproj-labs", "", "", false)
+	host, _ := NewGithubProvider(context.Background(), "argoproj-labs", "", "", false, nil)
 	repo := &Repository{
 		Organization: "argoproj-labs",
 		Repository:   "applicationset",
@@ -112,3 +115,52 @@ func TestGithubHasPath(t *testing.T) {
 	assert.Nil(t, err)
 	assert.False(t, ok)
 }
+
+func TestGithubListReposNotModified(t *testing.T) {
+	branchRequests := 0
+	pushedAt := "2021-09-01T00:00:00Z"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/orgs/argoproj/repos":
+			etag := `"` + pushedAt + `"`
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			_, _ = fmt.Fprintf(w, `[{"name":"argo-cd","owner":{"login":"argoproj"},"default_branch":"master","clone_url":"https://github.com/argoproj/argo-cd.git","pushed_at":%q}]`, pushedAt)
+		case "/api/v3/repos/argoproj/argo-cd/branches/master":
+			branchRequests++
+			_, _ = fmt.Fprintf(w, `{"name":"master","commit":{"sha":"%d"}}`, branchRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	provider, err := NewGithubProvider(context.Background(), "argoproj", "token", ts.URL, false, nil)
+	assert.NoError(t, err)
+	repos, err := provider.ListRepos(context.Background(), "https")
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.Equal(t, "1", repos[0].SHA)
+		assert.Equal(t, "https://github.com/argoproj/argo-cd.git", repos[0].URL)
+	}
+
+	// The branches aren't listed again while the repositories of the organization are not modified
+	repos, err = provider.ListRepos(context.Background(), "https")
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.Equal(t, "1", repos[0].SHA)
+	}
+	assert.Equal(t, 1, branchRequests)
+
+	pushedAt = "2021-09-02T00:00:00Z"
+	repos, err = provider.ListRepos(context.Background(), "https")
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.Equal(t, "2", repos[0].SHA)
+	}
+	assert.Equal(t, 2, branchRequests)
+}
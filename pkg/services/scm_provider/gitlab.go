@@ -3,9 +3,13 @@ package scm_provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	gitlab "github.com/xanzy/go-gitlab"
+
+	"github.com/argoproj-labs/applicationset/pkg/metrics"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
 )
 
 type GitlabProvider struct {
@@ -17,7 +21,7 @@ type GitlabProvider struct {
 
 var _ SCMProviderService = &GitlabProvider{}
 
-func NewGitlabProvider(ctx context.Context, organization string, token string, url string, allBranches, includeSubgroups bool) (*GitlabProvider, error) {
+func NewGitlabProvider(ctx context.Context, organization string, token string, url string, allBranches, includeSubgroups bool, httpClient *http.Client) (*GitlabProvider, error) {
 	// Undocumented environment variable to set a default token, to be used in testing to dodge anonymous rate limits.
 	if token == "" {
 		token = os.Getenv("GITLAB_TOKEN")
@@ -25,13 +29,13 @@ func NewGitlabProvider(ctx context.Context, organization string, token string, u
 	var client *gitlab.Client
 	if url == "" {
 		var err error
-		client, err = gitlab.NewClient(token)
+		client, err = gitlab.NewClient(token, gitlab.WithHTTPClient(metrics.NewSCMClient("gitlab", utils.NewConditionalClient(httpClient))))
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		var err error
-		client, err = gitlab.NewClient(token, gitlab.WithBaseURL(url))
+		client, err = gitlab.NewClient(token, gitlab.WithBaseURL(url), gitlab.WithHTTPClient(metrics.NewSCMClient("gitlab", utils.NewConditionalClient(httpClient))))
 		if err != nil {
 			return nil, err
 		}
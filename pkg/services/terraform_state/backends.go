@@ -0,0 +1,155 @@
+package terraform_state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// RemoteDefaultAddress is the address of Terraform Cloud.
+	RemoteDefaultAddress = "https://app.terraform.io"
+)
+
+// HTTPReader reads a Terraform state stored by the http backend.
+type HTTPReader struct {
+	client   *http.Client
+	address  string
+	username string
+	password string
+}
+
+var _ StateReader = (*HTTPReader)(nil)
+
+// NewHTTPReader returns a reader of the state at the address of the http backend, with optional basic credentials.
+func NewHTTPReader(address, username, password string) (StateReader, error) {
+	if address == "" {
+		return nil, fmt.Errorf("the address of the http backend is required")
+	}
+	return &HTTPReader{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		address:  address,
+		username: username,
+		password: password,
+	}, nil
+}
+
+func (r *HTTPReader) Read(ctx context.Context) (map[string]Output, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.username != "" || r.password != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	content, err := do(r.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading terraform state: %v", err)
+	}
+	return parseState(content)
+}
+
+// RemoteReader reads the current Terraform state of a workspace of Terraform Cloud or Terraform Enterprise.
+type RemoteReader struct {
+	client       *http.Client
+	address      string
+	organization string
+	workspace    string
+	token        string
+}
+
+var _ StateReader = (*RemoteReader)(nil)
+
+// remoteWorkspace is the subset of the workspace response used by the generator.
+type remoteWorkspace struct {
+	Data struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// remoteStateVersion is the subset of the state version response used by the generator.
+type remoteStateVersion struct {
+	Data struct {
+		Attributes struct {
+			HostedStateDownloadURL string `json:"hosted-state-download-url"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// NewRemoteReader returns a reader of the current state of the workspace of the organization. If address is empty,
+// Terraform Cloud is used.
+func NewRemoteReader(address, organization, workspace, token string) (StateReader, error) {
+	if organization == "" || workspace == "" {
+		return nil, fmt.Errorf("the organization and workspace of the remote backend are required")
+	}
+	if address == "" {
+		address = RemoteDefaultAddress
+	}
+	return &RemoteReader{
+		client:       &http.Client{Timeout: 30 * time.Second},
+		address:      strings.TrimSuffix(address, "/"),
+		organization: organization,
+		workspace:    workspace,
+		token:        token,
+	}, nil
+}
+
+func (r *RemoteReader) Read(ctx context.Context) (map[string]Output, error) {
+	workspace := &remoteWorkspace{}
+	err := r.getJSON(ctx, fmt.Sprintf("%s/api/v2/organizations/%s/workspaces/%s", r.address, url.PathEscape(r.organization), url.PathEscape(r.workspace)), workspace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching workspace %s/%s: %v", r.organization, r.workspace, err)
+	}
+
+	stateVersion := &remoteStateVersion{}
+	err = r.getJSON(ctx, fmt.Sprintf("%s/api/v2/workspaces/%s/current-state-version", r.address, url.PathEscape(workspace.Data.ID)), stateVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching current state version of workspace %s/%s: %v", r.organization, r.workspace, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, stateVersion.Data.Attributes.HostedStateDownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	content, err := do(r.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading state of workspace %s/%s: %v", r.organization, r.workspace, err)
+	}
+	return parseState(content)
+}
+
+func (r *RemoteReader) getJSON(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+	content, err := do(r.client, req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, out)
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return content, nil
+}
@@ -0,0 +1,140 @@
+package terraform_state
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testState = `{
+  "version": 4,
+  "terraform_version": "1.0.11",
+  "outputs": {
+    "vpc_id": {"value": "vpc-0123", "type": "string"},
+    "db_password": {"value": "s3cr3t", "type": "string", "sensitive": true}
+  },
+  "resources": []
+}`
+
+// fakeBucket is a bucket containing a single object.
+type fakeBucket struct {
+	key     string
+	content string
+}
+
+func (b *fakeBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	return []string{b.key}, nil
+}
+
+func (b *fakeBucket) Get(ctx context.Context, key string) ([]byte, error) {
+	if key != b.key {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return []byte(b.content), nil
+}
+
+func TestReaders(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/http/state":
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, testState)
+			return
+		case "/http/legacy":
+			_, _ = fmt.Fprint(w, `{"version": 3, "modules": []}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/organizations/example/workspaces/network":
+			_, _ = fmt.Fprint(w, `{"data":{"id":"ws-123","type":"workspaces"}}`)
+		case "/api/v2/workspaces/ws-123/current-state-version":
+			_, _ = fmt.Fprintf(w, `{"data":{"id":"sv-456","attributes":{"hosted-state-download-url":"%s/archivist/sv-456"}}}`, ts.URL)
+		case "/archivist/sv-456":
+			_, _ = fmt.Fprint(w, testState)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	expected := map[string]Output{
+		"vpc_id":      {Value: "vpc-0123"},
+		"db_password": {Value: "s3cr3t", Sensitive: true},
+	}
+
+	cases := []struct {
+		name          string
+		reader        func() (StateReader, error)
+		expectedError string
+	}{
+		{
+			name: "http backend",
+			reader: func() (StateReader, error) {
+				return NewHTTPReader(ts.URL+"/http/state", "user", "pass")
+			},
+		},
+		{
+			name: "http backend with wrong credentials",
+			reader: func() (StateReader, error) {
+				return NewHTTPReader(ts.URL+"/http/state", "user", "wrong")
+			},
+			expectedError: "error reading terraform state: unexpected status 401 Unauthorized",
+		},
+		{
+			name: "unsupported state version",
+			reader: func() (StateReader, error) {
+				return NewHTTPReader(ts.URL+"/http/legacy", "", "")
+			},
+			expectedError: "unsupported terraform state version 3: must be 4",
+		},
+		{
+			name: "remote backend",
+			reader: func() (StateReader, error) {
+				return NewRemoteReader(ts.URL, "example", "network", "my-token")
+			},
+		},
+		{
+			name: "remote backend with unknown workspace",
+			reader: func() (StateReader, error) {
+				return NewRemoteReader(ts.URL, "example", "missing", "my-token")
+			},
+			expectedError: "error fetching workspace example/missing: unexpected status 404 Not Found",
+		},
+		{
+			name: "bucket",
+			reader: func() (StateReader, error) {
+				return NewBucketReader(&fakeBucket{key: "network/terraform.tfstate", content: testState}, "network/terraform.tfstate"), nil
+			},
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			reader, err := cc.reader()
+			assert.NoError(t, err)
+
+			got, err := reader.Read(ctx)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, expected, got)
+		})
+	}
+}
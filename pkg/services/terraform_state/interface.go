@@ -0,0 +1,16 @@
+package terraform_state
+
+import "context"
+
+// Output is an output of a Terraform state.
+type Output struct {
+	// Value is the JSON decoded value of the output.
+	Value interface{}
+	// Sensitive is true if the output is marked as sensitive in the Terraform configuration.
+	Sensitive bool
+}
+
+type StateReader interface {
+	// Read gets the outputs of the state.
+	Read(ctx context.Context) (map[string]Output, error)
+}
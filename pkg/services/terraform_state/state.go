@@ -0,0 +1,57 @@
+package terraform_state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/argoproj-labs/applicationset/pkg/services/bucket"
+)
+
+// state is the subset of a Terraform state used by the generator.
+type state struct {
+	Version int `json:"version"`
+	Outputs map[string]struct {
+		Value     interface{} `json:"value"`
+		Sensitive bool        `json:"sensitive"`
+	} `json:"outputs"`
+}
+
+// parseState returns the outputs of a Terraform state, in the format of the version 4 of the state (Terraform 0.12 and
+// later).
+func parseState(content []byte) (map[string]Output, error) {
+	s := &state{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("error parsing terraform state: %v", err)
+	}
+	if s.Version != 4 {
+		return nil, fmt.Errorf("unsupported terraform state version %d: must be 4", s.Version)
+	}
+
+	outputs := make(map[string]Output, len(s.Outputs))
+	for name, output := range s.Outputs {
+		outputs[name] = Output{Value: output.Value, Sensitive: output.Sensitive}
+	}
+	return outputs, nil
+}
+
+// BucketReader reads a Terraform state stored in an object storage bucket by the s3 or gcs backends.
+type BucketReader struct {
+	bucket bucket.Bucket
+	key    string
+}
+
+var _ StateReader = (*BucketReader)(nil)
+
+// NewBucketReader returns a reader of the state at the key of the bucket.
+func NewBucketReader(b bucket.Bucket, key string) StateReader {
+	return &BucketReader{bucket: b, key: key}
+}
+
+func (r *BucketReader) Read(ctx context.Context) (map[string]Output, error) {
+	content, err := r.bucket.Get(ctx, r.key)
+	if err != nil {
+		return nil, err
+	}
+	return parseState(content)
+}
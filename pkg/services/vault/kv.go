@@ -0,0 +1,204 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultKVMount is the mount path of the KV secrets engine, if not specified.
+	DefaultKVMount = "secret"
+	// DefaultKubernetesAuthMount is the mount path of the Kubernetes auth method, if not specified.
+	DefaultKubernetesAuthMount = "kubernetes"
+)
+
+// Auth defines how to authenticate against Vault: either with a token, or with the Kubernetes auth method, logging in
+// as the given role with a service account JWT.
+type Auth struct {
+	Token               string
+	KubernetesRole      string
+	KubernetesAuthMount string
+	JWT                 string
+}
+
+// KVService reads secrets from a KV secrets engine.
+type KVService interface {
+	// List returns the names of the secrets directly under the path. Sub-paths, ending with a "/", are skipped.
+	List(ctx context.Context, path string) ([]string, error)
+	// Read returns the data of the secret at the path.
+	Read(ctx context.Context, path string) (map[string]interface{}, error)
+}
+
+type KVClient struct {
+	client    *http.Client
+	address   string
+	namespace string
+	token     string
+	mount     string
+	kvVersion int
+}
+
+var _ KVService = (*KVClient)(nil)
+
+// vaultResponse is the subset of the Vault API responses used by the generator.
+type vaultResponse struct {
+	Data json.RawMessage `json:"data"`
+	Auth struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewKVClient returns a client of the KV secrets engine mounted at mount, of the given version (1 or 2). namespace is
+// the optional Vault Enterprise namespace. If the auth does not contain a token, the client logs in with the Kubernetes
+// auth method.
+func NewKVClient(ctx context.Context, address, namespace, mount string, kvVersion int, auth Auth) (KVService, error) {
+	if address == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if mount == "" {
+		mount = DefaultKVMount
+	}
+	if kvVersion == 0 {
+		kvVersion = 2
+	}
+	if kvVersion != 1 && kvVersion != 2 {
+		return nil, fmt.Errorf("invalid KV secrets engine version %d: must be 1 or 2", kvVersion)
+	}
+	c := &KVClient{
+		client:    &http.Client{},
+		address:   strings.TrimSuffix(address, "/"),
+		namespace: namespace,
+		token:     auth.Token,
+		mount:     strings.Trim(mount, "/"),
+		kvVersion: kvVersion,
+	}
+	if c.token == "" {
+		if err := c.kubernetesLogin(ctx, auth); err != nil {
+			return nil, fmt.Errorf("error logging in to vault with the kubernetes auth method: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *KVClient) List(ctx context.Context, path string) ([]string, error) {
+	apiPath := fmt.Sprintf("%s/%s", c.mount, strings.Trim(path, "/"))
+	if c.kvVersion == 2 {
+		apiPath = fmt.Sprintf("%s/metadata/%s", c.mount, strings.Trim(path, "/"))
+	}
+	data := &struct {
+		Keys []string `json:"keys"`
+	}{}
+	if err := c.do(ctx, "LIST", apiPath, nil, data); err != nil {
+		return nil, fmt.Errorf("error listing secrets in %s: %v", path, err)
+	}
+	names := []string{}
+	for _, key := range data.Keys {
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		names = append(names, key)
+	}
+	return names, nil
+}
+
+func (c *KVClient) Read(ctx context.Context, path string) (map[string]interface{}, error) {
+	if c.kvVersion == 1 {
+		data := map[string]interface{}{}
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", c.mount, strings.Trim(path, "/")), nil, &data); err != nil {
+			return nil, fmt.Errorf("error reading secret %s: %v", path, err)
+		}
+		return data, nil
+	}
+	data := &struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/data/%s", c.mount, strings.Trim(path, "/")), nil, data); err != nil {
+		return nil, fmt.Errorf("error reading secret %s: %v", path, err)
+	}
+	return data.Data, nil
+}
+
+// kubernetesLogin logs in with the Kubernetes auth method, and keeps the returned token.
+func (c *KVClient) kubernetesLogin(ctx context.Context, auth Auth) error {
+	if auth.KubernetesRole == "" || auth.JWT == "" {
+		return fmt.Errorf("a role and a service account token are required")
+	}
+	authMount := auth.KubernetesAuthMount
+	if authMount == "" {
+		authMount = DefaultKubernetesAuthMount
+	}
+	body := map[string]string{
+		"role": auth.KubernetesRole,
+		"jwt":  auth.JWT,
+	}
+	resp := &vaultResponse{}
+	if err := c.request(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", strings.Trim(authMount, "/")), body, resp); err != nil {
+		return err
+	}
+	if resp.Auth.ClientToken == "" {
+		return fmt.Errorf("no client token in the login response")
+	}
+	c.token = resp.Auth.ClientToken
+	return nil
+}
+
+// do sends a request to the Vault API, decoding the data of the response into out.
+func (c *KVClient) do(ctx context.Context, method, apiPath string, body interface{}, out interface{}) error {
+	resp := &vaultResponse{}
+	if err := c.request(ctx, method, apiPath, body, resp); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("error decoding response data: %v", err)
+	}
+	return nil
+}
+
+func (c *KVClient) request(ctx context.Context, method, apiPath string, body interface{}, out *vaultResponse) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bodyJSON)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", c.address, apiPath), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.Join(out.Errors, ", "))
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVaultServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/auth/kubernetes/login" {
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["role"] != "applicationset" || body["jwt"] != "my-jwt" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = fmt.Fprint(w, `{"errors":["permission denied"]}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"auth":{"client_token":"login-token"}}`)
+			return
+		}
+
+		token := r.Header.Get("X-Vault-Token")
+		if token != "my-token" && token != "login-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		switch {
+		case r.Method == "LIST" && r.URL.Path == "/v1/secret/metadata/environments":
+			_, _ = fmt.Fprint(w, `{"data":{"keys":["production","staging","archived/"]}}`)
+		case r.Method == "LIST" && r.URL.Path == "/v1/kv/environments":
+			_, _ = fmt.Fprint(w, `{"data":{"keys":["production"]}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/secret/data/environments/production":
+			_, _ = fmt.Fprint(w, `{"data":{"data":{"cluster":"prod-1","replicas":3},"metadata":{"version":2}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/kv/environments/production":
+			_, _ = fmt.Fprint(w, `{"data":{"cluster":"prod-1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+}
+
+func TestKVClient(t *testing.T) {
+	ts := newVaultServer(t)
+	defer ts.Close()
+	ctx := context.Background()
+
+	t.Run("kv v2 with token", func(t *testing.T) {
+		kv, err := NewKVClient(ctx, ts.URL, "", "", 0, Auth{Token: "my-token"})
+		assert.NoError(t, err)
+
+		names, err := kv.List(ctx, "environments")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"production", "staging"}, names)
+
+		data, err := kv.Read(ctx, "environments/production")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"cluster": "prod-1", "replicas": float64(3)}, data)
+
+		_, err = kv.Read(ctx, "environments/missing")
+		assert.EqualError(t, err, "error reading secret environments/missing: unexpected status 404 Not Found")
+	})
+
+	t.Run("kv v1 with kubernetes auth", func(t *testing.T) {
+		kv, err := NewKVClient(ctx, ts.URL, "", "kv", 1, Auth{KubernetesRole: "applicationset", JWT: "my-jwt"})
+		assert.NoError(t, err)
+
+		names, err := kv.List(ctx, "/environments/")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"production"}, names)
+
+		data, err := kv.Read(ctx, "environments/production")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"cluster": "prod-1"}, data)
+	})
+
+	t.Run("kubernetes auth with unknown role", func(t *testing.T) {
+		_, err := NewKVClient(ctx, ts.URL, "", "", 2, Auth{KubernetesRole: "other", JWT: "my-jwt"})
+		assert.EqualError(t, err, "error logging in to vault with the kubernetes auth method: unexpected status 403 Forbidden: permission denied")
+	})
+
+	t.Run("invalid kv version", func(t *testing.T) {
+		_, err := NewKVClient(ctx, ts.URL, "", "", 3, Auth{Token: "my-token"})
+		assert.EqualError(t, err, "invalid KV secrets engine version 3: must be 1 or 2")
+	})
+}
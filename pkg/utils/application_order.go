@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/valyala/fasttemplate"
+)
+
+// RenderOrderKey renders the order key of an ApplicationSet with the params, as a Go template if useGoTemplate is true,
+// or with the {{param}} syntax otherwise, and returns it without leading and trailing spaces.
+func RenderOrderKey(key string, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (string, error) {
+	if useGoTemplate {
+		base, err := newGoTemplate(nil, goTemplateOptions)
+		if err != nil {
+			return "", err
+		}
+		rendered, err := renderGoTemplateString(base, key, goTemplateData(params))
+		if err != nil {
+			return "", fmt.Errorf("failed to render order key: %v", err)
+		}
+		return strings.TrimSpace(rendered), nil
+	}
+
+	rendered := fasttemplate.New(key, "{{", "}}").ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
+		value, ok := params[strings.TrimSpace(tag)]
+		if !ok {
+			return w.Write([]byte(fmt.Sprintf("{{%s}}", tag)))
+		}
+		return w.Write([]byte(paramString(value)))
+	})
+	return strings.TrimSpace(rendered), nil
+}
+
+// SortApplicationsByOrder returns the Applications sorted by their order keys, keys[i] being the key of
+// applications[i]. If values are given, the keys are sorted by their index in values, and the keys which are not part
+// of values come last. The other keys are sorted numerically if they are integers, and lexically otherwise.
+// Applications with the same key keep their order.
+func SortApplicationsByOrder(applications []argov1alpha1.Application, keys []string, values []string) []argov1alpha1.Application {
+	ranks := make(map[string]int, len(values))
+	for i, value := range values {
+		if _, ok := ranks[value]; !ok {
+			ranks[value] = i
+		}
+	}
+	rank := func(key string) int {
+		if r, ok := ranks[key]; ok {
+			return r
+		}
+		return len(values)
+	}
+
+	indexes := make([]int, len(applications))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := keys[indexes[i]], keys[indexes[j]]
+		if rankA, rankB := rank(a), rank(b); rankA != rankB {
+			return rankA < rankB
+		}
+		return lessOrderKey(a, b)
+	})
+
+	res := make([]argov1alpha1.Application, len(applications))
+	for i, index := range indexes {
+		res[i] = applications[index]
+	}
+	return res
+}
+
+// lessOrderKey compares the keys numerically if they are both integers, and lexically otherwise.
+func lessOrderKey(a string, b string) bool {
+	intA, errA := strconv.ParseInt(a, 10, 64)
+	intB, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		return intA < intB
+	}
+	return a < b
+}
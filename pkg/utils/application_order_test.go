@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderOrderKey(t *testing.T) {
+	params := map[string]interface{}{
+		"env":        "staging",
+		"waveNumber": "10",
+		"values.env": "prod",
+	}
+
+	cases := []struct {
+		name          string
+		key           string
+		useGoTemplate bool
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "param",
+			key:      "{{waveNumber}}",
+			expected: "10",
+		},
+		{
+			name:     "missing param",
+			key:      "{{ wave }}",
+			expected: "{{ wave }}",
+		},
+		{
+			name:          "Go template",
+			key:           " {{ .values.env }} ",
+			useGoTemplate: true,
+			expected:      "prod",
+		},
+		{
+			name:          "invalid Go template",
+			key:           "{{ .env ",
+			useGoTemplate: true,
+			expectedError: "failed to render order key: failed to parse template {{ .env : template: :1: unclosed action",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := RenderOrderKey(cc.key, params, cc.useGoTemplate, nil)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
+
+func TestSortApplicationsByOrder(t *testing.T) {
+	newApps := func(names ...string) []argov1alpha1.Application {
+		var apps []argov1alpha1.Application
+		for _, name := range names {
+			apps = append(apps, argov1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		return apps
+	}
+	names := func(apps []argov1alpha1.Application) []string {
+		var res []string
+		for _, app := range apps {
+			res = append(res, app.Name)
+		}
+		return res
+	}
+
+	cases := []struct {
+		name     string
+		keys     []string
+		values   []string
+		expected []string
+	}{
+		{
+			name:     "numeric keys",
+			keys:     []string{"10", "2", "1", "2"},
+			expected: []string{"c", "b", "d", "a"},
+		},
+		{
+			name:     "lexical keys",
+			keys:     []string{"prod", "dev", "staging", "dev"},
+			expected: []string{"b", "d", "a", "c"},
+		},
+		{
+			name:     "keys in the order of the values",
+			keys:     []string{"prod", "dev", "staging", "dev"},
+			values:   []string{"dev", "staging", "prod"},
+			expected: []string{"b", "d", "c", "a"},
+		},
+		{
+			name:     "keys which are not part of the values come last",
+			keys:     []string{"qa", "prod", "dev", "preview"},
+			values:   []string{"dev", "prod"},
+			expected: []string{"c", "b", "d", "a"},
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			apps := newApps("a", "b", "c", "d")
+			got := SortApplicationsByOrder(apps, cc.keys, cc.values)
+			assert.Equal(t, cc.expected, names(got))
+			// The Applications are not modified
+			assert.Equal(t, []string{"a", "b", "c", "d"}, names(apps))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// notModifiedHeader is set on the responses replayed from the cache, when the server answered 304 Not Modified to
+	// the conditional request.
+	notModifiedHeader = "X-From-Cache"
+	// maxConditionalResponsesSize is the maximum size of the bodies of the responses kept for the conditional
+	// requests, in bytes.
+	maxConditionalResponsesSize = 64 << 20
+	// maxConditionalResponseSize is the maximum size of the body of a response kept for the conditional requests, in
+	// bytes.
+	maxConditionalResponseSize = 4 << 20
+)
+
+// conditionalResponses are the responses kept for the conditional requests of the clients of the generators.
+var conditionalResponses = NewLRUCache(maxConditionalResponsesSize)
+
+// NewConditionalClient returns a copy of the client sending conditional GET requests, or of the default client if nil.
+// The responses are kept in memory, up to 64 MiB, and are shared by the clients.
+func NewConditionalClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	conditional := *client
+	conditional.Transport = newConditionalTransport(client.Transport, conditionalResponses)
+	return &conditional
+}
+
+// conditionalResponse is a response whose body is kept, with its ETag or Last-Modified validators.
+type conditionalResponse struct {
+	header http.Header
+	body   []byte
+}
+
+// conditionalTransport sends the GET requests with the If-None-Match and If-Modified-Since headers of the last
+// response to the same request, if it had an ETag or a Last-Modified header. When the server answers 304 Not Modified,
+// the last response is replayed as a 200 OK response, and NotModified returns true for it, so the callers transparently
+// read the last body without the server sending it again. Most APIs, e.g. GitHub, don't count the 304 responses in
+// their rate limits.
+type conditionalTransport struct {
+	base  http.RoundTripper
+	cache *LRUCache
+}
+
+// newConditionalTransport returns a transport sending conditional requests with the base transport, or with the
+// default transport if base is nil, keeping the responses in the cache.
+func newConditionalTransport(base http.RoundTripper, cache *LRUCache) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &conditionalTransport{base: base, cache: cache}
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := conditionalRequestKey(req)
+	var cached *conditionalResponse
+	if value, ok := t.cache.Get(key); ok {
+		cached = value.(*conditionalResponse)
+		// The request must not be modified by the transport, so the headers are set on a copy
+		req = req.Clone(req.Context())
+		if etag := cached.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		// The headers of the 304 response, e.g. the rate limits, update the headers of the last response
+		header := cached.header.Clone()
+		for name, values := range resp.Header {
+			header[name] = values
+		}
+		header.Set(notModifiedHeader, "1")
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       resp.Request,
+			TLS:           resp.TLS,
+		}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") ||
+		strings.Contains(resp.Header.Get("Cache-Control"), "no-store") || resp.ContentLength > maxConditionalResponseSize {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxConditionalResponseSize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxConditionalResponseSize {
+		// The body is too large to be kept, the rest of it is read by the caller
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	t.cache.Add(key, &conditionalResponse{header: resp.Header.Clone(), body: body}, int64(len(body)))
+	return resp, nil
+}
+
+// conditionalRequestKey returns the key of the response to the request: the hash of its URL and headers, so that the
+// responses to the requests with different credentials or content types are kept apart, without keeping the
+// credentials.
+func conditionalRequestKey(req *http.Request) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, req.URL.String())
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = io.WriteString(h, "\n"+name+": "+strings.Join(req.Header[name], ", "))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NotModified returns true if the response was replayed from the last response to the same request, the server having
+// answered 304 Not Modified to the conditional request.
+func NotModified(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(notModifiedHeader) != ""
+}
+
+// ResponseValidators returns the ETag and Last-Modified validators of the response, which identify its content.
+func ResponseValidators(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get("ETag") + "\n" + resp.Header.Get("Last-Modified")
+}
+
+// LRUCache is a cache of a bounded size, which discards its least recently used values first. It is safe for
+// concurrent use.
+type LRUCache struct {
+	lock     sync.Mutex
+	capacity int64
+	size     int64
+	// entries are the elements of lru, by key
+	entries map[string]*list.Element
+	// lru is the list of the entries, from the most recently used to the least recently used
+	lru *list.List
+}
+
+type lruCacheEntry struct {
+	key   string
+	value interface{}
+	size  int64
+}
+
+// NewLRUCache returns a cache whose values' sizes sum to at most capacity.
+func NewLRUCache(capacity int64) *LRUCache {
+	return &LRUCache{capacity: capacity, entries: map[string]*list.Element{}, lru: list.New()}
+}
+
+// Get returns the value of the key, if any.
+func (c *LRUCache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*lruCacheEntry).value, true
+}
+
+// Add sets the value of the key, of the given size, discarding the least recently used values until the cache fits
+// its capacity. A value larger than the capacity is not kept.
+func (c *LRUCache) Add(key string, value interface{}, size int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	if size > c.capacity {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&lruCacheEntry{key: key, value: value, size: size})
+	c.size += size
+	for c.size > c.capacity {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*lruCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
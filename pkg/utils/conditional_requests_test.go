@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalClient(t *testing.T) {
+	body := `[{"cluster":"staging"}]`
+	etag := `"v1"`
+	requests := 0
+	notModified := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: newConditionalTransport(nil, NewLRUCache(1024))}
+	get := func(token string) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(data)
+	}
+
+	resp, data := get("token")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, body, data)
+	assert.False(t, NotModified(resp))
+
+	// The response is replayed when the server answers 304
+	resp, data = get("token")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, body, data)
+	assert.True(t, NotModified(resp))
+	assert.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, ResponseValidators(resp), "\"v1\"\n")
+	assert.Equal(t, 1, notModified)
+
+	// The responses to other credentials are kept apart
+	resp, _ = get("other")
+	assert.False(t, NotModified(resp))
+	assert.Equal(t, 1, notModified)
+
+	// The response is sent again once it is modified
+	body = `[{"cluster":"production"}]`
+	etag = `"v2"`
+	resp, data = get("token")
+	assert.False(t, NotModified(resp))
+	assert.Equal(t, body, data)
+	assert.Equal(t, 4, requests)
+}
+
+func TestConditionalClientWithoutValidators(t *testing.T) {
+	conditional := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			conditional++
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	client := NewConditionalClient(nil)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.False(t, NotModified(resp))
+	}
+	assert.Equal(t, 0, conditional)
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(3)
+	cache.Add("a", "a", 1)
+	cache.Add("b", "b", 1)
+	cache.Add("c", "c", 1)
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+
+	// The least recently used value is discarded
+	cache.Add("d", "d", 1)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+
+	// A value larger than the capacity is not kept
+	cache.Add("e", "e", 4)
+	_, ok = cache.Get("e")
+	assert.False(t, ok)
+
+	cache.Add("a", "a2", 3)
+	value, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "a2", value)
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+}
@@ -30,6 +30,28 @@ import (
 // It returns the executed operation and an error.
 func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f controllerutil.MutateFn) (controllerutil.OperationResult, error) {
 
+	operation, err := ComputeOperation(ctx, c, obj, f)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	switch operation {
+	case controllerutil.OperationResultCreated:
+		if err := c.Create(ctx, obj); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	case controllerutil.OperationResultUpdated:
+		if err := c.Update(ctx, obj); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	}
+	return operation, nil
+}
+
+// ComputeOperation returns the operation CreateOrUpdate would execute, without executing it: the object is fetched
+// from the cluster, if it exists, and mutated by the callback MutateFn, but it is neither created nor updated.
+func ComputeOperation(ctx context.Context, c client.Reader, obj client.Object, f controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+
 	key := client.ObjectKeyFromObject(obj)
 	if err := c.Get(ctx, key, obj); err != nil {
 		if !errors.IsNotFound(err) {
@@ -38,9 +60,6 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f c
 		if err := mutate(f, key, obj); err != nil {
 			return controllerutil.OperationResultNone, err
 		}
-		if err := c.Create(ctx, obj); err != nil {
-			return controllerutil.OperationResultNone, err
-		}
 		return controllerutil.OperationResultCreated, nil
 	}
 
@@ -49,38 +68,9 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f c
 		return controllerutil.OperationResultNone, err
 	}
 
-	equality := conversion.EqualitiesOrDie(
-		func(a, b resource.Quantity) bool {
-			// Ignore formatting, only care that numeric value stayed the same.
-			// TODO: if we decide it's important, it should be safe to start comparing the format.
-			//
-			// Uninitialized quantities are equivalent to 0 quantities.
-			return a.Cmp(b) == 0
-		},
-		func(a, b metav1.MicroTime) bool {
-			return a.UTC() == b.UTC()
-		},
-		func(a, b metav1.Time) bool {
-			return a.UTC() == b.UTC()
-		},
-		func(a, b labels.Selector) bool {
-			return a.String() == b.String()
-		},
-		func(a, b fields.Selector) bool {
-			return a.String() == b.String()
-		},
-		func(a, b argov1alpha1.ApplicationDestination) bool {
-			return a.Namespace == b.Namespace && a.Name == b.Name && a.Server == b.Server
-		},
-	)
-
-	if equality.DeepEqual(existing, obj) {
+	if SemanticEqual(existing, obj) {
 		return controllerutil.OperationResultNone, nil
 	}
-
-	if err := c.Update(ctx, obj); err != nil {
-		return controllerutil.OperationResultNone, err
-	}
 	return controllerutil.OperationResultUpdated, nil
 }
 
@@ -94,3 +84,35 @@ func mutate(f controllerutil.MutateFn, key client.ObjectKey, obj client.Object)
 	}
 	return nil
 }
+
+// equality compares objects like equality.Semantic, and also compares argov1alpha1.ApplicationDestination, which has
+// a private variable.
+var equality = conversion.EqualitiesOrDie(
+	func(a, b resource.Quantity) bool {
+		// Ignore formatting, only care that numeric value stayed the same.
+		// TODO: if we decide it's important, it should be safe to start comparing the format.
+		//
+		// Uninitialized quantities are equivalent to 0 quantities.
+		return a.Cmp(b) == 0
+	},
+	func(a, b metav1.MicroTime) bool {
+		return a.UTC() == b.UTC()
+	},
+	func(a, b metav1.Time) bool {
+		return a.UTC() == b.UTC()
+	},
+	func(a, b labels.Selector) bool {
+		return a.String() == b.String()
+	},
+	func(a, b fields.Selector) bool {
+		return a.String() == b.String()
+	},
+	func(a, b argov1alpha1.ApplicationDestination) bool {
+		return a.Namespace == b.Namespace && a.Name == b.Name && a.Server == b.Server
+	},
+)
+
+// SemanticEqual returns true if the objects are semantically equal, e.g. the quantities with the same numeric value.
+func SemanticEqual(a interface{}, b interface{}) bool {
+	return equality.DeepEqual(a, b)
+}
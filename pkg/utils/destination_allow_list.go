@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// DestinationAllowList restricts the destinations of the generated Applications. An empty allow-list allows all the
+// destinations.
+type DestinationAllowList []AllowedDestination
+
+// AllowedDestination is a destination of the allow-list, whose patterns may contain '*' wildcards, which match any
+// sequence of characters.
+type AllowedDestination struct {
+	// Namespace is the pattern of the namespaces
+	Namespace string
+	// Server is the pattern of the server URLs, or of the names, of the clusters
+	Server string
+
+	namespaceRegexp *regexp.Regexp
+	serverRegexp    *regexp.Regexp
+}
+
+// ParseDestinationAllowList parses a comma-separated list of <namespace>@<server> destinations, e.g.
+// 'team-*@https://kubernetes.default.svc,*@in-cluster'.
+func ParseDestinationAllowList(value string) (DestinationAllowList, error) {
+	var allowList DestinationAllowList
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "@", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid destination '%s', expected <namespace>@<server>", entry)
+		}
+		allowList = append(allowList, AllowedDestination{
+			Namespace:       parts[0],
+			Server:          parts[1],
+			namespaceRegexp: globRegexp(parts[0]),
+			serverRegexp:    globRegexp(parts[1]),
+		})
+	}
+	return allowList, nil
+}
+
+// globRegexp returns the regexp matching the whole strings matched by a pattern with '*' wildcards.
+func globRegexp(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
+
+// IsAllowed returns true if the allow-list is empty, or if one of its destinations matches the namespace, and the
+// server URL or the name of the cluster, of the destination.
+func (l DestinationAllowList) IsAllowed(destination argov1alpha1.ApplicationDestination) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, allowed := range l {
+		if !allowed.namespaceRegexp.MatchString(destination.Namespace) {
+			continue
+		}
+		if (destination.Server != "" && allowed.serverRegexp.MatchString(destination.Server)) ||
+			(destination.Name != "" && allowed.serverRegexp.MatchString(destination.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the allow-list in the format parsed by ParseDestinationAllowList.
+func (l DestinationAllowList) String() string {
+	entries := make([]string, len(l))
+	for i, allowed := range l {
+		entries[i] = allowed.Namespace + "@" + allowed.Server
+	}
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDestinationAllowList(t *testing.T) {
+	allowList, err := ParseDestinationAllowList(" team-*@https://kubernetes.default.svc, *@in-cluster ,")
+	assert.NoError(t, err)
+	assert.Equal(t, "team-*@https://kubernetes.default.svc,*@in-cluster", allowList.String())
+
+	allowList, err = ParseDestinationAllowList("")
+	assert.NoError(t, err)
+	assert.Empty(t, allowList)
+
+	_, err = ParseDestinationAllowList("https://kubernetes.default.svc")
+	assert.EqualError(t, err, "invalid destination 'https://kubernetes.default.svc', expected <namespace>@<server>")
+
+	_, err = ParseDestinationAllowList("team@")
+	assert.EqualError(t, err, "invalid destination 'team@', expected <namespace>@<server>")
+}
+
+func TestDestinationAllowListIsAllowed(t *testing.T) {
+	allowList, err := ParseDestinationAllowList("team-*@https://kubernetes.default.svc,*@*.example.com,monitoring@in-cluster")
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		name        string
+		destination argov1alpha1.ApplicationDestination
+		allowed     bool
+	}{
+		{
+			name:        "namespace pattern",
+			destination: argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "team-a"},
+			allowed:     true,
+		},
+		{
+			name:        "namespace outside of the allow-list",
+			destination: argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "kube-system"},
+		},
+		{
+			name:        "server pattern",
+			destination: argov1alpha1.ApplicationDestination{Server: "https://prod.example.com", Namespace: "kube-system"},
+			allowed:     true,
+		},
+		{
+			name:        "server outside of the allow-list",
+			destination: argov1alpha1.ApplicationDestination{Server: "https://example.com.evil", Namespace: "team-a"},
+		},
+		{
+			name:        "cluster name",
+			destination: argov1alpha1.ApplicationDestination{Name: "in-cluster", Namespace: "monitoring"},
+			allowed:     true,
+		},
+		{
+			name:        "cluster name outside of the allow-list",
+			destination: argov1alpha1.ApplicationDestination{Name: "in-cluster", Namespace: "team-a"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.allowed, allowList.IsAllowed(c.destination))
+		})
+	}
+
+	assert.True(t, DestinationAllowList(nil).IsAllowed(argov1alpha1.ApplicationDestination{Server: "https://any", Namespace: "any"}))
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// GeneratorJSONNames returns the names of the generators as set in the ApplicationSets, e.g. scmProvider, by their Go
+// names, e.g. SCMProvider.
+func GeneratorJSONNames() map[string]string {
+	res := map[string]string{}
+	t := reflect.TypeOf(argoprojiov1alpha1.ApplicationSetGenerator{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "Schedule" || f.Type.Kind() != reflect.Ptr {
+			continue
+		}
+		res[f.Name] = strings.Split(f.Tag.Get("json"), ",")[0]
+	}
+	return res
+}
+
+// ParseDisabledGenerators returns the Go names of the disabled generators, from the comma-separated lists of the
+// names of the enabled and the disabled generators, e.g. list,clusters,git. All the generators are enabled by an empty
+// list, and the generators of both lists are disabled.
+func ParseDisabledGenerators(enabled, disabled string) (map[string]bool, error) {
+	byJSONName := map[string]string{}
+	var jsonNames []string
+	for name, jsonName := range GeneratorJSONNames() {
+		byJSONName[strings.ToLower(jsonName)] = name
+		jsonNames = append(jsonNames, jsonName)
+	}
+	sort.Strings(jsonNames)
+
+	parse := func(list string) (map[string]bool, error) {
+		res := map[string]bool{}
+		for _, jsonName := range strings.Split(list, ",") {
+			jsonName = strings.TrimSpace(jsonName)
+			if jsonName == "" {
+				continue
+			}
+			name, ok := byJSONName[strings.ToLower(jsonName)]
+			if !ok {
+				return nil, fmt.Errorf("unknown generator %q, the generators are %s", jsonName, strings.Join(jsonNames, ","))
+			}
+			res[name] = true
+		}
+		return res, nil
+	}
+
+	enabledNames, err := parse(enabled)
+	if err != nil {
+		return nil, err
+	}
+	res, err := parse(disabled)
+	if err != nil {
+		return nil, err
+	}
+	if len(enabledNames) > 0 {
+		for _, name := range byJSONName {
+			if !enabledNames[name] {
+				res[name] = true
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorJSONNames(t *testing.T) {
+	names := GeneratorJSONNames()
+	assert.Equal(t, "scmProvider", names["SCMProvider"])
+	assert.Equal(t, "clusters", names["Clusters"])
+	assert.Equal(t, "matrix", names["Matrix"])
+	assert.NotContains(t, names, "Schedule")
+}
+
+func TestParseDisabledGenerators(t *testing.T) {
+	disabled, err := ParseDisabledGenerators("", "")
+	require.NoError(t, err)
+	assert.Empty(t, disabled)
+
+	disabled, err = ParseDisabledGenerators("", " clusters, HTTP,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Clusters": true, "HTTP": true}, disabled)
+
+	disabled, err = ParseDisabledGenerators("list,clusters,git,matrix", "clusters")
+	require.NoError(t, err)
+	assert.True(t, disabled["Clusters"])
+	assert.True(t, disabled["SCMProvider"])
+	assert.True(t, disabled["Merge"])
+	assert.False(t, disabled["List"])
+	assert.False(t, disabled["Git"])
+	assert.False(t, disabled["Matrix"])
+
+	_, err = ParseDisabledGenerators("list,cluster", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown generator "cluster", the generators are `)
+	_, err = ParseDisabledGenerators("", "unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,264 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// sprigFuncMap are the Sprig functions available in Go templates, without the functions which read the environment of
+// the controller, and the functions to build valid Application names.
+var sprigFuncMap = sprig.GenericFuncMap()
+
+func init() {
+	delete(sprigFuncMap, "env")
+	delete(sprigFuncMap, "expandenv")
+	delete(sprigFuncMap, "getHostByName")
+
+	sprigFuncMap["slugify"] = slugify
+	sprigFuncMap["truncName"] = truncName
+	sprigFuncMap["shortHash"] = shortHash
+}
+
+// shortHashLength is the number of hexadecimal characters of the hashes of shortHash and truncName
+const shortHashLength = 8
+
+// slugify lowercases the string and replaces each run of characters other than lowercase alphanumeric characters by a
+// single '-', without leading or trailing '-', e.g. feature/My_Branch becomes feature-my-branch.
+func slugify(str string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(str) {
+		if ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(c)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// shortHash returns the first characters of the hexadecimal SHA-256 of the string.
+func shortHash(str string) string {
+	sum := sha256.Sum256([]byte(str))
+	return hex.EncodeToString(sum[:])[:shortHashLength]
+}
+
+// truncName returns the string unchanged if it has at most length characters. Otherwise the string is truncated, and
+// suffixed by '-' and the shortHash of the whole string, so that different long strings with the same prefix remain
+// different, e.g. {{ .branch | slugify | truncName 63 }} is a valid label value for any branch.
+func truncName(length int, str string) string {
+	if len(str) <= length {
+		return str
+	}
+	hash := shortHash(str)
+	if length <= shortHashLength {
+		// No room for a prefix: only the hash, truncated itself
+		if length < 0 {
+			return ""
+		}
+		return hash[:length]
+	}
+	if length == shortHashLength+1 {
+		return hash
+	}
+	prefix := strings.TrimRight(str[:length-shortHashLength-1], "-.")
+	if prefix == "" {
+		return hash
+	}
+	return prefix + "-" + hash
+}
+
+// renderGoTemplate renders each string of the JSON template, map keys included, as a Go template with the params as
+// data.
+func (r *Render) renderGoTemplate(tmplBytes []byte, params map[string]interface{}, options []string) (string, error) {
+	var tmpl interface{}
+	if err := json.Unmarshal(tmplBytes, &tmpl); err != nil {
+		return "", err
+	}
+
+	base, err := r.newGoTemplate(options)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := renderGoTemplateValue(base, tmpl, goTemplateData(params))
+	if err != nil {
+		return "", err
+	}
+
+	renderedBytes, err := json.Marshal(rendered)
+	if err != nil {
+		return "", err
+	}
+	return string(renderedBytes), nil
+}
+
+func renderGoTemplateValue(base *template.Template, value interface{}, data map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderGoTemplateString(base, v, data)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			renderedKey, err := renderGoTemplateString(base, key, data)
+			if err != nil {
+				return nil, err
+			}
+			res[renderedKey], err = renderGoTemplateValue(base, element, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, element := range v {
+			var err error
+			res[i], err = renderGoTemplateValue(base, element, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default:
+		return value, nil
+	}
+}
+
+// renderGoTemplateString renders the string as a Go template, parsed with base, which defines the functions and the
+// template partials.
+func renderGoTemplateString(base *template.Template, str string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(str, "{{") {
+		return str, nil
+	}
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return "", err
+	}
+	tmpl, err = tmpl.Parse(str)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %v", str, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %v", str, err)
+	}
+	return buf.String(), nil
+}
+
+// newGoTemplate returns the template the Go templates of the ApplicationSet are parsed with: with the functions, the
+// options, and the template partials of the controller, if any.
+func (r *Render) newGoTemplate(options []string) (*template.Template, error) {
+	if r.Partials == nil {
+		return newGoTemplate(nil, options)
+	}
+
+	partials, err := r.Partials.GetTemplatePartials()
+	if err != nil {
+		return nil, err
+	}
+	return newGoTemplate(partials, options)
+}
+
+// newGoTemplate returns an empty template with the functions and the options, which defines the given template
+// partials by name.
+func newGoTemplate(partials map[string]string, options []string) (*template.Template, error) {
+	base := template.New("").Funcs(sprigFuncMap)
+	for _, option := range options {
+		if err := setGoTemplateOption(base, option); err != nil {
+			return nil, err
+		}
+	}
+	for name, partial := range partials {
+		if _, err := base.New(name).Parse(partial); err != nil {
+			return nil, fmt.Errorf("failed to parse template partial %s: %v", name, err)
+		}
+	}
+	return base, nil
+}
+
+// setGoTemplateOption sets the option of the template, returning an error rather than panicking, as Template.Option
+// does, if the option is invalid.
+func setGoTemplateOption(tmpl *template.Template, option string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid Go template option %s: %v", option, r)
+		}
+	}()
+	tmpl.Option(option)
+	return nil
+}
+
+// goTemplateData returns the data of Go templates: the params by name, and the params with dotted names as nested
+// fields as well, e.g. values.env is both {{ index . "values.env" }} and {{ .values.env }}. A param with the same name
+// as the prefix of a dotted name takes precedence over the nested fields, e.g. {{ .path }} is the path param, and
+// path.basename is only available by name. Nested fields are added to copies of the object params, which are never
+// modified.
+func goTemplateData(params map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(params))
+	names := make([]string, 0, len(params))
+	for name, value := range params {
+		data[name] = value
+		names = append(names, name)
+	}
+	// Shorter names, which may be prefixes of other names, come first
+	sort.Strings(names)
+
+	// copied are the prefixes of the nested fields which are maps created or copied here
+	copied := map[string]bool{}
+	for _, name := range names {
+		parts := strings.Split(name, ".")
+		if len(parts) < 2 {
+			continue
+		}
+
+		fields := data
+		for i, part := range parts[:len(parts)-1] {
+			prefix := strings.Join(parts[:i+1], ".")
+			next, ok := fields[part]
+			if !ok {
+				next = map[string]interface{}{}
+				copied[prefix] = true
+			}
+			nextFields, ok := next.(map[string]interface{})
+			if !ok {
+				fields = nil
+				break
+			}
+			if !copied[prefix] {
+				nextFields = copyFields(nextFields)
+				copied[prefix] = true
+			}
+			fields[part] = nextFields
+			fields = nextFields
+		}
+		if fields == nil {
+			continue
+		}
+		if _, ok := fields[parts[len(parts)-1]]; !ok {
+			fields[parts[len(parts)-1]] = params[name]
+		}
+	}
+	return data
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,315 @@
+package utils
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderGoTemplateParams(t *testing.T) {
+	params := map[string]interface{}{
+		"name":           "guestbook",
+		"values.env":     "production",
+		"values.branch":  "feature/My_Branch",
+		"path":           "apps/guestbook",
+		"path.basename":  "guestbook",
+		"replicas":       "3",
+		"clusters":       `["eu","us"]`,
+		"metadata.owner": "",
+		"regions":        []interface{}{"eu", "us"},
+		"labels":         map[string]interface{}{"team": "platform"},
+		"labels.tier":    "frontend",
+	}
+
+	cases := []struct {
+		name          string
+		fieldVal      string
+		expectedVal   string
+		expectedError string
+	}{
+		{
+			name:        "simple substitution",
+			fieldVal:    "{{ .name }}",
+			expectedVal: "guestbook",
+		},
+		{
+			name:        "dotted param as nested field",
+			fieldVal:    "{{ .values.env }}",
+			expectedVal: "production",
+		},
+		{
+			name:        "dotted param by name",
+			fieldVal:    `{{ index . "values.env" }}`,
+			expectedVal: "production",
+		},
+		{
+			name:        "param with the name of a prefix takes precedence",
+			fieldVal:    `{{ .path }}/{{ index . "path.basename" }}`,
+			expectedVal: "apps/guestbook/guestbook",
+		},
+		{
+			name:        "conditional",
+			fieldVal:    `{{ if eq .values.env "production" }}prod{{ else }}dev{{ end }}`,
+			expectedVal: "prod",
+		},
+		{
+			name:        "sprig functions",
+			fieldVal:    `{{ .name | upper | trunc 5 }}-{{ .metadata.owner | default "platform" }}`,
+			expectedVal: "GUEST-platform",
+		},
+		{
+			name:        "loop over a list param",
+			fieldVal:    `{{ range $i, $c := fromJson .clusters }}{{ if $i }},{{ end }}{{ $c }}{{ end }}`,
+			expectedVal: "eu,us",
+		},
+		{
+			name:        "loop over a structured param",
+			fieldVal:    `{{ range $i, $r := .regions }}{{ if $i }},{{ end }}{{ $r | upper }}{{ end }}`,
+			expectedVal: "EU,US",
+		},
+		{
+			name:        "dotted param merged into a structured param",
+			fieldVal:    `{{ .labels.team }}-{{ .labels.tier }}`,
+			expectedVal: "platform-frontend",
+		},
+		{
+			name:        "special characters are not escaped",
+			fieldVal:    `{{ printf "%q" .name }}`,
+			expectedVal: `"guestbook"`,
+		},
+		{
+			name:        "name functions",
+			fieldVal:    `{{ .values.branch | slugify }}-{{ .values.branch | shortHash }}`,
+			expectedVal: "feature-my-branch-" + shortHash("feature/My_Branch"),
+		},
+		{
+			name:        "no template",
+			fieldVal:    "{ .name }",
+			expectedVal: "{ .name }",
+		},
+		{
+			name:          "invalid template",
+			fieldVal:      "{{ .name ",
+			expectedError: "failed to parse template {{ .name : template: :1: unclosed action",
+		},
+		{
+			name:          "environment of the controller is not available",
+			fieldVal:      `{{ env "HOME" }}`,
+			expectedError: "failed to parse template {{ env \"HOME\" }}: template: :1: function \"env\" not defined",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			application := &argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Annotations: map[string]string{"example.com/{{ .name }}": cc.fieldVal},
+				},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source: argov1alpha1.ApplicationSource{Path: cc.fieldVal},
+				},
+			}
+
+			render := Render{}
+			newApplication, err := render.RenderTemplateParams(application, nil, params, true, nil)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedVal, newApplication.Spec.Source.Path)
+			assert.Equal(t, map[string]string{"example.com/guestbook": cc.expectedVal}, newApplication.Annotations)
+		})
+	}
+}
+
+func TestGoTemplateDataDoesNotModifyParams(t *testing.T) {
+	labels := map[string]interface{}{"team": "platform"}
+	params := map[string]interface{}{
+		"labels":      labels,
+		"labels.tier": "frontend",
+	}
+
+	data := goTemplateData(params)
+
+	assert.Equal(t, map[string]interface{}{"team": "platform", "tier": "frontend"}, data["labels"])
+	assert.Equal(t, map[string]interface{}{"team": "platform"}, labels)
+}
+
+func TestSlugify(t *testing.T) {
+	for _, c := range []struct {
+		str      string
+		expected string
+	}{
+		{str: "main", expected: "main"},
+		{str: "feature/My_Branch", expected: "feature-my-branch"},
+		{str: "--release/1.2.x//", expected: "release-1-2-x"},
+		{str: "héllo wörld", expected: "h-llo-w-rld"},
+		{str: "/", expected: ""},
+	} {
+		assert.Equal(t, c.expected, slugify(c.str), c.str)
+	}
+}
+
+func TestTruncName(t *testing.T) {
+	long := "feature-a-very-long-branch-name"
+
+	for _, c := range []struct {
+		name     string
+		length   int
+		str      string
+		expected string
+	}{
+		{name: "short string is unchanged", length: 63, str: long, expected: long},
+		{name: "string of the exact length is unchanged", length: len(long), str: long, expected: long},
+		{name: "long string is truncated with a hash", length: 20, str: long, expected: "feature-a-v-" + shortHash(long)},
+		{name: "separators before the hash are trimmed", length: 19, str: long, expected: "feature-a-" + shortHash(long)},
+		{name: "only the hash", length: 9, str: long, expected: shortHash(long)},
+		{name: "truncated hash", length: 4, str: long, expected: shortHash(long)[:4]},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, truncName(c.length, c.str))
+			assert.LessOrEqual(t, len(truncName(c.length, c.str)), c.length)
+		})
+	}
+
+	assert.NotEqual(t, truncName(20, long+"-1"), truncName(20, long+"-2"))
+}
+
+// staticTemplatePartials are template partials which are defined by the tests.
+type staticTemplatePartials map[string]string
+
+func (p staticTemplatePartials) GetTemplatePartials() (map[string]string, error) {
+	return p, nil
+}
+
+func TestRenderGoTemplateParamsWithPartials(t *testing.T) {
+	params := map[string]interface{}{"cluster": "engineering-dev"}
+
+	cases := []struct {
+		name          string
+		partials      TemplatePartials
+		fieldVal      string
+		expectedVal   string
+		expectedError string
+	}{
+		{
+			name:        "include a partial",
+			partials:    staticTemplatePartials{"app-name": "{{ .cluster }}-guestbook"},
+			fieldVal:    `{{ template "app-name" . }}`,
+			expectedVal: "engineering-dev-guestbook",
+		},
+		{
+			name:        "partial using another partial and functions",
+			partials:    staticTemplatePartials{"app-name": `{{ template "prefix" . }}-{{ .cluster | upper }}`, "prefix": "apps"},
+			fieldVal:    `{{ template "app-name" . }}`,
+			expectedVal: "apps-ENGINEERING-DEV",
+		},
+		{
+			name:          "missing partial",
+			partials:      staticTemplatePartials{},
+			fieldVal:      `{{ template "app-name" . }}`,
+			expectedError: `failed to execute template {{ template "app-name" . }}: template: :1:12: executing "" at <{{template "app-name" .}}>: template "app-name" not defined`,
+		},
+		{
+			name:          "invalid partial",
+			partials:      staticTemplatePartials{"app-name": "{{ .cluster "},
+			fieldVal:      `{{ template "app-name" . }}`,
+			expectedError: "failed to parse template partial app-name: template: app-name:1: unclosed action",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			application := &argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source: argov1alpha1.ApplicationSource{Path: cc.fieldVal},
+				},
+			}
+
+			render := Render{Partials: cc.partials}
+			newApplication, err := render.RenderTemplateParams(application, nil, params, true, nil)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedVal, newApplication.Spec.Source.Path)
+		})
+	}
+}
+
+func TestRenderGoTemplateParamsWithOptions(t *testing.T) {
+	params := map[string]interface{}{"cluster": "engineering-dev", "values.env": "dev"}
+
+	cases := []struct {
+		name          string
+		options       []string
+		fieldVal      string
+		expectedVal   string
+		expectedError string
+	}{
+		{
+			name:        "missing key without options",
+			fieldVal:    "{{ .namespace }}",
+			expectedVal: "<no value>",
+		},
+		{
+			name:        "missing key as the zero value",
+			options:     []string{"missingkey=zero"},
+			fieldVal:    "{{ .namespace }}",
+			expectedVal: "<no value>",
+		},
+		{
+			name:        "existing keys with missingkey=error",
+			options:     []string{"missingkey=error"},
+			fieldVal:    "{{ .cluster }}-{{ .values.env }}",
+			expectedVal: "engineering-dev-dev",
+		},
+		{
+			name:          "missing key with missingkey=error",
+			options:       []string{"missingkey=error"},
+			fieldVal:      "{{ .namespace }}",
+			expectedError: `failed to execute template {{ .namespace }}: template: :1:3: executing "" at <.namespace>: map has no entry for key "namespace"`,
+		},
+		{
+			name:          "missing nested key with missingkey=error",
+			options:       []string{"missingkey=error"},
+			fieldVal:      "{{ .values.environment }}",
+			expectedError: `failed to execute template {{ .values.environment }}: template: :1:10: executing "" at <.values.environment>: map has no entry for key "environment"`,
+		},
+		{
+			name:          "invalid option",
+			options:       []string{"missingkey=fail"},
+			fieldVal:      "{{ .cluster }}",
+			expectedError: "invalid Go template option missingkey=fail: unrecognized option: missingkey=fail",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			application := &argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: argov1alpha1.ApplicationSpec{
+					Source: argov1alpha1.ApplicationSource{Path: cc.fieldVal},
+				},
+			}
+
+			render := Render{}
+			newApplication, err := render.RenderTemplateParams(application, nil, params, true, cc.options)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedVal, newApplication.Spec.Source.Path)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// HTTPClientOptions are the options of the HTTP clients of the generators calling external APIs.
+type HTTPClientOptions struct {
+	// ProxyURL is the URL of the HTTP or HTTPS proxy of the requests, overriding the default proxy.
+	ProxyURL string
+	// Insecure disables the verification of the TLS certificates of the servers.
+	Insecure bool
+	// CAData are the PEM-encoded certificates of the CAs trusted in addition to the system CAs.
+	CAData string
+}
+
+var (
+	httpClientsLock sync.Mutex
+	// defaultProxyURL is the proxy of the clients whose options don't set one, nil to read it from the environment.
+	defaultProxyURL *url.URL
+	// httpClients caches the clients by options, so that their connections are reused by the next generations.
+	httpClients = map[HTTPClientOptions]*http.Client{}
+)
+
+// SetDefaultProxy sets the proxy of the HTTP clients whose options don't set one. Without a default proxy, the proxy is
+// read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func SetDefaultProxy(proxyURL string) error {
+	parsed, err := parseProxyURL(proxyURL)
+	if err != nil {
+		return err
+	}
+
+	httpClientsLock.Lock()
+	defer httpClientsLock.Unlock()
+	defaultProxyURL = parsed
+	httpClients = map[HTTPClientOptions]*http.Client{}
+	return nil
+}
+
+// NewHTTPClient returns the HTTP client of the given options. The clients are shared by the generators, so they must
+// not be modified.
+func NewHTTPClient(opts HTTPClientOptions) (*http.Client, error) {
+	proxyURL, err := parseProxyURL(opts.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClientsLock.Lock()
+	defer httpClientsLock.Unlock()
+	if client, ok := httpClients[opts]; ok {
+		return client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch {
+	case proxyURL != nil:
+		transport.Proxy = http.ProxyURL(proxyURL)
+	case defaultProxyURL != nil:
+		transport.Proxy = http.ProxyURL(defaultProxyURL)
+	}
+	if opts.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	} else if opts.CAData != "" {
+		rootCAs, err := newCertPool(opts.CAData)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+
+	client := &http.Client{Transport: transport}
+	httpClients[opts] = client
+	return client, nil
+}
+
+// newCertPool returns the system CAs and the CAs of the PEM-encoded certificates.
+func newCertPool(caData string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(caData)) {
+		return nil, fmt.Errorf("error parsing the CA certificates: no PEM-encoded certificate found")
+	}
+	return pool, nil
+}
+
+// parseProxyURL parses the URL of an HTTP or HTTPS proxy, nil if empty.
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the proxy URL %q: %v", proxyURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("the proxy URL %q must be an http:// or https:// URL", proxyURL)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyOf returns the proxy URL of the requests of the client to https://example.com.
+func proxyOf(t *testing.T, client *http.Client) string {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+	proxyURL, err := client.Transport.(*http.Transport).Proxy(req)
+	require.NoError(t, err)
+	if proxyURL == nil {
+		return ""
+	}
+	return proxyURL.String()
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetDefaultProxy(""))
+	}()
+	client, err := NewHTTPClient(HTTPClientOptions{})
+	require.NoError(t, err)
+
+	// the clients are shared by the generators with the same options
+	other, err := NewHTTPClient(HTTPClientOptions{})
+	require.NoError(t, err)
+	assert.Same(t, client, other)
+
+	insecure, err := NewHTTPClient(HTTPClientOptions{Insecure: true})
+	require.NoError(t, err)
+	assert.NotSame(t, client, insecure)
+	assert.True(t, insecure.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+
+	client, err = NewHTTPClient(HTTPClientOptions{ProxyURL: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyOf(t, client))
+
+	// the default proxy is used unless the options set one
+	require.NoError(t, SetDefaultProxy("https://default-proxy.example.com"))
+	client, err = NewHTTPClient(HTTPClientOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://default-proxy.example.com", proxyOf(t, client))
+	client, err = NewHTTPClient(HTTPClientOptions{ProxyURL: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyOf(t, client))
+}
+
+func TestNewHTTPClientInvalidProxy(t *testing.T) {
+	for _, proxyURL := range []string{"proxy.example.com:3128", "socks5://proxy.example.com", "http://", ":"} {
+		t.Run(proxyURL, func(t *testing.T) {
+			_, err := NewHTTPClient(HTTPClientOptions{ProxyURL: proxyURL})
+			assert.Error(t, err)
+			assert.Error(t, SetDefaultProxy(proxyURL))
+		})
+	}
+}
+
+func TestNewHTTPClientCAData(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	client, err := NewHTTPClient(HTTPClientOptions{})
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+
+	client, err = NewHTTPClient(HTTPClientOptions{CAData: caData})
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = NewHTTPClient(HTTPClientOptions{CAData: "not a certificate"})
+	assert.EqualError(t, err, "error parsing the CA certificates: no PEM-encoded certificate found")
+}
@@ -0,0 +1,280 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/itchyny/gojq"
+)
+
+// IgnoreDifferences sets the ignored fields of the generated Application to their values in the existing Application,
+// or removes them from the generated Application if they are not set in the existing Application, so that the
+// differences in these fields don't cause the existing Application to be updated. The ignored fields are selected by
+// the JSON pointers and the jq path expressions of the ignore differences which apply to the Application. A jq path
+// expression which fails on an Application, e.g. because it iterates over a missing field, selects no field of it.
+func IgnoreDifferences(ignoreDifferences []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences, existing *argov1alpha1.Application, generated *argov1alpha1.Application) error {
+	var pointers, expressions []string
+	for _, ignoreDifference := range ignoreDifferences {
+		if ignoreDifference.Name != "" && ignoreDifference.Name != generated.Name {
+			continue
+		}
+		pointers = append(pointers, ignoreDifference.JSONPointers...)
+		expressions = append(expressions, ignoreDifference.JQPathExpressions...)
+	}
+	if len(pointers) == 0 && len(expressions) == 0 {
+		return nil
+	}
+
+	existingObj, err := toObject(existing)
+	if err != nil {
+		return fmt.Errorf("failed to convert the existing Application %s: %v", existing.Name, err)
+	}
+	generatedObj, err := toObject(generated)
+	if err != nil {
+		return fmt.Errorf("failed to convert the generated Application %s: %v", generated.Name, err)
+	}
+
+	var paths [][]interface{}
+	for _, pointer := range pointers {
+		path, err := parseJSONPointer(pointer)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+	for _, expression := range expressions {
+		code, err := compileJQPathExpression(expression)
+		if err != nil {
+			return err
+		}
+		// The fields which are only set in the generated Application are selected too, to be removed
+		paths = append(paths, jqPaths(code, existingObj)...)
+		paths = append(paths, jqPaths(code, generatedObj)...)
+	}
+
+	// The fields are set before they are removed, and removed in reverse order, so that removing an element of a list
+	// doesn't change the path of the next ones.
+	var removed [][]interface{}
+	seen := map[string]bool{}
+	for _, path := range paths {
+		key := pathKey(path)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		value, found := getPath(existingObj, path)
+		if !found {
+			removed = append(removed, path)
+			continue
+		}
+		setPath(generatedObj, path, value)
+	}
+	sort.SliceStable(removed, func(i, j int) bool {
+		return lessPath(removed[j], removed[i])
+	})
+	var res interface{} = generatedObj
+	for _, path := range removed {
+		res = removePath(res, path)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("failed to ignore the differences of Application %s: %v", generated.Name, err)
+	}
+	var app argov1alpha1.Application
+	if err := json.Unmarshal(data, &app); err != nil {
+		return fmt.Errorf("failed to ignore the differences of Application %s: %v", generated.Name, err)
+	}
+	*generated = app
+	return nil
+}
+
+// parseJSONPointer returns the reference tokens of a JSON pointer, as defined by RFC 6901.
+func parseJSONPointer(pointer string) ([]interface{}, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %s: must start with /", pointer)
+	}
+	var res []interface{}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		res = append(res, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+	}
+	return res, nil
+}
+
+// compileJQPathExpression compiles the jq expression returning the paths selected by the jq path expression.
+func compileJQPathExpression(expression string) (*gojq.Code, error) {
+	query, err := gojq.Parse(fmt.Sprintf("path(%s)", expression))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq path expression %s: %v", expression, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq path expression %s: %v", expression, err)
+	}
+	return code, nil
+}
+
+// jqPaths returns the paths selected by the compiled jq path expression in the object, until the expression fails.
+func jqPaths(code *gojq.Code, obj map[string]interface{}) [][]interface{} {
+	var res [][]interface{}
+	iter := code.Run(obj)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			return res
+		}
+		path, ok := value.([]interface{})
+		if !ok {
+			return res
+		}
+		res = append(res, path)
+	}
+}
+
+// getPath returns the value at the path in the object, whose elements are the keys of maps, or the indexes of lists
+// as integers or strings.
+func getPath(obj interface{}, path []interface{}) (interface{}, bool) {
+	current := obj
+	for _, key := range path {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			k, ok := key.(string)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = v[k]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			index, ok := pathIndex(key)
+			if !ok || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setPath sets the value at the path in the object, creating the missing maps. The value is not set if the path goes
+// through a missing element of a list, or a value which is neither a map nor a list.
+func setPath(obj map[string]interface{}, path []interface{}, value interface{}) {
+	if len(path) == 0 {
+		return
+	}
+	var current interface{} = obj
+	for i, key := range path {
+		last := i == len(path)-1
+		switch v := current.(type) {
+		case map[string]interface{}:
+			k, ok := key.(string)
+			if !ok {
+				return
+			}
+			if last {
+				v[k] = value
+				return
+			}
+			if _, ok := v[k]; !ok || v[k] == nil {
+				v[k] = map[string]interface{}{}
+			}
+			current = v[k]
+		case []interface{}:
+			index, ok := pathIndex(key)
+			if !ok || index < 0 || index >= len(v) {
+				return
+			}
+			if last {
+				v[index] = value
+				return
+			}
+			current = v[index]
+		default:
+			return
+		}
+	}
+}
+
+// removePath returns the object without the value at the path, if any.
+func removePath(obj interface{}, path []interface{}) interface{} {
+	if len(path) == 0 {
+		return obj
+	}
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		k, ok := path[0].(string)
+		if !ok {
+			return obj
+		}
+		child, ok := v[k]
+		if !ok {
+			return obj
+		}
+		if len(path) == 1 {
+			delete(v, k)
+		} else {
+			v[k] = removePath(child, path[1:])
+		}
+	case []interface{}:
+		index, ok := pathIndex(path[0])
+		if !ok || index < 0 || index >= len(v) {
+			return obj
+		}
+		if len(path) == 1 {
+			return append(v[:index:index], v[index+1:]...)
+		}
+		v[index] = removePath(v[index], path[1:])
+	}
+	return obj
+}
+
+// lessPath compares the paths element by element, the indexes of lists numerically and the keys of maps lexically.
+func lessPath(a []interface{}, b []interface{}) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		indexA, okA := pathIndex(a[i])
+		indexB, okB := pathIndex(b[i])
+		if okA && okB {
+			if indexA != indexB {
+				return indexA < indexB
+			}
+			continue
+		}
+		keyA, keyB := fmt.Sprint(a[i]), fmt.Sprint(b[i])
+		if keyA != keyB {
+			return keyA < keyB
+		}
+	}
+	return len(a) < len(b)
+}
+
+// pathKey returns a key identifying the path, which is the same for the indexes of lists given as integers or strings.
+func pathKey(path []interface{}) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = fmt.Sprint(key)
+	}
+	return strings.Join(keys, "\x00")
+}
+
+// pathIndex returns the index of a list of a path element, which is an integer for jq paths, or a string for JSON
+// pointers.
+func pathIndex(key interface{}) (int, bool) {
+	switch k := key.(type) {
+	case int:
+		return k, true
+	case float64:
+		return int(k), true
+	case string:
+		index, err := strconv.Atoi(k)
+		return index, err == nil
+	}
+	return 0, false
+}
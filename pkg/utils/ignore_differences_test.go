@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"testing"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIgnoreDifferences(t *testing.T) {
+	existing := argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Annotations: map[string]string{"example.com/mutated": "true"},
+		},
+		Spec: argov1alpha1.ApplicationSpec{
+			Project: "default",
+			Source: argov1alpha1.ApplicationSource{
+				RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+				TargetRevision: "v1.0.0",
+			},
+			SyncPolicy: &argov1alpha1.SyncPolicy{SyncOptions: []string{"CreateNamespace=true", "Validate=false"}},
+			Info:       []argov1alpha1.Info{{Name: "owner", Value: "existing"}},
+		},
+	}
+	newGenerated := func() argov1alpha1.Application {
+		return argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "default",
+				Source: argov1alpha1.ApplicationSource{
+					RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+					TargetRevision: "HEAD",
+				},
+				SyncPolicy: &argov1alpha1.SyncPolicy{SyncOptions: []string{"CreateNamespace=true"}},
+				Info:       []argov1alpha1.Info{{Name: "owner", Value: "generated"}, {Name: "team", Value: "generated"}},
+			},
+		}
+	}
+
+	cases := []struct {
+		name              string
+		ignoreDifferences []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences
+		expected          func(app *argov1alpha1.Application)
+		expectedError     string
+	}{
+		{
+			name:     "no ignored differences",
+			expected: func(app *argov1alpha1.Application) {},
+		},
+		{
+			name: "JSON pointers",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JSONPointers: []string{"/spec/source/targetRevision", "/metadata/annotations/example.com~1mutated", "/spec/info/1"}},
+			},
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.Source.TargetRevision = "v1.0.0"
+				app.Annotations = map[string]string{"example.com/mutated": "true"}
+				app.Spec.Info = []argov1alpha1.Info{{Name: "owner", Value: "generated"}}
+			},
+		},
+		{
+			name: "jq path expressions",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JQPathExpressions: []string{`.spec.syncPolicy.syncOptions`, `.spec.info[] | select(.name == "owner") | .value`}},
+			},
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.SyncPolicy.SyncOptions = []string{"CreateNamespace=true", "Validate=false"}
+				app.Spec.Info[0].Value = "existing"
+			},
+		},
+		{
+			name: "jq path expression selecting fields only set in the generated Application",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JQPathExpressions: []string{`.spec.info[] | select(.name == "team")`}},
+			},
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.Info = []argov1alpha1.Info{{Name: "owner", Value: "generated"}}
+			},
+		},
+		{
+			name: "jq path expression failing on the Applications",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JQPathExpressions: []string{`.spec.destination.namespace[]`}},
+			},
+			expected: func(app *argov1alpha1.Application) {},
+		},
+		{
+			name: "differences ignored for other Applications",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{Name: "other-app", JSONPointers: []string{"/spec/source/targetRevision"}},
+			},
+			expected: func(app *argov1alpha1.Application) {},
+		},
+		{
+			name: "differences ignored for the Application",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{Name: "app", JSONPointers: []string{"/spec/source/targetRevision"}},
+			},
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.Source.TargetRevision = "v1.0.0"
+			},
+		},
+		{
+			name: "invalid JSON pointer",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JSONPointers: []string{"spec/source"}},
+			},
+			expectedError: "invalid JSON pointer spec/source: must start with /",
+		},
+		{
+			name: "invalid jq path expression",
+			ignoreDifferences: []argoprojiov1alpha1.ApplicationSetResourceIgnoreDifferences{
+				{JQPathExpressions: []string{`.spec.info[`}},
+			},
+			expectedError: "invalid jq path expression .spec.info[",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			generated := newGenerated()
+			err := IgnoreDifferences(cc.ignoreDifferences, &existing, &generated)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			expected := newGenerated()
+			cc.expected(&expected)
+			assert.Equal(t, expected, generated)
+		})
+	}
+}
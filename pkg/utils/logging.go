@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+type loggerKey struct{}
+
+// ApplicationSetLogger returns a logger with the name and namespace of the ApplicationSet as fields. It logs at the
+// level of the log level annotation of the ApplicationSet if it is set, or at the level of the standard logger.
+func ApplicationSetLogger(appSet *argoprojiov1alpha1.ApplicationSet) *log.Entry {
+	if appSet == nil {
+		return log.NewEntry(log.StandardLogger())
+	}
+
+	logger := log.StandardLogger()
+	if value, exists := appSet.Annotations[common.AnnotationApplicationSetLogLevel]; exists {
+		level, err := log.ParseLevel(value)
+		if err != nil {
+			log.WithFields(log.Fields{"applicationset": appSet.Name, "namespace": appSet.Namespace}).
+				Warnf("ignoring the invalid log level %q of the %s annotation", value, common.AnnotationApplicationSetLogLevel)
+		} else {
+			logger = levelLogger(level)
+		}
+	}
+	return logger.WithFields(log.Fields{"applicationset": appSet.Name, "namespace": appSet.Namespace})
+}
+
+// levelLogger returns a logger which writes like the standard logger, but at the given level.
+func levelLogger(level log.Level) *log.Logger {
+	std := log.StandardLogger()
+	logger := log.New()
+	logger.SetOutput(std.Out)
+	logger.SetFormatter(std.Formatter)
+	logger.SetReportCaller(std.ReportCaller)
+	logger.ReplaceHooks(std.Hooks)
+	logger.SetLevel(level)
+	return logger
+}
+
+// ContextWithLogger returns a copy of the context which carries the logger.
+func ContextWithLogger(ctx context.Context, logger *log.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger carried by the context, or the standard logger if there is none.
+func LoggerFromContext(ctx context.Context) *log.Entry {
+	if logger, ok := ctx.Value(loggerKey{}).(*log.Entry); ok {
+		return logger
+	}
+	return log.NewEntry(log.StandardLogger())
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+)
+
+func TestApplicationSetLogger(t *testing.T) {
+	std := log.StandardLogger()
+	out, level := std.Out, std.Level
+	defer func() {
+		std.SetOutput(out)
+		std.SetLevel(level)
+	}()
+	var buf bytes.Buffer
+	std.SetOutput(&buf)
+	std.SetLevel(log.InfoLevel)
+
+	for _, c := range []struct {
+		name          string
+		annotations   map[string]string
+		expectedLevel log.Level
+	}{
+		{
+			name:          "level of the standard logger",
+			expectedLevel: log.InfoLevel,
+		},
+		{
+			name:          "level of the annotation",
+			annotations:   map[string]string{common.AnnotationApplicationSetLogLevel: "debug"},
+			expectedLevel: log.DebugLevel,
+		},
+		{
+			name:          "invalid level of the annotation",
+			annotations:   map[string]string{common.AnnotationApplicationSetLogLevel: "verbose"},
+			expectedLevel: log.InfoLevel,
+		},
+	} {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			buf.Reset()
+			appSet := &argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace", Annotations: cc.annotations},
+			}
+
+			logger := ApplicationSetLogger(appSet)
+			assert.Equal(t, cc.expectedLevel, logger.Logger.GetLevel())
+			assert.Equal(t, "name", logger.Data["applicationset"])
+			assert.Equal(t, "namespace", logger.Data["namespace"])
+
+			logger.Debug("debug message")
+			assert.Equal(t, cc.expectedLevel == log.DebugLevel, bytes.Contains(buf.Bytes(), []byte("debug message")))
+		})
+	}
+	assert.Equal(t, log.InfoLevel, std.GetLevel())
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.NotNil(t, LoggerFromContext(context.Background()))
+
+	logger := log.WithField("reconcileID", "id")
+	ctx := ContextWithLogger(context.Background(), logger)
+	assert.Equal(t, logger, LoggerFromContext(ctx))
+}
@@ -2,10 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 )
 
-func CombineStringMaps(a map[string]string, b map[string]string) (map[string]string, error) {
-	res := map[string]string{}
+func CombineStringMaps(a map[string]interface{}, b map[string]interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
 
 	for k, v := range a {
 		res[k] = v
@@ -13,8 +14,8 @@ func CombineStringMaps(a map[string]string, b map[string]string) (map[string]str
 
 	for k, v := range b {
 		current, present := res[k]
-		if present && current != v {
-			return nil, fmt.Errorf("found duplicate key %s with different value, a: %s ,b: %s", k, current, v)
+		if present && !reflect.DeepEqual(current, v) {
+			return nil, fmt.Errorf("found duplicate key %s with different value, a: %v ,b: %v", k, current, v)
 		}
 		res[k] = v
 	}
@@ -23,8 +24,8 @@ func CombineStringMaps(a map[string]string, b map[string]string) (map[string]str
 }
 
 // CombineStringMapsAllowDuplicates merges two maps. Where there are duplicates, take the latter map's value.
-func CombineStringMapsAllowDuplicates(a map[string]string, b map[string]string) (map[string]string, error) {
-	res := map[string]string{}
+func CombineStringMapsAllowDuplicates(a map[string]interface{}, b map[string]interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
 
 	for k, v := range a {
 		res[k] = v
@@ -10,32 +10,45 @@ import (
 func TestCombineStringMaps(t *testing.T) {
 	testCases := []struct {
 		name        string
-		left        map[string]string
-		right       map[string]string
-		expected    map[string]string
+		left        map[string]interface{}
+		right       map[string]interface{}
+		expected    map[string]interface{}
 		expectedErr error
 	}{
 		{
 			name:        "combines the maps",
-			left:        map[string]string{"foo": "bar"},
-			right:       map[string]string{"a": "b"},
-			expected:    map[string]string{"a": "b", "foo": "bar"},
+			left:        map[string]interface{}{"foo": "bar"},
+			right:       map[string]interface{}{"a": "b"},
+			expected:    map[string]interface{}{"a": "b", "foo": "bar"},
 			expectedErr: nil,
 		},
 		{
 			name:        "fails if keys are the same but value isn't",
-			left:        map[string]string{"foo": "bar", "a": "fail"},
-			right:       map[string]string{"a": "b", "c": "d"},
-			expected:    map[string]string{"a": "b", "foo": "bar"},
+			left:        map[string]interface{}{"foo": "bar", "a": "fail"},
+			right:       map[string]interface{}{"a": "b", "c": "d"},
+			expected:    map[string]interface{}{"a": "b", "foo": "bar"},
 			expectedErr: errors.New("found duplicate key a with different value, a: fail ,b: b"),
 		},
 		{
 			name:        "pass if keys & values are the same",
-			left:        map[string]string{"foo": "bar", "a": "b"},
-			right:       map[string]string{"a": "b", "c": "d"},
-			expected:    map[string]string{"a": "b", "c": "d", "foo": "bar"},
+			left:        map[string]interface{}{"foo": "bar", "a": "b"},
+			right:       map[string]interface{}{"a": "b", "c": "d"},
+			expected:    map[string]interface{}{"a": "b", "c": "d", "foo": "bar"},
 			expectedErr: nil,
 		},
+		{
+			name:        "compares structured values",
+			left:        map[string]interface{}{"foo": "bar", "a": []interface{}{"b", "c"}},
+			right:       map[string]interface{}{"a": []interface{}{"b", "c"}, "d": map[string]interface{}{"e": "f"}},
+			expected:    map[string]interface{}{"a": []interface{}{"b", "c"}, "d": map[string]interface{}{"e": "f"}, "foo": "bar"},
+			expectedErr: nil,
+		},
+		{
+			name:        "fails if structured values are different",
+			left:        map[string]interface{}{"a": []interface{}{"b", "c"}},
+			right:       map[string]interface{}{"a": []interface{}{"b"}},
+			expectedErr: errors.New("found duplicate key a with different value, a: [b c] ,b: [b]"),
+		},
 	}
 
 	for _, testCase := range testCases {
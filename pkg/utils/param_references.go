@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/valyala/fasttemplate"
+)
+
+// ResolveParamReferences returns a copy of the params, in which the references to other params with the {{param}}
+// syntax in string values are substituted, e.g. https://{{name}}.{{domain}}. Referenced params may themselves contain
+// references, but not cyclic ones. References to params which don't exist are kept as they are.
+func ResolveParamReferences(params map[string]interface{}) (map[string]interface{}, error) {
+	r := paramReferencesResolver{
+		params:   params,
+		resolved: make(map[string]interface{}, len(params)),
+	}
+
+	for name := range params {
+		if _, err := r.resolve(name); err != nil {
+			return nil, err
+		}
+	}
+	return r.resolved, nil
+}
+
+type paramReferencesResolver struct {
+	params   map[string]interface{}
+	resolved map[string]interface{}
+	// path are the names of the params being resolved, each one referencing the next one
+	path []string
+}
+
+func (r *paramReferencesResolver) resolve(name string) (interface{}, error) {
+	if value, ok := r.resolved[name]; ok {
+		return value, nil
+	}
+	for i, resolving := range r.path {
+		if resolving == name {
+			return nil, fmt.Errorf("cyclic param references: %s", strings.Join(append(r.path[i:], name), " -> "))
+		}
+	}
+
+	value := r.params[name]
+	str, ok := value.(string)
+	if !ok || !strings.Contains(str, "{{") {
+		r.resolved[name] = value
+		return value, nil
+	}
+
+	r.path = append(r.path, name)
+	var resolveErr error
+	resolvedStr := fasttemplate.New(str, "{{", "}}").ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
+		trimmedTag := strings.TrimSpace(tag)
+		if _, ok := r.params[trimmedTag]; !ok || resolveErr != nil {
+			return w.Write([]byte(fmt.Sprintf("{{%s}}", tag)))
+		}
+		referenced, err := r.resolve(trimmedTag)
+		if err != nil {
+			resolveErr = err
+			return 0, nil
+		}
+		return w.Write([]byte(paramString(referenced)))
+	})
+	r.path = r.path[:len(r.path)-1]
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+
+	r.resolved[name] = resolvedStr
+	return resolvedStr, nil
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParamReferences(t *testing.T) {
+	cases := []struct {
+		name          string
+		params        map[string]interface{}
+		expected      map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:     "no references",
+			params:   map[string]interface{}{"name": "guestbook", "replicas": "3"},
+			expected: map[string]interface{}{"name": "guestbook", "replicas": "3"},
+		},
+		{
+			name: "references to other params",
+			params: map[string]interface{}{
+				"name":   "guestbook",
+				"domain": "example.com",
+				"url":    "https://{{name}}.{{ domain }}",
+			},
+			expected: map[string]interface{}{
+				"name":   "guestbook",
+				"domain": "example.com",
+				"url":    "https://guestbook.example.com",
+			},
+		},
+		{
+			name: "nested references",
+			params: map[string]interface{}{
+				"url":    "{{host}}/{{path}}",
+				"host":   "https://{{name}}.example.com",
+				"name":   "guestbook",
+				"path":   "api",
+				"region": "{{url}}",
+			},
+			expected: map[string]interface{}{
+				"url":    "https://guestbook.example.com/api",
+				"host":   "https://guestbook.example.com",
+				"name":   "guestbook",
+				"path":   "api",
+				"region": "https://guestbook.example.com/api",
+			},
+		},
+		{
+			name: "reference to a structured param",
+			params: map[string]interface{}{
+				"regions": []interface{}{"eu", "us"},
+				"label":   "regions={{regions}}",
+			},
+			expected: map[string]interface{}{
+				"regions": []interface{}{"eu", "us"},
+				"label":   `regions=["eu","us"]`,
+			},
+		},
+		{
+			name:     "unknown references are kept",
+			params:   map[string]interface{}{"path": "{{cluster}}/{{ .name }}"},
+			expected: map[string]interface{}{"path": "{{cluster}}/{{ .name }}"},
+		},
+		{
+			name:          "self reference",
+			params:        map[string]interface{}{"name": "app-{{name}}"},
+			expectedError: "cyclic param references: name -> name",
+		},
+		{
+			name:          "cyclic references",
+			params:        map[string]interface{}{"a": "{{b}}", "b": "x-{{c}}", "c": "{{a}}"},
+			expectedError: "cyclic param references: ",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := ResolveParamReferences(cc.params)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
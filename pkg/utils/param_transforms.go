@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// ApplyParamTransforms returns a copy of the params, with the transforms applied in order: each transform sees the
+// params set or removed by the previous ones. The values are rendered as Go templates, regardless of the template
+// syntax of the ApplicationSet, with the given options.
+func ApplyParamTransforms(params map[string]interface{}, transforms []argoprojiov1alpha1.ParamTransform, goTemplateOptions []string) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, len(params)+len(transforms))
+	for name, value := range params {
+		res[name] = value
+	}
+
+	base, err := newGoTemplate(nil, goTemplateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transform := range transforms {
+		if transform.Name == "" {
+			return nil, fmt.Errorf("param transform without a name")
+		}
+		if transform.Remove {
+			delete(res, transform.Name)
+			continue
+		}
+
+		value, err := renderGoTemplateString(base, transform.Value, goTemplateData(res))
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform param %s: %v", transform.Name, err)
+		}
+		res[transform.Name] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyParamTransforms(t *testing.T) {
+	params := map[string]interface{}{
+		"branch":        "feature/My_Branch",
+		"cluster":       "engineering-dev",
+		"values.region": "eu-west-1",
+		"regions":       []interface{}{"eu", "us"},
+	}
+
+	cases := []struct {
+		name          string
+		transforms    []argoprojiov1alpha1.ParamTransform
+		expected      map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "no transforms",
+			expected: map[string]interface{}{
+				"branch":        "feature/My_Branch",
+				"cluster":       "engineering-dev",
+				"values.region": "eu-west-1",
+				"regions":       []interface{}{"eu", "us"},
+			},
+		},
+		{
+			name: "derive, join and rename params",
+			transforms: []argoprojiov1alpha1.ParamTransform{
+				{Name: "shortName", Value: "{{ .branch | slugify | truncName 12 }}"},
+				{Name: "regionList", Value: `{{ join "," .regions }}`},
+				{Name: "region", Value: "{{ .values.region }}"},
+				{Name: "values.region", Remove: true},
+				{Name: "regions", Remove: true},
+			},
+			expected: map[string]interface{}{
+				"branch":     "feature/My_Branch",
+				"cluster":    "engineering-dev",
+				"shortName":  "fea-" + shortHash("feature-my-branch"),
+				"regionList": "eu,us",
+				"region":     "eu-west-1",
+			},
+		},
+		{
+			name: "transforms see the previous transforms",
+			transforms: []argoprojiov1alpha1.ParamTransform{
+				{Name: "cluster", Value: "{{ .cluster | upper }}"},
+				{Name: "name", Value: "{{ .cluster }}-app"},
+			},
+			expected: map[string]interface{}{
+				"branch":        "feature/My_Branch",
+				"cluster":       "ENGINEERING-DEV",
+				"name":          "ENGINEERING-DEV-app",
+				"values.region": "eu-west-1",
+				"regions":       []interface{}{"eu", "us"},
+			},
+		},
+		{
+			name:          "transform without a name",
+			transforms:    []argoprojiov1alpha1.ParamTransform{{Value: "{{ .cluster }}"}},
+			expectedError: "param transform without a name",
+		},
+		{
+			name:          "invalid template",
+			transforms:    []argoprojiov1alpha1.ParamTransform{{Name: "name", Value: "{{ .cluster "}},
+			expectedError: "failed to transform param name: failed to parse template {{ .cluster : template: :1: unclosed action",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := ApplyParamTransforms(params, cc.transforms, nil)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+
+	// The params are not modified
+	assert.Equal(t, "engineering-dev", params["cluster"])
+	assert.Len(t, params, 4)
+}
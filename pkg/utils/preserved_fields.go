@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PreserveFields sets the preserved fields of the generated Application to their values in the existing Application,
+// or removes them from the generated Application if they are not set in the existing Application, so that updating
+// the existing Application leaves them untouched. A preserved field is a path of field names separated by dots, in
+// which the keys of maps may be given in brackets, e.g. spec.source.targetRevision or
+// metadata.annotations[argocd-image-updater.argoproj.io/image-list].
+func PreserveFields(existing *argov1alpha1.Application, generated *argov1alpha1.Application, preservedFields []string) error {
+	if len(preservedFields) == 0 {
+		return nil
+	}
+
+	existingObj, err := toObject(existing)
+	if err != nil {
+		return fmt.Errorf("failed to convert the existing Application %s: %v", existing.Name, err)
+	}
+	generatedObj, err := toObject(generated)
+	if err != nil {
+		return fmt.Errorf("failed to convert the generated Application %s: %v", generated.Name, err)
+	}
+
+	for _, preservedField := range preservedFields {
+		path, err := parseFieldPath(preservedField)
+		if err != nil {
+			return err
+		}
+
+		value, found, err := unstructured.NestedFieldNoCopy(existingObj, path...)
+		if err != nil {
+			return fmt.Errorf("failed to get preserved field %s of Application %s: %v", preservedField, existing.Name, err)
+		}
+		if !found {
+			unstructured.RemoveNestedField(generatedObj, path...)
+			continue
+		}
+		if err := unstructured.SetNestedField(generatedObj, runtime.DeepCopyJSONValue(value), path...); err != nil {
+			return fmt.Errorf("failed to set preserved field %s of Application %s: %v", preservedField, generated.Name, err)
+		}
+	}
+
+	data, err := json.Marshal(generatedObj)
+	if err != nil {
+		return fmt.Errorf("failed to preserve the fields of Application %s: %v", generated.Name, err)
+	}
+	var res argov1alpha1.Application
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("failed to preserve the fields of Application %s: %v", generated.Name, err)
+	}
+	*generated = res
+	return nil
+}
+
+// toObject returns the JSON representation of the Application as a map, the unexported fields of the Application
+// being ignored.
+func toObject(app *argov1alpha1.Application) (map[string]interface{}, error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseFieldPath returns the field names of the path of a preserved field, which must be a field of the spec, or the
+// annotations, labels or finalizers of the Application.
+func parseFieldPath(field string) ([]string, error) {
+	var path []string
+	rest := field
+	for rest != "" {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 2 {
+				return nil, fmt.Errorf("invalid preserved field %s: unterminated or empty map key", field)
+			}
+			path = append(path, rest[1:end])
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end == -1 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("invalid preserved field %s: empty field name", field)
+		}
+		path = append(path, rest[:end])
+		rest = rest[end:]
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid preserved field %s: empty field name", field)
+			}
+		}
+	}
+
+	switch {
+	case len(path) >= 2 && path[0] == "spec":
+	case len(path) >= 2 && path[0] == "metadata" && (path[1] == "annotations" || path[1] == "labels"):
+	case len(path) == 2 && path[0] == "metadata" && path[1] == "finalizers":
+	default:
+		return nil, fmt.Errorf("invalid preserved field %s: must be a field of the spec, or the annotations, labels or finalizers of the Application", field)
+	}
+	return path, nil
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreserveFields(t *testing.T) {
+	existing := argov1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Annotations: map[string]string{"example.com/pinned": "true", "a": "existing"},
+			Labels:      map[string]string{"env": "existing"},
+		},
+		Spec: argov1alpha1.ApplicationSpec{
+			Project: "existing",
+			Source: argov1alpha1.ApplicationSource{
+				RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+				TargetRevision: "v1.0.0",
+			},
+			SyncPolicy: &argov1alpha1.SyncPolicy{Automated: &argov1alpha1.SyncPolicyAutomated{Prune: true}},
+		},
+	}
+	newGenerated := func() argov1alpha1.Application {
+		return argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Annotations: map[string]string{"a": "generated"},
+				Labels:      map[string]string{"env": "generated", "team": "generated"},
+			},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "generated",
+				Source: argov1alpha1.ApplicationSource{
+					RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+					TargetRevision: "HEAD",
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name            string
+		preservedFields []string
+		expected        func(app *argov1alpha1.Application)
+		expectedError   string
+	}{
+		{
+			name:     "no preserved fields",
+			expected: func(app *argov1alpha1.Application) {},
+		},
+		{
+			name:            "spec fields",
+			preservedFields: []string{"spec.source.targetRevision", "spec.syncPolicy"},
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.Source.TargetRevision = "v1.0.0"
+				app.Spec.SyncPolicy = &argov1alpha1.SyncPolicy{Automated: &argov1alpha1.SyncPolicyAutomated{Prune: true}}
+			},
+		},
+		{
+			name:            "annotation with dots in its key",
+			preservedFields: []string{"metadata.annotations[example.com/pinned]"},
+			expected: func(app *argov1alpha1.Application) {
+				app.Annotations["example.com/pinned"] = "true"
+			},
+		},
+		{
+			name:            "labels not set in the existing Application are removed",
+			preservedFields: []string{"metadata.labels.env", "metadata.labels[team]"},
+			expected: func(app *argov1alpha1.Application) {
+				app.Labels = map[string]string{"env": "existing"}
+			},
+		},
+		{
+			name:            "field outside of the spec, annotations, labels and finalizers",
+			preservedFields: []string{"metadata.name"},
+			expectedError:   "invalid preserved field metadata.name: must be a field of the spec, or the annotations, labels or finalizers of the Application",
+		},
+		{
+			name:            "empty field name",
+			preservedFields: []string{"spec..project"},
+			expectedError:   "invalid preserved field spec..project: empty field name",
+		},
+		{
+			name:            "unterminated map key",
+			preservedFields: []string{"metadata.annotations[example.com/pinned"},
+			expectedError:   "invalid preserved field metadata.annotations[example.com/pinned: unterminated or empty map key",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			generated := newGenerated()
+			err := PreserveFields(&existing, &generated, cc.preservedFields)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			expected := newGenerated()
+			cc.expected(&expected)
+			assert.Equal(t, expected, generated)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ProjectAllowList restricts the Argo CD projects of the generated Applications. An empty allow-list allows all the
+// projects.
+type ProjectAllowList []AllowedProject
+
+// AllowedProject is a project pattern of the allow-list, which may contain '*' wildcards, which match any sequence of
+// characters.
+type AllowedProject struct {
+	Pattern string
+
+	regexp *regexp.Regexp
+}
+
+// ParseProjectAllowList parses a comma-separated list of project patterns, e.g. 'team-*,default'.
+func ParseProjectAllowList(value string) ProjectAllowList {
+	var allowList ProjectAllowList
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		allowList = append(allowList, AllowedProject{Pattern: pattern, regexp: globRegexp(pattern)})
+	}
+	return allowList
+}
+
+// IsAllowed returns true if the allow-list is empty, or if one of its patterns matches the project.
+func (l ProjectAllowList) IsAllowed(project string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, allowed := range l {
+		if allowed.regexp.MatchString(project) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the allow-list in the format parsed by ParseProjectAllowList.
+func (l ProjectAllowList) String() string {
+	patterns := make([]string, len(l))
+	for i, allowed := range l {
+		patterns[i] = allowed.Pattern
+	}
+	return strings.Join(patterns, ",")
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectAllowList(t *testing.T) {
+	allowList := ParseProjectAllowList(" team-*, default,")
+	assert.Equal(t, "team-*,default", allowList.String())
+
+	assert.True(t, allowList.IsAllowed("team-a"))
+	assert.True(t, allowList.IsAllowed("default"))
+	assert.False(t, allowList.IsAllowed("platform"))
+	assert.False(t, allowList.IsAllowed("default-2"))
+
+	assert.Empty(t, ParseProjectAllowList(""))
+	assert.True(t, ProjectAllowList(nil).IsAllowed("platform"))
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"github.com/argoproj-labs/applicationset/common"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// RenderedHash returns the hexadecimal SHA-256 of the fields of the rendered Application which are applied to the
+// existing Application: its spec, labels, annotations, except the rendered hash and params hash annotations, and
+// finalizers.
+func RenderedHash(app *argov1alpha1.Application) (string, error) {
+	annotations := map[string]string{}
+	for k, v := range app.Annotations {
+		if k != common.AnnotationApplicationSetRenderedHash && k != common.AnnotationApplicationSetParamsHash {
+			annotations[k] = v
+		}
+	}
+	data, err := json.Marshal(struct {
+		Spec        argov1alpha1.ApplicationSpec `json:"spec"`
+		Labels      map[string]string            `json:"labels,omitempty"`
+		Annotations map[string]string            `json:"annotations,omitempty"`
+		Finalizers  []string                     `json:"finalizers,omitempty"`
+	}{
+		Spec:        app.Spec,
+		Labels:      app.Labels,
+		Annotations: annotations,
+		Finalizers:  app.Finalizers,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the rendered Application %s: %v", app.Name, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ParamsHash returns the hexadecimal SHA-256 of the param set an Application is generated from, so that the changes of
+// the params are told apart from the changes of the template without recording the params themselves.
+func ParamsHash(params map[string]interface{}) (string, error) {
+	return jsonHash(params)
+}
+
+// ParamSetsHash returns the hexadecimal SHA-256 of the param sets generated by the generators of an ApplicationSet, in
+// order, which changes whenever one of them changes.
+func ParamSetsHash(paramSets []map[string]interface{}) (string, error) {
+	return jsonHash(paramSets)
+}
+
+// ParamSetsHasher computes the ParamSetsHash of param sets added one by one, without collecting them into a slice.
+type ParamSetsHasher struct {
+	hash  hash.Hash
+	count int
+}
+
+// NewParamSetsHasher returns a hasher of the param sets.
+func NewParamSetsHasher() *ParamSetsHasher {
+	return &ParamSetsHasher{hash: sha256.New()}
+}
+
+// Add adds the param set to the hashed param sets.
+func (h *ParamSetsHasher) Add(params map[string]interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to hash the params: %v", err)
+	}
+	// The param sets are hashed as their JSON array, so that the hash is the ParamSetsHash of the param sets
+	separator := ","
+	if h.count == 0 {
+		separator = "["
+	}
+	_, _ = h.hash.Write([]byte(separator))
+	_, _ = h.hash.Write(data)
+	h.count++
+	return nil
+}
+
+// Sum returns the ParamSetsHash of the added param sets. No param set may be added afterwards.
+func (h *ParamSetsHasher) Sum() string {
+	if h.count == 0 {
+		// The JSON of no param sets is null, as in ParamSetsHash
+		sum := sha256.Sum256([]byte("null"))
+		return hex.EncodeToString(sum[:])
+	}
+	_, _ = h.hash.Write([]byte("]"))
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+func jsonHash(v interface{}) (string, error) {
+	// The keys of the maps are sorted by the JSON encoding, so that the hash is stable
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the params: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
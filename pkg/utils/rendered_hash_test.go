@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/argoproj-labs/applicationset/common"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderedHash(t *testing.T) {
+	newApp := func() argov1alpha1.Application {
+		return argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Labels:      map[string]string{"env": "prod"},
+				Annotations: map[string]string{"team": "backend"},
+				Finalizers:  []string{argov1alpha1.ResourcesFinalizerName},
+			},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "default",
+				Source:  argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+			},
+		}
+	}
+	app := newApp()
+	hash, err := RenderedHash(&app)
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	cases := []struct {
+		name          string
+		mutate        func(app *argov1alpha1.Application)
+		expectChanged bool
+	}{
+		{
+			name:   "same Application",
+			mutate: func(app *argov1alpha1.Application) {},
+		},
+		{
+			name: "rendered hash annotation",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Annotations[common.AnnotationApplicationSetRenderedHash] = hash
+			},
+		},
+		{
+			name: "params hash annotation",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Annotations[common.AnnotationApplicationSetParamsHash] = hash
+			},
+		},
+		{
+			name: "fields which are not applied",
+			mutate: func(app *argov1alpha1.Application) {
+				app.ResourceVersion = "42"
+				app.Status.Sync.Status = argov1alpha1.SyncStatusCodeSynced
+			},
+		},
+		{
+			name: "spec",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Spec.Source.Path = "helm-guestbook"
+			},
+			expectChanged: true,
+		},
+		{
+			name: "labels",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Labels["env"] = "staging"
+			},
+			expectChanged: true,
+		},
+		{
+			name: "annotations",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Annotations["team"] = "frontend"
+			},
+			expectChanged: true,
+		},
+		{
+			name: "finalizers",
+			mutate: func(app *argov1alpha1.Application) {
+				app.Finalizers = nil
+			},
+			expectChanged: true,
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			app := newApp()
+			cc.mutate(&app)
+			got, err := RenderedHash(&app)
+			assert.NoError(t, err)
+			if cc.expectChanged {
+				assert.NotEqual(t, hash, got)
+			} else {
+				assert.Equal(t, hash, got)
+			}
+		})
+	}
+}
+
+func TestParamsHash(t *testing.T) {
+	hash, err := ParamsHash(map[string]interface{}{"cluster": "prod", "url": "https://prod.example.com", "values": map[string]interface{}{"replicas": "3", "tier": "backend"}})
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	same, err := ParamsHash(map[string]interface{}{"values": map[string]interface{}{"tier": "backend", "replicas": "3"}, "url": "https://prod.example.com", "cluster": "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	changed, err := ParamsHash(map[string]interface{}{"cluster": "prod", "url": "https://prod.example.com", "values": map[string]interface{}{"replicas": "5", "tier": "backend"}})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	_, err = ParamsHash(map[string]interface{}{"invalid": func() {}})
+	assert.Error(t, err)
+}
+
+func TestParamSetsHash(t *testing.T) {
+	paramSets := []map[string]interface{}{{"cluster": "staging"}, {"cluster": "prod"}}
+	hash, err := ParamSetsHash(paramSets)
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	reordered, err := ParamSetsHash([]map[string]interface{}{{"cluster": "prod"}, {"cluster": "staging"}})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, reordered)
+
+	empty, err := ParamSetsHash(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, empty)
+}
+
+func TestParamSetsHasher(t *testing.T) {
+	for _, paramSets := range [][]map[string]interface{}{
+		nil,
+		{{"cluster": "staging"}},
+		{{"cluster": "staging", "url": "<https://staging>"}, {"cluster": "prod", "values": map[string]interface{}{"replicas": 3}}},
+	} {
+		expected, err := ParamSetsHash(paramSets)
+		assert.NoError(t, err)
+
+		hasher := NewParamSetsHasher()
+		for _, params := range paramSets {
+			assert.NoError(t, hasher.Add(params))
+		}
+		assert.Equal(t, expected, hasher.Sum())
+	}
+
+	assert.Error(t, NewParamSetsHasher().Add(map[string]interface{}{"invalid": func() {}}))
+}
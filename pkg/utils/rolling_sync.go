@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ApplyRollingSync returns copies of the desired Applications, with the automated sync disabled for the Applications
+// of the steps which are not rolled out yet, and the current step of the rollout: the first step whose Applications are
+// not synced and Healthy in the required proportion, according to the current Applications. The Applications which
+// match no step belong to an additional last step.
+func ApplyRollingSync(rollingSync *argoprojiov1alpha1.ApplicationSetRolloutStrategy, desiredApplications []argov1alpha1.Application, currentApplications []argov1alpha1.Application) ([]argov1alpha1.Application, int64, error) {
+	var steps []argoprojiov1alpha1.ApplicationSetRolloutStep
+	if rollingSync != nil {
+		steps = rollingSync.Steps
+	}
+
+	appSteps, err := applicationSteps(steps, desiredApplications)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	current := make(map[string]argov1alpha1.Application, len(currentApplications))
+	for _, app := range currentApplications {
+		current[app.Name] = app
+	}
+
+	currentStep := int64(0)
+	for ; currentStep < int64(len(steps)); currentStep++ {
+		total, rolledOut := 0, 0
+		for i, app := range desiredApplications {
+			if appSteps[i] != currentStep {
+				continue
+			}
+			total++
+			if currentApp, ok := current[app.Name]; ok && isRolledOut(app, currentApp) {
+				rolledOut++
+			}
+		}
+
+		healthyPercentage := int64(100)
+		if steps[currentStep].HealthyPercentage != nil {
+			healthyPercentage = *steps[currentStep].HealthyPercentage
+		}
+		if int64(rolledOut)*100 < int64(total)*healthyPercentage {
+			break
+		}
+	}
+
+	res := make([]argov1alpha1.Application, len(desiredApplications))
+	for i, app := range desiredApplications {
+		res[i] = app
+		if appSteps[i] > currentStep && app.Spec.SyncPolicy != nil && app.Spec.SyncPolicy.Automated != nil {
+			syncPolicy := *app.Spec.SyncPolicy
+			syncPolicy.Automated = nil
+			res[i].Spec.SyncPolicy = &syncPolicy
+		}
+	}
+	return res, currentStep, nil
+}
+
+// applicationSteps returns the index of the step of each Application: the first step whose match expressions select
+// the labels of the Application, or the number of steps if there is none.
+func applicationSteps(steps []argoprojiov1alpha1.ApplicationSetRolloutStep, applications []argov1alpha1.Application) ([]int64, error) {
+	selectors := make([]labels.Selector, len(steps))
+	for i, step := range steps {
+		selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: step.MatchExpressions})
+		if err != nil {
+			return nil, fmt.Errorf("invalid match expressions of rolling sync step %d: %v", i, err)
+		}
+		selectors[i] = selector
+	}
+
+	res := make([]int64, len(applications))
+	for i, app := range applications {
+		res[i] = int64(len(steps))
+		for j, selector := range selectors {
+			if selector.Matches(labels.Set(app.Labels)) {
+				res[i] = int64(j)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// isRolledOut returns true if the current Application has the spec of the desired Application, and is synced and
+// Healthy.
+func isRolledOut(desired argov1alpha1.Application, current argov1alpha1.Application) bool {
+	if current.Status.Sync.Status != argov1alpha1.SyncStatusCodeSynced || current.Status.Health.Status != health.HealthStatusHealthy {
+		return false
+	}
+	return reflect.DeepEqual(comparableSpec(desired.Spec), comparableSpec(current.Spec))
+}
+
+// comparableSpec returns a copy of the spec, without the unexported fields of the destination, which are not part of
+// the Application in the cluster.
+func comparableSpec(spec argov1alpha1.ApplicationSpec) argov1alpha1.ApplicationSpec {
+	spec.Destination = argov1alpha1.ApplicationDestination{
+		Server:    spec.Destination.Server,
+		Namespace: spec.Destination.Namespace,
+		Name:      spec.Destination.Name,
+	}
+	return spec
+}
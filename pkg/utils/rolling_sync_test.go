@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"testing"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestApplyRollingSync(t *testing.T) {
+	automated := &argov1alpha1.SyncPolicy{Automated: &argov1alpha1.SyncPolicyAutomated{Prune: true}}
+	newApp := func(name string, env string, revision string) argov1alpha1.Application {
+		return argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}},
+			Spec: argov1alpha1.ApplicationSpec{
+				Source:     argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", TargetRevision: revision},
+				SyncPolicy: automated,
+			},
+		}
+	}
+	rolledOut := func(app argov1alpha1.Application) argov1alpha1.Application {
+		app.Status.Sync.Status = argov1alpha1.SyncStatusCodeSynced
+		app.Status.Health.Status = health.HealthStatusHealthy
+		return app
+	}
+	progressing := func(app argov1alpha1.Application) argov1alpha1.Application {
+		app.Status.Sync.Status = argov1alpha1.SyncStatusCodeSynced
+		app.Status.Health.Status = health.HealthStatusProgressing
+		return app
+	}
+
+	steps := &argoprojiov1alpha1.ApplicationSetRolloutStrategy{
+		Steps: []argoprojiov1alpha1.ApplicationSetRolloutStep{
+			{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev"}}}},
+			{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"staging"}}}},
+		},
+	}
+	desired := []argov1alpha1.Application{
+		newApp("dev-1", "dev", "v2"),
+		newApp("dev-2", "dev", "v2"),
+		newApp("staging", "staging", "v2"),
+		newApp("prod", "prod", "v2"),
+	}
+
+	cases := []struct {
+		name              string
+		rollingSync       *argoprojiov1alpha1.ApplicationSetRolloutStrategy
+		current           []argov1alpha1.Application
+		expectedStep      int64
+		expectedAutomated []bool
+		expectedError     string
+	}{
+		{
+			name:              "no Applications rolled out",
+			rollingSync:       steps,
+			expectedStep:      0,
+			expectedAutomated: []bool{true, true, false, false},
+		},
+		{
+			name:        "first step partially rolled out",
+			rollingSync: steps,
+			current: []argov1alpha1.Application{
+				rolledOut(newApp("dev-1", "dev", "v2")),
+				progressing(newApp("dev-2", "dev", "v2")),
+			},
+			expectedStep:      0,
+			expectedAutomated: []bool{true, true, false, false},
+		},
+		{
+			name:        "Applications with an outdated spec are not rolled out",
+			rollingSync: steps,
+			current: []argov1alpha1.Application{
+				rolledOut(newApp("dev-1", "dev", "v2")),
+				rolledOut(newApp("dev-2", "dev", "v1")),
+			},
+			expectedStep:      0,
+			expectedAutomated: []bool{true, true, false, false},
+		},
+		{
+			name: "healthy percentage of the first step reached",
+			rollingSync: &argoprojiov1alpha1.ApplicationSetRolloutStrategy{
+				Steps: []argoprojiov1alpha1.ApplicationSetRolloutStep{
+					{MatchExpressions: steps.Steps[0].MatchExpressions, HealthyPercentage: pointer.Int64Ptr(50)},
+					steps.Steps[1],
+				},
+			},
+			current: []argov1alpha1.Application{
+				rolledOut(newApp("dev-1", "dev", "v2")),
+				progressing(newApp("dev-2", "dev", "v2")),
+			},
+			expectedStep:      1,
+			expectedAutomated: []bool{true, true, true, false},
+		},
+		{
+			name:        "all the steps rolled out",
+			rollingSync: steps,
+			current: []argov1alpha1.Application{
+				rolledOut(newApp("dev-1", "dev", "v2")),
+				rolledOut(newApp("dev-2", "dev", "v2")),
+				rolledOut(newApp("staging", "staging", "v2")),
+			},
+			expectedStep:      2,
+			expectedAutomated: []bool{true, true, true, true},
+		},
+		{
+			name:              "no steps",
+			expectedStep:      0,
+			expectedAutomated: []bool{true, true, true, true},
+		},
+		{
+			name: "invalid match expressions",
+			rollingSync: &argoprojiov1alpha1.ApplicationSetRolloutStrategy{
+				Steps: []argoprojiov1alpha1.ApplicationSetRolloutStep{
+					{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Equals", Values: []string{"dev"}}}},
+				},
+			},
+			expectedError: `invalid match expressions of rolling sync step 0: "Equals" is not a valid`,
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, step, err := ApplyRollingSync(cc.rollingSync, desired, cc.current)
+			if cc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expectedStep, step)
+
+			var gotAutomated []bool
+			for i, app := range got {
+				assert.Equal(t, desired[i].Name, app.Name)
+				assert.Equal(t, desired[i].Spec.Source, app.Spec.Source)
+				gotAutomated = append(gotAutomated, app.Spec.SyncPolicy.Automated != nil)
+			}
+			assert.Equal(t, cc.expectedAutomated, gotAutomated)
+
+			// The desired Applications are not modified
+			assert.Equal(t, automated, desired[3].Spec.SyncPolicy)
+		})
+	}
+}
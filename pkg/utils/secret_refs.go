@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+// GetSecretRef returns the value of the key of a Secret in the namespace of the ApplicationSet, or an empty string if
+// the reference is nil. The Secret is read at each generation, through the client of the controller manager, which
+// serves it from the cache of the watched Secrets, so that the changes of the Secret are used by the next generation.
+func GetSecretRef(ctx context.Context, c client.Client, ref *argoprojiov1alpha1.SecretRef, namespace string) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	err := c.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      ref.SecretName,
+			Namespace: namespace,
+		},
+		secret)
+	if err != nil {
+		return "", fmt.Errorf("error fetching secret %s/%s: %v", namespace, ref.SecretName, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q in secret %s/%s not found", ref.Key, namespace, ref.SecretName)
+	}
+	return string(value), nil
+}
+
+var secretRefType = reflect.TypeOf(argoprojiov1alpha1.SecretRef{})
+
+// ReferencedSecrets returns the sorted names of the Secrets referenced by the generators of the ApplicationSet,
+// including the generators nested in Matrix, Merge and Union generators.
+func ReferencedSecrets(appSet *argoprojiov1alpha1.ApplicationSet) []string {
+	names := map[string]bool{}
+	collectSecretRefs(reflect.ValueOf(appSet.Spec.Generators), names)
+
+	res := make([]string, 0, len(names))
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func collectSecretRefs(v reflect.Value, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectSecretRefs(v.Elem(), names)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectSecretRefs(v.Index(i), names)
+		}
+	case reflect.Struct:
+		if v.Type() == secretRefType {
+			if name := v.Interface().(argoprojiov1alpha1.SecretRef).SecretName; name != "" {
+				names[name] = true
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				collectSecretRefs(v.Field(i), names)
+			}
+		}
+	}
+}
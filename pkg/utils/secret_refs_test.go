@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestGetSecretRef(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "test"},
+		Data: map[string][]byte{
+			"my-token": []byte("secret"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	ctx := context.Background()
+
+	token, err := GetSecretRef(ctx, c, &argoprojiov1alpha1.SecretRef{SecretName: "test-secret", Key: "my-token"}, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", token)
+
+	token, err = GetSecretRef(ctx, c, nil, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "", token)
+
+	_, err = GetSecretRef(ctx, c, &argoprojiov1alpha1.SecretRef{SecretName: "test-secret", Key: "other-token"}, "test")
+	assert.EqualError(t, err, `key "other-token" in secret test/test-secret not found`)
+
+	_, err = GetSecretRef(ctx, c, &argoprojiov1alpha1.SecretRef{SecretName: "test-secret", Key: "my-token"}, "other")
+	assert.Error(t, err)
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{
+		Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+			{SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{Github: &argoprojiov1alpha1.SCMProviderGeneratorGithub{
+				TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: "github-token", Key: "token"},
+			}}},
+			{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{PullRequest: &argoprojiov1alpha1.PullRequestGenerator{Github: &argoprojiov1alpha1.PullRequestGeneratorGithub{
+					GithubApp: &argoprojiov1alpha1.GithubAppRef{PrivateKeyRef: argoprojiov1alpha1.SecretRef{SecretName: "github-app", Key: "privateKey"}},
+				}}},
+				{Merge: &argoprojiov1alpha1.NestedMergeGenerator{Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
+					{SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{Gitlab: &argoprojiov1alpha1.SCMProviderGeneratorGitlab{
+						TokenRef: &argoprojiov1alpha1.SecretRef{SecretName: "github-token", Key: "gitlab"},
+					}}},
+				}}},
+			}}},
+			{List: &argoprojiov1alpha1.ListGenerator{}},
+		},
+	}}
+
+	assert.Equal(t, []string{"github-app", "github-token"}, ReferencedSecrets(appSet))
+	assert.Empty(t, ReferencedSecrets(&argoprojiov1alpha1.ApplicationSet{}))
+}
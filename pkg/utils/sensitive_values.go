@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces the sensitive values in logs, events and the ApplicationSet status.
+const RedactedValue = "++++++++"
+
+// SensitiveValues records the parameter values which must not appear in logs, events or the status of
+// ApplicationSets, such as the values read from Secrets by the Secret generator.
+//
+// SensitiveValues implements logrus.Hook: once added to the logger, every log entry is redacted.
+type SensitiveValues struct {
+	lock sync.RWMutex
+	// values are the sensitive values, by ApplicationSet namespace/name
+	values map[string]map[string]bool
+}
+
+var _ log.Hook = (*SensitiveValues)(nil)
+
+func NewSensitiveValues() *SensitiveValues {
+	return &SensitiveValues{
+		values: map[string]map[string]bool{},
+	}
+}
+
+// Add records sensitive values of the given ApplicationSet.
+func (s *SensitiveValues) Add(appSetNamespace, appSetName string, values ...string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := appSetNamespace + "/" + appSetName
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if s.values[key] == nil {
+			s.values[key] = map[string]bool{}
+		}
+		s.values[key][value] = true
+	}
+}
+
+// AddParam records the sensitive value of a parameter of the given ApplicationSet: for lists and objects, each of the
+// values they contain is recorded.
+func (s *SensitiveValues) AddParam(appSetNamespace, appSetName string, value interface{}) {
+	if s == nil {
+		return
+	}
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s.Add(appSetNamespace, appSetName, v)
+	case map[string]interface{}:
+		for _, element := range v {
+			s.AddParam(appSetNamespace, appSetName, element)
+		}
+	case []interface{}:
+		for _, element := range v {
+			s.AddParam(appSetNamespace, appSetName, element)
+		}
+	default:
+		s.Add(appSetNamespace, appSetName, fmt.Sprintf("%v", v))
+	}
+}
+
+// Values returns the sensitive values of the given ApplicationSet, sorted.
+func (s *SensitiveValues) Values(appSetNamespace, appSetName string) []string {
+	if s == nil {
+		return nil
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var res []string
+	for value := range s.values[appSetNamespace+"/"+appSetName] {
+		res = append(res, value)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Reset forgets the sensitive values of the given ApplicationSet, before its parameters are generated again.
+func (s *SensitiveValues) Reset(appSetNamespace, appSetName string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.values, appSetNamespace+"/"+appSetName)
+}
+
+// Redact replaces the sensitive values of all ApplicationSets within the text.
+func (s *SensitiveValues) Redact(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+	s.lock.RLock()
+	values := []string{}
+	for _, appSetValues := range s.values {
+		for value := range appSetValues {
+			values = append(values, value)
+		}
+	}
+	s.lock.RUnlock()
+
+	// Replace the longest values first, so that a value containing another one is fully redacted.
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, RedactedValue)
+	}
+	return text
+}
+
+func (s *SensitiveValues) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire redacts the message and the fields of the log entry.
+func (s *SensitiveValues) Fire(entry *log.Entry) error {
+	entry.Message = s.Redact(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = s.Redact(v)
+		case error:
+			if redacted := s.Redact(v.Error()); redacted != v.Error() {
+				entry.Data[key] = errors.New(redacted)
+			}
+		default:
+			formatted := fmt.Sprintf("%+v", v)
+			if redacted := s.Redact(formatted); redacted != formatted {
+				entry.Data[key] = redacted
+			}
+		}
+	}
+	return nil
+}
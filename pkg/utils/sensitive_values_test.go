@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensitiveValuesRedact(t *testing.T) {
+	sensitiveValues := NewSensitiveValues()
+	sensitiveValues.Add("argocd", "tenant-a", "s3cr3t", "", "s3cr3t-longer")
+	sensitiveValues.Add("argocd", "tenant-b", "p4ssw0rd")
+
+	assert.Equal(t, "password is ++++++++ and ++++++++", sensitiveValues.Redact("password is s3cr3t-longer and p4ssw0rd"))
+	assert.Equal(t, "nothing to redact", sensitiveValues.Redact("nothing to redact"))
+
+	assert.Equal(t, []string{"s3cr3t", "s3cr3t-longer"}, sensitiveValues.Values("argocd", "tenant-a"))
+
+	sensitiveValues.Reset("argocd", "tenant-b")
+	assert.Equal(t, "p4ssw0rd ++++++++", sensitiveValues.Redact("p4ssw0rd s3cr3t"))
+	assert.Empty(t, sensitiveValues.Values("argocd", "tenant-b"))
+
+	var nilValues *SensitiveValues
+	assert.Equal(t, "s3cr3t", nilValues.Redact("s3cr3t"))
+	nilValues.Add("argocd", "tenant-a", "s3cr3t")
+	nilValues.AddParam("argocd", "tenant-a", "s3cr3t")
+	assert.Empty(t, nilValues.Values("argocd", "tenant-a"))
+}
+
+func TestSensitiveValuesAddParam(t *testing.T) {
+	sensitiveValues := NewSensitiveValues()
+	sensitiveValues.AddParam("argocd", "tenant-a", "s3cr3t")
+	sensitiveValues.AddParam("argocd", "tenant-a", map[string]interface{}{
+		"users": []interface{}{"p4ssw0rd", map[string]interface{}{"pin": float64(1234)}},
+		"empty": nil,
+	})
+
+	assert.Equal(t, "++++++++ ++++++++ ++++++++", sensitiveValues.Redact("s3cr3t p4ssw0rd 1234"))
+}
+
+func TestSensitiveValuesLogHook(t *testing.T) {
+	sensitiveValues := NewSensitiveValues()
+	sensitiveValues.Add("argocd", "tenant-a", "s3cr3t")
+
+	buf := &bytes.Buffer{}
+	logger := log.New()
+	logger.SetOutput(buf)
+	logger.AddHook(sensitiveValues)
+
+	logger.WithError(errors.New("invalid value s3cr3t")).
+		WithField("params", []map[string]interface{}{{"password": "s3cr3t"}}).
+		WithField("name", "s3cr3t").
+		Errorf("unable to render s3cr3t")
+
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	assert.Contains(t, buf.String(), "unable to render ++++++++")
+	assert.Contains(t, buf.String(), "invalid value ++++++++")
+	assert.Contains(t, buf.String(), "password:++++++++")
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplatePartials provides the named templates which the Go templates of ApplicationSets can include, e.g.
+// {{ template "common-sync-policy" . }}.
+type TemplatePartials interface {
+	// GetTemplatePartials returns the templates by name.
+	GetTemplatePartials() (map[string]string, error)
+}
+
+// ConfigMapTemplatePartials reads the template partials from a ConfigMap: each key of the ConfigMap is the name of a
+// template.
+type ConfigMapTemplatePartials struct {
+	ctx       context.Context
+	client    client.Client
+	namespace string
+	name      string
+}
+
+var _ TemplatePartials = (*ConfigMapTemplatePartials)(nil)
+
+func NewConfigMapTemplatePartials(ctx context.Context, c client.Client, namespace string, name string) *ConfigMapTemplatePartials {
+	return &ConfigMapTemplatePartials{
+		ctx:       ctx,
+		client:    c,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// GetTemplatePartials reads the ConfigMap each time, from the cache of the client, so that changes to the partials
+// are taken into account on the next reconciliation of the ApplicationSets.
+func (p *ConfigMapTemplatePartials) GetTemplatePartials() (map[string]string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := p.client.Get(p.ctx, client.ObjectKey{Name: p.name, Namespace: p.namespace}, configMap)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching template partials ConfigMap %s/%s: %v", p.namespace, p.name, err)
+	}
+	return configMap.Data, nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapTemplatePartials(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := corev1.AddToScheme(scheme)
+	assert.NoError(t, err)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "template-partials", Namespace: "argocd"},
+		Data: map[string]string{
+			"common-labels": "team: {{ .team }}",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	partials, err := NewConfigMapTemplatePartials(context.Background(), fakeClient, "argocd", "template-partials").GetTemplatePartials()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"common-labels": "team: {{ .team }}"}, partials)
+
+	_, err = NewConfigMapTemplatePartials(context.Background(), fakeClient, "argocd", "missing").GetTemplatePartials()
+	assert.EqualError(t, err, `error fetching template partials ConfigMap argocd/missing: configmaps "missing" not found`)
+}
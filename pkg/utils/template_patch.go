@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/valyala/fasttemplate"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// RenderTemplatePatch substitutes the params in the template patch, a YAML or JSON document, and applies the result
+// as a strategic merge patch to the Application.
+func (r *Render) RenderTemplatePatch(app *argov1alpha1.Application, templatePatch string, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error) {
+	if app == nil {
+		return nil, fmt.Errorf("application is empty")
+	}
+
+	var renderedPatch string
+	if useGoTemplate {
+		base, err := r.newGoTemplate(goTemplateOptions)
+		if err != nil {
+			return nil, err
+		}
+		renderedPatch, err = renderGoTemplateString(base, templatePatch, goTemplateData(params))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fstTmpl := fasttemplate.New(templatePatch, "{{", "}}")
+		var err error
+		renderedPatch, err = r.replace(fstTmpl, params, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	patchBytes, err := yaml.YAMLToJSON([]byte(renderedPatch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template patch: %v", err)
+	}
+
+	appBytes, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedBytes, err := strategicpatch.StrategicMergePatch(appBytes, patchBytes, argov1alpha1.Application{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply template patch: %v", err)
+	}
+
+	var patchedApp argov1alpha1.Application
+	if err := json.Unmarshal(patchedBytes, &patchedApp); err != nil {
+		return nil, fmt.Errorf("failed to apply template patch: %v", err)
+	}
+	return &patchedApp, nil
+}
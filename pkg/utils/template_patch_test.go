@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderTemplatePatch(t *testing.T) {
+	params := map[string]interface{}{
+		"name":     "guestbook",
+		"autoSync": "true",
+		"kinds":    `["Deployment","StatefulSet"]`,
+	}
+
+	newApplication := func() *argov1alpha1.Application {
+		return &argov1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "guestbook",
+				Annotations: map[string]string{"existing": "annotation"},
+				Finalizers:  []string{"resources-finalizer.argocd.argoproj.io"},
+			},
+			Spec: argov1alpha1.ApplicationSpec{
+				Project: "default",
+				Source:  argov1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+				SyncPolicy: &argov1alpha1.SyncPolicy{
+					SyncOptions: []string{"CreateNamespace=true"},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name          string
+		templatePatch string
+		useGoTemplate bool
+		expected      func(app *argov1alpha1.Application)
+		expectedError string
+	}{
+		{
+			name: "patch fields which are not part of the template",
+			templatePatch: `
+spec:
+  ignoreDifferences:
+  - kind: Deployment
+    name: '{{name}}'
+    jsonPointers:
+    - /spec/replicas
+`,
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.IgnoreDifferences = []argov1alpha1.ResourceIgnoreDifferences{
+					{Kind: "Deployment", Name: "guestbook", JSONPointers: []string{"/spec/replicas"}},
+				}
+			},
+		},
+		{
+			name: "maps are merged and lists are replaced",
+			templatePatch: `
+metadata:
+  annotations:
+    example.com/app: '{{name}}'
+spec:
+  syncPolicy:
+    syncOptions:
+    - PruneLast=true
+`,
+			expected: func(app *argov1alpha1.Application) {
+				app.Annotations["example.com/app"] = "guestbook"
+				app.Spec.SyncPolicy.SyncOptions = []string{"PruneLast=true"}
+			},
+		},
+		{
+			name:          "JSON patch",
+			templatePatch: `{"spec": {"project": "{{name}}"}}`,
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.Project = "guestbook"
+			},
+		},
+		{
+			name: "Go template with a conditional and a loop",
+			templatePatch: `
+spec:
+{{- if eq .autoSync "true" }}
+  syncPolicy:
+    automated:
+      prune: true
+{{- end }}
+  ignoreDifferences:
+{{- range fromJson .kinds }}
+  - kind: {{ . }}
+    jsonPointers:
+    - /spec/replicas
+{{- end }}
+`,
+			useGoTemplate: true,
+			expected: func(app *argov1alpha1.Application) {
+				app.Spec.SyncPolicy.Automated = &argov1alpha1.SyncPolicyAutomated{Prune: true}
+				app.Spec.IgnoreDifferences = []argov1alpha1.ResourceIgnoreDifferences{
+					{Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+					{Kind: "StatefulSet", JSONPointers: []string{"/spec/replicas"}},
+				}
+			},
+		},
+		{
+			name:          "invalid YAML",
+			templatePatch: "spec: [",
+			expectedError: "failed to parse template patch: ",
+		},
+		{
+			name:          "invalid Go template",
+			templatePatch: "spec: {{ .name ",
+			useGoTemplate: true,
+			expectedError: "failed to parse template spec: {{ .name : ",
+		},
+		{
+			name:          "patch is not an object",
+			templatePatch: "- spec",
+			expectedError: "failed to apply template patch: ",
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			render := Render{}
+			app, err := render.RenderTemplatePatch(newApplication(), cc.templatePatch, params, cc.useGoTemplate, nil)
+			if cc.expectedError != "" {
+				// The messages of the YAML and patch libraries are not part of the test
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			expected := newApplication()
+			cc.expected(expected)
+			assert.Equal(t, expected, app)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderTemplateSelector renders the template selector of an ApplicationSet, a Go template, with the params, and
+// returns the name of the selected template, without leading and trailing spaces.
+func RenderTemplateSelector(selector string, params map[string]interface{}, goTemplateOptions []string) (string, error) {
+	base, err := newGoTemplate(nil, goTemplateOptions)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := renderGoTemplateString(base, selector, goTemplateData(params))
+	if err != nil {
+		return "", fmt.Errorf("failed to render template selector: %v", err)
+	}
+	return strings.TrimSpace(name), nil
+}
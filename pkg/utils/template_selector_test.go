@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplateSelector(t *testing.T) {
+	params := map[string]interface{}{"type": "frontend", "values.tier": "web"}
+
+	cases := []struct {
+		name          string
+		selector      string
+		options       []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "param value",
+			selector: "{{ .type }}",
+			expected: "frontend",
+		},
+		{
+			name:     "condition with spaces",
+			selector: `{{ if eq .values.tier "web" }} web-tier {{ end }}`,
+			expected: "web-tier",
+		},
+		{
+			name:     "empty result",
+			selector: `{{ if eq .type "backend" }}backend{{ end }}`,
+			expected: "",
+		},
+		{
+			name:     "constant",
+			selector: "frontend",
+			expected: "frontend",
+		},
+		{
+			name:          "missing param with missingkey=error",
+			selector:      "{{ .kind }}",
+			options:       []string{"missingkey=error"},
+			expectedError: `failed to render template selector: failed to execute template {{ .kind }}: template: :1:3: executing "" at <.kind>: map has no entry for key "kind"`,
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			got, err := RenderTemplateSelector(cc.selector, params, cc.options)
+			if cc.expectedError != "" {
+				assert.EqualError(t, err, cc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrackingMethod is how the ApplicationSet controller tracks the Applications generated by an ApplicationSet.
+type TrackingMethod string
+
+const (
+	// TrackingMethodOwnerReference sets the ApplicationSet as the controller owner of its Applications, so that they
+	// are garbage collected with it.
+	TrackingMethodOwnerReference TrackingMethod = "owner-reference"
+	// TrackingMethodAnnotation sets the tracking ID annotation on the Applications, which are not deleted with the
+	// ApplicationSet.
+	TrackingMethodAnnotation TrackingMethod = "annotation"
+)
+
+// TrackingMethods is a registry of available tracking methods.
+var TrackingMethods = map[string]TrackingMethod{
+	string(TrackingMethodOwnerReference): TrackingMethodOwnerReference,
+	string(TrackingMethodAnnotation):     TrackingMethodAnnotation,
+}
+
+// TrackingID returns the value of the tracking ID annotation of the Applications tracked by the ApplicationSet.
+func TrackingID(applicationSet *v1alpha1.ApplicationSet) string {
+	return applicationSet.Namespace + "/" + applicationSet.Name
+}
+
+// ParseTrackingID returns the namespace and the name of the ApplicationSet of a tracking ID.
+func ParseTrackingID(trackingID string) (string, string, bool) {
+	parts := strings.Split(trackingID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// GetTrackingApplicationSet returns the namespace and the name of the ApplicationSet tracking the Application, either
+// as its controller owner or with the tracking ID annotation, or false if the Application isn't tracked by any
+// ApplicationSet.
+func GetTrackingApplicationSet(app *argov1alpha1.Application) (string, string, bool) {
+	if owner := metav1.GetControllerOf(app); owner != nil {
+		if owner.APIVersion == v1alpha1.GroupVersion.String() && owner.Kind == "ApplicationSet" {
+			return app.Namespace, owner.Name, true
+		}
+	}
+	if trackingID, ok := app.Annotations[common.AnnotationApplicationSetTrackingID]; ok {
+		return ParseTrackingID(trackingID)
+	}
+	return "", "", false
+}
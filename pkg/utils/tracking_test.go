@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/common"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetTrackingApplicationSet(t *testing.T) {
+	appSet := argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "appset", Namespace: "argocd"},
+	}
+
+	cases := []struct {
+		name              string
+		ownerReferences   []metav1.OwnerReference
+		annotations       map[string]string
+		expectedNamespace string
+		expectedName      string
+		expectedTracked   bool
+	}{
+		{
+			name: "not tracked",
+		},
+		{
+			name: "owned by an ApplicationSet",
+			ownerReferences: []metav1.OwnerReference{
+				{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet", Name: "appset", Controller: pointer.BoolPtr(true)},
+			},
+			expectedNamespace: "argocd",
+			expectedName:      "appset",
+			expectedTracked:   true,
+		},
+		{
+			name: "owned by another kind of controller",
+			ownerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "appset", Controller: pointer.BoolPtr(true)},
+			},
+		},
+		{
+			name:              "tracked with the tracking ID annotation",
+			annotations:       map[string]string{common.AnnotationApplicationSetTrackingID: TrackingID(&appSet)},
+			expectedNamespace: "argocd",
+			expectedName:      "appset",
+			expectedTracked:   true,
+		},
+		{
+			name:        "invalid tracking ID annotation",
+			annotations: map[string]string{common.AnnotationApplicationSetTrackingID: "appset"},
+		},
+	}
+
+	for _, c := range cases {
+		cc := c
+		t.Run(cc.name, func(t *testing.T) {
+			app := argov1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "app",
+					Namespace:       "argocd",
+					OwnerReferences: cc.ownerReferences,
+					Annotations:     cc.annotations,
+				},
+			}
+			namespace, name, tracked := GetTrackingApplicationSet(&app)
+			assert.Equal(t, cc.expectedNamespace, namespace)
+			assert.Equal(t, cc.expectedName, name)
+			assert.Equal(t, cc.expectedTracked, tracked)
+		})
+	}
+}
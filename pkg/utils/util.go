@@ -17,13 +17,19 @@ import (
 )
 
 type Renderer interface {
-	RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]string) (*argov1alpha1.Application, error)
+	// RenderTemplateParams substitutes the params in the template, with Go templates and their options if
+	// useGoTemplate is true, or with the {{param}} syntax of fasttemplate otherwise.
+	RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error)
+	// RenderTemplatePatch substitutes the params in the template patch, and applies it to the rendered Application.
+	RenderTemplatePatch(app *argov1alpha1.Application, templatePatch string, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error)
 }
 
 type Render struct {
+	// Partials are the template partials which Go templates can include, if any.
+	Partials TemplatePartials
 }
 
-func (r *Render) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]string) (*argov1alpha1.Application, error) {
+func (r *Render) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy *argoprojiov1alpha1.ApplicationSetSyncPolicy, params map[string]interface{}, useGoTemplate bool, goTemplateOptions []string) (*argov1alpha1.Application, error) {
 	if tmpl == nil {
 		return nil, fmt.Errorf("application template is empty ")
 	}
@@ -37,8 +43,13 @@ func (r *Render) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy
 		return nil, err
 	}
 
-	fstTmpl := fasttemplate.New(string(tmplBytes), "{{", "}}")
-	replacedTmplStr, err := r.replace(fstTmpl, params, true)
+	var replacedTmplStr string
+	if useGoTemplate {
+		replacedTmplStr, err = r.renderGoTemplate(tmplBytes, params, goTemplateOptions)
+	} else {
+		fstTmpl := fasttemplate.New(string(tmplBytes), "{{", "}}")
+		replacedTmplStr, err = r.replace(fstTmpl, params, true)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -66,13 +77,13 @@ func (r *Render) RenderTemplateParams(tmpl *argov1alpha1.Application, syncPolicy
 // Replace executes basic string substitution of a template with replacement values.
 // 'allowUnresolved' indicates whether or not it is acceptable to have unresolved variables
 // remaining in the substituted template.
-func (r *Render) replace(fstTmpl *fasttemplate.Template, replaceMap map[string]string, allowUnresolved bool) (string, error) {
+func (r *Render) replace(fstTmpl *fasttemplate.Template, replaceMap map[string]interface{}, allowUnresolved bool) (string, error) {
 	var unresolvedErr error
 	replacedTmpl := fstTmpl.ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
 
 		trimmedTag := strings.TrimSpace(tag)
 
-		replacement, ok := replaceMap[trimmedTag]
+		value, ok := replaceMap[trimmedTag]
 		if len(trimmedTag) == 0 || !ok {
 			if allowUnresolved {
 				// just write the same string back
@@ -83,7 +94,7 @@ func (r *Render) replace(fstTmpl *fasttemplate.Template, replaceMap map[string]s
 		}
 		// The following escapes any special characters (e.g. newlines, tabs, etc...)
 		// in preparation for substitution
-		replacement = strconv.Quote(replacement)
+		replacement := strconv.Quote(paramString(value))
 		replacement = replacement[1 : len(replacement)-1]
 		return w.Write([]byte(replacement))
 	})
@@ -94,6 +105,19 @@ func (r *Render) replace(fstTmpl *fasttemplate.Template, replaceMap map[string]s
 	return replacedTmpl, nil
 }
 
+// paramString returns the string substituted for a parameter with the {{param}} syntax: strings are kept as-is, and
+// other values, such as the lists and objects generated for Go templates, are converted to their JSON representation.
+func paramString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(valueJSON)
+}
+
 // Log a warning if there are unrecognized generators
 func CheckInvalidGenerators(applicationSetInfo *argoprojiov1alpha1.ApplicationSet) {
 	hasInvalidGenerators, invalidGenerators := invalidGenerators(applicationSetInfo)
@@ -123,7 +147,8 @@ func invalidGenerators(applicationSetInfo *argoprojiov1alpha1.ApplicationSet) (b
 		found := false
 		for i := 0; i < v.NumField(); i++ {
 			field := v.Field(i)
-			if !field.CanInterface() {
+			// Schedule is common to all the generators, and is not a generator itself
+			if !field.CanInterface() || v.Type().Field(i).Name == "Schedule" {
 				continue
 			}
 			if !reflect.ValueOf(field.Interface()).IsNil() {
@@ -173,6 +198,9 @@ func addInvalidGeneratorNames(names map[string]bool, applicationSetInfo *argopro
 	}
 
 	for key := range generator {
+		if key == "schedule" {
+			continue
+		}
 		names[key] = true
 		break
 	}
@@ -27,6 +27,17 @@ func TestRenderTemplateParams(t *testing.T) {
 
 	fieldMap["Project"] = func(app *argov1alpha1.Application) *string { return &app.Spec.Project }
 
+	fieldMap["HelmReleaseName"] = func(app *argov1alpha1.Application) *string { return &app.Spec.Source.Helm.ReleaseName }
+	fieldMap["HelmValueFiles"] = func(app *argov1alpha1.Application) *string { return &app.Spec.Source.Helm.ValueFiles[0] }
+	fieldMap["KustomizeNamePrefix"] = func(app *argov1alpha1.Application) *string { return &app.Spec.Source.Kustomize.NamePrefix }
+
+	fieldMap["SyncOptions"] = func(app *argov1alpha1.Application) *string { return &app.Spec.SyncPolicy.SyncOptions[0] }
+	fieldMap["IgnoreDifferencesName"] = func(app *argov1alpha1.Application) *string { return &app.Spec.IgnoreDifferences[0].Name }
+	fieldMap["IgnoreDifferencesJSONPointers"] = func(app *argov1alpha1.Application) *string {
+		return &app.Spec.IgnoreDifferences[0].JSONPointers[0]
+	}
+	fieldMap["InfoValue"] = func(app *argov1alpha1.Application) *string { return &app.Spec.Info[0].Value }
+
 	emptyApplication := &argov1alpha1.Application{
 		Spec: argov1alpha1.ApplicationSpec{
 			Source: argov1alpha1.ApplicationSource{
@@ -34,6 +45,13 @@ func TestRenderTemplateParams(t *testing.T) {
 				RepoURL:        "",
 				TargetRevision: "",
 				Chart:          "",
+				Helm: &argov1alpha1.ApplicationSourceHelm{
+					ReleaseName: "",
+					ValueFiles:  []string{""},
+				},
+				Kustomize: &argov1alpha1.ApplicationSourceKustomize{
+					NamePrefix: "",
+				},
 			},
 			Destination: argov1alpha1.ApplicationDestination{
 				Server:    "",
@@ -41,20 +59,32 @@ func TestRenderTemplateParams(t *testing.T) {
 				Name:      "",
 			},
 			Project: "",
+			SyncPolicy: &argov1alpha1.SyncPolicy{
+				SyncOptions: argov1alpha1.SyncOptions{""},
+			},
+			IgnoreDifferences: []argov1alpha1.ResourceIgnoreDifferences{{
+				Kind:         "Deployment",
+				Name:         "",
+				JSONPointers: []string{""},
+			}},
+			Info: []argov1alpha1.Info{{
+				Name:  "url",
+				Value: "",
+			}},
 		},
 	}
 
 	tests := []struct {
 		name        string
 		fieldVal    string
-		params      map[string]string
+		params      map[string]interface{}
 		expectedVal string
 	}{
 		{
 			name:        "simple substitution",
 			fieldVal:    "{{one}}",
 			expectedVal: "two",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one": "two",
 			},
 		},
@@ -62,7 +92,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "simple substitution with whitespace",
 			fieldVal:    "{{ one }}",
 			expectedVal: "two",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one": "two",
 			},
 		},
@@ -71,7 +101,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "template characters but not in a template",
 			fieldVal:    "}} {{",
 			expectedVal: "}} {{",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one": "two",
 			},
 		},
@@ -80,7 +110,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "nested template",
 			fieldVal:    "{{ }}",
 			expectedVal: "{{ }}",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one": "{{ }}",
 			},
 		},
@@ -88,7 +118,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "field with whitespace",
 			fieldVal:    "{{ }}",
 			expectedVal: "{{ }}",
-			params: map[string]string{
+			params: map[string]interface{}{
 				" ": "two",
 				"":  "three",
 			},
@@ -98,7 +128,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "template contains itself, containing itself",
 			fieldVal:    "{{one}}",
 			expectedVal: "{{one}}",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"{{one}}": "{{one}}",
 			},
 		},
@@ -107,7 +137,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "template contains itself, containing something else",
 			fieldVal:    "{{one}}",
 			expectedVal: "{{one}}",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"{{one}}": "{{two}}",
 			},
 		},
@@ -116,7 +146,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "templates are case sensitive",
 			fieldVal:    "{{ONE}}",
 			expectedVal: "{{ONE}}",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"{{one}}": "two",
 			},
 		},
@@ -124,7 +154,7 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "multiple on a line",
 			fieldVal:    "{{one}}{{one}}",
 			expectedVal: "twotwo",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one": "two",
 			},
 		},
@@ -132,11 +162,19 @@ func TestRenderTemplateParams(t *testing.T) {
 			name:        "multiple different on a line",
 			fieldVal:    "{{one}}{{three}}",
 			expectedVal: "twofour",
-			params: map[string]string{
+			params: map[string]interface{}{
 				"one":   "two",
 				"three": "four",
 			},
 		},
+		{
+			name:        "structured value as JSON",
+			fieldVal:    "{{one}}",
+			expectedVal: `["two",{"three":"four"}]`,
+			params: map[string]interface{}{
+				"one": []interface{}{"two", map[string]interface{}{"three": "four"}},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -153,7 +191,7 @@ func TestRenderTemplateParams(t *testing.T) {
 
 				// Render the cloned application, into a new application
 				render := Render{}
-				newApplication, err := render.RenderTemplateParams(application, nil, test.params)
+				newApplication, err := render.RenderTemplateParams(application, nil, test.params, false, nil)
 
 				// Retrieve the value of the target field from the newApplication, then verify that
 				// the target field has been templated into the expected value
@@ -254,14 +292,14 @@ func TestRenderTemplateParamsFinalizers(t *testing.T) {
 			application := emptyApplication.DeepCopy()
 			application.Finalizers = c.existingFinalizers
 
-			params := map[string]string{
+			params := map[string]interface{}{
 				"one": "two",
 			}
 
 			// Render the cloned application, into a new application
 			render := Render{}
 
-			res, err := render.RenderTemplateParams(application, c.syncPolicy, params)
+			res, err := render.RenderTemplateParams(application, c.syncPolicy, params, false, nil)
 			assert.Nil(t, err)
 
 			assert.ElementsMatch(t, res.Finalizers, c.expectedFinalizers)
@@ -536,6 +574,35 @@ func TestInvalidGenerators(t *testing.T) {
 				"bbb": true,
 			},
 		},
+		{
+			testName: "invalid generator with a schedule, with annotation",
+			appSet: argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{
+							"spec":{
+								"generators":[
+									{"schedule":{"windows":[]},"aaa":{}}
+								]
+							}
+						}`,
+					},
+				},
+				Spec: argoprojiov1alpha1.ApplicationSetSpec{
+					Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+						{
+							Schedule: &argoprojiov1alpha1.GeneratorSchedule{},
+						},
+					},
+				},
+			},
+			expectedInvalid: true,
+			expectedNames: map[string]bool{
+				"aaa": true,
+			},
+		},
 		{
 			testName: "invalid generator, annotation with missing spec",
 			appSet: argoprojiov1alpha1.ApplicationSet{
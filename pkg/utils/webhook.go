@@ -8,34 +8,61 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/argoproj-labs/applicationset/api/v1alpha1"
 	"github.com/argoproj-labs/applicationset/common"
 	argosettings "github.com/argoproj/argo-cd/v2/util/settings"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/go-playground/webhooks.v5/bitbucket"
+	bitbucketserver "gopkg.in/go-playground/webhooks.v5/bitbucket-server"
 	"gopkg.in/go-playground/webhooks.v5/github"
 	"gopkg.in/go-playground/webhooks.v5/gitlab"
 )
 
 type WebhookHandler struct {
-	namespace string
-	github    *github.Webhook
-	gitlab    *gitlab.Webhook
-	client    client.Client
+	namespace         string
+	argocdSettingsMgr *argosettings.SettingsManager
+	client            client.Client
+	// debouncer delays the refreshes of the ApplicationSets, nil if they are refreshed as soon as an event is received
+	debouncer *refreshDebouncer
 }
 
 type gitGeneratorInfo struct {
 	Revision    string
 	TouchedHead bool
-	RepoRegexp  *regexp.Regexp
+	// AllRevisions is true if the generators are refreshed whatever their revision
+	AllRevisions bool
+	RepoRegexp   *regexp.Regexp
+}
+
+type scmProviderGeneratorInfo struct {
+	Github *scmProviderGeneratorGithubInfo
+	Gitlab *scmProviderGeneratorGitlabInfo
+}
+
+type scmProviderGeneratorGithubInfo struct {
+	Organization string
+	TouchedHead  bool
+	APIRegexp    *regexp.Regexp
+}
+
+type scmProviderGeneratorGitlabInfo struct {
+	Namespace   string
+	TouchedHead bool
+	APIRegexp   *regexp.Regexp
 }
 
 type prGeneratorInfo struct {
-	Github *prGeneratorGithubInfo
+	Github      *prGeneratorGithubInfo
+	Gitlab      *prGeneratorGitlabInfo
+	Gitea       *prGeneratorGiteaInfo
+	AzureDevOps *prGeneratorAzureDevOpsInfo
 }
 
 type prGeneratorGithubInfo struct {
@@ -44,33 +71,54 @@ type prGeneratorGithubInfo struct {
 	APIRegexp *regexp.Regexp
 }
 
-func NewWebhookHandler(namespace string, argocdSettingsMgr *argosettings.SettingsManager, client client.Client) (*WebhookHandler, error) {
-	// register the webhook secrets stored under "argocd-secret" for verifying incoming payloads
-	argocdSettings, err := argocdSettingsMgr.GetSettings()
+type prGeneratorGiteaInfo struct {
+	Repo      string
+	Owner     string
+	APIRegexp *regexp.Regexp
+}
+
+type prGeneratorAzureDevOpsInfo struct {
+	// RepoURL is the normalized URL of the repository, see normalizeAzureDevOpsURL
+	RepoURL string
+}
+
+type prGeneratorGitlabInfo struct {
+	Project   string
+	ProjectID string
+	APIRegexp *regexp.Regexp
+}
+
+// NewWebhookHandler returns a handler of the webhook events, which refreshes the ApplicationSets using the repositories
+// of the events once no event was received for them for the quiet period, or right away if the quiet period is 0.
+func NewWebhookHandler(namespace string, argocdSettingsMgr *argosettings.SettingsManager, client client.Client, quietPeriod time.Duration) (*WebhookHandler, error) {
+	// the webhook secrets stored under "argocd-secret", and in the webhook Secrets, are read at each event, see
+	// getWebhookSecrets
+	_, err := argocdSettingsMgr.GetSettings()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get argocd settings: %v", err)
 	}
-	githubHandler, err := github.New(github.Options.Secret(argocdSettings.WebhookGitHubSecret))
-	if err != nil {
-		return nil, fmt.Errorf("Unable to init GitHub webhook: %v", err)
+
+	h := &WebhookHandler{
+		namespace:         namespace,
+		argocdSettingsMgr: argocdSettingsMgr,
+		client:            client,
 	}
-	gitlabHandler, err := gitlab.New(gitlab.Options.Secret(argocdSettings.WebhookGitLabSecret))
-	if err != nil {
-		return nil, fmt.Errorf("Unable to init GitLab webhook: %v", err)
+	if quietPeriod > 0 {
+		h.debouncer = newRefreshDebouncer(quietPeriod, func(key types.NamespacedName) {
+			appSet := &v1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			if err := refreshApplicationSet(client, appSet); err != nil {
+				log.Errorf("Failed to refresh ApplicationSet '%s' for controller reprocessing: %v", key.Name, err)
+			}
+		})
 	}
-
-	return &WebhookHandler{
-		namespace: namespace,
-		github:    githubHandler,
-		gitlab:    gitlabHandler,
-		client:    client,
-	}, nil
+	return h, nil
 }
 
 func (h *WebhookHandler) HandleEvent(payload interface{}) {
 	gitGenInfo := getGitGeneratorInfo(payload)
+	scmGenInfo := getSCMProviderGeneratorInfo(payload)
 	prGenInfo := getPRGeneratorInfo(payload)
-	if gitGenInfo == nil && prGenInfo == nil {
+	if gitGenInfo == nil && scmGenInfo == nil && prGenInfo == nil {
 		return
 	}
 
@@ -84,32 +132,85 @@ func (h *WebhookHandler) HandleEvent(payload interface{}) {
 	for _, appSet := range appSetList.Items {
 		shouldRefresh := false
 		for _, gen := range appSet.Spec.Generators {
-			// check if the ApplicationSet uses the git generator that is relevant to the payload
-			shouldRefresh = shouldRefreshGitGenerator(gen.Git, gitGenInfo) || shouldRefreshPRGenerator(gen.PullRequest, prGenInfo)
+			// check if the ApplicationSet uses a generator that is relevant to the payload
+			shouldRefresh = shouldRefreshGitGenerators(getGitGenerators(gen), gitGenInfo) ||
+				shouldRefreshSCMProviderGenerators(getSCMProviderGenerators(gen), scmGenInfo) ||
+				shouldRefreshPRGenerators(getPRGenerators(gen), prGenInfo)
 			if shouldRefresh {
 				break
 			}
 		}
 		if shouldRefresh {
-			err := refreshApplicationSet(h.client, &appSet)
-			if err != nil {
-				log.Errorf("Failed to refresh ApplicationSet '%s' for controller reprocessing", appSet.Name)
-				continue
-			}
-			log.Infof("refresh ApplicationSet %v/%v from webhook", appSet.Namespace, appSet.Name)
+			h.requestRefresh(&appSet)
 		}
 	}
 }
 
+// requestRefresh refreshes the ApplicationSet, once its quiet period is over if the refreshes are debounced.
+func (h *WebhookHandler) requestRefresh(appSet *v1alpha1.ApplicationSet) {
+	if h.debouncer != nil {
+		h.debouncer.request(types.NamespacedName{Namespace: appSet.Namespace, Name: appSet.Name})
+		return
+	}
+	err := refreshApplicationSet(h.client, appSet)
+	if err != nil {
+		log.Errorf("Failed to refresh ApplicationSet '%s' for controller reprocessing", appSet.Name)
+		return
+	}
+	log.Infof("refresh ApplicationSet %v/%v from webhook", appSet.Namespace, appSet.Name)
+}
+
 func (h *WebhookHandler) Handler(w http.ResponseWriter, r *http.Request) {
 	var payload interface{}
-	var err error
+
+	secrets, err := h.getWebhookSecrets(r.Context())
+	if err != nil {
+		log.Errorf("Failed to get the webhook secrets: %v", err)
+		http.Error(w, "Failed to get the webhook secrets", http.StatusInternalServerError)
+		return
+	}
 
 	switch {
+	// Gitea also sends the X-GitHub-Event header, for compatibility
+	case r.Header.Get(giteaEventHeader) != "":
+		payload, err = parseWithSecrets(r, secrets.gitea, func(r *http.Request, secret string) (interface{}, error) {
+			return newGiteaWebhook(secret).Parse(r)
+		})
 	case r.Header.Get("X-GitHub-Event") != "":
-		payload, err = h.github.Parse(r, github.PushEvent, github.PullRequestEvent)
+		payload, err = parseWithSecrets(r, secrets.github, func(r *http.Request, secret string) (interface{}, error) {
+			hook, err := github.New(github.Options.Secret(secret))
+			if err != nil {
+				return nil, err
+			}
+			return hook.Parse(r, github.PushEvent, github.PullRequestEvent)
+		})
 	case r.Header.Get("X-Gitlab-Event") != "":
-		payload, err = h.gitlab.Parse(r, gitlab.PushEvents, gitlab.TagEvents)
+		payload, err = parseWithSecrets(r, secrets.gitlab, func(r *http.Request, secret string) (interface{}, error) {
+			hook, err := gitlab.New(gitlab.Options.Secret(secret))
+			if err != nil {
+				return nil, err
+			}
+			return hook.Parse(r, gitlab.PushEvents, gitlab.TagEvents, gitlab.MergeRequestEvents)
+		})
+	case r.Header.Get(azureDevOpsActivityIDHeader) != "":
+		payload, err = newAzureDevOpsWebhook(secrets.azureDevOps).Parse(r)
+	case r.Header.Get("X-Hook-UUID") != "":
+		// Bitbucket Cloud sends the UUID of the webhook with each event
+		payload, err = parseWithSecrets(r, secrets.bitbucket, func(r *http.Request, uuid string) (interface{}, error) {
+			hook, err := bitbucket.New(bitbucket.Options.UUID(uuid))
+			if err != nil {
+				return nil, err
+			}
+			return hook.Parse(r, bitbucket.RepoPushEvent)
+		})
+	case r.Header.Get("X-Event-Key") != "":
+		payload, err = parseWithSecrets(r, secrets.bitbucketServer, func(r *http.Request, secret string) (interface{}, error) {
+			hook, err := bitbucketserver.New(bitbucketserver.Options.Secret(secret))
+			if err != nil {
+				return nil, err
+			}
+			return hook.Parse(r, bitbucketserver.RepositoryReferenceChangedEvent, bitbucketserver.DiagnosticsPingEvent)
+		})
 	default:
 		log.Debug("Ignoring unknown webhook event")
 		http.Error(w, "Unknown webhook event", http.StatusBadRequest)
@@ -136,40 +237,187 @@ func parseRevision(ref string) string {
 
 func getGitGeneratorInfo(payload interface{}) *gitGeneratorInfo {
 	var (
-		webURL      string
-		revision    string
-		touchedHead bool
+		webURLs      []string
+		revision     string
+		touchedHead  bool
+		allRevisions bool
 	)
 	switch payload := payload.(type) {
 	case github.PushPayload:
-		webURL = payload.Repository.HTMLURL
+		webURLs = append(webURLs, payload.Repository.HTMLURL)
 		revision = parseRevision(payload.Ref)
 		touchedHead = payload.Repository.DefaultBranch == revision
 	case gitlab.PushEventPayload:
-		webURL = payload.Project.WebURL
+		webURLs = append(webURLs, payload.Project.WebURL)
 		revision = parseRevision(payload.Ref)
 		touchedHead = payload.Project.DefaultBranch == revision
+	case giteaPushPayload:
+		webURLs = append(webURLs, payload.Repository.HTMLURL)
+		revision = parseRevision(payload.Ref)
+		touchedHead = payload.Repository.DefaultBranch == revision
+	case azureDevOpsPushPayload:
+		webURLs = getAzureDevOpsRepoURLs(payload.Resource.Repository.RemoteURL)
+		if len(payload.Resource.RefUpdates) > 0 {
+			revision = parseRevision(payload.Resource.RefUpdates[0].Name)
+		}
+		touchedHead = parseRevision(payload.Resource.Repository.DefaultBranch) == revision
+	case bitbucket.RepoPushPayload:
+		webURLs = append(webURLs, payload.Repository.Links.HTML.Href)
+		// a push event may contain several changes, only the first pushed branch or tag is considered
+		for _, change := range payload.Push.Changes {
+			if change.New.Name != "" {
+				revision = change.New.Name
+				break
+			}
+		}
+		// the payload doesn't contain the main branch of the repository, so the generators using HEAD are refreshed
+		touchedHead = true
+	case bitbucketserver.RepositoryReferenceChangedPayload:
+		webURLs = getBitbucketServerCloneURLs(payload.Repository)
+		if len(payload.Changes) > 0 {
+			revision = parseRevision(payload.Changes[0].Reference.ID)
+		}
+		touchedHead = true
+	case genericRefreshPayload:
+		webURLs = payload.Repos
+		revision = parseRevision(payload.Revision)
+		touchedHead = revision == "HEAD"
+		allRevisions = revision == ""
 	default:
 		return nil
 	}
 
-	log.Infof("Received push event repo: %s, revision: %s, touchedHead: %v", webURL, revision, touchedHead)
-	urlObj, err := url.Parse(webURL)
-	if err != nil {
-		log.Errorf("Failed to parse repoURL '%s'", webURL)
+	log.Infof("Received push event repo: %s, revision: %s, touchedHead: %v", strings.Join(webURLs, ", "), revision, touchedHead)
+	var repoRegexpStrs []string
+	for _, webURL := range webURLs {
+		urlObj, err := url.Parse(webURL)
+		if err != nil {
+			log.Errorf("Failed to parse repoURL '%s'", webURL)
+			return nil
+		}
+		path := strings.TrimSuffix(strings.TrimPrefix(urlObj.Path, "/"), ".git")
+		repoRegexpStrs = append(repoRegexpStrs, `(http://|https://|\w+@|ssh://(\w+@)?)`+urlObj.Hostname()+"(:[0-9]+|)[:/]"+path+"(\\.git)?")
+	}
+	if len(repoRegexpStrs) == 0 {
 		return nil
 	}
-	regexpStr := `(?i)(http://|https://|\w+@|ssh://(\w+@)?)` + urlObj.Hostname() + "(:[0-9]+|)[:/]" + urlObj.Path[1:] + "(\\.git)?"
+	regexpStr := "(?i)(" + strings.Join(repoRegexpStrs, "|") + ")"
 	repoRegexp, err := regexp.Compile(regexpStr)
 	if err != nil {
-		log.Errorf("Failed to compile regexp for repoURL '%s'", webURL)
+		log.Errorf("Failed to compile regexp for repoURLs '%s'", strings.Join(webURLs, ", "))
 		return nil
 	}
 
 	return &gitGeneratorInfo{
-		RepoRegexp:  repoRegexp,
-		TouchedHead: touchedHead,
+		Revision:     revision,
+		RepoRegexp:   repoRegexp,
+		TouchedHead:  touchedHead,
+		AllRevisions: allRevisions,
+	}
+}
+
+// getBitbucketServerCloneURLs returns the HTTP and SSH clone URLs of a Bitbucket Server repository, from its links
+// which are not parsed by the webhook library.
+func getBitbucketServerCloneURLs(repository bitbucketserver.Repository) []string {
+	var cloneURLs []string
+	links, _ := repository.Links["clone"].([]interface{})
+	for _, l := range links {
+		link, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name := link["name"]; name != "http" && name != "ssh" {
+			continue
+		}
+		if href, ok := link["href"].(string); ok && href != "" {
+			cloneURLs = append(cloneURLs, href)
+		}
+	}
+	return cloneURLs
+}
+
+// getAzureDevOpsRepoURLs returns the remote URL of an Azure DevOps repository, and its SSH URL on Azure DevOps
+// Services, which has another host and path.
+func getAzureDevOpsRepoURLs(remoteURL string) []string {
+	repoURLs := []string{remoteURL}
+	urlObj, err := url.Parse(remoteURL)
+	if err != nil || !strings.EqualFold(urlObj.Hostname(), "dev.azure.com") {
+		return repoURLs
+	}
+	// https://dev.azure.com/{organization}/{project}/_git/{repo} is git@ssh.dev.azure.com:v3/{organization}/{project}/{repo}
+	parts := strings.Split(strings.TrimPrefix(urlObj.Path, "/"), "/")
+	if len(parts) == 4 && parts[2] == "_git" {
+		repoURLs = append(repoURLs, "ssh://ssh.dev.azure.com/v3/"+parts[0]+"/"+parts[1]+"/"+parts[3])
+	}
+	return repoURLs
+}
+
+// normalizeAzureDevOpsURL returns the URL without its scheme, user and escaping, in lower case, as the URLs of Azure
+// DevOps are case-insensitive, or an empty string if it can't be parsed.
+func normalizeAzureDevOpsURL(rawURL string) string {
+	urlObj, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
 	}
+	return strings.ToLower(urlObj.Host + strings.TrimSuffix(urlObj.Path, "/"))
+}
+
+// getSCMProviderGeneratorInfo returns the information of a push event used to find the SCM Provider generators which
+// discover the pushed repository, or nil if the payload isn't a push event of a supported SCM provider.
+func getSCMProviderGeneratorInfo(payload interface{}) *scmProviderGeneratorInfo {
+	var info scmProviderGeneratorInfo
+	switch payload := payload.(type) {
+	case github.PushPayload:
+		webURL := payload.Repository.HTMLURL
+		urlObj, err := url.Parse(webURL)
+		if err != nil {
+			log.Errorf("Failed to parse repoURL '%s'", webURL)
+			return nil
+		}
+		// the API of GitHub is served by api.github.com, and the API of GitHub Enterprise by the host of the repository
+		regexpStr := `(?i)(http://|https://)(api\.)?` + regexp.QuoteMeta(urlObj.Hostname()) + "(:[0-9]+|)([:/]|$)"
+		apiRegexp, err := regexp.Compile(regexpStr)
+		if err != nil {
+			log.Errorf("Failed to compile regexp for repoURL '%s'", webURL)
+			return nil
+		}
+		organization := payload.Repository.Owner.Login
+		if parts := strings.SplitN(payload.Repository.FullName, "/", 2); len(parts) == 2 {
+			organization = parts[0]
+		}
+		info.Github = &scmProviderGeneratorGithubInfo{
+			Organization: organization,
+			TouchedHead:  payload.Repository.DefaultBranch == parseRevision(payload.Ref),
+			APIRegexp:    apiRegexp,
+		}
+	case gitlab.PushEventPayload:
+		webURL := payload.Project.WebURL
+		urlObj, err := url.Parse(webURL)
+		if err != nil {
+			log.Errorf("Failed to parse repoURL '%s'", webURL)
+			return nil
+		}
+		regexpStr := `(?i)(http://|https://)` + regexp.QuoteMeta(urlObj.Hostname()) + "(:[0-9]+|)([:/]|$)"
+		apiRegexp, err := regexp.Compile(regexpStr)
+		if err != nil {
+			log.Errorf("Failed to compile regexp for repoURL '%s'", webURL)
+			return nil
+		}
+		// the namespace of a project is the full path of its group, including its parent groups
+		namespace := payload.Project.PathWithNamespace
+		if i := strings.LastIndex(namespace, "/"); i >= 0 {
+			namespace = namespace[:i]
+		}
+		info.Gitlab = &scmProviderGeneratorGitlabInfo{
+			Namespace:   namespace,
+			TouchedHead: payload.Project.DefaultBranch == parseRevision(payload.Ref),
+			APIRegexp:   apiRegexp,
+		}
+	default:
+		return nil
+	}
+
+	return &info
 }
 
 func getPRGeneratorInfo(payload interface{}) *prGeneratorInfo {
@@ -197,6 +445,59 @@ func getPRGeneratorInfo(payload interface{}) *prGeneratorInfo {
 			Owner:     payload.Repository.Owner.Login,
 			APIRegexp: apiRegexp,
 		}
+	case giteaPullRequestPayload:
+		if !isAllowedGiteaPullRequestAction(payload.Action) {
+			return nil
+		}
+
+		webURL := payload.Repository.HTMLURL
+		urlObj, err := url.Parse(webURL)
+		if err != nil {
+			log.Errorf("Failed to parse repoURL '%s'", webURL)
+			return nil
+		}
+		regexpStr := `(?i)(http://|https://)` + regexp.QuoteMeta(urlObj.Hostname()) + "(:[0-9]+|)([:/]|$)"
+		apiRegexp, err := regexp.Compile(regexpStr)
+		if err != nil {
+			log.Errorf("Failed to compile regexp for repoURL '%s'", webURL)
+			return nil
+		}
+		info.Gitea = &prGeneratorGiteaInfo{
+			Repo:      payload.Repository.Name,
+			Owner:     payload.Repository.Owner.Login,
+			APIRegexp: apiRegexp,
+		}
+	case azureDevOpsPullRequestPayload:
+		repoURL := normalizeAzureDevOpsURL(payload.Resource.Repository.RemoteURL)
+		if repoURL == "" {
+			log.Errorf("Failed to parse repoURL '%s'", payload.Resource.Repository.RemoteURL)
+			return nil
+		}
+		info.AzureDevOps = &prGeneratorAzureDevOpsInfo{
+			RepoURL: repoURL,
+		}
+	case gitlab.MergeRequestEventPayload:
+		if !isAllowedMergeRequestAction(payload.ObjectAttributes.Action) {
+			return nil
+		}
+
+		webURL := payload.Project.WebURL
+		urlObj, err := url.Parse(webURL)
+		if err != nil {
+			log.Errorf("Failed to parse repoURL '%s'", webURL)
+			return nil
+		}
+		regexpStr := `(?i)(http://|https://|\w+@|ssh://(\w+@)?)` + urlObj.Hostname() + "(:[0-9]+|)([:/]|$)"
+		apiRegexp, err := regexp.Compile(regexpStr)
+		if err != nil {
+			log.Errorf("Failed to compile regexp for repoURL '%s'", webURL)
+			return nil
+		}
+		info.Gitlab = &prGeneratorGitlabInfo{
+			Project:   payload.Project.PathWithNamespace,
+			ProjectID: fmt.Sprint(payload.ObjectAttributes.TargetProjectID),
+			APIRegexp: apiRegexp,
+		}
 	default:
 		return nil
 	}
@@ -223,6 +524,54 @@ func isAllowedPullRequestAction(action string) bool {
 	return false
 }
 
+// allowedGiteaPullRequestActions is a list of Gitea pull request actions that allow refresh. A merged pull request is
+// "closed".
+var allowedGiteaPullRequestActions = []string{
+	"opened",
+	"closed",
+	"reopened",
+	"synchronized",
+	"label_updated",
+	"label_cleared",
+}
+
+func isAllowedGiteaPullRequestAction(action string) bool {
+	for _, allow := range allowedGiteaPullRequestActions {
+		if allow == action {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedMergeRequestActions is a list of GitLab merge request actions that allow refresh.
+// "update" covers both new commits and label changes.
+var allowedMergeRequestActions = []string{
+	"open",
+	"close",
+	"reopen",
+	"update",
+	"merge",
+}
+
+func isAllowedMergeRequestAction(action string) bool {
+	for _, allow := range allowedMergeRequestActions {
+		if allow == action {
+			return true
+		}
+	}
+	return false
+}
+
+func shouldRefreshGitGenerators(gens []*v1alpha1.GitGenerator, info *gitGeneratorInfo) bool {
+	for _, gen := range gens {
+		if shouldRefreshGitGenerator(gen, info) {
+			return true
+		}
+	}
+	return false
+}
+
 func shouldRefreshGitGenerator(gen *v1alpha1.GitGenerator, info *gitGeneratorInfo) bool {
 	if gen == nil || info == nil {
 		return false
@@ -231,7 +580,7 @@ func shouldRefreshGitGenerator(gen *v1alpha1.GitGenerator, info *gitGeneratorInf
 	if !gitGeneratorUsesURL(gen, info.Revision, info.RepoRegexp) {
 		return false
 	}
-	if !genRevisionHasChanged(gen, info.Revision, info.TouchedHead) {
+	if !info.AllRevisions && !genRevisionHasChanged(gen, info.Revision, info.TouchedHead) {
 		return false
 	}
 	return true
@@ -256,26 +605,232 @@ func gitGeneratorUsesURL(gen *v1alpha1.GitGenerator, webURL string, repoRegexp *
 	return true
 }
 
+// getChildGenerators returns the generators nested within the Matrix, Merge and Union generators of gen, and the
+// terminal generators nested within them.
+func getChildGenerators(gen v1alpha1.ApplicationSetGenerator) ([]v1alpha1.ApplicationSetNestedGenerator, []v1alpha1.ApplicationSetTerminalGenerator) {
+	var nested []v1alpha1.ApplicationSetNestedGenerator
+	if gen.Matrix != nil {
+		nested = append(nested, gen.Matrix.Generators...)
+	}
+	if gen.Merge != nil {
+		nested = append(nested, gen.Merge.Generators...)
+	}
+	if gen.Union != nil {
+		nested = append(nested, gen.Union.Generators...)
+	}
+
+	var terminal []v1alpha1.ApplicationSetTerminalGenerator
+	for _, nestedGen := range nested {
+		if nestedGen.Matrix != nil {
+			terminal = append(terminal, nestedGen.Matrix.Generators...)
+		}
+		if nestedGen.Merge != nil {
+			terminal = append(terminal, nestedGen.Merge.Generators...)
+		}
+		if nestedGen.Union != nil {
+			terminal = append(terminal, nestedGen.Union.Generators...)
+		}
+	}
+	return nested, terminal
+}
+
+// getGitGenerators returns the Git generators of gen, including the ones nested within Matrix, Merge and Union
+// generators.
+func getGitGenerators(gen v1alpha1.ApplicationSetGenerator) []*v1alpha1.GitGenerator {
+	nested, terminal := getChildGenerators(gen)
+	gitGens := []*v1alpha1.GitGenerator{gen.Git}
+	for _, nestedGen := range nested {
+		gitGens = append(gitGens, nestedGen.Git)
+	}
+	for _, terminalGen := range terminal {
+		gitGens = append(gitGens, terminalGen.Git)
+	}
+	return gitGens
+}
+
+// getSCMProviderGenerators returns the SCM Provider generators of gen, including the ones nested within Matrix, Merge
+// and Union generators.
+func getSCMProviderGenerators(gen v1alpha1.ApplicationSetGenerator) []*v1alpha1.SCMProviderGenerator {
+	nested, terminal := getChildGenerators(gen)
+	scmGens := []*v1alpha1.SCMProviderGenerator{gen.SCMProvider}
+	for _, nestedGen := range nested {
+		scmGens = append(scmGens, nestedGen.SCMProvider)
+	}
+	for _, terminalGen := range terminal {
+		scmGens = append(scmGens, terminalGen.SCMProvider)
+	}
+	return scmGens
+}
+
+// getPRGenerators returns the PullRequest generators of gen, including the ones nested within Matrix, Merge and Union
+// generators.
+func getPRGenerators(gen v1alpha1.ApplicationSetGenerator) []*v1alpha1.PullRequestGenerator {
+	nested, terminal := getChildGenerators(gen)
+	prGens := []*v1alpha1.PullRequestGenerator{gen.PullRequest}
+	for _, nestedGen := range nested {
+		prGens = append(prGens, nestedGen.PullRequest)
+	}
+	for _, terminalGen := range terminal {
+		prGens = append(prGens, terminalGen.PullRequest)
+	}
+	return prGens
+}
+
+func shouldRefreshSCMProviderGenerators(gens []*v1alpha1.SCMProviderGenerator, info *scmProviderGeneratorInfo) bool {
+	for _, gen := range gens {
+		if shouldRefreshSCMProviderGenerator(gen, info) {
+			return true
+		}
+	}
+	return false
+}
+
+func shouldRefreshSCMProviderGenerator(gen *v1alpha1.SCMProviderGenerator, info *scmProviderGeneratorInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+
+	return shouldRefreshGithubSCMProviderGenerator(gen.Github, info.Github) || shouldRefreshGitlabSCMProviderGenerator(gen.Gitlab, info.Gitlab)
+}
+
+func shouldRefreshGithubSCMProviderGenerator(gen *v1alpha1.SCMProviderGeneratorGithub, info *scmProviderGeneratorGithubInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+	if !strings.EqualFold(gen.Organization, info.Organization) {
+		return false
+	}
+	// without allBranches, the generator only discovers the default branch of the repositories
+	if !gen.AllBranches && !info.TouchedHead {
+		return false
+	}
+	api := gen.API
+	if api == "" {
+		api = "https://api.github.com/"
+	}
+	if !info.APIRegexp.MatchString(api) {
+		log.Debugf("%s does not match %s", gen.API, info.APIRegexp.String())
+		return false
+	}
+
+	return true
+}
+
+func shouldRefreshPRGenerators(gens []*v1alpha1.PullRequestGenerator, info *prGeneratorInfo) bool {
+	for _, gen := range gens {
+		if shouldRefreshPRGenerator(gen, info) {
+			return true
+		}
+	}
+	return false
+}
+
 func shouldRefreshPRGenerator(gen *v1alpha1.PullRequestGenerator, info *prGeneratorInfo) bool {
 	if gen == nil || info == nil {
 		return false
 	}
 
-	if gen.Github == nil || info.Github == nil {
+	return shouldRefreshGithubPRGenerator(gen.Github, info.Github) || shouldRefreshGitlabPRGenerator(gen.GitLab, info.Gitlab) ||
+		shouldRefreshGiteaPRGenerator(gen.Gitea, info.Gitea) || shouldRefreshAzureDevOpsPRGenerator(gen.AzureDevOps, info.AzureDevOps)
+}
+
+func shouldRefreshGithubPRGenerator(gen *v1alpha1.PullRequestGeneratorGithub, info *prGeneratorGithubInfo) bool {
+	if gen == nil || info == nil {
 		return false
 	}
-	if gen.Github.Owner != info.Github.Owner {
+	if gen.Owner != info.Owner {
 		return false
 	}
-	if gen.Github.Repo != info.Github.Repo {
+	if gen.Repo != info.Repo {
 		return false
 	}
-	api := gen.Github.API
+	api := gen.API
 	if api == "" {
 		api = "https://api.github.com/"
 	}
-	if !info.Github.APIRegexp.MatchString(api) {
-		log.Debugf("%s does not match %s", gen.Github.API, info.Github.APIRegexp.String())
+	if !info.APIRegexp.MatchString(api) {
+		log.Debugf("%s does not match %s", gen.API, info.APIRegexp.String())
+		return false
+	}
+
+	return true
+}
+
+func shouldRefreshGiteaPRGenerator(gen *v1alpha1.PullRequestGeneratorGitea, info *prGeneratorGiteaInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+	if gen.Owner != info.Owner {
+		return false
+	}
+	if gen.Repo != info.Repo {
+		return false
+	}
+	if !info.APIRegexp.MatchString(gen.API) {
+		log.Debugf("%s does not match %s", gen.API, info.APIRegexp.String())
+		return false
+	}
+
+	return true
+}
+
+func shouldRefreshAzureDevOpsPRGenerator(gen *v1alpha1.PullRequestGeneratorAzureDevOps, info *prGeneratorAzureDevOpsInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+	api := gen.API
+	if api == "" {
+		api = "https://dev.azure.com"
+	}
+	// the generator lists the pull requests of {api}/{organization}/{project}/_git/{repo}
+	repoURL := normalizeAzureDevOpsURL(strings.TrimSuffix(api, "/") + "/" + gen.Organization + "/" + gen.Project + "/_git/" + gen.Repo)
+	if repoURL != info.RepoURL {
+		log.Debugf("%s does not match %s", repoURL, info.RepoURL)
+		return false
+	}
+
+	return true
+}
+
+func shouldRefreshGitlabPRGenerator(gen *v1alpha1.PullRequestGeneratorGitLab, info *prGeneratorGitlabInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+	if gen.Project != info.Project && gen.Project != info.ProjectID {
+		return false
+	}
+	api := gen.API
+	if api == "" {
+		api = "https://gitlab.com/"
+	}
+	if !info.APIRegexp.MatchString(api) {
+		log.Debugf("%s does not match %s", gen.API, info.APIRegexp.String())
+		return false
+	}
+
+	return true
+}
+
+func shouldRefreshGitlabSCMProviderGenerator(gen *v1alpha1.SCMProviderGeneratorGitlab, info *scmProviderGeneratorGitlabInfo) bool {
+	if gen == nil || info == nil {
+		return false
+	}
+	// a group referenced by its ID can't be matched, as push events only contain the path of the project
+	group := strings.Trim(gen.Group, "/")
+	if !strings.EqualFold(group, info.Namespace) &&
+		!(gen.IncludeSubgroups && strings.HasPrefix(strings.ToLower(info.Namespace), strings.ToLower(group)+"/")) {
+		return false
+	}
+	// without allBranches, the generator only discovers the default branch of the projects
+	if !gen.AllBranches && !info.TouchedHead {
+		return false
+	}
+	api := gen.API
+	if api == "" {
+		api = "https://gitlab.com/"
+	}
+	if !info.APIRegexp.MatchString(api) {
+		log.Debugf("%s does not match %s", gen.API, info.APIRegexp.String())
 		return false
 	}
 
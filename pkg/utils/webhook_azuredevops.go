@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// The webhook library doesn't support the service hooks of Azure DevOps, so their events are parsed here.
+const (
+	azureDevOpsActivityIDHeader = "X-Vss-ActivityId"
+
+	azureDevOpsPushEvent               = "git.push"
+	azureDevOpsPullRequestCreatedEvent = "git.pullrequest.created"
+	azureDevOpsPullRequestUpdatedEvent = "git.pullrequest.updated"
+	azureDevOpsPullRequestMergedEvent  = "git.pullrequest.merged"
+
+	// The keys of the argocd-secret Secret holding the credentials of the Azure DevOps service hooks
+	settingsWebhookAzureDevOpsUsernameKey = "webhook.azuredevops.username"
+	settingsWebhookAzureDevOpsPasswordKey = "webhook.azuredevops.password"
+	settingsWebhookAzureDevOpsTokenKey    = "webhook.azuredevops.token"
+)
+
+var (
+	errAzureDevOpsInvalidHTTPMethod = errors.New("invalid HTTP Method")
+	errAzureDevOpsUnauthorized      = errors.New("invalid or missing credentials")
+	errAzureDevOpsEventNotFound     = errors.New("event not defined to be parsed")
+	errAzureDevOpsParsingPayload    = errors.New("error parsing payload")
+)
+
+// azureDevOpsWebhook parses the push and pull request events of the Azure DevOps service hooks, and validates their
+// basic authentication or bearer token when credentials are configured.
+type azureDevOpsWebhook struct {
+	credentials []azureDevOpsCredentials
+}
+
+// azureDevOpsCredentials are the basic authentication, or the bearer token, of a service hook.
+type azureDevOpsCredentials struct {
+	username string
+	password string
+	token    string
+}
+
+// azureDevOpsRepository is the subset of the Azure DevOps repository of the service hook payloads used to match the
+// generators.
+type azureDevOpsRepository struct {
+	Name          string `json:"name"`
+	RemoteURL     string `json:"remoteUrl"`
+	DefaultBranch string `json:"defaultBranch"`
+	Project       struct {
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+type azureDevOpsPushPayload struct {
+	EventType string `json:"eventType"`
+	Resource  struct {
+		RefUpdates []struct {
+			Name string `json:"name"`
+		} `json:"refUpdates"`
+		Repository azureDevOpsRepository `json:"repository"`
+	} `json:"resource"`
+}
+
+type azureDevOpsPullRequestPayload struct {
+	EventType string `json:"eventType"`
+	Resource  struct {
+		PullRequestID int                   `json:"pullRequestId"`
+		Repository    azureDevOpsRepository `json:"repository"`
+	} `json:"resource"`
+}
+
+func newAzureDevOpsWebhook(credentials []azureDevOpsCredentials) *azureDevOpsWebhook {
+	return &azureDevOpsWebhook{credentials: credentials}
+}
+
+// authorized returns true if no credentials are configured, or if the request carries one of the configured basic
+// authentications or bearer tokens.
+func (hook *azureDevOpsWebhook) authorized(r *http.Request) bool {
+	if len(hook.credentials) == 0 {
+		return true
+	}
+	username, password, basicAuth := r.BasicAuth()
+	auth := r.Header.Get("Authorization")
+	for _, c := range hook.credentials {
+		if (c.username != "" || c.password != "") && basicAuth &&
+			subtle.ConstantTimeCompare([]byte(username), []byte(c.username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(password), []byte(c.password)) == 1 {
+			return true
+		}
+		if c.token != "" && strings.HasPrefix(auth, "Bearer ") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(c.token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse validates and parses the push and pull request events, and returns an azureDevOpsPushPayload or an
+// azureDevOpsPullRequestPayload.
+func (hook *azureDevOpsWebhook) Parse(r *http.Request) (interface{}, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	if r.Method != http.MethodPost {
+		return nil, errAzureDevOpsInvalidHTTPMethod
+	}
+	if !hook.authorized(r) {
+		return nil, errAzureDevOpsUnauthorized
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, errAzureDevOpsParsingPayload
+	}
+	var event struct {
+		EventType string `json:"eventType"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, errAzureDevOpsParsingPayload
+	}
+
+	switch event.EventType {
+	case azureDevOpsPushEvent:
+		var payload azureDevOpsPushPayload
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	case azureDevOpsPullRequestCreatedEvent, azureDevOpsPullRequestUpdatedEvent, azureDevOpsPullRequestMergedEvent:
+		var payload azureDevOpsPullRequestPayload
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	default:
+		return nil, errAzureDevOpsEventNotFound
+	}
+}
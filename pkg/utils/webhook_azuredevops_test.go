@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureDevOpsWebhookParse(t *testing.T) {
+	pushJSON, err := ioutil.ReadFile(filepath.Join("testdata", "azuredevops-push-event.json"))
+	assert.NoError(t, err)
+	pullRequestJSON, err := ioutil.ReadFile(filepath.Join("testdata", "azuredevops-pull-request-created-event.json"))
+	assert.NoError(t, err)
+
+	credentials := []azureDevOpsCredentials{
+		{username: "username", password: "password"},
+		{token: "token"},
+	}
+
+	tt := []struct {
+		desc          string
+		credentials   []azureDevOpsCredentials
+		payload       []byte
+		username      string
+		password      string
+		authorization string
+		expectedType  interface{}
+		expectedError error
+	}{
+		{
+			desc:         "push event without configured credentials",
+			payload:      pushJSON,
+			expectedType: azureDevOpsPushPayload{},
+		},
+		{
+			desc:         "push event with basic authentication",
+			credentials:  credentials,
+			payload:      pushJSON,
+			username:     "username",
+			password:     "password",
+			expectedType: azureDevOpsPushPayload{},
+		},
+		{
+			desc:          "pull request event with a bearer token",
+			credentials:   credentials,
+			payload:       pullRequestJSON,
+			authorization: "Bearer token",
+			expectedType:  azureDevOpsPullRequestPayload{},
+		},
+		{
+			desc:          "invalid password",
+			credentials:   credentials,
+			payload:       pushJSON,
+			username:      "username",
+			password:      "invalid",
+			expectedError: errAzureDevOpsUnauthorized,
+		},
+		{
+			desc:          "invalid token",
+			credentials:   []azureDevOpsCredentials{{token: "token"}},
+			payload:       pushJSON,
+			authorization: "Bearer invalid",
+			expectedError: errAzureDevOpsUnauthorized,
+		},
+		{
+			desc:          "missing credentials",
+			credentials:   credentials,
+			payload:       pushJSON,
+			expectedError: errAzureDevOpsUnauthorized,
+		},
+		{
+			desc:          "unsupported event",
+			payload:       []byte(`{"eventType": "build.complete"}`),
+			expectedError: errAzureDevOpsEventNotFound,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/webhook", bytes.NewReader(test.payload))
+			req.Header.Set(azureDevOpsActivityIDHeader, "3a7c2e1f-8f4b-4f0a-9d6e-2b1c0a9f8e7d")
+			if test.username != "" {
+				req.SetBasicAuth(test.username, test.password)
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+
+			payload, err := newAzureDevOpsWebhook(test.credentials).Parse(req)
+			if test.expectedError != nil {
+				assert.Equal(t, test.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, test.expectedType, payload)
+		})
+	}
+}
+
+func TestShouldRefreshAzureDevOpsPRGenerator(t *testing.T) {
+	info := &prGeneratorAzureDevOpsInfo{RepoURL: normalizeAzureDevOpsURL("https://org@dev.azure.com/org/My%20Project/_git/repo")}
+	assert.True(t, shouldRefreshAzureDevOpsPRGenerator(&v1alpha1.PullRequestGeneratorAzureDevOps{Organization: "org", Project: "My Project", Repo: "repo"}, info))
+	assert.True(t, shouldRefreshAzureDevOpsPRGenerator(&v1alpha1.PullRequestGeneratorAzureDevOps{Organization: "Org", Project: "my project", Repo: "Repo", API: "https://dev.azure.com/"}, info))
+	assert.False(t, shouldRefreshAzureDevOpsPRGenerator(&v1alpha1.PullRequestGeneratorAzureDevOps{Organization: "org", Project: "My Project", Repo: "other"}, info))
+	assert.False(t, shouldRefreshAzureDevOpsPRGenerator(&v1alpha1.PullRequestGeneratorAzureDevOps{Organization: "org", Project: "My Project", Repo: "repo", API: "https://azuredevops.example.com/tfs"}, info))
+	assert.False(t, shouldRefreshAzureDevOpsPRGenerator(nil, info))
+}
+
+func TestGetAzureDevOpsRepoURLs(t *testing.T) {
+	assert.Equal(t, []string{"https://org@dev.azure.com/org/project/_git/repo", "ssh://ssh.dev.azure.com/v3/org/project/repo"}, getAzureDevOpsRepoURLs("https://org@dev.azure.com/org/project/_git/repo"))
+	assert.Equal(t, []string{"https://azuredevops.example.com/tfs/collection/project/_git/repo"}, getAzureDevOpsRepoURLs("https://azuredevops.example.com/tfs/collection/project/_git/repo"))
+}
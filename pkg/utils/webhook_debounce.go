@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// webhookDebounceMaxDelayFactor bounds the delay of a refresh to this number of quiet periods after the first event
+// requesting it, so that a continuous stream of events doesn't postpone the refresh indefinitely.
+const webhookDebounceMaxDelayFactor = 5
+
+// refreshDebouncer delays the refreshes of the ApplicationSets requested by webhook events until no event requested
+// them for the quiet period, so that a burst of events, e.g. rapid pushes to a monorepo, results in a single refresh of
+// each ApplicationSet.
+type refreshDebouncer struct {
+	quietPeriod time.Duration
+	maxDelay    time.Duration
+	refresh     func(key types.NamespacedName)
+
+	lock    sync.Mutex
+	pending map[types.NamespacedName]*pendingRefresh
+}
+
+// pendingRefresh is a refresh of an ApplicationSet waiting for the end of the quiet period.
+type pendingRefresh struct {
+	timer  *time.Timer
+	first  time.Time
+	events int
+}
+
+func newRefreshDebouncer(quietPeriod time.Duration, refresh func(key types.NamespacedName)) *refreshDebouncer {
+	return &refreshDebouncer{
+		quietPeriod: quietPeriod,
+		maxDelay:    webhookDebounceMaxDelayFactor * quietPeriod,
+		refresh:     refresh,
+		pending:     map[types.NamespacedName]*pendingRefresh{},
+	}
+}
+
+// request schedules the refresh of the ApplicationSet at the end of the quiet period, coalescing it with the refresh
+// already pending, if any.
+func (d *refreshDebouncer) request(key types.NamespacedName) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := time.Now()
+	if p, ok := d.pending[key]; ok {
+		p.events++
+		delay := d.quietPeriod
+		if remaining := p.first.Add(d.maxDelay).Sub(now); remaining < delay {
+			delay = remaining
+		}
+		if delay < 0 {
+			delay = 0
+		}
+		p.timer.Reset(delay)
+		return
+	}
+
+	p := &pendingRefresh{first: now, events: 1}
+	p.timer = time.AfterFunc(d.quietPeriod, func() {
+		d.fire(key, p)
+	})
+	d.pending[key] = p
+}
+
+// fire refreshes the ApplicationSet once its quiet period is over.
+func (d *refreshDebouncer) fire(key types.NamespacedName, p *pendingRefresh) {
+	d.lock.Lock()
+	// the timer may fire again after the refresh, if it was reset while the refresh was starting
+	if d.pending[key] != p {
+		d.lock.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	events := p.events
+	d.lock.Unlock()
+
+	log.Infof("refresh ApplicationSet %v/%v from %d webhook event(s)", key.Namespace, key.Name, events)
+	d.refresh(key)
+}
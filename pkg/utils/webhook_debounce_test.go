@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRefreshDebouncer(t *testing.T) {
+	quietPeriod := 50 * time.Millisecond
+	appSet1 := types.NamespacedName{Namespace: "namespace", Name: "appset1"}
+	appSet2 := types.NamespacedName{Namespace: "namespace", Name: "appset2"}
+
+	var lock sync.Mutex
+	refreshes := map[types.NamespacedName]int{}
+	getRefreshes := func(key types.NamespacedName) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return refreshes[key]
+	}
+	d := newRefreshDebouncer(quietPeriod, func(key types.NamespacedName) {
+		lock.Lock()
+		defer lock.Unlock()
+		refreshes[key]++
+	})
+
+	// a burst of events is coalesced in a single refresh, at the end of the quiet period
+	for i := 0; i < 5; i++ {
+		d.request(appSet1)
+		time.Sleep(quietPeriod / 5)
+	}
+	d.request(appSet2)
+	assert.Equal(t, 0, getRefreshes(appSet1))
+	assert.Eventually(t, func() bool {
+		return getRefreshes(appSet1) == 1 && getRefreshes(appSet2) == 1
+	}, time.Second, quietPeriod/10)
+	time.Sleep(2 * quietPeriod)
+	assert.Equal(t, 1, getRefreshes(appSet1))
+	assert.Equal(t, 1, getRefreshes(appSet2))
+
+	// a continuous stream of events doesn't postpone the refresh beyond the maximum delay
+	start := time.Now()
+	for time.Since(start) < 2*d.maxDelay {
+		d.request(appSet1)
+		time.Sleep(quietPeriod / 2)
+	}
+	assert.GreaterOrEqual(t, getRefreshes(appSet1), 2)
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/argoproj-labs/applicationset/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The refresh endpoint lets CI systems and internal platforms, which are not Git providers, request the refresh of
+// ApplicationSets, with a plain JSON body or a CloudEvent carrying it.
+const (
+	// cloudEventsContentType is the content type of the CloudEvents in structured mode, whose data is the request
+	cloudEventsContentType = "application/cloudevents+json"
+	// cloudEventsSpecVersionHeader is sent with the CloudEvents in binary mode, whose body is the request
+	cloudEventsSpecVersionHeader = "Ce-Specversion"
+
+	// settingsWebhookRefreshTokenKey is the key of the argocd-secret Secret holding the bearer token of the refresh
+	// endpoint
+	settingsWebhookRefreshTokenKey = "webhook.applicationset.refresh.token"
+)
+
+var (
+	errRefreshInvalidHTTPMethod = errors.New("invalid HTTP Method")
+	errRefreshUnauthorized      = errors.New("invalid or missing bearer token")
+	errRefreshParsingPayload    = errors.New("error parsing payload")
+	errRefreshEmptyRequest      = errors.New("no repository or ApplicationSet to refresh")
+)
+
+// genericRefreshPayload is the request of the refresh endpoint.
+type genericRefreshPayload struct {
+	// Repos are the URLs of the Git repositories whose Git generators are refreshed
+	Repos []string `json:"repos,omitempty"`
+	// Revision restricts the refresh to the Git generators targeting this branch or tag, all the Git generators of the
+	// repositories are refreshed if empty
+	Revision string `json:"revision,omitempty"`
+	// ApplicationSets are refreshed whatever their generators, as namespace/name, or name in the namespace of the
+	// controller
+	ApplicationSets []string `json:"applicationSets,omitempty"`
+}
+
+// cloudEvent is the envelope of a CloudEvent in structured mode.
+type cloudEvent struct {
+	SpecVersion string          `json:"specversion"`
+	Type        string          `json:"type"`
+	Source      string          `json:"source"`
+	ID          string          `json:"id"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// genericWebhook parses the requests of the refresh endpoint, which must carry one of the configured bearer tokens.
+type genericWebhook struct {
+	tokens []string
+}
+
+func newGenericWebhook(tokens []string) *genericWebhook {
+	return &genericWebhook{tokens: tokens}
+}
+
+// authorized returns true if the request carries one of the configured bearer tokens. Unlike the events of the Git
+// providers, the requests are never accepted without a token, since they name the ApplicationSets to refresh.
+func (hook *genericWebhook) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	for _, t := range hook.tokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse validates and parses a refresh request, sent as a plain JSON body, or as a CloudEvent in structured or binary
+// mode.
+func (hook *genericWebhook) Parse(r *http.Request) (*genericRefreshPayload, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	if r.Method != http.MethodPost {
+		return nil, errRefreshInvalidHTTPMethod
+	}
+	if !hook.authorized(r) {
+		return nil, errRefreshUnauthorized
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, errRefreshParsingPayload
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == cloudEventsContentType {
+		var event cloudEvent
+		if err := json.Unmarshal(body, &event); err != nil || len(event.Data) == 0 {
+			return nil, errRefreshParsingPayload
+		}
+		log.Debugf("Received CloudEvent %s of type %s from %s", event.ID, event.Type, event.Source)
+		body = event.Data
+	} else if r.Header.Get(cloudEventsSpecVersionHeader) != "" {
+		log.Debugf("Received CloudEvent %s of type %s from %s", r.Header.Get("Ce-Id"), r.Header.Get("Ce-Type"), r.Header.Get("Ce-Source"))
+	}
+
+	var payload genericRefreshPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errRefreshParsingPayload
+	}
+	if len(payload.Repos) == 0 && len(payload.ApplicationSets) == 0 {
+		return nil, errRefreshEmptyRequest
+	}
+	return &payload, nil
+}
+
+// RefreshHandler refreshes the ApplicationSets named by the request, and the ApplicationSets whose Git generators use
+// its repositories.
+func (h *WebhookHandler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	secrets, err := h.getWebhookSecrets(r.Context())
+	if err != nil {
+		log.Errorf("Failed to get the webhook secrets: %v", err)
+		http.Error(w, "Failed to get the webhook secrets", http.StatusInternalServerError)
+		return
+	}
+
+	payload, err := newGenericWebhook(secrets.refresh).Parse(r)
+	if err != nil {
+		log.Infof("Refresh request processing failed: %s", err)
+		status := http.StatusBadRequest
+		switch err {
+		case errRefreshInvalidHTTPMethod:
+			status = http.StatusMethodNotAllowed
+		case errRefreshUnauthorized:
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, fmt.Sprintf("Refresh request processing failed: %s", html.EscapeString(err.Error())), status)
+		return
+	}
+
+	appSets, err := h.getNamedApplicationSets(r.Context(), payload.ApplicationSets)
+	if err != nil {
+		log.Infof("Refresh request processing failed: %s", err)
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, fmt.Sprintf("Refresh request processing failed: %s", html.EscapeString(err.Error())), status)
+		return
+	}
+	for i := range appSets {
+		h.requestRefresh(&appSets[i])
+	}
+
+	if len(payload.Repos) > 0 {
+		h.HandleEvent(*payload)
+	}
+}
+
+// getNamedApplicationSets returns the ApplicationSets named as namespace/name, or name in the namespace of the
+// controller, so that the request is rejected before any refresh if one of them doesn't exist.
+func (h *WebhookHandler) getNamedApplicationSets(ctx context.Context, names []string) ([]v1alpha1.ApplicationSet, error) {
+	var appSets []v1alpha1.ApplicationSet
+	for _, name := range names {
+		key := types.NamespacedName{Namespace: h.namespace, Name: name}
+		if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+			key = types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+		}
+		appSet := v1alpha1.ApplicationSet{}
+		if err := h.client.Get(ctx, key, &appSet); err != nil {
+			return nil, err
+		}
+		appSets = append(appSets, appSet)
+	}
+	return appSets, nil
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	argosettings "github.com/argoproj/argo-cd/v2/util/settings"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenericWebhookParse(t *testing.T) {
+	tt := []struct {
+		desc          string
+		method        string
+		headers       map[string]string
+		body          string
+		expected      *genericRefreshPayload
+		expectedError error
+	}{
+		{
+			desc:     "JSON body",
+			headers:  map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json"},
+			body:     `{"repos": ["https://github.com/org/repo"], "revision": "main"}`,
+			expected: &genericRefreshPayload{Repos: []string{"https://github.com/org/repo"}, Revision: "main"},
+		},
+		{
+			desc:     "rotated token",
+			headers:  map[string]string{"Authorization": "Bearer next"},
+			body:     `{"applicationSets": ["argocd/appset"]}`,
+			expected: &genericRefreshPayload{ApplicationSets: []string{"argocd/appset"}},
+		},
+		{
+			desc:    "structured CloudEvent",
+			headers: map[string]string{"Authorization": "Bearer token", "Content-Type": "application/cloudevents+json; charset=utf-8"},
+			body: `{"specversion": "1.0", "type": "com.example.build.finished", "source": "/ci", "id": "1",
+				"data": {"applicationSets": ["appset"]}}`,
+			expected: &genericRefreshPayload{ApplicationSets: []string{"appset"}},
+		},
+		{
+			desc: "binary CloudEvent",
+			headers: map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json",
+				"Ce-Specversion": "1.0", "Ce-Type": "com.example.build.finished", "Ce-Source": "/ci", "Ce-Id": "1"},
+			body:     `{"repos": ["https://github.com/org/repo"]}`,
+			expected: &genericRefreshPayload{Repos: []string{"https://github.com/org/repo"}},
+		},
+		{
+			desc:          "structured CloudEvent without data",
+			headers:       map[string]string{"Authorization": "Bearer token", "Content-Type": "application/cloudevents+json"},
+			body:          `{"specversion": "1.0", "type": "com.example.build.finished", "source": "/ci", "id": "1"}`,
+			expectedError: errRefreshParsingPayload,
+		},
+		{
+			desc:          "invalid token",
+			headers:       map[string]string{"Authorization": "Bearer other"},
+			body:          `{"applicationSets": ["appset"]}`,
+			expectedError: errRefreshUnauthorized,
+		},
+		{
+			desc:          "basic authentication",
+			headers:       map[string]string{"Authorization": "Basic dG9rZW46dG9rZW4="},
+			body:          `{"applicationSets": ["appset"]}`,
+			expectedError: errRefreshUnauthorized,
+		},
+		{
+			desc:          "missing token",
+			body:          `{"applicationSets": ["appset"]}`,
+			expectedError: errRefreshUnauthorized,
+		},
+		{
+			desc:          "empty request",
+			headers:       map[string]string{"Authorization": "Bearer token"},
+			body:          `{}`,
+			expectedError: errRefreshEmptyRequest,
+		},
+		{
+			desc:          "invalid JSON",
+			headers:       map[string]string{"Authorization": "Bearer token"},
+			body:          `{"repos": "https://github.com/org/repo"}`,
+			expectedError: errRefreshParsingPayload,
+		},
+		{
+			desc:          "GET request",
+			method:        "GET",
+			headers:       map[string]string{"Authorization": "Bearer token"},
+			expectedError: errRefreshInvalidHTTPMethod,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			method := test.method
+			if method == "" {
+				method = "POST"
+			}
+			req := httptest.NewRequest(method, "/api/webhook/refresh", bytes.NewReader([]byte(test.body)))
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			payload, err := newGenericWebhook([]string{"token", "next"}).Parse(req)
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expected, payload)
+		})
+	}
+}
+
+func TestGenericWebhookWithoutTokens(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/webhook/refresh", bytes.NewReader([]byte(`{"applicationSets": ["appset"]}`)))
+	req.Header.Set("Authorization", "Bearer ")
+
+	_, err := newGenericWebhook(nil).Parse(req)
+	assert.Equal(t, errRefreshUnauthorized, err)
+}
+
+func TestRefreshHandler(t *testing.T) {
+	namespace := "test"
+	fakeClient := newFakeClient(namespace)
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = corev1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	tt := []struct {
+		desc               string
+		token              string
+		body               string
+		expectedStatusCode int
+		effectedAppSets    []string
+	}{
+		{
+			desc:               "repository",
+			token:              "token",
+			body:               `{"repos": ["https://github.com/org/repo"]}`,
+			expectedStatusCode: http.StatusOK,
+			effectedAppSets:    []string{"git-github", "git-github-dev"},
+		},
+		{
+			desc:               "repository and revision",
+			token:              "token",
+			body:               `{"repos": ["https://github.com/org/repo.git"], "revision": "refs/heads/dev"}`,
+			expectedStatusCode: http.StatusOK,
+			effectedAppSets:    []string{"git-github-dev"},
+		},
+		{
+			desc:               "repository and HEAD",
+			token:              "token",
+			body:               `{"repos": ["https://github.com/org/repo"], "revision": "HEAD"}`,
+			expectedStatusCode: http.StatusOK,
+			effectedAppSets:    []string{"git-github"},
+		},
+		{
+			desc:               "ApplicationSets",
+			token:              "token",
+			body:               `{"applicationSets": ["list", "other/list"]}`,
+			expectedStatusCode: http.StatusOK,
+			effectedAppSets:    []string{"list", "other/list"},
+		},
+		{
+			desc:               "unknown ApplicationSet",
+			token:              "token",
+			body:               `{"applicationSets": ["list", "unknown"]}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "invalid token",
+			token:              "other",
+			body:               `{"applicationSets": ["list"]}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			fc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				fakeAppWithGitGenerator("git-github", namespace, "https://github.com/org/repo"),
+				fakeAppWithGitGeneratorRevision("git-github-dev", namespace, "git@github.com:org/repo.git", "dev"),
+				fakeAppWithGitGenerator("git-github-other", namespace, "https://github.com/org/other"),
+				&argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "list", Namespace: namespace}},
+				&argoprojiov1alpha1.ApplicationSet{ObjectMeta: metav1.ObjectMeta{Name: "list", Namespace: "other"}},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "refresh-token",
+						Namespace: namespace,
+						Labels:    map[string]string{LabelKeyWebhookSecret: "refresh"},
+					},
+					Data: map[string][]byte{webhookSecretTokenKey: []byte("token")},
+				},
+			).Build()
+			set := argosettings.NewSettingsManager(context.TODO(), fakeClient, namespace)
+			h, err := NewWebhookHandler(namespace, set, fc, 0)
+			assert.Nil(t, err)
+
+			req := httptest.NewRequest("POST", "/api/webhook/refresh", bytes.NewReader([]byte(test.body)))
+			req.Header.Set("Authorization", "Bearer "+test.token)
+			w := httptest.NewRecorder()
+
+			h.RefreshHandler(w, req)
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			list := &argoprojiov1alpha1.ApplicationSetList{}
+			err = fc.List(context.TODO(), list)
+			assert.Nil(t, err)
+			for _, gotAppSet := range list.Items {
+				effected := false
+				for _, name := range test.effectedAppSets {
+					if name == gotAppSet.Name && gotAppSet.Namespace == namespace || name == gotAppSet.Namespace+"/"+gotAppSet.Name {
+						effected = true
+					}
+				}
+				assert.Equal(t, effected, gotAppSet.RefreshRequired(), gotAppSet.Namespace+"/"+gotAppSet.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// The webhook library doesn't support Gitea, so its events are parsed here. Forgejo sends the same events and headers
+// as Gitea.
+const (
+	giteaEventHeader     = "X-Gitea-Event"
+	giteaSignatureHeader = "X-Gitea-Signature"
+
+	giteaPushEvent        = "push"
+	giteaPullRequestEvent = "pull_request"
+)
+
+var (
+	errGiteaInvalidHTTPMethod      = errors.New("invalid HTTP Method")
+	errGiteaEventNotFound          = errors.New("event not defined to be parsed")
+	errGiteaParsingPayload         = errors.New("error parsing payload")
+	errGiteaMissingSignatureHeader = errors.New("missing X-Gitea-Signature Header")
+	errGiteaHMACVerificationFailed = errors.New("HMAC verification failed")
+)
+
+// giteaWebhook parses the push and pull request events of Gitea, and verifies their signature when a secret is
+// configured.
+type giteaWebhook struct {
+	secret string
+}
+
+// giteaRepository is the subset of the Gitea repository of the webhook payloads used to match the generators.
+type giteaRepository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	DefaultBranch string `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type giteaPushPayload struct {
+	Ref        string          `json:"ref"`
+	Repository giteaRepository `json:"repository"`
+}
+
+type giteaPullRequestPayload struct {
+	Action     string          `json:"action"`
+	Number     int             `json:"number"`
+	Repository giteaRepository `json:"repository"`
+}
+
+func newGiteaWebhook(secret string) *giteaWebhook {
+	return &giteaWebhook{secret: secret}
+}
+
+// Parse verifies and parses the push and pull request events, and returns a giteaPushPayload or a
+// giteaPullRequestPayload.
+func (hook *giteaWebhook) Parse(r *http.Request) (interface{}, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	if r.Method != http.MethodPost {
+		return nil, errGiteaInvalidHTTPMethod
+	}
+
+	event := r.Header.Get(giteaEventHeader)
+	if event != giteaPushEvent && event != giteaPullRequestEvent {
+		return nil, errGiteaEventNotFound
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, errGiteaParsingPayload
+	}
+
+	if hook.secret != "" {
+		signature := r.Header.Get(giteaSignatureHeader)
+		if signature == "" {
+			return nil, errGiteaMissingSignatureHeader
+		}
+		mac := hmac.New(sha256.New, []byte(hook.secret))
+		_, _ = mac.Write(body)
+		expectedMAC := hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(signature), []byte(expectedMAC)) {
+			return nil, errGiteaHMACVerificationFailed
+		}
+	}
+
+	switch event {
+	case giteaPushEvent:
+		var payload giteaPushPayload
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	default:
+		var payload giteaPullRequestPayload
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	}
+}
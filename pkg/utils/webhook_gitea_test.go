@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiteaWebhookParse(t *testing.T) {
+	eventJSON, err := ioutil.ReadFile(filepath.Join("testdata", "gitea-push-event.json"))
+	assert.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(eventJSON)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tt := []struct {
+		desc          string
+		method        string
+		event         string
+		signature     string
+		expectedError error
+	}{
+		{
+			desc:      "push event with a valid signature",
+			method:    "POST",
+			event:     "push",
+			signature: signature,
+		},
+		{
+			desc:          "push event with an invalid signature",
+			method:        "POST",
+			event:         "push",
+			signature:     "invalid",
+			expectedError: errGiteaHMACVerificationFailed,
+		},
+		{
+			desc:          "push event without signature",
+			method:        "POST",
+			event:         "push",
+			expectedError: errGiteaMissingSignatureHeader,
+		},
+		{
+			desc:          "unsupported event",
+			method:        "POST",
+			event:         "issues",
+			signature:     signature,
+			expectedError: errGiteaEventNotFound,
+		},
+		{
+			desc:          "invalid method",
+			method:        "GET",
+			event:         "push",
+			signature:     signature,
+			expectedError: errGiteaInvalidHTTPMethod,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/api/webhook", bytes.NewReader(eventJSON))
+			req.Header.Set(giteaEventHeader, test.event)
+			if test.signature != "" {
+				req.Header.Set(giteaSignatureHeader, test.signature)
+			}
+
+			payload, err := newGiteaWebhook("secret").Parse(req)
+			if test.expectedError != nil {
+				assert.Equal(t, test.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.IsType(t, giteaPushPayload{}, payload) {
+				push := payload.(giteaPushPayload)
+				assert.Equal(t, "refs/heads/main", push.Ref)
+				assert.Equal(t, "https://gitea.example.com/org/repo", push.Repository.HTMLURL)
+				assert.Equal(t, "main", push.Repository.DefaultBranch)
+				assert.Equal(t, "org", push.Repository.Owner.Login)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/go-playground/webhooks.v5/bitbucket"
+	bitbucketserver "gopkg.in/go-playground/webhooks.v5/bitbucket-server"
+	"gopkg.in/go-playground/webhooks.v5/github"
+	"gopkg.in/go-playground/webhooks.v5/gitlab"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelKeyWebhookSecret is the label of the Secrets holding the secrets of the webhooks. Its value is the provider
+// the secrets are used for: github, gitlab, bitbucket, bitbucketserver, gitea, azuredevops, or refresh for the tokens of
+// the refresh endpoint.
+const LabelKeyWebhookSecret = "argocd.argoproj.io/application-set-webhook"
+
+const (
+	webhookProviderGitHub          = "github"
+	webhookProviderGitLab          = "gitlab"
+	webhookProviderBitbucket       = "bitbucket"
+	webhookProviderBitbucketServer = "bitbucketserver"
+	webhookProviderGitea           = "gitea"
+	webhookProviderAzureDevOps     = "azuredevops"
+	webhookProviderRefresh         = "refresh"
+
+	// The keys of the webhook Secrets. The secret of Bitbucket Cloud is the UUID of the webhook, and Azure DevOps
+	// and the refresh endpoint use a basic authentication or a bearer token.
+	webhookSecretKey         = "secret"
+	webhookSecretUsernameKey = "username"
+	webhookSecretPasswordKey = "password"
+	webhookSecretTokenKey    = "token"
+)
+
+// webhookSecrets holds the secrets accepted for the events of each provider. Several secrets are accepted while they
+// are rotated, and the events of a provider without secrets are not verified.
+type webhookSecrets struct {
+	github          []string
+	gitlab          []string
+	bitbucket       []string
+	bitbucketServer []string
+	gitea           []string
+	azureDevOps     []azureDevOpsCredentials
+	// refresh holds the bearer tokens of the refresh endpoint, which rejects all the requests without tokens
+	refresh []string
+}
+
+// getWebhookSecrets returns the secrets configured in argocd-secret, and in the Secrets of the namespace labeled with
+// their provider. They are read from the caches of the clients at each event, so that the changes of the Secrets are
+// taken into account without restarting the controller.
+func (h *WebhookHandler) getWebhookSecrets(ctx context.Context) (*webhookSecrets, error) {
+	argocdSettings, err := h.argocdSettingsMgr.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("error getting argocd settings: %v", err)
+	}
+
+	secrets := &webhookSecrets{
+		github:          appendWebhookSecret(nil, argocdSettings.WebhookGitHubSecret),
+		gitlab:          appendWebhookSecret(nil, argocdSettings.WebhookGitLabSecret),
+		bitbucket:       appendWebhookSecret(nil, argocdSettings.WebhookBitbucketUUID),
+		bitbucketServer: appendWebhookSecret(nil, argocdSettings.WebhookBitbucketServerSecret),
+		// Argo CD configures the secret of the Gitea webhooks, which are compatible with the Gogs ones, as the Gogs secret
+		gitea: appendWebhookSecret(nil, argocdSettings.WebhookGogsSecret),
+		azureDevOps: appendAzureDevOpsCredentials(nil, azureDevOpsCredentials{
+			username: argocdSettings.Secrets[settingsWebhookAzureDevOpsUsernameKey],
+			password: argocdSettings.Secrets[settingsWebhookAzureDevOpsPasswordKey],
+			token:    argocdSettings.Secrets[settingsWebhookAzureDevOpsTokenKey],
+		}),
+		refresh: appendWebhookSecret(nil, argocdSettings.Secrets[settingsWebhookRefreshTokenKey]),
+	}
+
+	secretList := &corev1.SecretList{}
+	err = h.client.List(ctx, secretList, client.InNamespace(h.namespace), client.HasLabels{LabelKeyWebhookSecret})
+	if err != nil {
+		return nil, fmt.Errorf("error listing the webhook secrets: %v", err)
+	}
+	for _, secret := range secretList.Items {
+		value := string(secret.Data[webhookSecretKey])
+		switch provider := secret.Labels[LabelKeyWebhookSecret]; provider {
+		case webhookProviderGitHub:
+			secrets.github = appendWebhookSecret(secrets.github, value)
+		case webhookProviderGitLab:
+			secrets.gitlab = appendWebhookSecret(secrets.gitlab, value)
+		case webhookProviderBitbucket:
+			secrets.bitbucket = appendWebhookSecret(secrets.bitbucket, value)
+		case webhookProviderBitbucketServer:
+			secrets.bitbucketServer = appendWebhookSecret(secrets.bitbucketServer, value)
+		case webhookProviderGitea:
+			secrets.gitea = appendWebhookSecret(secrets.gitea, value)
+		case webhookProviderAzureDevOps:
+			secrets.azureDevOps = appendAzureDevOpsCredentials(secrets.azureDevOps, azureDevOpsCredentials{
+				username: string(secret.Data[webhookSecretUsernameKey]),
+				password: string(secret.Data[webhookSecretPasswordKey]),
+				token:    string(secret.Data[webhookSecretTokenKey]),
+			})
+		case webhookProviderRefresh:
+			secrets.refresh = appendWebhookSecret(secrets.refresh, string(secret.Data[webhookSecretTokenKey]))
+		default:
+			log.Warnf("Ignoring the webhook secret %s/%s of the unknown provider '%s'", secret.Namespace, secret.Name, provider)
+		}
+	}
+	return secrets, nil
+}
+
+func appendWebhookSecret(secrets []string, secret string) []string {
+	if secret == "" {
+		return secrets
+	}
+	return append(secrets, secret)
+}
+
+func appendAzureDevOpsCredentials(credentials []azureDevOpsCredentials, c azureDevOpsCredentials) []azureDevOpsCredentials {
+	if c.username == "" && c.password == "" && c.token == "" {
+		return credentials
+	}
+	return append(credentials, c)
+}
+
+// parseWithSecrets parses the request with each of the secrets until its verification succeeds, or without secret if
+// there are none.
+func parseWithSecrets(r *http.Request, secrets []string, parse func(r *http.Request, secret string) (interface{}, error)) (interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading payload: %v", err)
+	}
+	if len(secrets) == 0 {
+		secrets = []string{""}
+	}
+
+	var payload interface{}
+	for _, secret := range secrets {
+		req := r.Clone(r.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		payload, err = parse(req, secret)
+		if err == nil || !isVerificationError(err) {
+			return payload, err
+		}
+	}
+	return nil, err
+}
+
+// isVerificationError returns true if the error is due to a secret which doesn't match the one of the event.
+func isVerificationError(err error) bool {
+	switch err {
+	case github.ErrHMACVerificationFailed, gitlab.ErrGitLabTokenVerificationFailed, bitbucket.ErrUUIDVerificationFailed,
+		bitbucketserver.ErrHMACVerificationFailed, errGiteaHMACVerificationFailed:
+		return true
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/argoproj-labs/applicationset/api/v1alpha1"
@@ -19,6 +20,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -28,6 +30,7 @@ func TestWebhookHandler(t *testing.T) {
 		desc               string
 		headerKey          string
 		headerValue        string
+		headers            map[string]string
 		effectedAppSets    []string
 		payloadFile        string
 		expectedStatusCode int
@@ -38,7 +41,7 @@ func TestWebhookHandler(t *testing.T) {
 			headerKey:          "X-GitHub-Event",
 			headerValue:        "push",
 			payloadFile:        "github-commit-event.json",
-			effectedAppSets:    []string{"git-github"},
+			effectedAppSets:    []string{"git-github", "matrix-git-github", "scm-provider-github"},
 			expectedStatusCode: http.StatusOK,
 			expectedRefresh:    true,
 		},
@@ -47,7 +50,64 @@ func TestWebhookHandler(t *testing.T) {
 			headerKey:          "X-Gitlab-Event",
 			headerValue:        "Push Hook",
 			payloadFile:        "gitlab-event.json",
-			effectedAppSets:    []string{"git-gitlab"},
+			effectedAppSets:    []string{"git-gitlab", "scm-provider-gitlab", "scm-provider-gitlab-subgroups"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from a Bitbucket Cloud repository via Commit",
+			headerKey:          "X-Event-Key",
+			headerValue:        "repo:push",
+			headers:            map[string]string{"X-Hook-UUID": "{6b3b0a4e-2d4d-4b1f-8c5f-0d7e2b6c1a3f}"},
+			payloadFile:        "bitbucket-push-event.json",
+			effectedAppSets:    []string{"git-bitbucket"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from a Bitbucket Server repository via Commit",
+			headerKey:          "X-Event-Key",
+			headerValue:        "repo:refs_changed",
+			payloadFile:        "bitbucket-server-event.json",
+			effectedAppSets:    []string{"git-bitbucket-server-ssh", "git-bitbucket-server-http"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from a Gitea repository via Commit",
+			headerKey:          "X-Gitea-Event",
+			headerValue:        "push",
+			headers:            map[string]string{"X-GitHub-Event": "push"},
+			payloadFile:        "gitea-push-event.json",
+			effectedAppSets:    []string{"git-gitea"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from a Gitea repository via pull_request opened event",
+			headerKey:          "X-Gitea-Event",
+			headerValue:        "pull_request",
+			headers:            map[string]string{"X-GitHub-Event": "pull_request"},
+			payloadFile:        "gitea-pull-request-opened-event.json",
+			effectedAppSets:    []string{"pull-request-gitea"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from an Azure DevOps repository via Commit",
+			headerKey:          "X-Vss-ActivityId",
+			headerValue:        "3a7c2e1f-8f4b-4f0a-9d6e-2b1c0a9f8e7d",
+			payloadFile:        "azuredevops-push-event.json",
+			effectedAppSets:    []string{"git-azuredevops"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from an Azure DevOps repository via pull request created event",
+			headerKey:          "X-Vss-ActivityId",
+			headerValue:        "3a7c2e1f-8f4b-4f0a-9d6e-2b1c0a9f8e7d",
+			payloadFile:        "azuredevops-pull-request-created-event.json",
+			effectedAppSets:    []string{"pull-request-azuredevops"},
 			expectedStatusCode: http.StatusOK,
 			expectedRefresh:    true,
 		},
@@ -74,7 +134,7 @@ func TestWebhookHandler(t *testing.T) {
 			headerKey:          "X-GitHub-Event",
 			headerValue:        "pull_request",
 			payloadFile:        "github-pull-request-opened-event.json",
-			effectedAppSets:    []string{"pull-request-github"},
+			effectedAppSets:    []string{"pull-request-github", "matrix-pull-request-github"},
 			expectedStatusCode: http.StatusOK,
 			expectedRefresh:    true,
 		},
@@ -87,6 +147,24 @@ func TestWebhookHandler(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedRefresh:    false,
 		},
+		{
+			desc:               "WebHook from a GitLab repository via merge request open event",
+			headerKey:          "X-Gitlab-Event",
+			headerValue:        "Merge Request Hook",
+			payloadFile:        "gitlab-merge-request-open-event.json",
+			effectedAppSets:    []string{"pull-request-gitlab"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "WebHook from a GitLab repository via merge request approved event",
+			headerKey:          "X-Gitlab-Event",
+			headerValue:        "Merge Request Hook",
+			payloadFile:        "gitlab-merge-request-approved-event.json",
+			effectedAppSets:    []string{"pull-request-gitlab"},
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    false,
+		},
 	}
 
 	namespace := "test"
@@ -96,20 +174,43 @@ func TestWebhookHandler(t *testing.T) {
 	assert.Nil(t, err)
 	err = argov1alpha1.AddToScheme(scheme)
 	assert.Nil(t, err)
+	err = corev1.AddToScheme(scheme)
+	assert.Nil(t, err)
 
 	for _, test := range tt {
 		t.Run(test.desc, func(t *testing.T) {
 			fc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
 				fakeAppWithGitGenerator("git-github", namespace, "https://github.com/org/repo"),
 				fakeAppWithGitGenerator("git-gitlab", namespace, "https://gitlab/group/name"),
+				fakeAppWithGitGenerator("git-bitbucket", namespace, "git@bitbucket.org:org/repo.git"),
+				fakeAppWithGitGenerator("git-bitbucket-server-ssh", namespace, "ssh://git@bitbucketserver.example.com:7999/proj/repo.git"),
+				fakeAppWithGitGenerator("git-bitbucket-server-http", namespace, "https://bitbucketserver.example.com/scm/proj/repo.git"),
+				fakeAppWithGitGenerator("git-gitea", namespace, "git@gitea.example.com:org/repo.git"),
+				fakeAppWithGiteaPullRequestGenerator("pull-request-gitea", namespace, "https://gitea.example.com/", "org", "repo"),
+				fakeAppWithGiteaPullRequestGenerator("pull-request-gitea-other", namespace, "https://gitea.other.com/", "org", "repo"),
+				fakeAppWithGitGenerator("git-azuredevops", namespace, "git@ssh.dev.azure.com:v3/org/project/repo"),
+				fakeAppWithAzureDevOpsPullRequestGenerator("pull-request-azuredevops", namespace, "org", "project", "repo"),
+				fakeAppWithAzureDevOpsPullRequestGenerator("pull-request-azuredevops-other", namespace, "org", "project", "other"),
+				fakeAppWithGitGeneratorRevision("git-github-dev", namespace, "https://github.com/org/repo", "dev"),
+				fakeAppWithMatrixGitGenerator("matrix-git-github", namespace, "https://github.com/org/repo"),
+				fakeAppWithSCMProviderGenerator("scm-provider-github", namespace, "Org", false),
+				fakeAppWithSCMProviderGenerator("scm-provider-github-other", namespace, "other", true),
+				fakeAppWithGitlabSCMProviderGenerator("scm-provider-gitlab", namespace, "https://gitlab", "group", false),
+				fakeAppWithGitlabSCMProviderGenerator("scm-provider-gitlab-subgroups", namespace, "https://gitlab/", "group", true),
+				fakeAppWithGitlabSCMProviderGenerator("scm-provider-gitlab-other", namespace, "https://gitlab.example.com", "group", false),
 				fakeAppWithPullRequestGenerator("pull-request-github", namespace, "Codertocat", "Hello-World"),
+				fakeAppWithGitlabPullRequestGenerator("pull-request-gitlab", namespace, "https://gitlab.example.com", "gitlabhq/gitlab-test"),
+				fakeAppWithMatrixPullRequestGenerator("matrix-pull-request-github", namespace, "Codertocat", "Hello-World"),
 			).Build()
 			set := argosettings.NewSettingsManager(context.TODO(), fakeClient, namespace)
-			h, err := NewWebhookHandler(namespace, set, fc)
+			h, err := NewWebhookHandler(namespace, set, fc, 0)
 			assert.Nil(t, err)
 
 			req := httptest.NewRequest("POST", "/api/webhook", nil)
 			req.Header.Set(test.headerKey, test.headerValue)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
 			eventJSON, err := ioutil.ReadFile(filepath.Join("testdata", test.payloadFile))
 			assert.NoError(t, err)
 			req.Body = ioutil.NopCloser(bytes.NewReader(eventJSON))
@@ -123,20 +224,105 @@ func TestWebhookHandler(t *testing.T) {
 			assert.Nil(t, err)
 			for i := range list.Items {
 				gotAppSet := &list.Items[i]
+				effected := false
 				for _, appSetName := range test.effectedAppSets {
 					if appSetName == gotAppSet.Name {
-						if expected, got := test.expectedRefresh, gotAppSet.RefreshRequired(); expected != got {
-							t.Errorf("unexpected RefreshRequired() expect: %v got: %v", expected, got)
-						}
-					} else {
-						assert.False(t, gotAppSet.RefreshRequired())
+						effected = true
+					}
+				}
+				if effected {
+					if expected, got := test.expectedRefresh, gotAppSet.RefreshRequired(); expected != got {
+						t.Errorf("unexpected RefreshRequired() for %s expect: %v got: %v", gotAppSet.Name, expected, got)
 					}
+				} else {
+					assert.False(t, gotAppSet.RefreshRequired(), gotAppSet.Name)
 				}
 			}
 		})
 	}
 }
 
+func TestWebhookHandlerSecrets(t *testing.T) {
+	namespace := "test"
+	fakeClient := newFakeClient(namespace)
+	scheme := runtime.NewScheme()
+	err := argoprojiov1alpha1.AddToScheme(scheme)
+	assert.Nil(t, err)
+	err = corev1.AddToScheme(scheme)
+	assert.Nil(t, err)
+
+	webhookSecret := func(name, provider, secret string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{LabelKeyWebhookSecret: provider},
+			},
+			Data: map[string][]byte{webhookSecretKey: []byte(secret)},
+		}
+	}
+
+	tt := []struct {
+		desc               string
+		token              string
+		expectedStatusCode int
+		expectedRefresh    bool
+	}{
+		{
+			desc:               "current secret",
+			token:              "current",
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "rotated secret",
+			token:              "next",
+			expectedStatusCode: http.StatusOK,
+			expectedRefresh:    true,
+		},
+		{
+			desc:               "secret of another provider",
+			token:              "github",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "missing secret",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			fc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				fakeAppWithGitGenerator("git-gitlab", namespace, "https://gitlab/group/name"),
+				webhookSecret("gitlab-current", "gitlab", "current"),
+				webhookSecret("gitlab-next", "gitlab", "next"),
+				webhookSecret("github", "github", "github"),
+			).Build()
+			set := argosettings.NewSettingsManager(context.TODO(), fakeClient, namespace)
+			h, err := NewWebhookHandler(namespace, set, fc, 0)
+			assert.Nil(t, err)
+
+			eventJSON, err := ioutil.ReadFile(filepath.Join("testdata", "gitlab-event.json"))
+			assert.NoError(t, err)
+			req := httptest.NewRequest("POST", "/api/webhook", bytes.NewReader(eventJSON))
+			req.Header.Set("X-Gitlab-Event", "Push Hook")
+			if test.token != "" {
+				req.Header.Set("X-Gitlab-Token", test.token)
+			}
+			w := httptest.NewRecorder()
+
+			h.Handler(w, req)
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			gotAppSet := &argoprojiov1alpha1.ApplicationSet{}
+			err = fc.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "git-gitlab"}, gotAppSet)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRefresh, gotAppSet.RefreshRequired())
+		})
+	}
+}
+
 func TestGenRevisionHasChanged(t *testing.T) {
 	assert.True(t, genRevisionHasChanged(&v1alpha1.GitGenerator{}, "master", true))
 	assert.False(t, genRevisionHasChanged(&v1alpha1.GitGenerator{}, "master", false))
@@ -148,7 +334,47 @@ func TestGenRevisionHasChanged(t *testing.T) {
 	assert.False(t, genRevisionHasChanged(&v1alpha1.GitGenerator{Revision: "refs/heads/dev"}, "master", false))
 }
 
+func TestShouldRefreshGithubSCMProviderGenerator(t *testing.T) {
+	info := &scmProviderGeneratorGithubInfo{
+		Organization: "org",
+		TouchedHead:  true,
+		APIRegexp:    regexp.MustCompile(`(?i)(http://|https://)(api\.)?github\.com(:[0-9]+|)([:/]|$)`),
+	}
+	assert.True(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "org"}, info))
+	assert.True(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "Org", API: "https://api.github.com/"}, info))
+	assert.False(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "other"}, info))
+	assert.False(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "org", API: "https://ghe.example.com/api/v3"}, info))
+	assert.False(t, shouldRefreshGithubSCMProviderGenerator(nil, info))
+
+	// a push to another branch than the default branch is only relevant to the generators discovering all the branches
+	info.TouchedHead = false
+	assert.False(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "org"}, info))
+	assert.True(t, shouldRefreshGithubSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGithub{Organization: "org", AllBranches: true}, info))
+}
+
+func TestShouldRefreshGitlabSCMProviderGenerator(t *testing.T) {
+	info := &scmProviderGeneratorGitlabInfo{
+		Namespace:   "group/subgroup",
+		TouchedHead: true,
+		APIRegexp:   regexp.MustCompile(`(?i)(http://|https://)gitlab\.com(:[0-9]+|)([:/]|$)`),
+	}
+	assert.True(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "group/subgroup"}, info))
+	assert.True(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "Group", IncludeSubgroups: true, API: "https://gitlab.com"}, info))
+	assert.False(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "group"}, info))
+	assert.False(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "gro", IncludeSubgroups: true}, info))
+	assert.False(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "group/subgroup", API: "https://gitlab.example.com"}, info))
+	assert.False(t, shouldRefreshGitlabSCMProviderGenerator(nil, info))
+
+	info.TouchedHead = false
+	assert.False(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "group/subgroup"}, info))
+	assert.True(t, shouldRefreshGitlabSCMProviderGenerator(&v1alpha1.SCMProviderGeneratorGitlab{Group: "group/subgroup", AllBranches: true}, info))
+}
+
 func fakeAppWithGitGenerator(name, namespace, repo string) *argoprojiov1alpha1.ApplicationSet {
+	return fakeAppWithGitGeneratorRevision(name, namespace, repo, "")
+}
+
+func fakeAppWithGitGeneratorRevision(name, namespace, repo, revision string) *argoprojiov1alpha1.ApplicationSet {
 	return &argoprojiov1alpha1.ApplicationSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -158,7 +384,8 @@ func fakeAppWithGitGenerator(name, namespace, repo string) *argoprojiov1alpha1.A
 			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
 				{
 					Git: &argoprojiov1alpha1.GitGenerator{
-						RepoURL: repo,
+						RepoURL:  repo,
+						Revision: revision,
 					},
 				},
 			},
@@ -187,6 +414,171 @@ func fakeAppWithPullRequestGenerator(name, namespace, owner, repo string) *argop
 	}
 }
 
+func fakeAppWithGiteaPullRequestGenerator(name, namespace, api, owner, repo string) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+						Gitea: &argoprojiov1alpha1.PullRequestGeneratorGitea{
+							API:   api,
+							Owner: owner,
+							Repo:  repo,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithAzureDevOpsPullRequestGenerator(name, namespace, organization, project, repo string) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+						AzureDevOps: &argoprojiov1alpha1.PullRequestGeneratorAzureDevOps{
+							Organization: organization,
+							Project:      project,
+							Repo:         repo,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithGitlabPullRequestGenerator(name, namespace, api, project string) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+						GitLab: &argoprojiov1alpha1.PullRequestGeneratorGitLab{
+							API:     api,
+							Project: project,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithMatrixPullRequestGenerator(name, namespace, owner, repo string) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					Matrix: &argoprojiov1alpha1.MatrixGenerator{
+						Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+							{
+								Clusters: &argoprojiov1alpha1.ClusterGenerator{},
+							},
+							{
+								PullRequest: &argoprojiov1alpha1.PullRequestGenerator{
+									Github: &argoprojiov1alpha1.PullRequestGeneratorGithub{
+										Owner: owner,
+										Repo:  repo,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithMatrixGitGenerator(name, namespace, repo string) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					Matrix: &argoprojiov1alpha1.MatrixGenerator{
+						Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+							{
+								Clusters: &argoprojiov1alpha1.ClusterGenerator{},
+							},
+							{
+								Git: &argoprojiov1alpha1.GitGenerator{
+									RepoURL: repo,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithSCMProviderGenerator(name, namespace, organization string, allBranches bool) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{
+						Github: &argoprojiov1alpha1.SCMProviderGeneratorGithub{
+							Organization: organization,
+							AllBranches:  allBranches,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeAppWithGitlabSCMProviderGenerator(name, namespace, api, group string, includeSubgroups bool) *argoprojiov1alpha1.ApplicationSet {
+	return &argoprojiov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{
+					SCMProvider: &argoprojiov1alpha1.SCMProviderGenerator{
+						Gitlab: &argoprojiov1alpha1.SCMProviderGeneratorGitlab{
+							API:              api,
+							Group:            group,
+							IncludeSubgroups: includeSubgroups,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func newFakeClient(ns string) *kubefake.Clientset {
 	s := runtime.NewScheme()
 	s.AddKnownTypes(argoprojiov1alpha1.GroupVersion, &argoprojiov1alpha1.ApplicationSet{})
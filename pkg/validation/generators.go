@@ -0,0 +1,210 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validateGenerators checks that each generator of the ApplicationSet, and each child generator of the Matrix, Merge
+// and Union generators, sets exactly one generator, and that the combination-type generators have enough children and
+// valid keys.
+func validateGenerators(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "generators")
+	if len(appSet.Spec.Generators) == 0 {
+		errs = append(errs, field.Required(path, "at least one generator is required"))
+	}
+	for i, generator := range appSet.Spec.Generators {
+		genPath := path.Index(i)
+		errs = append(errs, validateGeneratorFields(&generator, genPath)...)
+		switch {
+		case generator.Matrix != nil:
+			errs = append(errs, validateMatrix(len(generator.Matrix.Generators), genPath.Child("matrix"))...)
+			errs = append(errs, validateNestedGenerators(generator.Matrix.Generators, genPath.Child("matrix", "generators"))...)
+		case generator.Merge != nil:
+			errs = append(errs, validateMerge(generator.Merge.MergeKeys, nestedParams(generator.Merge.Generators), len(generator.Merge.Generators), genPath.Child("merge"))...)
+			errs = append(errs, validateNestedGenerators(generator.Merge.Generators, genPath.Child("merge", "generators"))...)
+		case generator.Union != nil:
+			errs = append(errs, validateUnion(generator.Union.DeduplicationKeys, len(generator.Union.Generators), genPath.Child("union"))...)
+			errs = append(errs, validateNestedGenerators(generator.Union.Generators, genPath.Child("union", "generators"))...)
+		}
+	}
+	return errs
+}
+
+func validateNestedGenerators(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, generator := range generators {
+		genPath := path.Index(i)
+		errs = append(errs, validateGeneratorFields(&generator, genPath)...)
+		switch {
+		case generator.Matrix != nil:
+			errs = append(errs, validateMatrix(len(generator.Matrix.Generators), genPath.Child("matrix"))...)
+			errs = append(errs, validateTerminalGenerators(generator.Matrix.Generators, genPath.Child("matrix", "generators"))...)
+		case generator.Merge != nil:
+			errs = append(errs, validateMerge(generator.Merge.MergeKeys, terminalParams(generator.Merge.Generators), len(generator.Merge.Generators), genPath.Child("merge"))...)
+			errs = append(errs, validateTerminalGenerators(generator.Merge.Generators, genPath.Child("merge", "generators"))...)
+		case generator.Union != nil:
+			errs = append(errs, validateUnion(generator.Union.DeduplicationKeys, len(generator.Union.Generators), genPath.Child("union"))...)
+			errs = append(errs, validateTerminalGenerators(generator.Union.Generators, genPath.Child("union", "generators"))...)
+		}
+	}
+	return errs
+}
+
+func validateTerminalGenerators(generators []argoprojiov1alpha1.ApplicationSetTerminalGenerator, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, generator := range generators {
+		errs = append(errs, validateGeneratorFields(&generator, path.Index(i))...)
+	}
+	return errs
+}
+
+// validateGeneratorFields checks that exactly one generator field is set on a generator.
+func validateGeneratorFields(generator interface{}, path *field.Path) field.ErrorList {
+	names := generatorNames(generator)
+	switch len(names) {
+	case 0:
+		return field.ErrorList{field.Required(path, "a generator is required")}
+	case 1:
+		return nil
+	default:
+		return field.ErrorList{field.Invalid(path, strings.Join(names, ", "), "exactly one generator may be set")}
+	}
+}
+
+// generatorNames returns the names of the generator fields set on a generator, i.e. its pointer fields other than the
+// schedule common to all the generators.
+func generatorNames(generator interface{}) []string {
+	var names []string
+	v := reflect.Indirect(reflect.ValueOf(generator))
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Name == "Schedule" || f.Type.Kind() != reflect.Ptr || v.Field(i).IsNil() {
+			continue
+		}
+		names = append(names, strings.Split(f.Tag.Get("json"), ",")[0])
+	}
+	return names
+}
+
+func validateMatrix(children int, path *field.Path) field.ErrorList {
+	if children != 2 {
+		return field.ErrorList{field.Invalid(path.Child("generators"), children, "a Matrix generator requires exactly two generators")}
+	}
+	return nil
+}
+
+// validateMerge checks the merge keys of a Merge generator, which must be params of its base generator when they are
+// known.
+func validateMerge(mergeKeys []string, params []*paramSet, children int, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if children < 2 {
+		errs = append(errs, field.Invalid(path.Child("generators"), children, "a Merge generator requires two or more generators"))
+	}
+	keysPath := path.Child("mergeKeys")
+	if len(mergeKeys) == 0 {
+		errs = append(errs, field.Required(keysPath, "at least one merge key is required"))
+	}
+	errs = append(errs, validateKeys(mergeKeys, keysPath)...)
+	if len(params) > 0 && params[0] != nil {
+		for i, key := range mergeKeys {
+			if key != "" && !params[0].has(key) {
+				errs = append(errs, field.Invalid(keysPath.Index(i), key, "not a param of the first generator of the Merge generator"))
+			}
+		}
+	}
+	return errs
+}
+
+func validateUnion(deduplicationKeys []string, children int, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if children < 2 {
+		errs = append(errs, field.Invalid(path.Child("generators"), children, "a Union generator requires two or more generators"))
+	}
+	return append(errs, validateKeys(deduplicationKeys, path.Child("deduplicationKeys"))...)
+}
+
+// validateKeys checks that the keys are neither empty nor duplicated.
+func validateKeys(keys []string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, key := range keys {
+		switch {
+		case strings.TrimSpace(key) == "":
+			errs = append(errs, field.Invalid(path.Index(i), key, "must not be empty"))
+		case seen[key]:
+			errs = append(errs, field.Duplicate(path.Index(i), key))
+		}
+		seen[key] = true
+	}
+	return errs
+}
+
+// generatorDescription returns the path and the generator set on a generator, e.g. spec.generators[0] (list), for the
+// error messages.
+func generatorDescription(generator interface{}, path *field.Path) string {
+	return fmt.Sprintf("%s (%s)", path.String(), strings.Join(generatorNames(generator), ", "))
+}
+
+// validateDisabledGenerators rejects the generators disabled by the controller, including the generators nested in
+// Matrix, Merge and Union generators.
+func (v *ApplicationSetValidator) validateDisabledGenerators(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	if len(v.DisabledGenerators) == 0 {
+		return nil
+	}
+
+	var errs field.ErrorList
+	path := field.NewPath("spec", "generators")
+	for i, generator := range appSet.Spec.Generators {
+		genPath := path.Index(i)
+		errs = append(errs, v.disabledGeneratorErrors(&generator, genPath)...)
+		var children []argoprojiov1alpha1.ApplicationSetNestedGenerator
+		var childrenPath *field.Path
+		switch {
+		case generator.Matrix != nil:
+			children, childrenPath = generator.Matrix.Generators, genPath.Child("matrix", "generators")
+		case generator.Merge != nil:
+			children, childrenPath = generator.Merge.Generators, genPath.Child("merge", "generators")
+		case generator.Union != nil:
+			children, childrenPath = generator.Union.Generators, genPath.Child("union", "generators")
+		}
+		for j, child := range children {
+			childPath := childrenPath.Index(j)
+			errs = append(errs, v.disabledGeneratorErrors(&child, childPath)...)
+			var terminals []argoprojiov1alpha1.ApplicationSetTerminalGenerator
+			var terminalsPath *field.Path
+			switch {
+			case child.Matrix != nil:
+				terminals, terminalsPath = child.Matrix.Generators, childPath.Child("matrix", "generators")
+			case child.Merge != nil:
+				terminals, terminalsPath = child.Merge.Generators, childPath.Child("merge", "generators")
+			case child.Union != nil:
+				terminals, terminalsPath = child.Union.Generators, childPath.Child("union", "generators")
+			}
+			for k, terminal := range terminals {
+				errs = append(errs, v.disabledGeneratorErrors(&terminal, terminalsPath.Index(k))...)
+			}
+		}
+	}
+	return errs
+}
+
+// disabledGeneratorErrors returns the errors of the disabled generator fields set on a generator.
+func (v *ApplicationSetValidator) disabledGeneratorErrors(generator interface{}, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	val := reflect.Indirect(reflect.ValueOf(generator))
+	for i := 0; i < val.NumField(); i++ {
+		f := val.Type().Field(i)
+		if f.Type.Kind() != reflect.Ptr || val.Field(i).IsNil() || !v.DisabledGenerators[f.Name] {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		errs = append(errs, field.Forbidden(path.Child(name), fmt.Sprintf("the %s generator is disabled by the ApplicationSet controller", name)))
+	}
+	return errs
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func listGenerator(elements ...string) *argoprojiov1alpha1.ListGenerator {
+	list := &argoprojiov1alpha1.ListGenerator{}
+	for _, element := range elements {
+		list.Elements = append(list.Elements, apiextensionsv1.JSON{Raw: []byte(element)})
+	}
+	return list
+}
+
+func TestValidateGenerators(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		generators     []argoprojiov1alpha1.ApplicationSetGenerator
+		expectedErrors []string
+	}{
+		{
+			name: "valid generators",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{List: listGenerator(`{"cluster": "a"}`)},
+				{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+					{Merge: &argoprojiov1alpha1.NestedMergeGenerator{
+						Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
+							{List: listGenerator(`{"cluster": "a"}`)},
+							{List: listGenerator(`{"cluster": "a", "replicas": "2"}`)},
+						},
+						MergeKeys: []string{"cluster"},
+					}},
+				}}},
+			},
+		},
+		{
+			name:           "no generator",
+			expectedErrors: []string{"spec.generators: Required value: at least one generator is required"},
+		},
+		{
+			name: "several generators",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{List: listGenerator(), Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+				{},
+			},
+			expectedErrors: []string{
+				`spec.generators[0]: Invalid value: "list, clusters": exactly one generator may be set`,
+				"spec.generators[1]: Required value: a generator is required",
+			},
+		},
+		{
+			name: "a generator with a schedule",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{List: listGenerator(), Schedule: &argoprojiov1alpha1.GeneratorSchedule{}},
+			},
+		},
+		{
+			name: "Matrix generator with three generators",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					{List: listGenerator()}, {List: listGenerator()}, {List: listGenerator(), Git: &argoprojiov1alpha1.GitGenerator{}},
+				}}},
+			},
+			expectedErrors: []string{
+				"spec.generators[0].matrix.generators: Invalid value: 3: a Matrix generator requires exactly two generators",
+				`spec.generators[0].matrix.generators[2]: Invalid value: "list, git": exactly one generator may be set`,
+			},
+		},
+		{
+			name: "nested Matrix generator with one generator",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Union: &argoprojiov1alpha1.UnionGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					{List: listGenerator()},
+					{Matrix: &argoprojiov1alpha1.NestedMatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{{}}}},
+				}}},
+			},
+			expectedErrors: []string{
+				"spec.generators[0].union.generators[1].matrix.generators: Invalid value: 1: a Matrix generator requires exactly two generators",
+				"spec.generators[0].union.generators[1].matrix.generators[0]: Required value: a generator is required",
+			},
+		},
+		{
+			name: "Merge generator without merge keys",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Merge: &argoprojiov1alpha1.MergeGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					{List: listGenerator()},
+				}}},
+			},
+			expectedErrors: []string{
+				"spec.generators[0].merge.generators: Invalid value: 1: a Merge generator requires two or more generators",
+				"spec.generators[0].merge.mergeKeys: Required value: at least one merge key is required",
+			},
+		},
+		{
+			name: "Merge generator with invalid merge keys",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Merge: &argoprojiov1alpha1.MergeGenerator{
+					Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+						{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+						{List: listGenerator(`{"server": "https://kubernetes.default.svc"}`)},
+					},
+					MergeKeys: []string{"server", "", "server", "metadata.labels.env", "cluster"},
+				}},
+			},
+			expectedErrors: []string{
+				`spec.generators[0].merge.mergeKeys[1]: Invalid value: "": must not be empty`,
+				`spec.generators[0].merge.mergeKeys[2]: Duplicate value: "server"`,
+				`spec.generators[0].merge.mergeKeys[4]: Invalid value: "cluster": not a param of the first generator of the Merge generator`,
+			},
+		},
+		{
+			name: "Merge generator whose base generator has unknown params",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Merge: &argoprojiov1alpha1.MergeGenerator{
+					Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+						{Git: &argoprojiov1alpha1.GitGenerator{Files: []argoprojiov1alpha1.GitFileGeneratorItem{{Path: "config.json"}}}},
+						{List: listGenerator()},
+					},
+					MergeKeys: []string{"cluster"},
+				}},
+			},
+		},
+		{
+			name: "Union generator with duplicate deduplication keys",
+			generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+				{Union: &argoprojiov1alpha1.UnionGenerator{
+					Generators:        []argoprojiov1alpha1.ApplicationSetNestedGenerator{{List: listGenerator()}, {List: listGenerator()}},
+					DeduplicationKeys: []string{"cluster", "cluster"},
+				}},
+			},
+			expectedErrors: []string{`spec.generators[0].union.deduplicationKeys[1]: Duplicate value: "cluster"`},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{Generators: c.generators}}
+
+			var got []string
+			for _, err := range validateGenerators(appSet) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, c.expectedErrors, got)
+		})
+	}
+}
+
+func TestValidateDisabledGenerators(t *testing.T) {
+	appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{
+		Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+			{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+			{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+				{List: listGenerator(`{"cluster": "a"}`)},
+				{Merge: &argoprojiov1alpha1.NestedMergeGenerator{
+					Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
+						{List: listGenerator(`{"cluster": "a"}`)},
+						{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+					},
+					MergeKeys: []string{"cluster"},
+				}},
+			}}},
+		},
+	}}
+
+	validator := NewApplicationSetValidator(Options{DisabledGenerators: map[string]bool{"Clusters": true}})
+	var errs []string
+	for _, err := range validator.validateDisabledGenerators(appSet) {
+		errs = append(errs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"spec.generators[0].clusters: Forbidden: the clusters generator is disabled by the ApplicationSet controller",
+		"spec.generators[1].matrix.generators[1].merge.generators[1].clusters: Forbidden: the clusters generator is disabled by the ApplicationSet controller",
+	}, errs)
+
+	assert.Empty(t, NewApplicationSetValidator(Options{DisabledGenerators: map[string]bool{"HTTP": true}}).validateDisabledGenerators(appSet))
+	assert.Empty(t, NewApplicationSetValidator(Options{}).validateDisabledGenerators(appSet))
+}
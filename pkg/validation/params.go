@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// paramReferenceRegexp matches the references to params with the {{param}} syntax.
+var paramReferenceRegexp = regexp.MustCompile(`{{([^{}]*)}}`)
+
+// paramSet are the params a generator is known to generate: the params with these names, and the params whose name
+// starts with one of the prefixes, e.g. the labels of the clusters. A nil paramSet stands for a generator whose params
+// aren't known before it runs, e.g. the params read from the files of a Git repository.
+type paramSet struct {
+	names    map[string]bool
+	prefixes []string
+}
+
+func newParamSet(names ...string) *paramSet {
+	p := &paramSet{names: map[string]bool{}}
+	for _, name := range names {
+		p.names[name] = true
+	}
+	return p
+}
+
+func (p *paramSet) has(name string) bool {
+	if p.names[name] {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unionParams returns the params generated by any of the generators, or nil if the params of one of them aren't known.
+func unionParams(params []*paramSet) *paramSet {
+	res := newParamSet()
+	for _, p := range params {
+		if p == nil {
+			return nil
+		}
+		for name := range p.names {
+			res.names[name] = true
+		}
+		res.prefixes = append(res.prefixes, p.prefixes...)
+	}
+	return res
+}
+
+// leafParams returns the params of the List, Cluster and Git directory generators, which are known without running
+// them, or nil for the other generators.
+func leafParams(list *argoprojiov1alpha1.ListGenerator, clusters *argoprojiov1alpha1.ClusterGenerator, git *argoprojiov1alpha1.GitGenerator) *paramSet {
+	switch {
+	case list != nil:
+		params := newParamSet()
+		for _, element := range list.Elements {
+			var object map[string]interface{}
+			if err := json.Unmarshal(element.Raw, &object); err != nil {
+				return nil
+			}
+			for key, value := range object {
+				values, ok := value.(map[string]interface{})
+				if key != "values" || !ok {
+					params.names[key] = true
+					continue
+				}
+				for k := range values {
+					params.names["values."+k] = true
+				}
+			}
+		}
+		return params
+	case clusters != nil:
+		params := newParamSet("name", "nameNormalized", "server")
+		params.prefixes = []string{"metadata.annotations.", "metadata.labels."}
+		for key := range clusters.Values {
+			params.names["values."+key] = true
+		}
+		return params
+	case git != nil && len(git.Files) == 0:
+		params := newParamSet("path", "path.basename", "path.basenameNormalized")
+		params.prefixes = []string{"path["}
+		return params
+	}
+	return nil
+}
+
+func generatorParams(generator *argoprojiov1alpha1.ApplicationSetGenerator) *paramSet {
+	switch {
+	case generator.Matrix != nil:
+		return unionParams(nestedParams(generator.Matrix.Generators))
+	case generator.Merge != nil:
+		return unionParams(nestedParams(generator.Merge.Generators))
+	case generator.Union != nil:
+		return unionParams(nestedParams(generator.Union.Generators))
+	}
+	return leafParams(generator.List, generator.Clusters, generator.Git)
+}
+
+func nestedParams(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator) []*paramSet {
+	res := make([]*paramSet, len(generators))
+	for i, generator := range generators {
+		switch {
+		case generator.Matrix != nil:
+			res[i] = unionParams(terminalParams(generator.Matrix.Generators))
+		case generator.Merge != nil:
+			res[i] = unionParams(terminalParams(generator.Merge.Generators))
+		case generator.Union != nil:
+			res[i] = unionParams(terminalParams(generator.Union.Generators))
+		default:
+			res[i] = leafParams(generator.List, generator.Clusters, generator.Git)
+		}
+	}
+	return res
+}
+
+func terminalParams(generators []argoprojiov1alpha1.ApplicationSetTerminalGenerator) []*paramSet {
+	res := make([]*paramSet, len(generators))
+	for i, generator := range generators {
+		res[i] = leafParams(generator.List, generator.Clusters, generator.Git)
+	}
+	return res
+}
+
+// validateTemplateParams checks that the {{param}} references of the templates are params generated by each generator
+// whose params are known. The Go templates, and the ApplicationSets transforming their params, aren't checked.
+func validateTemplateParams(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	if appSet.Spec.GoTemplate || len(appSet.Spec.ParamTransforms) > 0 {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i := range appSet.Spec.Generators {
+		generator := &appSet.Spec.Generators[i]
+		params := generatorParams(generator)
+		if params == nil {
+			continue
+		}
+		for name := range appSet.Spec.ParamDefaults {
+			params.names[name] = true
+		}
+
+		genPath := field.NewPath("spec", "generators").Index(i)
+		check := func(template interface{}, path *field.Path) {
+			for _, ref := range paramReferences(template) {
+				if !params.has(ref) {
+					errs = append(errs, field.Invalid(path, "{{"+ref+"}}",
+						fmt.Sprintf("the param '%s' is not generated by %s", ref, generatorDescription(generator, genPath))))
+				}
+			}
+		}
+		check(appSet.Spec.Template, field.NewPath("spec", "template"))
+		if template, name := generatorTemplate(generator); template != nil {
+			check(*template, genPath.Child(name, "template"))
+		}
+		for _, name := range sortedTemplateNames(appSet.Spec.Templates) {
+			check(appSet.Spec.Templates[name], field.NewPath("spec", "templates").Key(name))
+		}
+		if appSet.Spec.TemplatePatch != nil {
+			check(*appSet.Spec.TemplatePatch, field.NewPath("spec", "templatePatch"))
+		}
+	}
+	return errs
+}
+
+// paramReferences returns the sorted names of the params referenced by a template, or by a string.
+func paramReferences(template interface{}) []string {
+	str, ok := template.(string)
+	if !ok {
+		bytes, err := json.Marshal(template)
+		if err != nil {
+			return nil
+		}
+		str = string(bytes)
+	}
+
+	seen := map[string]bool{}
+	var refs []string
+	for _, match := range paramReferenceRegexp.FindAllStringSubmatch(str, -1) {
+		ref := strings.TrimSpace(match[1])
+		if ref == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// generatorTemplate returns the template of the generator set on a generator, and the name of the generator.
+func generatorTemplate(generator *argoprojiov1alpha1.ApplicationSetGenerator) (*argoprojiov1alpha1.ApplicationSetTemplate, string) {
+	v := reflect.ValueOf(generator).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() {
+			continue
+		}
+		t := f.Elem().FieldByName("Template")
+		if !t.IsValid() {
+			continue
+		}
+		if template, ok := t.Addr().Interface().(*argoprojiov1alpha1.ApplicationSetTemplate); ok {
+			return template, strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return nil, ""
+}
+
+func sortedTemplateNames(templates map[string]argoprojiov1alpha1.ApplicationSetTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,155 @@
+package validation
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+)
+
+func TestValidateTemplateParams(t *testing.T) {
+	template := argoprojiov1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}-{{ app }}"},
+		Spec: argov1alpha1.ApplicationSpec{
+			Destination: argov1alpha1.ApplicationDestination{Server: "{{url}}", Namespace: "{{values.namespace}}"},
+		},
+	}
+	patch := `{"spec": {"source": {"targetRevision": "{{revision}}"}}}`
+
+	for _, c := range []struct {
+		name           string
+		spec           argoprojiov1alpha1.ApplicationSetSpec
+		expectedErrors []string
+	}{
+		{
+			name: "declared params",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: listGenerator(`{"cluster": "a", "app": "guestbook", "url": "https://a", "values": {"namespace": "ns"}}`)},
+				},
+				Template: template,
+			},
+		},
+		{
+			name: "undeclared params",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: listGenerator(`{"cluster": "a", "url": "https://a"}`)},
+				},
+				Template:      template,
+				TemplatePatch: &patch,
+			},
+			expectedErrors: []string{
+				`spec.template: Invalid value: "{{app}}": the param 'app' is not generated by spec.generators[0] (list)`,
+				`spec.template: Invalid value: "{{values.namespace}}": the param 'values.namespace' is not generated by spec.generators[0] (list)`,
+				`spec.templatePatch: Invalid value: "{{revision}}": the param 'revision' is not generated by spec.generators[0] (list)`,
+			},
+		},
+		{
+			name: "param defaults",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: listGenerator(`{"cluster": "a", "url": "https://a"}`)},
+				},
+				ParamDefaults: map[string]string{"app": "guestbook", "values.namespace": "default"},
+				Template:      template,
+			},
+		},
+		{
+			name: "Cluster generator in a Matrix generator",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+						{Clusters: &argoprojiov1alpha1.ClusterGenerator{Values: map[string]string{"namespace": "ns"}}},
+						{Git: &argoprojiov1alpha1.GitGenerator{Directories: []argoprojiov1alpha1.GitDirectoryGeneratorItem{{Path: "apps/*"}}}},
+					}}},
+				},
+				Template: argoprojiov1alpha1.ApplicationSetTemplate{
+					ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{metadata.labels.env}}-{{path.basename}}-{{path[1]}}"},
+					Spec: argov1alpha1.ApplicationSpec{
+						Destination: argov1alpha1.ApplicationDestination{Server: "{{server}}", Namespace: "{{values.namespace}}"},
+					},
+				},
+			},
+		},
+		{
+			name: "generator template",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{Clusters: &argoprojiov1alpha1.ClusterGenerator{Template: argoprojiov1alpha1.ApplicationSetTemplate{
+						ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}"},
+					}}},
+				},
+			},
+			expectedErrors: []string{
+				`spec.generators[0].clusters.template: Invalid value: "{{cluster}}": the param 'cluster' is not generated by spec.generators[0] (clusters)`,
+			},
+		},
+		{
+			name: "named templates",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+				},
+				Templates: map[string]argoprojiov1alpha1.ApplicationSetTemplate{"prod": template},
+			},
+			expectedErrors: []string{
+				`spec.templates[prod]: Invalid value: "{{app}}": the param 'app' is not generated by spec.generators[0] (clusters)`,
+				`spec.templates[prod]: Invalid value: "{{cluster}}": the param 'cluster' is not generated by spec.generators[0] (clusters)`,
+				`spec.templates[prod]: Invalid value: "{{url}}": the param 'url' is not generated by spec.generators[0] (clusters)`,
+				`spec.templates[prod]: Invalid value: "{{values.namespace}}": the param 'values.namespace' is not generated by spec.generators[0] (clusters)`,
+			},
+		},
+		{
+			name: "generator with unknown params",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{Matrix: &argoprojiov1alpha1.MatrixGenerator{Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+						{List: listGenerator(`{"cluster": "a"}`)},
+						{PullRequest: &argoprojiov1alpha1.PullRequestGenerator{}},
+					}}},
+				},
+				Template: template,
+			},
+		},
+		{
+			name: "Go template",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				GoTemplate: true,
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: listGenerator(`{"cluster": "a"}`)},
+				},
+				Template: template,
+			},
+		},
+		{
+			name: "param transforms",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: listGenerator(`{"cluster": "a"}`)},
+				},
+				ParamTransforms: []argoprojiov1alpha1.ParamTransform{{Name: "app", Value: "guestbook"}},
+				Template:        template,
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := &argoprojiov1alpha1.ApplicationSet{Spec: c.spec}
+
+			var got []string
+			for _, err := range validateTemplateParams(appSet) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, c.expectedErrors, got)
+		})
+	}
+}
+
+func TestParamReferences(t *testing.T) {
+	assert.Equal(t, []string{"a", "b.c", "d[0]"}, paramReferences("{{ b.c }}-{{a}}/{{d[0]}}/{{a}}/{{}}/{ {e} }"))
+	assert.Equal(t, []string{"name"}, paramReferences(argoprojiov1alpha1.ApplicationSetTemplate{
+		ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}"},
+	}))
+}
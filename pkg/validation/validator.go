@@ -0,0 +1,199 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ValidatingWebhookPath is the path of the validating admission webhook of the ApplicationSets, on the webhook server
+// of the controller manager.
+const ValidatingWebhookPath = "/validate-argoproj-io-v1alpha1-applicationset"
+
+// ApplicationSetValidator is a validating admission webhook, which rejects the ApplicationSets whose errors can be
+// detected without running their generators, so that they are reported when the ApplicationSets are applied rather
+// than in the logs and the conditions of the controller.
+type ApplicationSetValidator struct {
+	Options
+}
+
+// Options are the restrictions of the controller enforced by the ApplicationSetValidator. The zero value allows all
+// the projects, destinations and generators.
+type Options struct {
+	// AllowedProjects restricts the projects of the templates, all the projects are allowed if empty
+	AllowedProjects utils.ProjectAllowList
+	// AllowedDestinations restricts the destinations of the templates, all the destinations are allowed if empty
+	AllowedDestinations utils.DestinationAllowList
+	// DisabledGenerators are the Go names of the generators disabled by the controller, e.g. SCMProvider
+	DisabledGenerators map[string]bool
+	// ForbidTemplatedProjects rejects the projects of the templates rendered from params
+	ForbidTemplatedProjects bool
+}
+
+var _ admission.Handler = &ApplicationSetValidator{}
+
+// NewApplicationSetValidator returns a validator enforcing the restrictions of the options.
+func NewApplicationSetValidator(opts Options) *ApplicationSetValidator {
+	return &ApplicationSetValidator{Options: opts}
+}
+
+// Handle validates the ApplicationSets which are created or updated.
+func (v *ApplicationSetValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	appSet := &argoprojiov1alpha1.ApplicationSet{}
+	if err := json.Unmarshal(req.Object.Raw, appSet); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("error decoding the ApplicationSet: %v", err))
+	}
+	if err := validateUnknownFields(req.Object.Raw); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if errs := v.Validate(appSet); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+		return admission.Denied(strings.Join(messages, "; "))
+	}
+	return admission.Allowed("")
+}
+
+// validateUnknownFields rejects the fields of the spec of the ApplicationSet which are not in its type. The API server
+// drops the unknown fields against the schema of the CRD, except in the templates of the generators and in the Matrix,
+// Merge and Union generators, whose schema is left out of the CRD to keep it under the size limit of etcd, so that the
+// unknown fields there, e.g. a misspelled field of a template, are only rejected by the webhook. The fields of the
+// wrong type are already rejected when the ApplicationSet is decoded.
+func validateUnknownFields(raw []byte) error {
+	var obj struct {
+		Spec json.RawMessage `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil || len(obj.Spec) == 0 {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(obj.Spec))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&argoprojiov1alpha1.ApplicationSetSpec{}); err != nil {
+		return fmt.Errorf("spec: %s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// Validate returns the errors of the generators, of the params referenced by the templates, and of the projects and the
+// destinations of the templates of the ApplicationSet.
+func (v *ApplicationSetValidator) Validate(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateGenerators(appSet)...)
+	errs = append(errs, v.validateDisabledGenerators(appSet)...)
+	errs = append(errs, validateTemplateParams(appSet)...)
+	errs = append(errs, v.validateProjects(appSet)...)
+	if v.ForbidTemplatedProjects {
+		errs = append(errs, ValidateTemplatedProjects(appSet)...)
+	}
+	errs = append(errs, v.validateDestinations(appSet)...)
+	return errs
+}
+
+// validateProjects checks the projects of the templates against the allow-list. As for the destinations, the projects
+// rendered from params aren't checked.
+func (v *ApplicationSetValidator) validateProjects(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	if len(v.AllowedProjects) == 0 {
+		return nil
+	}
+
+	var errs field.ErrorList
+	check := func(template *argoprojiov1alpha1.ApplicationSetTemplate, path *field.Path) {
+		project := template.Spec.Project
+		if project == "" || strings.Contains(project, "{{") || v.AllowedProjects.IsAllowed(project) {
+			return
+		}
+		errs = append(errs, field.Forbidden(path.Child("spec", "project"),
+			fmt.Sprintf("the project %s is not allowed, the allowed projects are %s", project, v.AllowedProjects.String())))
+	}
+	forEachTemplate(appSet, check)
+	return errs
+}
+
+// templatePatchProjectRegexp matches the project key of a YAML or JSON template patch.
+var templatePatchProjectRegexp = regexp.MustCompile(`(^|[\s{},"'])project["']?\s*:`)
+
+// ValidateTemplatedProjects rejects the projects of the templates rendered from params, and the template patch setting
+// the project, so that the params, e.g. the labels of a cluster or the name of a branch, can't choose the project of
+// the Applications.
+func ValidateTemplatedProjects(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	var errs field.ErrorList
+	check := func(template *argoprojiov1alpha1.ApplicationSetTemplate, path *field.Path) {
+		if project := template.Spec.Project; strings.Contains(project, "{{") {
+			errs = append(errs, field.Forbidden(path.Child("spec", "project"),
+				fmt.Sprintf("the project %s is rendered from params, which is forbidden by the ApplicationSet controller", project)))
+		}
+	}
+	forEachTemplate(appSet, check)
+	if appSet.Spec.TemplatePatch != nil && templatePatchProjectRegexp.MatchString(*appSet.Spec.TemplatePatch) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "templatePatch"),
+			"the template patch sets the project, which is forbidden by the ApplicationSet controller"))
+	}
+	return errs
+}
+
+// validateDestinations checks the destinations of the templates against the allow-list. The destinations rendered
+// from params are only known once the Applications are generated, so they aren't checked.
+func (v *ApplicationSetValidator) validateDestinations(appSet *argoprojiov1alpha1.ApplicationSet) field.ErrorList {
+	if len(v.AllowedDestinations) == 0 {
+		return nil
+	}
+
+	var errs field.ErrorList
+	check := func(template *argoprojiov1alpha1.ApplicationSetTemplate, path *field.Path) {
+		destination := template.Spec.Destination
+		if destination.Server == "" && destination.Name == "" {
+			return
+		}
+		if isTemplated(destination) || v.AllowedDestinations.IsAllowed(destination) {
+			return
+		}
+		server := destination.Server
+		if server == "" {
+			server = destination.Name
+		}
+		errs = append(errs, field.Forbidden(path.Child("spec", "destination"),
+			fmt.Sprintf("the destination %s@%s is not allowed, the allowed destinations are %s", destination.Namespace, server, v.AllowedDestinations.String())))
+	}
+
+	forEachTemplate(appSet, check)
+	return errs
+}
+
+// forEachTemplate calls fn with the template of the ApplicationSet, the templates of its generators, and its named
+// templates, in this order.
+func forEachTemplate(appSet *argoprojiov1alpha1.ApplicationSet, fn func(template *argoprojiov1alpha1.ApplicationSetTemplate, path *field.Path)) {
+	fn(&appSet.Spec.Template, field.NewPath("spec", "template"))
+	for i := range appSet.Spec.Generators {
+		if template, name := generatorTemplate(&appSet.Spec.Generators[i]); template != nil {
+			fn(template, field.NewPath("spec", "generators").Index(i).Child(name, "template"))
+		}
+	}
+	for _, name := range sortedTemplateNames(appSet.Spec.Templates) {
+		template := appSet.Spec.Templates[name]
+		fn(&template, field.NewPath("spec", "templates").Key(name))
+	}
+}
+
+func isTemplated(destination argov1alpha1.ApplicationDestination) bool {
+	return strings.Contains(destination.Server, "{{") || strings.Contains(destination.Name, "{{") ||
+		strings.Contains(destination.Namespace, "{{")
+}
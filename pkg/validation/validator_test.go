@@ -0,0 +1,220 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	argoprojiov1alpha1 "github.com/argoproj-labs/applicationset/api/v1alpha1"
+	"github.com/argoproj-labs/applicationset/pkg/utils"
+)
+
+func TestValidateDestinations(t *testing.T) {
+	allowList, err := utils.ParseDestinationAllowList("team-*@https://kubernetes.default.svc,*@staging")
+	assert.NoError(t, err)
+	validator := NewApplicationSetValidator(Options{AllowedDestinations: allowList})
+
+	templateWithDestination := func(destination argov1alpha1.ApplicationDestination) argoprojiov1alpha1.ApplicationSetTemplate {
+		return argoprojiov1alpha1.ApplicationSetTemplate{Spec: argov1alpha1.ApplicationSpec{Destination: destination}}
+	}
+
+	for _, c := range []struct {
+		name           string
+		spec           argoprojiov1alpha1.ApplicationSetSpec
+		expectedErrors []string
+	}{
+		{
+			name: "allowed destination",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Template: templateWithDestination(argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "team-a"}),
+			},
+		},
+		{
+			name: "templated destination",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Template: templateWithDestination(argov1alpha1.ApplicationDestination{Server: "{{server}}", Namespace: "kube-system"}),
+			},
+		},
+		{
+			name: "destination outside of the allow-list",
+			spec: argoprojiov1alpha1.ApplicationSetSpec{
+				Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+					{List: &argoprojiov1alpha1.ListGenerator{
+						Template: templateWithDestination(argov1alpha1.ApplicationDestination{Name: "production", Namespace: "team-a"}),
+					}},
+					{Clusters: &argoprojiov1alpha1.ClusterGenerator{}},
+				},
+				Template: templateWithDestination(argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "kube-system"}),
+				Templates: map[string]argoprojiov1alpha1.ApplicationSetTemplate{
+					"staging": templateWithDestination(argov1alpha1.ApplicationDestination{Name: "staging", Namespace: "kube-system"}),
+				},
+			},
+			expectedErrors: []string{
+				"spec.template.spec.destination: Forbidden: the destination kube-system@https://kubernetes.default.svc is not allowed, the allowed destinations are team-*@https://kubernetes.default.svc,*@staging",
+				"spec.generators[0].list.template.spec.destination: Forbidden: the destination team-a@production is not allowed, the allowed destinations are team-*@https://kubernetes.default.svc,*@staging",
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := &argoprojiov1alpha1.ApplicationSet{Spec: c.spec}
+
+			var got []string
+			for _, err := range validator.validateDestinations(appSet) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, c.expectedErrors, got)
+		})
+	}
+
+	// all the destinations are allowed without an allow-list
+	appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{
+		Template: templateWithDestination(argov1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "kube-system"}),
+	}}
+	assert.Empty(t, NewApplicationSetValidator(Options{}).validateDestinations(appSet))
+}
+
+func TestValidateProjects(t *testing.T) {
+	validator := NewApplicationSetValidator(Options{AllowedProjects: utils.ParseProjectAllowList("team-*")})
+
+	templateWithProject := func(project string) argoprojiov1alpha1.ApplicationSetTemplate {
+		return argoprojiov1alpha1.ApplicationSetTemplate{Spec: argov1alpha1.ApplicationSpec{Project: project}}
+	}
+
+	appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{
+		Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+			{List: &argoprojiov1alpha1.ListGenerator{Template: templateWithProject("platform")}},
+			{Clusters: &argoprojiov1alpha1.ClusterGenerator{Template: templateWithProject("{{values.project}}")}},
+		},
+		Template: templateWithProject("team-a"),
+		Templates: map[string]argoprojiov1alpha1.ApplicationSetTemplate{
+			"default": templateWithProject("default"),
+			"empty":   templateWithProject(""),
+		},
+	}}
+
+	var got []string
+	for _, err := range validator.validateProjects(appSet) {
+		got = append(got, err.Error())
+	}
+	assert.Equal(t, []string{
+		"spec.generators[0].list.template.spec.project: Forbidden: the project platform is not allowed, the allowed projects are team-*",
+		"spec.templates[default].spec.project: Forbidden: the project default is not allowed, the allowed projects are team-*",
+	}, got)
+
+	// all the projects are allowed without an allow-list
+	assert.Empty(t, NewApplicationSetValidator(Options{}).validateProjects(appSet))
+}
+
+func TestValidateTemplatedProjects(t *testing.T) {
+	templateWithProject := func(project string) argoprojiov1alpha1.ApplicationSetTemplate {
+		return argoprojiov1alpha1.ApplicationSetTemplate{Spec: argov1alpha1.ApplicationSpec{Project: project}}
+	}
+
+	appSet := &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{
+		Generators: []argoprojiov1alpha1.ApplicationSetGenerator{
+			{List: &argoprojiov1alpha1.ListGenerator{Template: templateWithProject("platform")}},
+			{Clusters: &argoprojiov1alpha1.ClusterGenerator{Template: templateWithProject("{{metadata.labels.project}}")}},
+		},
+		Template: templateWithProject("{{ .values.project }}"),
+		Templates: map[string]argoprojiov1alpha1.ApplicationSetTemplate{
+			"default": templateWithProject("default"),
+		},
+	}}
+
+	var got []string
+	for _, err := range ValidateTemplatedProjects(appSet) {
+		got = append(got, err.Error())
+	}
+	assert.Equal(t, []string{
+		"spec.template.spec.project: Forbidden: the project {{ .values.project }} is rendered from params, which is forbidden by the ApplicationSet controller",
+		"spec.generators[1].clusters.template.spec.project: Forbidden: the project {{metadata.labels.project}} is rendered from params, which is forbidden by the ApplicationSet controller",
+	}, got)
+
+	appSet = &argoprojiov1alpha1.ApplicationSet{Spec: argoprojiov1alpha1.ApplicationSetSpec{Template: templateWithProject("default")}}
+	for _, patch := range []string{
+		"spec:\n  project: '{{ .project }}'\n",
+		`{"spec": {"project": "{{ .project }}"}}`,
+		"{{- if .privileged }}\nspec:\n  project: admin\n{{- end }}\n",
+	} {
+		patch := patch
+		appSet.Spec.TemplatePatch = &patch
+		errs := ValidateTemplatedProjects(appSet)
+		if assert.Len(t, errs, 1, patch) {
+			assert.Equal(t, "spec.templatePatch: Forbidden: the template patch sets the project, which is forbidden by the ApplicationSet controller", errs[0].Error())
+		}
+	}
+	for _, patch := range []string{
+		"spec:\n  source:\n    helm:\n      valueFiles:\n      - '{{ .project }}.yaml'\n",
+		"metadata:\n  annotations:\n    team/project: '{{ .project }}'\n",
+	} {
+		patch := patch
+		appSet.Spec.TemplatePatch = &patch
+		assert.Empty(t, ValidateTemplatedProjects(appSet), patch)
+	}
+}
+
+func TestApplicationSetValidatorHandle(t *testing.T) {
+	validAppSet := argoprojiov1alpha1.ApplicationSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "argocd"},
+		Spec: argoprojiov1alpha1.ApplicationSetSpec{
+			Generators: []argoprojiov1alpha1.ApplicationSetGenerator{{List: listGenerator(`{"cluster": "a"}`)}},
+			Template: argoprojiov1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojiov1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}"},
+			},
+		},
+	}
+	invalidAppSet := validAppSet.DeepCopy()
+	invalidAppSet.Spec.Template.Name = "{{name}}"
+	invalidAppSet.Spec.Generators = append(invalidAppSet.Spec.Generators, argoprojiov1alpha1.ApplicationSetGenerator{})
+
+	request := func(operation admissionv1.Operation, appSet interface{}) admission.Request {
+		raw, err := json.Marshal(appSet)
+		assert.NoError(t, err)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	validator := NewApplicationSetValidator(Options{})
+
+	res := validator.Handle(context.Background(), request(admissionv1.Create, validAppSet))
+	assert.True(t, res.Allowed)
+
+	res = validator.Handle(context.Background(), request(admissionv1.Update, invalidAppSet))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, `spec.generators[1]: Required value: a generator is required; spec.template: Invalid value: "{{name}}": the param 'name' is not generated by spec.generators[0] (list)`, string(res.Result.Reason))
+
+	// The fields of the generator templates are not in the schema of the CRD, so the webhook rejects the unknown ones
+	unknownField := map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "ApplicationSet",
+		"metadata":   map[string]interface{}{"name": "name", "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"generators": []interface{}{map[string]interface{}{"list": map[string]interface{}{
+				"elements": []interface{}{map[string]interface{}{"cluster": "a"}},
+				"template": map[string]interface{}{"spec": map[string]interface{}{"source": map[string]interface{}{"targetRevison": "HEAD"}}},
+			}}},
+			"template": map[string]interface{}{"metadata": map[string]interface{}{"name": "{{cluster}}"}},
+		},
+	}
+	res = validator.Handle(context.Background(), request(admissionv1.Create, unknownField))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, `spec: unknown field "targetRevison"`, string(res.Result.Reason))
+
+	res = validator.Handle(context.Background(), request(admissionv1.Delete, invalidAppSet))
+	assert.True(t, res.Allowed)
+
+	res = validator.Handle(context.Background(), request(admissionv1.Create, "not an ApplicationSet"))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, int32(http.StatusBadRequest), res.Result.Code)
+}